@@ -0,0 +1,179 @@
+// Command vccsync refreshes the embedded VCC library from checkouts of
+// varnish-cache and varnish-modules. It copies the VCC files of their VMODs
+// into vcclib and records the release each one came from in
+// vcclib/manifest.json. It runs from go generate in the repository root.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/perbu/vclparser"
+	"github.com/perbu/vclparser/pkg/vcc"
+)
+
+// upstream describes where a source tree keeps its VCC files
+type upstream struct {
+	name  string
+	globs []string
+}
+
+var (
+	varnishCache = upstream{
+		name: "varnish-cache",
+		// vmod/ since Varnish 7, lib/libvmod_*/ before
+		globs: []string{"vmod/vmod_*.vcc", "lib/libvmod_*/vmod_*.vcc", "lib/libvmod_*/vmod.vcc"},
+	}
+	varnishModules = upstream{
+		name:  "varnish-modules",
+		globs: []string{"src/vmod_*.vcc", "src/*.vcc"},
+	}
+)
+
+// acInit matches the release in configure.ac, as in
+// AC_INIT([Varnish], [7.5.0], [varnish-dev@varnish-cache.org])
+var acInit = regexp.MustCompile(`AC_INIT\(\[[^\]]*\],\s*\[([^\]]+)\]`)
+
+func main() {
+	cacheDir := flag.String("varnish-cache", "", "varnish-cache checkout to copy the built-in VMODs from")
+	modulesDir := flag.String("varnish-modules", "", "varnish-modules checkout to copy VMODs from")
+	out := flag.String("out", "vcclib", "directory of the embedded VCC files")
+	flag.Parse()
+
+	if *cacheDir == "" && *modulesDir == "" {
+		fmt.Fprintln(os.Stderr, "vccsync: set -varnish-cache or -varnish-modules, such as with VARNISH_CACHE_SRC for go generate")
+		os.Exit(2)
+	}
+
+	manifest, err := readManifest(*out)
+	if err != nil {
+		fatal(err)
+	}
+	for _, src := range []struct {
+		dir string
+		up  upstream
+	}{{*cacheDir, varnishCache}, {*modulesDir, varnishModules}} {
+		if src.dir == "" {
+			continue
+		}
+		files, err := syncTree(src.dir, src.up, *out, manifest)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Printf("vccsync: copied %d VCC files from %s %s\n", len(files), src.up.name, manifest.Files[files[0]].Version)
+	}
+	if err := writeManifest(*out, manifest); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "vccsync: %v\n", err)
+	os.Exit(1)
+}
+
+// syncTree copies the VCC files of an upstream tree into out and records
+// them in the manifest. It returns the names of the files copied, and fails
+// before copying anything if one of them does not parse.
+func syncTree(dir string, up upstream, out string, manifest *vclparser.VCCManifest) ([]string, error) {
+	version, err := releaseVersion(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", up.name, err)
+	}
+
+	sources := map[string]string{}
+	for _, glob := range up.globs {
+		matches, err := filepath.Glob(filepath.Join(dir, glob))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			name := filepath.Base(path)
+			if name == "vmod.vcc" {
+				// lib/libvmod_std/vmod.vcc in old trees
+				name = "vmod_" + filepath.Base(filepath.Dir(path))[len("libvmod_"):] + ".vcc"
+			}
+			if _, ok := sources[name]; !ok {
+				sources[name] = path
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%s: no VCC files found in %s", up.name, dir)
+	}
+
+	names := make([]string, 0, len(sources))
+	contents := map[string][]byte{}
+	for name, path := range sources {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := vcc.NewParser(bytes.NewReader(data)).Parse(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		names = append(names, name)
+		contents[name] = data
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(out, name), contents[name], 0o644); err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(dir, sources[name])
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files[name] = vclparser.VCCSource{Upstream: up.name, Path: filepath.ToSlash(rel), Version: version}
+	}
+	if up.name == varnishCache.name {
+		manifest.Varnish = version
+	}
+	return names, nil
+}
+
+// releaseVersion reads the release of an upstream tree from its configure.ac
+func releaseVersion(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "configure.ac"))
+	if err != nil {
+		return "", err
+	}
+	m := acInit.FindSubmatch(data)
+	if m == nil {
+		return "", fmt.Errorf("no AC_INIT release in configure.ac")
+	}
+	return string(m[1]), nil
+}
+
+func readManifest(out string) (*vclparser.VCCManifest, error) {
+	manifest := &vclparser.VCCManifest{Files: map[string]vclparser.VCCSource{}}
+	data, err := os.ReadFile(filepath.Join(out, vclparser.VCCManifestFile))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("%s: %v", vclparser.VCCManifestFile, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]vclparser.VCCSource{}
+	}
+	return manifest, nil
+}
+
+func writeManifest(out string, manifest *vclparser.VCCManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(out, vclparser.VCCManifestFile), append(data, '\n'), 0o644)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncTree(t *testing.T) {
+	src, out := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(src, "configure.ac"), "AC_INIT([Varnish], [7.5.0], [varnish-dev@varnish-cache.org])\n")
+	writeFile(t, filepath.Join(src, "vmod", "vmod_std.vcc"), "$Module std 3 \"Standard library\"\n$Function VOID f()\n")
+	writeFile(t, filepath.Join(src, "vmod", "vmod_directors.vcc"), "$Module directors 3 \"Directors\"\n$Object round_robin()\n")
+
+	manifest, err := readManifest(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := syncTree(src, varnishCache, out, manifest)
+	if err != nil {
+		t.Fatalf("syncTree() error = %v", err)
+	}
+	if len(names) != 2 || names[0] != "vmod_directors.vcc" || names[1] != "vmod_std.vcc" {
+		t.Errorf("names = %v", names)
+	}
+	if manifest.Varnish != "7.5.0" {
+		t.Errorf("Varnish = %q, want 7.5.0", manifest.Varnish)
+	}
+	if got := manifest.Files["vmod_std.vcc"]; got.Upstream != "varnish-cache" || got.Path != "vmod/vmod_std.vcc" || got.Version != "7.5.0" {
+		t.Errorf("vmod_std.vcc source = %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(out, "vmod_std.vcc")); err != nil {
+		t.Errorf("vmod_std.vcc was not copied: %v", err)
+	}
+
+	// The manifest survives a round trip
+	if err := writeManifest(out, manifest); err != nil {
+		t.Fatal(err)
+	}
+	reread, err := readManifest(out)
+	if err != nil || reread.Files["vmod_directors.vcc"].Version != "7.5.0" {
+		t.Errorf("readManifest() = %+v, %v", reread, err)
+	}
+}
+
+func TestSyncTreeErrors(t *testing.T) {
+	src, out := t.TempDir(), t.TempDir()
+	manifest, _ := readManifest(out)
+	if _, err := syncTree(src, varnishModules, out, manifest); err == nil {
+		t.Error("expected an error without configure.ac")
+	}
+
+	writeFile(t, filepath.Join(src, "configure.ac"), "AC_INIT([varnish-modules], [0.24.0])\n")
+	writeFile(t, filepath.Join(src, "src", "vmod_bad.vcc"), "$Module bad 3\n$Function WIDGET f()\n")
+	if _, err := syncTree(src, varnishModules, out, manifest); err == nil {
+		t.Error("expected an error for a VCC file that does not parse")
+	}
+	if _, err := os.Stat(filepath.Join(out, "vmod_bad.vcc")); !os.IsNotExist(err) {
+		t.Error("a VCC file that does not parse was copied")
+	}
+}
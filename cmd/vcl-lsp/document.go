@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// document is an open text document and its parse result
+type document struct {
+	uri       string
+	version   int
+	text      string
+	lines     *lexer.LineIndex
+	program   *ast.Program
+	parseErrs []parser.DetailedError
+	parsed    *parser.Document
+}
+
+// newDocument parses text. If prev is the previous version of the document,
+// only the declarations affected by the change are parsed again. Otherwise
+// the parse cache, if there is one, may already hold the tree.
+func newDocument(uri string, version int, text string, cfg *parser.Config, parses *cache.Cache, prev *document) *document {
+	d := &document{
+		uri:     uri,
+		version: version,
+		text:    text,
+		lines:   lexer.NewLineIndex(text),
+	}
+
+	if prev != nil {
+		d.parsed = prev.parsed
+		d.parsed.Replace(text)
+	} else if parses != nil {
+		d.parsed = parses.NewDocument(text, uriFilename(uri))
+	} else {
+		d.parsed = parser.NewDocument(text, uriFilename(uri), cfg)
+	}
+	d.program = d.parsed.Program
+	d.parseErrs = d.parsed.Errors
+	return d
+}
+
+// uriFilename returns the path of a file URI, or the URI itself for other
+// schemes
+func uriFilename(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// position converts a byte offset to an LSP position, whose character is
+// counted in UTF-16 code units
+func (d *document) position(offset int) position {
+	line, character := d.lines.UTF16(offset)
+	return position{Line: line, Character: character}
+}
+
+// offset converts an LSP position to a byte offset, clamping positions
+// past the end of a line or the document
+func (d *document) offset(pos position) int {
+	return d.lines.OffsetUTF16(pos.Line, pos.Character)
+}
+
+// lineRange returns the range of the text on a 1-indexed source line,
+// without leading indentation
+func (d *document) lineRange(line int) lspRange {
+	if line < 1 || line > d.lines.Lines() {
+		return lspRange{}
+	}
+	start := d.lines.LineStart(line - 1)
+	end := len(d.text)
+	if line < d.lines.Lines() {
+		end = d.lines.LineStart(line) - 1
+	}
+	text := d.text[start:end]
+	indent := len(text) - len(strings.TrimLeft(text, " \t"))
+	return lspRange{Start: d.position(start + indent), End: d.position(start + len(strings.TrimRight(text, " \t\r")))}
+}
+
+// wordRange returns the range of the dotted name starting at offset, such as
+// req.http.host, or a single character if there is none
+func (d *document) wordRange(offset int) lspRange {
+	end := offset
+	for end < len(d.text) && isNameByte(d.text[end]) {
+		end++
+	}
+	if end == offset && end < len(d.text) {
+		end++
+	}
+	return lspRange{Start: d.position(offset), End: d.position(end)}
+}
+
+// wordAt returns the dotted name around offset and where it starts
+func (d *document) wordAt(offset int) (string, int) {
+	start, end := offset, offset
+	for start > 0 && isNameByte(d.text[start-1]) {
+		start--
+	}
+	for end < len(d.text) && isNameByte(d.text[end]) {
+		end++
+	}
+	return strings.Trim(d.text[start:end], "."), start
+}
+
+// prefixAt returns the dotted name ending at offset, which is what the
+// user is typing when completion is requested
+func (d *document) prefixAt(offset int) (string, int) {
+	start := offset
+	for start > 0 && isNameByte(d.text[start-1]) {
+		start--
+	}
+	return d.text[start:offset], start
+}
+
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.'
+}
+
+// subAt returns the subroutine containing offset, if any
+func (d *document) subAt(offset int) *ast.SubDecl {
+	for _, decl := range d.program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Start().Offset <= offset && offset <= sub.End().Offset {
+			return sub
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/vcc"
+)
+
+// statementKeywords are offered when completing a name without a dot
+var statementKeywords = []string{"set", "unset", "if", "else", "elseif", "return", "call", "synthetic", "new"}
+
+// hover describes the name under the cursor: a declaration, a VMOD function
+// or method, or a VCL variable
+func (s *server) hover(doc *document, offset int) *hover {
+	word, start := doc.wordAt(offset)
+	if word == "" {
+		return nil
+	}
+
+	var text string
+	if decl, _ := s.findDeclaration(doc, word); decl != nil {
+		text = fmt.Sprintf("```vcl\n%s\n```", declarationSignature(decl))
+	} else if sig, desc, ok := s.vmodMember(doc, word); ok {
+		text = fmt.Sprintf("```vcl\n%s\n```", sig)
+		if desc != "" {
+			text += "\n\n" + desc
+		}
+	} else if info, name, ok := s.variable(word); ok {
+		text = variableDoc(name, info)
+	} else {
+		return nil
+	}
+
+	r := lspRange{Start: doc.position(start), End: doc.position(start + len(word))}
+	return &hover{Contents: markupContent{Kind: "markdown", Value: text}, Range: &r}
+}
+
+// definition finds the declaration of the sub, backend, probe, ACL or VMOD
+// object under the cursor, looking in the document first and then in the
+// other open documents
+func (s *server) definition(doc *document, offset int) []location {
+	word, _ := doc.wordAt(offset)
+	if word == "" {
+		return nil
+	}
+	if decl, owner := s.findDeclaration(doc, word); decl != nil {
+		return []location{owner.nameLocation(decl.node, decl.name)}
+	}
+	if obj, owner := s.findDeclaration(doc, strings.SplitN(word, ".", 2)[0]); obj != nil {
+		return []location{owner.nameLocation(obj.node, obj.name)}
+	}
+	return nil
+}
+
+// completion offers the variables usable in the enclosing subroutine,
+// members of imported VMODs and VMOD objects, subroutines after "call", and
+// statement keywords
+func (s *server) completion(doc *document, offset int) []completionItem {
+	prefix, start := doc.prefixAt(offset)
+	replace := lspRange{Start: doc.position(start), End: doc.position(offset)}
+	var items []completionItem
+	add := func(label string, kind int, detail string) {
+		if strings.HasPrefix(label, prefix) {
+			items = append(items, completionItem{
+				Label:    label,
+				Kind:     kind,
+				Detail:   detail,
+				TextEdit: &textEdit{Range: replace, NewText: label},
+			})
+		}
+	}
+
+	before := strings.TrimRight(doc.text[:start], " \t")
+	if strings.HasSuffix(before, "call") {
+		for _, decl := range doc.program.Declarations {
+			if sub, ok := decl.(*ast.SubDecl); ok {
+				add(sub.Name, completionFunction, "sub")
+			}
+		}
+		return items
+	}
+
+	sub := doc.subAt(offset)
+	if sub == nil {
+		return nil
+	}
+	s.completeVariables(sub.Name, add)
+
+	for _, module := range importedModules(doc.program) {
+		mod, ok := s.registry.GetModule(module)
+		if !ok {
+			continue
+		}
+		add(module, completionModule, "vmod")
+		for _, fn := range mod.Functions {
+			add(module+"."+fn.Name, completionFunction, functionSignature(module+"."+fn.Name, fn.ReturnType, fn.Parameters))
+		}
+	}
+	for name, obj := range s.vmodObjects(doc) {
+		for _, m := range obj.Methods {
+			add(name+"."+m.Name, completionFunction, functionSignature(name+"."+m.Name, m.ReturnType, m.Parameters))
+		}
+	}
+
+	if !strings.Contains(prefix, ".") {
+		for _, kw := range statementKeywords {
+			add(kw, completionKeyword, "")
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+// completeVariables offers the variables that are readable or writable in
+// a built-in subroutine. Custom subroutines can be called from anywhere, so
+// every variable is offered there.
+func (s *server) completeVariables(subName string, add func(label string, kind int, detail string)) {
+	variables, err := s.loader.GetVariables()
+	if err != nil {
+		return
+	}
+	methods, _ := s.loader.GetMethods()
+	method := strings.TrimPrefix(subName, "vcl_")
+	_, builtin := methods[method]
+	builtin = builtin && strings.HasPrefix(subName, "vcl_")
+
+	for name, info := range variables {
+		if builtin && !info.IsReadableInMethod(method, methods) && !info.IsWritableInMethod(method, methods) {
+			continue
+		}
+		add(name, completionVariable, info.Type)
+	}
+}
+
+// declaration is a named top-level declaration or VMOD object
+type declaration struct {
+	kind string
+	name string
+	node ast.Node
+	// constructor is the VMOD object constructor of a "new" statement
+	constructor string
+}
+
+// findDeclaration looks for a declaration named name in doc and then in
+// the other open documents
+func (s *server) findDeclaration(doc *document, name string) (*declaration, *document) {
+	if decl := doc.declaration(name); decl != nil {
+		return decl, doc
+	}
+	s.mu.Lock()
+	others := make([]*document, 0, len(s.docs))
+	for _, other := range s.docs {
+		if other.uri != doc.uri {
+			others = append(others, other)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(others, func(i, j int) bool { return others[i].uri < others[j].uri })
+
+	for _, other := range others {
+		if decl := other.declaration(name); decl != nil {
+			return decl, other
+		}
+	}
+	return nil, nil
+}
+
+// declaration returns the top-level declaration or VMOD object named name
+func (d *document) declaration(name string) *declaration {
+	for _, decl := range d.program.Declarations {
+		switch n := decl.(type) {
+		case *ast.SubDecl:
+			if n.Name == name {
+				return &declaration{kind: "sub", name: name, node: n}
+			}
+			if n.Body == nil {
+				continue
+			}
+			for _, stmt := range n.Body.Statements {
+				if ns, ok := stmt.(*ast.NewStatement); ok && expressionName(ns.Name) == name {
+					ctor := ""
+					if call, ok := ns.Constructor.(*ast.CallExpression); ok {
+						ctor = expressionName(call.Function)
+					}
+					return &declaration{kind: "new", name: name, node: ns, constructor: ctor}
+				}
+			}
+		case *ast.BackendDecl:
+			if n.Name == name {
+				return &declaration{kind: "backend", name: name, node: n}
+			}
+		case *ast.ProbeDecl:
+			if n.Name == name {
+				return &declaration{kind: "probe", name: name, node: n}
+			}
+		case *ast.ACLDecl:
+			if n.Name == name {
+				return &declaration{kind: "acl", name: name, node: n}
+			}
+		}
+	}
+	return nil
+}
+
+// nameLocation returns the location of name within the declaration node
+func (d *document) nameLocation(node ast.Node, name string) location {
+	start := node.Start().Offset
+	if i := strings.Index(d.text[start:], name); i >= 0 {
+		start += i
+	}
+	return location{
+		URI:   d.uri,
+		Range: lspRange{Start: d.position(start), End: d.position(start + len(name))},
+	}
+}
+
+func declarationSignature(decl *declaration) string {
+	if decl.kind == "new" {
+		return fmt.Sprintf("new %s = %s(...)", decl.name, decl.constructor)
+	}
+	return decl.kind + " " + decl.name
+}
+
+// vmodMember describes module.function or object.method for an imported
+// VMOD or an object created with new
+func (s *server) vmodMember(doc *document, word string) (signature, description string, ok bool) {
+	prefix, member, found := strings.Cut(word, ".")
+	if !found {
+		return "", "", false
+	}
+
+	for _, module := range importedModules(doc.program) {
+		if module != prefix {
+			continue
+		}
+		if fn, err := s.registry.GetFunction(module, member); err == nil {
+			return functionSignature(word, fn.ReturnType, fn.Parameters), documentation(fn.Doc, fn.Description), true
+		}
+		if obj, err := s.registry.GetObject(module, member); err == nil {
+			return functionSignature("new x = "+word, "", obj.Constructor), documentation(obj.Doc, obj.Description), true
+		}
+	}
+
+	if obj, ok := s.vmodObjects(doc)[prefix]; ok {
+		if m := obj.FindMethod(member); m != nil {
+			return functionSignature(word, m.ReturnType, m.Parameters), documentation(m.Doc, m.Description), true
+		}
+	}
+	return "", "", false
+}
+
+// documentation returns the RST documentation of a VMOD function, object or
+// method, falling back to its description for modules parsed without docs
+func documentation(doc, description string) string {
+	if doc != "" {
+		return doc
+	}
+	return description
+}
+
+// vmodObjects returns the VMOD objects created with new, by name
+func (s *server) vmodObjects(doc *document) map[string]*vcc.Object {
+	objects := map[string]*vcc.Object{}
+	for _, decl := range doc.program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub.Body == nil {
+			continue
+		}
+		for _, stmt := range sub.Body.Statements {
+			ns, ok := stmt.(*ast.NewStatement)
+			if !ok {
+				continue
+			}
+			call, ok := ns.Constructor.(*ast.CallExpression)
+			if !ok {
+				continue
+			}
+			module, object, found := strings.Cut(expressionName(call.Function), ".")
+			if !found {
+				continue
+			}
+			if obj, err := s.registry.GetObject(module, object); err == nil {
+				objects[expressionName(ns.Name)] = obj
+			}
+		}
+	}
+	return objects
+}
+
+func importedModules(program *ast.Program) []string {
+	var modules []string
+	for _, decl := range program.Declarations {
+		if imp, ok := decl.(*ast.ImportDecl); ok {
+			modules = append(modules, imp.Module)
+		}
+	}
+	return modules
+}
+
+// variable looks up a VCL variable, matching headers such as req.http.host
+// to their req.http. entry
+func (s *server) variable(name string) (metadata.VCLVariable, string, bool) {
+	variables, err := s.loader.GetVariables()
+	if err != nil {
+		return metadata.VCLVariable{}, "", false
+	}
+	if info, ok := variables[name]; ok {
+		return info, name, true
+	}
+	if i := strings.Index(name, ".http."); i >= 0 {
+		if info, ok := variables[name[:i+len(".http.")]]; ok {
+			return info, name, true
+		}
+	}
+	return metadata.VCLVariable{}, "", false
+}
+
+func variableDoc(name string, info metadata.VCLVariable) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "```vcl\n%s %s\n```", info.Type, name)
+	access := []struct {
+		label   string
+		methods []string
+	}{
+		{"Readable from", info.ReadableFrom},
+		{"Writable from", info.WritableFrom},
+		{"Unsetable from", info.UnsetableFrom},
+	}
+	for _, a := range access {
+		if len(a.methods) > 0 {
+			fmt.Fprintf(&sb, "\n\n%s: %s", a.label, strings.Join(a.methods, ", "))
+		}
+	}
+	if info.VersionLow > 40 {
+		fmt.Fprintf(&sb, "\n\nRequires VCL %d.%d", info.VersionLow/10, info.VersionLow%10)
+	}
+	return sb.String()
+}
+
+// functionSignature formats a VMOD function, method or constructor
+func functionSignature(name string, ret vcc.VCCType, params []vcc.Parameter) string {
+	args := make([]string, len(params))
+	for i, p := range params {
+		arg := string(p.Type)
+		if p.Name != "" {
+			arg += " " + p.Name
+		}
+		if p.HasDefault {
+			arg += " = " + p.FormatDefault()
+		}
+		if p.Optional {
+			arg = "[" + arg + "]"
+		}
+		args[i] = arg
+	}
+	sig := name + "(" + strings.Join(args, ", ") + ")"
+	if ret != "" {
+		sig = string(ret) + " " + sig
+	}
+	return sig
+}
+
+// expressionName returns the dotted name of an identifier or member access
+func expressionName(expr ast.Expression) string {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return e.Name
+	case *ast.MemberExpression:
+		return expressionName(e.Object) + "." + expressionName(e.Property)
+	}
+	return ""
+}
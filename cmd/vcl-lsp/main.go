@@ -0,0 +1,83 @@
+// Command vcl-lsp is a Language Server Protocol server for VCL.
+//
+// It speaks the protocol over stdin and stdout and provides diagnostics
+// from the parser, analyzer and VMOD validation, hover information for
+// variables and VMOD functions, go-to-definition for subroutines, backends,
+// probes, ACLs and VMOD objects, and completion of the variables available
+// in the enclosing subroutine.
+//
+// Settings are read from the same configuration files as vclparse, starting
+// from the server's working directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func main() {
+	logFile := flag.String("log", "", "write the server log to this file instead of stderr")
+	flag.Parse()
+
+	if err := run(*logFile); err != nil {
+		fmt.Fprintf(os.Stderr, "vcl-lsp: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(logFile string) error {
+	var logOut io.Writer = os.Stderr
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		logOut = f
+	}
+	logger := log.New(logOut, "vcl-lsp: ", log.LstdFlags)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return err
+	}
+	if err := analyzer.ConfigurePlugins(cfg.Plugins.Settings); err != nil {
+		return err
+	}
+
+	registry := vmod.NewRegistry()
+	if cfg.VMOD.Profile != "" {
+		registry = vmod.NewEmptyRegistry()
+		if err := registry.LoadProfile(cfg.VMOD.Profile); err != nil {
+			return err
+		}
+	}
+	policy, err := vmod.ParseConflictPolicy(cfg.VMOD.Conflicts)
+	if err != nil {
+		return err
+	}
+	registry.SetConflictPolicy(policy)
+	for _, path := range cfg.VMOD.VCCPaths {
+		if err := registry.LoadVCCPath(path); err != nil {
+			return err
+		}
+	}
+	registry.SetVMODPath(cfg.VMOD.VMODPath...)
+
+	s, err := newServer(cfg, registry, os.Stdout, logger)
+	if err != nil {
+		return err
+	}
+	return s.run(os.Stdin)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// server is a language server for one client connection
+type server struct {
+	cfg       *config.Config
+	parserCfg *parser.Config
+	// parses is the on-disk parse cache, nil unless configured
+	parses   *cache.Cache
+	registry *vmod.Registry
+	loader   *metadata.MetadataLoader
+	out      *writer
+	logger   *log.Logger
+
+	mu          sync.Mutex
+	docs        map[string]*document
+	initialized bool
+	shutdown    bool
+}
+
+func newServer(cfg *config.Config, registry *vmod.Registry, out io.Writer, logger *log.Logger) (*server, error) {
+	dialect, _ := lexer.ParseDialect(cfg.Parser.Dialect)
+	loader := metadata.New()
+	if dialect == lexer.DialectFastly {
+		loader = metadata.NewFastly()
+	}
+	for _, overlay := range cfg.Metadata.Overlays {
+		if err := loader.LoadOverlayFile(overlay); err != nil {
+			return nil, err
+		}
+	}
+	parserCfg := &parser.Config{
+		DisableInlineC: cfg.Parser.DisableInlineC,
+		MaxErrors:      cfg.Parser.MaxErrors,
+		Legacy:         cfg.Parser.Legacy,
+		Dialect:        dialect,
+	}
+	var parses *cache.Cache
+	if cfg.Cache.Dir != "" {
+		var err error
+		parses, err = cache.New(
+			cache.WithParserConfig(parserCfg),
+			cache.WithDir(cfg.Cache.Dir, int64(cfg.Cache.MaxSizeMB)<<20))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &server{
+		cfg:       cfg,
+		parserCfg: parserCfg,
+		parses:    parses,
+		registry:  registry,
+		loader:    loader,
+		out:       &writer{w: out},
+		logger:    logger,
+		docs:      make(map[string]*document),
+	}, nil
+}
+
+// errExit is returned by run when the client sent "exit"
+var errExit = errors.New("exit")
+
+// run serves requests until the input ends or the client exits. It returns
+// nil for an orderly exit after "shutdown".
+func (s *server) run(in io.Reader) error {
+	r := bufio.NewReader(in)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := s.handle(body); err != nil {
+			if errors.Is(err, errExit) {
+				if s.shutdown {
+					return nil
+				}
+				return errors.New("exit without shutdown")
+			}
+			return err
+		}
+	}
+}
+
+// handle dispatches one message. Only I/O failures and exit are returned;
+// request errors are reported to the client.
+func (s *server) handle(body []byte) error {
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return s.out.write(response{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &responseError{Code: codeParseError, Message: err.Error()}})
+	}
+
+	if req.Method == "exit" {
+		return errExit
+	}
+	if !s.initialized && req.Method != "initialize" {
+		if req.ID == nil {
+			return nil
+		}
+		return s.reply(req, nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"})
+	}
+
+	result, rerr := s.dispatch(req)
+	if req.ID == nil {
+		if rerr != nil {
+			s.logger.Printf("%s: %s", req.Method, rerr.Message)
+		}
+		return nil
+	}
+	return s.reply(req, result, rerr)
+}
+
+func (s *server) reply(req request, result interface{}, rerr *responseError) error {
+	return s.out.write(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr})
+}
+
+func (s *server) dispatch(req request) (interface{}, *responseError) {
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+		var result initializeResult
+		result.ServerInfo.Name = "vcl-lsp"
+		result.Capabilities = serverCapabilities{
+			TextDocumentSync:   syncFull,
+			HoverProvider:      true,
+			DefinitionProvider: true,
+			CompletionProvider: &completionOptions{TriggerCharacters: []string{"."}},
+		}
+		return result, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Version, params.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if n := len(params.ContentChanges); n > 0 {
+			s.update(params.TextDocument.URI, params.TextDocument.Version, params.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.mu.Lock()
+		delete(s.docs, params.TextDocument.URI)
+		s.mu.Unlock()
+		// Clear the diagnostics of the closed document
+		s.publish(publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+		return nil, nil
+	case "textDocument/hover", "textDocument/definition", "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		doc := s.document(params.TextDocument.URI)
+		if doc == nil {
+			return nil, nil
+		}
+		offset := doc.offset(params.Position)
+		switch req.Method {
+		case "textDocument/hover":
+			return s.hover(doc, offset), nil
+		case "textDocument/definition":
+			return s.definition(doc, offset), nil
+		default:
+			return s.completion(doc, offset), nil
+		}
+	}
+
+	if req.ID == nil {
+		// Unknown notifications, such as $/cancelRequest, are ignored
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+func (s *server) document(uri string) *document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.docs[uri]
+}
+
+// update reparses a document and publishes its diagnostics
+func (s *server) update(uri string, version int, text string) {
+	s.mu.Lock()
+	doc := newDocument(uri, version, text, s.parserCfg, s.parses, s.docs[uri])
+	s.docs[uri] = doc
+	s.mu.Unlock()
+
+	s.publish(publishDiagnosticsParams{
+		URI:         uri,
+		Version:     version,
+		Diagnostics: s.diagnostics(doc),
+	})
+}
+
+func (s *server) publish(params publishDiagnosticsParams) {
+	msg := notification{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics", Params: params}
+	if err := s.out.write(msg); err != nil {
+		s.logger.Printf("publishing diagnostics: %v", err)
+	}
+}
+
+// diagnostics returns the parse errors of a document or, if it parses
+// cleanly, the analyzer and lint findings, as vclparse check does
+func (s *server) diagnostics(doc *document) []diagnostic {
+	var diags []analyzer.Diagnostic
+	for _, perr := range doc.parseErrs {
+		diags = append(diags, analyzer.DiagnosticFromParseError(perr))
+	}
+	if len(diags) == 0 {
+		linter, err := s.cfg.NewLinter(s.registry)
+		if err != nil {
+			s.logger.Printf("config: %v", err)
+		}
+		diags = linter.Lint(doc.program, doc.uri, doc.text)
+	}
+
+	result := make([]diagnostic, 0, len(diags))
+	for _, d := range diags {
+		result = append(result, diagnostic{
+			Range:    diagnosticRange(doc, d),
+			Severity: lspSeverity(d.Severity),
+			Code:     d.Code,
+			Source:   "vclparser",
+			Message:  d.Message,
+		})
+	}
+	return result
+}
+
+// diagnosticRange places a diagnostic on the construct it refers to, on the
+// name it starts at when its end is unknown, or on the whole line for
+// findings that only know their line
+func diagnosticRange(doc *document, d analyzer.Diagnostic) lspRange {
+	if d.Position.Column > 0 {
+		r := doc.wordRange(d.Position.Offset)
+		if end := d.EndPosition.Offset; end > doc.offset(r.End) {
+			r.End = doc.position(end)
+		}
+		return r
+	}
+	return doc.lineRange(d.Position.Line)
+}
+
+func lspSeverity(severity analyzer.Severity) int {
+	switch severity {
+	case analyzer.SeverityWarning:
+		return severityWarning
+	case analyzer.SeverityInfo:
+		return severityInformation
+	default:
+		return severityError
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+const testVCL = `vcl 4.1;
+
+import std;
+
+backend default {
+    .host = "127.0.0.1";
+}
+
+sub normalize {
+    set req.http.host = std.tolower(req.http.host);
+}
+
+sub vcl_recv {
+    call normalize;
+    set req.url = std.tolower(req.url);
+}
+
+sub vcl_deliver {
+    set resp.http.x-bad = req.foo;
+}
+`
+
+// client drives a server through in-memory pipes
+type client struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s, err := newServer(config.Default(), vmod.NewRegistry(), outW, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &client{t: t, in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+	go func() {
+		err := s.run(inR)
+		outW.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+func (c *client) send(msg interface{}) {
+	c.t.Helper()
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// receive reads the next message from the server
+func (c *client) receive() map[string]json.RawMessage {
+	c.t.Helper()
+	body, err := readMessage(c.out)
+	if err != nil {
+		c.t.Fatalf("reading message: %v", err)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+// call sends a request and decodes the result of its response into result
+func (c *client) call(method string, params, result interface{}) {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	msg := c.receive()
+	if e, ok := msg["error"]; ok {
+		c.t.Fatalf("%s: error %s", method, e)
+	}
+	if result != nil {
+		if err := json.Unmarshal(msg["result"], result); err != nil {
+			c.t.Fatalf("%s: decoding result %s: %v", method, msg["result"], err)
+		}
+	}
+}
+
+func (c *client) diagnostics() publishDiagnosticsParams {
+	c.t.Helper()
+	msg := c.receive()
+	var method string
+	json.Unmarshal(msg["method"], &method)
+	if method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected publishDiagnostics, got %q", method)
+	}
+	var params publishDiagnosticsParams
+	if err := json.Unmarshal(msg["params"], &params); err != nil {
+		c.t.Fatal(err)
+	}
+	return params
+}
+
+func (c *client) close() {
+	c.t.Helper()
+	c.call("shutdown", nil, nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		c.t.Errorf("server exited with error: %v", err)
+	}
+}
+
+// at returns the LSP position of the first occurrence of needle in text,
+// plus skip bytes
+func at(t *testing.T, text, needle string, skip int) map[string]interface{} {
+	t.Helper()
+	i := strings.Index(text, needle)
+	if i < 0 {
+		t.Fatalf("%q not found", needle)
+	}
+	i += skip
+	line := strings.Count(text[:i], "\n")
+	return map[string]interface{}{"line": line, "character": i - strings.LastIndex(text[:i], "\n") - 1}
+}
+
+func openTestDocument(t *testing.T, c *client, uri, text string) publishDiagnosticsParams {
+	t.Helper()
+	var init initializeResult
+	c.call("initialize", map[string]interface{}{}, &init)
+	if !init.Capabilities.HoverProvider || init.Capabilities.TextDocumentSync != syncFull {
+		t.Fatalf("unexpected capabilities: %+v", init.Capabilities)
+	}
+	c.notify("initialized", map[string]interface{}{})
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "vcl", "version": 1, "text": text},
+	})
+	return c.diagnostics()
+}
+
+func TestServerDiagnostics(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///tmp/test.vcl"
+	diags := openTestDocument(t, c, uri, testVCL)
+
+	if diags.URI != uri || diags.Version != 1 {
+		t.Errorf("diagnostics for %s version %d", diags.URI, diags.Version)
+	}
+	var found bool
+	for _, d := range diags.Diagnostics {
+		if strings.Contains(d.Message, "req.foo") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a diagnostic for req.foo, got %+v", diags.Diagnostics)
+	}
+
+	// Fixing the error clears the diagnostic
+	fixed := strings.Replace(testVCL, "req.foo", "req.url", 1)
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]interface{}{{"text": fixed}},
+	})
+	diags = c.diagnostics()
+	if len(diags.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics after fix, got %+v", diags.Diagnostics)
+	}
+
+	// A syntax error is reported as a parse error
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 3},
+		"contentChanges": []map[string]interface{}{{"text": "vcl 4.1;\nsub vcl_recv {\n    set req.url = ;\n}\n"}},
+	})
+	diags = c.diagnostics()
+	if len(diags.Diagnostics) == 0 || diags.Diagnostics[0].Range.Start.Line != 2 {
+		t.Errorf("expected a parse error on line 3, got %+v", diags.Diagnostics)
+	}
+
+	c.notify("textDocument/didClose", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	if diags = c.diagnostics(); len(diags.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared on close, got %+v", diags.Diagnostics)
+	}
+	c.close()
+}
+
+func TestServerHover(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///tmp/test.vcl"
+	openTestDocument(t, c, uri, testVCL)
+
+	tests := []struct {
+		name   string
+		needle string
+		want   string
+	}{
+		{"variable", "req.url =", "STRING req.url"},
+		{"header", "req.http.host =", "HEADER req.http.host"},
+		{"vmod function", "std.tolower(req.url", "std.tolower("},
+		{"sub", "call normalize", "sub normalize"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip := 0
+			if strings.HasPrefix(tt.needle, "call ") {
+				skip = len("call ")
+			}
+			var h *hover
+			c.call("textDocument/hover", map[string]interface{}{
+				"textDocument": map[string]interface{}{"uri": uri},
+				"position":     at(t, testVCL, tt.needle, skip),
+			}, &h)
+			if h == nil {
+				t.Fatal("expected hover")
+			}
+			if !strings.Contains(h.Contents.Value, tt.want) {
+				t.Errorf("hover = %q, want it to contain %q", h.Contents.Value, tt.want)
+			}
+		})
+	}
+	c.close()
+}
+
+func TestServerDefinition(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///tmp/test.vcl"
+	openTestDocument(t, c, uri, testVCL)
+
+	var locs []location
+	c.call("textDocument/definition", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     at(t, testVCL, "call normalize", len("call ")),
+	}, &locs)
+
+	want := at(t, testVCL, "sub normalize", len("sub "))
+	if len(locs) != 1 || locs[0].URI != uri || locs[0].Range.Start.Line != want["line"] ||
+		locs[0].Range.Start.Character != want["character"] {
+		t.Errorf("definition = %+v, want line %v character %v", locs, want["line"], want["character"])
+	}
+	c.close()
+}
+
+func TestServerCompletion(t *testing.T) {
+	c := newClient(t)
+	uri := "file:///tmp/test.vcl"
+	text := strings.Replace(testVCL, "    call normalize;\n", "    call normalize;\n    set beresp.\n", 1)
+	openTestDocument(t, c, uri, text)
+
+	complete := func(needle string, skip int) []completionItem {
+		var items []completionItem
+		c.call("textDocument/completion", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"position":     at(t, text, needle, skip),
+		}, &items)
+		return items
+	}
+	labels := func(items []completionItem) map[string]bool {
+		m := map[string]bool{}
+		for _, item := range items {
+			m[item.Label] = true
+		}
+		return m
+	}
+
+	// beresp is not available in vcl_recv
+	if got := labels(complete("set beresp.", len("set beresp."))); len(got) != 0 {
+		t.Errorf("expected no beresp completions in vcl_recv, got %v", got)
+	}
+
+	got := labels(complete("set req.url", len("set req.")))
+	for _, want := range []string{"req.url", "req.method", "req.http."} {
+		if !got[want] {
+			t.Errorf("expected %s in completions", want)
+		}
+	}
+
+	got = labels(complete("std.tolower(req.url", len("std.")))
+	if !got["std.tolower"] || got["req.url"] {
+		t.Errorf("unexpected std. completions %v", got)
+	}
+	c.close()
+}
+
+func TestServerParseCache(t *testing.T) {
+	cfg := config.Default()
+	cfg.Cache.Dir = t.TempDir()
+	uri := "file:///tmp/test.vcl"
+	open := func() *server {
+		s, err := newServer(cfg, vmod.NewRegistry(), io.Discard, log.New(io.Discard, "", 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.update(uri, 1, testVCL)
+		return s
+	}
+
+	first := open()
+	// A restarted server reads the tree from the cache directory
+	second := open()
+	if stats := second.parses.Stats(); stats.DiskHits != 1 || stats.Misses != 0 {
+		t.Errorf("Stats() of the restarted server = %+v", stats)
+	}
+	want := first.document(uri).program
+	if got := second.document(uri).program; len(got.Declarations) != len(want.Declarations) {
+		t.Errorf("Expected %d declarations from the cache, got %d", len(want.Declarations), len(got.Declarations))
+	}
+
+	// Edits are parsed incrementally as before
+	second.update(uri, 2, strings.Replace(testVCL, "req.foo", "req.url", 1))
+	if doc := second.document(uri); len(doc.parseErrs) != 0 || doc.version != 2 {
+		t.Errorf("Unexpected document after the edit: version %d, errors %v", doc.version, doc.parseErrs)
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	r := bufio.NewReader(bytes.NewBufferString("Content-Length: 2\r\nContent-Type: x\r\n\r\n{}"))
+	body, err := readMessage(r)
+	if err != nil || string(body) != "{}" {
+		t.Fatalf("readMessage = %q, %v", body, err)
+	}
+	if _, err := readMessage(bufio.NewReader(bytes.NewBufferString("X: 1\r\n\r\n"))); err == nil {
+		t.Error("expected an error for a message without Content-Length")
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/report"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// runCheck parses and analyzes each file and prints the diagnostics. It
+// returns exitError(1) when any error-level diagnostic was reported.
+func runCheck(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	snippet := fs.String("snippet", "", "check the files as statements of this built-in subroutine, as in ingress annotations")
+	prelude := fs.String("prelude", "", "VCL file with the declarations the -snippet files may refer to")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse check [flags] file.vcl...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+	registry, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	if err := setupPlugins(cfg); err != nil {
+		return err
+	}
+
+	if *snippet != "" {
+		return checkSnippets(stdout, fs.Args(), *snippet, *prelude, registry, cfg, format)
+	}
+
+	var all []analyzer.Diagnostic
+	for _, filename := range fs.Args() {
+		diags, err := checkFile(filename, registry, cfg, parses)
+		if err != nil {
+			return err
+		}
+		all = append(all, diags...)
+	}
+	return writeDiagnostics(stdout, format, all)
+}
+
+// checkSnippets analyzes each file as the statements of the built-in
+// subroutine sub and prints the diagnostics
+func checkSnippets(stdout io.Writer, filenames []string, sub, preludeFile string, registry *vmod.Registry, cfg *config.Config, format report.Format) error {
+	var prelude string
+	if preludeFile != "" {
+		var err error
+		if prelude, err = readInput(preludeFile); err != nil {
+			return err
+		}
+	}
+	a, err := cfg.NewAnalyzer(registry)
+	if err != nil {
+		return err
+	}
+	var all []analyzer.Diagnostic
+	for _, filename := range filenames {
+		input, err := readInput(filename)
+		if err != nil {
+			return err
+		}
+		diags, err := a.AnalyzeSnippet(input, filename, analyzer.SnippetOptions{Sub: sub, Prelude: prelude})
+		if err != nil {
+			return err
+		}
+		all = append(all, diags...)
+	}
+	return writeDiagnostics(stdout, format, all)
+}
+
+// writeDiagnostics prints diagnostics to stdout and returns
+// exitError(1) when any of them is an error
+func writeDiagnostics(stdout io.Writer, format report.Format, diags []analyzer.Diagnostic) error {
+	if err := report.Write(stdout, format, diags); err != nil {
+		return err
+	}
+	for _, d := range diags {
+		if d.Severity == analyzer.SeverityError {
+			return exitError(1)
+		}
+	}
+	return nil
+}
+
+// checkFile returns the parse errors for a file or, if it parses cleanly, the
+// semantic analysis and lint findings. VTC files are checked with checkVTC.
+func checkFile(filename string, registry *vmod.Registry, cfg *config.Config, parses *cache.Cache) ([]analyzer.Diagnostic, error) {
+	if strings.HasSuffix(filename, ".vtc") {
+		return checkVTC(filename, registry, cfg, parses)
+	}
+	input, err := readInput(filename)
+	if err != nil {
+		return nil, err
+	}
+	return checkSource(input, filename, filename != "-", registry, cfg, parses)
+}
+
+// checkSource is like checkFile for VCL source that was read already. With
+// includes set, the files it includes are resolved relative to filename and
+// analyzed with it, as they may declare what it uses.
+func checkSource(input, filename string, includes bool, registry *vmod.Registry, cfg *config.Config, parses *cache.Cache) ([]analyzer.Diagnostic, error) {
+	result := parses.ParseDetailed(input, filename)
+	diags := analyzer.ParseDiagnostics(result)
+	if len(result.Errors) > 0 {
+		return diags, nil
+	}
+	program := result.Program
+
+	linter, err := cfg.NewLinter(registry)
+	if err != nil {
+		return nil, err
+	}
+	if includes && hasIncludes(program) {
+		resolved, resolver, err := resolveIncludes(filename, cfg, parses)
+		if err != nil {
+			// An include that fails to parse is reported like the file
+			var detailed parser.DetailedError
+			if errors.As(err, &detailed) {
+				return append(diags, analyzer.DiagnosticFromParseError(detailed)), nil
+			}
+			return nil, err
+		}
+		program = resolved
+		linter.SetSourceLocator(resolver.SourceMap())
+	}
+	found := linter.Lint(program, filename, input)
+	if includes {
+		// resolveIncludes reads the main file by its absolute path; report
+		// it by the name it was given
+		if main, err := filepath.Abs(filename); err == nil {
+			for i := range found {
+				if found[i].Filename == main {
+					found[i].Filename = filename
+				}
+				if chain := found[i].IncludeChain; len(chain) > 0 && chain[0] == main {
+					found[i].IncludeChain = append([]string{filename}, chain[1:]...)
+				}
+			}
+		}
+	}
+	return append(diags, found...), nil
+}
+
+// hasIncludes reports whether a program has include statements
+func hasIncludes(program *ast.Program) bool {
+	for _, decl := range program.Declarations {
+		if _, ok := decl.(*ast.IncludeDecl); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFile parses a file through parses, which has the configured parser
+// settings, and returns the program together with the parse errors as
+// diagnostics
+func parseFile(filename string, parses *cache.Cache) (*ast.Program, []analyzer.Diagnostic, error) {
+	input, err := readInput(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	program, diags := parseSource(input, filename, parses)
+	return program, diags, nil
+}
+
+// parseSource is like parseFile for source that was read already. It
+// reports syntax errors only, not warnings
+func parseSource(input, filename string, parses *cache.Cache) (*ast.Program, []analyzer.Diagnostic) {
+	result := parses.ParseDetailed(input, filename)
+
+	var diags []analyzer.Diagnostic
+	for _, perr := range result.Errors {
+		diags = append(diags, analyzer.DiagnosticFromParseError(perr))
+	}
+	return result.Program, diags
+}
+
+// readInput reads a file, treating "-" as standard input
+func readInput(filename string) (string, error) {
+	var data []byte
+	var err error
+	if filename == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/analyzer/goplugin"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// configFlags registers the flags that override configuration file settings
+// on a command's flag set
+type configFlags struct {
+	fs *flag.FlagSet
+}
+
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	fs.String("format", "", "output format: vim, emacs, json or sarif (default from config, else vim)")
+	fs.String("varnish-version", "", "Varnish release to validate against, e.g. 7.5 or 6.0-enterprise")
+	fs.String("unreferenced", "", "report unused backends, ACLs, probes and subs as warning, error or off")
+	fs.String("fallthrough", "", "report built-in subs that return on only some paths as info, warning, error or off")
+	fs.String("backtracking", "", "report regexes prone to catastrophic backtracking as info, warning, error or off")
+	fs.String("deprecations", "", "report deprecated constructs such as req.esi as info, warning, error or off")
+	fs.String("backend-audit", "", "report backends without probe or route and unattached probes as info, warning, error or off")
+	fs.String("features", "", "comma-separated optional features enabled in varnishd, e.g. VCL_CONNECT, or none")
+	fs.String("labels", "", "comma-separated VCL labels that return (vcl(label)) may refer to")
+	fs.String("deny-headers", "", "comma-separated headers that may not be set, e.g. resp.http.X-Internal-*")
+	fs.String("allow-headers", "", "comma-separated exceptions to -deny-headers")
+	fs.Int("jobs", 0, "number of analysis passes to run at the same time")
+	fs.Int("max-errors", 0, "maximum number of parse errors to report per file")
+	fs.Bool("disable-inline-c", false, "reject inline C blocks")
+	fs.Bool("legacy", false, "accept Varnish 2 and 3 VCL, warning about each old construct")
+	fs.String("dialect", "", "VCL dialect to read: varnish or fastly (default varnish)")
+	fs.String("cache-dir", "", "directory keeping parse results between runs")
+	fs.String("vcl-path", "", "comma-separated directories searched for relative include paths")
+	fs.String("vcc-path", "", "comma-separated VCC files or directories to load")
+	fs.String("vcc-dir", "", "alias for -vcc-path")
+	fs.String("vmod-path", "", "comma-separated directories searched for VCC files of imported modules")
+	fs.String("vmod-profile", "", "embedded VMODs to load: enterprise (default all)")
+	fs.String("vmod-conflicts", "", "module used when two VCC files define it: replace, error, prefer-higher or prefer-first")
+	fs.String("metadata-overlay", "", "comma-separated JSON metadata overlays, e.g. extra backend properties")
+	fs.Bool("load-plugins", false, "load the analyzer plugin shared objects listed in the config")
+	fs.String("plugin", "", "comma-separated analyzer plugin shared objects to load (implies -load-plugins)")
+	return &configFlags{fs: fs}
+}
+
+// load resolves the effective config: defaults, user config, project config
+// and finally any flags given explicitly on the command line
+func (cf *configFlags) load() (*config.Config, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	cf.fs.Visit(func(f *flag.Flag) {
+		value := f.Value.String()
+		switch f.Name {
+		case "format":
+			cfg.Format = value
+		case "varnish-version":
+			cfg.VarnishVersion = value
+		case "unreferenced":
+			cfg.Analyzer.Unreferenced = value
+		case "fallthrough":
+			cfg.Analyzer.Fallthrough = value
+		case "backtracking":
+			cfg.Analyzer.Backtracking = value
+		case "deprecations":
+			cfg.Analyzer.Deprecations = value
+		case "backend-audit":
+			cfg.Analyzer.BackendAudit = value
+		case "features":
+			cfg.Analyzer.Features = []string{}
+			if value != "none" {
+				cfg.Analyzer.Features = append(cfg.Analyzer.Features, splitList(value)...)
+			}
+		case "labels":
+			cfg.Analyzer.Labels = splitList(value)
+		case "deny-headers":
+			cfg.Analyzer.Headers.Deny = splitList(value)
+		case "allow-headers":
+			cfg.Analyzer.Headers.Allow = splitList(value)
+		case "jobs":
+			cfg.Analyzer.Jobs, _ = strconv.Atoi(value)
+		case "max-errors":
+			cfg.Parser.MaxErrors, _ = strconv.Atoi(value)
+		case "disable-inline-c":
+			cfg.Parser.DisableInlineC = value == "true"
+		case "legacy":
+			cfg.Parser.Legacy = value == "true"
+		case "dialect":
+			cfg.Parser.Dialect = value
+		case "cache-dir":
+			cfg.Cache.Dir = value
+		case "vcl-path":
+			cfg.Parser.VCLPath = splitList(value)
+		case "vcc-path", "vcc-dir":
+			cfg.VMOD.VCCPaths = splitList(value)
+		case "vmod-path":
+			cfg.VMOD.VMODPath = splitList(value)
+		case "vmod-profile":
+			cfg.VMOD.Profile = value
+		case "vmod-conflicts":
+			cfg.VMOD.Conflicts = value
+		case "metadata-overlay":
+			cfg.Metadata.Overlays = splitList(value)
+		case "load-plugins":
+			cfg.Plugins.Load = value == "true"
+		case "plugin":
+			cfg.Plugins.Load = true
+			cfg.Plugins.Paths = splitList(value)
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// setupPlugins loads shared object plugins when enabled and hands every
+// registered plugin its settings
+func setupPlugins(cfg *config.Config) error {
+	if cfg.Plugins.Load {
+		if err := goplugin.LoadAll(cfg.Plugins.Paths); err != nil {
+			return err
+		}
+	}
+	return analyzer.ConfigurePlugins(cfg.Plugins.Settings)
+}
+
+// newCache returns the parse cache of a command, parsing with the
+// configured parser settings and keeping results in the cache directory
+// when one is configured
+func newCache(cfg *config.Config) (*cache.Cache, error) {
+	options := []cache.Option{cache.WithParserConfig(parserConfig(cfg))}
+	if cfg.Cache.Dir != "" {
+		options = append(options, cache.WithDir(cfg.Cache.Dir, int64(cfg.Cache.MaxSizeMB)<<20))
+	}
+	return cache.New(options...)
+}
+
+// parserConfig converts the parser section of the config
+func parserConfig(cfg *config.Config) *parser.Config {
+	// The dialect was checked by config.Validate
+	dialect, _ := lexer.ParseDialect(cfg.Parser.Dialect)
+	return &parser.Config{
+		DisableInlineC: cfg.Parser.DisableInlineC,
+		MaxErrors:      cfg.Parser.MaxErrors,
+		Legacy:         cfg.Parser.Legacy,
+		Dialect:        dialect,
+	}
+}
+
+// newRegistry returns the embedded VMOD registry extended with the configured
+// VCC paths
+func newRegistry(cfg *config.Config) (*vmod.Registry, error) {
+	registry := vmod.NewRegistry()
+	if cfg.VMOD.Profile != "" {
+		registry = vmod.NewEmptyRegistry()
+		if err := registry.LoadProfile(cfg.VMOD.Profile); err != nil {
+			return nil, err
+		}
+	}
+	policy, err := vmod.ParseConflictPolicy(cfg.VMOD.Conflicts)
+	if err != nil {
+		return nil, err
+	}
+	registry.SetConflictPolicy(policy)
+	for _, path := range cfg.VMOD.VCCPaths {
+		if err := registry.LoadVCCPath(path); err != nil {
+			return nil, err
+		}
+	}
+	registry.SetVMODPath(cfg.VMOD.VMODPath...)
+	return registry, nil
+}
+
+// runConfig implements the "config" command
+func runConfig(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] != "show" {
+		fmt.Fprintf(stderr, "Usage: vclparse config show [flags]\n")
+		return exitError(2)
+	}
+
+	fs := flag.NewFlagSet("config show", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	out, err := cfg.YAML()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "# Effective configuration")
+	fmt.Fprintln(stdout, "# Sources (lowest precedence first):")
+	fmt.Fprintln(stdout, "#   built-in defaults")
+	for _, src := range cfg.Sources {
+		fmt.Fprintf(stdout, "#   %s\n", src)
+	}
+	var set []string
+	fs.Visit(func(f *flag.Flag) { set = append(set, "-"+f.Name) })
+	if len(set) > 0 {
+		fmt.Fprintf(stdout, "#   command line: %s\n", strings.Join(set, " "))
+	}
+	fmt.Fprint(stdout, string(out))
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/printer"
+	"github.com/perbu/vclparser/pkg/report"
+)
+
+// runFmt formats VCL files in the canonical style. Like gofmt it prints the
+// result, or with -w rewrites the files and with -l lists those that
+// change. With -includes the arguments are main files, and every file they
+// include is formatted in place of the merged program. It returns
+// exitError(1) when a file does not parse.
+func runFmt(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	write := fs.Bool("w", false, "write the result to the file instead of standard output")
+	list := fs.Bool("l", false, "list files whose formatting differs")
+	includes := fs.Bool("includes", false, "also format the files included by each file")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse fmt [flags] file.vcl...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	filenames := fs.Args()
+	if *includes {
+		if filenames, err = includeTrees(filenames, cfg, parses); err != nil {
+			return err
+		}
+	}
+
+	var failed []analyzer.Diagnostic
+	for _, filename := range filenames {
+		src, err := readInput(filename)
+		if err != nil {
+			return err
+		}
+		if _, diags := parseSource(src, filename, parses); len(diags) > 0 {
+			failed = append(failed, diags...)
+			continue
+		}
+
+		out, err := printer.Format(src, filename)
+		if err != nil {
+			return err
+		}
+
+		if *list && out != src {
+			fmt.Fprintln(stdout, filename)
+		}
+		switch {
+		case *write && filename != "-":
+			if out != src {
+				info, err := os.Stat(filename)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(filename, []byte(out), info.Mode().Perm()); err != nil {
+					return err
+				}
+			}
+		case !*list:
+			fmt.Fprint(stdout, out)
+		}
+	}
+
+	if len(failed) > 0 {
+		return writeDiagnostics(stdout, format, failed)
+	}
+	return nil
+}
+
+// includeTrees returns the main files and every file they include, each
+// once, in include order
+func includeTrees(mains []string, cfg *config.Config, parses *cache.Cache) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, main := range mains {
+		_, resolver, err := resolveIncludes(main, cfg, parses)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range resolver.Files() {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/perbu/vclparser/pkg/graph"
+)
+
+// runGraph renders the call graph, return transitions and backend topology
+// of a main VCL file and its includes
+func runGraph(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	as := fs.String("as", "dot", "output format: dot, mermaid or json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse graph [flags] main.vcl\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	var write func(*graph.Graph) error
+	switch *as {
+	case "dot":
+		write = func(g *graph.Graph) error { return g.WriteDOT(stdout) }
+	case "mermaid":
+		write = func(g *graph.Graph) error { return g.WriteMermaid(stdout) }
+	case "json":
+		write = func(g *graph.Graph) error { return g.WriteJSON(stdout) }
+	default:
+		return fmt.Errorf("unknown output format %q", *as)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	program, _, err := resolveIncludes(fs.Arg(0), cfg, parses)
+	if err != nil {
+		return err
+	}
+	return write(graph.Build(program))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+// runIncludes resolves the includes of a main VCL file and prints the tree
+// of included files or, with -print, the merged program
+func runIncludes(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("includes", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	merged := fs.Bool("print", false, "print the merged program instead of the include tree")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse includes [flags] main.vcl\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	program, resolver, err := resolveIncludes(fs.Arg(0), cfg, parses)
+	if err != nil {
+		return err
+	}
+
+	if *merged {
+		return printer.Fprint(stdout, program)
+	}
+
+	fmt.Fprintln(stdout, fs.Arg(0))
+	printed := map[string]bool{}
+	sources := resolver.SourceMap()
+	for _, decl := range program.Declarations {
+		source, ok := sources.Lookup(decl)
+		if !ok {
+			continue
+		}
+		// The first entry of the chain is the main file
+		path := append(append([]string(nil), source.Chain...), source.File)
+		for depth := 1; depth < len(path); depth++ {
+			key := strings.Join(path[:depth+1], "\x00")
+			if !printed[key] {
+				printed[key] = true
+				fmt.Fprintf(stdout, "%s%s\n", strings.Repeat("  ", depth), path[depth])
+			}
+		}
+	}
+	return nil
+}
+
+// resolveIncludes reads a main VCL file and merges the files it includes,
+// parsing them through parses. Relative includes are found next to the main
+// file unless a search path is configured; URLs are fetched only when
+// remote includes are enabled.
+func resolveIncludes(filename string, cfg *config.Config, parses *cache.Cache) (*ast.Program, *include.Resolver, error) {
+	main, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	options := []include.Option{
+		include.WithBasePath(filepath.Dir(main)),
+		include.WithSearchPath(cfg.Parser.VCLPath...),
+		include.WithParseFunc(parses.Parse),
+	}
+	if remote := cfg.Parser.RemoteIncludes; remote.Enable {
+		options = append(options, include.WithRemoteIncludes(
+			include.WithCacheDir(remote.CacheDir),
+			include.WithChecksums(remote.Checksums),
+			include.WithRequireChecksums(remote.RequireChecksums),
+			include.WithS3(include.S3Config{
+				Endpoint:        remote.S3Endpoint,
+				Region:          remote.S3Region,
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			}),
+		))
+	}
+	resolver := include.NewResolver(options...)
+	program, err := resolver.ResolveFile(main)
+	if err != nil {
+		return nil, nil, err
+	}
+	return program, resolver, nil
+}
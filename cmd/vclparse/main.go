@@ -0,0 +1,99 @@
+// Command vclparse parses and checks VCL files from the command line.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command named by args[0] with the rest of args and returns
+// the exit status
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "parse":
+		err = runParse(args[1:], stdout, stderr)
+	case "check":
+		err = runCheck(args[1:], stdout, stderr)
+	case "fmt":
+		err = runFmt(args[1:], stdout, stderr)
+	case "includes":
+		err = runIncludes(args[1:], stdout, stderr)
+	case "vmods":
+		err = runVMODs(args[1:], stdout, stderr)
+	case "ast":
+		err = runAST(args[1:], stdout, stderr)
+	case "config":
+		err = runConfig(args[1:], stdout, stderr)
+	case "test":
+		err = runTest(args[1:], stdout, stderr)
+	case "graph":
+		err = runGraph(args[1:], stdout, stderr)
+	case "rename":
+		err = runRename(args[1:], stdout, stderr)
+	case "migrate":
+		err = runMigrate(args[1:], stdout, stderr)
+	case "serve":
+		err = runServe(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "vclparse: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	if err != nil {
+		if exit, ok := err.(exitError); ok {
+			return int(exit)
+		}
+		fmt.Fprintf(stderr, "vclparse: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// exitError requests a specific exit status without printing anything further
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, `Usage: vclparse <command> [flags] [arguments]
+
+Commands:
+  parse     Parse VCL files, reporting syntax errors
+  check     Parse and analyze VCL files, reporting diagnostics
+  fmt       Format VCL files in the canonical style
+  includes  Resolve the includes of a VCL file and show the include tree
+  rename    Rename a backend, ACL, probe or subroutine across included files
+  migrate   Migrate VCL 4.0 files to VCL 4.1, printing a diff
+  vmods     List the known VMODs, or the functions of the given VMODs
+  ast       Print the syntax tree of a VCL file, as an outline or JSON
+  config    Show the effective configuration ("config show")
+  test      Run YAML test suites against VCL with the simulator
+  graph     Render the request flow and backend topology (DOT, Mermaid, JSON)
+  serve     Serve the VCLParser gRPC service of proto/vclparser/v1
+
+Run 'vclparse <command> -h' for command flags. Commands reporting
+diagnostics exit with status 1 when there are errors; -format json and
+-format sarif print them for CI jobs and code scanning.
+
+Settings are read from built-in defaults, the user config file
+(e.g. ~/.config/vclparser/config.yaml), the nearest .vclparser.yaml and
+command line flags, in increasing order of precedence.
+`)
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testFiles are the files of the project each test runs in
+var testFiles = map[string]string{
+	"main.vcl": `vcl 4.1;
+
+include "backends.vcl";
+
+sub vcl_recv {
+	if (req.url ~ "^/admin") {
+		return (synth(403, "Forbidden"));
+	}
+	set req.backend_hint = default;
+}
+`,
+	"backends.vcl": `vcl 4.1;
+
+backend default {
+	.host = "127.0.0.1";
+}
+`,
+	"tenants.vcl": `vcl 4.1;
+
+sub tenant_a {
+	set req.http.X-Tenant = "a";
+}
+`,
+	"calls.vcl": `vcl 4.1;
+
+import std;
+
+include "backends.vcl";
+
+include "tenants.vcl";
+
+sub vcl_recv {
+    set req.http.Host = std.tolower(req.http.Host);
+    call tenant_a;
+}
+`,
+	"bad.vcl": `vcl 4.1;
+
+sub vcl_recv {
+	sett req.url = "/";
+}
+`,
+	"unknown.vcl": `vcl 4.1;
+
+backend default {
+	.host = "127.0.0.1";
+}
+
+sub vcl_recv {
+	set req.foo = "x";
+}
+`,
+	"unused.vcl": `vcl 4.1;
+
+backend default {
+	.host = "127.0.0.1";
+}
+
+backend spare {
+	.host = "127.0.0.2";
+}
+`,
+	"ugly.vcl": "vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\n",
+	"old.vcl": `vcl 4.0;
+
+backend default {
+	.host = "127.0.0.1";
+}
+`,
+	"snippet.vcl": `set req.http.X-Snippet = "1";
+`,
+	"suite.yaml": `vcl: main.vcl
+tests:
+  - name: admin is forbidden
+    request:
+      url: /admin
+    expect:
+      action: synth
+      status: 403
+`,
+	"failing.yaml": `vcl: main.vcl
+tests:
+  - name: admin is passed
+    request:
+      url: /admin
+    expect:
+      action: pass
+`,
+	"broken.yaml": `vcl: bad.vcl
+tests:
+  - name: never runs
+    request:
+      url: /
+`,
+}
+
+// workdir makes a temporary project with testFiles and extra, without user
+// configuration, the working directory for the rest of the test
+func workdir(t *testing.T, extra map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	// Keep config.FindProjectConfig from looking above the project
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, files := range []map[string]string{testFiles, extra} {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return dir
+}
+
+// runArgs runs vclparse with args and returns the exit status and output
+func runArgs(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout []string // substrings of standard output, in order
+		stderr []string // substrings of standard error, in order
+	}{
+		{"no command", nil, 2, nil, []string{"Usage: vclparse <command>"}},
+		{"unknown command", []string{"nosuch"}, 2, nil, []string{`unknown command "nosuch"`, "Usage:"}},
+		{"help", []string{"help"}, 0, []string{"Usage: vclparse <command>", "serve"}, nil},
+
+		{"parse", []string{"parse", "main.vcl", "backends.vcl"}, 0, nil, nil},
+		{"parse error", []string{"parse", "bad.vcl"}, 1, []string{`bad.vcl:4:2:`, `unknown statement "sett"`, "[parse-error]"}, nil},
+		{"parse emacs", []string{"parse", "-format", "emacs", "bad.vcl"}, 1, []string{"bad.vcl:4.2:"}, nil},
+		{"parse missing file", []string{"parse", "nosuch.vcl"}, 1, nil, []string{"vclparse: ", "nosuch.vcl"}},
+		{"parse without files", []string{"parse"}, 2, nil, []string{"Usage: vclparse parse"}},
+		{"parse bad flag", []string{"parse", "-nosuch", "main.vcl"}, 2, nil, []string{"-nosuch"}},
+		{"parse bad format", []string{"parse", "-format", "xml", "main.vcl"}, 1, nil, []string{`"xml"`}},
+
+		{"check", []string{"check", "backends.vcl"}, 0, nil, nil},
+		{"check includes", []string{"check", "calls.vcl"}, 0, nil, nil},
+		{"check error", []string{"check", "unknown.vcl"}, 1, []string{"unknown.vcl:8:", "req.foo", "[variable-access]"}, nil},
+		{"check syntax error", []string{"check", "bad.vcl"}, 1, []string{"[parse-error]"}, nil},
+		{"check unreferenced", []string{"check", "unused.vcl"}, 0, []string{"unused.vcl:7:", "warning:", "spare"}, nil},
+		{"check unreferenced error", []string{"check", "-unreferenced", "error", "unused.vcl"}, 1, []string{"error:", "spare"}, nil},
+		{"check bad setting", []string{"check", "-fallthrough", "loud", "backends.vcl"}, 1, nil, []string{"vclparse: ", "loud"}},
+		{"check snippet", []string{"check", "-snippet", "vcl_recv", "snippet.vcl"}, 0, nil, nil},
+		{"check snippet error", []string{"check", "-snippet", "vcl_backend_response", "snippet.vcl"}, 1, []string{"snippet.vcl:1:", "req.http.X-Snippet"}, nil},
+
+		{"fmt", []string{"fmt", "ugly.vcl"}, 0, []string{"vcl 4.1;\n\nbackend default {\n    .host = \"127.0.0.1\";\n}\n"}, nil},
+		{"fmt list", []string{"fmt", "-l", "ugly.vcl", "old.vcl"}, 0, []string{"ugly.vcl\nold.vcl\n"}, nil},
+		{"fmt includes", []string{"fmt", "-l", "-includes", "main.vcl"}, 0, []string{"main.vcl", "backends.vcl"}, nil},
+		{"fmt calls", []string{"fmt", "-l", "calls.vcl"}, 0, nil, nil},
+		{"fmt error", []string{"fmt", "bad.vcl"}, 1, []string{"bad.vcl:4:"}, nil},
+
+		{"includes", []string{"includes", "main.vcl"}, 0, []string{"main.vcl\n  backends.vcl\n"}, nil},
+		{"includes print", []string{"includes", "-print", "main.vcl"}, 0, []string{"backend default", "sub vcl_recv"}, nil},
+		{"includes calls", []string{"includes", "calls.vcl"}, 0, []string{"calls.vcl\n  backends.vcl\n  tenants.vcl\n"}, nil},
+		{"includes missing", []string{"includes", "nosuch.vcl"}, 1, nil, []string{"nosuch.vcl"}},
+
+		{"rename", []string{"rename", "main.vcl", "backend", "default", "origin"}, 0, []string{"backends.vcl:3:", "default -> origin", "main.vcl:9:", "default -> origin"}, nil},
+		{"rename kind", []string{"rename", "main.vcl", "table", "a", "b"}, 1, nil, []string{"cannot rename a table"}},
+		{"rename arguments", []string{"rename", "main.vcl", "backend"}, 2, nil, []string{"Usage: vclparse rename"}},
+
+		{"migrate", []string{"migrate", "old.vcl"}, 0, []string{"--- a/old.vcl", "-vcl 4.0;", "+vcl 4.1;"}, nil},
+		{"migrate current", []string{"migrate", "backends.vcl"}, 0, nil, nil},
+
+		{"vmods", []string{"vmods"}, 0, []string{"std v3"}, nil},
+		{"vmods module", []string{"vmods", "std"}, 0, []string{"Module std", "STRING std.tolower(STRING_LIST s)"}, nil},
+		{"vmods unknown", []string{"vmods", "nosuch"}, 1, nil, []string{"unknown VMOD nosuch"}},
+		{"vmods profile", []string{"vmods", "-vmod-profile", "nosuch"}, 1, nil, []string{"nosuch"}},
+
+		{"ast", []string{"ast", "backends.vcl"}, 0, []string{"Program", "  BackendDecl(default) 3:", "    BackendProperty(host)"}, nil},
+		{"ast json", []string{"ast", "-json", "backends.vcl"}, 0, []string{`"schemaVersion"`, `"type": "BackendDecl"`}, nil},
+		{"ast error", []string{"ast", "bad.vcl"}, 1, []string{"[parse-error]"}, nil},
+
+		{"config", []string{"config", "show"}, 0, []string{"# Effective configuration", "#   built-in defaults\n", "format: vim"}, nil},
+		{"config flags", []string{"config", "show", "-format", "json", "-jobs", "2"}, 0, []string{"#   command line: -format -jobs", "format: json", "jobs: 2"}, nil},
+		{"config without show", []string{"config"}, 2, nil, []string{"Usage: vclparse config show"}},
+
+		{"test", []string{"test", "suite.yaml"}, 0, []string{"ok\tsuite.yaml\t1/1 passed"}, nil},
+		{"test verbose", []string{"test", "-v", "suite.yaml"}, 0, []string{"--- PASS: admin is forbidden", "ok\tsuite.yaml"}, nil},
+		{"test failure", []string{"test", "suite.yaml", "failing.yaml"}, 1, []string{"ok\tsuite.yaml", "--- FAIL: admin is passed", "FAIL\tfailing.yaml\t0/1 passed"}, nil},
+		{"test broken vcl", []string{"test", "broken.yaml"}, 1, []string{"bad.vcl:4:"}, nil},
+		{"test cover", []string{"test", "-cover", "suite.yaml"}, 0, []string{"ok\tsuite.yaml", "vcl_recv"}, nil},
+
+		{"graph", []string{"graph", "main.vcl"}, 0, []string{"digraph", "vcl_recv"}, nil},
+		{"graph mermaid", []string{"graph", "-as", "mermaid", "main.vcl"}, 0, []string{"flowchart", "backend_default"}, nil},
+		{"graph format", []string{"graph", "-as", "png", "main.vcl"}, 1, nil, []string{`unknown output format "png"`}},
+
+		{"serve arguments", []string{"serve", "extra"}, 2, nil, []string{"Usage: vclparse serve"}},
+		{"serve listen", []string{"serve", "-listen", "bogus"}, 1, nil, []string{"vclparse: listen tcp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workdir(t, nil)
+			code, stdout, stderr := runArgs(tt.args...)
+			if code != tt.code {
+				t.Errorf("Exit status %d, want %d\nstdout:\n%s\nstderr:\n%s", code, tt.code, stdout, stderr)
+			}
+			if tt.stdout == nil && stdout != "" {
+				t.Errorf("Unexpected output:\n%s", stdout)
+			}
+			containsInOrder(t, "stdout", stdout, tt.stdout)
+			containsInOrder(t, "stderr", stderr, tt.stderr)
+		})
+	}
+}
+
+func containsInOrder(t *testing.T, name, output string, want []string) {
+	t.Helper()
+	rest := output
+	for _, s := range want {
+		i := strings.Index(rest, s)
+		if i < 0 {
+			t.Errorf("%s does not contain %q after the previous matches:\n%s", name, s, output)
+			return
+		}
+		rest = rest[i+len(s):]
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	workdir(t, nil)
+	code, stdout, _ := runArgs("check", "-format", "json", "unknown.vcl", "bad.vcl")
+	if code != 1 {
+		t.Errorf("Exit status %d, want 1", code)
+	}
+	var diags []struct {
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Severity string `json:"severity"`
+		Code     string `json:"code"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &diags); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, stdout)
+	}
+	found := map[string]bool{}
+	for _, d := range diags {
+		found[d.File+":"+d.Code] = d.Severity == "error" && d.Line > 0
+	}
+	for _, key := range []string{"unknown.vcl:variable-access", "bad.vcl:parse-error"} {
+		if !found[key] {
+			t.Errorf("Missing error %s in %+v", key, diags)
+		}
+	}
+
+	// A clean file gives an empty list rather than no output
+	code, stdout, _ = runArgs("parse", "-format", "json", "main.vcl")
+	if code != 0 || strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("Got status %d and %q, want 0 and []", code, stdout)
+	}
+
+	code, stdout, _ = runArgs("vmods", "-format", "json", "std")
+	var modules []struct{ Name string }
+	if err := json.Unmarshal([]byte(stdout), &modules); code != 0 || err != nil || len(modules) != 1 || modules[0].Name != "std" {
+		t.Errorf("vmods -format json std: status %d, error %v, modules %+v", code, err, modules)
+	}
+}
+
+func TestRunSARIF(t *testing.T) {
+	workdir(t, nil)
+	code, stdout, _ := runArgs("parse", "-format", "sarif", "bad.vcl")
+	if code != 1 {
+		t.Errorf("Exit status %d, want 1", code)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, stdout)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("Unexpected SARIF log:\n%s", stdout)
+	}
+	result := log.Runs[0].Results[0]
+	if result.RuleID != "parse-error" || result.Level != "error" || len(result.Locations) != 1 {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+	if loc := result.Locations[0].PhysicalLocation; loc.ArtifactLocation.URI != "bad.vcl" || loc.Region.StartLine != 4 {
+		t.Errorf("Unexpected location %+v", loc)
+	}
+}
+
+func TestRunConfigLayering(t *testing.T) {
+	user := "format: sarif\nanalyzer:\n  unreferenced: off\n"
+	project := "format: json\n"
+
+	tests := []struct {
+		name    string
+		user    string
+		project string
+		args    []string
+		code    int
+		prefix  string // start of standard output
+	}{
+		{"defaults", "", "", []string{"check", "unused.vcl"}, 0, "unused.vcl:7:"},
+		{"user config", user, "", []string{"check", "unused.vcl"}, 0, "{"},
+		{"project over user", user, project, []string{"check", "unused.vcl"}, 0, "[]"},
+		{"flag over project", user, project, []string{"check", "-format", "vim", "unused.vcl"}, 0, ""},
+		{"flag over user", user, project, []string{"check", "-format", "vim", "-unreferenced", "error", "unused.vcl"}, 1, "unused.vcl:7:"},
+		{"project analyzer", "", "analyzer:\n  unreferenced: error\n", []string{"check", "unused.vcl"}, 1, "unused.vcl:7:"},
+		{"invalid project", "", "analyzer:\n  unreferenced: loud\n", []string{"check", "unused.vcl"}, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extra := map[string]string{}
+			if tt.user != "" {
+				extra["xdg/vclparser/config.yaml"] = tt.user
+			}
+			if tt.project != "" {
+				extra[".vclparser.yaml"] = tt.project
+			}
+			workdir(t, extra)
+			code, stdout, stderr := runArgs(tt.args...)
+			if code != tt.code {
+				t.Errorf("Exit status %d, want %d\nstdout:\n%s\nstderr:\n%s", code, tt.code, stdout, stderr)
+			}
+			if !strings.HasPrefix(stdout, tt.prefix) || (tt.prefix == "" && stdout != "") {
+				t.Errorf("Output does not start with %q:\n%s", tt.prefix, stdout)
+			}
+		})
+	}
+
+	// config show lists the sources in order of precedence
+	dir := workdir(t, map[string]string{"xdg/vclparser/config.yaml": user, ".vclparser.yaml": project})
+	code, stdout, _ := runArgs("config", "show", "-jobs", "3")
+	if code != 0 {
+		t.Fatalf("Exit status %d", code)
+	}
+	userPath := filepath.Join(dir, "xdg", "vclparser", "config.yaml")
+	projectPath := filepath.Join(dir, ".vclparser.yaml")
+	containsInOrder(t, "stdout", stdout, []string{
+		"built-in defaults", userPath, projectPath, "command line: -jobs",
+		"format: json", "unreferenced: \"off\"", "jobs: 3",
+	})
+}
+
+func TestRunWrite(t *testing.T) {
+	dir := workdir(t, nil)
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if code, stdout, _ := runArgs("fmt", "-w", "ugly.vcl"); code != 0 || stdout != "" {
+		t.Errorf("fmt -w: status %d, output %q", code, stdout)
+	}
+	if got := read("ugly.vcl"); !strings.Contains(got, "\n    .host = ") {
+		t.Errorf("fmt -w did not format the file:\n%s", got)
+	}
+
+	if code, stdout, _ := runArgs("migrate", "-w", "old.vcl"); code != 0 || stdout != "" {
+		t.Errorf("migrate -w: status %d, output %q", code, stdout)
+	}
+	if got := read("old.vcl"); !strings.HasPrefix(got, "vcl 4.1;") {
+		t.Errorf("migrate -w did not migrate the file:\n%s", got)
+	}
+
+	if code, stdout, _ := runArgs("rename", "-w", "main.vcl", "backend", "default", "origin"); code != 0 || stdout != "" {
+		t.Errorf("rename -w: status %d, output %q", code, stdout)
+	}
+	if got := read("backends.vcl"); !strings.Contains(got, "backend origin {") {
+		t.Errorf("rename -w did not rename the declaration:\n%s", got)
+	}
+	if got := read("main.vcl"); !strings.Contains(got, "req.backend_hint = origin;") {
+		t.Errorf("rename -w did not rename the reference:\n%s", got)
+	}
+
+	coverage := filepath.Join(dir, "coverage.lcov")
+	if code, _, _ := runArgs("test", "-coverprofile", coverage, "suite.yaml"); code != 0 {
+		t.Errorf("test -coverprofile: status %d", code)
+	}
+	if got := read("coverage.lcov"); !strings.Contains(got, "SF:main.vcl") {
+		t.Errorf("Unexpected coverage profile:\n%s", got)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/migrate"
+	"github.com/perbu/vclparser/pkg/report"
+)
+
+// runMigrate migrates VCL 4.0 files to VCL 4.1. It prints a unified diff of
+// the changes, or with -w rewrites the files, and reports the constructs
+// needing manual attention as warnings.
+func runMigrate(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	write := fs.Bool("w", false, "write the result to the file instead of printing a diff")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse migrate [flags] file.vcl...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	var manual []analyzer.Diagnostic
+	for _, filename := range fs.Args() {
+		src, err := readInput(filename)
+		if err != nil {
+			return err
+		}
+		result, err := migrate.Migrate(src, filename)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		manual = append(manual, result.Manual...)
+
+		if !*write || filename == "-" {
+			fmt.Fprint(stdout, result.Diff)
+			continue
+		}
+		if result.Source != src {
+			info, err := os.Stat(filename)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, []byte(result.Source), info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(manual) > 0 {
+		return report.Write(stderr, format, manual)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/ast/astjson"
+	"github.com/perbu/vclparser/pkg/report"
+)
+
+// runParse parses each file and prints the parse errors, without semantic
+// analysis. It returns exitError(1) when any file failed to parse.
+func runParse(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse parse [flags] file.vcl...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	var all []analyzer.Diagnostic
+	for _, filename := range fs.Args() {
+		_, diags, err := parseFile(filename, parses)
+		if err != nil {
+			return err
+		}
+		all = append(all, diags...)
+	}
+	return writeDiagnostics(stdout, format, all)
+}
+
+// runAST prints the syntax tree of a file, as an indented outline or as the
+// JSON document of the astjson package
+func runAST(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ast", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	asJSON := fs.Bool("json", false, "print the tree as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse ast [flags] file.vcl\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+	program, diags, err := parseFile(fs.Arg(0), parses)
+	if err != nil {
+		return err
+	}
+	if len(diags) > 0 {
+		return writeDiagnostics(stdout, format, diags)
+	}
+
+	if *asJSON {
+		data, err := astjson.MarshalIndent(program, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(stdout, "%s\n", data)
+		return err
+	}
+
+	depth := 0
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		fmt.Fprintf(stdout, "%s%s", strings.Repeat("  ", depth), c.Node())
+		if start := c.Node().Start(); start.Line > 0 {
+			fmt.Fprintf(stdout, " %s", start)
+		}
+		fmt.Fprintln(stdout)
+		depth++
+		return true
+	}, func(c *ast.Cursor) bool {
+		depth--
+		return true
+	})
+	return nil
+}
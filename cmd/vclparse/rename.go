@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+// renameKinds maps the kinds accepted on the command line to symbol kinds
+var renameKinds = map[string]types.SymbolKind{
+	"backend": types.SymbolBackend,
+	"acl":     types.SymbolACL,
+	"probe":   types.SymbolProbe,
+	"sub":     types.SymbolSubroutine,
+}
+
+// runRename renames a backend, ACL, probe or subroutine throughout a main
+// VCL file and the files it includes. It lists the edits, or with -w
+// applies them to each file they belong in.
+func runRename(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("rename", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	write := fs.Bool("w", false, "write the changes to the files instead of listing them")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse rename [flags] main.vcl backend|acl|probe|sub old new\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() != 4 {
+		fs.Usage()
+		return exitError(2)
+	}
+	kind, ok := renameKinds[fs.Arg(1)]
+	if !ok {
+		return fmt.Errorf("cannot rename a %s", fs.Arg(1))
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	program, resolver, err := resolveIncludes(fs.Arg(0), cfg, parses)
+	if err != nil {
+		return err
+	}
+	_, edits, err := analyzer.Rename(program, kind, fs.Arg(2), fs.Arg(3))
+	if err != nil {
+		return err
+	}
+
+	byFile := analyzer.EditsByFile(edits, resolver.SourceMap().Path)
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	wd, _ := os.Getwd()
+	for _, file := range files {
+		if file == "" {
+			return fmt.Errorf("cannot tell which file %d edits belong in", len(byFile[file]))
+		}
+		if !*write {
+			name := file
+			if rel, err := filepath.Rel(wd, file); err == nil {
+				name = rel
+			}
+			for _, e := range byFile[file] {
+				fmt.Fprintf(stdout, "%s:%d:%d: %s -> %s\n", name, e.Start.Line, e.Start.Column, fs.Arg(2), e.NewText)
+			}
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		out, err := analyzer.ApplyEdits(string(src), byFile[file])
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if err := os.WriteFile(file, []byte(out), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+
+	"google.golang.org/grpc"
+
+	"github.com/perbu/vclparser/pkg/grpcserver"
+)
+
+// runServe serves the VCLParser gRPC service until interrupted
+func runServe(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	listen := fs.String("listen", "127.0.0.1:50051", "address to listen on")
+	root := fs.String("root", "", "directory ResolveIncludes may read files from; without it requests must carry their files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse serve [flags]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	registry, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	options := []grpcserver.Option{grpcserver.WithRegistry(registry)}
+	if *root != "" {
+		options = append(options, grpcserver.WithRoot(*root))
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer()
+	grpcserver.New(options...).Register(server)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		server.GracefulStop()
+	}()
+	fmt.Fprintf(stderr, "vclparse: serving on %s\n", listener.Addr())
+	return server.Serve(listener)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/perbu/vclparser/pkg/report"
+	"github.com/perbu/vclparser/pkg/sim"
+	"github.com/perbu/vclparser/pkg/vcltest"
+)
+
+// runTest runs YAML test suites against the VCL they name with the
+// simulator, optionally recording which statements ran. It returns
+// exitError(1) when a test fails or cannot run.
+func runTest(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	verbose := fs.Bool("v", false, "list passing tests too")
+	cover := fs.Bool("cover", false, "print statement and branch coverage per subroutine")
+	coverProfile := fs.String("coverprofile", "", "write coverage in lcov format to `file`")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse test [flags] suite.yaml...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	parses, err := newCache(cfg)
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	var profile bytes.Buffer
+	failed := false
+	for _, path := range fs.Args() {
+		suite, err := vcltest.Load(path)
+		if err != nil {
+			return err
+		}
+		if suite.VCL == "" {
+			return fmt.Errorf("%s: no vcl file given", path)
+		}
+		program, diags, err := parseFile(suite.VCL, parses)
+		if err != nil {
+			return err
+		}
+		if len(diags) > 0 {
+			// Report why the suite cannot run, then go on with the next one
+			if err := writeDiagnostics(stdout, format, diags); err != nil && err != exitError(1) {
+				return err
+			}
+			failed = true
+			continue
+		}
+
+		simulator := sim.New(program)
+		coverage := sim.NewCoverage(program)
+		simulator.SetCoverage(coverage)
+
+		passed := 0
+		for _, c := range suite.Tests {
+			outcome := vcltest.RunCase(simulator, c)
+			switch {
+			case outcome.Err != nil:
+				fmt.Fprintf(stdout, "--- ERROR: %s: %v\n", outcome.Name, outcome.Err)
+			case !outcome.Passed():
+				fmt.Fprintf(stdout, "--- FAIL: %s\n", outcome.Name)
+				for _, failure := range outcome.Failures {
+					fmt.Fprintf(stdout, "    %s\n", failure)
+				}
+			default:
+				passed++
+				if *verbose {
+					fmt.Fprintf(stdout, "--- PASS: %s\n", outcome.Name)
+				}
+				continue
+			}
+			failed = true
+		}
+		status := "ok"
+		if passed < len(suite.Tests) {
+			status = "FAIL"
+		}
+		fmt.Fprintf(stdout, "%s\t%s\t%d/%d passed\n", status, path, passed, len(suite.Tests))
+
+		if *cover {
+			if err := coverage.WriteText(stdout); err != nil {
+				return err
+			}
+		}
+		if err := coverage.WriteLCOV(&profile, suite.VCL); err != nil {
+			return err
+		}
+	}
+	if *coverProfile != "" {
+		if err := os.WriteFile(*coverProfile, profile.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return exitError(1)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/report"
+	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// runVMODs lists the VMODs known to the registry or, given module names,
+// the functions and objects they provide
+func runVMODs(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("vmods", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cf := addConfigFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: vclparse vmods [flags] [module...]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError(2)
+	}
+
+	cfg, err := cf.load()
+	if err != nil {
+		return err
+	}
+	format, err := report.ParseFormat(cfg.Format)
+	if err != nil {
+		return err
+	}
+	registry, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		stats := registry.GetModuleStats()
+		names := make([]string, 0, len(stats))
+		for name := range stats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if format == report.FormatJSON {
+			list := make([]vmod.ModuleStats, len(names))
+			for i, name := range names {
+				list[i] = stats[name]
+			}
+			return writeJSON(stdout, list)
+		}
+		for _, name := range names {
+			fmt.Fprintln(stdout, stats[name])
+		}
+		return nil
+	}
+
+	var modules []*vcc.Module
+	for _, name := range fs.Args() {
+		module, ok := registry.GetModule(name)
+		if !ok {
+			return fmt.Errorf("unknown VMOD %s", name)
+		}
+		modules = append(modules, module)
+	}
+	if format == report.FormatJSON {
+		return writeJSON(stdout, modules)
+	}
+	for i, module := range modules {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintln(stdout, module)
+		for _, f := range module.Functions {
+			fmt.Fprintf(stdout, "  %s\n", signature(module.Name+"."+f.Name, f.ReturnType, f.Parameters))
+		}
+		for _, o := range module.Objects {
+			fmt.Fprintf(stdout, "  new %s\n", signature(module.Name+"."+o.Name, "", o.Constructor))
+			for _, m := range o.Methods {
+				fmt.Fprintf(stdout, "    %s\n", signature("."+m.Name, m.ReturnType, m.Parameters))
+			}
+		}
+	}
+	return nil
+}
+
+// signature formats a function, constructor or method like the VCC files
+// declare them
+func signature(name string, ret vcc.VCCType, params []vcc.Parameter) string {
+	args := make([]string, len(params))
+	for i, p := range params {
+		arg := string(p.Type)
+		if p.Name != "" {
+			arg += " " + p.Name
+		}
+		if p.HasDefault {
+			arg += " = " + p.FormatDefault()
+		}
+		if p.Optional {
+			arg = "[" + arg + "]"
+		}
+		args[i] = arg
+	}
+	sig := name + "(" + strings.Join(args, ", ") + ")"
+	if ret != "" {
+		sig = string(ret) + " " + sig
+	}
+	return sig
+}
+
+func writeJSON(stdout io.Writer, v interface{}) error {
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/config"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/vmod"
+	"github.com/perbu/vclparser/pkg/vtc"
+)
+
+// checkVTC checks a varnishtest file: the expectations of its clients and
+// servers, and each VCL it loads with -vcl or -vcl+backend. VCL given to
+// -errvcl is meant to fail and is skipped. Positions are translated back
+// to the VTC file.
+func checkVTC(filename string, registry *vmod.Registry, cfg *config.Config, parses *cache.Cache) ([]analyzer.Diagnostic, error) {
+	input, err := readInput(filename)
+	if err != nil {
+		return nil, err
+	}
+	file, err := vtc.Parse(input, filename)
+	var syntaxErr *vtc.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return []analyzer.Diagnostic{{
+			Filename: filename,
+			Position: lexer.Position{Line: syntaxErr.Line},
+			Severity: analyzer.SeverityError,
+			Code:     "vtc-syntax",
+			Message:  syntaxErr.Message,
+		}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var diags []analyzer.Diagnostic
+	for _, problem := range file.Check() {
+		diags = append(diags, analyzer.Diagnostic{
+			Filename: filename,
+			Position: lexer.Position{Line: problem.Line},
+			Severity: analyzer.SeverityWarning,
+			Code:     "vtc-expect",
+			Message:  problem.Message,
+		})
+	}
+
+	for _, v := range file.Varnishes {
+		for i, vcl := range v.VCLs {
+			if vcl.ExpectedError != "" {
+				continue
+			}
+			source, header := vcl.Source()
+			found, err := checkSource(source, fmt.Sprintf("%s:%s#%d", filename, v.Name, i+1), false, registry, cfg, parses)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range found {
+				d.Filename = filename
+				d.Position = vtcPosition(vcl, header, d.Position)
+				d.EndPosition = vtcPosition(vcl, header, d.EndPosition)
+				diags = append(diags, d)
+			}
+		}
+	}
+	return diags, nil
+}
+
+// vtcPosition translates a position in the source of an embedded VCL to
+// the VTC file. Positions in the lines varnishtest adds point at the
+// start of the VCL block.
+func vtcPosition(vcl vtc.VCL, header int, pos lexer.Position) lexer.Position {
+	if pos.Line == 0 {
+		return pos
+	}
+	line := pos.Line - header
+	if line < 1 {
+		return lexer.Position{Line: vcl.Line, Column: vcl.Column}
+	}
+	translated := lexer.Position{Line: vcl.Line + line - 1, Column: pos.Column}
+	if line == 1 && pos.Column > 0 {
+		translated.Column += vcl.Column - 1
+	}
+	return translated
+}
@@ -5,45 +5,62 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
-	"path/filepath"
 	"strings"
+
+	"github.com/perbu/vclparser/vcclib"
 )
 
-//go:embed vcclib
+// Refresh vcclib from upstream checkouts with
+//
+//	VARNISH_CACHE_SRC=... VARNISH_MODULES_SRC=... go generate
+//
+//go:generate go run ./cmd/vccsync -varnish-cache=${VARNISH_CACHE_SRC} -varnish-modules=${VARNISH_MODULES_SRC} -out=vcclib
+
+// VCCManifestFile is the file in vcclib recording where the embedded VCC
+// files come from. cmd/vccsync writes it when refreshing vcclib.
+const VCCManifestFile = vcclib.ManifestFile
+
+// VCCManifest records the upstream release each embedded VCC file was copied
+// from
+type VCCManifest = vcclib.Manifest
+
+// VCCSource is the origin of an embedded VCC file
+type VCCSource = vcclib.Source
+
+//go:embed vcclib/*.vcc vcclib/manifest.json
 var embeddedVCCFiles embed.FS
 
-// GetEmbeddedVCCFiles returns the embedded filesystem containing all VCC files
+// GetEmbeddedVCCFiles returns the embedded filesystem containing all VCC
+// files, such as vcclib/vmod_std.vcc
+//
+// Deprecated: Use EmbeddedVCCFS, which holds the same files at its root.
 func GetEmbeddedVCCFiles() embed.FS {
 	return embeddedVCCFiles
 }
 
-// ListEmbeddedVCCFiles returns a list of all embedded VCC file paths
-func ListEmbeddedVCCFiles() ([]string, error) {
-	var vccFiles []string
-
-	err := fs.WalkDir(embeddedVCCFiles, ".", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if !d.IsDir() && strings.HasSuffix(strings.ToLower(path), ".vcc") {
-			vccFiles = append(vccFiles, path)
-		}
-
-		return nil
-	})
+// EmbeddedVCCFS returns the filesystem of the embedded VCC files, which
+// holds them, such as vmod_std.vcc, and the manifest at its root
+func EmbeddedVCCFS() fs.FS {
+	return vcclib.FS()
+}
 
-	return vccFiles, err
+// ListEmbeddedVCCFiles returns a list of all embedded VCC file paths, such
+// as vcclib/vmod_std.vcc
+func ListEmbeddedVCCFiles() ([]string, error) {
+	names, err := vcclib.List()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		names[i] = "vcclib/" + name
+	}
+	return names, nil
 }
 
 // OpenEmbeddedVCCFile opens a specific embedded VCC file for reading
 func OpenEmbeddedVCCFile(filename string) (io.ReadCloser, error) {
 	// Handle both relative and full paths
-	if !strings.HasPrefix(filename, "vcclib/") {
-		filename = filepath.Join("vcclib", filename)
-	}
-
-	file, err := embeddedVCCFiles.Open(filename)
+	file, err := vcclib.Open(strings.TrimPrefix(filename, "vcclib/"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open embedded VCC file %s: %v", filename, err)
 	}
@@ -66,3 +83,9 @@ func GetEmbeddedVCCContent(filename string) ([]byte, error) {
 
 	return content, nil
 }
+
+// EmbeddedVCCManifest returns the manifest of the embedded VCC files. Files
+// added by hand are not listed.
+func EmbeddedVCCManifest() (*VCCManifest, error) {
+	return vcclib.ReadManifest()
+}
@@ -1,13 +1,13 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/perbu/vclparser/pkg/analyzer"
 	ast2 "github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/ast/astjson"
 	"github.com/perbu/vclparser/pkg/parser"
 	"github.com/perbu/vclparser/pkg/types"
 	"github.com/perbu/vclparser/pkg/vmod"
@@ -121,59 +121,6 @@ func (pp *PrettyPrinter) VisitStringLiteral(node *ast2.StringLiteral) interface{
 	return nil
 }
 
-// JSONExporter exports the AST as JSON
-type JSONExporter struct {
-	ast2.BaseVisitor
-}
-
-func (je *JSONExporter) VisitProgram(node *ast2.Program) interface{} {
-	result := map[string]interface{}{
-		"type": "Program",
-	}
-
-	if node.VCLVersion != nil {
-		result["vclVersion"] = ast2.Accept(node.VCLVersion, je)
-	}
-
-	declarations := make([]interface{}, len(node.Declarations))
-	for i, decl := range node.Declarations {
-		declarations[i] = ast2.Accept(decl, je)
-	}
-	result["declarations"] = declarations
-
-	return result
-}
-
-func (je *JSONExporter) VisitVCLVersionDecl(node *ast2.VCLVersionDecl) interface{} {
-	return map[string]interface{}{
-		"type":    "VCLVersionDecl",
-		"version": node.Version,
-	}
-}
-
-func (je *JSONExporter) VisitBackendDecl(node *ast2.BackendDecl) interface{} {
-	properties := make([]interface{}, len(node.Properties))
-	for i, prop := range node.Properties {
-		properties[i] = map[string]interface{}{
-			"name":  prop.Name,
-			"value": ast2.Accept(prop.Value, je),
-		}
-	}
-
-	return map[string]interface{}{
-		"type":       "BackendDecl",
-		"name":       node.Name,
-		"properties": properties,
-	}
-}
-
-func (je *JSONExporter) VisitStringLiteral(node *ast2.StringLiteral) interface{} {
-	return map[string]interface{}{
-		"type":  "StringLiteral",
-		"value": node.Value,
-	}
-}
-
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: parse_vcl <vcl-file> [--json]")
@@ -213,10 +160,7 @@ func main() {
 
 	if outputJSON {
 		// Export as JSON
-		exporter := &JSONExporter{}
-		result := ast2.Accept(program, exporter)
-
-		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		jsonBytes, err := astjson.MarshalIndent(program, "", "  ")
 		if err != nil {
 			log.Fatalf("JSON marshal error: %v", err)
 		}
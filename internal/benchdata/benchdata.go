@@ -0,0 +1,24 @@
+// Package benchdata generates the large VCL configurations the benchmarks
+// and concurrency tests of the other packages work on
+package benchdata
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed tenant.vcl.in
+var tenantTemplate string
+
+// Tenants returns a multi-tenant configuration with a version declaration
+// and the tenant template repeated for tenants tenant0, tenant1 and so on.
+// A hundred tenants make a few hundred KB.
+func Tenants(tenants int) string {
+	var sb strings.Builder
+	sb.WriteString("vcl 4.1;\n")
+	for i := 0; i < tenants; i++ {
+		sb.WriteString(strings.ReplaceAll(tenantTemplate, "TENANT", fmt.Sprintf("tenant%d", i)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,23 @@
+// Package pathutil holds the path checks shared by the include resolver,
+// the VMOD registry and the analyzer
+package pathutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsUnsafe reports whether a path is absolute or climbs out of the
+// directory it is resolved against, which varnishd only accepts with
+// vcc_unsafe_path on
+func IsUnsafe(path string) bool {
+	if filepath.IsAbs(path) {
+		return true
+	}
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == ".." {
+			return true
+		}
+	}
+	return false
+}
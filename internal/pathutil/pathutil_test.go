@@ -0,0 +1,23 @@
+package pathutil
+
+import "testing"
+
+func TestIsUnsafe(t *testing.T) {
+	tests := []struct {
+		path   string
+		unsafe bool
+	}{
+		{"backends.vcl", false},
+		{"conf.d/recv.vcl", false},
+		{"./recv.vcl", false},
+		{"..vcl", false},
+		{"/etc/varnish/main.vcl", true},
+		{"../shared.vcl", true},
+		{"conf.d/../../shared.vcl", true},
+	}
+	for _, tt := range tests {
+		if got := IsUnsafe(tt.path); got != tt.unsafe {
+			t.Errorf("IsUnsafe(%q) = %v, want %v", tt.path, got, tt.unsafe)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package strutil
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"host", "host", 0},
+		{"hots", "host", 1},
+		{"first_byte_timout", "first_byte_timeout", 1},
+		{"", "port", 4},
+		{"backedn", "backend", 1},
+		{"sett", "set", 1},
+	}
+
+	for _, test := range tests {
+		if got := EditDistance(test.a, test.b); got != test.expected {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.expected)
+		}
+	}
+}
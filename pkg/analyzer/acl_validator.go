@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// aclNetwork is an ACL entry that names an IP address, with the mask applied
+type aclNetwork struct {
+	entry *ast.ACLEntry
+	net   *net.IPNet
+}
+
+// String formats the network the way it is written in the ACL
+func (n aclNetwork) String() string {
+	s := fmt.Sprintf("%q", n.entry.Host)
+	if n.entry.PrefixLen >= 0 {
+		s += fmt.Sprintf("/%d", n.entry.PrefixLen)
+	}
+	if n.entry.Negated {
+		s = "!" + s
+	}
+	return s
+}
+
+// contains reports whether every address of other is in n
+func (n aclNetwork) contains(other aclNetwork) bool {
+	ones, _ := n.net.Mask.Size()
+	otherOnes, _ := other.net.Mask.Size()
+	return len(n.net.IP) == len(other.net.IP) && ones <= otherOnes && n.net.Contains(other.net.IP)
+}
+
+// ACLValidator checks the address entries of each ACL against each other.
+// Varnish matches the most specific entry rather than the first one, so
+// entry order never matters, but entries can still be redundant, conflict
+// or have no effect:
+//
+//   - the same network listed twice is redundant, or an error when only one
+//     of them is negated
+//   - a network inside a larger one of the same kind is redundant
+//   - a negated network outside every other entry excludes nothing, since
+//     addresses that match no entry do not match the ACL anyway
+//   - a network inside a larger one of the opposite kind takes precedence
+//     over it, which is reported for information
+//
+// Addresses with bits set beyond their mask are also reported. Host names
+// are not resolved and are left out.
+type ACLValidator struct {
+	diagnostics []Diagnostic
+}
+
+// NewACLValidator creates a new ACL validator
+func NewACLValidator() *ACLValidator {
+	return &ACLValidator{}
+}
+
+// Validate checks every ACL in the program
+func (av *ACLValidator) Validate(program *ast.Program) []Diagnostic {
+	av.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		if acl, ok := decl.(*ast.ACLDecl); ok {
+			av.validateACL(acl)
+		}
+	}
+
+	return av.diagnostics
+}
+
+func (av *ACLValidator) validateACL(acl *ast.ACLDecl) {
+	var networks []aclNetwork
+	for _, entry := range acl.Entries {
+		if network, ok := av.network(entry); ok {
+			networks = append(networks, network)
+		}
+	}
+
+	for i, n := range networks {
+		av.compare(acl, n, networks[:i], networks[i+1:])
+	}
+}
+
+// network parses an entry that is an IP address, reporting host bits set
+// beyond the mask
+func (av *ACLValidator) network(entry *ast.ACLEntry) (aclNetwork, bool) {
+	ip := net.ParseIP(entry.Host)
+	if ip == nil {
+		return aclNetwork{}, false
+	}
+	bits := 128
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits = v4, 32
+	}
+	ones := entry.PrefixLen
+	if ones < 0 {
+		ones = bits
+	}
+	if ones > bits {
+		// Reported by the parser
+		return aclNetwork{}, false
+	}
+	ipnet := &net.IPNet{IP: ip.Mask(net.CIDRMask(ones, bits)), Mask: net.CIDRMask(ones, bits)}
+	if !ipnet.IP.Equal(ip) {
+		av.add(entry, SeverityWarning, "acl-mask", fmt.Sprintf(
+			"%s has bits set beyond the /%d mask; Varnish uses %s/%d", entry.Host, ones, ipnet.IP, ones))
+	}
+	return aclNetwork{entry: entry, net: ipnet}, true
+}
+
+// compare reports how n relates to the entries listed before and after it
+// in the same ACL
+func (av *ACLValidator) compare(acl *ast.ACLDecl, n aclNetwork, before, after []aclNetwork) {
+	// Identical networks are reported once, on the later entry
+	for _, other := range before {
+		if other.contains(n) && n.contains(other) {
+			if other.entry.Negated != n.entry.Negated {
+				av.add(n.entry, SeverityError, "acl-conflict", fmt.Sprintf(
+					"%s conflicts with %s in acl %s", n, other, acl.Name))
+			} else {
+				av.add(n.entry, SeverityWarning, "acl-overlap", fmt.Sprintf(
+					"%s is listed twice in acl %s", n, acl.Name))
+			}
+			return
+		}
+	}
+	for _, other := range after {
+		if other.contains(n) && n.contains(other) {
+			return
+		}
+	}
+
+	// The most specific larger network decides what n overrides
+	var parent *aclNetwork
+	for _, group := range [][]aclNetwork{before, after} {
+		for i := range group {
+			other := group[i]
+			if !other.contains(n) {
+				continue
+			}
+			if parent == nil || parent.contains(other) {
+				parent = &group[i]
+			}
+		}
+	}
+
+	switch {
+	case parent == nil && n.entry.Negated:
+		av.add(n.entry, SeverityWarning, "acl-negation", fmt.Sprintf(
+			"%s excludes addresses no other entry of acl %s includes, so it has no effect", n, acl.Name))
+	case parent == nil:
+	case parent.entry.Negated == n.entry.Negated:
+		av.add(n.entry, SeverityWarning, "acl-overlap", fmt.Sprintf(
+			"%s is already covered by %s in acl %s", n, parent, acl.Name))
+	default:
+		av.add(n.entry, SeverityInfo, "acl-override", fmt.Sprintf(
+			"%s overrides part of %s in acl %s; Varnish uses the most specific entry, whatever the order",
+			n, parent, acl.Name))
+	}
+}
+
+func (av *ACLValidator) add(node ast.Node, severity Severity, code, message string) {
+	av.diagnostics = append(av.diagnostics, NewDiagnostic(node, severity, code, message))
+}
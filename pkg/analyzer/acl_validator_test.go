@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestACLValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+		codes    []string
+	}{
+		{
+			name: "distinct networks",
+			vclCode: `vcl 4.1;
+				acl purge {
+					"localhost";
+					"127.0.0.1";
+					"10.0.0.0"/8;
+					"192.168.0.0"/16;
+					"fd00::"/8;
+				}`,
+		},
+		{
+			name: "duplicate entry",
+			vclCode: `vcl 4.1;
+				acl purge {
+					"10.0.0.0"/8;
+					"10.0.0.0"/8;
+				}`,
+			expected: []string{`"10.0.0.0"/8 is listed twice in acl purge`},
+			codes:    []string{"acl-overlap"},
+		},
+		{
+			name: "conflicting entries",
+			vclCode: `vcl 4.1;
+				acl purge {
+					"192.168.1.1";
+					!"192.168.1.1"/32;
+				}`,
+			expected: []string{`!"192.168.1.1"/32 conflicts with "192.168.1.1" in acl purge`},
+			codes:    []string{"acl-conflict"},
+		},
+		{
+			name: "entry covered by a broader one",
+			vclCode: `vcl 4.1;
+				acl internal {
+					"10.1.0.0"/16;
+					"10.0.0.0"/8;
+				}`,
+			expected: []string{`"10.1.0.0"/16 is already covered by "10.0.0.0"/8 in acl internal`},
+			codes:    []string{"acl-overlap"},
+		},
+		{
+			name: "negation inside a broader entry",
+			vclCode: `vcl 4.1;
+				acl internal {
+					"10.0.0.0"/8;
+					!"10.0.0.0"/24;
+				}`,
+			expected: []string{`!"10.0.0.0"/24 overrides part of "10.0.0.0"/8 in acl internal`},
+			codes:    []string{"acl-override"},
+		},
+		{
+			name: "negation without effect",
+			vclCode: `vcl 4.1;
+				acl internal {
+					"10.0.0.0"/8;
+					!"192.168.0.1";
+				}`,
+			expected: []string{`!"192.168.0.1" excludes addresses no other entry of acl internal includes`},
+			codes:    []string{"acl-negation"},
+		},
+		{
+			name: "most specific parent",
+			vclCode: `vcl 4.1;
+				acl internal {
+					"10.0.0.0"/8;
+					!"10.1.0.0"/16;
+					"10.1.2.0"/24;
+				}`,
+			expected: []string{
+				`!"10.1.0.0"/16 overrides part of "10.0.0.0"/8`,
+				`"10.1.2.0"/24 overrides part of !"10.1.0.0"/16`,
+			},
+			codes: []string{"acl-override", "acl-override"},
+		},
+		{
+			name: "host bits beyond the mask",
+			vclCode: `vcl 4.1;
+				acl internal {
+					"10.1.2.3"/8;
+				}`,
+			expected: []string{"10.1.2.3 has bits set beyond the /8 mask; Varnish uses 10.0.0.0/8"},
+			codes:    []string{"acl-mask"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewACLValidator().Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.codes[i] {
+					t.Errorf("diagnostic %d code = %s, want %s", i, diags[i].Code, test.codes[i])
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/perbu/vclparser/pkg/ast"
@@ -12,65 +13,358 @@ import (
 
 // Analyzer performs semantic analysis on VCL AST
 type Analyzer struct {
-	symbolTable       *types.SymbolTable
-	vmodValidator     *VMODValidator
-	returnValidator   *ReturnActionValidator
-	variableValidator *VariableAccessValidator
-	versionValidator  *VersionValidator
-	metadataLoader    *metadata.MetadataLoader
-	errors            []string
+	symbolTable        *types.SymbolTable
+	vmodValidator      *VMODValidator
+	returnValidator    *ReturnActionValidator
+	variableValidator  *VariableAccessValidator
+	versionValidator   *VersionValidator
+	backendValidator   *BackendAssignmentValidator
+	pipeValidator      *PipeValidator
+	conditionValidator *ConditionValidator
+	numericValidator   *NumericRangeValidator
+	syntheticValidator *SyntheticValidator
+	setTypeValidator   *SetTypeValidator
+	regexValidator     *RegexValidator
+	hashValidator      *HashValidator
+	doFlagsValidator   *DoFlagsValidator
+	propertyValidator  *BackendPropertyValidator
+	unrefValidator     *UnreferencedValidator
+	reportUnref        bool
+	flowValidator      *FlowValidator
+	configValidator    *ConfigValidator
+	labelValidator     *LabelValidator
+	headerValidator    *HeaderValidator
+	aclValidator       *ACLValidator
+	lifetimeValidator  *ObjectLifetimeValidator
+	backendAudit       *BackendAuditValidator
+	deprecValidator    *DeprecationValidator
+	reportDeprecated   bool
+	parallelism        int
+	// ctx is the context of a running AnalyzeDiagnosticsContext or
+	// RunRulesContext, nil otherwise
+	ctx            context.Context
+	config         Config
+	metadataLoader *metadata.MetadataLoader
+	registry       *vmod.Registry
+	sourceLocator  SourceLocator
+	errors         []string
 }
 
 // NewAnalyzer creates a new semantic analyzer
 func NewAnalyzer(registry *vmod.Registry) *Analyzer {
+	return NewAnalyzerWithMetadata(registry, metadata.New())
+}
+
+// NewAnalyzerWithMetadata creates a semantic analyzer that checks
+// subroutines, return actions and variables against the given metadata
+// instead of that of Varnish, such as metadata.NewFastly()
+func NewAnalyzerWithMetadata(registry *vmod.Registry, metadataLoader *metadata.MetadataLoader) *Analyzer {
 	symbolTable := types.NewSymbolTable()
 	vmodValidator := NewVMODValidator(registry, symbolTable)
-
-	// Load metadata for return action validation
-	metadataLoader := metadata.New()
+	if meta, err := metadataLoader.GetMetadata(); err == nil {
+		vmodValidator.SetBuiltinModules(meta.Modules)
+	}
 
 	returnValidator := NewReturnActionValidator(metadataLoader)
 	variableValidator := NewVariableAccessValidator(metadataLoader, symbolTable)
 	versionValidator := NewVersionValidator(metadataLoader)
+	backendValidator := NewBackendAssignmentValidator(metadataLoader, symbolTable, registry)
+	pipeValidator := NewPipeValidator(metadataLoader)
+	conditionValidator := NewConditionValidator()
+	numericValidator := NewNumericRangeValidator()
+	syntheticValidator := NewSyntheticValidator(metadataLoader, symbolTable, registry)
+	setTypeValidator := NewSetTypeValidator(metadataLoader, symbolTable, registry)
+	regexValidator := NewRegexValidator(metadataLoader, symbolTable, registry)
+	hashValidator := NewHashValidator()
+	doFlagsValidator := NewDoFlagsValidator(metadataLoader)
+	propertyValidator := NewBackendPropertyValidator(metadataLoader)
+	unrefValidator := NewUnreferencedValidator(symbolTable)
 
 	return &Analyzer{
-		symbolTable:       symbolTable,
-		vmodValidator:     vmodValidator,
-		returnValidator:   returnValidator,
-		variableValidator: variableValidator,
-		versionValidator:  versionValidator,
-		metadataLoader:    metadataLoader,
-		errors:            []string{},
+		symbolTable:        symbolTable,
+		vmodValidator:      vmodValidator,
+		returnValidator:    returnValidator,
+		variableValidator:  variableValidator,
+		versionValidator:   versionValidator,
+		backendValidator:   backendValidator,
+		pipeValidator:      pipeValidator,
+		conditionValidator: conditionValidator,
+		numericValidator:   numericValidator,
+		syntheticValidator: syntheticValidator,
+		setTypeValidator:   setTypeValidator,
+		regexValidator:     regexValidator,
+		hashValidator:      hashValidator,
+		doFlagsValidator:   doFlagsValidator,
+		propertyValidator:  propertyValidator,
+		unrefValidator:     unrefValidator,
+		reportUnref:        true,
+		flowValidator:      NewFlowValidator(),
+		configValidator:    NewConfigValidator(DefaultConfig()),
+		labelValidator:     NewLabelValidator(),
+		headerValidator:    NewHeaderValidator(),
+		aclValidator:       NewACLValidator(),
+		lifetimeValidator:  NewObjectLifetimeValidator(),
+		backendAudit:       NewBackendAuditValidator(),
+		deprecValidator:    NewDeprecationValidator(metadataLoader),
+		reportDeprecated:   true,
+		config:             DefaultConfig(),
+		metadataLoader:     metadataLoader,
+		registry:           registry,
+		errors:             []string{},
 	}
 }
 
-// Analyze performs complete semantic analysis on an AST
-func (a *Analyzer) Analyze(program *ast.Program) []string {
-	a.errors = []string{}
+// SetVarnishVersion validates against a specific Varnish release, such as
+// "7.5" or "6.0-enterprise", instead of accepting everything the metadata
+// knows about. An empty version clears the selection.
+func (a *Analyzer) SetVarnishVersion(version string) error {
+	var release metadata.Release
+	if version != "" {
+		var err error
+		if release, err = metadata.ParseRelease(version); err != nil {
+			return err
+		}
+	}
+	a.returnValidator.SetRelease(release)
+	a.propertyValidator.SetRelease(release)
+	a.versionValidator.SetRelease(release)
+	a.deprecValidator.SetRelease(release)
+	return nil
+}
 
-	// Perform VMOD validation
-	vmodErrors := a.vmodValidator.Validate(program)
-	a.errors = append(a.errors, vmodErrors...)
+// SetConfig makes the analyzer accept what a varnishd with the given
+// parameters and features accepts. Its ErrUnref replaces the level set with
+// SetUnreferenced.
+func (a *Analyzer) SetConfig(config Config) {
+	a.config = config
+	a.configValidator = NewConfigValidator(config)
+	a.reportUnref = true
+	if config.ErrUnref {
+		a.unrefValidator.SetSeverity(SeverityError)
+	} else {
+		a.unrefValidator.SetSeverity(SeverityWarning)
+	}
+}
 
-	// Perform return action validation
-	returnErrors := a.returnValidator.Validate(program)
-	a.errors = append(a.errors, returnErrors...)
+// Config returns the configuration set with SetConfig, or DefaultConfig
+func (a *Analyzer) Config() Config {
+	return a.config
+}
 
-	// Perform variable access validation
-	variableErrors := a.variableValidator.Validate(program)
-	a.errors = append(a.errors, variableErrors...)
+// SetLabels sets the VCL labels known to varnishd, which return
+// (vcl(label)) may switch to. With no labels, any label name is accepted.
+func (a *Analyzer) SetLabels(labels ...string) {
+	a.labelValidator.SetLabels(labels...)
+}
 
-	// Perform VCL version compatibility validation
-	versionErrors := a.versionValidator.Validate(program)
-	a.errors = append(a.errors, versionErrors...)
+// SetHeaderPolicy sets the headers that may not be set, such as internal
+// headers that must not reach clients, and the exceptions to them. Entries
+// are header names like "X-Backend-*" or variables like
+// "resp.http.X-Backend-*", and match regardless of case.
+func (a *Analyzer) SetHeaderPolicy(allow, deny []string) {
+	a.headerValidator.SetHeaderPolicy(allow, deny)
+}
 
-	// TODO: Add other semantic analysis passes here
-	// - Type checking
-	// - Control flow analysis
+// RegisterCallCheck adds a check of the arguments of calls to a built-in or
+// VMOD function, run by the VMOD validation next to DefaultCallChecks
+func (a *Analyzer) RegisterCallCheck(check CallCheck) {
+	a.vmodValidator.RegisterCallCheck(check)
+}
 
+// SetUnreferenced sets how backends, ACLs, probes and subroutines that are
+// never used are reported: "warning" (the default), "error" as varnishd does
+// with vcc_err_unref on, or "off".
+func (a *Analyzer) SetUnreferenced(level string) error {
+	switch level {
+	case "", "warning":
+		a.reportUnref = true
+		a.unrefValidator.SetSeverity(SeverityWarning)
+	case "error":
+		a.reportUnref = true
+		a.unrefValidator.SetSeverity(SeverityError)
+	case "off":
+		a.reportUnref = false
+	default:
+		return fmt.Errorf("unknown unreferenced level %q, expected warning, error or off", level)
+	}
+	return nil
+}
+
+// SetDeprecations sets how deprecated constructs such as req.esi or the
+// ban() built-in are reported: "warning" (the default), "info", "error" or
+// "off".
+func (a *Analyzer) SetDeprecations(level string) error {
+	if level == "" {
+		level = "warning"
+	}
+	report, severity, err := optionalLevel("deprecations", level)
+	if err != nil {
+		return err
+	}
+	a.reportDeprecated = report
+	a.deprecValidator.SetSeverity(severity)
+	return nil
+}
+
+// SetFallthrough sets how built-in subroutines that return on some paths
+// but fall through to the built-in VCL on others are reported: "off" (the
+// default), "info", "warning" or "error".
+func (a *Analyzer) SetFallthrough(level string) error {
+	report, severity, err := optionalLevel("fallthrough", level)
+	if err != nil {
+		return err
+	}
+	a.flowValidator.SetFallthrough(report, severity)
+	return nil
+}
+
+// SetBacktracking sets how regular expressions that nest unbounded
+// quantifiers, and may backtrack catastrophically, are reported: "off" (the
+// default), "info", "warning" or "error".
+func (a *Analyzer) SetBacktracking(level string) error {
+	report, severity, err := optionalLevel("backtracking", level)
+	if err != nil {
+		return err
+	}
+	a.regexValidator.SetBacktracking(report, severity)
+	return nil
+}
+
+// SetBackendAudit sets how backends without a health probe, probes no
+// backend uses and backends that requests are never routed to are
+// reported: "off" (the default), "info", "warning" or "error".
+func (a *Analyzer) SetBackendAudit(level string) error {
+	report, severity, err := optionalLevel("backend audit", level)
+	if err != nil {
+		return err
+	}
+	a.backendAudit.SetReport(report, severity)
+	return nil
+}
+
+// optionalLevel parses the level of a check that is off by default
+func optionalLevel(check, level string) (bool, Severity, error) {
+	switch level {
+	case "", "off":
+		return false, SeverityWarning, nil
+	case "info":
+		return true, SeverityInfo, nil
+	case "warning":
+		return true, SeverityWarning, nil
+	case "error":
+		return true, SeverityError, nil
+	}
+	return false, SeverityWarning, fmt.Errorf("unknown %s level %q, expected info, warning, error or off", check, level)
+}
+
+// Metadata returns the metadata the analyzer validates against. Overlays
+// merged into it, for instance with LoadOverlayFile, apply to subsequent
+// analysis.
+func (a *Analyzer) Metadata() *metadata.MetadataLoader {
+	return a.metadataLoader
+}
+
+// Analyze performs complete semantic analysis on an AST. Only error-level
+// findings are returned; use AnalyzeDiagnostics to also see warnings.
+func (a *Analyzer) Analyze(program *ast.Program) []string {
+	a.runValidators(program)
+	for _, diag := range a.runDiagnosticValidators(program) {
+		if diag.Severity == SeverityError {
+			a.errors = append(a.errors, legacyMessage(diag))
+		}
+	}
 	return a.errors
 }
 
+// runDiagnosticValidators runs the checks that report diagnostics of
+// varying severity, including registered passes and the rules of registered
+// plugins
+func (a *Analyzer) runDiagnosticValidators(program *ast.Program) []Diagnostic {
+	validators := []interface {
+		Validate(*ast.Program) []Diagnostic
+	}{
+		a.pipeValidator,
+		a.conditionValidator,
+		a.numericValidator,
+		a.syntheticValidator,
+		a.setTypeValidator,
+		a.regexValidator,
+		a.hashValidator,
+		a.doFlagsValidator,
+		a.propertyValidator,
+		a.configValidator,
+		a.labelValidator,
+		a.headerValidator,
+		a.aclValidator,
+		a.lifetimeValidator,
+		a.flowValidator,
+		a.backendAudit,
+	}
+	if a.reportDeprecated {
+		validators = append(validators, a.deprecValidator)
+	}
+	if a.reportUnref {
+		validators = append(validators, a.unrefValidator)
+	}
+
+	var passes []pass
+	for _, v := range validators {
+		v := v
+		if sv, ok := v.(subroutineValidator); ok && a.parallelism > 1 {
+			passes = append(passes, subroutinePasses(program, sv)...)
+			continue
+		}
+		passes = append(passes, diagnosticPass(func() []Diagnostic { return v.Validate(program) }))
+	}
+	passes = append(passes, a.registeredPasses(program)...)
+	diags, _ := a.runPasses(passes)
+	return append(diags, a.runPlugins(program)...)
+}
+
+// runValidators runs the built-in validators that report errors only. Their
+// findings are returned as diagnostics and also recorded as messages for
+// Analyze.
+func (a *Analyzer) runValidators(program *ast.Program) []Diagnostic {
+	a.errors = []string{}
+	var diags []Diagnostic
+
+	// Perform VMOD validation. Restriction violations are only warnings in
+	// lenient mode. It defines the symbols the other passes look up, so it
+	// runs first and on its own.
+	a.vmodValidator.Validate(program)
+	recordReferences(program, a.symbolTable)
+	for _, diag := range a.vmodValidator.Diagnostics() {
+		if a.config.LenientVmodRestrictions && diag.Code == "vmod-restriction" {
+			diag.Severity = SeverityWarning
+		} else if diag.Severity == SeverityError {
+			a.errors = append(a.errors, legacyMessage(diag))
+		}
+		diags = append(diags, diag)
+	}
+
+	validate := func(v interface {
+		Validate(*ast.Program) []string
+		Diagnostics() []Diagnostic
+	}) pass {
+		return func() ([]Diagnostic, []string) {
+			messages := v.Validate(program)
+			return v.Diagnostics(), messages
+		}
+	}
+	passDiags, messages := a.runPasses([]pass{
+		// Return action validation
+		validate(a.returnValidator),
+		// Variable access validation
+		validate(a.variableValidator),
+		// VCL version compatibility validation
+		validate(a.versionValidator),
+		// Backend assignment type checking
+		validate(a.backendValidator),
+	})
+	a.errors = append(a.errors, messages...)
+	return append(diags, passDiags...)
+}
+
 // AnalyzeWithSymbolTable performs complete semantic analysis on an AST and returns validation errors
 // along with the populated symbol table. This is useful when external code needs access to the
 // symbol table for additional processing or symbol lookups after validation.
@@ -84,6 +378,13 @@ func (a *Analyzer) GetSymbolTable() *types.SymbolTable {
 	return a.symbolTable
 }
 
+// ResolvedCall returns how the arguments of a VMOD function or method call
+// of the program last analyzed match the parameters of the callee, or nil
+// if call is not such a call or did not pass validation
+func (a *Analyzer) ResolvedCall(call *ast.CallExpression) *CallResolution {
+	return a.vmodValidator.Resolution(call)
+}
+
 // ValidateVCLFile validates a VCL file with VMOD support using the provided registry.
 // This is a convenience function that creates an analyzer instance and performs complete
 // semantic validation. Returns validation errors and an error if validation fails.
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// BackendAuditValidator connects backends, probes and directors to find
+// gaps in health checking and routing: backends without a health probe,
+// probes no backend or VMOD uses, and backends never added to a director
+// nor assigned to req.backend_hint or bereq.backend. Unlike the
+// unreferenced check, a backend only compared against or passed to
+// std.healthy() still counts as unrouted. The audit is off until enabled
+// with SetReport.
+type BackendAuditValidator struct {
+	report      bool
+	severity    Severity
+	diagnostics []Diagnostic
+}
+
+// NewBackendAuditValidator creates a new backend audit
+func NewBackendAuditValidator() *BackendAuditValidator {
+	return &BackendAuditValidator{severity: SeverityWarning}
+}
+
+// SetReport sets whether the audit runs, and with which severity its
+// findings are reported
+func (bv *BackendAuditValidator) SetReport(report bool, severity Severity) {
+	bv.report = report
+	bv.severity = severity
+}
+
+// Validate audits the backends and probes of the program
+func (bv *BackendAuditValidator) Validate(program *ast.Program) []Diagnostic {
+	bv.diagnostics = nil
+	if !bv.report {
+		return nil
+	}
+
+	var backends []*ast.BackendDecl
+	var probes []*ast.ProbeDecl
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.BackendDecl:
+			if d != nil {
+				backends = append(backends, d)
+			}
+		case *ast.ProbeDecl:
+			if d != nil {
+				probes = append(probes, d)
+			}
+		}
+	}
+
+	// Names attached as probes and names routed to, from backend
+	// properties, director members and backend assignments
+	attached := make(map[string]bool)
+	routed := make(map[string]bool)
+	defaultProbe := false
+	for _, p := range probes {
+		defaultProbe = defaultProbe || p.Name == "default"
+	}
+	for _, b := range backends {
+		if probe := backendProperty(b.Properties, "probe"); probe != nil {
+			attached[ast.VariableName(probe)] = true
+		} else if defaultProbe {
+			attached["default"] = true
+		}
+	}
+	walkNodes(program, func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.CallExpression:
+			name := ast.VariableName(n.Function)
+			if strings.HasSuffix(name, ".add_backend") && len(n.Arguments) > 0 {
+				routed[ast.VariableName(n.Arguments[0])] = true
+			}
+			// VMOD directors such as dynamic take probes as arguments
+			for _, arg := range n.Arguments {
+				attached[ast.VariableName(arg)] = true
+			}
+			for _, arg := range n.NamedArguments {
+				attached[ast.VariableName(arg)] = true
+			}
+		case *ast.SetStatement:
+			switch strings.ToLower(ast.VariableName(n.Variable)) {
+			case "req.backend_hint", "bereq.backend", "req.backend":
+				routed[ast.VariableName(n.Value)] = true
+			}
+		case *ast.DirectorBackend:
+			routed[ast.VariableName(backendProperty(n.Properties, "backend"))] = true
+		}
+	})
+
+	for i, b := range backends {
+		if backendProperty(b.Properties, "probe") == nil && !defaultProbe {
+			bv.add(b, fmt.Sprintf("backend %s has no health probe", b.Name))
+		}
+		// The first backend, or the one named default, is used for
+		// requests that do not pick one
+		implicit := i == 0 || b.Name == "default"
+		if !implicit && !routed[b.Name] {
+			bv.add(b, fmt.Sprintf("backend %s is neither added to a director nor assigned to req.backend_hint or bereq.backend", b.Name))
+		}
+	}
+	for _, p := range probes {
+		if !attached[p.Name] {
+			bv.add(p, fmt.Sprintf("probe %s is not attached to any backend", p.Name))
+		}
+	}
+	return bv.diagnostics
+}
+
+// backendProperty returns the value of the property called name, or nil
+func backendProperty(properties []*ast.BackendProperty, name string) ast.Expression {
+	for _, prop := range properties {
+		if prop != nil && prop.Name == name {
+			return prop.Value
+		}
+	}
+	return nil
+}
+
+func (bv *BackendAuditValidator) add(decl ast.Declaration, message string) {
+	bv.diagnostics = append(bv.diagnostics, NewDiagnostic(decl, bv.severity, "backend-audit", message))
+}
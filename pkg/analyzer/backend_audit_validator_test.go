@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+const backendAuditVCL = `vcl 4.1;
+
+import directors;
+import std;
+
+probe health {
+	.url = "/health";
+}
+
+probe spare_probe {
+	.url = "/";
+}
+
+backend first {
+	.host = "192.0.2.1";
+	.probe = health;
+}
+
+backend pooled {
+	.host = "192.0.2.2";
+	.probe = { .url = "/"; }
+}
+
+backend api {
+	.host = "192.0.2.3";
+	.probe = health;
+}
+
+backend checked {
+	.host = "192.0.2.4";
+	.probe = health;
+}
+
+sub vcl_init {
+	new pool = directors.round_robin();
+	pool.add_backend(pooled);
+}
+
+sub vcl_recv {
+	if (req.url ~ "^/api") {
+		set req.backend_hint = api;
+	} else if (std.healthy(checked)) {
+		set req.backend_hint = pool.backend();
+	}
+}
+`
+
+func TestBackendAuditValidator(t *testing.T) {
+	program, err := parser.Parse(backendAuditVCL, "test.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bv := NewBackendAuditValidator()
+	if diags := bv.Validate(program); len(diags) != 0 {
+		t.Errorf("Expected no findings while off, got %+v", diags)
+	}
+
+	bv.SetReport(true, SeverityInfo)
+	var messages []string
+	for _, d := range bv.Validate(program) {
+		if d.Code != "backend-audit" || d.Severity != SeverityInfo {
+			t.Errorf("Unexpected code or severity: %+v", d)
+		}
+		messages = append(messages, d.Message)
+	}
+	sort.Strings(messages)
+	want := []string{
+		"backend checked is neither added to a director nor assigned to req.backend_hint or bereq.backend",
+		"probe spare_probe is not attached to any backend",
+	}
+	if len(messages) != len(want) || messages[0] != want[0] || messages[1] != want[1] {
+		t.Errorf("Unexpected findings:\n%v", messages)
+	}
+}
+
+func TestBackendAuditProbes(t *testing.T) {
+	src := `vcl 4.1;
+backend a { .host = "192.0.2.1"; }
+backend b { .host = "192.0.2.2"; }
+sub vcl_recv { set req.backend_hint = b; }
+`
+	program, err := parser.Parse(src, "test.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewAnalyzer(vmod.DefaultRegistry)
+	if err := a.SetBackendAudit("warning"); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, d := range a.AnalyzeDiagnostics(program) {
+		if d.Code == "backend-audit" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected both backends to lack a probe, got %d findings", count)
+	}
+
+	// A probe named default applies to backends without one
+	src = `vcl 4.1;
+probe default { .url = "/"; }
+backend a { .host = "192.0.2.1"; }
+`
+	program, err = parser.Parse(src, "test.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bv := NewBackendAuditValidator()
+	bv.SetReport(true, SeverityWarning)
+	if diags := bv.Validate(program); len(diags) != 0 {
+		t.Errorf("Expected the default probe to cover the backend, got %+v", diags)
+	}
+
+	if err := a.SetBackendAudit("loud"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/internal/strutil"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+)
+
+// BackendPropertyValidator checks backend declaration attributes against the
+// backend property schema in the metadata: attribute names, the releases
+// supporting them and the types of literal values, such as an unquoted
+// .port. Like VCC it also requires exactly one of .host and .path. The
+// schema is data, so attributes added by new Varnish releases are supported
+// by updating the metadata or loading an overlay rather than by changing
+// this validator.
+type BackendPropertyValidator struct {
+	loader      *metadata.MetadataLoader
+	release     metadata.Release
+	diagnostics []Diagnostic
+}
+
+// NewBackendPropertyValidator creates a new backend property validator
+func NewBackendPropertyValidator(loader *metadata.MetadataLoader) *BackendPropertyValidator {
+	return &BackendPropertyValidator{
+		loader: loader,
+	}
+}
+
+// SetRelease selects the Varnish release whose backend attributes are
+// allowed. The zero release accepts every attribute in the schema.
+func (bv *BackendPropertyValidator) SetRelease(release metadata.Release) {
+	bv.release = release
+}
+
+// Validate checks the attributes of every backend declaration
+func (bv *BackendPropertyValidator) Validate(program *ast.Program) []Diagnostic {
+	bv.diagnostics = nil
+
+	schema, err := bv.loader.GetBackendProperties()
+	if err != nil || len(schema) == 0 {
+		return nil
+	}
+
+	for _, decl := range program.Declarations {
+		backend, ok := decl.(*ast.BackendDecl)
+		if !ok {
+			continue
+		}
+		for _, prop := range backend.Properties {
+			bv.validateProperty(backend.Name, prop, schema)
+		}
+		bv.validateAddress(backend, schema)
+	}
+
+	return bv.diagnostics
+}
+
+func (bv *BackendPropertyValidator) validateProperty(backend string, prop *ast.BackendProperty, schema map[string]metadata.BackendProperty) {
+	info, ok := schema[prop.Name]
+	if !ok {
+		msg := fmt.Sprintf("unknown property .%s in backend %s", prop.Name, backend)
+		if suggestion := closestName(prop.Name, schema); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean .%s?)", suggestion)
+		}
+		bv.add(prop, SeverityError, "backend-property", msg)
+		return
+	}
+
+	if err := info.Releases.Check(bv.release); err != nil {
+		bv.add(prop, SeverityError, "backend-property-release", fmt.Sprintf("backend property .%s %v", prop.Name, err))
+	}
+
+	if got := literalType(prop.Value); got != "" && got != info.Type {
+		msg := fmt.Sprintf("backend property .%s must be %s, not %s", prop.Name, info.Type, got)
+		switch {
+		case got == "INT" && info.Type == "STRING":
+			msg += fmt.Sprintf(" (quote it: \"%d\")", prop.Value.(*ast.IntegerLiteral).Value)
+		case (got == "INT" || got == "REAL") && info.Type == "DURATION":
+			msg += " (add a time unit, such as s or ms)"
+		}
+		bv.add(prop.Value, SeverityError, "backend-property-type", msg)
+	}
+
+	if lit, ok := prop.Value.(*ast.StringLiteral); ok && prop.Name == "path" && !strings.HasPrefix(lit.Value, "/") {
+		bv.add(prop.Value, SeverityError, "backend-property-type",
+			fmt.Sprintf("backend property .path must be an absolute path to a Unix domain socket, not %q", lit.Value))
+	}
+}
+
+// validateAddress reports backends with neither or both of .host and .path
+func (bv *BackendPropertyValidator) validateAddress(backend *ast.BackendDecl, schema map[string]metadata.BackendProperty) {
+	if _, ok := schema["host"]; !ok {
+		return
+	}
+	var host, path bool
+	for _, prop := range backend.Properties {
+		switch prop.Name {
+		case "host":
+			host = true
+		case "path":
+			path = true
+		}
+	}
+	switch {
+	case !host && !path:
+		bv.add(backend, SeverityError, "backend-address", fmt.Sprintf("backend %s needs a .host or .path", backend.Name))
+	case host && path:
+		bv.add(backend, SeverityError, "backend-address", fmt.Sprintf("backend %s has both .host and .path; use one of them", backend.Name))
+	}
+}
+
+// literalType returns the type of a literal backend property value, or ""
+// when the value is not a literal, such as a reference to a probe
+func literalType(value ast.Expression) string {
+	switch v := value.(type) {
+	case *ast.StringLiteral:
+		return "STRING"
+	case *ast.IntegerLiteral:
+		return "INT"
+	case *ast.FloatLiteral:
+		return "REAL"
+	case *ast.TimeExpression:
+		return "DURATION"
+	case *ast.BooleanLiteral:
+		return "BOOL"
+	case *ast.ObjectExpression:
+		return "PROBE"
+	case *ast.Identifier:
+		if v.Name == "true" || v.Name == "false" {
+			return "BOOL"
+		}
+	}
+	return ""
+}
+
+// closestName returns the schema entry within two edits of name, if any
+func closestName(name string, schema map[string]metadata.BackendProperty) string {
+	candidates := make([]string, 0, len(schema))
+	for candidate := range schema {
+		candidates = append(candidates, candidate)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := strutil.EditDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+func (bv *BackendPropertyValidator) add(node ast.Node, severity Severity, code, message string) {
+	bv.diagnostics = append(bv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
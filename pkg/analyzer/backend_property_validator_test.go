@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestBackendPropertyValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		release  string
+		overlay  string
+		expected []string
+	}{
+		{
+			name: "known properties",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.port = "8080";
+					.connect_timeout = 1s;
+					.first_byte_timeout = 30s;
+					.max_connections = 100;
+				}`,
+		},
+		{
+			name: "misspelled property",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.first_byte_timout = 30s;
+				}`,
+			expected: []string{"unknown property .first_byte_timout in backend default (did you mean .first_byte_timeout?)"},
+		},
+		{
+			name: "unknown property without suggestion",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.keepalive = 1;
+				}`,
+			expected: []string{"unknown property .keepalive in backend default"},
+		},
+		{
+			name: "newer property without a target release",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.last_byte_timeout = 60s;
+				}`,
+		},
+		{
+			name: "newer property on an older release",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.last_byte_timeout = 60s;
+					.wait_limit = 10;
+				}`,
+			release: "7.4",
+			expected: []string{
+				"backend property .last_byte_timeout requires Varnish 7.6 or later (target: 7.4)",
+				"backend property .wait_limit requires Varnish 7.6 or later",
+			},
+		},
+		{
+			name: "enterprise property on open source",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.ssl = true;
+				}`,
+			release:  "7.5",
+			expected: []string{"backend property .ssl is only available in Varnish Enterprise"},
+		},
+		{
+			name: "literal values of the wrong type",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.port = 8080;
+					.connect_timeout = 5;
+					.max_connections = "100";
+					.ssl = true;
+					.probe = { .url = "/"; };
+				}`,
+			expected: []string{
+				`backend property .port must be STRING, not INT (quote it: "8080")`,
+				"backend property .connect_timeout must be DURATION, not INT (add a time unit, such as s or ms)",
+				"backend property .max_connections must be INT, not STRING",
+			},
+		},
+		{
+			name: "missing address",
+			vclCode: `vcl 4.1;
+				backend default {
+					.port = "8080";
+				}`,
+			expected: []string{"backend default needs a .host or .path"},
+		},
+		{
+			name: "both host and path",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.path = "/run/app.sock";
+				}`,
+			expected: []string{"backend default has both .host and .path"},
+		},
+		{
+			name: "relative socket path",
+			vclCode: `vcl 4.1;
+				backend default {
+					.path = "run/app.sock";
+				}`,
+			expected: []string{`backend property .path must be an absolute path to a Unix domain socket, not "run/app.sock"`},
+		},
+		{
+			name: "property added by an overlay",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.keepalive = 1;
+				}`,
+			overlay: `{"backend_properties": {"keepalive": {"type": "INT"}}}`,
+		},
+		{
+			name: "property removed by an overlay",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.proxy_header = 1;
+				}`,
+			overlay:  `{"backend_properties": {"proxy_header": null}}`,
+			expected: []string{"unknown property .proxy_header"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			loader := metadata.New()
+			if test.overlay != "" {
+				if err := loader.MergeOverlay([]byte(test.overlay)); err != nil {
+					t.Fatalf("Failed to merge overlay: %v", err)
+				}
+			}
+			validator := NewBackendPropertyValidator(loader)
+			if test.release != "" {
+				release, err := metadata.ParseRelease(test.release)
+				if err != nil {
+					t.Fatal(err)
+				}
+				validator.SetRelease(release)
+			}
+
+			diags := validator.Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != SeverityError {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, SeverityError)
+				}
+			}
+		})
+	}
+}
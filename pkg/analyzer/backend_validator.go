@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// BackendAssignmentValidator checks that every assignment to a BACKEND-typed
+// variable (req.backend_hint, bereq.backend, ...) is given a backend: a
+// declared backend, a director method returning BACKEND, or a VMOD function
+// returning BACKEND.
+//
+// It relies on the symbol table populated by the VMOD validator, so it must
+// run after it.
+type BackendAssignmentValidator struct {
+	typeResolver
+	diagnostics []Diagnostic
+}
+
+// NewBackendAssignmentValidator creates a new backend assignment validator
+func NewBackendAssignmentValidator(loader *metadata.MetadataLoader, symbolTable *types.SymbolTable, registry *vmod.Registry) *BackendAssignmentValidator {
+	return &BackendAssignmentValidator{
+		typeResolver: typeResolver{
+			loader:      loader,
+			symbolTable: symbolTable,
+			registry:    registry,
+		},
+	}
+}
+
+// Validate checks all backend assignments in the program. It returns the
+// findings as messages; Diagnostics returns them with positions.
+func (bav *BackendAssignmentValidator) Validate(program *ast.Program) []string {
+	bav.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			bav.walkStatement(sub.Body)
+		}
+	}
+
+	return legacyMessages(bav.diagnostics)
+}
+
+// Diagnostics returns the findings of the last Validate call
+func (bav *BackendAssignmentValidator) Diagnostics() []Diagnostic {
+	return bav.diagnostics
+}
+
+func (bav *BackendAssignmentValidator) walkStatement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		for _, inner := range s.Statements {
+			bav.walkStatement(inner)
+		}
+	case *ast.IfStatement:
+		if s.Then != nil {
+			bav.walkStatement(s.Then)
+		}
+		if s.Else != nil {
+			bav.walkStatement(s.Else)
+		}
+	case *ast.SetStatement:
+		bav.validateSet(s)
+	}
+}
+
+// validateSet checks a single set statement if its target is BACKEND-typed
+func (bav *BackendAssignmentValidator) validateSet(stmt *ast.SetStatement) {
+	target := ast.VariableName(stmt.Variable)
+	if target == "" || bav.variableType(target) != string(vcc.TypeBackend) {
+		return
+	}
+
+	got, detail := bav.expressionType(stmt.Value)
+	if got == "" || got == vcc.TypeBackend {
+		return
+	}
+
+	message := fmt.Sprintf("cannot assign %s to %s (BACKEND)", got, target)
+	if detail != "" {
+		message += ": " + detail
+	}
+	bav.diagnostics = append(bav.diagnostics, NewDiagnostic(stmt, SeverityError, "backend-type", message))
+}
+
+// expressionType returns the VCC type of a backend assignment value along with
+// a hint explaining a mismatch. An empty type means it could not be
+// determined, in which case no error is reported.
+func (bav *BackendAssignmentValidator) expressionType(expr ast.Expression) (vcc.VCCType, string) {
+	switch e := expr.(type) {
+	case *ast.StringLiteral:
+		return vcc.TypeString, fmt.Sprintf("use the backend name without quotes, not \"%s\"", e.Value)
+	case *ast.IntegerLiteral:
+		return vcc.TypeInt, ""
+	case *ast.FloatLiteral:
+		return vcc.TypeReal, ""
+	case *ast.BooleanLiteral:
+		return vcc.TypeBool, ""
+	case *ast.TimeExpression:
+		return vcc.TypeDuration, ""
+	case *ast.BinaryExpression:
+		if e.Operator == "+" {
+			return vcc.TypeString, "string concatenation does not produce a backend"
+		}
+		return "", ""
+	case *ast.ParenthesizedExpression:
+		return bav.expressionType(e.Expression)
+	case *ast.Identifier:
+		return bav.identifierType(e.Name)
+	case *ast.MemberExpression:
+		name := ast.VariableName(e)
+		if t := bav.variableType(name); t != "" {
+			return vcc.VCCType(t), ""
+		}
+		return "", ""
+	case *ast.CallExpression:
+		return bav.callReturnType(e)
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestBackendAssignmentValidator(t *testing.T) {
+	registry := setupTestRegistry(t)
+
+	tests := []struct {
+		name        string
+		body        string
+		expectError string
+	}{
+		{
+			name: "declared backend",
+			body: `sub vcl_recv { set req.backend_hint = default; }`,
+		},
+		{
+			name: "director method",
+			body: `sub vcl_init { new rr = directors.round_robin(); }
+				sub vcl_recv { set req.backend_hint = rr.backend(); }`,
+		},
+		{
+			name: "backend variable",
+			body: `sub vcl_backend_fetch { set bereq.backend = bereq.backend; }`,
+		},
+		{
+			name:        "quoted backend name",
+			body:        `sub vcl_recv { set req.backend_hint = "default"; }`,
+			expectError: "cannot assign STRING to req.backend_hint (BACKEND): use the backend name without quotes",
+		},
+		{
+			name:        "director object without method call",
+			body:        `sub vcl_init { new rr = directors.round_robin(); } sub vcl_recv { set req.backend_hint = rr; }`,
+			expectError: "use rr.backend()",
+		},
+		{
+			name:        "VMOD function returning STRING",
+			body:        `sub vcl_backend_fetch { set bereq.backend = std.toupper("x"); }`,
+			expectError: "std.toupper() returns STRING",
+		},
+		{
+			name: "nested in else branch",
+			body: `sub vcl_recv {
+					if (req.url ~ "^/a") {
+						set req.backend_hint = default;
+					} else if (req.url ~ "^/b") {
+						set req.backend_hint = default;
+					} else {
+						set req.backend_hint = "b";
+					}
+				}`,
+			expectError: "cannot assign STRING to req.backend_hint",
+		},
+		{
+			name: "non-backend variable ignored",
+			body: `sub vcl_recv { set req.http.x-target = "default"; }`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vclCode := `vcl 4.1;
+import std;
+import directors;
+backend default { .host = "127.0.0.1"; }
+` + test.body
+
+			program, err := parser.Parse(vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			errors := NewAnalyzer(registry).Analyze(program)
+			var backendErrors []string
+			for _, e := range errors {
+				if strings.Contains(e, "BACKEND") {
+					backendErrors = append(backendErrors, e)
+				}
+			}
+
+			if test.expectError == "" {
+				if len(backendErrors) != 0 {
+					t.Errorf("Expected no backend errors, got: %v", backendErrors)
+				}
+				return
+			}
+			if len(backendErrors) != 1 {
+				t.Fatalf("Expected 1 backend error, got %d: %v", len(backendErrors), errors)
+			}
+			if !strings.Contains(backendErrors[0], test.expectError) {
+				t.Errorf("Expected error containing %q, got %q", test.expectError, backendErrors[0])
+			}
+		})
+	}
+}
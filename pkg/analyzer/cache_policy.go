@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+// CachePolicy summarizes the cache lifetime decisions of a program, in
+// source order within each group
+type CachePolicy struct {
+	// TTL, Grace and Keep are the assignments of beresp.ttl, beresp.grace
+	// and beresp.keep, and of req.ttl and req.grace, which cap them for a
+	// request
+	TTL   []CacheDecision
+	Grace []CacheDecision
+	Keep  []CacheDecision
+	// Headers are the sets and unsets of the headers caches and clients
+	// derive lifetimes from, such as Cache-Control and Expires, on beresp
+	// and resp
+	Headers []CacheDecision
+	// Bypass are the decisions not to cache: return (pass), return (pipe),
+	// including hit-for-pass with return (pass(DURATION)) in
+	// vcl_backend_response, and beresp.uncacheable
+	Bypass []CacheDecision
+}
+
+// CacheDecision is one statement deciding how a response is cached
+type CacheDecision struct {
+	Sub string
+	// Action is "set", "unset" or "return"
+	Action string
+	// Target is the variable set or unset, or the return action
+	Target string
+	// Value is the value assigned, or the arguments of the return action,
+	// as formatted VCL. It is empty for unsets and plain returns.
+	Value string
+	// Conditions are the conditions of the if statements the decision is
+	// nested in, outermost first, as formatted VCL. Conditions of else
+	// branches are negated.
+	Conditions []string
+	Position   lexer.Position
+}
+
+// cacheHeaders are the headers that carry cache lifetimes, in lower case
+var cacheHeaders = map[string]bool{
+	"cache-control":     true,
+	"surrogate-control": true,
+	"expires":           true,
+	"age":               true,
+	"pragma":            true,
+}
+
+// ExtractCachePolicy collects the cache lifetime decisions of program, so
+// the effective caching policy can be reviewed without reading all of it.
+// The extraction is syntactic: decisions in custom subroutines are listed
+// under those subroutines, whichever built-in subroutine calls them.
+func ExtractCachePolicy(program *ast.Program) *CachePolicy {
+	e := &policyExtractor{policy: &CachePolicy{}}
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			e.sub = sub.Name
+			e.statement(sub.Body, nil)
+		}
+	}
+	return e.policy
+}
+
+// policyExtractor holds the state of one ExtractCachePolicy run
+type policyExtractor struct {
+	policy *CachePolicy
+	sub    string
+}
+
+// statement collects the decisions in stmt, which is reached under
+// conditions
+func (e *policyExtractor) statement(stmt ast.Statement, conditions []string) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		for _, inner := range s.Statements {
+			e.statement(inner, conditions)
+		}
+	case *ast.IfStatement:
+		condition := printer.Expression(s.Condition)
+		e.statement(s.Then, withCondition(conditions, condition))
+		if s.Else != nil {
+			e.statement(s.Else, withCondition(conditions, "!("+condition+")"))
+		}
+	case *ast.SetStatement:
+		name := ast.VariableName(s.Variable)
+		value := printer.Expression(s.Value)
+		if s.Operator != "=" {
+			value = s.Operator + " " + value
+		}
+		if group := e.variableGroup(name); group != nil {
+			*group = append(*group, e.decision(s, "set", name, value, conditions))
+		} else if name == "beresp.uncacheable" {
+			e.policy.Bypass = append(e.policy.Bypass, e.decision(s, "set", name, value, conditions))
+		}
+	case *ast.UnsetStatement:
+		name := ast.VariableName(s.Variable)
+		if e.isCacheHeader(name) {
+			e.policy.Headers = append(e.policy.Headers, e.decision(s, "unset", name, "", conditions))
+		}
+	case *ast.ReturnStatement:
+		action := returnActionName(s.Action)
+		if action != "pass" && action != "pipe" {
+			return
+		}
+		value := ""
+		if call, ok := ast.Unparen(s.Action).(*ast.CallExpression); ok {
+			args := make([]string, len(call.Arguments))
+			for i, arg := range call.Arguments {
+				args[i] = printer.Expression(arg)
+			}
+			value = strings.Join(args, ", ")
+		}
+		e.policy.Bypass = append(e.policy.Bypass, e.decision(s, "return", action, value, conditions))
+	}
+}
+
+// variableGroup returns the group assignments to name belong to, or nil
+func (e *policyExtractor) variableGroup(name string) *[]CacheDecision {
+	switch name {
+	case "beresp.ttl", "req.ttl":
+		return &e.policy.TTL
+	case "beresp.grace", "req.grace":
+		return &e.policy.Grace
+	case "beresp.keep":
+		return &e.policy.Keep
+	}
+	if e.isCacheHeader(name) {
+		return &e.policy.Headers
+	}
+	return nil
+}
+
+// isCacheHeader reports whether name is a lifetime header of beresp or resp
+func (e *policyExtractor) isCacheHeader(name string) bool {
+	for _, prefix := range []string{"beresp.http.", "resp.http."} {
+		if header, ok := strings.CutPrefix(name, prefix); ok {
+			return cacheHeaders[strings.ToLower(header)]
+		}
+	}
+	return false
+}
+
+func (e *policyExtractor) decision(node ast.Node, action, target, value string, conditions []string) CacheDecision {
+	return CacheDecision{
+		Sub:        e.sub,
+		Action:     action,
+		Target:     target,
+		Value:      value,
+		Conditions: conditions,
+		Position:   node.Start(),
+	}
+}
+
+// withCondition returns conditions with condition appended, without
+// sharing the backing array of conditions
+func withCondition(conditions []string, condition string) []string {
+	return append(conditions[:len(conditions):len(conditions)], condition)
+}
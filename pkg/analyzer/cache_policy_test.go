@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractCachePolicy(t *testing.T) {
+	program := parseVCL(t, `vcl 4.1;
+
+sub vcl_recv {
+    if (req.http.Authorization) {
+        return (pass);
+    }
+    set req.grace = 10s;
+}
+
+sub vcl_backend_response {
+    if (beresp.status >= 500) {
+        set beresp.ttl = 0s;
+        return (pass(30s));
+    } else if (bereq.url ~ "^/static/") {
+        set beresp.ttl = 1d;
+        unset beresp.http.Set-Cookie;
+        set beresp.http.Cache-Control = "public, max-age=86400";
+    } else {
+        set beresp.uncacheable = true;
+    }
+    set beresp.grace = 1h;
+    set beresp.keep = 2h;
+}
+
+sub vcl_deliver {
+    unset resp.http.Age;
+}
+`)
+	policy := ExtractCachePolicy(program)
+
+	expectedTTL := []CacheDecision{
+		{Sub: "vcl_backend_response", Action: "set", Target: "beresp.ttl", Value: "0s",
+			Conditions: []string{"beresp.status >= 500"}},
+		{Sub: "vcl_backend_response", Action: "set", Target: "beresp.ttl", Value: "1d",
+			Conditions: []string{"!(beresp.status >= 500)", `bereq.url ~ "^/static/"`}},
+	}
+	if len(policy.TTL) != len(expectedTTL) {
+		t.Fatalf("Expected %d TTL decisions, got %+v", len(expectedTTL), policy.TTL)
+	}
+	for i, d := range policy.TTL {
+		d.Position = expectedTTL[i].Position
+		if !reflect.DeepEqual(d, expectedTTL[i]) {
+			t.Errorf("TTL %d: expected %+v, got %+v", i, expectedTTL[i], d)
+		}
+	}
+
+	if len(policy.Grace) != 2 || policy.Grace[0].Target != "req.grace" || policy.Grace[1].Value != "1h" ||
+		len(policy.Grace[1].Conditions) != 0 {
+		t.Errorf("Unexpected grace decisions: %+v", policy.Grace)
+	}
+	if len(policy.Keep) != 1 || policy.Keep[0].Value != "2h" {
+		t.Errorf("Unexpected keep decisions: %+v", policy.Keep)
+	}
+
+	var headers []string
+	for _, d := range policy.Headers {
+		headers = append(headers, d.Action+" "+d.Target)
+	}
+	if !reflect.DeepEqual(headers, []string{"set beresp.http.Cache-Control", "unset resp.http.Age"}) {
+		t.Errorf("Unexpected header decisions: %v", headers)
+	}
+
+	var bypass []string
+	for _, d := range policy.Bypass {
+		bypass = append(bypass, d.Sub+" "+d.Target+" "+d.Value)
+	}
+	expectedBypass := []string{
+		"vcl_recv pass ",
+		"vcl_backend_response pass 30s",
+		"vcl_backend_response beresp.uncacheable true",
+	}
+	if !reflect.DeepEqual(bypass, expectedBypass) {
+		t.Errorf("Expected bypass decisions %q, got %q", expectedBypass, bypass)
+	}
+	if policy.Bypass[0].Position.Line != 5 || policy.Bypass[2].Conditions[1] != `!(bereq.url ~ "^/static/")` {
+		t.Errorf("Unexpected bypass details: %+v", policy.Bypass)
+	}
+}
@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// CallCheck validates the literal arguments of calls to one function
+// beyond their types, such as the backreferences of a substitution or a
+// format string. The VMODValidator runs the checks registered for each
+// function it meets; DefaultCallChecks are registered from the start.
+type CallCheck interface {
+	// Function names the function checked: a built-in function such as
+	// "regsub", or a VMOD function as module.function, such as
+	// "std.strftime"
+	Function() string
+	// Check returns the findings for one call. args holds the arguments in
+	// the order of the function's parameters, with named arguments in
+	// place and nil for omitted ones.
+	Check(call *ast.CallExpression, args []ast.Expression) []Diagnostic
+}
+
+// DefaultCallChecks returns the checks for the functions of VCL and vmod_std
+// that take patterns or formats:
+//
+//   - regsub() and regsuball(): backreferences in the substitution to
+//     groups the pattern lacks. The patterns themselves are compiled by the
+//     RegexValidator.
+//   - std.strftime() and utils.time_format(): unknown conversions in the
+//     format
+//   - std.syslog(): priorities outside the syslog facilities and levels
+//   - std.querysort(): constant URLs, which can be sorted in the source
+func DefaultCallChecks() []CallCheck {
+	return []CallCheck{
+		substitutionCheck("regsub"),
+		substitutionCheck("regsuball"),
+		strftimeCheck{function: "std.strftime", format: 1},
+		strftimeCheck{function: "utils.time_format", format: 0},
+		syslogCheck{},
+		querysortCheck{},
+	}
+}
+
+// RegisterCallCheck adds a check for calls to check.Function(). Several
+// checks may be registered for one function; they run in order.
+func (v *VMODValidator) RegisterCallCheck(check CallCheck) {
+	if v.callChecks == nil {
+		v.callChecks = make(map[string][]CallCheck)
+	}
+	name := check.Function()
+	v.callChecks[name] = append(v.callChecks[name], check)
+}
+
+// runCallChecks runs the checks registered for function on a call
+func (v *VMODValidator) runCallChecks(function string, call *ast.CallExpression, args []ast.Expression) {
+	for _, check := range v.callChecks[function] {
+		v.diagnostics = append(v.diagnostics, check.Check(call, args)...)
+	}
+}
+
+// stringArgument returns the literal value of args[i], if it is a string
+// literal
+func stringArgument(args []ast.Expression, i int) (*ast.StringLiteral, bool) {
+	if i >= len(args) {
+		return nil, false
+	}
+	lit, ok := args[i].(*ast.StringLiteral)
+	return lit, ok
+}
+
+// substitutionCheck checks the substitution of regsub() or regsuball()
+// against the groups of its pattern
+type substitutionCheck string
+
+func (c substitutionCheck) Function() string { return string(c) }
+
+func (c substitutionCheck) Check(call *ast.CallExpression, args []ast.Expression) []Diagnostic {
+	pattern, ok := stringArgument(args, 1)
+	if !ok {
+		return nil
+	}
+	sub, ok := stringArgument(args, 2)
+	if !ok {
+		return nil
+	}
+	re, err := syntax.Parse(pcreToRE2(pattern.Value), syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	groups := re.MaxCap()
+
+	var diags []Diagnostic
+	for i := 0; i+1 < len(sub.Value); i++ {
+		if sub.Value[i] != '\\' {
+			continue
+		}
+		i++
+		if n := int(sub.Value[i] - '0'); n >= 0 && n <= 9 && n > groups {
+			diags = append(diags, NewDiagnostic(sub, SeverityError, "regsub-backref",
+				fmt.Sprintf("%s substitution refers to \\%d, but the pattern %q has %s",
+					string(c), n, pattern.Value, pluralGroups(groups))))
+		}
+	}
+	return diags
+}
+
+func pluralGroups(n int) string {
+	switch n {
+	case 0:
+		return "no groups"
+	case 1:
+		return "1 group"
+	}
+	return fmt.Sprintf("%d groups", n)
+}
+
+// strftimeConversions are the conversion characters of strftime(3)
+const strftimeConversions = "aAbBcCdDeFgGhHIjklmMnprRsStTuUVwWxXyYzZ+%"
+
+// strftimeCheck checks the strftime(3) format of a function, the argument
+// at index format
+type strftimeCheck struct {
+	function string
+	format   int
+}
+
+func (c strftimeCheck) Function() string { return c.function }
+
+func (c strftimeCheck) Check(call *ast.CallExpression, args []ast.Expression) []Diagnostic {
+	format, ok := stringArgument(args, c.format)
+	if !ok {
+		return nil
+	}
+	var diags []Diagnostic
+	for i := 0; i < len(format.Value); i++ {
+		if format.Value[i] != '%' {
+			continue
+		}
+		i++
+		// E and O select alternative representations
+		if i < len(format.Value) && (format.Value[i] == 'E' || format.Value[i] == 'O') {
+			i++
+		}
+		if i >= len(format.Value) {
+			diags = append(diags, NewDiagnostic(format, SeverityWarning, "strftime-format",
+				fmt.Sprintf("%s format %q ends in an incomplete conversion", c.function, format.Value)))
+			break
+		}
+		if !strings.ContainsRune(strftimeConversions, rune(format.Value[i])) {
+			diags = append(diags, NewDiagnostic(format, SeverityWarning, "strftime-format",
+				fmt.Sprintf("%s format %q has unknown conversion %%%c", c.function, format.Value, format.Value[i])))
+		}
+	}
+	return diags
+}
+
+// maxSyslogPriority is LOG_LOCAL7|LOG_DEBUG, the highest facility and level
+const maxSyslogPriority = 23<<3 | 7
+
+// syslogCheck checks the priority of std.syslog()
+type syslogCheck struct{}
+
+func (syslogCheck) Function() string { return "std.syslog" }
+
+func (syslogCheck) Check(call *ast.CallExpression, args []ast.Expression) []Diagnostic {
+	if len(args) == 0 {
+		return nil
+	}
+	priority, ok := args[0].(*ast.IntegerLiteral)
+	if !ok || (priority.Value >= 0 && priority.Value <= maxSyslogPriority) {
+		return nil
+	}
+	return []Diagnostic{NewDiagnostic(priority, SeverityError, "syslog-priority",
+		fmt.Sprintf("std.syslog priority %d is not a facility and level between 0 and %d", priority.Value, maxSyslogPriority))}
+}
+
+// querysortCheck reports std.querysort() of constant URLs
+type querysortCheck struct{}
+
+func (querysortCheck) Function() string { return "std.querysort" }
+
+func (querysortCheck) Check(call *ast.CallExpression, args []ast.Expression) []Diagnostic {
+	url, ok := stringArgument(args, 0)
+	if !ok {
+		return nil
+	}
+	return []Diagnostic{NewDiagnostic(url, SeverityInfo, "querysort-literal",
+		fmt.Sprintf("std.querysort of the constant URL %q can be sorted in the source instead", url.Value))}
+}
@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestDefaultCallChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		code     string
+		severity Severity
+		message  string
+	}{
+		{
+			name:     "backreference beyond the groups",
+			body:     `set req.url = regsub(req.url, "^/(a)/(b)", "/\1/\3");`,
+			code:     "regsub-backref",
+			severity: SeverityError,
+			message:  `regsub substitution refers to \3, but the pattern "^/(a)/(b)" has 2 groups`,
+		},
+		{
+			name:     "backreference without groups",
+			body:     `set req.url = regsuball(req.url, "/+", "\1");`,
+			code:     "regsub-backref",
+			severity: SeverityError,
+			message:  "has no groups",
+		},
+		{
+			name: "backreferences in range",
+			body: `set req.url = regsub(req.url, "^/(?<first>a)/(b)", "\0\1\2");`,
+		},
+		{
+			name:     "unknown strftime conversion",
+			body:     `set resp.http.Date = utils.time_format("%Y-%m-%d %Q");`,
+			code:     "strftime-format",
+			severity: SeverityWarning,
+			message:  "unknown conversion %Q",
+		},
+		{
+			name: "valid strftime format",
+			body: `set resp.http.Date = utils.time_format("%a, %d %b %Y %H:%M:%S %Z %Ey %%");`,
+		},
+		{
+			name:     "syslog priority out of range",
+			body:     `std.syslog(200, "message");`,
+			code:     "syslog-priority",
+			severity: SeverityError,
+			message:  "std.syslog priority 200",
+		},
+		{
+			name:     "querysort of a literal",
+			body:     `set req.url = std.querysort("/?b=1&a=2");`,
+			code:     "querysort-literal",
+			severity: SeverityInfo,
+			message:  "constant URL",
+		},
+		{
+			name: "querysort of the URL",
+			body: `set req.url = std.querysort(req.url);`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := parseVCL(t, "vcl 4.1;\nimport std;\nimport utils;\nsub vcl_deliver {\n    "+tt.body+"\n}\n")
+			found := NewAnalyzer(vmod.DefaultRegistry).AnalyzeDiagnostics(program)
+			if tt.code == "" {
+				if len(found) > 0 {
+					t.Errorf("Expected no findings, got %v", found)
+				}
+				return
+			}
+			if len(found) != 1 || found[0].Code != tt.code || found[0].Severity != tt.severity ||
+				!strings.Contains(found[0].Message, tt.message) {
+				t.Errorf("Expected a %s finding containing %q, got %v", tt.code, tt.message, found)
+			}
+		})
+	}
+}
+
+// logCaseCheck requires literal log messages to be lower case
+type logCaseCheck struct{}
+
+func (logCaseCheck) Function() string { return "std.log" }
+
+func (logCaseCheck) Check(call *ast.CallExpression, args []ast.Expression) []Diagnostic {
+	if lit, ok := stringArgument(args, 0); ok && strings.ToLower(lit.Value) != lit.Value {
+		return []Diagnostic{NewDiagnostic(lit, SeverityWarning, "acme-log-case", "log messages must be lower case")}
+	}
+	return nil
+}
+
+func TestRegisterCallCheck(t *testing.T) {
+	program := parseVCL(t, "vcl 4.1;\nimport std;\nsub vcl_recv {\n    std.log(\"Hello\");\n}\n")
+	a := NewAnalyzer(vmod.DefaultRegistry)
+	a.RegisterCallCheck(logCaseCheck{})
+
+	var found bool
+	for _, d := range a.AnalyzeDiagnostics(program) {
+		if d.Code == "acme-log-case" && d.Position.Line == 4 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected the registered check to report the call")
+	}
+	a = NewAnalyzer(vmod.DefaultRegistry)
+	a.RegisterCallCheck(logCaseCheck{})
+	if errs := a.Analyze(program); len(errs) != 0 {
+		t.Errorf("Expected warnings to stay out of Analyze, got %v", errs)
+	}
+}
@@ -0,0 +1,296 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// CallGraph records which subroutines of a program call which, following
+// call statements. Built-in subroutines such as vcl_recv are the entry
+// points varnishd invokes; custom subroutines only run when called, or when
+// a VMOD such as vmod_sub runs a subroutine passed to it as SUB argument.
+// Passed subroutines count as referenced and reachable, but not as calls
+// for Callers, Callees and Cycles.
+//
+// A subroutine declared more than once, as built-in subroutines may be to
+// have their bodies concatenated, is a single node of the graph.
+type CallGraph struct {
+	// names lists every declared subroutine in order of first declaration
+	names []string
+	decls map[string][]*ast.SubDecl
+	// callees and callers list distinct names in order of first call
+	callees map[string][]string
+	callers map[string][]string
+	calls   map[string][]*ast.CallStatement
+	// passes lists the subroutines each subroutine passes to a function,
+	// and passedBy the reverse
+	passes    map[string][]string
+	passedBy  map[string][]string
+	arguments map[string][]*ast.Identifier
+}
+
+// NewCallGraph builds the call graph of a program
+func NewCallGraph(program *ast.Program) *CallGraph {
+	g := &CallGraph{
+		decls:   make(map[string][]*ast.SubDecl),
+		callees: make(map[string][]string),
+		callers: make(map[string][]string),
+		calls:   make(map[string][]*ast.CallStatement),
+
+		passes:    make(map[string][]string),
+		passedBy:  make(map[string][]string),
+		arguments: make(map[string][]*ast.Identifier),
+	}
+
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub == nil {
+			continue
+		}
+		if _, seen := g.decls[sub.Name]; !seen {
+			g.names = append(g.names, sub.Name)
+		}
+		g.decls[sub.Name] = append(g.decls[sub.Name], sub)
+	}
+
+	for _, name := range g.names {
+		for _, sub := range g.decls[name] {
+			if sub.Body == nil {
+				continue
+			}
+			walkSubStatements(sub.Body, func(stmt ast.Statement) {
+				call, ok := stmt.(*ast.CallStatement)
+				if !ok || call == nil {
+					return
+				}
+				callee := calledSubName(call)
+				if callee == "" {
+					return
+				}
+				g.calls[callee] = append(g.calls[callee], call)
+				if !containsString(g.callees[name], callee) {
+					g.callees[name] = append(g.callees[name], callee)
+				}
+				if !containsString(g.callers[callee], name) {
+					g.callers[callee] = append(g.callers[callee], name)
+				}
+			})
+			walkNodes(sub.Body, func(node ast.Node) {
+				if call, ok := node.(*ast.CallExpression); ok {
+					g.addArguments(name, call)
+				}
+			})
+		}
+	}
+
+	return g
+}
+
+// addArguments records the subroutines passed by name in the arguments of
+// call. Names share one namespace, so an identifier naming a subroutine
+// refers to it.
+func (g *CallGraph) addArguments(name string, call *ast.CallExpression) {
+	args := append([]ast.Expression(nil), call.Arguments...)
+	names := make([]string, 0, len(call.NamedArguments))
+	for argName := range call.NamedArguments {
+		names = append(names, argName)
+	}
+	sort.Strings(names)
+	for _, argName := range names {
+		args = append(args, call.NamedArguments[argName])
+	}
+	for _, arg := range args {
+		ident, ok := arg.(*ast.Identifier)
+		if !ok || ident == nil {
+			continue
+		}
+		if _, declared := g.decls[ident.Name]; !declared {
+			continue
+		}
+		g.arguments[ident.Name] = append(g.arguments[ident.Name], ident)
+		if !containsString(g.passes[name], ident.Name) {
+			g.passes[name] = append(g.passes[name], ident.Name)
+		}
+		if !containsString(g.passedBy[ident.Name], name) {
+			g.passedBy[ident.Name] = append(g.passedBy[ident.Name], name)
+		}
+	}
+}
+
+// Subroutines returns the names of all declared subroutines in declaration
+// order
+func (g *CallGraph) Subroutines() []string {
+	return append([]string(nil), g.names...)
+}
+
+// Declarations returns the declarations of a subroutine, in source order
+func (g *CallGraph) Declarations(name string) []*ast.SubDecl {
+	return g.decls[name]
+}
+
+// Callees returns the subroutines called by name, in order of first call
+func (g *CallGraph) Callees(name string) []string {
+	return g.callees[name]
+}
+
+// Callers returns the subroutines calling name, in declaration order of the
+// caller's first call
+func (g *CallGraph) Callers(name string) []string {
+	return g.callers[name]
+}
+
+// Calls returns the call statements calling name
+func (g *CallGraph) Calls(name string) []*ast.CallStatement {
+	return g.calls[name]
+}
+
+// Arguments returns the identifiers passing name to a function, as for the
+// SUB parameters of VMODs
+func (g *CallGraph) Arguments(name string) []*ast.Identifier {
+	return g.arguments[name]
+}
+
+// PassedBy returns the subroutines passing name to a function, in
+// declaration order of the first one passing it
+func (g *CallGraph) PassedBy(name string) []string {
+	return g.passedBy[name]
+}
+
+// Unreferenced returns the custom subroutines that are never called nor
+// passed to a function, the ones varnishd rejects as unused unless
+// vcc_err_unref is off. A subroutine only called from an unreferenced one is
+// still referenced; see Unreachable.
+func (g *CallGraph) Unreferenced() []string {
+	var result []string
+	for _, name := range g.names {
+		if !isBuiltinSubroutine(name) && len(g.callers[name]) == 0 && len(g.passedBy[name]) == 0 {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Reachable returns the subroutines that run when varnishd invokes the
+// built-in subroutines, directly or through any chain of calls. The result
+// is sorted.
+func (g *CallGraph) Reachable() []string {
+	var roots []string
+	for _, name := range g.names {
+		if isBuiltinSubroutine(name) {
+			roots = append(roots, name)
+		}
+	}
+	return g.ReachableFrom(roots...)
+}
+
+// ReachableFrom returns the subroutines reachable from the given ones,
+// including themselves. The result is sorted.
+func (g *CallGraph) ReachableFrom(names ...string) []string {
+	seen := make(map[string]bool)
+	var visit func(string)
+	visit = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		for _, callee := range g.callees[name] {
+			visit(callee)
+		}
+		for _, passed := range g.passes[name] {
+			visit(passed)
+		}
+	}
+	for _, name := range names {
+		visit(name)
+	}
+
+	result := make([]string, 0, len(seen))
+	for name := range seen {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Unreachable returns the declared custom subroutines that no built-in
+// subroutine reaches, in declaration order
+func (g *CallGraph) Unreachable() []string {
+	reachable := make(map[string]bool)
+	for _, name := range g.Reachable() {
+		reachable[name] = true
+	}
+	var result []string
+	for _, name := range g.names {
+		if !reachable[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Cycles returns every recursive call chain, which varnishd rejects. Each
+// cycle starts at its first declared subroutine and lists the subroutines in
+// call order without repeating the first; a subroutine calling itself is a
+// cycle of one. Cycles are reported once, in declaration order of their
+// first subroutine.
+func (g *CallGraph) Cycles() [][]string {
+	index := make(map[string]int, len(g.names))
+	for i, name := range g.names {
+		index[name] = i
+	}
+
+	var cycles [][]string
+	for start, name := range g.names {
+		// Only follow subroutines declared after the start so that each
+		// cycle is found once, from its first subroutine
+		var path []string
+		onPath := make(map[string]bool)
+		var visit func(string)
+		visit = func(current string) {
+			path = append(path, current)
+			onPath[current] = true
+			for _, callee := range g.callees[current] {
+				i, declared := index[callee]
+				switch {
+				case !declared || i < start:
+				case callee == name:
+					cycles = append(cycles, append([]string(nil), path...))
+				case !onPath[callee]:
+					visit(callee)
+				}
+			}
+			path = path[:len(path)-1]
+			onPath[current] = false
+		}
+		visit(name)
+	}
+	return cycles
+}
+
+// IsRecursive reports whether name can end up calling itself
+func (g *CallGraph) IsRecursive(name string) bool {
+	for _, callee := range g.callees[name] {
+		if containsString(g.ReachableFrom(callee), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// calledSubName returns the name of the subroutine a call statement calls
+func calledSubName(call *ast.CallStatement) string {
+	if ident, ok := call.Function.(*ast.Identifier); ok && ident != nil {
+		return ident.Name
+	}
+	return ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
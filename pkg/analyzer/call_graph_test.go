@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestCallGraph(t *testing.T) {
+	vclCode := `vcl 4.1;
+
+sub normalize_host {
+	set req.http.host = "example.com";
+}
+
+sub normalize {
+	call normalize_host;
+}
+
+sub only_from_unused {
+	set req.http.x = "1";
+}
+
+sub unused {
+	call only_from_unused;
+}
+
+sub vcl_recv {
+	call normalize;
+	if (req.url ~ "^/a") {
+		call normalize_host;
+	}
+}
+
+sub vcl_deliver {
+	call normalize;
+}
+`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	g := NewCallGraph(program)
+
+	if got := g.Callees("vcl_recv"); !reflect.DeepEqual(got, []string{"normalize", "normalize_host"}) {
+		t.Errorf("Callees(vcl_recv) = %v", got)
+	}
+	if got := g.Callers("normalize"); !reflect.DeepEqual(got, []string{"vcl_recv", "vcl_deliver"}) {
+		t.Errorf("Callers(normalize) = %v", got)
+	}
+	if got := len(g.Calls("normalize_host")); got != 2 {
+		t.Errorf("Expected 2 calls of normalize_host, got %d", got)
+	}
+	if got := g.Unreferenced(); !reflect.DeepEqual(got, []string{"unused"}) {
+		t.Errorf("Unreferenced() = %v", got)
+	}
+	if got := g.Unreachable(); !reflect.DeepEqual(got, []string{"only_from_unused", "unused"}) {
+		t.Errorf("Unreachable() = %v", got)
+	}
+	want := []string{"normalize", "normalize_host", "vcl_deliver", "vcl_recv"}
+	if got := g.Reachable(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reachable() = %v, want %v", got, want)
+	}
+	if got := g.ReachableFrom("unused"); !reflect.DeepEqual(got, []string{"only_from_unused", "unused"}) {
+		t.Errorf("ReachableFrom(unused) = %v", got)
+	}
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Errorf("Expected no cycles, got %v", cycles)
+	}
+}
+
+func TestCallGraphRecursion(t *testing.T) {
+	// The parser only accepts calls of subroutines declared earlier, so
+	// mutual recursion needs declarations merged from several sources, as
+	// the include resolver does
+	first, err := parser.Parse(`vcl 4.1;
+
+sub b {
+	set req.http.x = "1";
+}
+
+sub a {
+	call b;
+}
+`, "first.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	second, err := parser.Parse(`vcl 4.1;
+
+sub a {
+	set req.http.x = "1";
+}
+
+sub b {
+	call a;
+}
+
+sub self {
+	call self;
+}
+`, "second.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	first.Declarations = append(first.Declarations, second.Declarations...)
+	g := NewCallGraph(first)
+
+	if got := g.Subroutines(); !reflect.DeepEqual(got, []string{"b", "a", "self"}) {
+		t.Errorf("Subroutines() = %v", got)
+	}
+	if got := len(g.Declarations("b")); got != 2 {
+		t.Errorf("Expected 2 declarations of b, got %d", got)
+	}
+	want := [][]string{{"b", "a"}, {"self"}}
+	if got := g.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cycles() = %v, want %v", got, want)
+	}
+	if !g.IsRecursive("a") || !g.IsRecursive("self") {
+		t.Error("Expected a and self to be recursive")
+	}
+	if got := g.Unreferenced(); len(got) != 0 {
+		t.Errorf("Expected every subroutine to be referenced, got %v", got)
+	}
+}
+
+func TestCallGraphSubArguments(t *testing.T) {
+	program, err := parser.Parse(`vcl 4.1;
+import subs;
+
+sub on_hit {
+	set req.http.x-hit = "1";
+}
+
+sub helper {
+	call on_hit;
+}
+
+sub vcl_recv {
+	subs.run(helper);
+	subs.run(s = helper);
+}
+`, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	g := NewCallGraph(program)
+
+	if got := g.PassedBy("helper"); !reflect.DeepEqual(got, []string{"vcl_recv"}) {
+		t.Errorf("PassedBy(helper) = %v", got)
+	}
+	if got := len(g.Arguments("helper")); got != 2 {
+		t.Errorf("Expected 2 arguments passing helper, got %d", got)
+	}
+	if got := g.Callees("vcl_recv"); len(got) != 0 {
+		t.Errorf("Expected passed subroutines not to be callees, got %v", got)
+	}
+	if got := g.Unreferenced(); len(got) != 0 {
+		t.Errorf("Unreferenced() = %v", got)
+	}
+	want := []string{"helper", "on_hit", "vcl_recv"}
+	if got := g.Reachable(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reachable() = %v, want %v", got, want)
+	}
+}
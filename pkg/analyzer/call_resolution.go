@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/vcc"
+)
+
+// CallResolution is a VMOD function or method call matched to the
+// parameters of its callee, for tools that need to know which value each
+// parameter gets
+type CallResolution struct {
+	// Callee is the function or method called, as module.function or
+	// object.method
+	Callee string
+	Call   *ast.CallExpression
+	// Parameters are the parameters written in VCL, without PRIV_*
+	// parameters
+	Parameters []vcc.Parameter
+	// Arguments are the arguments in parameter order, nil for the
+	// parameters left out
+	Arguments []ast.Expression
+	// States tell for each parameter whether its argument was given,
+	// defaulted or omitted
+	States []vcc.ArgumentState
+}
+
+// Defaulted returns the names of the parameters that get their default
+// value
+func (r *CallResolution) Defaulted() []string {
+	var names []string
+	for i, state := range r.States {
+		if state == vcc.ArgumentDefaulted {
+			names = append(names, r.Parameters[i].Name)
+		}
+	}
+	return names
+}
+
+// Omitted returns the names of the optional parameters without default
+// value that were left out
+func (r *CallResolution) Omitted() []string {
+	var names []string
+	for i, state := range r.States {
+		if state == vcc.ArgumentOmitted {
+			names = append(names, r.Parameters[i].Name)
+		}
+	}
+	return names
+}
+
+// resolve records the resolution of a call that passed validation
+func (v *VMODValidator) resolve(call *ast.CallExpression, callee string, params []vcc.Parameter, args []ast.Expression, states []vcc.ArgumentState) {
+	v.resolutions[call] = &CallResolution{
+		Callee:     callee,
+		Call:       call,
+		Parameters: params,
+		Arguments:  args,
+		States:     states,
+	}
+}
+
+// Resolution returns the resolution of a VMOD call of the program last
+// validated, or nil if call is not a VMOD call or did not pass validation
+func (v *VMODValidator) Resolution(call *ast.CallExpression) *CallResolution {
+	return v.resolutions[call]
+}
@@ -0,0 +1,492 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// ConditionValidator finds if/else-if chains with dead branches: conditions
+// identical to an earlier one in the chain, conditions subsumed by an earlier
+// literal regex or equality match on the same subject, conjunctions that can
+// never be true, and conditions whose value is known without a request.
+type ConditionValidator struct {
+	diagnostics []Diagnostic
+}
+
+// NewConditionValidator creates a new condition validator
+func NewConditionValidator() *ConditionValidator {
+	return &ConditionValidator{}
+}
+
+// chainEntry is one condition of an if/else-if chain
+type chainEntry struct {
+	condition ast.Expression
+	key       string
+	node      ast.Node // where the condition is reported
+}
+
+// Validate checks every if/else-if chain in the program
+func (cv *ConditionValidator) Validate(program *ast.Program) []Diagnostic {
+	cv.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			cv.diagnostics = append(cv.diagnostics, cv.validateSubroutine(sub)...)
+		}
+	}
+
+	return cv.diagnostics
+}
+
+// validateSubroutine checks the if/else-if chains of one subroutine
+func (cv *ConditionValidator) validateSubroutine(sub *ast.SubDecl) []Diagnostic {
+	v := &ConditionValidator{}
+	v.walkBlock(sub.Body)
+	return v.diagnostics
+}
+
+func (cv *ConditionValidator) walkBlock(block *ast.BlockStatement) {
+	for _, stmt := range block.Statements {
+		cv.walkStatement(stmt)
+	}
+}
+
+func (cv *ConditionValidator) walkStatement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		cv.walkBlock(s)
+	case *ast.IfStatement:
+		cv.checkChain(s)
+	}
+}
+
+// checkChain compares each condition in the chain against all earlier ones,
+// then descends into the branch bodies
+func (cv *ConditionValidator) checkChain(head *ast.IfStatement) {
+	var earlier []chainEntry
+
+	for stmt := head; stmt != nil; {
+		entry := chainEntry{
+			condition: ast.Unparen(stmt.Condition),
+			key:       expressionKey(stmt.Condition),
+			node:      stmt.Condition,
+		}
+		if stmt.Condition.Start().Line == 0 {
+			entry.node = stmt
+		}
+
+		if !cv.checkContradiction(entry) {
+			cv.checkConstant(entry, stmt.Else != nil)
+		}
+		for _, prev := range earlier {
+			if cv.checkRedundant(entry, prev) {
+				break
+			}
+		}
+		earlier = append(earlier, entry)
+
+		if stmt.Then != nil {
+			cv.walkStatement(stmt.Then)
+		}
+		next, ok := stmt.Else.(*ast.IfStatement)
+		if !ok {
+			if stmt.Else != nil {
+				cv.walkStatement(stmt.Else)
+			}
+			break
+		}
+		stmt = next
+	}
+}
+
+// checkRedundant reports entry if an earlier condition already covers it.
+// It returns true when a diagnostic was added.
+func (cv *ConditionValidator) checkRedundant(entry, prev chainEntry) bool {
+	if entry.key != "" && entry.key == prev.key {
+		cv.add(entry.node, "redundant-condition", fmt.Sprintf(
+			"condition is identical to the one at line %d, so the branch at line %d is never taken",
+			prev.node.Start().Line, entry.node.Start().Line))
+		return true
+	}
+
+	for _, disjunct := range splitOr(prev.condition) {
+		if key := expressionKey(disjunct); key != "" && key == entry.key {
+			cv.add(entry.node, "redundant-condition", fmt.Sprintf(
+				"condition is already covered by the one at line %d, so the branch at line %d is never taken",
+				prev.node.Start().Line, entry.node.Start().Line))
+			return true
+		}
+		if matchSubsumes(disjunct, entry.condition) {
+			cv.add(entry.node, "redundant-condition", fmt.Sprintf(
+				"condition is subsumed by the earlier match at line %d, so the branch at line %d is never taken",
+				prev.node.Start().Line, entry.node.Start().Line))
+			return true
+		}
+	}
+	return false
+}
+
+// checkContradiction reports a && chain requiring one subject to equal two
+// different string literals, to both equal and differ from the same one, or
+// to lie in an empty numeric range. It returns true when a diagnostic was
+// added.
+func (cv *ConditionValidator) checkContradiction(entry chainEntry) bool {
+	equal := map[string]string{}
+	unequal := map[string]map[string]bool{}
+	ranges := map[string]*numericRange{}
+	for _, conjunct := range splitAnd(entry.condition) {
+		if subject, value, ok := literalEquality(conjunct); ok {
+			if other, exists := equal[subject]; exists && other != value {
+				cv.add(entry.node, "impossible-condition", fmt.Sprintf(
+					"condition can never be true: %s cannot equal both %q and %q", subject, other, value))
+				return true
+			}
+			if unequal[subject][value] {
+				cv.add(entry.node, "impossible-condition", fmt.Sprintf(
+					"condition can never be true: %s cannot both equal and differ from %q", subject, value))
+				return true
+			}
+			equal[subject] = value
+			continue
+		}
+		if subject, value, ok := literalComparison(conjunct, "!="); ok {
+			if other, exists := equal[subject]; exists && other == value {
+				cv.add(entry.node, "impossible-condition", fmt.Sprintf(
+					"condition can never be true: %s cannot both equal and differ from %q", subject, value))
+				return true
+			}
+			if unequal[subject] == nil {
+				unequal[subject] = map[string]bool{}
+			}
+			unequal[subject][value] = true
+			continue
+		}
+		if subject, operator, bound, ok := numericComparison(conjunct); ok {
+			r := ranges[subject]
+			if r == nil {
+				r = &numericRange{}
+				ranges[subject] = r
+			}
+			r.restrict(operator, bound)
+			if r.empty() {
+				cv.add(entry.node, "impossible-condition", fmt.Sprintf(
+					"condition can never be true: no value of %s satisfies all its comparisons", subject))
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkConstant reports conditions that are always true or always false,
+// such as comparisons between literals, x && !x, or a subject that differs
+// from one of two literals or both equals and differs from one
+func (cv *ConditionValidator) checkConstant(entry chainEntry, hasElse bool) {
+	value, ok := evalConstant(entry.condition)
+	if !ok || value.kind != constBool {
+		if !alwaysHolds(entry.condition) {
+			return
+		}
+		value = boolValue(true)
+	}
+
+	switch {
+	case !value.bool:
+		cv.add(entry.node, "constant-condition", fmt.Sprintf(
+			"condition is always false, so the branch at line %d is never taken", entry.node.Start().Line))
+	case hasElse:
+		cv.add(entry.node, "constant-condition", "condition is always true, so the branches after it are never taken")
+	default:
+		cv.add(entry.node, "constant-condition", "condition is always true; the if statement can be removed")
+	}
+}
+
+// alwaysHolds matches || chains like x != "a" || x != "b" and
+// x == "a" || x != "a", which hold for every value of x
+func alwaysHolds(expr ast.Expression) bool {
+	equal := map[string]map[string]bool{}
+	unequal := map[string]string{}
+	for _, disjunct := range splitOr(expr) {
+		if subject, value, ok := literalEquality(disjunct); ok {
+			if other, exists := unequal[subject]; exists && other == value {
+				return true
+			}
+			if equal[subject] == nil {
+				equal[subject] = map[string]bool{}
+			}
+			equal[subject][value] = true
+			continue
+		}
+		subject, value, ok := literalComparison(disjunct, "!=")
+		if !ok {
+			continue
+		}
+		if other, exists := unequal[subject]; exists && other != value {
+			return true
+		}
+		if equal[subject][value] {
+			return true
+		}
+		unequal[subject] = value
+	}
+	return false
+}
+
+// numericRange is the set of numbers allowed by a series of comparisons
+type numericRange struct {
+	lower, upper         float64
+	hasLower, hasUpper   bool
+	lowerOpen, upperOpen bool
+}
+
+func (r *numericRange) restrict(operator string, bound float64) {
+	switch operator {
+	case ">":
+		r.raise(bound, true)
+	case ">=":
+		r.raise(bound, false)
+	case "<":
+		r.limit(bound, true)
+	case "<=":
+		r.limit(bound, false)
+	case "==":
+		r.raise(bound, false)
+		r.limit(bound, false)
+	}
+}
+
+func (r *numericRange) raise(bound float64, open bool) {
+	if !r.hasLower || bound > r.lower || bound == r.lower && open {
+		r.lower, r.lowerOpen, r.hasLower = bound, open, true
+	}
+}
+
+func (r *numericRange) limit(bound float64, open bool) {
+	if !r.hasUpper || bound < r.upper || bound == r.upper && open {
+		r.upper, r.upperOpen, r.hasUpper = bound, open, true
+	}
+}
+
+func (r *numericRange) empty() bool {
+	if !r.hasLower || !r.hasUpper {
+		return false
+	}
+	return r.lower > r.upper || r.lower == r.upper && (r.lowerOpen || r.upperOpen)
+}
+
+// numericComparison matches a subject compared with a number literal in
+// either operand order, returning the operator as if the subject were on
+// the left
+func numericComparison(expr ast.Expression) (subject, operator string, bound float64, ok bool) {
+	bin, isBinary := ast.Unparen(expr).(*ast.BinaryExpression)
+	if !isBinary {
+		return "", "", 0, false
+	}
+	flipped := map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<=", "==": "=="}
+	if _, known := flipped[bin.Operator]; !known {
+		return "", "", 0, false
+	}
+	if value, isNumber := evalConstant(bin.Right); isNumber && value.kind == constNumber {
+		subject = expressionKey(bin.Left)
+		return subject, bin.Operator, value.number, subject != "" && !isLiteral(bin.Left)
+	}
+	if value, isNumber := evalConstant(bin.Left); isNumber && value.kind == constNumber {
+		subject = expressionKey(bin.Right)
+		return subject, flipped[bin.Operator], value.number, subject != "" && !isLiteral(bin.Right)
+	}
+	return "", "", 0, false
+}
+
+// isLiteral reports whether expr has a known value
+func isLiteral(expr ast.Expression) bool {
+	_, ok := evalConstant(expr)
+	return ok
+}
+
+func (cv *ConditionValidator) add(node ast.Node, code, message string) {
+	cv.diagnostics = append(cv.diagnostics, NewDiagnostic(node, SeverityWarning, code, message))
+}
+
+// matchSubsumes reports whether every request matching later also matches
+// earlier, for literal regex and string equality tests on the same subject
+func matchSubsumes(earlier, later ast.Expression) bool {
+	eSubject, eLit, eStart, eEnd, ok := literalMatch(earlier)
+	if !ok {
+		return false
+	}
+	lSubject, lLit, lStart, lEnd, ok := literalMatch(later)
+	if !ok || eSubject != lSubject {
+		return false
+	}
+
+	if eStart && !(lStart && strings.HasPrefix(lLit, eLit)) {
+		return false
+	}
+	if eEnd && !(lEnd && strings.HasSuffix(lLit, eLit)) {
+		return false
+	}
+	return strings.Contains(lLit, eLit)
+}
+
+// literalMatch describes a positive regex match against a literal pattern, or
+// a string equality, as subject + literal with anchoring flags
+func literalMatch(expr ast.Expression) (subject, literal string, anchoredStart, anchoredEnd, ok bool) {
+	expr = ast.Unparen(expr)
+	if subject, value, ok := literalEquality(expr); ok {
+		return subject, value, true, true, true
+	}
+
+	match, isMatch := expr.(*ast.RegexMatchExpression)
+	if !isMatch || match.Operator != "~" {
+		return "", "", false, false, false
+	}
+	pattern, isString := match.Right.(*ast.StringLiteral)
+	subject = expressionKey(match.Left)
+	if !isString || subject == "" {
+		return "", "", false, false, false
+	}
+	literal, anchoredStart, anchoredEnd, ok = regexLiteral(pattern.Value)
+	return subject, literal, anchoredStart, anchoredEnd, ok
+}
+
+// literalEquality matches "subject == "literal"" in either operand order
+func literalEquality(expr ast.Expression) (subject, value string, ok bool) {
+	return literalComparison(expr, "==")
+}
+
+// literalComparison matches "subject <operator> "literal"" in either operand
+// order
+func literalComparison(expr ast.Expression, operator string) (subject, value string, ok bool) {
+	bin, isBinary := ast.Unparen(expr).(*ast.BinaryExpression)
+	if !isBinary || bin.Operator != operator {
+		return "", "", false
+	}
+	if lit, isString := bin.Right.(*ast.StringLiteral); isString {
+		subject = expressionKey(bin.Left)
+		return subject, lit.Value, subject != ""
+	}
+	if lit, isString := bin.Left.(*ast.StringLiteral); isString {
+		subject = expressionKey(bin.Right)
+		return subject, lit.Value, subject != ""
+	}
+	return "", "", false
+}
+
+// regexLiteral decodes a regular expression that matches a fixed string,
+// optionally anchored with ^ and $. Patterns using any other regex feature
+// are rejected.
+func regexLiteral(pattern string) (literal string, anchoredStart, anchoredEnd bool, ok bool) {
+	if strings.HasPrefix(pattern, "^") {
+		anchoredStart = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "$") && !strings.HasSuffix(pattern, `\$`) {
+		anchoredEnd = true
+		pattern = pattern[:len(pattern)-1]
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(pattern) {
+				return "", false, false, false
+			}
+			next := pattern[i+1]
+			// Escaped letters and digits are classes or backreferences
+			if next >= 'a' && next <= 'z' || next >= 'A' && next <= 'Z' || next >= '0' && next <= '9' {
+				return "", false, false, false
+			}
+			sb.WriteByte(next)
+			i++
+		case strings.IndexByte(".*+?()[]{}|^$", c) >= 0:
+			return "", false, false, false
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), anchoredStart, anchoredEnd, true
+}
+
+func splitOr(expr ast.Expression) []ast.Expression {
+	return splitBinary(expr, "||")
+}
+
+func splitAnd(expr ast.Expression) []ast.Expression {
+	return splitBinary(expr, "&&")
+}
+
+// splitBinary flattens a chain of the given logical operator
+func splitBinary(expr ast.Expression, operator string) []ast.Expression {
+	expr = ast.Unparen(expr)
+	if bin, ok := expr.(*ast.BinaryExpression); ok && bin.Operator == operator {
+		return append(splitBinary(bin.Left, operator), splitBinary(bin.Right, operator)...)
+	}
+	return []ast.Expression{expr}
+}
+
+// expressionKey renders an expression in a canonical form so that two
+// syntactically equivalent expressions get the same key. Redundant
+// parentheses are dropped and header names are lowercased. It returns "" for
+// expressions it cannot render.
+func expressionKey(expr ast.Expression) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Identifier:
+		return e.Name
+	case *ast.MemberExpression:
+		name := ast.VariableName(e)
+		if i := strings.Index(name, ".http."); i >= 0 {
+			name = name[:i+6] + strings.ToLower(name[i+6:])
+		}
+		return name
+	case *ast.StringLiteral:
+		return strconv.Quote(e.Value)
+	case *ast.IntegerLiteral:
+		return strconv.FormatInt(e.Value, 10)
+	case *ast.FloatLiteral:
+		return strconv.FormatFloat(e.Value, 'g', -1, 64)
+	case *ast.BooleanLiteral:
+		return strconv.FormatBool(e.Value)
+	case *ast.TimeExpression:
+		return e.Value
+	case *ast.DurationLiteral:
+		return e.Value
+	case *ast.IPExpression:
+		return e.Value
+	case *ast.UnaryExpression:
+		operand := expressionKey(e.Operand)
+		if operand == "" {
+			return ""
+		}
+		return e.Operator + "(" + operand + ")"
+	case *ast.BinaryExpression:
+		return binaryKey(expressionKey(e.Left), e.Operator, expressionKey(e.Right))
+	case *ast.RegexMatchExpression:
+		return binaryKey(expressionKey(e.Left), e.Operator, expressionKey(e.Right))
+	case *ast.CallExpression:
+		function := expressionKey(e.Function)
+		if function == "" || len(e.NamedArguments) > 0 {
+			return ""
+		}
+		args := make([]string, len(e.Arguments))
+		for i, arg := range e.Arguments {
+			if args[i] = expressionKey(arg); args[i] == "" {
+				return ""
+			}
+		}
+		return function + "(" + strings.Join(args, ", ") + ")"
+	default:
+		return ""
+	}
+}
+
+func binaryKey(left, operator, right string) string {
+	if left == "" || right == "" {
+		return ""
+	}
+	return "(" + left + " " + operator + " " + right + ")"
+}
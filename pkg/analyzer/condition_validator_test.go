@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestConditionValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{
+			name: "distinct conditions",
+			body: `if (req.url ~ "^/api") { return (pass); }
+				elsif (req.url ~ "^/static") { return (hash); }`,
+		},
+		{
+			name: "identical conditions",
+			body: `if (req.http.Host == "example.com") { return (pass); }
+				else if (req.http.host == "example.com") { return (hash); }`,
+			expected: []string{"identical to the one at line 3, so the branch at line 4 is never taken"},
+		},
+		{
+			name: "redundant parentheses",
+			body: `if (req.method == "PURGE") { return (purge); }
+				elsif ((req.method == "PURGE")) { return (pass); }`,
+			expected: []string{"identical"},
+		},
+		{
+			name: "prefix regex subsumes longer prefix",
+			body: `if (req.url ~ "^/api") { return (pass); }
+				elsif (req.url ~ "^/api/v2") { return (hash); }`,
+			expected: []string{"subsumed by the earlier match at line 3"},
+		},
+		{
+			name: "regex subsumes equality",
+			body: `if (req.url ~ "/admin") { return (pass); }
+				elsif (req.url == "/wp/admin/login") { return (hash); }`,
+			expected: []string{"subsumed"},
+		},
+		{
+			name: "more specific first is fine",
+			body: `if (req.url ~ "^/api/v2") { return (pass); }
+				elsif (req.url ~ "^/api") { return (hash); }`,
+		},
+		{
+			name: "different subjects",
+			body: `if (req.url ~ "^/api") { return (pass); }
+				elsif (req.http.referer ~ "^/api/v2") { return (hash); }`,
+		},
+		{
+			name: "regex with metacharacters is not compared",
+			body: `if (req.url ~ "^/a.i") { return (pass); }
+				elsif (req.url ~ "^/a.i/v2") { return (hash); }`,
+		},
+		{
+			name: "escaped dot is literal",
+			body: `if (req.url ~ "\.png$") { return (hash); }
+				elsif (req.url ~ "^/img/logo\.png$") { return (pass); }`,
+			expected: []string{"subsumed"},
+		},
+		{
+			name: "covered by earlier disjunct",
+			body: `if (req.method == "GET" || req.method == "HEAD") { return (hash); }
+				elsif (req.method == "HEAD") { return (pass); }`,
+			expected: []string{"already covered"},
+		},
+		{
+			name:     "contradictory conjunction",
+			body:     `if (req.method == "GET" && req.method == "POST") { return (pass); }`,
+			expected: []string{"cannot equal both \"GET\" and \"POST\""},
+		},
+		{
+			name:     "equal and different",
+			body:     `if (req.method != "GET" && req.method == "GET") { return (pass); }`,
+			expected: []string{"cannot both equal and differ from \"GET\""},
+		},
+		{
+			name:     "empty numeric range",
+			body:     `if (req.restarts > 2 && req.restarts < 1) { return (pass); }`,
+			expected: []string{"no value of req.restarts satisfies all its comparisons"},
+		},
+		{
+			name: "numeric range with room",
+			body: `if (req.restarts >= 1 && 3 > req.restarts) { return (pass); }`,
+		},
+		{
+			name:     "literal comparison",
+			body:     `if ("a" == "b") { return (pass); }`,
+			expected: []string{"always false, so the branch at line 3 is never taken"},
+		},
+		{
+			name:     "expression and its negation",
+			body:     `if (req.http.x || !req.http.x) { return (pass); } else { return (hash); }`,
+			expected: []string{"always true, so the branches after it are never taken"},
+		},
+		{
+			name:     "differs from one of two values",
+			body:     `if (req.method != "GET" || req.method != "HEAD") { return (pass); }`,
+			expected: []string{"always true; the if statement can be removed"},
+		},
+		{
+			name:     "equals or differs from a value",
+			body:     `if (req.method == "GET" || req.method != "GET") { return (pass); }`,
+			expected: []string{"always true; the if statement can be removed"},
+		},
+		{
+			name:     "differs from or equals a value",
+			body:     `if (req.method != "GET" || req.method == "GET") { return (pass); }`,
+			expected: []string{"always true; the if statement can be removed"},
+		},
+		{
+			name: "equals one of two values",
+			body: `if (req.method == "GET" || req.method != "HEAD") { return (pass); }`,
+		},
+		{
+			name: "calls are not folded",
+			body: `if (std.random(0, 10) < std.random(0, 10)) { return (pass); }`,
+		},
+		{
+			name: "nested chain",
+			body: `if (req.http.x) {
+					if (req.url == "/a") { return (pass); }
+					elsif (req.url == "/a") { return (hash); }
+				}`,
+			expected: []string{"identical"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vclCode := "vcl 4.1;\nsub vcl_recv {\n\t\t\t\t" + test.body + "\n}\n"
+			program, err := parser.Parse(vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewConditionValidator().Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != SeverityWarning {
+					t.Errorf("diagnostic %d severity = %s, want warning", i, diags[i].Severity)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexLiteral(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		literal    string
+		start, end bool
+		isLiteral  bool
+	}{
+		{"^/api", "/api", true, false, true},
+		{`\.css$`, ".css", false, true, true},
+		{"^/exact$", "/exact", true, true, true},
+		{`^/a\d`, "", false, false, false},
+		{"(?i)foo", "", false, false, false},
+		{"a|b", "", false, false, false},
+	}
+
+	for _, test := range tests {
+		literal, start, end, ok := regexLiteral(test.pattern)
+		if ok != test.isLiteral {
+			t.Errorf("regexLiteral(%q) ok = %v, want %v", test.pattern, ok, test.isLiteral)
+			continue
+		}
+		if ok && (literal != test.literal || start != test.start || end != test.end) {
+			t.Errorf("regexLiteral(%q) = %q %v %v, want %q %v %v",
+				test.pattern, literal, start, end, test.literal, test.start, test.end)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/internal/pathutil"
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Config mirrors the varnishd parameters and feature flags that decide
+// whether VCL compiles, so VCL can be checked against the configuration it
+// will be deployed with before it gets there
+type Config struct {
+	// ErrUnref reports declarations that are never used as errors instead
+	// of warnings, like the vcc_err_unref parameter
+	ErrUnref bool
+	// AllowInlineC accepts C{ }C blocks, like vcc_allow_inline_c
+	AllowInlineC bool
+	// UnsafePath accepts include and import ... from paths that are
+	// absolute or contain "..", like vcc_unsafe_path
+	UnsafePath bool
+	// LenientVmodRestrictions reports calls of VMOD functions outside the
+	// subroutines they are restricted to as warnings instead of errors
+	LenientVmodRestrictions bool
+	// Features holds the optional language features that are enabled
+	Features Features
+}
+
+// Features is a set of optional language features
+type Features uint
+
+const (
+	// FeatureVCLConnect enables the vcl_connect subroutine and
+	// return (connect) of Varnish Enterprise
+	FeatureVCLConnect Features = 1 << iota
+
+	// AllFeatures enables every optional feature
+	AllFeatures = FeatureVCLConnect
+)
+
+// featureNames maps feature bits to the names used by ParseFeatures
+var featureNames = []struct {
+	feature Features
+	name    string
+}{
+	{FeatureVCLConnect, "VCL_CONNECT"},
+}
+
+// ParseFeatures converts feature names such as "VCL_CONNECT" into a set.
+// Names are case-insensitive.
+func ParseFeatures(names ...string) (Features, error) {
+	var features Features
+	for _, name := range names {
+		found := false
+		for _, f := range featureNames {
+			if strings.EqualFold(name, f.name) {
+				features |= f.feature
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown feature %q", name)
+		}
+	}
+	return features, nil
+}
+
+// Has reports whether every feature in f is enabled
+func (fs Features) Has(f Features) bool {
+	return fs&f == f
+}
+
+// String returns the names of the enabled features separated by commas
+func (fs Features) String() string {
+	var names []string
+	for _, f := range featureNames {
+		if fs.Has(f.feature) {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// DefaultConfig returns the configuration an Analyzer starts with, which
+// accepts everything the analyzer knows about and reports unused
+// declarations as warnings
+func DefaultConfig() Config {
+	return Config{
+		AllowInlineC: true,
+		UnsafePath:   true,
+		Features:     AllFeatures,
+	}
+}
+
+// VarnishdConfig returns the configuration of a stock open source varnishd:
+// unused declarations and inline C are errors, and no optional features are
+// enabled
+func VarnishdConfig() Config {
+	return Config{
+		ErrUnref:   true,
+		UnsafePath: true,
+	}
+}
+
+// ConfigValidator reports constructs that the configuration does not allow:
+// inline C, unsafe include paths and disabled features
+type ConfigValidator struct {
+	config      Config
+	diagnostics []Diagnostic
+}
+
+// NewConfigValidator creates a new validator for the given configuration
+func NewConfigValidator(config Config) *ConfigValidator {
+	return &ConfigValidator{config: config}
+}
+
+// Validate checks the program against the configuration
+func (cv *ConfigValidator) Validate(program *ast.Program) []Diagnostic {
+	cv.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.IncludeDecl:
+			if d != nil && !cv.config.UnsafePath && pathutil.IsUnsafe(d.Path) {
+				cv.add(d, "unsafe-path", fmt.Sprintf("include path %s is unsafe, which vcc_unsafe_path does not allow", d.Path))
+			}
+		case *ast.ImportDecl:
+			if d != nil && d.From != "" && !cv.config.UnsafePath && pathutil.IsUnsafe(d.From) {
+				cv.add(d, "unsafe-path", fmt.Sprintf("import path %s is unsafe, which vcc_unsafe_path does not allow", d.From))
+			}
+		case *ast.SubDecl:
+			if d == nil {
+				continue
+			}
+			if d.Name == "vcl_connect" && !cv.config.Features.Has(FeatureVCLConnect) {
+				cv.add(d, "feature-disabled", "vcl_connect requires the VCL_CONNECT feature")
+			}
+			if d.Body != nil {
+				walkSubStatements(d.Body, cv.checkStatement)
+			}
+		}
+	}
+
+	return cv.diagnostics
+}
+
+func (cv *ConfigValidator) checkStatement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.CSourceStatement:
+		if !cv.config.AllowInlineC {
+			cv.add(s, "inline-c", "inline C is not allowed, as with vcc_allow_inline_c off")
+		}
+	case *ast.ReturnStatement:
+		if returnActionName(s.Action) == "connect" && !cv.config.Features.Has(FeatureVCLConnect) {
+			cv.add(s, "feature-disabled", "return (connect) requires the VCL_CONNECT feature")
+		}
+	}
+}
+
+func (cv *ConfigValidator) add(node ast.Node, code, message string) {
+	cv.diagnostics = append(cv.diagnostics, NewDiagnostic(node, SeverityError, code, message))
+}
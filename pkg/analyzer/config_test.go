@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// configFindings analyzes vclCode with the given config and returns the
+// diagnostic codes with their severities, e.g. "inline-c:error"
+func configFindings(t *testing.T, registry *vmod.Registry, config Config, vclCode string) []string {
+	t.Helper()
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	a := NewAnalyzer(registry)
+	a.SetConfig(config)
+	var findings []string
+	for _, diag := range a.AnalyzeDiagnostics(program) {
+		findings = append(findings, diag.Code+":"+diag.Severity.String())
+	}
+	return findings
+}
+
+func TestConfigChecks(t *testing.T) {
+	vclCode := `vcl 4.1;
+
+include "/etc/varnish/backends.vcl";
+import std from "../vmods/libvmod_std.so";
+
+sub vcl_recv {
+	C{ /* inline */ }C
+	return (connect);
+}
+
+sub vcl_connect {
+	return (connect);
+}
+`
+	tests := []struct {
+		name     string
+		config   Config
+		expected []string
+	}{
+		{"default", DefaultConfig(), nil},
+		{"varnishd", VarnishdConfig(), []string{"inline-c:error", "feature-disabled:error",
+			"feature-disabled:error", "feature-disabled:error"}},
+		{"safe paths", Config{AllowInlineC: true, Features: FeatureVCLConnect}, []string{"unsafe-path:error", "unsafe-path:error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := configFindings(t, vmod.NewRegistry(), tt.config, vclCode)
+			if strings.Join(got, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConfigErrUnref(t *testing.T) {
+	vclCode := "vcl 4.1;\n\nsub unused {\n}\n"
+	config := DefaultConfig()
+	if got := configFindings(t, nil, config, vclCode); strings.Join(got, " ") != "unreferenced:warning" {
+		t.Errorf("Expected an unreferenced warning, got %v", got)
+	}
+	config.ErrUnref = true
+	if got := configFindings(t, nil, config, vclCode); strings.Join(got, " ") != "unreferenced:error" {
+		t.Errorf("Expected an unreferenced error, got %v", got)
+	}
+}
+
+func TestConfigLenientVmodRestrictions(t *testing.T) {
+	vcc := filepath.Join(t.TempDir(), "restricted.vcc")
+	err := os.WriteFile(vcc, []byte(`$Module restricted 3 "Restricted functions"
+$ABI strict
+$Function VOID recv_only()
+$Restrict vcl_recv
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := vmod.NewRegistry()
+	if err := registry.LoadVCCPath(vcc); err != nil {
+		t.Fatal(err)
+	}
+
+	vclCode := `vcl 4.1;
+
+import restricted;
+
+sub vcl_deliver {
+	restricted.recv_only();
+}
+`
+	config := DefaultConfig()
+	if got := configFindings(t, registry, config, vclCode); strings.Join(got, " ") != "vmod-restriction:error" {
+		t.Errorf("Expected a restriction error, got %v", got)
+	}
+
+	config.LenientVmodRestrictions = true
+	if got := configFindings(t, registry, config, vclCode); strings.Join(got, " ") != "vmod-restriction:warning" {
+		t.Errorf("Expected a restriction warning, got %v", got)
+	}
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	a := NewAnalyzer(registry)
+	a.SetConfig(config)
+	if errors := a.Analyze(program); len(errors) != 0 {
+		t.Errorf("Expected no errors in lenient mode, got %v", errors)
+	}
+}
+
+func TestParseFeatures(t *testing.T) {
+	features, err := ParseFeatures("vcl_connect")
+	if err != nil || !features.Has(FeatureVCLConnect) || features.String() != "VCL_CONNECT" {
+		t.Errorf("ParseFeatures = %v, %v", features, err)
+	}
+	if _, err := ParseFeatures("VCL_TELEPORT"); err == nil {
+		t.Error("Expected an error for an unknown feature")
+	}
+}
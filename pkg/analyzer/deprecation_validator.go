@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+)
+
+// DeprecationValidator warns about constructs that still compile but are
+// deprecated, such as req.esi, beresp.storage_hint and the ban() built-in,
+// and suggests vcl 4.1 for vcl 4.0 declarations. What is deprecated comes
+// from the deprecated_since entries in the metadata. Findings have the code
+// "deprecated" and are warnings unless SetSeverity says otherwise.
+type DeprecationValidator struct {
+	loader      *metadata.MetadataLoader
+	release     metadata.Release
+	severity    Severity
+	diagnostics []Diagnostic
+}
+
+// NewDeprecationValidator creates a new deprecation validator
+func NewDeprecationValidator(loader *metadata.MetadataLoader) *DeprecationValidator {
+	return &DeprecationValidator{
+		loader:   loader,
+		severity: SeverityWarning,
+	}
+}
+
+// SetSeverity sets the severity of the findings
+func (dv *DeprecationValidator) SetSeverity(severity Severity) {
+	dv.severity = severity
+}
+
+// SetRelease selects the Varnish release VCL is written for. Constructs
+// deprecated after that release are not reported. The zero release reports
+// every deprecation.
+func (dv *DeprecationValidator) SetRelease(release metadata.Release) {
+	dv.release = release
+}
+
+// Validate reports the deprecated constructs used in program
+func (dv *DeprecationValidator) Validate(program *ast.Program) []Diagnostic {
+	dv.diagnostics = nil
+
+	meta, err := dv.loader.GetMetadata()
+	if err != nil || meta == nil {
+		return nil
+	}
+
+	if program.VCLVersion != nil {
+		construct := "vcl " + program.VCLVersion.Version
+		// Older VCL versions are still supported, so only suggest the newer
+		// one rather than calling them deprecated
+		if dep, ok := meta.Deprecations[construct]; ok && dep.Replacement != "" && dv.applies(dep) {
+			dv.add(program.VCLVersion, dv.severity, "deprecated", fmt.Sprintf(
+				"%s still works, but consider %s, available since Varnish %s", construct, dep.Replacement, dep.DeprecatedSince))
+		}
+	}
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		switch e := c.Node().(type) {
+		case *ast.MemberExpression:
+			name := ast.VariableName(e)
+			if variable, ok := meta.VCLVariables[name]; ok && variable.DeprecatedSince != "" {
+				dv.report(e, name, metadata.Deprecation{
+					DeprecatedSince: variable.DeprecatedSince,
+					Replacement:     variable.Replacement,
+				})
+			}
+			// The parts of a variable name are not variables themselves
+			return false
+		case *ast.CallExpression:
+			if ident, ok := e.Function.(*ast.Identifier); ok {
+				construct := ident.Name + "()"
+				if dep, ok := meta.Deprecations[construct]; ok {
+					dv.report(ident, construct, dep)
+				}
+			}
+		}
+		return true
+	}, nil)
+
+	return dv.diagnostics
+}
+
+// applies reports whether the target release is not older than dep
+func (dv *DeprecationValidator) applies(dep metadata.Deprecation) bool {
+	if !dv.release.IsZero() {
+		if since, err := metadata.ParseRelease(dep.DeprecatedSince); err == nil && dv.release.Before(since) {
+			return false
+		}
+	}
+	return true
+}
+
+// report adds a finding for construct unless the target release predates
+// its deprecation
+func (dv *DeprecationValidator) report(node ast.Node, construct string, dep metadata.Deprecation) {
+	if !dv.applies(dep) {
+		return
+	}
+	message := fmt.Sprintf("%s is deprecated since Varnish %s", construct, dep.DeprecatedSince)
+	if dep.Replacement != "" {
+		message += fmt.Sprintf("; use %s instead", dep.Replacement)
+	}
+	dv.add(node, dv.severity, "deprecated", message)
+}
+
+func (dv *DeprecationValidator) add(node ast.Node, severity Severity, code, message string) {
+	dv.diagnostics = append(dv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestDeprecationValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		release  string
+		expected []string
+	}{
+		{
+			name: "current constructs",
+			vclCode: `vcl 4.1;
+				import std;
+				sub vcl_recv {
+					if (std.ban("req.url ~ " + req.url)) {
+						return (synth(200));
+					}
+				}
+				sub vcl_backend_response {
+					set beresp.storage = storage.s0;
+				}`,
+		},
+		{
+			name:     "vcl 4.0",
+			vclCode:  `vcl 4.0;`,
+			expected: []string{"vcl 4.0 still works, but consider vcl 4.1, available since Varnish 6.0"},
+		},
+		{
+			name: "deprecated variables",
+			vclCode: `vcl 4.0;
+				sub vcl_recv {
+					set req.esi = false;
+				}
+				sub vcl_backend_response {
+					if (beresp.storage_hint == "s0") {
+						set beresp.ttl = 1h;
+					}
+				}`,
+			expected: []string{
+				"vcl 4.0",
+				"req.esi is deprecated since Varnish 6.0; use resp.do_esi instead",
+				"beresp.storage_hint is deprecated since Varnish 6.0; use beresp.storage instead",
+			},
+		},
+		{
+			name: "ban built-in",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					ban("req.url ~ " + req.url);
+				}`,
+			expected: []string{"ban() is deprecated since Varnish 6.2; use std.ban() instead"},
+		},
+		{
+			name:    "vcl 4.0 before vcl 4.1",
+			vclCode: `vcl 4.0;`,
+			release: "5.2",
+		},
+		{
+			name: "release before the deprecation",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					ban("req.url ~ " + req.url);
+				}`,
+			release: "6.0",
+		},
+		{
+			name: "release after the deprecation",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					ban("req.url ~ " + req.url);
+				}`,
+			release:  "7.5",
+			expected: []string{"ban() is deprecated"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			validator := NewDeprecationValidator(metadata.New())
+			if test.release != "" {
+				release, err := metadata.ParseRelease(test.release)
+				if err != nil {
+					t.Fatal(err)
+				}
+				validator.SetRelease(release)
+			}
+			diags := validator.Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != SeverityWarning || diags[i].Code != "deprecated" {
+					t.Errorf("diagnostic %d = %s %s, want a deprecated warning", i, diags[i].Severity, diags[i].Code)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzerSetDeprecations(t *testing.T) {
+	program, err := parser.Parse("vcl 4.0;\n", "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	a := NewAnalyzer(setupTestRegistry(t))
+	if errs := a.Analyze(program); len(errs) != 0 {
+		t.Errorf("deprecations must not be errors by default, got %v", errs)
+	}
+
+	if err := a.SetDeprecations("error"); err != nil {
+		t.Fatal(err)
+	}
+	if errs := a.Analyze(program); len(errs) != 1 {
+		t.Errorf("expected the deprecation as an error, got %v", errs)
+	}
+
+	if err := a.SetDeprecations("off"); err != nil {
+		t.Fatal(err)
+	}
+	for _, diag := range a.AnalyzeDiagnostics(program) {
+		if diag.Code == "deprecated" {
+			t.Errorf("deprecations are off, got %v", diag)
+		}
+	}
+
+	if err := a.SetDeprecations("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
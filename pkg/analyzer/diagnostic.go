@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// Severity classifies how serious a diagnostic is
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+// String returns the lowercase severity name used in compiler-style output
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic is a single finding produced while parsing or analyzing VCL
+type Diagnostic struct {
+	Filename string
+	// Position is where the finding starts. Line is 0 when unknown, and
+	// Column is 0 when only the line is known.
+	Position lexer.Position
+	// EndPosition is where the construct the finding refers to ends, or the
+	// zero position when it is not known
+	EndPosition lexer.Position
+	Severity    Severity
+	// Code identifies the kind of finding, e.g. "variable-access", so tools
+	// can filter or look up findings without matching on messages. Plugin
+	// findings default to the ID of the rule that produced them.
+	Code    string
+	Message string
+	// IncludeChain lists the files that included Filename, outermost first,
+	// when the program was merged from several files and a SourceLocator
+	// was set
+	IncludeChain []string
+
+	// node is the construct the finding refers to, used to find the
+	// declaration and thereby the file it came from
+	node ast.Node
+}
+
+// NewDiagnostic returns a diagnostic spanning node, or without a position
+// if node is nil. Findings made with it by rules and passes are attributed to
+// the included file node came from.
+func NewDiagnostic(node ast.Node, severity Severity, code, message string) Diagnostic {
+	diag := Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+		node:     node,
+	}
+	if node != nil {
+		diag.Position = node.Start()
+		diag.EndPosition = node.End()
+	}
+	return diag
+}
+
+// DiagnosticFromParseError converts a parser error into a Diagnostic,
+// folding any suggestion into the message
+func DiagnosticFromParseError(err parser.DetailedError) Diagnostic {
+	message := err.Message
+	if err.Suggestion != "" {
+		message += fmt.Sprintf(" (did you mean %s?)", err.Suggestion)
+	}
+	return Diagnostic{
+		Filename: err.Filename,
+		Position: err.Position,
+		Severity: SeverityError,
+		Code:     "parse-error",
+		Message:  message,
+	}
+}
+
+// DiagnosticFromParseWarning converts a parser warning into a Diagnostic
+// with the warning's code
+func DiagnosticFromParseWarning(w parser.Warning) Diagnostic {
+	return Diagnostic{
+		Filename:    w.Filename,
+		Position:    w.Position,
+		EndPosition: w.EndPosition,
+		Severity:    SeverityWarning,
+		Code:        w.Code,
+		Message:     w.Message,
+	}
+}
+
+// ParseDiagnostics returns the errors and warnings of a parse as
+// diagnostics, errors first
+func ParseDiagnostics(result *parser.ParseResult) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(result.Errors)+len(result.Warnings))
+	for _, err := range result.Errors {
+		diags = append(diags, DiagnosticFromParseError(err))
+	}
+	for _, w := range result.Warnings {
+		diags = append(diags, DiagnosticFromParseWarning(w))
+	}
+	return diags
+}
+
+// legacyMessage formats a diagnostic the way the []string APIs report
+// findings, with the line folded into the message
+func legacyMessage(diag Diagnostic) string {
+	if diag.Position.Line > 0 {
+		return fmt.Sprintf("at line %d: %s", diag.Position.Line, diag.Message)
+	}
+	return diag.Message
+}
+
+// legacyMessages formats diagnostics for the []string validator APIs
+func legacyMessages(diags []Diagnostic) []string {
+	result := make([]string, len(diags))
+	for i, diag := range diags {
+		result[i] = legacyMessage(diag)
+	}
+	return result
+}
+
+// AnalyzeDiagnostics performs complete semantic analysis on an AST and returns
+// the findings as diagnostics. Filenames are left empty unless a SourceLocator
+// is set; callers that know the source file should fill them in.
+func (a *Analyzer) AnalyzeDiagnostics(program *ast.Program) []Diagnostic {
+	diags := a.runValidators(program)
+	diags = append(diags, a.runDiagnosticValidators(program)...)
+	if a.sourceLocator != nil {
+		locateSources(program, diags, a.sourceLocator)
+	}
+	return diags
+}
+
+// AnalyzeDiagnosticsContext is AnalyzeDiagnostics that skips the analysis
+// passes not started yet once ctx is done. It then returns the findings of
+// the passes that ran together with ctx.Err(), so a deadline yields partial
+// results rather than none.
+func (a *Analyzer) AnalyzeDiagnosticsContext(ctx context.Context, program *ast.Program) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	err := a.withContext(ctx, func() {
+		diags = a.AnalyzeDiagnostics(program)
+	})
+	return diags, err
+}
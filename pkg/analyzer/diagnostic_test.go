@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestAnalyzeDiagnostics(t *testing.T) {
+	vclCode := `vcl 4.1;
+
+sub vcl_recv {
+	return (lookup);
+}
+`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	diags := NewAnalyzer(nil).AnalyzeDiagnostics(program)
+	if len(diags) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d: %v", len(diags), diags)
+	}
+	if diags[0].Severity != SeverityError {
+		t.Errorf("Expected error severity, got %s", diags[0].Severity)
+	}
+	if diags[0].Position.Line != 4 {
+		t.Errorf("Expected line 4, got %d", diags[0].Position.Line)
+	}
+}
+
+func TestDiagnosticPositionsAndCodes(t *testing.T) {
+	vclCode := `vcl 4.1;
+
+sub vcl_recv {
+	set beresp.ttl = 1s;
+	return (lookup);
+}
+`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	expected := map[string]struct {
+		line, column int
+		text         string
+	}{
+		"variable-access": {4, 6, "beresp.ttl"},
+		"return-action":   {5, 2, "return (lookup"},
+	}
+
+	diags := NewAnalyzer(nil).AnalyzeDiagnostics(program)
+	if len(diags) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(diags), diags)
+	}
+	for _, diag := range diags {
+		want, ok := expected[diag.Code]
+		if !ok {
+			t.Errorf("Unexpected diagnostic %q: %s", diag.Code, diag.Message)
+			continue
+		}
+		if diag.Position.Line != want.line || diag.Position.Column != want.column {
+			t.Errorf("%s: expected %d:%d, got %d:%d", diag.Code, want.line, want.column,
+				diag.Position.Line, diag.Position.Column)
+		}
+		if text := vclCode[diag.Position.Offset:diag.EndPosition.Offset]; !strings.HasPrefix(text, want.text) {
+			t.Errorf("%s: expected span starting with %q, got %q", diag.Code, want.text, text)
+		}
+		if strings.Contains(diag.Message, "at line") {
+			t.Errorf("%s: message repeats the line: %s", diag.Code, diag.Message)
+		}
+	}
+}
+
+func TestLegacyMessages(t *testing.T) {
+	vclCode := `vcl 4.1;
+
+sub vcl_recv {
+	set beresp.ttl = 1s;
+}
+`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	validator := NewVariableAccessValidator(metadata.New(), types.NewSymbolTable())
+	errors := validator.Validate(program)
+	diags := validator.Diagnostics()
+	if len(errors) != 1 || len(diags) != 1 {
+		t.Fatalf("Expected 1 error and 1 diagnostic, got %v and %v", errors, diags)
+	}
+	if want := "at line 4: " + diags[0].Message; errors[0] != want {
+		t.Errorf("Expected %q, got %q", want, errors[0])
+	}
+}
@@ -0,0 +1,221 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"runtime"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/cache"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// DirOptions configures AnalyzeDir. The zero value analyzes with the
+// embedded VMODs and default settings.
+type DirOptions struct {
+	// Registry holds the VMODs imports are checked against. Nil selects a
+	// registry with the embedded VCC files.
+	Registry *vmod.Registry
+	// ParserConfig is used to parse every file. Nil selects
+	// parser.DefaultConfig(). Files included by several others are parsed
+	// once and shared, so Arena may not be set.
+	ParserConfig *parser.Config
+	// ResolverOptions are applied after include.WithFS, for instance to set
+	// a search path or placeholder values. Includes are otherwise resolved
+	// relative to the root of the file system.
+	ResolverOptions []include.Option
+	// Configure is called with the analyzer of every file before it runs,
+	// to apply settings such as SetVarnishVersion. An error is reported as
+	// the Err of that file.
+	Configure func(*Analyzer) error
+	// Jobs is the number of files analyzed at the same time. Zero selects
+	// runtime.GOMAXPROCS(0).
+	Jobs int
+}
+
+// FileResult is the outcome of analyzing one file found by AnalyzeDir
+type FileResult struct {
+	// Path is the path of the file within the file system
+	Path string
+	// Diagnostics holds the parse errors of the file and its includes or,
+	// when everything parsed, the analysis findings. Findings in included
+	// files carry the name of that file and the include chain.
+	Diagnostics []Diagnostic
+	// Err is set when the file could not be analyzed, for instance because
+	// an include is missing or Configure failed
+	Err error
+}
+
+// AnalyzeDir analyzes every .vcl file of fsys and sends the result for each
+// file on the returned channel as soon as it is done, so callers can report
+// findings while the rest of the directory is still being analyzed. The
+// channel is closed after the last file.
+//
+// Files included by other files are analyzed as part of those, not on their
+// own, since fragments usually depend on declarations of the file including
+// them. Every file is parsed once, however many files include it. Results
+// arrive in the order files finish, not in path order.
+func AnalyzeDir(fsys fs.FS, opts DirOptions) <-chan FileResult {
+	return AnalyzeDirContext(context.Background(), fsys, opts)
+}
+
+// AnalyzeDirContext is AnalyzeDir that stops once ctx is done. Files being
+// analyzed at that point are sent with the findings so far and ctx.Err() as
+// Err, if the caller still receives; the other files are left out. The
+// channel is closed either way, so callers may simply stop receiving.
+func AnalyzeDirContext(ctx context.Context, fsys fs.FS, opts DirOptions) <-chan FileResult {
+	results := make(chan FileResult)
+	go func() {
+		defer close(results)
+		d := &dirAnalysis{ctx: ctx, fsys: fsys, opts: opts, results: results}
+		d.run()
+	}()
+	return results
+}
+
+// dirAnalysis holds the state of one AnalyzeDir call
+type dirAnalysis struct {
+	ctx     context.Context
+	fsys    fs.FS
+	opts    DirOptions
+	results chan<- FileResult
+}
+
+// send passes a result to the caller unless the context is done and the
+// caller may have stopped receiving
+func (d *dirAnalysis) send(result FileResult) bool {
+	select {
+	case d.results <- result:
+		return true
+	case <-d.ctx.Done():
+		return false
+	}
+}
+
+// dirFile is a .vcl file of the directory with its includes resolved
+type dirFile struct {
+	path      string
+	program   *ast.Program
+	sourceMap *include.SourceMap
+	err       error
+}
+
+func (d *dirAnalysis) run() {
+	var paths []string
+	err := fs.WalkDir(d.fsys, ".", func(p string, entry fs.DirEntry, err error) error {
+		if ctxErr := d.ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if !d.send(FileResult{Path: p, Err: err}) {
+				return d.ctx.Err()
+			}
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() && path.Ext(p) == ".vcl" {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		if d.ctx.Err() == nil {
+			d.send(FileResult{Path: ".", Err: err})
+		}
+		return
+	}
+
+	// Resolve every file, remembering which files are included by others
+	c, err := cache.New(cache.WithParserConfig(d.opts.ParserConfig))
+	if err != nil {
+		d.send(FileResult{Path: ".", Err: err})
+		return
+	}
+	options := append([]include.Option{include.WithFS(d.fsys)}, d.opts.ResolverOptions...)
+	options = append(options, include.WithParseFunc(c.Parse))
+	files := make([]dirFile, len(paths))
+	included := make(map[string]bool)
+	for i, p := range paths {
+		resolver := include.NewResolver(options...)
+		program, err := resolver.ResolveFileContext(d.ctx, p)
+		if d.ctx.Err() != nil {
+			return
+		}
+		files[i] = dirFile{path: p, program: program, sourceMap: resolver.SourceMap(), err: err}
+		for _, f := range resolver.Files() {
+			if f != p {
+				included[path.Clean(f)] = true
+			}
+		}
+		// An include that fails to parse is reported with the file
+		// including it
+		var parseErr *include.ParseError
+		if errors.As(err, &parseErr) && parseErr.Path != p {
+			included[path.Clean(parseErr.Path)] = true
+		}
+	}
+
+	registry := d.opts.Registry
+	if registry == nil {
+		registry = vmod.NewRegistry()
+	}
+	jobs := d.opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, jobs)
+	for _, f := range files {
+		if included[f.path] {
+			continue
+		}
+		slots <- struct{}{}
+		if d.ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(f dirFile) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			d.send(d.analyze(f, registry))
+		}(f)
+	}
+	wg.Wait()
+}
+
+// analyze returns the findings for a resolved file
+func (d *dirAnalysis) analyze(f dirFile, registry *vmod.Registry) FileResult {
+	result := FileResult{Path: f.path}
+	if f.err != nil {
+		var detailed parser.DetailedError
+		if errors.As(f.err, &detailed) {
+			result.Diagnostics = []Diagnostic{DiagnosticFromParseError(detailed)}
+		} else {
+			result.Err = f.err
+		}
+		return result
+	}
+
+	a := NewAnalyzer(registry)
+	if d.opts.Configure != nil {
+		if err := d.opts.Configure(a); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	a.SetSourceLocator(f.sourceMap)
+	result.Diagnostics, result.Err = a.AnalyzeDiagnosticsContext(d.ctx, f.program)
+	for i := range result.Diagnostics {
+		if result.Diagnostics[i].Filename == "" {
+			result.Diagnostics[i].Filename = f.path
+		}
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAnalyzeDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.vcl": {Data: []byte(`vcl 4.1;
+include "conf.d/recv.vcl";
+
+backend default { .host = "127.0.0.1"; }
+`)},
+		"conf.d/recv.vcl": {Data: []byte(`vcl 4.1;
+
+sub vcl_recv {
+	return (lookup);
+}
+`)},
+		"clean.vcl": {Data: []byte(`vcl 4.1;
+
+backend default { .host = "127.0.0.1"; }
+`)},
+		"broken.vcl":  {Data: []byte("vcl 4.1;\nsub vcl_recv {\n")},
+		"missing.vcl": {Data: []byte("vcl 4.1;\ninclude \"nowhere.vcl\";\n")},
+		"README.md":   {Data: []byte("not VCL")},
+	}
+
+	results := make(map[string]FileResult)
+	for result := range AnalyzeDir(fsys, DirOptions{Jobs: 2}) {
+		if _, seen := results[result.Path]; seen {
+			t.Errorf("%s reported twice", result.Path)
+		}
+		results[result.Path] = result
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected results for 4 files, got %d: %v", len(results), results)
+	}
+	if _, ok := results["conf.d/recv.vcl"]; ok {
+		t.Error("Included file should only be analyzed as part of main.vcl")
+	}
+
+	main := results["main.vcl"]
+	if main.Err != nil || len(main.Diagnostics) != 1 {
+		t.Fatalf("Expected one finding for main.vcl, got %v (err %v)", main.Diagnostics, main.Err)
+	}
+	if d := main.Diagnostics[0]; d.Filename != "conf.d/recv.vcl" || d.Position.Line != 4 {
+		t.Errorf("Expected finding at conf.d/recv.vcl:4, got %s:%d", d.Filename, d.Position.Line)
+	}
+
+	if clean := results["clean.vcl"]; clean.Err != nil || len(clean.Diagnostics) != 0 {
+		t.Errorf("Expected no findings for clean.vcl, got %v (err %v)", clean.Diagnostics, clean.Err)
+	}
+
+	broken := results["broken.vcl"]
+	if broken.Err != nil || len(broken.Diagnostics) != 1 || broken.Diagnostics[0].Severity != SeverityError {
+		t.Errorf("Expected a parse error for broken.vcl, got %v (err %v)", broken.Diagnostics, broken.Err)
+	}
+
+	if missing := results["missing.vcl"]; missing.Err == nil {
+		t.Error("Expected an error for the missing include")
+	}
+}
+
+func TestAnalyzeDirCrossFileCalls(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.vcl": {Data: []byte(`vcl 4.1;
+include "tenants/a.vcl";
+include "tenants/b.vcl";
+
+backend default { .host = "127.0.0.1"; }
+
+sub vcl_recv {
+	call tenant_a;
+	call tenant_b;
+}
+`)},
+		"missing.vcl": {Data: []byte(`vcl 4.1;
+include "tenants/a.vcl";
+
+backend default { .host = "127.0.0.1"; }
+
+sub vcl_recv {
+	call tenant_a;
+	call tenant_b;
+}
+`)},
+		"tenants/a.vcl": {Data: []byte("vcl 4.1;\nsub tenant_a {\n\tset req.http.X-Tenant = \"a\";\n}\n")},
+		"tenants/b.vcl": {Data: []byte("vcl 4.1;\nsub tenant_b {\n\tset req.http.X-Tenant = \"b\";\n}\n")},
+	}
+
+	results := make(map[string]FileResult)
+	for result := range AnalyzeDir(fsys, DirOptions{}) {
+		results[result.Path] = result
+	}
+	// The tenant files are analyzed as part of the files including them
+	if len(results) != 2 {
+		t.Fatalf("Expected results for 2 files, got %v", results)
+	}
+	if main := results["main.vcl"]; main.Err != nil || len(main.Diagnostics) != 0 {
+		t.Errorf("Expected no findings for main.vcl, got %v (err %v)", main.Diagnostics, main.Err)
+	}
+	missing := results["missing.vcl"]
+	if missing.Err != nil || len(missing.Diagnostics) != 1 {
+		t.Fatalf("Expected one finding for missing.vcl, got %v (err %v)", missing.Diagnostics, missing.Err)
+	}
+	if d := missing.Diagnostics[0]; d.Code != "undefined-subroutine" || d.Filename != "missing.vcl" || d.Position.Line != 8 {
+		t.Errorf("Expected undefined-subroutine at missing.vcl:8, got %s at %s:%d", d.Code, d.Filename, d.Position.Line)
+	}
+}
+
+func TestAnalyzeDirConfigure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.vcl": {Data: []byte("vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\n")},
+	}
+	var configured int
+	for result := range AnalyzeDir(fsys, DirOptions{Configure: func(a *Analyzer) error {
+		configured++
+		return a.SetVarnishVersion("7.4")
+	}}) {
+		if result.Err != nil {
+			t.Errorf("Unexpected error for %s: %v", result.Path, result.Err)
+		}
+	}
+	if configured != 1 {
+		t.Errorf("Expected Configure to be called once, got %d", configured)
+	}
+}
+
+func TestAnalyzeDirContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.vcl": {Data: []byte("vcl 4.1;\nsub vcl_recv { return (lookup); }\n")},
+		"b.vcl": {Data: []byte("vcl 4.1;\nsub vcl_recv { return (lookup); }\n")},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for result := range AnalyzeDirContext(ctx, fsys, DirOptions{}) {
+		t.Errorf("Expected no results after cancellation, got %s", result.Path)
+	}
+
+	// Stopping to receive must not leak the analysis
+	ctx, cancel = context.WithCancel(context.Background())
+	results := AnalyzeDirContext(ctx, fsys, DirOptions{Jobs: 1})
+	<-results
+	cancel()
+	for range results {
+	}
+}
+
+func TestAnalyzeDiagnosticsContext(t *testing.T) {
+	program := parseVCL(t, "vcl 4.1;\nsub vcl_recv { return (lookup); }\n")
+
+	diags, err := NewAnalyzer(nil).AnalyzeDiagnosticsContext(context.Background(), program)
+	if err != nil || len(diags) != 1 {
+		t.Fatalf("Expected 1 finding, got %v (err %v)", diags, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	diags, err = NewAnalyzer(nil).AnalyzeDiagnosticsContext(ctx, program)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if len(diags) != 0 {
+		t.Errorf("Expected the skipped passes to report nothing, got %v", diags)
+	}
+}
@@ -0,0 +1,237 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+)
+
+// doFlagConflict is a pair of fetch processing flags that make no sense when
+// both are enabled on the same path through a subroutine
+type doFlagConflict struct {
+	first, second string
+	message       string
+}
+
+var doFlagConflicts = []doFlagConflict{
+	{
+		first:  "beresp.do_gzip",
+		second: "beresp.do_gunzip",
+		message: "beresp.do_gzip and beresp.do_gunzip are both enabled: Varnish only gzips uncompressed " +
+			"and gunzips compressed bodies, so at most one of them applies to a response",
+	},
+	{
+		first:  "beresp.do_esi",
+		second: "beresp.do_gunzip",
+		message: "beresp.do_esi and beresp.do_gunzip are both enabled: the ESI processed object is stored " +
+			"uncompressed, and Varnish does not compress it again for clients accepting gzip",
+	},
+}
+
+// backendErrorIneffective are flags that are writable in vcl_backend_error
+// but ignored there, because the synthetic body is stored as-is without
+// running the fetch processors
+var backendErrorIneffective = map[string]bool{
+	"beresp.do_gzip":   true,
+	"beresp.do_gunzip": true,
+	"beresp.do_stream": true,
+}
+
+// DoFlagsValidator checks the beresp.do_* fetch processing flags for
+// combinations Varnish documents as ineffective, and the use of ESI: ESI
+// processing enabled for every content type and ESI variables that stay
+// constant because it is never enabled. Flags that do not exist in the
+// program's VCL version are left to the version validator.
+type DoFlagsValidator struct {
+	loader      *metadata.MetadataLoader
+	version     int
+	diagnostics []Diagnostic
+}
+
+// doFlagState tracks the constant values assigned to flags along one path
+type doFlagState map[string]bool
+
+// NewDoFlagsValidator creates a new do_* flag validator
+func NewDoFlagsValidator(loader *metadata.MetadataLoader) *DoFlagsValidator {
+	return &DoFlagsValidator{
+		loader: loader,
+	}
+}
+
+// Validate checks the do_* assignments in every subroutine
+func (dv *DoFlagsValidator) Validate(program *ast.Program) []Diagnostic {
+	dv.diagnostics = nil
+	dv.version = programVCLVersion(program)
+
+	var respEsiOff []*ast.SetStatement
+
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub.Body == nil {
+			continue
+		}
+
+		reported := map[string]bool{}
+		dv.walkBlock(sub.Body, doFlagState{}, reported)
+
+		walkSubStatements(sub.Body, func(stmt ast.Statement) {
+			set, ok := stmt.(*ast.SetStatement)
+			if !ok {
+				return
+			}
+			name := ast.VariableName(set.Variable)
+			value, isConst := boolConstant(set.Value)
+			switch {
+			case name == "resp.do_esi" && isConst && !value && dv.available(name):
+				respEsiOff = append(respEsiOff, set)
+			case sub.Name == "vcl_backend_error" && backendErrorIneffective[name] && dv.available(name):
+				dv.add(set, SeverityWarning, "do-flags-no-effect", fmt.Sprintf(
+					"%s has no effect in vcl_backend_error: the synthetic body is stored as-is", name))
+			}
+		})
+	}
+
+	// resp.do_esi can only turn off ESI processing that beresp.do_esi
+	// turned on when the object was fetched
+	esi := ExtractESIUsage(program)
+	if len(esi.Enabled) == 0 {
+		for _, set := range respEsiOff {
+			dv.add(set, SeverityInfo, "do-flags-no-effect",
+				"resp.do_esi = false has no effect: beresp.do_esi is never enabled, so no object is ESI processed")
+		}
+		for _, read := range esi.LevelReads {
+			dv.add(read.node, SeverityInfo, "esi-level-constant",
+				"req.esi_level is always 0: beresp.do_esi is never enabled, so no ESI includes are requested")
+		}
+	}
+	dv.checkESIContentType(esi)
+
+	return dv.diagnostics
+}
+
+// checkESIContentType reports the assignments turning ESI processing on
+// that some path from vcl_backend_response reaches without looking at the
+// Content-Type, which has Varnish parse images and other binary responses
+// for ESI tags. Synthetic bodies in vcl_backend_error are written by the
+// VCL itself.
+func (dv *DoFlagsValidator) checkESIContentType(esi *ESIUsage) {
+	reported := make(map[ast.Node]bool)
+	for _, flow := range esi.Flows {
+		node := flow.Assignment.node
+		if flow.Path[0] != "vcl_backend_response" || flow.ContentTypeChecked || node == nil || reported[node] {
+			continue
+		}
+		reported[node] = true
+		dv.add(node, SeverityWarning, "esi-content-type", fmt.Sprintf(
+			"beresp.do_esi is enabled in %s without checking beresp.http.Content-Type, "+
+				"so every response is parsed for ESI tags; enable it for HTML only", strings.Join(flow.Path, " -> ")))
+	}
+}
+
+// walkBlock follows constant flag assignments through nested blocks. Each
+// branch starts from the state of its enclosing block.
+func (dv *DoFlagsValidator) walkBlock(block *ast.BlockStatement, state doFlagState, reported map[string]bool) {
+	for _, stmt := range block.Statements {
+		switch s := stmt.(type) {
+		case *ast.SetStatement:
+			name := ast.VariableName(s.Variable)
+			if !strings.HasPrefix(name, "beresp.do_") {
+				continue
+			}
+			value, ok := boolConstant(s.Value)
+			if !ok {
+				delete(state, name)
+				continue
+			}
+			state[name] = value
+			if value {
+				dv.checkConflicts(s, name, state, reported)
+			}
+		case *ast.BlockStatement:
+			dv.walkBlock(s, state.copy(), reported)
+		case *ast.IfStatement:
+			for _, branch := range ifBranches(s) {
+				dv.walkBlock(branch, state.copy(), reported)
+			}
+		}
+	}
+}
+
+// checkConflicts reports each conflict involving flag once per subroutine
+func (dv *DoFlagsValidator) checkConflicts(stmt *ast.SetStatement, flag string, state doFlagState, reported map[string]bool) {
+	for _, conflict := range doFlagConflicts {
+		if flag != conflict.first && flag != conflict.second {
+			continue
+		}
+		if !state[conflict.first] || !state[conflict.second] || reported[conflict.first+conflict.second] {
+			continue
+		}
+		if !dv.available(conflict.first) || !dv.available(conflict.second) {
+			continue
+		}
+		reported[conflict.first+conflict.second] = true
+		dv.add(stmt, SeverityWarning, "do-flags-conflict", conflict.message)
+	}
+}
+
+// available reports whether the metadata lists the variable for the
+// program's VCL version
+func (dv *DoFlagsValidator) available(name string) bool {
+	variables, err := dv.loader.GetVariables()
+	if err != nil {
+		return false
+	}
+	v, ok := variables[name]
+	return ok && dv.version >= v.VersionLow && dv.version <= v.VersionHigh
+}
+
+func (dv *DoFlagsValidator) add(node ast.Node, severity Severity, code, message string) {
+	dv.diagnostics = append(dv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
+
+func (s doFlagState) copy() doFlagState {
+	c := make(doFlagState, len(s))
+	for k, v := range s {
+		c[k] = v
+	}
+	return c
+}
+
+// ifBranches returns the blocks of an if/else-if chain, including the final
+// else block
+func ifBranches(stmt *ast.IfStatement) []*ast.BlockStatement {
+	var blocks []*ast.BlockStatement
+	for stmt != nil {
+		if block, ok := stmt.Then.(*ast.BlockStatement); ok {
+			blocks = append(blocks, block)
+		}
+		switch e := stmt.Else.(type) {
+		case *ast.IfStatement:
+			stmt = e
+			continue
+		case *ast.BlockStatement:
+			blocks = append(blocks, e)
+		}
+		break
+	}
+	return blocks
+}
+
+// boolConstant returns the value of true or false. The parser produces
+// these as identifiers.
+func boolConstant(expr ast.Expression) (value, ok bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BooleanLiteral:
+		return e.Value, true
+	case *ast.Identifier:
+		switch e.Name {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestDoFlagsValidator(t *testing.T) {
+	loader := metadata.New()
+
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+		severity Severity
+	}{
+		{
+			name: "gzip and gunzip in different branches",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					if (beresp.http.content-type ~ "text") {
+						set beresp.do_gzip = true;
+						set beresp.do_esi = true;
+					} else {
+						set beresp.do_gunzip = true;
+					}
+					set beresp.do_stream = true;
+				}`,
+		},
+		{
+			name: "gzip and gunzip together",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.do_gzip = true;
+					if (beresp.http.x-legacy) {
+						set beresp.do_gunzip = true;
+					}
+				}`,
+			expected: []string{"beresp.do_gzip and beresp.do_gunzip are both enabled"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "gzip switched off before gunzip",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.do_gzip = true;
+					set beresp.do_gzip = false;
+					set beresp.do_gunzip = true;
+				}`,
+		},
+		{
+			name: "fetch flags in vcl_backend_error",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_error {
+					set beresp.do_gzip = true;
+					set beresp.do_esi = true;
+				}`,
+			expected: []string{"beresp.do_gzip has no effect in vcl_backend_error"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "resp.do_esi without beresp.do_esi",
+			vclCode: `vcl 4.1;
+				sub vcl_deliver {
+					set resp.do_esi = false;
+				}`,
+			expected: []string{"resp.do_esi = false has no effect"},
+			severity: SeverityInfo,
+		},
+		{
+			name: "resp.do_esi with beresp.do_esi",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.do_esi = beresp.http.x-esi == "on";
+				}
+				sub vcl_deliver {
+					if (req.http.x-no-esi) {
+						set resp.do_esi = false;
+					}
+				}`,
+		},
+		{
+			name: "esi and gunzip together",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					if (beresp.http.content-type ~ "html") {
+						set beresp.do_gunzip = true;
+						set beresp.do_esi = true;
+					}
+				}`,
+			expected: []string{"beresp.do_esi and beresp.do_gunzip are both enabled"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "esi without content type check",
+			vclCode: `vcl 4.1;
+				sub enable_esi {
+					set beresp.do_esi = true;
+				}
+				sub vcl_backend_response {
+					if (bereq.url ~ "^/pages/") {
+						call enable_esi;
+					}
+				}`,
+			expected: []string{"beresp.do_esi is enabled in vcl_backend_response -> enable_esi without checking beresp.http.Content-Type"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "esi checked at the call",
+			vclCode: `vcl 4.1;
+				sub enable_esi {
+					set beresp.do_esi = true;
+				}
+				sub vcl_backend_response {
+					if (beresp.http.Content-Type ~ "text/html") {
+						call enable_esi;
+					}
+				}`,
+		},
+		{
+			name: "esi level without esi",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.esi_level > 0) {
+						return (pass);
+					}
+				}`,
+			expected: []string{"req.esi_level is always 0"},
+			severity: SeverityInfo,
+		},
+		{
+			name: "resp.do_esi is left to the version validator in 4.0",
+			vclCode: `vcl 4.0;
+				sub vcl_deliver {
+					set resp.do_esi = false;
+				}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewDoFlagsValidator(loader).Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != test.severity {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, test.severity)
+				}
+			}
+		})
+	}
+}
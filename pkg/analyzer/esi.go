@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+// ESIUsage lists where a program turns Edge Side Includes processing on
+// and off, where it reads the ESI nesting level, and the paths through
+// which fetched objects become ESI processed
+type ESIUsage struct {
+	// Enabled are the assignments of beresp.do_esi that may turn ESI
+	// processing on: true or a value that is not constant
+	Enabled []ESIUse
+	// Disabled are the assignments of false to beresp.do_esi and
+	// resp.do_esi
+	Disabled []ESIUse
+	// LevelReads are the reads of req.esi_level
+	LevelReads []ESIUse
+	// Flows are the paths from a built-in subroutine to each assignment in
+	// Enabled, one for every way of reaching it through call statements
+	Flows []ESIFlow
+}
+
+// ESIUse is one statement or expression of a program concerning ESI
+type ESIUse struct {
+	Sub      string
+	Variable string
+	// Value is the value assigned as formatted VCL, empty for reads
+	Value string
+	// Conditions are the conditions of the if statements in Sub the use
+	// is nested in, outermost first, as formatted VCL. Conditions of else
+	// branches are negated.
+	Conditions []string
+	Position   lexer.Position
+
+	node ast.Node
+}
+
+// ESIFlow is a path from a built-in subroutine, through the custom
+// subroutines it calls, to an assignment turning ESI processing on
+type ESIFlow struct {
+	// Path is the built-in subroutine followed by the custom subroutines
+	// called on the way to the assignment
+	Path []string
+	// Conditions are the conditions of the if statements around the calls
+	// and the assignment, outermost first
+	Conditions []string
+	// ContentTypeChecked reports whether one of the conditions looks at
+	// beresp.http.Content-Type, or the value assigned is not constant, so
+	// that only some responses are processed
+	ContentTypeChecked bool
+	Assignment         ESIUse
+}
+
+// ExtractESIUsage collects the ESI related statements of program. Enabled,
+// Disabled and LevelReads are syntactic and list uses in custom subroutines
+// once; Flows follow the calls from the built-in subroutines.
+func ExtractESIUsage(program *ast.Program) *ESIUsage {
+	e := &esiExtractor{usage: &ESIUsage{}, subs: make(map[string]*ast.SubDecl)}
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub != nil && sub.Body != nil {
+			e.subs[sub.Name] = sub
+			e.sub = sub.Name
+			e.statement(sub.Body, nil)
+		}
+	}
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub != nil && sub.Body != nil && strings.HasPrefix(sub.Name, "vcl_") {
+			e.flows(sub.Body, []string{sub.Name}, nil, false)
+		}
+	}
+	return e.usage
+}
+
+// esiExtractor holds the state of one ExtractESIUsage run
+type esiExtractor struct {
+	usage *ESIUsage
+	subs  map[string]*ast.SubDecl
+	sub   string
+}
+
+// statement collects the ESI uses in stmt, which is reached under
+// conditions
+func (e *esiExtractor) statement(stmt ast.Statement, conditions []string) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		for _, inner := range s.Statements {
+			e.statement(inner, conditions)
+		}
+		return
+	case *ast.IfStatement:
+		e.levelReads(s.Condition, conditions)
+		condition := printer.Expression(s.Condition)
+		e.statement(s.Then, withCondition(conditions, condition))
+		if s.Else != nil {
+			e.statement(s.Else, withCondition(conditions, "!("+condition+")"))
+		}
+		return
+	case *ast.SetStatement:
+		name := ast.VariableName(s.Variable)
+		if name == "beresp.do_esi" || name == "resp.do_esi" {
+			use := e.use(s, name, printer.Expression(s.Value), conditions)
+			if value, ok := boolConstant(s.Value); ok && !value {
+				e.usage.Disabled = append(e.usage.Disabled, use)
+			} else if name == "beresp.do_esi" {
+				e.usage.Enabled = append(e.usage.Enabled, use)
+			}
+		}
+	}
+	e.levelReads(stmt, conditions)
+}
+
+// levelReads collects the reads of req.esi_level below node. Blocks of
+// nested statements are left to statement.
+func (e *esiExtractor) levelReads(node ast.Node, conditions []string) {
+	walkNodes(node, func(n ast.Node) {
+		if member, ok := n.(*ast.MemberExpression); ok && ast.VariableName(member) == "req.esi_level" {
+			e.usage.LevelReads = append(e.usage.LevelReads, e.use(member, "req.esi_level", "", conditions))
+		}
+	})
+}
+
+// flows follows stmt and the subroutines it calls to the assignments
+// turning ESI processing on. Subroutines already on path are not entered
+// again.
+func (e *esiExtractor) flows(stmt ast.Statement, path, conditions []string, typed bool) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		for _, inner := range s.Statements {
+			e.flows(inner, path, conditions, typed)
+		}
+	case *ast.IfStatement:
+		condition := printer.Expression(s.Condition)
+		checks := typed || checksContentType(s.Condition)
+		e.flows(s.Then, path, withCondition(conditions, condition), checks)
+		if s.Else != nil {
+			e.flows(s.Else, path, withCondition(conditions, "!("+condition+")"), checks)
+		}
+	case *ast.CallStatement:
+		name := calledSubName(s)
+		if sub, ok := e.subs[name]; ok && !containsString(path, name) {
+			e.flows(sub.Body, append(path[:len(path):len(path)], name), conditions, typed)
+		}
+	case *ast.SetStatement:
+		if ast.VariableName(s.Variable) != "beresp.do_esi" {
+			return
+		}
+		value, ok := boolConstant(s.Value)
+		if ok && !value {
+			return
+		}
+		// A value that is not constant decides per response, as a
+		// check of the Content-Type would
+		flow := ESIFlow{Path: path, Conditions: conditions, ContentTypeChecked: typed || !ok}
+		for _, use := range e.usage.Enabled {
+			if use.node == s {
+				flow.Assignment = use
+			}
+		}
+		e.usage.Flows = append(e.usage.Flows, flow)
+	}
+}
+
+func (e *esiExtractor) use(node ast.Node, variable, value string, conditions []string) ESIUse {
+	return ESIUse{
+		Sub:        e.sub,
+		Variable:   variable,
+		Value:      value,
+		Conditions: conditions,
+		Position:   node.Start(),
+		node:       node,
+	}
+}
+
+// checksContentType reports whether a condition looks at the Content-Type
+// of the backend response
+func checksContentType(condition ast.Expression) bool {
+	found := false
+	walkNodes(condition, func(n ast.Node) {
+		if member, ok := n.(*ast.MemberExpression); ok && strings.EqualFold(ast.VariableName(member), "beresp.http.Content-Type") {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestExtractESIUsage(t *testing.T) {
+	src := `vcl 4.1;
+
+sub esi_html {
+	if (beresp.http.Content-Type ~ "html") {
+		set beresp.do_esi = true;
+	}
+}
+
+sub vcl_recv {
+	if (req.esi_level > 0) {
+		set req.http.X-Fragment = "1";
+	}
+}
+
+sub vcl_backend_response {
+	if (bereq.url ~ "^/shop/") {
+		call esi_html;
+	} else {
+		set beresp.do_esi = false;
+	}
+}
+
+sub vcl_deliver {
+	set resp.do_esi = req.http.X-Debug != "raw";
+	set resp.http.X-Level = req.esi_level;
+}
+`
+	program, err := parser.Parse(src, "test.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := ExtractESIUsage(program)
+
+	if len(usage.Enabled) != 1 || usage.Enabled[0].Sub != "esi_html" || usage.Enabled[0].Value != "true" ||
+		!reflect.DeepEqual(usage.Enabled[0].Conditions, []string{`beresp.http.Content-Type ~ "html"`}) {
+		t.Errorf("Unexpected enabling assignments: %+v", usage.Enabled)
+	}
+	if len(usage.Disabled) != 1 || usage.Disabled[0].Variable != "beresp.do_esi" || usage.Disabled[0].Position.Line != 19 {
+		t.Errorf("Unexpected disabling assignments: %+v", usage.Disabled)
+	}
+	if len(usage.LevelReads) != 2 || usage.LevelReads[0].Sub != "vcl_recv" || usage.LevelReads[1].Sub != "vcl_deliver" {
+		t.Errorf("Unexpected esi_level reads: %+v", usage.LevelReads)
+	}
+
+	if len(usage.Flows) != 1 {
+		t.Fatalf("Expected one flow, got %+v", usage.Flows)
+	}
+	flow := usage.Flows[0]
+	if !reflect.DeepEqual(flow.Path, []string{"vcl_backend_response", "esi_html"}) || !flow.ContentTypeChecked ||
+		len(flow.Conditions) != 2 || flow.Assignment.Position.Line != 5 {
+		t.Errorf("Unexpected flow: %+v", flow)
+	}
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// constValue is the value of an expression known without running it
+type constValue struct {
+	kind   constKind
+	bool   bool
+	number float64
+	str    string
+}
+
+type constKind int
+
+const (
+	constBool constKind = iota + 1
+	constNumber
+	constString
+)
+
+// evalConstant folds expressions whose value does not depend on the
+// request: literals, comparisons and matches between literals, logical
+// operators with a known operand, and comparisons of an expression with
+// itself, such as req.url == req.url or x && !x. It reports false when
+// the value is not known.
+func evalConstant(expr ast.Expression) (constValue, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BooleanLiteral:
+		return constValue{kind: constBool, bool: e.Value}, true
+	case *ast.Identifier:
+		// The parser reads true and false in conditions as identifiers
+		if e.Name == "true" || e.Name == "false" {
+			return boolValue(e.Name == "true"), true
+		}
+	case *ast.IntegerLiteral:
+		return constValue{kind: constNumber, number: float64(e.Value)}, true
+	case *ast.FloatLiteral:
+		return constValue{kind: constNumber, number: e.Value}, true
+	case *ast.StringLiteral:
+		return constValue{kind: constString, str: e.Value}, true
+	case *ast.UnaryExpression:
+		operand, ok := evalConstant(e.Operand)
+		switch {
+		case !ok:
+		case e.Operator == "!" && operand.kind == constBool:
+			return boolValue(!operand.bool), true
+		case e.Operator == "-" && operand.kind == constNumber:
+			return constValue{kind: constNumber, number: -operand.number}, true
+		}
+	case *ast.BinaryExpression:
+		return evalBinary(e)
+	case *ast.RegexMatchExpression:
+		subject, ok := evalConstant(e.Left)
+		pattern, isLit := e.Right.(*ast.StringLiteral)
+		if !ok || !isLit || subject.kind != constString {
+			break
+		}
+		re, err := regexp.Compile(pcreToRE2(pattern.Value))
+		if err != nil {
+			break
+		}
+		return boolValue(re.MatchString(subject.str) == (e.Operator == "~")), true
+	}
+	return constValue{}, false
+}
+
+func evalBinary(e *ast.BinaryExpression) (constValue, bool) {
+	left, leftOK := evalConstant(e.Left)
+	right, rightOK := evalConstant(e.Right)
+
+	switch e.Operator {
+	case "&&", "||":
+		// A known operand decides the result if it is false for && or
+		// true for ||, whatever the other one is
+		decisive := e.Operator == "||"
+		for _, operand := range []struct {
+			value constValue
+			ok    bool
+		}{{left, leftOK}, {right, rightOK}} {
+			if operand.ok && operand.value.kind == constBool && operand.value.bool == decisive {
+				return boolValue(decisive), true
+			}
+		}
+		if leftOK && rightOK && left.kind == constBool && right.kind == constBool {
+			return boolValue(!decisive), true
+		}
+		if isNegationOf(e.Left, e.Right) || isNegationOf(e.Right, e.Left) {
+			return boolValue(decisive), true
+		}
+		return constValue{}, false
+	}
+
+	if !leftOK || !rightOK {
+		// Both sides are the same deterministic expression
+		if key := stableKey(e.Left); key != "" && key == stableKey(e.Right) {
+			switch e.Operator {
+			case "==", "<=", ">=":
+				return boolValue(true), true
+			case "!=", "<", ">":
+				return boolValue(false), true
+			}
+		}
+		return constValue{}, false
+	}
+	if left.kind != right.kind {
+		return constValue{}, false
+	}
+
+	var cmp int
+	switch left.kind {
+	case constNumber:
+		cmp = compareNumbers(left.number, right.number)
+	case constString:
+		cmp = strings.Compare(left.str, right.str)
+	case constBool:
+		if e.Operator != "==" && e.Operator != "!=" {
+			return constValue{}, false
+		}
+		if left.bool != right.bool {
+			cmp = 1
+		}
+	}
+	switch e.Operator {
+	case "==":
+		return boolValue(cmp == 0), true
+	case "!=":
+		return boolValue(cmp != 0), true
+	case "<":
+		return boolValue(cmp < 0), true
+	case "<=":
+		return boolValue(cmp <= 0), true
+	case ">":
+		return boolValue(cmp > 0), true
+	case ">=":
+		return boolValue(cmp >= 0), true
+	}
+	return constValue{}, false
+}
+
+// isNegationOf reports whether negated is !expr
+func isNegationOf(negated, expr ast.Expression) bool {
+	unary, ok := ast.Unparen(negated).(*ast.UnaryExpression)
+	if !ok || unary.Operator != "!" {
+		return false
+	}
+	key := stableKey(unary.Operand)
+	return key != "" && key == stableKey(expr)
+}
+
+// stableKey returns the expressionKey of expressions that evaluate to the
+// same value each time within a condition, which rules out function calls
+// such as std.random(). It returns "" for other expressions.
+func stableKey(expr ast.Expression) string {
+	stable := true
+	inspectExpression(expr, func(e ast.Expression) {
+		if _, ok := e.(*ast.CallExpression); ok {
+			stable = false
+		}
+	})
+	if !stable {
+		return ""
+	}
+	return expressionKey(expr)
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolValue(b bool) constValue {
+	return constValue{kind: constBool, bool: b}
+}
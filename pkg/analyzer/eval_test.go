@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestEvalConstant(t *testing.T) {
+	tests := []struct {
+		condition string
+		known     bool
+		value     bool
+	}{
+		{`true`, true, true},
+		{`!false`, true, true},
+		{`1 < 2`, true, true},
+		{`"abc" ~ "^a"`, true, true},
+		{`"abc" !~ "^a"`, true, false},
+		{`req.url == req.url`, true, true},
+		{`req.restarts > req.restarts`, true, false},
+		{`req.http.x && !req.http.x`, true, false},
+		{`req.http.x || true`, true, true},
+		{`req.http.x && true`, false, false},
+		{`req.url == "/"`, false, false},
+		{`std.random(0, 1) == std.random(0, 1)`, false, false},
+	}
+
+	for _, test := range tests {
+		vclCode := "vcl 4.1;\nsub vcl_recv { if (" + test.condition + ") { return (pass); } }\n"
+		program, err := parser.Parse(vclCode, "test.vcl")
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", test.condition, err)
+		}
+		ifStmt := program.Declarations[0].(*ast.SubDecl).Body.Statements[0].(*ast.IfStatement)
+
+		value, ok := evalConstant(ifStmt.Condition)
+		if ok != test.known {
+			t.Errorf("evalConstant(%s) known = %v, want %v", test.condition, ok, test.known)
+			continue
+		}
+		if ok && (value.kind != constBool || value.bool != test.value) {
+			t.Errorf("evalConstant(%s) = %+v, want %v", test.condition, value, test.value)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// flowExit says how control leaves a statement. The values are ordered so
+// that the exit of an if/else is the smaller of its branches.
+type flowExit int
+
+const (
+	// flowContinue falls through to the next statement
+	flowContinue flowExit = iota
+	// flowReturn is a bare return, which leaves the current subroutine
+	flowReturn
+	// flowAction is a return with an action, restart or error, which ends
+	// the VCL state no matter how deeply subroutine calls are nested
+	flowAction
+)
+
+// FlowValidator follows the control flow through each subroutine. It flags
+// statements that can never run because every path before them returned,
+// branches whose condition an earlier returning if statement already
+// handled, and optionally built-in subroutines that return on some paths but fall
+// through to the built-in VCL on others.
+type FlowValidator struct {
+	subs                map[string]*ast.SubDecl
+	exits               map[string]flowExit
+	reportFallthrough   bool
+	fallthroughSeverity Severity
+	diagnostics         []Diagnostic
+}
+
+// NewFlowValidator creates a new control flow validator. Fallthrough is not
+// reported until enabled with SetFallthrough.
+func NewFlowValidator() *FlowValidator {
+	return &FlowValidator{fallthroughSeverity: SeverityWarning}
+}
+
+// SetFallthrough sets whether built-in subroutines that fall through to the
+// built-in VCL on only some paths are reported, and with which severity
+func (fv *FlowValidator) SetFallthrough(report bool, severity Severity) {
+	fv.reportFallthrough = report
+	fv.fallthroughSeverity = severity
+}
+
+// Validate checks the control flow of every subroutine in the program
+func (fv *FlowValidator) Validate(program *ast.Program) []Diagnostic {
+	fv.diagnostics = nil
+	fv.subs = make(map[string]*ast.SubDecl)
+	fv.exits = make(map[string]flowExit)
+
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			fv.subs[sub.Name] = sub
+		}
+	}
+
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub.Body == nil {
+			continue
+		}
+		exit, _ := fv.block(sub.Body, true)
+		fv.checkBranches(sub.Body.Statements, nil)
+
+		// A built-in subroutine without any return is the documented way
+		// of adding to the built-in VCL; only mixing the two is suspicious
+		if fv.reportFallthrough && exit == flowContinue && isBuiltinSubroutine(sub.Name) && hasReturn(sub.Body) {
+			fv.add(sub, fv.fallthroughSeverity, "fallthrough",
+				fmt.Sprintf("%s returns on some paths but falls through to the built-in %s on others", sub.Name, sub.Name))
+		}
+	}
+
+	return fv.diagnostics
+}
+
+// block returns how control leaves a block and the statement that made it
+// leave. With report set, the first statement after that one is flagged.
+func (fv *FlowValidator) block(block *ast.BlockStatement, report bool) (flowExit, ast.Statement) {
+	for i, stmt := range block.Statements {
+		exit, exitStmt := fv.statement(stmt, report)
+		if exit == flowContinue {
+			continue
+		}
+		if report && i+1 < len(block.Statements) {
+			fv.add(block.Statements[i+1], SeverityWarning, "unreachable",
+				fmt.Sprintf("statement after %s is never executed", describeExit(exitStmt)))
+		}
+		return exit, exitStmt
+	}
+	return flowContinue, nil
+}
+
+func (fv *FlowValidator) statement(stmt ast.Statement, report bool) (flowExit, ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		return fv.block(s, report)
+	case *ast.IfStatement:
+		thenExit, thenStmt := fv.statement(s.Then, report)
+		elseExit, elseStmt := flowContinue, ast.Statement(nil)
+		if s.Else != nil {
+			elseExit, elseStmt = fv.statement(s.Else, report)
+		}
+		if elseExit < thenExit {
+			return elseExit, elseStmt
+		}
+		if thenExit == flowContinue {
+			return flowContinue, nil
+		}
+		// Both branches leave; name the last one in the source
+		if elseStmt != nil {
+			return thenExit, elseStmt
+		}
+		return thenExit, thenStmt
+	case *ast.ReturnStatement:
+		if s.Action == nil {
+			return flowReturn, s
+		}
+		return flowAction, s
+	case *ast.RestartStatement, *ast.ErrorStatement:
+		return flowAction, s
+	case *ast.CallStatement:
+		if fv.subExit(calledSubName(s)) == flowAction {
+			return flowAction, s
+		}
+	}
+	return flowContinue, nil
+}
+
+// handledCondition is the condition of an if statement whose branch always
+// leaves, so the condition is false for the statements after it
+type handledCondition struct {
+	condition ast.Expression
+	exit      ast.Statement
+}
+
+// checkBranches reports if and else-if conditions that can only be true
+// for requests an earlier if statement in the same block already sent
+// elsewhere, as in if (req.url ~ "^/api") { return (pass); } followed by
+// if (req.url ~ "^/api/v2") { ... }
+func (fv *FlowValidator) checkBranches(stmts []ast.Statement, handled []handledCondition) {
+	// Appending must not leak facts into the caller's slice
+	handled = handled[:len(handled):len(handled)]
+
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.BlockStatement:
+			fv.checkBranches(s.Statements, handled)
+		case *ast.IfStatement:
+			var exited []handledCondition
+			for link := s; link != nil; {
+				if prev, ok := handledBy(link.Condition, handled); ok {
+					fv.add(link.Condition, SeverityWarning, "unreachable-branch", fmt.Sprintf(
+						"branch is never taken: %s at line %d already handles requests matching this condition",
+						describeExit(prev.exit), prev.exit.Start().Line))
+				}
+				fv.checkBranches(branchStatements(link.Then), handled)
+				if exit, exitStmt := fv.statement(link.Then, false); exit != flowContinue {
+					exited = append(exited, handledCondition{link.Condition, exitStmt})
+				}
+				next, ok := link.Else.(*ast.IfStatement)
+				if !ok {
+					fv.checkBranches(branchStatements(link.Else), handled)
+					break
+				}
+				link = next
+			}
+			handled = append(forgetChanged(handled, s), exited...)
+		case *ast.CallStatement, *ast.ExpressionStatement:
+			// Anything may change in the called code
+			handled = handled[:0:0]
+		default:
+			handled = forgetChanged(handled, s)
+		}
+	}
+}
+
+// branchStatements returns the statements of an if branch
+func branchStatements(stmt ast.Statement) []ast.Statement {
+	switch s := stmt.(type) {
+	case nil:
+		return nil
+	case *ast.BlockStatement:
+		return s.Statements
+	}
+	return []ast.Statement{stmt}
+}
+
+// handledBy returns the handled condition that is true whenever cond is,
+// comparing each conjunct of cond with each disjunct of the handled ones
+func handledBy(cond ast.Expression, handled []handledCondition) (handledCondition, bool) {
+	for _, prev := range handled {
+		for _, disjunct := range splitOr(prev.condition) {
+			key := expressionKey(disjunct)
+			for _, conjunct := range splitAnd(cond) {
+				if key != "" && key == expressionKey(conjunct) || matchSubsumes(disjunct, conjunct) {
+					return prev, true
+				}
+			}
+		}
+	}
+	return handledCondition{}, false
+}
+
+// forgetChanged drops the handled conditions that mention a variable stmt
+// sets or unsets, or all of them if it calls anything
+func forgetChanged(handled []handledCondition, stmt ast.Statement) []handledCondition {
+	var changed []string
+	calls := false
+	walkSubStatements(stmt, func(inner ast.Statement) {
+		switch s := inner.(type) {
+		case *ast.SetStatement:
+			changed = append(changed, expressionKey(s.Variable))
+		case *ast.UnsetStatement:
+			changed = append(changed, expressionKey(s.Variable))
+		case *ast.CallStatement, *ast.ExpressionStatement:
+			calls = true
+		}
+	})
+	if calls {
+		return handled[:0:0]
+	}
+
+	var kept []handledCondition
+	for _, prev := range handled {
+		key := expressionKey(prev.condition)
+		stale := key == ""
+		for _, name := range changed {
+			if name == "" || strings.Contains(key, name) {
+				stale = true
+			}
+		}
+		if !stale {
+			kept = append(kept, prev)
+		}
+	}
+	return kept
+}
+
+// subExit returns how a call to the named subroutine leaves the caller.
+// Only a subroutine that always ends the VCL state leaves it; a bare return
+// merely ends the call. Recursion, which VCC rejects, counts as continuing.
+func (fv *FlowValidator) subExit(name string) flowExit {
+	if exit, ok := fv.exits[name]; ok {
+		return exit
+	}
+	sub, ok := fv.subs[name]
+	if !ok {
+		return flowContinue
+	}
+	fv.exits[name] = flowContinue
+	exit, _ := fv.block(sub.Body, false)
+	if exit != flowAction {
+		exit = flowContinue
+	}
+	fv.exits[name] = exit
+	return exit
+}
+
+// describeExit names the statement that ended a path for use in messages
+func describeExit(stmt ast.Statement) string {
+	switch s := stmt.(type) {
+	case *ast.ReturnStatement:
+		if s.Action == nil {
+			return "return"
+		}
+		return fmt.Sprintf("return (%s)", returnActionName(s.Action))
+	case *ast.RestartStatement:
+		return "restart"
+	case *ast.ErrorStatement:
+		return "error"
+	case *ast.CallStatement:
+		return fmt.Sprintf("call %s, which always returns,", calledSubName(s))
+	}
+	return "return"
+}
+
+// hasReturn reports whether a block contains a return statement anywhere
+func hasReturn(block *ast.BlockStatement) bool {
+	found := false
+	walkSubStatements(block, func(stmt ast.Statement) {
+		if _, ok := stmt.(*ast.ReturnStatement); ok {
+			found = true
+		}
+	})
+	return found
+}
+
+func (fv *FlowValidator) add(node ast.Node, severity Severity, code, message string) {
+	fv.diagnostics = append(fv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
@@ -0,0 +1,233 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestFlowValidator(t *testing.T) {
+	tests := []struct {
+		name              string
+		vclCode           string
+		reportFallthrough bool
+		expected          []string
+		codes             []string
+	}{
+		{
+			name: "return at the end",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					set req.http.X-Seen = "1";
+					return (hash);
+				}`,
+		},
+		{
+			name: "statement after return",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					return (pass);
+					set req.http.X-Seen = "1";
+					set req.http.X-Other = "1";
+				}`,
+			expected: []string{"statement after return (pass) is never executed"},
+			codes:    []string{"unreachable"},
+		},
+		{
+			name: "statement after return (pipe) in a branch",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.http.Upgrade) {
+						return (pipe);
+						unset req.http.Cookie;
+					}
+				}`,
+			expected: []string{"statement after return (pipe) is never executed"},
+			codes:    []string{"unreachable"},
+		},
+		{
+			name: "both branches return",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.method == "POST") {
+						return (pass);
+					} else {
+						return (hash);
+					}
+					set req.http.X-Seen = "1";
+				}`,
+			expected: []string{"statement after return (hash) is never executed"},
+			codes:    []string{"unreachable"},
+		},
+		{
+			name: "only one branch returns",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.method == "POST") {
+						return (pass);
+					}
+					set req.http.X-Seen = "1";
+				}`,
+		},
+		{
+			name: "statement after restart",
+			vclCode: `vcl 4.1;
+				sub vcl_deliver {
+					if (resp.status == 503) {
+						restart;
+						set resp.http.X-Seen = "1";
+					}
+				}`,
+			expected: []string{"statement after restart is never executed"},
+			codes:    []string{"unreachable"},
+		},
+		{
+			name: "bare return in a custom subroutine",
+			vclCode: `vcl 4.1;
+				sub strip {
+					return;
+					unset req.http.Cookie;
+				}
+				sub vcl_recv {
+					call strip;
+					set req.http.X-Seen = "1";
+				}`,
+			expected: []string{"statement after return is never executed"},
+			codes:    []string{"unreachable"},
+		},
+		{
+			name: "call to a subroutine that always returns",
+			vclCode: `vcl 4.1;
+				sub deny {
+					return (synth(403));
+				}
+				sub vcl_recv {
+					call deny;
+					set req.http.X-Seen = "1";
+				}`,
+			expected: []string{"statement after call deny, which always returns, is never executed"},
+			codes:    []string{"unreachable"},
+		},
+		{
+			name: "fallthrough not reported by default",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.method == "POST") {
+						return (pass);
+					}
+				}`,
+		},
+		{
+			name: "fallthrough on some paths",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.method == "POST") {
+						return (pass);
+					}
+				}`,
+			reportFallthrough: true,
+			expected:          []string{"vcl_recv returns on some paths but falls through to the built-in vcl_recv on others"},
+			codes:             []string{"fallthrough"},
+		},
+		{
+			name: "documented fallthrough without any return",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					unset req.http.Cookie;
+				}`,
+			reportFallthrough: true,
+		},
+		{
+			name: "every path returns through a call",
+			vclCode: `vcl 4.1;
+				sub deny {
+					return (synth(403));
+				}
+				sub vcl_recv {
+					if (client.ip == "127.0.0.1") {
+						return (pass);
+					}
+					call deny;
+				}`,
+			reportFallthrough: true,
+		},
+		{
+			name: "branch handled by an earlier return",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.url ~ "^/api") {
+						return (pass);
+					}
+					if (req.http.x && req.url ~ "^/api/v2") {
+						set req.http.X-Version = "2";
+					}
+				}`,
+			expected: []string{"return (pass) at line 4 already handles requests matching this condition"},
+			codes:    []string{"unreachable-branch"},
+		},
+		{
+			name: "condition changed after the return",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.http.X-Api) {
+						return (pass);
+					}
+					set req.http.X-Api = "1";
+					if (req.http.X-Api) {
+						return (hash);
+					}
+				}`,
+		},
+		{
+			name: "branch that does not always leave",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.method == "PURGE") {
+						if (client.ip == "127.0.0.1") {
+							return (purge);
+						}
+					}
+					if (req.method == "PURGE") {
+						return (synth(405));
+					}
+				}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			validator := NewFlowValidator()
+			validator.SetFallthrough(test.reportFallthrough, SeverityWarning)
+			diags := validator.Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.codes[i] {
+					t.Errorf("diagnostic %d code = %s, want %s", i, diags[i].Code, test.codes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzerSetFallthrough(t *testing.T) {
+	a := NewAnalyzer(nil)
+	for _, level := range []string{"", "off", "info", "warning", "error"} {
+		if err := a.SetFallthrough(level); err != nil {
+			t.Errorf("SetFallthrough(%q) = %v", level, err)
+		}
+	}
+	if err := a.SetFallthrough("loud"); err == nil {
+		t.Error("SetFallthrough(\"loud\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,56 @@
+// Package goplugin loads analyzer plugins compiled as Go shared objects with
+// "go build -buildmode=plugin". It is kept separate from the analyzer package
+// so programs that never load shared objects don't link the plugin runtime.
+//
+// A plugin package must export a constructor:
+//
+//	func NewPlugin() analyzer.Plugin
+//
+// The shared object has to be built with the same Go toolchain and the same
+// version of this module as the program loading it.
+package goplugin
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+)
+
+// ConstructorName is the symbol looked up in each shared object
+const ConstructorName = "NewPlugin"
+
+// Load opens a shared object and registers the plugin it provides
+func Load(path string) (analyzer.Plugin, error) {
+	so, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening plugin %s: %w", path, err)
+	}
+
+	sym, err := so.Lookup(ConstructorName)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+
+	constructor, ok := sym.(func() analyzer.Plugin)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s: %s has type %T, want func() analyzer.Plugin",
+			path, ConstructorName, sym)
+	}
+
+	p := constructor()
+	if err := analyzer.RegisterPlugin(p); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// LoadAll loads each shared object in turn, stopping at the first failure
+func LoadAll(paths []string) error {
+	for _, path := range paths {
+		if _, err := Load(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// volatileHashInputs are request values that differ between nearly all
+// clients, so hashing on them gives every client its own cache object
+var volatileHashInputs = map[string]string{
+	"req.http.cookie":          "cookies differ per client",
+	"req.http.user-agent":      "there are thousands of distinct User-Agent strings",
+	"req.http.x-forwarded-for": "it is different for every client",
+	"client.ip":                "it is different for every client",
+}
+
+// HashValidator checks how the cache key is built: hash_data() is only valid
+// in vcl_hash, a vcl_hash that returns lookup must have hashed something
+// (normally req.url), and hashing on per-client values ruins the hit rate.
+type HashValidator struct {
+	diagnostics []Diagnostic
+}
+
+// NewHashValidator creates a new vcl_hash validator
+func NewHashValidator() *HashValidator {
+	return &HashValidator{}
+}
+
+// Validate checks every hash_data() call and vcl_hash itself
+func (hv *HashValidator) Validate(program *ast.Program) []Diagnostic {
+	hv.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			hv.diagnostics = append(hv.diagnostics, hv.validateSubroutine(sub)...)
+		}
+	}
+
+	return hv.diagnostics
+}
+
+// validateSubroutine checks the hash_data() calls of one subroutine
+func (hv *HashValidator) validateSubroutine(sub *ast.SubDecl) []Diagnostic {
+	v := &HashValidator{}
+	if sub.Name == "vcl_hash" {
+		v.validateHashSub(sub)
+		return v.diagnostics
+	}
+
+	// Custom subroutines may be called from vcl_hash, so only the built-in
+	// ones are known to be wrong
+	if !strings.HasPrefix(sub.Name, "vcl_") {
+		return nil
+	}
+	walkSubStatements(sub.Body, func(stmt ast.Statement) {
+		if call := hashDataStatement(stmt); call != nil {
+			v.add(call, SeverityError, "hash-data-context",
+				fmt.Sprintf("hash_data() can only be called in vcl_hash, not in %s", sub.Name))
+		}
+	})
+	return v.diagnostics
+}
+
+// validateHashSub checks the hash_data() calls made in vcl_hash, in order
+func (hv *HashValidator) validateHashSub(sub *ast.SubDecl) {
+	hashCalls := 0
+	hashesURL := false
+
+	walkSubStatements(sub.Body, func(stmt ast.Statement) {
+		if call := hashDataStatement(stmt); call != nil {
+			hashCalls++
+			for _, arg := range call.Arguments {
+				inspectExpression(arg, func(e ast.Expression) {
+					name := strings.ToLower(ast.VariableName(e))
+					if name == "req.url" || name == "bereq.url" {
+						hashesURL = true
+					}
+					if reason, volatile := volatileHashInputs[name]; volatile {
+						hv.add(e, SeverityWarning, "hash-client-split", fmt.Sprintf(
+							"hashing on %s splits the cache per client because %s; normalize it or use Vary instead",
+							ast.VariableName(e), reason))
+					}
+				})
+			}
+			return
+		}
+
+		ret, ok := stmt.(*ast.ReturnStatement)
+		if !ok || returnActionName(ret.Action) != "lookup" {
+			return
+		}
+		// return (lookup) skips the built-in vcl_hash, which would
+		// otherwise hash req.url and the host
+		switch {
+		case hashCalls == 0:
+			hv.add(ret, SeverityWarning, "hash-missing-data",
+				"return (lookup) in vcl_hash without any hash_data() call: every request maps to the same cache object")
+		case !hashesURL:
+			hv.add(ret, SeverityWarning, "hash-missing-url",
+				"return (lookup) in vcl_hash without hashing req.url: different URLs share a cache object")
+		}
+	})
+}
+
+func (hv *HashValidator) add(node ast.Node, severity Severity, code, message string) {
+	hv.diagnostics = append(hv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
+
+// hashDataStatement returns the call if stmt is hash_data(...)
+func hashDataStatement(stmt ast.Statement) *ast.CallExpression {
+	if exprStmt, ok := stmt.(*ast.ExpressionStatement); ok {
+		return HashDataCall(exprStmt.Expression)
+	}
+	return nil
+}
+
+// HashDataCall returns the call if n is a hash_data() call
+func HashDataCall(n ast.Node) *ast.CallExpression {
+	call, ok := n.(*ast.CallExpression)
+	if !ok {
+		return nil
+	}
+	if ident, ok := call.Function.(*ast.Identifier); ok && ident.Name == "hash_data" {
+		return call
+	}
+	return nil
+}
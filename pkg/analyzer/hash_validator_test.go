@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestHashValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+		severity Severity
+	}{
+		{
+			name: "url and host",
+			vclCode: `vcl 4.1;
+				sub vcl_hash {
+					hash_data(req.url);
+					if (req.http.host) {
+						hash_data(req.http.host);
+					} else {
+						hash_data(server.ip);
+					}
+					return (lookup);
+				}`,
+		},
+		{
+			name: "falling through to the built-in vcl_hash",
+			vclCode: `vcl 4.1;
+				sub vcl_hash {
+					hash_data(req.http.X-Language);
+				}`,
+		},
+		{
+			name: "lookup without hash_data",
+			vclCode: `vcl 4.1;
+				sub vcl_hash {
+					return (lookup);
+				}`,
+			expected: []string{"without any hash_data() call"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "lookup without req.url",
+			vclCode: `vcl 4.1;
+				sub vcl_hash {
+					hash_data(req.http.host);
+					return (lookup);
+				}`,
+			expected: []string{"without hashing req.url"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "hashing on cookies",
+			vclCode: `vcl 4.1;
+				sub vcl_hash {
+					hash_data(req.url);
+					hash_data(req.http.Cookie);
+				}`,
+			expected: []string{"hashing on req.http.Cookie splits the cache per client"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "hashing on user agent inside a function",
+			vclCode: `vcl 4.1;
+				import std;
+				sub vcl_hash {
+					hash_data(std.tolower(req.http.User-Agent));
+				}`,
+			expected: []string{"req.http.User-Agent"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "hash_data outside vcl_hash",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					hash_data(req.url);
+				}
+				sub custom_hash {
+					hash_data(req.http.host);
+				}`,
+			expected: []string{"hash_data() can only be called in vcl_hash, not in vcl_recv"},
+			severity: SeverityError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewHashValidator().Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != test.severity {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, test.severity)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import "github.com/perbu/vclparser/pkg/ast"
+
+// SubHeaderUsage lists the headers one subroutine uses, in source order
+type SubHeaderUsage struct {
+	Sub     string
+	Read    []HeaderReference
+	Written []HeaderReference
+	Unset   []HeaderReference
+	// Forwarded are the written headers Varnish sends on, see
+	// ForwardedHeader
+	Forwarded []ForwardedHeader
+}
+
+// ForwardedHeader is a header write that leaves Varnish. Backend requests
+// are built from req and sent as bereq, and responses are delivered from
+// beresp, through the cache, as resp, so writes to req and bereq reach the
+// backend and writes to beresp and resp reach the client unless the header
+// is unset again.
+type ForwardedHeader struct {
+	HeaderReference
+	// Destination is "backend" or "client"
+	Destination string
+}
+
+// headerDestinations maps header variables to where their writes go
+var headerDestinations = map[string]string{
+	"req":    "backend",
+	"bereq":  "backend",
+	"beresp": "client",
+	"resp":   "client",
+}
+
+// HeaderReport lists the headers each subroutine of program reads, writes
+// and unsets, and which of the writes are forwarded to backends or clients,
+// for auditing what a VCL exposes. Subroutines are listed in the order they
+// are first defined; repeated definitions of a built-in subroutine are
+// reported together, and subroutines without header uses are left out.
+func HeaderReport(program *ast.Program) []SubHeaderUsage {
+	var report []SubHeaderUsage
+	index := make(map[string]int)
+	for _, ref := range ReferencedHeaders(program) {
+		i, ok := index[ref.Sub]
+		if !ok {
+			i = len(report)
+			index[ref.Sub] = i
+			report = append(report, SubHeaderUsage{Sub: ref.Sub})
+		}
+		usage := &report[i]
+		switch ref.Access {
+		case HeaderRead:
+			usage.Read = append(usage.Read, ref)
+		case HeaderWrite:
+			usage.Written = append(usage.Written, ref)
+			if destination, ok := headerDestinations[ref.Object]; ok {
+				usage.Forwarded = append(usage.Forwarded, ForwardedHeader{HeaderReference: ref, Destination: destination})
+			}
+		case HeaderUnset:
+			usage.Unset = append(usage.Unset, ref)
+		}
+	}
+	return report
+}
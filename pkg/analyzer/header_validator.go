@@ -0,0 +1,240 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/perbu/vclparser/internal/strutil"
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// headerPrefixes are the variables whose .http. members are headers
+var headerPrefixes = map[string]bool{
+	"req":     true,
+	"req_top": true,
+	"bereq":   true,
+	"beresp":  true,
+	"obj":     true,
+	"resp":    true,
+}
+
+// wellKnownHeaders are checked for near misses, in their canonical spelling
+var wellKnownHeaders = []string{
+	"Accept", "Accept-Encoding", "Accept-Language", "Accept-Ranges",
+	"Access-Control-Allow-Origin", "Age", "Authorization", "Cache-Control",
+	"Connection", "Content-Disposition", "Content-Encoding", "Content-Language",
+	"Content-Length", "Content-Range", "Content-Security-Policy", "Content-Type",
+	"Cookie", "Date", "ETag", "Expires", "Host", "If-Modified-Since",
+	"If-None-Match", "Keep-Alive", "Last-Modified", "Location", "Origin",
+	"Pragma", "Range", "Referer", "Server", "Set-Cookie",
+	"Strict-Transport-Security", "Surrogate-Control", "Transfer-Encoding",
+	"Upgrade", "User-Agent", "Vary", "Via", "WWW-Authenticate",
+	"X-Forwarded-For", "X-Forwarded-Host", "X-Forwarded-Proto", "X-Real-IP",
+}
+
+// headerRef is one use of a header variable
+type headerRef struct {
+	node   ast.Node
+	prefix string // e.g. "req"
+	name   string // the header name as written
+	set    bool   // whether the header is set
+}
+
+// HeaderValidator checks the header names used in req.http.*, resp.http.*
+// and the other header variables: names must consist of HTTP token
+// characters, a header should be spelled the same way throughout the
+// program, and names one typo away from a well-known header are likely
+// mistakes. With SetHeaderPolicy it also reports setting headers that are
+// denied, such as internal headers that must not reach clients.
+type HeaderValidator struct {
+	allow       []string
+	deny        []string
+	diagnostics []Diagnostic
+}
+
+// NewHeaderValidator creates a new header name validator
+func NewHeaderValidator() *HeaderValidator {
+	return &HeaderValidator{}
+}
+
+// SetHeaderPolicy sets the headers that may not be set and the exceptions to
+// them. Entries are header names, optionally with a variable such as
+// "resp.http.X-Backend", and may contain * wildcards; they match regardless
+// of case.
+func (hv *HeaderValidator) SetHeaderPolicy(allow, deny []string) {
+	hv.allow = lowerAll(allow)
+	hv.deny = lowerAll(deny)
+}
+
+// Validate checks every header reference in the program
+func (hv *HeaderValidator) Validate(program *ast.Program) []Diagnostic {
+	hv.diagnostics = nil
+
+	var refs []headerRef
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.SetStatement:
+			if ref, ok := headerReference(n.Variable); ok {
+				ref.node = n
+				ref.set = true
+				refs = append(refs, ref)
+				// The target has been recorded, only check the value
+				hv.collect(n.Value, &refs)
+				return false
+			}
+		case *ast.AddStatement:
+			if ref, ok := headerReference(n.Variable); ok {
+				ref.node = n
+				ref.set = true
+				refs = append(refs, ref)
+				hv.collect(n.Value, &refs)
+				return false
+			}
+		case *ast.MemberExpression:
+			if ref, ok := headerReference(n); ok {
+				refs = append(refs, ref)
+				return false
+			}
+		}
+		return true
+	}, nil)
+
+	for _, ref := range refs {
+		hv.checkName(ref)
+	}
+	hv.checkSpelling(refs)
+
+	return hv.diagnostics
+}
+
+// collect appends the header references in an expression
+func (hv *HeaderValidator) collect(expr ast.Expression, refs *[]headerRef) {
+	if expr == nil {
+		return
+	}
+	ast.Apply(expr, func(c *ast.Cursor) bool {
+		if member, ok := c.Node().(*ast.MemberExpression); ok {
+			if ref, ok := headerReference(member); ok {
+				*refs = append(*refs, ref)
+				return false
+			}
+		}
+		return true
+	}, nil)
+}
+
+// checkName reports invalid characters, near misses of well-known headers
+// and denied headers
+func (hv *HeaderValidator) checkName(ref headerRef) {
+	variable := ref.prefix + ".http." + ref.name
+	for _, r := range ref.name {
+		if !isTokenChar(r) {
+			hv.add(ref.node, SeverityError, "header-name",
+				fmt.Sprintf("invalid character %q in header name %s", r, ref.name))
+			return
+		}
+	}
+
+	if suggestion := headerTypo(ref.name); suggestion != "" {
+		hv.add(ref.node, SeverityWarning, "header-typo",
+			fmt.Sprintf("%s looks like a misspelling of %s.http.%s", variable, ref.prefix, suggestion))
+	}
+
+	if ref.set && hv.denied(ref.prefix, ref.name) {
+		hv.add(ref.node, SeverityWarning, "header-denied",
+			fmt.Sprintf("%s is set, but the header policy denies %s", variable, ref.name))
+	}
+}
+
+// checkSpelling reports headers written with different case in different
+// places. The spelling used first is taken as the intended one.
+func (hv *HeaderValidator) checkSpelling(refs []headerRef) {
+	first := make(map[string]string)
+	for _, ref := range refs {
+		key := strings.ToLower(ref.name)
+		spelling, seen := first[key]
+		if !seen {
+			first[key] = ref.name
+			continue
+		}
+		if spelling != ref.name {
+			hv.add(ref.node, SeverityInfo, "header-case",
+				fmt.Sprintf("header %s is spelled %s elsewhere; header names are case-insensitive, but one spelling is easier to search for",
+					ref.name, spelling))
+		}
+	}
+}
+
+// denied reports whether setting the header is denied by the policy
+func (hv *HeaderValidator) denied(prefix, name string) bool {
+	matches := func(patterns []string) bool {
+		header := strings.ToLower(name)
+		variable := strings.ToLower(prefix) + ".http." + header
+		for _, pattern := range patterns {
+			target := header
+			if strings.Contains(pattern, ".http.") {
+				target = variable
+			}
+			if ok, _ := path.Match(pattern, target); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(hv.deny) && !matches(hv.allow)
+}
+
+func (hv *HeaderValidator) add(node ast.Node, severity Severity, code, message string) {
+	hv.diagnostics = append(hv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
+
+// headerReference returns the header a variable refers to, if any
+func headerReference(expr ast.Expression) (headerRef, bool) {
+	name := ast.VariableName(expr)
+	i := strings.Index(name, ".http.")
+	if i < 0 || !headerPrefixes[name[:i]] || len(name) == i+len(".http.") {
+		return headerRef{}, false
+	}
+	// A Fastly subfield, as in req.http.Cookie:session, is not part of the
+	// header name
+	header, _, _ := strings.Cut(name[i+len(".http."):], ":")
+	return headerRef{node: expr, prefix: name[:i], name: header}, true
+}
+
+// headerTypo returns the well-known header a name is one edit away from, or
+// "" if there is none or the name is a well-known header itself
+func headerTypo(name string) string {
+	if len(name) < 4 {
+		return ""
+	}
+	lower := strings.ToLower(name)
+	suggestion := ""
+	for _, known := range wellKnownHeaders {
+		switch strutil.EditDistance(lower, strings.ToLower(known)) {
+		case 0:
+			return ""
+		case 1:
+			suggestion = known
+		}
+	}
+	return suggestion
+}
+
+// isTokenChar reports whether r may appear in an HTTP header name (RFC 9110
+// tchar)
+func isTokenChar(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
+func lowerAll(list []string) []string {
+	lowered := make([]string, len(list))
+	for i, s := range list {
+		lowered[i] = strings.ToLower(s)
+	}
+	return lowered
+}
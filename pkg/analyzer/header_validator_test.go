@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestHeaderValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		allow    []string
+		deny     []string
+		expected []string
+		codes    []string
+	}{
+		{
+			name: "consistent headers",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.http.Cookie && req.http.X-Forwarded-For) {
+						unset req.http.Cookie;
+					}
+					set req.http.X-Request.Id = req.http.X-Forwarded-For;
+				}`,
+		},
+		{
+			name: "differently cased header",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					if (beresp.http.Content-Type ~ "text") {
+						set beresp.http.Content-type = "text/html";
+					}
+				}`,
+			expected: []string{"header Content-type is spelled Content-Type elsewhere"},
+			codes:    []string{"header-case"},
+		},
+		{
+			name: "misspelled well-known header",
+			vclCode: `vcl 4.1;
+				sub vcl_deliver {
+					set resp.http.Cache-Controll = "no-store";
+					unset resp.http.Sever;
+				}`,
+			expected: []string{
+				"resp.http.Cache-Controll looks like a misspelling of resp.http.Cache-Control",
+				"resp.http.Sever looks like a misspelling of resp.http.Server",
+			},
+			codes: []string{"header-typo", "header-typo"},
+		},
+		{
+			name: "denied header",
+			vclCode: `vcl 4.1;
+				sub vcl_deliver {
+					set resp.http.X-Internal-Backend = "web1";
+					set resp.http.X-Internal-Cache = "hit";
+					set req.http.X-Internal-Backend = "web1";
+				}`,
+			deny:     []string{"resp.http.x-internal-*"},
+			allow:    []string{"X-Internal-Cache"},
+			expected: []string{"resp.http.X-Internal-Backend is set, but the header policy denies X-Internal-Backend"},
+			codes:    []string{"header-denied"},
+		},
+		{
+			name: "denied header is only reported when set",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.http.Authorization) {
+						return (pass);
+					}
+				}
+				sub vcl_backend_fetch {
+					set bereq.http.Authorization = "Basic x";
+				}`,
+			deny:     []string{"Authorization"},
+			expected: []string{"bereq.http.Authorization is set, but the header policy denies Authorization"},
+			codes:    []string{"header-denied"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			validator := NewHeaderValidator()
+			validator.SetHeaderPolicy(test.allow, test.deny)
+			diags := validator.Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.codes[i] {
+					t.Errorf("diagnostic %d code = %s, want %s", i, diags[i].Code, test.codes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHeaderValidatorInvalidCharacter(t *testing.T) {
+	// The lexer only produces valid names, but trees may be built by hand
+	program := &ast.Program{Declarations: []ast.Declaration{
+		&ast.SubDecl{Name: "vcl_recv", Body: &ast.BlockStatement{Statements: []ast.Statement{
+			&ast.UnsetStatement{Variable: &ast.MemberExpression{
+				Object: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "req"},
+					Property: &ast.Identifier{Name: "http"},
+				},
+				Property: &ast.Identifier{Name: "X Forwarded"},
+			}},
+		}}},
+	}}
+
+	diags := NewHeaderValidator().Validate(program)
+	if len(diags) != 1 || diags[0].Code != "header-name" {
+		t.Fatalf("Expected one header-name diagnostic, got %v", diags)
+	}
+	if want := `invalid character ' ' in header name X Forwarded`; diags[0].Message != want {
+		t.Errorf("message = %q, want %q", diags[0].Message, want)
+	}
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// LabelValidator checks return (vcl(label)), which hands a request over to
+// the VCL loaded under a label. The argument must be a label name, and when
+// the labels known to varnishd are given, one of them.
+type LabelValidator struct {
+	labels      map[string]bool
+	diagnostics []Diagnostic
+}
+
+// NewLabelValidator creates a new label validator. With no labels, label
+// names are not checked against a list.
+func NewLabelValidator(labels ...string) *LabelValidator {
+	lv := &LabelValidator{}
+	lv.SetLabels(labels...)
+	return lv
+}
+
+// SetLabels sets the labels that return (vcl(label)) may refer to, as
+// created with vcl.label. With no labels, any name is accepted.
+func (lv *LabelValidator) SetLabels(labels ...string) {
+	lv.labels = nil
+	if len(labels) == 0 {
+		return
+	}
+	lv.labels = make(map[string]bool, len(labels))
+	for _, label := range labels {
+		lv.labels[label] = true
+	}
+}
+
+// Validate checks every return (vcl(...)) in the program
+func (lv *LabelValidator) Validate(program *ast.Program) []Diagnostic {
+	lv.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub == nil || sub.Body == nil {
+			continue
+		}
+		walkSubStatements(sub.Body, func(stmt ast.Statement) {
+			ret, ok := stmt.(*ast.ReturnStatement)
+			if !ok || ret == nil {
+				return
+			}
+			if call := vclLabelCall(ret.Action); call != nil {
+				lv.validateCall(call)
+			}
+		})
+	}
+
+	return lv.diagnostics
+}
+
+func (lv *LabelValidator) validateCall(call *ast.CallExpression) {
+	var label *ast.Identifier
+	if len(call.Arguments) == 1 && len(call.NamedArguments) == 0 {
+		label, _ = call.Arguments[0].(*ast.Identifier)
+	}
+	if label == nil {
+		lv.add(call, "vcl() takes a single VCL label name")
+		return
+	}
+	if lv.labels != nil && !lv.labels[label.Name] {
+		lv.add(label, fmt.Sprintf("unknown VCL label %s, known labels are %s", label.Name, lv.knownLabels()))
+	}
+}
+
+func (lv *LabelValidator) knownLabels() string {
+	names := make([]string, 0, len(lv.labels))
+	for name := range lv.labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (lv *LabelValidator) add(node ast.Node, message string) {
+	lv.diagnostics = append(lv.diagnostics, NewDiagnostic(node, SeverityError, "vcl-label", message))
+}
+
+// vclLabelCall returns the call if a return action is vcl(...)
+func vclLabelCall(action ast.Expression) *ast.CallExpression {
+	if paren, ok := action.(*ast.ParenthesizedExpression); ok {
+		action = paren.Expression
+	}
+	call, ok := action.(*ast.CallExpression)
+	if !ok || call == nil {
+		return nil
+	}
+	if ident, ok := call.Function.(*ast.Identifier); ok && ident.Name == "vcl" {
+		return call
+	}
+	return nil
+}
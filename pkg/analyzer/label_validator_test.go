@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestLabelValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		body     string
+		expected []string
+	}{
+		{
+			name: "any label without a list",
+			body: `return (vcl(api));`,
+		},
+		{
+			name:   "known label",
+			labels: []string{"api", "static"},
+			body:   `if (req.http.host == "api.example.com") { return (vcl(api)); }`,
+		},
+		{
+			name:     "unknown label",
+			labels:   []string{"api", "static"},
+			body:     `return (vcl(admin));`,
+			expected: []string{"unknown VCL label admin, known labels are api, static"},
+		},
+		{
+			name:     "string argument",
+			body:     `return (vcl("api"));`,
+			expected: []string{"vcl() takes a single VCL label name"},
+		},
+		{
+			name:     "no argument",
+			body:     `return (vcl());`,
+			expected: []string{"vcl() takes a single VCL label name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vclCode := "vcl 4.1;\n\nsub vcl_recv {\n\t" + tt.body + "\n}\n"
+			program, err := parser.Parse(vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			a := NewAnalyzer(nil)
+			a.SetLabels(tt.labels...)
+			var messages []string
+			for _, diag := range a.AnalyzeDiagnostics(program) {
+				messages = append(messages, diag.Message)
+				if diag.Code != "vcl-label" || diag.Severity != SeverityError {
+					t.Errorf("Unexpected diagnostic: %+v", diag)
+				}
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected %v, got %v", tt.expected, messages)
+			}
+		})
+	}
+}
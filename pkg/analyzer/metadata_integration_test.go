@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/metadata"
 	"github.com/perbu/vclparser/pkg/parser"
 )
 
@@ -90,3 +92,33 @@ sub vcl_recv {
 		})
 	}
 }
+
+func TestAnalyzerFastly(t *testing.T) {
+	src := `table hosts { "a.example.com": "b.example.com" }
+
+sub vcl_recv {
+	declare local var.host STRING;
+	set var.host = table.lookup(hosts, req.http.host, req.http.host);
+	set req.http.X-Session = req.http.Cookie:session;
+	if (std.strlen(var.host) > 0) {
+		error 801 "redirect";
+	}
+	return(hash);
+}
+
+sub vcl_error {
+	synthetic {"redirect"};
+	return(deliver);
+}
+`
+	config := parser.DefaultConfig()
+	config.Dialect = lexer.DialectFastly
+	program, err := parser.ParseWithConfig(src, "main.vcl", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errors := NewAnalyzerWithMetadata(nil, metadata.NewFastly()).Analyze(program)
+	if len(errors) != 1 || !strings.Contains(errors[0], "return action 'hash' is not allowed in method 'recv'") {
+		t.Errorf("Expected only the return action to be reported, got %v", errors)
+	}
+}
@@ -0,0 +1,296 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// statusVariables are the VCL variables holding an HTTP status code
+var statusVariables = map[string]bool{
+	"resp.status":   true,
+	"beresp.status": true,
+}
+
+// NumericRangeValidator flags integer constants that are out of range for
+// where they are used: status codes in synth() and status variables, backend
+// ports, probe windows, and integer arithmetic that overflows 64 bits (for
+// instance a TTL built from a chain of multiplications).
+type NumericRangeValidator struct {
+	diagnostics []Diagnostic
+}
+
+// NewNumericRangeValidator creates a new numeric range validator
+func NewNumericRangeValidator() *NumericRangeValidator {
+	return &NumericRangeValidator{}
+}
+
+// Validate checks all numeric constants in the program
+func (nv *NumericRangeValidator) Validate(program *ast.Program) []Diagnostic {
+	nv.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.BackendDecl:
+			nv.validateBackend(d)
+		case *ast.ProbeDecl:
+			props := make(map[string]ast.Expression, len(d.Properties))
+			for _, prop := range d.Properties {
+				props[prop.Name] = prop.Value
+			}
+			nv.validateProbe(props)
+		case *ast.SubDecl:
+			if d.Body != nil {
+				walkSubStatements(d.Body, nv.validateStatement)
+			}
+		}
+	}
+
+	return nv.diagnostics
+}
+
+func (nv *NumericRangeValidator) validateBackend(backend *ast.BackendDecl) {
+	for _, prop := range backend.Properties {
+		switch prop.Name {
+		case "port":
+			nv.validatePort(prop.Value)
+		case "probe":
+			if obj, ok := prop.Value.(*ast.ObjectExpression); ok {
+				props := map[string]ast.Expression{}
+				for _, p := range obj.Properties {
+					if name := propertyName(p.Key); name != "" {
+						props[name] = p.Value
+					}
+				}
+				nv.validateProbe(props)
+			}
+		}
+	}
+}
+
+// validatePort checks numeric ports; service names like "http" are left alone
+func (nv *NumericRangeValidator) validatePort(value ast.Expression) {
+	var port string
+	switch v := value.(type) {
+	case *ast.StringLiteral:
+		port = v.Value
+	case *ast.IntegerLiteral:
+		port = strconv.FormatInt(v.Value, 10)
+	default:
+		return
+	}
+
+	if !isAllDigits(port) {
+		return
+	}
+	if n, err := strconv.ParseInt(port, 10, 64); err != nil || n < 1 || n > 65535 {
+		nv.add(value, SeverityError, "port-range", fmt.Sprintf("port %s is out of range (1-65535)", port))
+	}
+}
+
+// validateProbe checks the numeric probe properties
+func (nv *NumericRangeValidator) validateProbe(props map[string]ast.Expression) {
+	if value, ok := props["expected_response"]; ok {
+		nv.validateStatus(value, "probe .expected_response")
+	}
+
+	window, hasWindow := integerValue(props["window"])
+	if hasWindow && (window < 1 || window > 64) {
+		nv.add(props["window"], SeverityError, "probe-range",
+			fmt.Sprintf("probe .window %d is out of range (1-64)", window))
+	}
+	if threshold, ok := integerValue(props["threshold"]); ok {
+		if threshold < 0 {
+			nv.add(props["threshold"], SeverityError, "probe-range",
+				fmt.Sprintf("probe .threshold %d must not be negative", threshold))
+		} else if hasWindow && threshold > window {
+			nv.add(props["threshold"], SeverityError, "probe-range",
+				fmt.Sprintf("probe .threshold %d is larger than .window %d, so the backend can never become healthy", threshold, window))
+		}
+	}
+}
+
+func (nv *NumericRangeValidator) validateStatement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.ReturnStatement:
+		if call, ok := ast.Unparen(s.Action).(*ast.CallExpression); ok && returnActionName(call) == "synth" {
+			if len(call.Arguments) > 0 {
+				nv.validateStatus(call.Arguments[0], "synth status")
+			}
+		}
+		nv.validateArithmetic(s.Action)
+	case *ast.ErrorStatement:
+		if s.Code != nil {
+			nv.validateStatus(s.Code, "error status")
+		}
+	case *ast.SetStatement:
+		if statusVariables[ast.VariableName(s.Variable)] {
+			nv.validateStatus(s.Value, ast.VariableName(s.Variable))
+		}
+		nv.validateArithmetic(s.Value)
+	case *ast.IfStatement:
+		nv.validateArithmetic(s.Condition)
+	case *ast.ExpressionStatement:
+		nv.validateArithmetic(s.Expression)
+	case *ast.CallStatement:
+		nv.validateArithmetic(s.Function)
+	}
+}
+
+// validateStatus checks a constant HTTP status code. Varnish accepts codes
+// above 999 in some places and then uses the code modulo 1000, so those are
+// only warned about.
+func (nv *NumericRangeValidator) validateStatus(value ast.Expression, what string) {
+	code, ok := integerValue(value)
+	if !ok {
+		return
+	}
+	switch {
+	case code < 100 || code > 65535:
+		nv.add(value, SeverityError, "status-range",
+			fmt.Sprintf("%s %d is out of range (100-999)", what, code))
+	case code > 999:
+		nv.add(value, SeverityWarning, "status-range",
+			fmt.Sprintf("%s %d is above 999; Varnish sends %d", what, code, code%1000))
+	}
+}
+
+// validateArithmetic reports constant integer sub-expressions that overflow
+// a 64-bit VCL INT
+func (nv *NumericRangeValidator) validateArithmetic(expr ast.Expression) {
+	nv.foldInteger(expr)
+}
+
+// foldInteger evaluates constant integer arithmetic, reporting the first
+// overflow it finds. ok is false if the expression is not a constant integer
+// or already overflowed.
+func (nv *NumericRangeValidator) foldInteger(expr ast.Expression) (value int64, ok bool) {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return e.Value, true
+	case *ast.ParenthesizedExpression:
+		return nv.foldInteger(e.Expression)
+	case *ast.UnaryExpression:
+		v, ok := nv.foldInteger(e.Operand)
+		if !ok {
+			return 0, false
+		}
+		switch e.Operator {
+		case "-":
+			return -v, true
+		case "+":
+			return v, true
+		}
+		return 0, false
+	case *ast.BinaryExpression:
+		left, leftOK := nv.foldInteger(e.Left)
+		right, rightOK := nv.foldInteger(e.Right)
+		if !leftOK || !rightOK {
+			return 0, false
+		}
+		result, known, overflow := integerOp(left, e.Operator, right)
+		if overflow {
+			nv.add(e, SeverityError, "integer-overflow", fmt.Sprintf(
+				"integer overflow: %d %s %d does not fit in a 64-bit INT", left, e.Operator, right))
+			return 0, false
+		}
+		return result, known
+	case *ast.CallExpression:
+		for _, arg := range e.Arguments {
+			nv.foldInteger(arg)
+		}
+		for _, arg := range e.NamedArguments {
+			nv.foldInteger(arg)
+		}
+	case *ast.RegexMatchExpression:
+		nv.foldInteger(e.Left)
+	}
+	return 0, false
+}
+
+// integerOp applies +, - or * to two integers. known is false for other
+// operators; overflow is set when the result does not fit in an int64.
+func integerOp(a int64, op string, b int64) (result int64, known, overflow bool) {
+	switch op {
+	case "+":
+		result = a + b
+		return result, true, (a > 0 && b > 0 && result < 0) || (a < 0 && b < 0 && result >= 0)
+	case "-":
+		result = a - b
+		return result, true, (a >= 0 && b < 0 && result < 0) || (a < 0 && b > 0 && result >= 0)
+	case "*":
+		if a == 0 || b == 0 {
+			return 0, true, false
+		}
+		hi, lo := bits.Mul64(absUint64(a), absUint64(b))
+		negative := (a < 0) != (b < 0)
+		if hi != 0 || lo > math.MaxInt64+1 || (lo == math.MaxInt64+1 && !negative) {
+			return 0, true, true
+		}
+		if negative {
+			return int64(-lo), true, false
+		}
+		return int64(lo), true, false
+	}
+	return 0, false, false
+}
+
+func absUint64(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+	return uint64(v)
+}
+
+// integerValue returns the value of an integer constant, allowing a sign
+func integerValue(expr ast.Expression) (int64, bool) {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return e.Value, true
+	case *ast.ParenthesizedExpression:
+		return integerValue(e.Expression)
+	case *ast.UnaryExpression:
+		v, ok := integerValue(e.Operand)
+		if !ok {
+			return 0, false
+		}
+		switch e.Operator {
+		case "-":
+			return -v, true
+		case "+":
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// propertyName returns the name of an object literal key such as .window
+func propertyName(key ast.Expression) string {
+	switch k := key.(type) {
+	case *ast.Identifier:
+		return strings.TrimPrefix(k.Name, ".")
+	case *ast.StringLiteral:
+		return k.Value
+	}
+	return ""
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (nv *NumericRangeValidator) add(node ast.Node, severity Severity, code, message string) {
+	nv.diagnostics = append(nv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
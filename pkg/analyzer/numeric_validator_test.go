@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestNumericRangeValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+		severity Severity
+	}{
+		{
+			name: "valid values",
+			vclCode: `vcl 4.1;
+				backend default { .host = "127.0.0.1"; .port = "8080"; }
+				backend named { .host = "127.0.0.1"; .port = "http"; }
+				probe healthcheck { .url = "/"; .window = 8; .threshold = 3; .expected_response = 204; }
+				sub vcl_recv { return (synth(750, "Redirect")); }
+				sub vcl_backend_response { set beresp.ttl = 3600 * 24 * 1s; }`,
+		},
+		{
+			name: "synth status too low",
+			vclCode: `vcl 4.1;
+				sub vcl_recv { return (synth(42, "Nope")); }`,
+			expected: []string{"synth status 42 is out of range (100-999)"},
+			severity: SeverityError,
+		},
+		{
+			name: "synth status above 999",
+			vclCode: `vcl 4.1;
+				sub vcl_recv { return (synth(1301, "Moved")); }`,
+			expected: []string{"Varnish sends 301"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "status variable",
+			vclCode: `vcl 4.1;
+				sub vcl_deliver { set resp.status = 99; }`,
+			expected: []string{"resp.status 99 is out of range"},
+			severity: SeverityError,
+		},
+		{
+			name: "port out of range",
+			vclCode: `vcl 4.1;
+				backend default { .host = "127.0.0.1"; .port = "80800"; }`,
+			expected: []string{"port 80800 is out of range (1-65535)"},
+			severity: SeverityError,
+		},
+		{
+			name: "port zero",
+			vclCode: `vcl 4.1;
+				backend default { .host = "127.0.0.1"; .port = "0"; }`,
+			expected: []string{"port 0 is out of range"},
+			severity: SeverityError,
+		},
+		{
+			name: "inline probe window and threshold",
+			vclCode: `vcl 4.1;
+				backend default {
+					.host = "127.0.0.1";
+					.probe = { .url = "/"; .window = 65; .threshold = 70; };
+				}`,
+			expected: []string{".window 65 is out of range (1-64)", ".threshold 70 is larger than .window 65"},
+			severity: SeverityError,
+		},
+		{
+			name: "ttl multiplication overflow",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.ttl = 86400 * 365 * 1000000000000 * 1s; }`,
+			expected: []string{"integer overflow: 31536000 * 1000000000000"},
+			severity: SeverityError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewNumericRangeValidator().Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != test.severity {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, test.severity)
+				}
+			}
+		})
+	}
+}
+
+func TestIntegerOp(t *testing.T) {
+	tests := []struct {
+		a, b     int64
+		op       string
+		result   int64
+		overflow bool
+	}{
+		{3, 4, "*", 12, false},
+		{-3, 4, "*", -12, false},
+		{1 << 62, 2, "*", 0, true},
+		{-(1 << 62), 2, "*", -(1 << 63), false},
+		{1<<63 - 1, 1, "+", 0, true},
+		{-(1 << 63), 1, "-", 0, true},
+	}
+	for _, test := range tests {
+		result, _, overflow := integerOp(test.a, test.op, test.b)
+		if overflow != test.overflow || (!overflow && result != test.result) {
+			t.Errorf("integerOp(%d %s %d) = %d, %v; want %d, %v",
+				test.a, test.op, test.b, result, overflow, test.result, test.overflow)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// ObjectLifetimeValidator checks where VMOD objects are created and used.
+// varnishd only allows new in vcl_init and in subroutines called from
+// nowhere else, and vcl_init runs its statements in order, so an object used
+// there before its new statement has not been created yet.
+type ObjectLifetimeValidator struct {
+	diagnostics []Diagnostic
+}
+
+// NewObjectLifetimeValidator creates a new object lifetime validator
+func NewObjectLifetimeValidator() *ObjectLifetimeValidator {
+	return &ObjectLifetimeValidator{}
+}
+
+// Validate checks every new statement and the uses of objects in vcl_init
+func (ov *ObjectLifetimeValidator) Validate(program *ast.Program) []Diagnostic {
+	ov.diagnostics = nil
+
+	graph := NewCallGraph(program)
+	news := make(map[string][]*ast.NewStatement)
+	objects := make(map[string]bool)
+	for _, name := range graph.Subroutines() {
+		for _, decl := range graph.Declarations(name) {
+			ast.Apply(decl, func(c *ast.Cursor) bool {
+				if stmt, ok := c.Node().(*ast.NewStatement); ok {
+					news[name] = append(news[name], stmt)
+					objects[newObjectName(stmt)] = true
+				}
+				return true
+			}, nil)
+		}
+	}
+
+	ov.checkPlacement(graph, news)
+	ov.checkOrder(graph, objects)
+
+	return ov.diagnostics
+}
+
+// checkPlacement reports new statements that run outside vcl_init
+func (ov *ObjectLifetimeValidator) checkPlacement(graph *CallGraph, news map[string][]*ast.NewStatement) {
+	reported := make(map[*ast.NewStatement]bool)
+	for _, builtin := range graph.Subroutines() {
+		if !isBuiltinSubroutine(builtin) || builtin == "vcl_init" {
+			continue
+		}
+		for _, name := range graph.ReachableFrom(builtin) {
+			for _, stmt := range news[name] {
+				if reported[stmt] {
+					continue
+				}
+				reported[stmt] = true
+				msg := fmt.Sprintf("new %s in %s: objects can only be created in vcl_init", newObjectName(stmt), name)
+				if name != builtin {
+					msg += fmt.Sprintf(", but %s is called from %s", name, builtin)
+				}
+				ov.add(stmt, "vmod-new", msg)
+			}
+		}
+	}
+}
+
+// checkOrder follows vcl_init, including the subroutines it calls, in
+// execution order and reports objects used before they are created
+func (ov *ObjectLifetimeValidator) checkOrder(graph *CallGraph, objects map[string]bool) {
+	created := make(map[string]bool)
+	reported := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	use := func(node ast.Node, name string) {
+		if objects[name] && !created[name] && !reported[name] {
+			reported[name] = true
+			ov.add(node, "vmod-object-order", fmt.Sprintf("object %s is used before it is created in vcl_init", name))
+		}
+	}
+
+	var walk func(sub string)
+	walk = func(sub string) {
+		if visiting[sub] {
+			return
+		}
+		visiting[sub] = true
+		defer delete(visiting, sub)
+
+		for _, decl := range graph.Declarations(sub) {
+			ast.Apply(decl.Body, func(c *ast.Cursor) bool {
+				switch n := c.Node().(type) {
+				case *ast.NewStatement:
+					// The constructor runs before the object exists
+					ast.Apply(n.Constructor, func(c *ast.Cursor) bool {
+						return ov.visitUse(c.Node(), use)
+					}, nil)
+					created[newObjectName(n)] = true
+					return false
+				case *ast.CallStatement:
+					if name := calledSubName(n); name != "" {
+						walk(name)
+					}
+					return false
+				}
+				return ov.visitUse(c.Node(), use)
+			}, nil)
+		}
+	}
+	walk("vcl_init")
+}
+
+// visitUse calls use for an identifier that may name an object, either on
+// its own or as the root of a member expression such as obj.method. It
+// returns whether the children of node still need to be visited.
+func (ov *ObjectLifetimeValidator) visitUse(node ast.Node, use func(ast.Node, string)) bool {
+	switch n := node.(type) {
+	case *ast.MemberExpression:
+		root := ast.Expression(n)
+		for {
+			member, ok := root.(*ast.MemberExpression)
+			if !ok {
+				break
+			}
+			root = member.Object
+		}
+		if ident, ok := root.(*ast.Identifier); ok {
+			use(n, ident.Name)
+		}
+		return false
+	case *ast.Identifier:
+		use(n, n.Name)
+	}
+	return true
+}
+
+func (ov *ObjectLifetimeValidator) add(node ast.Node, code, message string) {
+	ov.diagnostics = append(ov.diagnostics, NewDiagnostic(node, SeverityError, code, message))
+}
+
+// newObjectName returns the name of the object a new statement creates
+func newObjectName(stmt *ast.NewStatement) string {
+	if ident, ok := stmt.Name.(*ast.Identifier); ok {
+		return ident.Name
+	}
+	return ""
+}
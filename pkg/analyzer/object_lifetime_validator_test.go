@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestObjectLifetimeValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+		codes    []string
+	}{
+		{
+			name: "objects created before use",
+			vclCode: `vcl 4.1;
+				import directors;
+				backend a { .host = "127.0.0.1"; }
+				sub setup {
+					vdir.add_backend(a);
+				}
+				sub vcl_init {
+					new vdir = directors.round_robin();
+					call setup;
+				}
+				sub vcl_recv {
+					set req.backend_hint = vdir.backend();
+				}`,
+		},
+		{
+			name: "new outside vcl_init",
+			vclCode: `vcl 4.1;
+				import directors;
+				sub vcl_recv {
+					new vdir = directors.round_robin();
+				}`,
+			expected: []string{"new vdir in vcl_recv: objects can only be created in vcl_init"},
+			codes:    []string{"vmod-new"},
+		},
+		{
+			name: "new in a subroutine called outside vcl_init",
+			vclCode: `vcl 4.1;
+				import directors;
+				sub make {
+					new vdir = directors.round_robin();
+				}
+				sub vcl_init {
+					call make;
+				}
+				sub vcl_recv {
+					call make;
+				}`,
+			expected: []string{"new vdir in make: objects can only be created in vcl_init, but make is called from vcl_recv"},
+			codes:    []string{"vmod-new"},
+		},
+		{
+			name: "object used before it is created",
+			vclCode: `vcl 4.1;
+				import directors;
+				backend a { .host = "127.0.0.1"; }
+				sub setup {
+					vdir.add_backend(a);
+				}
+				sub vcl_init {
+					call setup;
+					new vdir = directors.round_robin();
+					new other = directors.fallback(other.backend());
+				}`,
+			expected: []string{
+				"object vdir is used before it is created in vcl_init",
+				"object other is used before it is created in vcl_init",
+			},
+			codes: []string{"vmod-object-order", "vmod-object-order"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewObjectLifetimeValidator().Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.codes[i] {
+					t.Errorf("diagnostic %d code = %s, want %s", i, diags[i].Code, test.codes[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// pass is one analysis pass. It returns its findings as diagnostics and,
+// for the passes behind the []string APIs, as messages.
+type pass func() ([]Diagnostic, []string)
+
+// subroutineValidator is implemented by the validators that check each
+// subroutine on its own, without following calls. validateSubroutine must be
+// safe to call for several subroutines at the same time.
+type subroutineValidator interface {
+	validateSubroutine(sub *ast.SubDecl) []Diagnostic
+}
+
+// SetParallelism sets how many analysis passes may run at the same time.
+// With n above 1, passes that do not depend on each other, such as return
+// action, variable access and lint checks, run concurrently, which speeds up
+// the analysis of large configurations. The passes that check each
+// subroutine on its own, such as the condition, set type and hash_data()
+// checks, are split into one pass per subroutine, so a configuration with
+// many subroutines keeps all workers busy. The passes that follow calls
+// between subroutines still see the whole program. The findings are the
+// same, and in the same order, as when the passes run one after the other,
+// which is the default.
+func (a *Analyzer) SetParallelism(n int) {
+	a.parallelism = n
+}
+
+// runPasses runs passes and concatenates their findings in the order of the
+// passes, regardless of the order in which they finish. Once the context of
+// the analysis is done, the passes not started yet are skipped.
+func (a *Analyzer) runPasses(passes []pass) ([]Diagnostic, []string) {
+	diags := make([][]Diagnostic, len(passes))
+	messages := make([][]string, len(passes))
+	if a.parallelism <= 1 {
+		for i, run := range passes {
+			if a.cancelled() {
+				break
+			}
+			diags[i], messages[i] = run()
+		}
+	} else {
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, a.parallelism)
+		for i, run := range passes {
+			slots <- struct{}{}
+			if a.cancelled() {
+				<-slots
+				break
+			}
+			wg.Add(1)
+			go func(i int, run pass) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				diags[i], messages[i] = run()
+			}(i, run)
+		}
+		wg.Wait()
+	}
+
+	var allDiags []Diagnostic
+	var allMessages []string
+	for i := range passes {
+		allDiags = append(allDiags, diags[i]...)
+		allMessages = append(allMessages, messages[i]...)
+	}
+	return allDiags, allMessages
+}
+
+// subroutinePasses returns one pass per subroutine of program for v, in the
+// order of the declarations, so their findings concatenate to those of the
+// whole program
+func subroutinePasses(program *ast.Program, v subroutineValidator) []pass {
+	var passes []pass
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			sub := sub
+			passes = append(passes, diagnosticPass(func() []Diagnostic {
+				return v.validateSubroutine(sub)
+			}))
+		}
+	}
+	return passes
+}
+
+// diagnosticPass wraps a validator that reports diagnostics only
+func diagnosticPass(validate func() []Diagnostic) pass {
+	return func() ([]Diagnostic, []string) {
+		return validate(), nil
+	}
+}
+
+// cancelled reports whether the context of the analysis is done
+func (a *Analyzer) cancelled() bool {
+	return a.ctx != nil && a.ctx.Err() != nil
+}
+
+// withContext runs fn with ctx as the context of the analysis and returns
+// ctx.Err() afterwards
+func (a *Analyzer) withContext(ctx context.Context, fn func()) error {
+	a.ctx = ctx
+	defer func() { a.ctx = nil }()
+	fn()
+	return ctx.Err()
+}
+
+// RunRulesContext is RunRules that skips the rules not started yet once ctx
+// is done, returning the findings so far and ctx.Err()
+func (a *Analyzer) RunRulesContext(ctx context.Context, program *ast.Program, rules []Rule) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	err := a.withContext(ctx, func() {
+		diags = a.RunRules(program, rules)
+	})
+	return diags, err
+}
+
+// RunRules runs rules over program, as many at a time as set with
+// SetParallelism, and returns their findings in the order of the rules.
+// Findings without a code get the ID of the rule that produced them.
+func (a *Analyzer) RunRules(program *ast.Program, rules []Rule) []Diagnostic {
+	ctx := a.Context()
+	passes := make([]pass, len(rules))
+	for i, rule := range rules {
+		rule := rule
+		passes[i] = diagnosticPass(func() []Diagnostic {
+			diags := rule.Check(program, ctx)
+			for j := range diags {
+				if diags[j].Code == "" {
+					diags[j].Code = rule.ID()
+				}
+			}
+			return diags
+		})
+	}
+	diags, _ := a.runPasses(passes)
+	return diags
+}
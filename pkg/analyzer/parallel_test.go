@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/perbu/vclparser/internal/benchdata"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// tenantProgram parses a generated configuration of the given number of
+// tenants from the tenant template of the benchmarks
+func tenantProgram(tb testing.TB, tenants int) *ast.Program {
+	// Some findings, so there is an order to keep
+	src := benchdata.Tenants(tenants) +
+		"sub vcl_deliver {\n\tset beresp.ttl = 1s;\n\treturn (lookup);\n}\n" +
+		"backend unused { .host = \"192.0.2.1\"; }\n"
+
+	program, err := parser.Parse(src, "tenants.vcl")
+	if err != nil {
+		tb.Fatalf("Failed to parse VCL: %v", err)
+	}
+	return program
+}
+
+func TestParallelAnalysisMatchesSequential(t *testing.T) {
+	program := tenantProgram(t, 5)
+	registry := vmod.NewRegistry()
+
+	sequential := NewAnalyzer(registry)
+	want := sequential.AnalyzeDiagnostics(program)
+	wantErrors := NewAnalyzer(registry).Analyze(program)
+	if len(want) == 0 || len(wantErrors) == 0 {
+		t.Fatal("Expected findings from the test program")
+	}
+
+	for i := 0; i < 5; i++ {
+		parallel := NewAnalyzer(registry)
+		parallel.SetParallelism(8)
+		if got := parallel.AnalyzeDiagnostics(program); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Parallel diagnostics differ:\ngot  %v\nwant %v", got, want)
+		}
+
+		parallel = NewAnalyzer(registry)
+		parallel.SetParallelism(8)
+		if got := parallel.Analyze(program); !reflect.DeepEqual(got, wantErrors) {
+			t.Fatalf("Parallel errors differ:\ngot  %v\nwant %v", got, wantErrors)
+		}
+	}
+}
+
+func BenchmarkAnalyzeDiagnostics(b *testing.B) {
+	program := tenantProgram(b, 100)
+	registry := vmod.NewRegistry()
+
+	for _, parallelism := range []int{1, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				a := NewAnalyzer(registry)
+				a.SetParallelism(parallelism)
+				a.AnalyzeDiagnostics(program)
+			}
+		})
+	}
+}
+
+func TestParallelSubroutinePasses(t *testing.T) {
+	src := `vcl 4.1;
+
+sub vcl_recv {
+	if (req.url == "/a") { return (pass); }
+	elsif (req.url == "/a") { return (hash); }
+	hash_data(req.url);
+}
+
+sub vcl_deliver {
+	if ("a" == "b") { set resp.status = "ok"; }
+}
+
+sub helper {
+	if (req.method == "GET" || req.method != "GET") { set req.http.x = 1; }
+}
+`
+	program, err := parser.Parse(src, "subs.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	a := NewAnalyzer(vmod.NewRegistry())
+	for _, v := range []subroutineValidator{a.conditionValidator, a.setTypeValidator, a.hashValidator} {
+		var got []Diagnostic
+		for _, run := range subroutinePasses(program, v) {
+			diags, _ := run()
+			got = append(got, diags...)
+		}
+		want := v.(interface {
+			Validate(*ast.Program) []Diagnostic
+		}).Validate(program)
+		if len(want) == 0 {
+			t.Errorf("%T: expected findings from the test program", v)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%T: per-subroutine findings differ:\ngot  %v\nwant %v", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Pass is a custom validation pass, such as a company's naming conventions
+// or mandatory security headers. Registered passes run inside Analyze and
+// AnalyzeDiagnostics next to the built-in checks, and may run concurrently
+// with them, so Run must only read the program and the context.
+type Pass interface {
+	// Name identifies the pass, e.g. "acme/security-headers". Findings
+	// without a code are given the name as code.
+	Name() string
+	// Run inspects the program and returns its findings
+	Run(program *ast.Program, ctx *Context) []Diagnostic
+}
+
+var (
+	passesMu     sync.RWMutex
+	customPasses []Pass
+)
+
+// RegisterPass adds a pass to the analysis of every Analyzer. Passes run in
+// the order they were registered. Registering two passes with the same name
+// is an error.
+func RegisterPass(p Pass) error {
+	passesMu.Lock()
+	defer passesMu.Unlock()
+
+	name := p.Name()
+	if name == "" {
+		return fmt.Errorf("pass has no name")
+	}
+	for _, existing := range customPasses {
+		if existing.Name() == name {
+			return fmt.Errorf("pass '%s' is already registered", name)
+		}
+	}
+	customPasses = append(customPasses, p)
+	return nil
+}
+
+// UnregisterPass removes a previously registered pass
+func UnregisterPass(name string) {
+	passesMu.Lock()
+	defer passesMu.Unlock()
+	for i, p := range customPasses {
+		if p.Name() == name {
+			customPasses = append(customPasses[:i:i], customPasses[i+1:]...)
+			return
+		}
+	}
+}
+
+// Passes returns the registered passes in the order they run
+func Passes() []Pass {
+	passesMu.RLock()
+	defer passesMu.RUnlock()
+	return append([]Pass(nil), customPasses...)
+}
+
+// registeredPasses wraps the registered passes for runPasses
+func (a *Analyzer) registeredPasses(program *ast.Program) []pass {
+	ctx := a.Context()
+	var result []pass
+	for _, p := range Passes() {
+		p := p
+		result = append(result, diagnosticPass(func() []Diagnostic {
+			diags := p.Run(program, ctx)
+			for i := range diags {
+				if diags[i].Code == "" {
+					diags[i].Code = p.Name()
+				}
+			}
+			return diags
+		}))
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// securityHeaderPass requires vcl_deliver to set Strict-Transport-Security
+type securityHeaderPass struct{}
+
+func (securityHeaderPass) Name() string { return "test/security-headers" }
+
+func (securityHeaderPass) Run(program *ast.Program, ctx *Context) []Diagnostic {
+	for _, ref := range ReferencedHeaders(program) {
+		if ref.Variable == "resp.http.Strict-Transport-Security" && ref.Access == HeaderWrite {
+			return nil
+		}
+	}
+	return []Diagnostic{{Severity: SeverityError, Message: "Strict-Transport-Security is never set"}}
+}
+
+func TestRegisterPass(t *testing.T) {
+	if err := RegisterPass(securityHeaderPass{}); err != nil {
+		t.Fatalf("RegisterPass: %v", err)
+	}
+	defer UnregisterPass("test/security-headers")
+
+	if err := RegisterPass(securityHeaderPass{}); err == nil {
+		t.Error("Expected an error registering a pass twice")
+	}
+	if passes := Passes(); len(passes) != 1 || passes[0].Name() != "test/security-headers" {
+		t.Errorf("Expected the registered pass, got %v", passes)
+	}
+
+	program := parseVCL(t, "vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\nsub vcl_deliver { }\n")
+	var found bool
+	for _, d := range NewAnalyzer(nil).AnalyzeDiagnostics(program) {
+		if d.Code == "test/security-headers" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a finding coded with the pass name")
+	}
+	if errs := NewAnalyzer(nil).Analyze(program); len(errs) != 1 {
+		t.Errorf("Expected the pass error from Analyze, got %v", errs)
+	}
+
+	program = parseVCL(t, "vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\n"+
+		"sub vcl_deliver { set resp.http.Strict-Transport-Security = \"max-age=31536000\"; }\n")
+	if errs := NewAnalyzer(nil).Analyze(program); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	UnregisterPass("test/security-headers")
+	if len(Passes()) != 0 {
+		t.Error("Expected no passes after unregistering")
+	}
+}
@@ -0,0 +1,250 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+)
+
+// pipeNoEffectVariables are client variables that remain writable in vcl_pipe
+// but only influence caching, which never happens for a piped request
+var pipeNoEffectVariables = map[string]bool{
+	"req.ttl":              true,
+	"req.grace":            true,
+	"req.hash_always_miss": true,
+	"req.hash_ignore_busy": true,
+	"req.hash_ignore_vary": true,
+	"req.esi":              true,
+	"req.storage":          true,
+}
+
+// PipeValidator checks semantics specific to vcl_pipe: which bereq fields may
+// be set there, how the Connection header is handled, including for the
+// WebSocket upgrades other subroutines pipe, and cache-related settings that
+// have no effect once a request is piped.
+type PipeValidator struct {
+	loader      *metadata.MetadataLoader
+	diagnostics []Diagnostic
+}
+
+// NewPipeValidator creates a new vcl_pipe validator
+func NewPipeValidator(loader *metadata.MetadataLoader) *PipeValidator {
+	return &PipeValidator{
+		loader: loader,
+	}
+}
+
+// Validate checks vcl_pipe in the program
+func (pv *PipeValidator) Validate(program *ast.Program) []Diagnostic {
+	pv.diagnostics = nil
+
+	passesUpgrade := false
+	var upgradePipes []*ast.ReturnStatement
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub.Body == nil {
+			continue
+		}
+		if sub.Name == "vcl_pipe" {
+			passesUpgrade = pv.validatePipeSub(sub)
+		} else {
+			upgradePipes = append(upgradePipes, pipesOnUpgrade(sub.Body, false)...)
+		}
+	}
+
+	// Varnish sends piped requests with Connection: close, so a WebSocket
+	// handshake only reaches the backend if vcl_pipe passes it along
+	if !passesUpgrade {
+		for _, ret := range upgradePipes {
+			pv.add(ret, SeverityWarning, "pipe-websocket",
+				"return (pipe) for an Upgrade request needs vcl_pipe to copy req.http.Upgrade and req.http.Connection to bereq; "+
+					"otherwise Varnish sends Connection: close and the WebSocket handshake fails")
+		}
+	}
+
+	return pv.diagnostics
+}
+
+// pipesOnUpgrade returns the return (pipe) statements in stmt that are
+// taken for requests with an Upgrade header, as upgrade tells for stmt
+// itself
+func pipesOnUpgrade(stmt ast.Statement, upgrade bool) []*ast.ReturnStatement {
+	var pipes []*ast.ReturnStatement
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		for _, inner := range s.Statements {
+			pipes = append(pipes, pipesOnUpgrade(inner, upgrade)...)
+		}
+	case *ast.IfStatement:
+		pipes = append(pipes, pipesOnUpgrade(s.Then, upgrade || readsUpgrade(s.Condition))...)
+		if s.Else != nil {
+			pipes = append(pipes, pipesOnUpgrade(s.Else, upgrade)...)
+		}
+	case *ast.ReturnStatement:
+		if upgrade && returnActionName(s.Action) == "pipe" {
+			pipes = append(pipes, s)
+		}
+	}
+	return pipes
+}
+
+// readsUpgrade reports whether a condition looks at the Upgrade header of
+// the request
+func readsUpgrade(condition ast.Expression) bool {
+	found := false
+	walkNodes(condition, func(n ast.Node) {
+		if member, ok := n.(*ast.MemberExpression); ok && strings.EqualFold(ast.VariableName(member), "req.http.Upgrade") {
+			found = true
+		}
+	})
+	return found
+}
+
+// validatePipeSub checks the assignments made inside vcl_pipe. It returns
+// whether the Upgrade and Connection headers of the client are passed to
+// the backend.
+func (pv *PipeValidator) validatePipeSub(sub *ast.SubDecl) bool {
+	var upgradeCopies []*ast.SetStatement
+	var connectionCopies []*ast.SetStatement
+
+	walkSubStatements(sub.Body, func(stmt ast.Statement) {
+		switch s := stmt.(type) {
+		case *ast.SetStatement:
+			name := ast.VariableName(s.Variable)
+			lower := strings.ToLower(name)
+			switch {
+			case lower == "bereq.http.upgrade":
+				upgradeCopies = append(upgradeCopies, s)
+			case lower == "bereq.http.connection":
+				if isStringValue(s.Value, "close") {
+					break
+				}
+				if strings.ToLower(ast.VariableName(s.Value)) == "req.http.connection" {
+					connectionCopies = append(connectionCopies, s)
+					break
+				}
+				pv.add(s, SeverityWarning, "pipe-connection",
+					"setting bereq.http.Connection to anything but \"close\" in vcl_pipe lets later requests on the connection bypass Varnish")
+			case pipeNoEffectVariables[name]:
+				pv.add(s, SeverityWarning, "pipe-no-effect",
+					fmt.Sprintf("setting %s in vcl_pipe has no effect: piped requests are never cached", name))
+			case strings.HasPrefix(name, "bereq."):
+				pv.checkBereqWritable(s, name)
+			}
+		case *ast.UnsetStatement:
+			if strings.ToLower(ast.VariableName(s.Variable)) == "bereq.http.connection" {
+				pv.add(s, SeverityWarning, "pipe-connection",
+					"unsetting bereq.http.Connection in vcl_pipe removes \"Connection: close\", letting later requests on the connection bypass Varnish")
+			}
+		}
+	})
+
+	// Copying the client's Connection header is the documented WebSocket
+	// pattern, but only when Upgrade is passed along as well
+	if len(upgradeCopies) == 0 {
+		for _, s := range connectionCopies {
+			pv.add(s, SeverityWarning, "pipe-connection",
+				"bereq.http.Connection is copied from the client without bereq.http.Upgrade; only do this for WebSocket upgrades")
+		}
+	}
+	if len(connectionCopies) == 0 {
+		for _, s := range upgradeCopies {
+			pv.add(s, SeverityWarning, "pipe-connection",
+				"bereq.http.Upgrade is set without copying bereq.http.Connection, which Varnish sets to close; the backend will not upgrade the connection")
+		}
+	}
+	return len(upgradeCopies) > 0 && len(connectionCopies) > 0
+}
+
+// checkBereqWritable adds a note listing the bereq fields that can be set in
+// vcl_pipe when the target is not one of them. The variable access validator
+// reports the error itself.
+func (pv *PipeValidator) checkBereqWritable(stmt *ast.SetStatement, name string) {
+	if pv.loader.ValidateVariableAccess(name, "pipe", "write") == nil {
+		return
+	}
+	settable := pv.pipeWritableBereq()
+	if len(settable) == 0 {
+		return
+	}
+	pv.add(stmt, SeverityInfo, "pipe-readonly",
+		fmt.Sprintf("%s cannot be set in vcl_pipe; settable bereq fields there are: %s",
+			name, strings.Join(settable, ", ")))
+}
+
+// pipeWritableBereq returns the bereq variables writable in vcl_pipe
+// according to the metadata
+func (pv *PipeValidator) pipeWritableBereq() []string {
+	variables, err := pv.loader.GetVariables()
+	if err != nil {
+		return nil
+	}
+	methods, err := pv.loader.GetMethods()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for name, variable := range variables {
+		if strings.HasPrefix(name, "bereq.") && variable.IsWritableInMethod("pipe", methods) {
+			if strings.HasSuffix(name, ".") {
+				name += "*"
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (pv *PipeValidator) add(node ast.Node, severity Severity, code, message string) {
+	pv.diagnostics = append(pv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
+
+// walkSubStatements calls fn for every statement in a block, descending into
+// if/else branches and nested blocks
+func walkSubStatements(stmt ast.Statement, fn func(ast.Statement)) {
+	if stmt == nil {
+		return
+	}
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		if s == nil {
+			return
+		}
+		for _, inner := range s.Statements {
+			walkSubStatements(inner, fn)
+		}
+		return
+	case *ast.IfStatement:
+		walkSubStatements(s.Then, fn)
+		walkSubStatements(s.Else, fn)
+	}
+	fn(stmt)
+}
+
+// returnActionName extracts the action from return (action) or
+// return (action(args))
+func returnActionName(expr ast.Expression) string {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return e.Name
+	case *ast.CallExpression:
+		if ident, ok := e.Function.(*ast.Identifier); ok {
+			return ident.Name
+		}
+	case *ast.ParenthesizedExpression:
+		return returnActionName(e.Expression)
+	}
+	return ""
+}
+
+// isStringValue reports whether expr is a string literal equal to value,
+// ignoring case
+func isStringValue(expr ast.Expression, value string) bool {
+	lit, ok := expr.(*ast.StringLiteral)
+	return ok && strings.EqualFold(lit.Value, value)
+}
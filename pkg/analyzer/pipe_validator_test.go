@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestPipeValidator(t *testing.T) {
+	loader := metadata.New()
+
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string // message fragments, in order
+		severity Severity
+	}{
+		{
+			name: "connection close is fine",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					set bereq.http.Connection = "close";
+					set bereq.url = "/piped";
+					return (pipe);
+				}`,
+		},
+		{
+			name: "websocket upgrade pattern",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					if (req.http.upgrade) {
+						set bereq.http.upgrade = req.http.upgrade;
+						set bereq.http.connection = req.http.connection;
+					}
+				}`,
+		},
+		{
+			name: "keep-alive connection",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					set bereq.http.Connection = "keep-alive";
+				}`,
+			expected: []string{"to anything but \"close\""},
+			severity: SeverityWarning,
+		},
+		{
+			name: "unset connection",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					unset bereq.http.connection;
+				}`,
+			expected: []string{"unsetting bereq.http.Connection"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "connection copied without upgrade",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					set bereq.http.connection = req.http.connection;
+				}`,
+			expected: []string{"without bereq.http.Upgrade"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "upgrade copied without connection",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					set bereq.http.upgrade = req.http.upgrade;
+				}`,
+			expected: []string{"bereq.http.Upgrade is set without copying bereq.http.Connection"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "websocket piped without vcl_pipe",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.http.Upgrade ~ "(?i)websocket") {
+						return (pipe);
+					}
+					if (req.method == "PRI") {
+						return (pipe);
+					}
+				}`,
+			expected: []string{"return (pipe) for an Upgrade request needs vcl_pipe to copy"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "websocket piped with upgrade pattern",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.http.upgrade) {
+						return (pipe);
+					}
+				}
+				sub vcl_pipe {
+					if (req.http.upgrade) {
+						set bereq.http.upgrade = req.http.upgrade;
+						set bereq.http.connection = req.http.connection;
+					}
+				}`,
+		},
+		{
+			name: "cache logic in pipe",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					set req.hash_always_miss = true;
+				}`,
+			expected: []string{"req.hash_always_miss in vcl_pipe has no effect"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "bereq field not settable in pipe",
+			vclCode: `vcl 4.1;
+				sub vcl_pipe {
+					set bereq.first_byte_timeout = 10s;
+				}`,
+			expected: []string{"settable bereq fields there are: bereq.backend, bereq.connect_timeout, bereq.http.*"},
+			severity: SeverityInfo,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewPipeValidator(loader).Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != test.severity {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, test.severity)
+				}
+				if diags[i].Position.Line == 0 {
+					t.Errorf("diagnostic %d has no position", i)
+				}
+			}
+		})
+	}
+}
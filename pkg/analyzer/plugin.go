@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// Context gives rules read access to the state built up during analysis
+type Context struct {
+	SymbolTable *types.SymbolTable
+	Registry    *vmod.Registry
+	Metadata    *metadata.MetadataLoader
+}
+
+// Rule is a single check contributed by a plugin
+type Rule interface {
+	// ID uniquely identifies the rule, e.g. "acme/no-debug-headers"
+	ID() string
+	// Description is a one-line summary shown in rule listings
+	Description() string
+	// Check inspects the program and returns its findings
+	Check(program *ast.Program, ctx *Context) []Diagnostic
+}
+
+// Plugin bundles a set of rules, typically distributed by a third party.
+// Plugins register themselves with RegisterPlugin, usually from an init
+// function, and are then run by every Analyzer.
+type Plugin interface {
+	// Name identifies the plugin and selects its configuration section
+	Name() string
+	// Rules returns the checks provided by the plugin
+	Rules() []Rule
+	// Configure applies user settings. It is called with nil when the
+	// configuration has no section for the plugin.
+	Configure(settings map[string]interface{}) error
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   = map[string]Plugin{}
+)
+
+// RegisterPlugin makes a plugin available to all analyzers. Registering two
+// plugins with the same name is an error.
+func RegisterPlugin(p Plugin) error {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	name := p.Name()
+	if name == "" {
+		return fmt.Errorf("plugin has no name")
+	}
+	if _, exists := plugins[name]; exists {
+		return fmt.Errorf("plugin '%s' is already registered", name)
+	}
+	plugins[name] = p
+	return nil
+}
+
+// UnregisterPlugin removes a previously registered plugin
+func UnregisterPlugin(name string) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	delete(plugins, name)
+}
+
+// Plugins returns the registered plugins sorted by name
+func Plugins() []Plugin {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+
+	result := make([]Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+// ConfigurePlugins passes each registered plugin its section of settings,
+// keyed by plugin name
+func ConfigurePlugins(settings map[string]map[string]interface{}) error {
+	for _, p := range Plugins() {
+		if err := p.Configure(settings[p.Name()]); err != nil {
+			return fmt.Errorf("configuring plugin '%s': %w", p.Name(), err)
+		}
+	}
+	for name := range settings {
+		if !isPluginRegistered(name) {
+			return fmt.Errorf("configuration for unknown plugin '%s'", name)
+		}
+	}
+	return nil
+}
+
+func isPluginRegistered(name string) bool {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	_, ok := plugins[name]
+	return ok
+}
+
+// Context returns the state rules are run with. The symbol table is
+// populated by the last analysis.
+func (a *Analyzer) Context() *Context {
+	return &Context{
+		SymbolTable: a.symbolTable,
+		Registry:    a.registry,
+		Metadata:    a.metadataLoader,
+	}
+}
+
+// runPlugins runs every rule of every registered plugin
+func (a *Analyzer) runPlugins(program *ast.Program) []Diagnostic {
+	var rules []Rule
+	for _, p := range Plugins() {
+		rules = append(rules, p.Rules()...)
+	}
+	return a.RunRules(program, rules)
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// subNameRule flags subroutines whose name matches a configured value
+type subNameRule struct {
+	plugin *testPlugin
+}
+
+func (r *subNameRule) ID() string          { return "test/forbidden-sub" }
+func (r *subNameRule) Description() string { return "flags forbidden subroutine names" }
+
+func (r *subNameRule) Check(program *ast.Program, ctx *Context) []Diagnostic {
+	if ctx.Metadata == nil || ctx.SymbolTable == nil {
+		return []Diagnostic{{Severity: SeverityError, Message: "context not populated"}}
+	}
+	var diags []Diagnostic
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Name == r.plugin.forbidden {
+			diags = append(diags, Diagnostic{
+				Position: sub.Start(),
+				Severity: r.plugin.severity,
+				Message:  fmt.Sprintf("subroutine '%s' is forbidden", sub.Name),
+			})
+		}
+	}
+	return diags
+}
+
+type testPlugin struct {
+	forbidden string
+	severity  Severity
+}
+
+func (p *testPlugin) Name() string  { return "test" }
+func (p *testPlugin) Rules() []Rule { return []Rule{&subNameRule{plugin: p}} }
+
+func (p *testPlugin) Configure(settings map[string]interface{}) error {
+	if settings == nil {
+		return nil
+	}
+	name, ok := settings["forbidden"].(string)
+	if !ok {
+		return fmt.Errorf("forbidden must be a string")
+	}
+	p.forbidden = name
+	return nil
+}
+
+func registerTestPlugin(t *testing.T, p Plugin) {
+	t.Helper()
+	if err := RegisterPlugin(p); err != nil {
+		t.Fatalf("RegisterPlugin failed: %v", err)
+	}
+	t.Cleanup(func() { UnregisterPlugin(p.Name()) })
+}
+
+func TestPluginRulesRun(t *testing.T) {
+	plugin := &testPlugin{forbidden: "debug", severity: SeverityError}
+	registerTestPlugin(t, plugin)
+
+	vclCode := `vcl 4.1;
+
+sub debug {
+}
+
+sub vcl_recv {
+	call debug;
+	return (hash);
+}
+`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	errors := NewAnalyzer(nil).Analyze(program)
+	if len(errors) != 1 || errors[0] != "at line 3: subroutine 'debug' is forbidden" {
+		t.Errorf("unexpected errors: %v", errors)
+	}
+
+	plugin.severity = SeverityWarning
+	if errors := NewAnalyzer(nil).Analyze(program); len(errors) != 0 {
+		t.Errorf("warnings should not be reported by Analyze: %v", errors)
+	}
+	diags := NewAnalyzer(nil).AnalyzeDiagnostics(program)
+	if len(diags) != 1 || diags[0].Severity != SeverityWarning {
+		t.Errorf("expected one warning diagnostic, got %v", diags)
+	}
+}
+
+func TestRegisterPluginDuplicate(t *testing.T) {
+	registerTestPlugin(t, &testPlugin{})
+	if err := RegisterPlugin(&testPlugin{}); err == nil {
+		t.Error("expected error registering duplicate plugin")
+	}
+}
+
+func TestConfigurePlugins(t *testing.T) {
+	plugin := &testPlugin{forbidden: "debug"}
+	registerTestPlugin(t, plugin)
+
+	err := ConfigurePlugins(map[string]map[string]interface{}{
+		"test": {"forbidden": "legacy"},
+	})
+	if err != nil {
+		t.Fatalf("ConfigurePlugins failed: %v", err)
+	}
+	if plugin.forbidden != "legacy" {
+		t.Errorf("plugin not configured, forbidden = %q", plugin.forbidden)
+	}
+
+	if err := ConfigurePlugins(map[string]map[string]interface{}{"test": {"forbidden": 1}}); err == nil {
+		t.Error("expected error from plugin Configure")
+	}
+	if err := ConfigurePlugins(map[string]map[string]interface{}{"other": {}}); err == nil {
+		t.Error("expected error for unknown plugin section")
+	}
+}
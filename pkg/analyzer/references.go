@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+// recordReferences adds the uses of the backends, ACLs, probes, custom
+// subroutines, VMODs and VMOD objects of program to their symbols, which
+// must have been defined, as the VMODValidator does. Later passes and
+// callers look them up instead of walking the tree again.
+func recordReferences(program *ast.Program, st *types.SymbolTable) {
+	for _, decl := range program.Declarations {
+		if decl == nil {
+			continue
+		}
+		sub := ""
+		if s, ok := decl.(*ast.SubDecl); ok {
+			sub = s.Name
+		}
+		ast.Apply(decl, func(c *ast.Cursor) bool {
+			switch n := c.Node().(type) {
+			case *ast.ReturnStatement:
+				// Return actions are not symbols
+				return false
+			case *ast.Identifier:
+				if _, ok := c.Parent().(*ast.NewStatement); ok && c.Name() == "Name" {
+					// The name of a new statement declares the object
+					return true
+				}
+				symbol := st.Lookup(n.Name)
+				if symbol == nil || !isReference(c, symbol.Kind) {
+					return true
+				}
+				switch symbol.Kind {
+				case types.SymbolBackend, types.SymbolACL, types.SymbolProbe, types.SymbolSubroutine,
+					types.SymbolModule, types.SymbolVMODObject:
+					symbol.References = append(symbol.References, types.Reference{
+						Node:     n,
+						Position: n.StartPos,
+						Sub:      sub,
+					})
+				}
+			}
+			return true
+		}, nil)
+	}
+}
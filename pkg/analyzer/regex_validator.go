@@ -0,0 +1,325 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// banCondition matches one condition of a ban expression, such as
+// req.url ~ "^/images/"
+var banCondition = regexp.MustCompile(`^\s*(\S+?)\s*(!~|~)\s*(.*?)\s*$`)
+
+// RegexValidator compiles the regular expressions VCC compiles: string
+// literals matched with ~ and !~, the patterns of regsub() and regsuball(),
+// the conditions of ban() and VMOD string parameters named like regexes.
+// Varnish uses PCRE, so patterns are checked with Go's RE2 parser after
+// rewriting the PCRE-only constructs, like lookarounds and backreferences,
+// into something RE2 accepts. Patterns that nest unbounded quantifiers and
+// may backtrack catastrophically are reported once enabled with
+// SetBacktracking.
+//
+// It relies on the symbol table populated by the VMOD validator, so it must
+// run after it.
+type RegexValidator struct {
+	typeResolver
+	reportBacktracking   bool
+	backtrackingSeverity Severity
+	diagnostics          []Diagnostic
+}
+
+// NewRegexValidator creates a new regular expression validator
+func NewRegexValidator(loader *metadata.MetadataLoader, symbolTable *types.SymbolTable, registry *vmod.Registry) *RegexValidator {
+	return &RegexValidator{
+		typeResolver: typeResolver{
+			loader:      loader,
+			symbolTable: symbolTable,
+			registry:    registry,
+		},
+		backtrackingSeverity: SeverityWarning,
+	}
+}
+
+// SetBacktracking sets whether patterns prone to catastrophic backtracking
+// are reported, and with which severity
+func (rv *RegexValidator) SetBacktracking(report bool, severity Severity) {
+	rv.reportBacktracking = report
+	rv.backtrackingSeverity = severity
+}
+
+// Validate checks every regular expression literal in the program
+func (rv *RegexValidator) Validate(program *ast.Program) []Diagnostic {
+	rv.diagnostics = nil
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		switch e := c.Node().(type) {
+		case *ast.RegexMatchExpression:
+			if lit, ok := e.Right.(*ast.StringLiteral); ok {
+				rv.checkPattern(lit, lit.Value)
+			}
+		case *ast.CallExpression:
+			rv.checkCall(e)
+		}
+		return true
+	}, nil)
+
+	return rv.diagnostics
+}
+
+// checkCall checks the regex arguments of built-in functions and VMOD calls
+func (rv *RegexValidator) checkCall(call *ast.CallExpression) {
+	if ident, ok := call.Function.(*ast.Identifier); ok {
+		switch ident.Name {
+		case "regsub", "regsuball":
+			if len(call.Arguments) > 1 {
+				if lit, ok := call.Arguments[1].(*ast.StringLiteral); ok {
+					rv.checkPattern(lit, lit.Value)
+				}
+			}
+		case "ban":
+			if len(call.Arguments) > 0 {
+				if lit, ok := call.Arguments[0].(*ast.StringLiteral); ok {
+					rv.checkBan(lit)
+				}
+			}
+		}
+		return
+	}
+
+	_, _, params, _ := rv.callSignature(call)
+	positional := 0
+	for _, p := range vcc.VCLParameters(params) {
+		arg, named := call.NamedArguments[p.Name]
+		if !named && positional < len(call.Arguments) {
+			arg = call.Arguments[positional]
+		}
+		positional++
+		if lit, ok := arg.(*ast.StringLiteral); ok && p.Type == vcc.TypeString && isRegexParameter(p.Name) {
+			rv.checkPattern(lit, lit.Value)
+		}
+	}
+}
+
+// checkBan checks the regular expressions in the conditions of a ban
+// expression given as a single string
+func (rv *RegexValidator) checkBan(lit *ast.StringLiteral) {
+	for _, condition := range strings.Split(lit.Value, "&&") {
+		m := banCondition.FindStringSubmatch(condition)
+		if m == nil {
+			continue
+		}
+		pattern := m[3]
+		if len(pattern) >= 2 && (pattern[0] == '"' || pattern[0] == '\'') && pattern[len(pattern)-1] == pattern[0] {
+			pattern = pattern[1 : len(pattern)-1]
+		}
+		rv.checkPattern(lit, pattern)
+	}
+}
+
+// checkPattern compiles a pattern found in lit. When the pattern is the
+// whole literal, the diagnostic points at the offending part of it.
+func (rv *RegexValidator) checkPattern(lit *ast.StringLiteral, pattern string) {
+	re, err := syntax.Parse(pcreToRE2(pattern), syntax.Perl)
+	if err != nil {
+		message := fmt.Sprintf("invalid regular expression %q", pattern)
+		var serr *syntax.Error
+		if errors.As(err, &serr) {
+			message += ": " + string(serr.Code)
+		}
+		diag := NewDiagnostic(lit, SeverityError, "invalid-regex", message)
+		if serr != nil && serr.Expr != "" && pattern == lit.Value && lit.Start().Line == lit.End().Line {
+			if i := strings.Index(pattern, serr.Expr); i >= 0 {
+				diag.Position.Column += 1 + i
+				diag.Position.Offset += 1 + i
+				diag.EndPosition = diag.Position
+				diag.EndPosition.Column += len(serr.Expr)
+				diag.EndPosition.Offset += len(serr.Expr)
+			}
+		}
+		rv.diagnostics = append(rv.diagnostics, diag)
+		return
+	}
+
+	if rv.reportBacktracking && nestedQuantifier(re, false) {
+		rv.diagnostics = append(rv.diagnostics, NewDiagnostic(lit, rv.backtrackingSeverity, "regex-backtracking",
+			fmt.Sprintf("regular expression %q nests unbounded quantifiers and may backtrack catastrophically", pattern)))
+	}
+}
+
+// isRegexParameter reports whether a VMOD parameter name says it takes a
+// regular expression, like regex, pattern or name_re
+func isRegexParameter(name string) bool {
+	return name == "regex" || name == "pattern" || name == "re" || strings.HasSuffix(name, "_re") || strings.HasSuffix(name, "_regex")
+}
+
+// nestedQuantifier reports whether an unbounded quantifier is applied to an
+// expression that itself contains one, as in (a+)+ or (.*,)*
+func nestedQuantifier(re *syntax.Regexp, repeated bool) bool {
+	unbounded := re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max == -1)
+	if unbounded && repeated {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if nestedQuantifier(sub, repeated || unbounded) {
+			return true
+		}
+	}
+	return false
+}
+
+// pcreToRE2 rewrites the PCRE constructs RE2 does not support into ones it
+// accepts with the same syntax rules. The result is only meant for
+// validation: lookarounds become plain groups and backreferences match the
+// empty string.
+func pcreToRE2(pattern string) string {
+	var b strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(pcreEscape(pattern, &i, inClass))
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+			b.WriteByte(c)
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+			// A ] right after [ or [^ is literal
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				i++
+				b.WriteByte('^')
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				i++
+				b.WriteString(`\]`)
+			}
+		case c == '(' && strings.HasPrefix(pattern[i:], "(?"):
+			b.WriteString(pcreGroup(pattern, &i))
+		case (c == '*' || c == '+' || c == '?' || c == '}') && i+1 < len(pattern) && pattern[i+1] == '+':
+			// Possessive quantifier
+			b.WriteByte(c)
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pcreEscape rewrites the escape sequence whose character after the
+// backslash is pattern[*i], and advances *i to its last character
+func pcreEscape(pattern string, i *int, inClass bool) string {
+	c := pattern[*i]
+	switch {
+	case c >= '1' && c <= '9' && !inClass:
+		for *i+1 < len(pattern) && pattern[*i+1] >= '0' && pattern[*i+1] <= '9' {
+			*i++
+		}
+		return "(?:)"
+	case c == 'k' && !inClass && *i+1 < len(pattern):
+		end := map[byte]byte{'<': '>', '\'': '\'', '{': '}'}[pattern[*i+1]]
+		if j := strings.IndexByte(pattern[*i+2:], end); end != 0 && j >= 0 {
+			*i += j + 2
+			return "(?:)"
+		}
+	case c == 'g' && !inClass:
+		j := *i + 1
+		for j < len(pattern) && strings.IndexByte("{}<>'-0123456789", pattern[j]) >= 0 {
+			j++
+		}
+		*i = j - 1
+		return "(?:)"
+	case c == 'Z':
+		return `\z`
+	case c == 'h':
+		if inClass {
+			return `\t `
+		}
+		return `[\t ]`
+	case c == 'H' && !inClass:
+		return `[^\t ]`
+	case c == 'R' && !inClass:
+		return `(?:\r\n|\n|\r)`
+	case c == 'K' || c == 'G':
+		return ""
+	case c == 'e':
+		return `\x1b`
+	}
+	return `\` + string(c)
+}
+
+// pcreGroup rewrites the group opening "(?" at pattern[*i] and advances *i
+// to its last character
+func pcreGroup(pattern string, i *int) string {
+	rest := pattern[*i:]
+	for _, prefix := range []string{"(?<=", "(?<!", "(?=", "(?!", "(?>", "(?|"} {
+		if strings.HasPrefix(rest, prefix) {
+			*i += len(prefix) - 1
+			return "(?:"
+		}
+	}
+	switch {
+	case strings.HasPrefix(rest, "(?#"):
+		// Comment
+		if j := strings.IndexByte(rest, ')'); j >= 0 {
+			*i += j
+			return ""
+		}
+	case strings.HasPrefix(rest, "(?<"), strings.HasPrefix(rest, "(?'"):
+		// Named group
+		end := map[byte]byte{'<': '>', '\'': '\''}[rest[2]]
+		if j := strings.IndexByte(rest[3:], end); j >= 0 {
+			*i += j + 3
+			return "(?P<" + rest[3:3+j] + ">"
+		}
+	case strings.HasPrefix(rest, "(?R)"), strings.HasPrefix(rest, "(?&"), strings.HasPrefix(rest, "(?P>"),
+		len(rest) > 2 && (rest[2] >= '0' && rest[2] <= '9' || rest[2] == '+' || rest[2] == '-' && len(rest) > 3 && rest[3] >= '0' && rest[3] <= '9'):
+		// Recursion and subroutine calls
+		if j := strings.IndexByte(rest, ')'); j >= 0 {
+			*i += j
+			return "(?:)"
+		}
+	case strings.HasPrefix(rest, "(?("):
+		// Conditional; the condition is dropped
+		if j := strings.IndexByte(rest[3:], ')'); j >= 0 {
+			*i += j + 3
+			return "(?:"
+		}
+	}
+
+	// Option settings, dropping those RE2 lacks, such as x
+	j := 2
+	for j < len(rest) && strings.IndexByte("imsxnJU-^", rest[j]) >= 0 {
+		j++
+	}
+	if j < len(rest) && (rest[j] == ')' || rest[j] == ':') {
+		flags := strings.TrimSuffix(strings.Map(func(r rune) rune {
+			if strings.ContainsRune("xnJ^", r) {
+				return -1
+			}
+			return r
+		}, rest[2:j]), "-")
+		*i += j
+		if flags == "" {
+			if rest[j] == ')' {
+				return ""
+			}
+			return "(?:"
+		}
+		return "(?" + flags + string(rest[j])
+	}
+	*i++
+	return "(?"
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestRegexValidator(t *testing.T) {
+	registry := vmod.NewRegistry()
+
+	tests := []struct {
+		name               string
+		vclCode            string
+		reportBacktracking bool
+		expected           []string
+		codes              []string
+	}{
+		{
+			name: "valid patterns",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.url ~ "^/(images|css)/" && req.http.host !~ "(?i)^www\.") {
+						set req.url = regsub(req.url, "\?.*$", "");
+					}
+				}`,
+		},
+		{
+			name: "pcre only constructs",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.url ~ "^/(?!admin)(?<section>[a-z]+)/\k<section>" ||
+					    req.url ~ "(a)\1(?=b)(?<=a)b++\Z" ||
+					    req.url ~ "(?x) ^ /static/ (?# comment )") {
+						return (pass);
+					}
+				}`,
+		},
+		{
+			name: "unbalanced group",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.url ~ "^/(foo") {
+						return (pass);
+					}
+				}`,
+			expected: []string{`invalid regular expression "^/(foo": missing closing )`},
+			codes:    []string{"invalid-regex"},
+		},
+		{
+			name: "regsuball pattern",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					set req.url = regsuball(req.url, "[a-", "");
+				}`,
+			expected: []string{`invalid regular expression "[a-": missing closing ]`},
+			codes:    []string{"invalid-regex"},
+		},
+		{
+			name: "ban expression",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					ban("obj.http.x-url ~ ^/ok && obj.http.x-host ~ '*.example'");
+				}`,
+			expected: []string{`invalid regular expression "*.example": missing argument to repetition operator`},
+			codes:    []string{"invalid-regex"},
+		},
+		{
+			name: "vmod regex parameter",
+			vclCode: `vcl 4.1;
+				import urlplus;
+				sub vcl_recv {
+					urlplus.query_keep_regex("^(utm_");
+					urlplus.query_keep_regex(regex = "^id$");
+				}`,
+			expected: []string{`invalid regular expression "^(utm_"`},
+			codes:    []string{"invalid-regex"},
+		},
+		{
+			name: "backtracking not reported by default",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.url ~ "^(a+)+$") {
+						return (pass);
+					}
+				}`,
+		},
+		{
+			name: "nested quantifiers",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (req.url ~ "^(a+)+$" || req.url ~ "^(/[a-z]+){1,3}$") {
+						return (pass);
+					}
+				}`,
+			reportBacktracking: true,
+			expected:           []string{"may backtrack catastrophically"},
+			codes:              []string{"regex-backtracking"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			a := NewAnalyzer(registry)
+			level := "off"
+			if test.reportBacktracking {
+				level = "warning"
+			}
+			if err := a.SetBacktracking(level); err != nil {
+				t.Fatal(err)
+			}
+			var diags []Diagnostic
+			for _, diag := range a.AnalyzeDiagnostics(program) {
+				if strings.Contains(diag.Code, "regex") {
+					diags = append(diags, diag)
+				}
+			}
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.codes[i] {
+					t.Errorf("diagnostic %d code = %s, want %s", i, diags[i].Code, test.codes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRegexDiagnosticPosition(t *testing.T) {
+	program, err := parser.Parse("vcl 4.1;\nsub vcl_recv {\n\tif (req.url ~ \"^/a(b\") {}\n}\n", "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	var lit ast.Node
+	walkNodes(program, func(node ast.Node) {
+		if s, ok := node.(*ast.StringLiteral); ok {
+			lit = s
+		}
+	})
+
+	diags := NewRegexValidator(nil, nil, nil).Validate(program)
+	if len(diags) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d: %v", len(diags), diags)
+	}
+	// Go reports the whole pattern for a missing ), so the diagnostic
+	// starts right after the opening quote
+	if got, want := diags[0].Position.Offset, lit.Start().Offset+1; got != want {
+		t.Errorf("diagnostic offset = %d, want %d", got, want)
+	}
+}
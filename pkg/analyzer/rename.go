@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+// TextEdit replaces the source text between Start and End with NewText
+type TextEdit struct {
+	Start   lexer.Position
+	End     lexer.Position
+	NewText string
+	// Decl is the top-level declaration containing the edit, which tells
+	// the file it applies to in programs merged from includes
+	Decl ast.Declaration
+}
+
+// EditsByFile groups edits by the file their declaration was read from, as
+// reported by file, such as include.SourceMap.Path. Edits whose file is
+// unknown are grouped under "". Each group keeps the order of edits.
+func EditsByFile(edits []TextEdit, file func(ast.Declaration) string) map[string][]TextEdit {
+	files := make(map[string][]TextEdit)
+	for _, e := range edits {
+		name := ""
+		if e.Decl != nil {
+			name = file(e.Decl)
+		}
+		files[name] = append(files[name], e)
+	}
+	return files
+}
+
+// ApplyEdits returns source with the edits applied. The edits must be for
+// this source and must not overlap.
+func ApplyEdits(source string, edits []TextEdit) (string, error) {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Offset < sorted[j].Start.Offset })
+
+	var b strings.Builder
+	last := 0
+	for _, e := range sorted {
+		if e.Start.Offset < last || e.End.Offset < e.Start.Offset || e.End.Offset > len(source) {
+			return "", fmt.Errorf("invalid edit at line %d, column %d", e.Start.Line, e.Start.Column)
+		}
+		b.WriteString(source[last:e.Start.Offset])
+		b.WriteString(e.NewText)
+		last = e.End.Offset
+	}
+	b.WriteString(source[last:])
+	return b.String(), nil
+}
+
+// identifierPattern matches the names VCL accepts for declarations
+var identifierPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Rename renames the backend, ACL, probe or custom subroutine oldName to
+// newName, along with every reference to it: backend_hint assignments and
+// other uses in expressions, .probe properties, ACL matches and call
+// statements. The program is edited in place and returned together with the
+// edits that make the same change to the source text, ordered by offset.
+//
+// Positions come from the parsed source, so for programs merged by the
+// include resolver the edits must be matched to their files, which
+// EditsByFile does with the resolver's source map.
+func Rename(program *ast.Program, kind types.SymbolKind, oldName, newName string) (*ast.Program, []TextEdit, error) {
+	switch kind {
+	case types.SymbolBackend, types.SymbolACL, types.SymbolProbe, types.SymbolSubroutine:
+	default:
+		return nil, nil, fmt.Errorf("cannot rename a %s", kind)
+	}
+	if !identifierPattern.MatchString(newName) {
+		return nil, nil, fmt.Errorf("%q is not a valid name", newName)
+	}
+	if kind == types.SymbolSubroutine && (isBuiltinSubroutine(oldName) || isBuiltinSubroutine(newName)) {
+		return nil, nil, fmt.Errorf("cannot rename built-in subroutines")
+	}
+	if declarationKind(program, oldName) != kind {
+		return nil, nil, fmt.Errorf("no %s named %s", kind, oldName)
+	}
+	if oldName == newName {
+		return program, nil, nil
+	}
+	if symbolDeclared(program, newName) {
+		return nil, nil, fmt.Errorf("%s is already declared", newName)
+	}
+
+	var edits []TextEdit
+	var decl ast.Declaration
+	addEdit := func(start lexer.Position) {
+		end := start
+		end.Column += len(oldName)
+		end.Offset += len(oldName)
+		edits = append(edits, TextEdit{Start: start, End: end, NewText: newName, Decl: decl})
+	}
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if d, ok := c.Node().(ast.Declaration); ok {
+			if _, top := c.Parent().(*ast.Program); top {
+				decl = d
+			}
+		}
+		switch n := c.Node().(type) {
+		case *ast.BackendDecl:
+			if kind == types.SymbolBackend && n.Name == oldName {
+				n.Name = newName
+				addEdit(n.NamePos)
+			}
+		case *ast.ACLDecl:
+			if kind == types.SymbolACL && n.Name == oldName {
+				n.Name = newName
+				addEdit(n.NamePos)
+			}
+		case *ast.ProbeDecl:
+			if kind == types.SymbolProbe && n.Name == oldName {
+				n.Name = newName
+				addEdit(n.NamePos)
+			}
+		case *ast.SubDecl:
+			if kind == types.SymbolSubroutine && n.Name == oldName {
+				n.Name = newName
+				addEdit(n.NamePos)
+			}
+		case *ast.ReturnStatement:
+			// Return actions are not symbols
+			return false
+		case *ast.Identifier:
+			if n.Name == oldName && isReference(c, kind) {
+				c.Replace(&ast.Identifier{BaseNode: n.BaseNode, Name: newName})
+				addEdit(n.StartPos)
+			}
+		}
+		return true
+	}, nil)
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start.Offset < edits[j].Start.Offset })
+	return program, edits, nil
+}
+
+// isReference reports whether the identifier under the cursor can refer to
+// a symbol of the given kind. Subroutines are only referenced by call
+// statements and as function arguments; the other symbols are values, which
+// are neither member names nor functions.
+func isReference(c *ast.Cursor, kind types.SymbolKind) bool {
+	switch c.Parent().(type) {
+	case *ast.CallStatement:
+		return kind == types.SymbolSubroutine
+	case *ast.MemberExpression:
+		return kind != types.SymbolSubroutine && c.Name() != "Property"
+	case *ast.CallExpression:
+		return c.Name() != "Function"
+	default:
+		return kind != types.SymbolSubroutine
+	}
+}
+
+// declarationKind returns the kind of the top-level declaration named name,
+// or SymbolVariable if there is none
+func declarationKind(program *ast.Program, name string) types.SymbolKind {
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.BackendDecl:
+			if d != nil && d.Name == name {
+				return types.SymbolBackend
+			}
+		case *ast.ACLDecl:
+			if d != nil && d.Name == name {
+				return types.SymbolACL
+			}
+		case *ast.ProbeDecl:
+			if d != nil && d.Name == name {
+				return types.SymbolProbe
+			}
+		case *ast.SubDecl:
+			if d != nil && d.Name == name {
+				return types.SymbolSubroutine
+			}
+		}
+	}
+	return types.SymbolVariable
+}
+
+// symbolDeclared reports whether name is taken by a declaration, an imported
+// VMOD or an object created with new, all of which share one namespace
+func symbolDeclared(program *ast.Program, name string) bool {
+	if declarationKind(program, name) != types.SymbolVariable {
+		return true
+	}
+	for _, decl := range program.Declarations {
+		if imp, ok := decl.(*ast.ImportDecl); ok && imp != nil {
+			if imp.Module == name || imp.Alias == name {
+				return true
+			}
+		}
+	}
+	found := false
+	walkNodes(program, func(node ast.Node) {
+		if ns, ok := node.(*ast.NewStatement); ok {
+			if ident, ok := ns.Name.(*ast.Identifier); ok && ident.Name == name {
+				found = true
+			}
+		}
+	})
+	return found
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+const renameVCL = `vcl 4.1;
+
+probe health { .url = "/health"; }
+
+backend origin {
+    .host = "origin.example.com";
+    .probe = health;
+}
+
+backend spare { .host = "spare.example.com"; }
+
+acl purgers { "localhost"; }
+
+sub pick {
+    set req.backend_hint = origin;
+}
+
+sub vcl_recv {
+    if (client.ip ~ purgers) {
+        return (purge);
+    }
+    call pick;
+    set req.http.origin = "origin";
+}
+`
+
+// applyEdits applies non-overlapping edits to the source
+func applyEdits(source string, edits []TextEdit) string {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Offset > sorted[j].Start.Offset })
+	for _, e := range sorted {
+		source = source[:e.Start.Offset] + e.NewText + source[e.End.Offset:]
+	}
+	return source
+}
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     types.SymbolKind
+		old, new string
+		edits    int
+		want     []string
+	}{
+		{"backend", types.SymbolBackend, "origin", "primary", 2,
+			[]string{"backend primary {", "set req.backend_hint = primary;", "set req.http.origin = \"origin\";"}},
+		{"probe", types.SymbolProbe, "health", "alive", 2,
+			[]string{"probe alive {", ".probe = alive;"}},
+		{"acl", types.SymbolACL, "purgers", "admins", 2,
+			[]string{"acl admins {", "client.ip ~ admins"}},
+		{"subroutine", types.SymbolSubroutine, "pick", "choose_backend", 2,
+			[]string{"sub choose_backend {", "call choose_backend;"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parser.Parse(renameVCL, "rename.vcl")
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			renamed, edits, err := Rename(program, tt.kind, tt.old, tt.new)
+			if err != nil {
+				t.Fatalf("Rename failed: %v", err)
+			}
+			if len(edits) != tt.edits {
+				t.Errorf("Expected %d edits, got %d: %v", tt.edits, len(edits), edits)
+			}
+
+			edited := applyEdits(renameVCL, edits)
+			for _, want := range tt.want {
+				if !strings.Contains(edited, want) {
+					t.Errorf("Expected %q in the edited source:\n%s", want, edited)
+				}
+			}
+
+			// The edited AST analyzes like the edited source
+			if declarationKind(renamed, tt.new) != tt.kind || declarationKind(renamed, tt.old) != types.SymbolVariable {
+				t.Errorf("Expected the declaration to be renamed in the AST")
+			}
+			if _, err := parser.Parse(edited, "rename.vcl"); err != nil {
+				t.Errorf("Edited source does not parse: %v", err)
+			}
+			graph := NewCallGraph(renamed)
+			if tt.kind == types.SymbolSubroutine && len(graph.Callers(tt.new)) != 1 {
+				t.Errorf("Expected %s to be called once in the edited AST", tt.new)
+			}
+		})
+	}
+}
+
+func TestRenameErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     types.SymbolKind
+		old, new string
+		err      string
+	}{
+		{"unknown", types.SymbolBackend, "nope", "other", "no Backend named nope"},
+		{"wrong kind", types.SymbolACL, "origin", "other", "no ACL named origin"},
+		{"taken", types.SymbolBackend, "origin", "purgers", "purgers is already declared"},
+		{"invalid", types.SymbolBackend, "origin", "1st", "not a valid name"},
+		{"builtin", types.SymbolSubroutine, "vcl_recv", "recv", "built-in"},
+		{"variable", types.SymbolVariable, "req", "request", "cannot rename"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parser.Parse(renameVCL, "rename.vcl")
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			_, _, err = Rename(program, tt.kind, tt.old, tt.new)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -10,21 +10,28 @@ import (
 // ReturnActionValidator validates return statements against VCL metadata
 type ReturnActionValidator struct {
 	loader        *metadata.MetadataLoader
+	release       metadata.Release
 	currentMethod string
-	errors        []string
+	diagnostics   []Diagnostic
 }
 
 // NewReturnActionValidator creates a new return action validator
 func NewReturnActionValidator(loader *metadata.MetadataLoader) *ReturnActionValidator {
 	return &ReturnActionValidator{
 		loader: loader,
-		errors: []string{},
 	}
 }
 
-// Validate validates all return statements in a VCL program
+// SetRelease selects the Varnish release whose return actions are allowed.
+// The zero release accepts every action in the metadata.
+func (rav *ReturnActionValidator) SetRelease(release metadata.Release) {
+	rav.release = release
+}
+
+// Validate validates all return statements in a VCL program. It returns the
+// findings as messages; Diagnostics returns them with positions.
 func (rav *ReturnActionValidator) Validate(program *ast.Program) []string {
-	rav.errors = []string{}
+	rav.diagnostics = nil
 
 	// Visit all subroutines and validate return statements
 	for _, decl := range program.Declarations {
@@ -34,15 +41,20 @@ func (rav *ReturnActionValidator) Validate(program *ast.Program) []string {
 		}
 	}
 
-	return rav.errors
+	return legacyMessages(rav.diagnostics)
+}
+
+// Diagnostics returns the findings of the last Validate call
+func (rav *ReturnActionValidator) Diagnostics() []Diagnostic {
+	return rav.diagnostics
 }
 
 // validateSubroutineReturns validates return statements in VCL built-in subroutines only.
 // Extracts the method name from the subroutine (removing vcl_ prefix) and validates each
 // return statement's action against the metadata for that VCL method context.
 func (rav *ReturnActionValidator) validateSubroutineReturns(sub *ast.SubDecl) {
-	// Only validate built-in VCL subroutines (those starting with vcl_)
-	if !isBuiltinSubroutine(sub.Name) {
+	// Only validate built-in VCL subroutines the metadata knows
+	if !isKnownBuiltin(rav.loader, sub.Name) {
 		return
 	}
 
@@ -54,7 +66,7 @@ func (rav *ReturnActionValidator) validateSubroutineReturns(sub *ast.SubDecl) {
 
 	for _, returnStmt := range returnStmts {
 		if err := rav.validateReturnStatement(returnStmt, methodName); err != nil {
-			rav.errors = append(rav.errors, err.Error())
+			rav.diagnostics = append(rav.diagnostics, NewDiagnostic(returnStmt, SeverityError, "return-action", err.Error()))
 		}
 	}
 }
@@ -69,15 +81,11 @@ func (rav *ReturnActionValidator) validateReturnStatement(stmt *ast.ReturnStatem
 	// Extract action name from the expression
 	actionName, err := rav.extractActionName(stmt.Action)
 	if err != nil {
-		return fmt.Errorf("invalid return action at line %d: %v", stmt.StartPos.Line, err)
+		return fmt.Errorf("invalid return action: %v", err)
 	}
 
 	// Validate against metadata
-	if err := rav.loader.ValidateReturnAction(methodName, actionName); err != nil {
-		return fmt.Errorf("at line %d: %v", stmt.StartPos.Line, err)
-	}
-
-	return nil
+	return rav.loader.ValidateReturnActionForRelease(methodName, actionName, rav.release)
 }
 
 // extractActionName extracts the action name from a return expression, handling both simple
@@ -138,6 +146,21 @@ func isBuiltinSubroutine(name string) bool {
 	return len(name) > 4 && name[:4] == "vcl_"
 }
 
+// isKnownBuiltin reports whether name is a built-in subroutine the metadata
+// of loader describes. Other vcl_ subroutines are reported once, as unknown,
+// by the VersionValidator.
+func isKnownBuiltin(loader *metadata.MetadataLoader, name string) bool {
+	if !isBuiltinSubroutine(name) {
+		return false
+	}
+	methods, err := loader.GetMethods()
+	if err != nil {
+		return false
+	}
+	_, ok := methods[extractMethodName(name)]
+	return ok
+}
+
 // extractMethodName removes the vcl_ prefix from a subroutine name
 func extractMethodName(subroutineName string) string {
 	if len(subroutineName) > 4 && subroutineName[:4] == "vcl_" {
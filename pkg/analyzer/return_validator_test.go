@@ -302,3 +302,49 @@ func TestExtractMethodName(t *testing.T) {
 		}
 	}
 }
+
+func TestReturnActionValidator_Release(t *testing.T) {
+	vclCode := `vcl 4.1;
+		sub vcl_hit {
+			return (miss);
+		}
+		sub vcl_backend_fetch {
+			return (error);
+		}
+	`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	tests := []struct {
+		release    string
+		errorCount int
+	}{
+		{"", 0},
+		{"6.0", 1},  // return(error) from vcl_backend_fetch arrived in 6.3
+		{"6.6", 0},  // both available
+		{"7.5", 1},  // return(miss) from vcl_hit is gone
+		{"5.2", 1},  // only return(error) is missing
+		{"7.0", 1},  // removal takes effect in the release listed
+		{"6.3", 0},  // introduction takes effect in the release listed
+		{"6.2", 1},  // one release too early
+		{"99.0", 1}, // far future release still lacks miss
+	}
+
+	for _, test := range tests {
+		validator := NewReturnActionValidator(metadata.New())
+		if test.release != "" {
+			release, err := metadata.ParseRelease(test.release)
+			if err != nil {
+				t.Fatalf("ParseRelease(%q): %v", test.release, err)
+			}
+			validator.SetRelease(release)
+		}
+
+		errors := validator.Validate(program)
+		if len(errors) != test.errorCount {
+			t.Errorf("release %q: expected %d errors, got %d: %v", test.release, test.errorCount, len(errors), errors)
+		}
+	}
+}
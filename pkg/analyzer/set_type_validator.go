@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// SetTypeValidator checks that the value of a set statement has a type the
+// variable accepts, so that `set beresp.ttl = "foo";` is rejected as VCC
+// does. Types are inferred from literals, the metadata's variable types and
+// the return types in the VCC files; assignments whose value type cannot be
+// determined are not reported. BACKEND variables are left to the
+// BackendAssignmentValidator.
+//
+// It relies on the symbol table populated by the VMOD validator, so it must
+// run after it.
+type SetTypeValidator struct {
+	typeResolver
+	diagnostics []Diagnostic
+}
+
+// NewSetTypeValidator creates a new set statement type checker
+func NewSetTypeValidator(loader *metadata.MetadataLoader, symbolTable *types.SymbolTable, registry *vmod.Registry) *SetTypeValidator {
+	return &SetTypeValidator{
+		typeResolver: typeResolver{
+			loader:      loader,
+			symbolTable: symbolTable,
+			registry:    registry,
+		},
+	}
+}
+
+// Validate checks every set statement in the program
+func (tv *SetTypeValidator) Validate(program *ast.Program) []Diagnostic {
+	tv.diagnostics = nil
+
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Body != nil {
+			tv.diagnostics = append(tv.diagnostics, tv.validateSubroutine(sub)...)
+		}
+	}
+
+	return tv.diagnostics
+}
+
+// validateSubroutine checks the set statements of one subroutine
+func (tv *SetTypeValidator) validateSubroutine(sub *ast.SubDecl) []Diagnostic {
+	v := &SetTypeValidator{typeResolver: tv.typeResolver}
+	walkSubStatements(sub.Body, func(stmt ast.Statement) {
+		if set, ok := stmt.(*ast.SetStatement); ok {
+			v.validateSet(set)
+		}
+	})
+	return v.diagnostics
+}
+
+func (tv *SetTypeValidator) validateSet(stmt *ast.SetStatement) {
+	target := ast.VariableName(stmt.Variable)
+	want := vcc.VCCType(tv.variableType(target))
+	switch want {
+	case "", vcc.TypeBackend, "HTTP", vcc.TypeStevedore, vcc.TypeBlob:
+		return
+	}
+
+	got, detail := tv.expressionType(stmt.Value)
+	if got == "" {
+		return
+	}
+
+	// set x += y is set x = x + y
+	if stmt.Operator != "" && stmt.Operator != "=" {
+		op := stmt.Operator[:1]
+		result, ok := types.BinaryResult(op, string(want), string(got))
+		if !ok {
+			tv.add(stmt, fmt.Sprintf("%s %s %s is not possible in set %s %s", want, op, got, target, stmt.Operator), detail)
+			return
+		}
+		got = vcc.VCCType(result)
+	}
+	if !types.CanCoerce(string(got), string(want)) {
+		tv.add(stmt, fmt.Sprintf("cannot assign %s to %s (%s)", got, target, want), detail)
+	}
+}
+
+// expressionType returns the VCC type of an expression along with a hint
+// explaining where it came from. An empty type means it could not be
+// determined.
+func (tv *SetTypeValidator) expressionType(expr ast.Expression) (vcc.VCCType, string) {
+	switch e := expr.(type) {
+	case *ast.StringLiteral:
+		return vcc.TypeString, ""
+	case *ast.IntegerLiteral:
+		return vcc.TypeInt, ""
+	case *ast.FloatLiteral:
+		return vcc.TypeReal, ""
+	case *ast.BooleanLiteral:
+		return vcc.TypeBool, ""
+	case *ast.TimeExpression:
+		return vcc.TypeDuration, ""
+	case *ast.IPExpression:
+		return vcc.TypeIP, ""
+	case *ast.ParenthesizedExpression:
+		return tv.expressionType(e.Expression)
+	case *ast.Identifier:
+		if t := tv.variableType(e.Name); t != "" {
+			return vcc.VCCType(t), ""
+		}
+		return tv.identifierType(e.Name)
+	case *ast.MemberExpression:
+		return vcc.VCCType(tv.variableType(ast.VariableName(e))), ""
+	case *ast.CallExpression:
+		return tv.callReturnType(e)
+	case *ast.RegexMatchExpression:
+		return vcc.TypeBool, ""
+	case *ast.UnaryExpression:
+		if e.Operator == "!" {
+			return vcc.TypeBool, ""
+		}
+		return tv.expressionType(e.Operand)
+	case *ast.BinaryExpression:
+		switch e.Operator {
+		case "==", "!=", "<", ">", "<=", ">=", "~", "!~", "&&", "||":
+			return vcc.TypeBool, ""
+		}
+		left, _ := tv.expressionType(e.Left)
+		right, _ := tv.expressionType(e.Right)
+		if left == "" || right == "" {
+			return "", ""
+		}
+		result, ok := types.BinaryResult(e.Operator, string(left), string(right))
+		if !ok {
+			// Reported here rather than as a mismatch of the whole value
+			message := fmt.Sprintf("%s %s %s is not possible", left, e.Operator, right)
+			if types.IsStringType(string(right)) && e.Operator == "+" {
+				message += "; a concatenation must start with a string"
+			}
+			tv.add(e, message, "")
+		}
+		return vcc.VCCType(result), ""
+	}
+	return "", ""
+}
+
+func (tv *SetTypeValidator) add(node ast.Node, message, detail string) {
+	if detail != "" {
+		message += ": " + detail
+	}
+	tv.diagnostics = append(tv.diagnostics, NewDiagnostic(node, SeverityError, "set-type", message))
+}
@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestSetTypeValidator(t *testing.T) {
+	registry := setupTestRegistry(t)
+
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+	}{
+		{
+			name: "matching types",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.ttl = 1h;
+					set beresp.grace = beresp.ttl * 2;
+					set beresp.keep = -1s;
+					set beresp.status = 200;
+					set beresp.do_esi = true;
+					set beresp.uncacheable = beresp.http.Set-Cookie;
+					set beresp.http.X-TTL = beresp.ttl;
+					set beresp.http.X-Age = "age: " + beresp.ttl;
+					set beresp.ttl *= 2;
+				}`,
+		},
+		{
+			name: "string assigned to a duration",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.ttl = "foo";
+				}`,
+			expected: []string{"cannot assign STRING to beresp.ttl (DURATION)"},
+		},
+		{
+			name: "number without a unit",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.ttl = 10;
+				}`,
+			expected: []string{"cannot assign INT to beresp.ttl (DURATION)"},
+		},
+		{
+			name: "real assigned to an integer",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					set resp.status = 200.5;
+				}`,
+			expected: []string{"cannot assign REAL to resp.status (INT)"},
+		},
+		{
+			name: "vmod return types",
+			vclCode: `vcl 4.1;
+				import std;
+				sub vcl_recv {
+					set req.http.X-Random = std.random(0, 1);
+					set req.http.X-Log = std.log("x");
+				}`,
+			expected: []string{"cannot assign VOID to req.http.X-Log (HEADER): std.log() returns VOID"},
+		},
+		{
+			name: "time arithmetic",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.ttl = now - beresp.time;
+					set beresp.grace = now + 1s;
+				}`,
+			expected: []string{"cannot assign TIME to beresp.grace (DURATION)"},
+		},
+		{
+			name: "scaling by a string",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.ttl *= "2";
+				}`,
+			expected: []string{"DURATION * STRING is not possible in set beresp.ttl *="},
+		},
+		{
+			name: "concatenation converts to string",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.http.X-TTL = "x" + 5s;
+					set beresp.http.X-Backend = "backend: " + beresp.backend + ", status " + beresp.status;
+					set beresp.http.X-Cache += 1;
+				}`,
+		},
+		{
+			name: "concatenation assigned to a duration",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.ttl = "x" + 5;
+				}`,
+			expected: []string{"cannot assign STRING to beresp.ttl (DURATION)"},
+		},
+		{
+			name: "concatenation not starting with a string",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response {
+					set beresp.http.X-TTL = 5s + "x";
+				}`,
+			expected: []string{"DURATION + STRING is not possible; a concatenation must start with a string"},
+		},
+		{
+			name: "integer scaled by a real",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					set resp.status = resp.status * 1.5;
+				}`,
+			expected: []string{"INT * REAL is not possible"},
+		},
+		{
+			name: "unknown types are not reported",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					set req.url = regsub(req.url, "\?.*", "");
+				}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			var diags []Diagnostic
+			for _, diag := range NewAnalyzer(registry).AnalyzeDiagnostics(program) {
+				if diag.Code == "set-type" {
+					diags = append(diags, diag)
+				}
+			}
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != SeverityError {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, SeverityError)
+				}
+			}
+		})
+	}
+}
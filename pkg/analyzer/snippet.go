@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// SnippetOptions describes the configuration a fragment of VCL is inserted
+// into, for AnalyzeSnippet
+type SnippetOptions struct {
+	// Sub is the built-in subroutine the fragment becomes part of, such as
+	// "vcl_backend_response". The vcl_ prefix is optional.
+	Sub string
+	// Prelude is VCL source with the declarations the fragment may refer
+	// to, such as the imports and backends of the configuration. It may
+	// start with a version declaration, which otherwise is vcl 4.1.
+	// Findings in the prelude are not reported.
+	Prelude string
+}
+
+// AnalyzeSnippet checks a fragment of VCL without version declaration or
+// subroutine around it, as embedded in Kubernetes ingress annotations,
+// against the variable access and return action rules of the subroutine it
+// is inserted into. Syntax errors in the fragment are returned as
+// diagnostics; an unknown subroutine or a prelude that does not parse is
+// an error.
+func (a *Analyzer) AnalyzeSnippet(src, filename string, opts SnippetOptions) ([]Diagnostic, error) {
+	name := "vcl_" + strings.TrimPrefix(opts.Sub, "vcl_")
+	methods, err := a.metadataLoader.GetMethods()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := methods[strings.TrimPrefix(name, "vcl_")]; !ok {
+		return nil, fmt.Errorf("unknown built-in subroutine %q", opts.Sub)
+	}
+
+	prelude, err := parser.ParseSnippet(opts.Prelude, "prelude")
+	if err != nil {
+		return nil, fmt.Errorf("prelude: %w", err)
+	}
+	if len(prelude.Statements) > 0 {
+		return nil, fmt.Errorf("prelude: expected declarations, not statements")
+	}
+
+	snippet, err := parser.ParseSnippet(src, filename)
+	if err != nil {
+		if detailed, ok := err.(parser.DetailedError); ok {
+			return []Diagnostic{DiagnosticFromParseError(detailed)}, nil
+		}
+		return nil, err
+	}
+	if len(snippet.Declarations) > 0 || snippet.VCLVersion != nil {
+		diag := NewDiagnostic(nil, SeverityError, "snippet", fmt.Sprintf("expected the statements of %s, not declarations", name))
+		diag.Filename = filename
+		diag.Position = snippetStart(snippet)
+		return []Diagnostic{diag}, nil
+	}
+
+	sub := &ast.SubDecl{Name: name, Body: &ast.BlockStatement{Statements: snippet.Statements}}
+	if len(snippet.Statements) > 0 {
+		sub.StartPos = snippet.Statements[0].Start()
+		sub.EndPos = snippet.Statements[len(snippet.Statements)-1].End()
+		sub.Body.BaseNode = sub.BaseNode
+	}
+	version := prelude.VCLVersion
+	if version == nil {
+		version = &ast.VCLVersionDecl{Version: "4.1"}
+	}
+	program := &ast.Program{
+		VCLVersion:   version,
+		Declarations: append(prelude.Declarations, sub),
+	}
+
+	diags := append(a.runValidators(program), a.runDiagnosticValidators(program)...)
+	locateSources(program, diags, snippetLocator{sub: sub, filename: filename})
+	var result []Diagnostic
+	for _, diag := range diags {
+		if diag.Filename == filename {
+			result = append(result, diag)
+		}
+	}
+	return result, nil
+}
+
+// snippetLocator places the subroutine made of a fragment in its file and
+// the declarations of the prelude nowhere
+type snippetLocator struct {
+	sub      *ast.SubDecl
+	filename string
+}
+
+func (l snippetLocator) Locate(decl ast.Declaration) (string, []string, bool) {
+	return l.filename, nil, decl == l.sub
+}
+
+// snippetStart returns the position of the first node of a fragment
+func snippetStart(snippet *parser.Snippet) lexer.Position {
+	if snippet.VCLVersion != nil {
+		return snippet.VCLVersion.Start()
+	}
+	return snippet.Declarations[0].Start()
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestAnalyzeSnippet(t *testing.T) {
+	a := NewAnalyzer(vmod.DefaultRegistry)
+	opts := SnippetOptions{
+		Sub:     "vcl_backend_response",
+		Prelude: "import std;\nbackend svc { .host = \"192.0.2.1\"; }\nsub unused {}\n",
+	}
+
+	diags, err := a.AnalyzeSnippet("std.log(\"fetched\");\nif (beresp.status >= 500) {\n\treturn (retry);\n}\nset beresp.ttl = 1h;\n", "annotation", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 0 {
+		t.Errorf("Expected no findings, got %+v", diags)
+	}
+
+	// The vcl_ prefix is optional
+	opts.Sub = "backend_response"
+	diags, err = a.AnalyzeSnippet("set req.url = \"/\";\nreturn (hash);\n", "annotation", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := map[string]int{}
+	for _, d := range diags {
+		if d.Filename != "annotation" {
+			t.Errorf("Expected the snippet file name, got %q", d.Filename)
+		}
+		codes[d.Code] = d.Position.Line
+	}
+	if len(diags) != 2 || codes["variable-access"] != 1 || codes["return-action"] != 2 {
+		t.Errorf("Expected the write of req.url and the return to be reported, got %+v", diags)
+	}
+
+	diags, err = a.AnalyzeSnippet("set beresp.ttl = ;", "annotation", opts)
+	if err != nil || len(diags) != 1 || diags[0].Code != "parse-error" {
+		t.Errorf("Expected a syntax error, got %+v %v", diags, err)
+	}
+	diags, err = a.AnalyzeSnippet("sub vcl_recv {}", "annotation", opts)
+	if err != nil || len(diags) != 1 || diags[0].Code != "snippet" {
+		t.Errorf("Expected declarations to be reported, got %+v %v", diags, err)
+	}
+
+	if _, err := a.AnalyzeSnippet("", "annotation", SnippetOptions{Sub: "vcl_foo"}); err == nil {
+		t.Error("Expected an error for an unknown subroutine")
+	}
+	if _, err := a.AnalyzeSnippet("", "annotation", SnippetOptions{Sub: "vcl_recv", Prelude: "set req.url = \"/\";"}); err == nil {
+		t.Error("Expected an error for statements in the prelude")
+	}
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"reflect"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// SourceLocator reports the file a top-level declaration was read from and
+// the chain of files that included it, outermost first. include.SourceMap
+// implements it for programs merged by the include resolver.
+type SourceLocator interface {
+	Locate(decl ast.Declaration) (filename string, chain []string, ok bool)
+}
+
+// SetSourceLocator makes AnalyzeDiagnostics fill in the file name and
+// include chain of each diagnostic from the declaration it was found in. A
+// nil locator turns this off.
+func (a *Analyzer) SetSourceLocator(locator SourceLocator) {
+	a.sourceLocator = locator
+}
+
+// locateSources fills in Filename and IncludeChain of diagnostics that do not
+// have a file name yet
+func locateSources(program *ast.Program, diags []Diagnostic, locator SourceLocator) {
+	var owners map[ast.Node]ast.Declaration
+	for i := range diags {
+		diag := &diags[i]
+		if diag.Filename != "" {
+			continue
+		}
+		var decl ast.Declaration
+		if diag.node != nil {
+			if owners == nil {
+				owners = declarationOwners(program)
+			}
+			decl = owners[diag.node]
+		} else {
+			decl = declarationAt(program, diag)
+		}
+		if decl == nil {
+			continue
+		}
+		if filename, chain, ok := locator.Locate(decl); ok {
+			diag.Filename = filename
+			diag.IncludeChain = chain
+		}
+	}
+}
+
+// declarationAt returns the only declaration spanning the position of a
+// diagnostic that has no node, as reported by plugins. Declarations merged
+// from different files can cover the same positions, in which case the
+// owner is unknown.
+func declarationAt(program *ast.Program, diag *Diagnostic) ast.Declaration {
+	if diag.Position.Line == 0 {
+		return nil
+	}
+	var found ast.Declaration
+	for _, decl := range program.Declarations {
+		if decl == nil || reflect.ValueOf(decl).IsNil() {
+			continue
+		}
+		start, end := decl.Start(), decl.End()
+		if diag.Position.Line < start.Line || diag.Position.Line > end.Line {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = decl
+	}
+	return found
+}
+
+// declarationOwners maps every node of the program to the top-level
+// declaration containing it
+func declarationOwners(program *ast.Program) map[ast.Node]ast.Declaration {
+	owners := make(map[ast.Node]ast.Declaration)
+	for _, decl := range program.Declarations {
+		walkNodes(decl, func(node ast.Node) {
+			owners[node] = decl
+		})
+	}
+	return owners
+}
+
+var nodeType = reflect.TypeOf((*ast.Node)(nil)).Elem()
+
+// walkNodes calls fn for root and every node below it. Unlike the visitors,
+// it reaches every node type, including those added after a visitor was
+// written.
+func walkNodes(root ast.Node, fn func(ast.Node)) {
+	seen := make(map[ast.Node]bool)
+	var walk func(reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Interface:
+			if !v.IsNil() {
+				walk(v.Elem())
+			}
+		case reflect.Ptr:
+			if v.IsNil() {
+				return
+			}
+			if v.Type().Implements(nodeType) {
+				node := v.Interface().(ast.Node)
+				if seen[node] {
+					return
+				}
+				seen[node] = true
+				fn(node)
+			}
+			walk(v.Elem())
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).IsExported() {
+					walk(v.Field(i))
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i))
+			}
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				walk(iter.Value())
+			}
+		}
+	}
+	walk(reflect.ValueOf(root))
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+func TestSourceLocator(t *testing.T) {
+	reader := include.NewMemoryFileReader(map[string]string{
+		"main.vcl": `vcl 4.1;
+
+include "recv.vcl";
+
+sub vcl_deliver {
+	return (lookup);
+}
+`,
+		"recv.vcl": `vcl 4.1;
+
+include "normalize.vcl";
+
+sub vcl_recv {
+	return (deliver);
+}
+`,
+		"normalize.vcl": `vcl 4.1;
+
+sub normalize {
+	set req.http.x = req.foo;
+}
+`,
+	})
+	resolver := include.NewResolver(include.WithFileReader(reader))
+	program, err := resolver.ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+
+	a := NewAnalyzer(nil)
+	a.SetSourceLocator(resolver.SourceMap())
+	diags := a.AnalyzeDiagnostics(program)
+
+	expected := map[string]struct {
+		file  string
+		chain []string
+		line  int
+	}{
+		"variable-access": {"normalize.vcl", []string{"main.vcl", "recv.vcl"}, 4},
+		"unreferenced":    {"normalize.vcl", []string{"main.vcl", "recv.vcl"}, 3},
+	}
+	returns := map[string][]string{}
+	for _, diag := range diags {
+		if diag.Code == "return-action" {
+			returns[diag.Filename] = diag.IncludeChain
+			if diag.Position.Line != 6 {
+				t.Errorf("Expected return-action on line 6 of %s, got %d", diag.Filename, diag.Position.Line)
+			}
+			continue
+		}
+		want, ok := expected[diag.Code]
+		if !ok {
+			t.Errorf("Unexpected diagnostic: %+v", diag)
+			continue
+		}
+		if diag.Filename != want.file || !equalStrings(diag.IncludeChain, want.chain) || diag.Position.Line != want.line {
+			t.Errorf("%s: got %s:%d via %v, expected %s:%d via %v", diag.Code, diag.Filename,
+				diag.Position.Line, diag.IncludeChain, want.file, want.line, want.chain)
+		}
+	}
+
+	if chain, ok := returns["main.vcl"]; !ok || len(chain) != 0 {
+		t.Errorf("Expected a return-action diagnostic in main.vcl without chain, got %v", returns)
+	}
+	if chain, ok := returns["recv.vcl"]; !ok || !equalStrings(chain, []string{"main.vcl"}) {
+		t.Errorf("Expected a return-action diagnostic in recv.vcl included from main.vcl, got %v", returns)
+	}
+}
+
+func TestSourceLocatorUnset(t *testing.T) {
+	reader := include.NewMemoryFileReader(map[string]string{
+		"main.vcl": "vcl 4.1;\n\nsub vcl_recv {\n\treturn (deliver);\n}\n",
+	})
+	program, err := include.NewResolver(include.WithFileReader(reader)).ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	for _, diag := range NewAnalyzer(nil).AnalyzeDiagnostics(program) {
+		if diag.Filename != "" || diag.IncludeChain != nil {
+			t.Errorf("Expected no source without a locator, got %+v", diag)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEditsByFile(t *testing.T) {
+	files := map[string]string{
+		"main.vcl": `vcl 4.1;
+
+include "backends.vcl";
+
+sub vcl_recv {
+	set req.backend_hint = origin;
+}
+`,
+		"backends.vcl": `vcl 4.1;
+
+backend origin { .host = "192.0.2.1"; }
+`,
+	}
+	resolver := include.NewResolver(include.WithFileReader(include.NewMemoryFileReader(files)))
+	program, err := resolver.ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	_, edits, err := Rename(program, types.SymbolBackend, "origin", "primary")
+	if err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+
+	byFile := EditsByFile(edits, resolver.SourceMap().Path)
+	if len(byFile) != 2 {
+		t.Fatalf("Expected edits for 2 files, got %v", byFile)
+	}
+	want := map[string]string{
+		"main.vcl":     "set req.backend_hint = primary;",
+		"backends.vcl": "backend primary {",
+	}
+	for file, text := range want {
+		out, err := ApplyEdits(files[file], byFile[file])
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if !strings.Contains(out, text) || strings.Contains(out, "origin") {
+			t.Errorf("%s not renamed correctly:\n%s", file, out)
+		}
+	}
+
+	overlapping := append(byFile["main.vcl"], byFile["main.vcl"]...)
+	if _, err := ApplyEdits(files["main.vcl"], overlapping); err == nil {
+		t.Error("Expected overlapping edits to be rejected")
+	}
+}
@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// HeaderReference is one use of an HTTP header in a program
+type HeaderReference struct {
+	// Variable is the header variable, e.g. "req.http.Host"
+	Variable string
+	// Object is the variable the header belongs to, such as "req" or
+	// "beresp"
+	Object string
+	// Header is the header name as written
+	Header string
+	// Sub is the subroutine the reference is in
+	Sub      string
+	Access   HeaderAccess
+	Position lexer.Position
+}
+
+// HeaderAccess is how a header is used
+type HeaderAccess int
+
+const (
+	HeaderRead HeaderAccess = iota
+	HeaderWrite
+	HeaderUnset
+)
+
+func (a HeaderAccess) String() string {
+	switch a {
+	case HeaderWrite:
+		return "write"
+	case HeaderUnset:
+		return "unset"
+	default:
+		return "read"
+	}
+}
+
+// ReferencedHeaders returns every use of a header in program, in source
+// order. Together with the symbol table of the analysis (see
+// Analyzer.GetSymbolTable), which answers which backends, ACLs, VMOD
+// objects and subroutines a program declares and where, it gives tools
+// such as editors the names a program refers to.
+func ReferencedHeaders(program *ast.Program) []HeaderReference {
+	var refs []HeaderReference
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub.Body == nil {
+			continue
+		}
+		ast.Apply(sub.Body, func(c *ast.Cursor) bool {
+			member, ok := c.Node().(*ast.MemberExpression)
+			if !ok {
+				return true
+			}
+			ref, ok := headerReference(member)
+			if !ok {
+				return true
+			}
+			access := HeaderRead
+			switch c.Parent().(type) {
+			case *ast.SetStatement:
+				if c.Name() == "Variable" {
+					access = HeaderWrite
+				}
+			case *ast.UnsetStatement:
+				access = HeaderUnset
+			}
+			refs = append(refs, HeaderReference{
+				Variable: ref.prefix + ".http." + ref.name,
+				Object:   ref.prefix,
+				Header:   ref.name,
+				Sub:      sub.Name,
+				Access:   access,
+				Position: member.Start(),
+			})
+			return false
+		}, nil)
+	}
+	return refs
+}
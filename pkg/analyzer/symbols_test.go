@@ -0,0 +1,267 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestSymbolTableDeclarations(t *testing.T) {
+	program := parseVCL(t, `vcl 4.1;
+
+backend web { .host = "127.0.0.1"; }
+
+acl purgers { "127.0.0.1"; }
+
+sub normalize {
+    unset req.http.Cookie;
+}
+
+sub vcl_recv {
+    call normalize;
+}
+`)
+	a := NewAnalyzer(nil)
+	a.AnalyzeDiagnostics(program)
+	st := a.GetSymbolTable()
+
+	decl, ok := st.Declaration("web").(*ast.BackendDecl)
+	if !ok || decl.Name != "web" {
+		t.Errorf("Expected the backend declaration of web, got %v", st.Declaration("web"))
+	}
+	if symbol := st.Lookup("web"); symbol == nil || symbol.Position.Line != 3 {
+		t.Errorf("Expected web to be declared at line 3, got %v", symbol)
+	}
+	if _, ok := st.Declaration("purgers").(*ast.ACLDecl); !ok {
+		t.Errorf("Expected the ACL declaration of purgers, got %v", st.Declaration("purgers"))
+	}
+
+	subs := st.Symbols(types.SymbolSubroutine)
+	var names []string
+	for _, s := range subs {
+		names = append(names, s.Name)
+	}
+	if len(names) != 2 || names[0] != "normalize" || names[1] != "vcl_recv" {
+		t.Errorf("Expected subroutines normalize and vcl_recv, got %v", names)
+	}
+}
+
+func TestReferencedHeaders(t *testing.T) {
+	program := parseVCL(t, `vcl 4.1;
+
+sub vcl_recv {
+    if (req.http.Host == "example.com") {
+        set req.http.X-Seen = "1";
+    }
+}
+
+sub vcl_deliver {
+    unset resp.http.Server;
+}
+`)
+	refs := ReferencedHeaders(program)
+	expected := []HeaderReference{
+		{Variable: "req.http.Host", Object: "req", Header: "Host", Sub: "vcl_recv"},
+		{Variable: "req.http.X-Seen", Object: "req", Header: "X-Seen", Sub: "vcl_recv", Access: HeaderWrite},
+		{Variable: "resp.http.Server", Object: "resp", Header: "Server", Sub: "vcl_deliver", Access: HeaderUnset},
+	}
+	if len(refs) != len(expected) {
+		t.Fatalf("Expected %d references, got %v", len(expected), refs)
+	}
+	for i, ref := range refs {
+		ref.Position = expected[i].Position
+		if ref != expected[i] {
+			t.Errorf("Reference %d: expected %+v, got %+v", i, expected[i], ref)
+		}
+	}
+	if refs[0].Position.Line != 4 {
+		t.Errorf("Expected the first reference at line 4, got %v", refs[0].Position)
+	}
+}
+
+func TestShadowedBuiltin(t *testing.T) {
+	tests := []struct {
+		name     string
+		vclCode  string
+		severity Severity
+		expected string
+	}{
+		{
+			name:     "backend named like a variable",
+			vclCode:  "vcl 4.1;\nbackend req { .host = \"127.0.0.1\"; }\n",
+			severity: SeverityError,
+			expected: "backend req shadows the built-in variable req",
+		},
+		{
+			name:     "sub named like a variable namespace",
+			vclCode:  "vcl 4.1;\nsub client { }\nsub vcl_recv { call client; }\n",
+			severity: SeverityError,
+			expected: "subroutine client shadows the built-in variable client.ip",
+		},
+		{
+			name:     "sub named like a function",
+			vclCode:  "vcl 4.1;\nsub regsub { }\nsub vcl_recv { call regsub; }\n",
+			severity: SeverityError,
+			expected: "subroutine regsub shadows the built-in function regsub",
+		},
+		{
+			name:     "acl named default",
+			vclCode:  "vcl 4.1;\nacl default { \"127.0.0.1\"; }\n",
+			severity: SeverityWarning,
+			expected: "acl default is named like the default backend",
+		},
+		{
+			name:    "backend named default",
+			vclCode: "vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := parseVCL(t, tt.vclCode)
+			var found []Diagnostic
+			for _, d := range NewAnalyzer(nil).AnalyzeDiagnostics(program) {
+				if d.Code == "shadowed-builtin" {
+					found = append(found, d)
+				}
+			}
+			if tt.expected == "" {
+				if len(found) > 0 {
+					t.Errorf("Expected no shadowing diagnostics, got %v", found)
+				}
+				return
+			}
+			if len(found) != 1 || found[0].Message != tt.expected || found[0].Severity != tt.severity {
+				t.Errorf("Expected %q, got %v", tt.expected, found)
+			}
+		})
+	}
+}
+
+func TestSymbolReferences(t *testing.T) {
+	src := `vcl 4.1;
+
+import directors;
+
+probe health { .url = "/"; }
+
+backend web {
+    .host = "127.0.0.1";
+    .probe = health;
+}
+
+acl purgers { "127.0.0.1"; }
+
+sub vcl_init {
+    new dir = directors.round_robin();
+    dir.add_backend(web);
+}
+
+sub pick {
+    set req.backend_hint = dir.backend();
+}
+
+sub vcl_recv {
+    if (client.ip ~ purgers) {
+        return (purge);
+    }
+    call pick;
+}
+`
+	program := parseVCL(t, src)
+	a := NewAnalyzer(vmod.DefaultRegistry)
+	a.AnalyzeDiagnostics(program)
+	st := a.GetSymbolTable()
+
+	tests := []struct {
+		name  string
+		subs  []string
+		lines []int
+	}{
+		{name: "health", subs: []string{""}, lines: []int{9}},
+		{name: "web", subs: []string{"vcl_init"}, lines: []int{16}},
+		{name: "purgers", subs: []string{"vcl_recv"}, lines: []int{24}},
+		{name: "directors", subs: []string{"vcl_init"}, lines: []int{15}},
+		{name: "dir", subs: []string{"vcl_init", "pick"}, lines: []int{16, 20}},
+		{name: "pick", subs: []string{"vcl_recv"}, lines: []int{27}},
+	}
+	for _, tt := range tests {
+		symbol := st.Lookup(tt.name)
+		if symbol == nil {
+			t.Errorf("%s: not defined", tt.name)
+			continue
+		}
+		if len(symbol.References) != len(tt.lines) {
+			t.Errorf("%s: expected %d references, got %v", tt.name, len(tt.lines), symbol.References)
+			continue
+		}
+		for i, ref := range symbol.References {
+			if ref.Sub != tt.subs[i] || ref.Position.Line != tt.lines[i] {
+				t.Errorf("%s: expected a reference in %q at line %d, got %q at line %d",
+					tt.name, tt.subs[i], tt.lines[i], ref.Sub, ref.Position.Line)
+			}
+		}
+	}
+
+	for _, name := range []string{"dir", "web", "pick"} {
+		pos := st.Lookup(name).DefPosition
+		if !strings.HasPrefix(src[pos.Offset:], name) {
+			t.Errorf("Expected the definition of %s at its name, got %q", name, src[pos.Offset:pos.Offset+10])
+		}
+	}
+}
+
+func TestHeaderReport(t *testing.T) {
+	program := parseVCL(t, `vcl 4.1;
+
+sub vcl_recv {
+    unset req.http.Cookie;
+    set req.http.X-Client = req.http.User-Agent;
+}
+
+sub vcl_backend_fetch {
+    set bereq.http.X-Token = "secret";
+}
+
+sub vcl_deliver {
+    set resp.http.X-Cache = "HIT";
+    unset resp.http.Server;
+}
+
+sub vcl_recv {
+    if (req.http.Host) {
+        return (hash);
+    }
+}
+`)
+	report := HeaderReport(program)
+	if len(report) != 3 {
+		t.Fatalf("Expected 3 subroutines, got %+v", report)
+	}
+
+	recv := report[0]
+	if recv.Sub != "vcl_recv" || len(recv.Unset) != 1 || len(recv.Written) != 1 || len(recv.Read) != 2 {
+		t.Fatalf("Unexpected vcl_recv usage: %+v", recv)
+	}
+	if recv.Unset[0].Header != "Cookie" || recv.Written[0].Header != "X-Client" {
+		t.Errorf("Unexpected vcl_recv usage: %+v", recv)
+	}
+	if recv.Read[0].Header != "User-Agent" || recv.Read[1].Header != "Host" || recv.Read[1].Position.Line != 18 {
+		t.Errorf("Expected reads of User-Agent and Host, got %+v", recv.Read)
+	}
+	if len(recv.Forwarded) != 1 || recv.Forwarded[0].Destination != "backend" {
+		t.Errorf("Expected X-Client to be forwarded to the backend, got %+v", recv.Forwarded)
+	}
+
+	if fetch := report[1]; fetch.Sub != "vcl_backend_fetch" || len(fetch.Forwarded) != 1 ||
+		fetch.Forwarded[0].Variable != "bereq.http.X-Token" || fetch.Forwarded[0].Destination != "backend" {
+		t.Errorf("Unexpected vcl_backend_fetch usage: %+v", fetch)
+	}
+	if deliver := report[2]; deliver.Sub != "vcl_deliver" || len(deliver.Forwarded) != 1 ||
+		deliver.Forwarded[0].Destination != "client" || len(deliver.Unset) != 1 {
+		t.Errorf("Unexpected vcl_deliver usage: %+v", deliver)
+	}
+}
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// variableRoots are the first components of VCL variable names
+var variableRoots = map[string]bool{
+	"req": true, "req_top": true, "bereq": true, "beresp": true, "resp": true,
+	"obj": true, "client": true, "server": true, "local": true, "remote": true,
+	"now": true, "sess": true, "storage": true,
+}
+
+// SyntheticTemplate is the body of a synthetic() statement taken apart into
+// the pieces joined with +: literal text, and values computed at runtime
+// together with the variables they read
+type SyntheticTemplate struct {
+	Parts []TemplatePart
+}
+
+// TemplatePart is one piece of a synthetic body
+type TemplatePart struct {
+	// Expr is the piece as parsed, without surrounding parentheses
+	Expr ast.Expression
+	// Literal is set when the piece is a string literal with the text Text
+	Literal bool
+	Text    string
+	// Long is set for literals written as long strings
+	Long bool
+	// Variables are the VCL variables the piece reads, in source order
+	Variables []TemplateVariable
+}
+
+// TemplateVariable is a variable read by a piece of a synthetic body, such
+// as req.url or req.http.host, or by an argument of a function called there
+type TemplateVariable struct {
+	Name string
+	Expr ast.Expression
+}
+
+// ParseSyntheticTemplate takes apart a synthetic body
+func ParseSyntheticTemplate(body ast.Expression) *SyntheticTemplate {
+	template := &SyntheticTemplate{}
+	if body == nil {
+		return template
+	}
+	for _, piece := range splitBinary(body, "+") {
+		part := TemplatePart{Expr: piece}
+		if lit, ok := piece.(*ast.StringLiteral); ok {
+			part.Literal = true
+			part.Text = lit.Value
+			part.Long = lit.Long
+		} else {
+			part.Variables = templateVariables(piece, nil)
+		}
+		template.Parts = append(template.Parts, part)
+	}
+	return template
+}
+
+// Text joins the literal text of the body, substituting placeholder for
+// every value computed at runtime
+func (t *SyntheticTemplate) Text(placeholder string) string {
+	var sb strings.Builder
+	for _, part := range t.Parts {
+		if part.Literal {
+			sb.WriteString(part.Text)
+		} else {
+			sb.WriteString(placeholder)
+		}
+	}
+	return sb.String()
+}
+
+// Variables returns the variables read anywhere in the body, in source order
+func (t *SyntheticTemplate) Variables() []TemplateVariable {
+	var variables []TemplateVariable
+	for _, part := range t.Parts {
+		variables = append(variables, part.Variables...)
+	}
+	return variables
+}
+
+// templateVariables appends the variables read by expr. The function of a
+// call is not a variable, but its arguments may read some.
+func templateVariables(expr ast.Expression, variables []TemplateVariable) []TemplateVariable {
+	switch e := expr.(type) {
+	case *ast.Identifier, *ast.MemberExpression:
+		name := ast.VariableName(e)
+		if root, _, _ := strings.Cut(name, "."); variableRoots[root] {
+			variables = append(variables, TemplateVariable{Name: name, Expr: e})
+		}
+	case *ast.ParenthesizedExpression:
+		variables = templateVariables(e.Expression, variables)
+	case *ast.UnaryExpression:
+		variables = templateVariables(e.Operand, variables)
+	case *ast.BinaryExpression:
+		variables = templateVariables(e.Left, variables)
+		variables = templateVariables(e.Right, variables)
+	case *ast.RegexMatchExpression:
+		variables = templateVariables(e.Left, variables)
+	case *ast.CallExpression:
+		for _, arg := range e.Arguments {
+			variables = templateVariables(arg, variables)
+		}
+		names := make([]string, 0, len(e.NamedArguments))
+		for name := range e.NamedArguments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			variables = templateVariables(e.NamedArguments[name], variables)
+		}
+	}
+	return variables
+}
@@ -0,0 +1,391 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// syntheticResponse maps the subroutines where synthetic() is allowed to the
+// variable prefix of the response being built there
+var syntheticResponse = map[string]string{
+	"vcl_synth":         "resp.",
+	"vcl_backend_error": "beresp.",
+	// Fastly builds synthetic responses in vcl_error
+	"vcl_error": "obj.",
+}
+
+// htmlVoidElements never have a closing tag
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// htmlOptionalEnd are elements whose closing tag may be omitted
+var htmlOptionalEnd = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true,
+	"dt": true, "dd": true, "tr": true, "td": true, "th": true,
+	"thead": true, "tbody": true, "tfoot": true, "option": true, "colgroup": true,
+}
+
+// SyntheticValidator checks synthetic() bodies built by concatenation: every
+// piece must be convertible to STRING, response variables must belong to the
+// subroutine the body is built in, and when the subroutine sets an HTML or
+// JSON Content-Type the literal parts of the body must form a well-formed
+// document. Bodies in custom subroutines are checked against every built-in
+// subroutine that calls them: synthetic() must be allowed there, and the
+// variables the body reads must be readable there.
+//
+// It relies on the symbol table populated by the VMOD validator, so it must
+// run after it.
+type SyntheticValidator struct {
+	typeResolver
+	diagnostics []Diagnostic
+}
+
+// NewSyntheticValidator creates a new synthetic body validator
+func NewSyntheticValidator(loader *metadata.MetadataLoader, symbolTable *types.SymbolTable, registry *vmod.Registry) *SyntheticValidator {
+	return &SyntheticValidator{
+		typeResolver: typeResolver{
+			loader:      loader,
+			symbolTable: symbolTable,
+			registry:    registry,
+		},
+	}
+}
+
+// Validate checks every synthetic statement in the program
+func (sv *SyntheticValidator) Validate(program *ast.Program) []Diagnostic {
+	sv.diagnostics = nil
+
+	graph := NewCallGraph(program)
+	synthetics := make(map[string][]*ast.SyntheticStatement)
+	contentTypes := make(map[string]string)
+	for _, name := range graph.Subroutines() {
+		for _, sub := range graph.Declarations(name) {
+			if sub.Body == nil {
+				continue
+			}
+			walkSubStatements(sub.Body, func(stmt ast.Statement) {
+				switch s := stmt.(type) {
+				case *ast.SyntheticStatement:
+					synthetics[name] = append(synthetics[name], s)
+				case *ast.SetStatement:
+					variable := strings.ToLower(ast.VariableName(s.Variable))
+					if variable == "resp.http.content-type" || variable == "beresp.http.content-type" {
+						if lit, ok := s.Value.(*ast.StringLiteral); ok {
+							contentTypes[name] = strings.ToLower(lit.Value)
+						}
+					}
+				}
+			})
+		}
+	}
+
+	for _, name := range graph.Subroutines() {
+		if len(synthetics[name]) == 0 {
+			continue
+		}
+		contexts := []string{name}
+		contentType := contentTypes[name]
+		if !isBuiltinSubroutine(name) {
+			contexts = callingBuiltins(graph, name)
+			if contentType == "" {
+				contentType = sharedContentType(contexts, contentTypes)
+			}
+		}
+		for _, stmt := range synthetics[name] {
+			sv.validateSynthetic(name, contexts, stmt, contentType)
+		}
+	}
+
+	return sv.diagnostics
+}
+
+// callingBuiltins returns the built-in subroutines from which a custom
+// subroutine is reached
+func callingBuiltins(graph *CallGraph, name string) []string {
+	var builtins []string
+	for _, builtin := range graph.Subroutines() {
+		if isBuiltinSubroutine(builtin) && containsString(graph.ReachableFrom(builtin), name) {
+			builtins = append(builtins, builtin)
+		}
+	}
+	return builtins
+}
+
+// sharedContentType returns the Content-Type set by all of the subroutines,
+// or "" if they do not agree
+func sharedContentType(subs []string, contentTypes map[string]string) string {
+	shared := ""
+	for i, sub := range subs {
+		if i > 0 && contentTypes[sub] != shared {
+			return ""
+		}
+		shared = contentTypes[sub]
+	}
+	return shared
+}
+
+// validateSynthetic checks a synthetic statement of subName, which runs in
+// the built-in subroutines contexts: subName itself if it is built-in,
+// otherwise those calling it
+func (sv *SyntheticValidator) validateSynthetic(subName string, contexts []string, stmt *ast.SyntheticStatement, contentType string) {
+	if stmt.Response == nil {
+		return
+	}
+
+	var allowed []string
+	for _, context := range contexts {
+		if _, ok := syntheticResponse[context]; ok {
+			allowed = append(allowed, context)
+			continue
+		}
+		message := fmt.Sprintf("synthetic() cannot be used in %s, only in vcl_synth and vcl_backend_error", context)
+		if context != subName {
+			message = fmt.Sprintf("synthetic() in %s cannot run in %s, which calls it; it is only allowed in vcl_synth and vcl_backend_error",
+				subName, context)
+		}
+		sv.add(stmt, SeverityError, "synthetic-context", message)
+	}
+	if isBuiltinSubroutine(subName) && len(allowed) == 0 {
+		return
+	}
+
+	template := ParseSyntheticTemplate(stmt.Response)
+	for i, part := range template.Parts {
+		piece := part.Expr
+		pieceType, detail := sv.pieceType(piece)
+		switch {
+		case pieceType == "":
+		case !types.CanCoerce(string(pieceType), "STRING"):
+			message := fmt.Sprintf("synthetic body: %s is %s, which cannot be converted to STRING", describePiece(piece), pieceType)
+			if detail != "" {
+				message += ": " + detail
+			}
+			sv.add(piece, SeverityError, "synthetic-type", message)
+		case i == 0 && len(template.Parts) > 1 && !types.IsStringType(string(pieceType)):
+			sv.add(piece, SeverityError, "synthetic-type", fmt.Sprintf(
+				"synthetic body starts with %s (%s), and %s + STRING is not possible; start with a string such as \"\" + %s",
+				describePiece(piece), pieceType, pieceType, describePiece(piece)))
+		}
+	}
+	for _, context := range allowed {
+		if context != subName {
+			sv.checkReadable(template, subName, context)
+		}
+		sv.checkResponseVariables(template, context, syntheticResponse[context])
+	}
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		sv.checkJSON(stmt, template)
+	case strings.Contains(contentType, "html"):
+		sv.checkHTML(stmt, template)
+	}
+}
+
+// checkReadable reports variables that the body built in the custom
+// subroutine subName reads but that cannot be read in context, which calls
+// it. The variable access validator only checks built-in subroutines.
+func (sv *SyntheticValidator) checkReadable(template *SyntheticTemplate, subName, context string) {
+	method := strings.TrimPrefix(context, "vcl_")
+	for _, v := range template.Variables() {
+		if sv.variableType(v.Name) == "" {
+			continue
+		}
+		if sv.loader.ValidateVariableAccess(v.Name, method, "read") != nil {
+			sv.add(v.Expr, SeverityError, "synthetic-variable", fmt.Sprintf(
+				"synthetic body in %s reads %s, which cannot be read in %s, which calls it", subName, v.Name, context))
+		}
+	}
+}
+
+// pieceType returns the VCC type of one piece of a concatenation, or "" if
+// it cannot be determined
+func (sv *SyntheticValidator) pieceType(expr ast.Expression) (vcc.VCCType, string) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.StringLiteral:
+		return vcc.TypeString, ""
+	case *ast.IntegerLiteral:
+		return vcc.TypeInt, ""
+	case *ast.FloatLiteral:
+		return vcc.TypeReal, ""
+	case *ast.BooleanLiteral:
+		return vcc.TypeBool, ""
+	case *ast.TimeExpression:
+		return vcc.TypeDuration, ""
+	case *ast.Identifier:
+		if t := sv.variableType(e.Name); t != "" {
+			return vcc.VCCType(t), ""
+		}
+		return sv.identifierType(e.Name)
+	case *ast.MemberExpression:
+		return vcc.VCCType(sv.variableType(ast.VariableName(e))), ""
+	case *ast.CallExpression:
+		return sv.callReturnType(e)
+	}
+	return "", ""
+}
+
+// checkResponseVariables points out resp.* used in vcl_backend_error and
+// beresp.* used in vcl_synth. The variable access validator reports the
+// error itself; this adds which variable was probably meant.
+func (sv *SyntheticValidator) checkResponseVariables(template *SyntheticTemplate, subName, prefix string) {
+	method := strings.TrimPrefix(subName, "vcl_")
+	other := "resp."
+	if prefix == "resp." {
+		other = "beresp."
+	}
+
+	for _, v := range template.Variables() {
+		if !strings.HasPrefix(v.Name, other) {
+			continue
+		}
+		if sv.loader.ValidateVariableAccess(v.Name, method, "read") == nil {
+			continue
+		}
+		suggestion := prefix + strings.TrimPrefix(v.Name, other)
+		if sv.loader.ValidateVariableAccess(suggestion, method, "read") != nil {
+			continue
+		}
+		sv.add(v.Expr, SeverityInfo, "synthetic-variable", fmt.Sprintf(
+			"the response built in %s is %s*, so %s is not available; use %s", subName, prefix, v.Name, suggestion))
+	}
+}
+
+// checkJSON validates the body as JSON, with each non-literal piece replaced
+// by 0, which is valid both as a value and inside a JSON string
+func (sv *SyntheticValidator) checkJSON(stmt *ast.SyntheticStatement, template *SyntheticTemplate) {
+	body := template.Text("0")
+	if strings.TrimSpace(body) == "" {
+		return
+	}
+	var document interface{}
+	if err := json.Unmarshal([]byte(body), &document); err != nil {
+		sv.add(stmt, SeverityWarning, "synthetic-json",
+			fmt.Sprintf("synthetic body is not valid JSON although Content-Type is JSON: %v", err))
+	}
+}
+
+// checkHTML checks that the tags in the body are balanced, allowing the
+// closing tags HTML lets you omit
+func (sv *SyntheticValidator) checkHTML(stmt *ast.SyntheticStatement, template *SyntheticTemplate) {
+	if problem := htmlTagProblem(template.Text("")); problem != "" {
+		sv.add(stmt, SeverityWarning, "synthetic-html",
+			"synthetic HTML body is not well-formed: "+problem)
+	}
+}
+
+func (sv *SyntheticValidator) add(node ast.Node, severity Severity, code, message string) {
+	sv.diagnostics = append(sv.diagnostics, NewDiagnostic(node, severity, code, message))
+}
+
+// htmlTagProblem describes the first unbalanced tag in body, or returns ""
+func htmlTagProblem(body string) string {
+	var open []string
+	for i := 0; i < len(body); i++ {
+		if body[i] != '<' {
+			continue
+		}
+		rest := body[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return "comment is never closed"
+			}
+			i += end + 2
+			continue
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			if end := strings.IndexByte(rest, '>'); end >= 0 {
+				i += end
+			}
+			continue
+		}
+
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return "tag is never closed with '>'"
+		}
+		tag := rest[1:end]
+		i += end
+
+		closing := strings.HasPrefix(tag, "/")
+		name := strings.ToLower(strings.TrimPrefix(tag, "/"))
+		if j := strings.IndexAny(name, " \t\r\n/"); j >= 0 {
+			name = name[:j]
+		}
+		if name == "" || htmlVoidElements[name] {
+			continue
+		}
+
+		if !closing {
+			if !strings.HasSuffix(tag, "/") {
+				open = append(open, name)
+			}
+			continue
+		}
+
+		// Pop elements with optional end tags until the matching one
+		for len(open) > 0 && open[len(open)-1] != name && htmlOptionalEnd[open[len(open)-1]] {
+			open = open[:len(open)-1]
+		}
+		if len(open) == 0 {
+			return fmt.Sprintf("</%s> has no matching <%s>", name, name)
+		}
+		if top := open[len(open)-1]; top != name {
+			return fmt.Sprintf("<%s> is closed by </%s>", top, name)
+		}
+		open = open[:len(open)-1]
+	}
+
+	for _, name := range open {
+		if !htmlOptionalEnd[name] {
+			return fmt.Sprintf("<%s> is never closed", name)
+		}
+	}
+	return ""
+}
+
+// describePiece renders a concatenation piece for messages
+func describePiece(expr ast.Expression) string {
+	if key := expressionKey(expr); key != "" {
+		return key
+	}
+	return "expression"
+}
+
+// inspectExpression calls fn for expr and every expression nested in it
+func inspectExpression(expr ast.Expression, fn func(ast.Expression)) {
+	if expr == nil {
+		return
+	}
+	fn(expr)
+	switch e := expr.(type) {
+	case *ast.ParenthesizedExpression:
+		inspectExpression(e.Expression, fn)
+	case *ast.UnaryExpression:
+		inspectExpression(e.Operand, fn)
+	case *ast.BinaryExpression:
+		inspectExpression(e.Left, fn)
+		inspectExpression(e.Right, fn)
+	case *ast.RegexMatchExpression:
+		inspectExpression(e.Left, fn)
+		inspectExpression(e.Right, fn)
+	case *ast.CallExpression:
+		for _, arg := range e.Arguments {
+			inspectExpression(arg, fn)
+		}
+		for _, arg := range e.NamedArguments {
+			inspectExpression(arg, fn)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+func TestSyntheticValidator(t *testing.T) {
+	loader := metadata.New()
+
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+		severity Severity
+	}{
+		{
+			name: "plain concatenation",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					set resp.http.Content-Type = "text/html; charset=utf-8";
+					synthetic("<html><body><h1>" + resp.status + " " + resp.reason + "</h1><p>XID: " + req.xid + "</body></html>");
+					return (deliver);
+				}`,
+		},
+		{
+			name: "starts with INT",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					synthetic(resp.status + " error");
+				}`,
+			expected: []string{"starts with resp.status (INT), and INT + STRING is not possible"},
+			severity: SeverityError,
+		},
+		{
+			name: "unstringable piece",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					synthetic("body: " + resp.body);
+				}`,
+			expected: []string{"resp.body is BODY, which cannot be converted to STRING"},
+			severity: SeverityError,
+		},
+		{
+			name: "resp in vcl_backend_error",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_error {
+					synthetic("Error " + resp.reason);
+				}`,
+			expected: []string{"use beresp.reason"},
+			severity: SeverityInfo,
+		},
+		{
+			name: "synthetic outside synth",
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					synthetic("nope");
+				}`,
+			expected: []string{"synthetic() cannot be used in vcl_recv"},
+			severity: SeverityError,
+		},
+		{
+			name: "synthetic in custom sub",
+			vclCode: `vcl 4.1;
+				sub error_page {
+					synthetic("Error " + resp.reason);
+				}`,
+		},
+		{
+			name: "unbalanced HTML",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					set resp.http.content-type = "text/html";
+					synthetic("<html><div><h1>" + resp.reason + "</div></html>");
+				}`,
+			expected: []string{"<h1> is closed by </div>"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "HTML without Content-Type is not checked",
+			vclCode: `vcl 4.1;
+				sub vcl_synth {
+					synthetic("<div>" + resp.reason);
+				}`,
+		},
+		{
+			name: "valid JSON",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_error {
+					set beresp.http.Content-Type = "application/json";
+					synthetic("[" + beresp.status + ", 1]");
+				}`,
+		},
+		{
+			name: "invalid JSON",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_error {
+					set beresp.http.Content-Type = "application/json";
+					synthetic("[" + beresp.status + ", 1");
+				}`,
+			expected: []string{"not valid JSON"},
+			severity: SeverityWarning,
+		},
+		{
+			name: "custom sub reads variables of its callers",
+			vclCode: `vcl 4.1;
+				sub error_page {
+					synthetic({"<p>"} + std.toupper(req.url) + {"</p>"});
+				}
+				sub vcl_synth {
+					call error_page;
+				}
+				sub vcl_backend_error {
+					call error_page;
+				}`,
+			expected: []string{"synthetic body in error_page reads req.url, which cannot be read in vcl_backend_error"},
+			severity: SeverityError,
+		},
+		{
+			name: "custom sub called outside synth",
+			vclCode: `vcl 4.1;
+				sub error_page {
+					synthetic("Error");
+				}
+				sub vcl_recv {
+					call error_page;
+				}`,
+			expected: []string{"synthetic() in error_page cannot run in vcl_recv, which calls it"},
+			severity: SeverityError,
+		},
+		{
+			name: "custom sub with the Content-Type of its caller",
+			vclCode: `vcl 4.1;
+				sub error_page {
+					synthetic("{" + resp.status + "}");
+				}
+				sub vcl_synth {
+					set resp.http.Content-Type = "application/json";
+					call error_page;
+				}`,
+			expected: []string{"not valid JSON"},
+			severity: SeverityWarning,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := NewSyntheticValidator(loader, types.NewSymbolTable(), nil).Validate(program)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Severity != test.severity {
+					t.Errorf("diagnostic %d severity = %s, want %s", i, diags[i].Severity, test.severity)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLTagProblem(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{"<!DOCTYPE html><html><head><title>x</title></head><body><p>a<br>b</body></html>", ""},
+		{"<ul><li>one<li>two</ul>", ""},
+		{"<!-- <div> --><span>x</span>", ""},
+		{"<img src=x/><b>x</b>", ""},
+		{"<div>", "<div> is never closed"},
+		{"</div>", "</div> has no matching <div>"},
+		{"<b><i>x</b></i>", "<i> is closed by </b>"},
+		{"<a href=x", "tag is never closed with '>'"},
+	}
+
+	for _, test := range tests {
+		if got := htmlTagProblem(test.body); got != test.expected {
+			t.Errorf("htmlTagProblem(%q) = %q, want %q", test.body, got, test.expected)
+		}
+	}
+}
+
+func TestParseSyntheticTemplate(t *testing.T) {
+	program, err := parser.Parse(`vcl 4.1;
+		sub vcl_synth {
+			synthetic({"<h1>"} + resp.status + " " + regsub(req.http.Host, "^www\\.", "") + ("!" + resp.reason));
+		}`, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	stmt := program.Declarations[0].(*ast.SubDecl).Body.Statements[0].(*ast.SyntheticStatement)
+
+	template := ParseSyntheticTemplate(stmt.Response)
+	if got := template.Text("$"); got != "<h1>$ $!$" {
+		t.Errorf("Text = %q, want %q", got, "<h1>$ $!$")
+	}
+	if !template.Parts[0].Literal || !template.Parts[0].Long || template.Parts[2].Long {
+		t.Errorf("literal parts = %+v", template.Parts)
+	}
+
+	var names []string
+	for _, v := range template.Variables() {
+		names = append(names, v.Name)
+	}
+	if got, want := strings.Join(names, " "), "resp.status req.http.Host resp.reason"; got != want {
+		t.Errorf("Variables = %s, want %s", got, want)
+	}
+}
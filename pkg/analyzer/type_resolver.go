@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/types"
+	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// typeResolver determines the VCC type of identifiers, VCL variables and
+// VMOD calls for validators that need to type-check expressions
+type typeResolver struct {
+	loader      *metadata.MetadataLoader
+	symbolTable *types.SymbolTable
+	registry    *vmod.Registry
+}
+
+// identifierType resolves a bare identifier on the right-hand side
+func (tr *typeResolver) identifierType(name string) (vcc.VCCType, string) {
+	symbol := tr.symbolTable.Lookup(name)
+	if symbol == nil {
+		return "", ""
+	}
+
+	switch symbol.Kind {
+	case types.SymbolBackend:
+		return vcc.TypeBackend, ""
+	case types.SymbolVMODObject:
+		hint := fmt.Sprintf("'%s' is a %s.%s object", name, symbol.ModuleName, symbol.ObjectType)
+		if tr.objectHasBackendMethod(symbol) {
+			hint += fmt.Sprintf(", use %s.backend()", name)
+		}
+		return vcc.VCCType("OBJECT"), hint
+	case types.SymbolACL:
+		return vcc.TypeACL, ""
+	case types.SymbolProbe:
+		return vcc.TypeProbe, ""
+	case types.SymbolSubroutine:
+		return vcc.TypeSubroutine, ""
+	}
+	return "", ""
+}
+
+// callReturnType looks up the return type of module.function() and
+// object.method() calls in the VMOD registry
+func (tr *typeResolver) callReturnType(call *ast.CallExpression) (vcc.VCCType, string) {
+	callee, ret, _, ok := tr.callSignature(call)
+	if !ok {
+		return "", ""
+	}
+	return ret, fmt.Sprintf("%s() returns %s", callee, ret)
+}
+
+// callSignature looks up a module.function() or object.method() call in the
+// VMOD registry and returns the callee's name, return type and parameters
+func (tr *typeResolver) callSignature(call *ast.CallExpression) (string, vcc.VCCType, []vcc.Parameter, bool) {
+	if tr.registry == nil {
+		return "", "", nil, false
+	}
+	member, ok := call.Function.(*ast.MemberExpression)
+	if !ok {
+		return "", "", nil, false
+	}
+	base, ok := member.Object.(*ast.Identifier)
+	if !ok {
+		return "", "", nil, false
+	}
+	prop, ok := member.Property.(*ast.Identifier)
+	if !ok {
+		return "", "", nil, false
+	}
+
+	callee := base.Name + "." + prop.Name
+	if tr.symbolTable.IsModuleImported(base.Name) {
+		function, err := tr.registry.GetFunction(base.Name, prop.Name)
+		if err != nil {
+			return "", "", nil, false
+		}
+		return callee, function.ReturnType, function.Parameters, true
+	}
+
+	symbol := tr.symbolTable.Lookup(base.Name)
+	if symbol == nil || symbol.Kind != types.SymbolVMODObject || symbol.ModuleName == "" {
+		return "", "", nil, false
+	}
+	method, err := tr.registry.GetMethod(symbol.ModuleName, symbol.ObjectType, prop.Name)
+	if err != nil {
+		return "", "", nil, false
+	}
+	return callee, method.ReturnType, method.Parameters, true
+}
+
+// objectHasBackendMethod reports whether a VMOD object exposes .backend(),
+// as the directors do
+func (tr *typeResolver) objectHasBackendMethod(symbol *types.Symbol) bool {
+	if tr.registry == nil {
+		return false
+	}
+	method, err := tr.registry.GetMethod(symbol.ModuleName, symbol.ObjectType, "backend")
+	return err == nil && method.ReturnType == vcc.TypeBackend
+}
+
+// variableType returns the metadata type of a VCL variable, or "" if unknown
+func (tr *typeResolver) variableType(name string) string {
+	if name == "" {
+		return ""
+	}
+	variables, err := tr.loader.GetVariables()
+	if err != nil {
+		return ""
+	}
+	if v, ok := variables[name]; ok {
+		return v.Type
+	}
+	// Headers are listed by prefix, e.g. "req.http."
+	if i := strings.Index(name, ".http."); i >= 0 {
+		if v, ok := variables[name[:i+6]]; ok {
+			return v.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+// UnreferencedValidator reports backends, ACLs, probes and custom
+// subroutines that are declared but never referenced, which varnishd
+// rejects unless its vcc_err_unref parameter is off. Findings are warnings
+// by default; SetSeverity makes them errors as in varnishd.
+//
+// Uses are taken from the symbol table, so the declarations and their
+// references must have been recorded in it, as the Analyzer does.
+type UnreferencedValidator struct {
+	symbolTable *types.SymbolTable
+	severity    Severity
+	diagnostics []Diagnostic
+}
+
+// NewUnreferencedValidator creates a new unreferenced symbol validator
+func NewUnreferencedValidator(symbolTable *types.SymbolTable) *UnreferencedValidator {
+	return &UnreferencedValidator{
+		symbolTable: symbolTable,
+		severity:    SeverityWarning,
+	}
+}
+
+// SetSeverity sets the severity of the findings
+func (uv *UnreferencedValidator) SetSeverity(severity Severity) {
+	uv.severity = severity
+}
+
+// Validate reports every unreferenced declaration
+func (uv *UnreferencedValidator) Validate(program *ast.Program) []Diagnostic {
+	uv.diagnostics = nil
+
+	unusedSubs := make(map[string]bool)
+	for _, name := range NewCallGraph(program).Unreferenced() {
+		unusedSubs[name] = true
+	}
+
+	firstBackend := true
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.BackendDecl:
+			// The first backend, or the one named default, is used for
+			// requests that do not pick one
+			if d == nil {
+				continue
+			}
+			implicit := firstBackend || d.Name == "default"
+			firstBackend = false
+			if !implicit && !uv.referenced(d.Name) {
+				uv.add(d, "backend", d.Name)
+			}
+		case *ast.ACLDecl:
+			if d != nil && !uv.referenced(d.Name) {
+				uv.add(d, "ACL", d.Name)
+			}
+		case *ast.ProbeDecl:
+			// A probe named default applies to backends without one
+			if d != nil && d.Name != "default" && !uv.referenced(d.Name) {
+				uv.add(d, "probe", d.Name)
+			}
+		case *ast.SubDecl:
+			if d != nil && unusedSubs[d.Name] {
+				uv.add(d, "subroutine", d.Name)
+			}
+		}
+	}
+
+	return uv.diagnostics
+}
+
+// referenced reports whether the backend, ACL or probe called name is used
+// anywhere in the program
+func (uv *UnreferencedValidator) referenced(name string) bool {
+	symbol := uv.symbolTable.Lookup(name)
+	return symbol != nil && len(symbol.References) > 0
+}
+
+func (uv *UnreferencedValidator) add(decl ast.Declaration, kind, name string) {
+	uv.diagnostics = append(uv.diagnostics, NewDiagnostic(decl, uv.severity, "unreferenced",
+		fmt.Sprintf("%s %s is declared but never used", kind, name)))
+}
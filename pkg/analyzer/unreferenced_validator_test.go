@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+const unreferencedVCL = `vcl 4.1;
+
+probe healthcheck {
+	.url = "/health";
+}
+
+probe unused_probe {
+	.url = "/";
+}
+
+backend first {
+	.host = "127.0.0.1";
+}
+
+backend web {
+	.host = "127.0.0.2";
+	.probe = healthcheck;
+}
+
+backend spare {
+	.host = "127.0.0.3";
+}
+
+acl purgers {
+	"127.0.0.1";
+}
+
+acl unused_acl {
+	"10.0.0.0"/8;
+}
+
+sub helper {
+	set req.backend_hint = web;
+}
+
+sub unused_sub {
+	call helper;
+}
+
+sub vcl_recv {
+	if (req.method == "PURGE" && client.ip ~ purgers) {
+		return (purge);
+	}
+}
+`
+
+func unreferencedFindings(t *testing.T, level string) []Diagnostic {
+	t.Helper()
+	program, err := parser.Parse(unreferencedVCL, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	a := NewAnalyzer(nil)
+	if err := a.SetUnreferenced(level); err != nil {
+		t.Fatal(err)
+	}
+	var findings []Diagnostic
+	for _, diag := range a.AnalyzeDiagnostics(program) {
+		if diag.Code == "unreferenced" {
+			findings = append(findings, diag)
+		} else {
+			t.Errorf("Unexpected diagnostic: %s", diag.Message)
+		}
+	}
+	return findings
+}
+
+func TestUnreferencedValidator(t *testing.T) {
+	findings := unreferencedFindings(t, "")
+
+	var messages []string
+	for _, diag := range findings {
+		if diag.Severity != SeverityWarning {
+			t.Errorf("Expected a warning by default, got %s", diag.Severity)
+		}
+		messages = append(messages, diag.Message)
+	}
+	sort.Strings(messages)
+	expected := []string{
+		"ACL unused_acl is declared but never used",
+		"backend spare is declared but never used",
+		"probe unused_probe is declared but never used",
+		"subroutine unused_sub is declared but never used",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected findings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(messages, "\n"))
+	}
+}
+
+func TestUnreferencedLevels(t *testing.T) {
+	for _, diag := range unreferencedFindings(t, "error") {
+		if diag.Severity != SeverityError {
+			t.Errorf("Expected an error, got %s: %s", diag.Severity, diag.Message)
+		}
+	}
+	if findings := unreferencedFindings(t, "off"); len(findings) != 0 {
+		t.Errorf("Expected no findings when off, got %v", findings)
+	}
+	if err := NewAnalyzer(nil).SetUnreferenced("fatal"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
@@ -14,7 +14,7 @@ type VariableAccessValidator struct {
 	loader        *metadata.MetadataLoader
 	symbolTable   *types.SymbolTable
 	currentMethod string
-	errors        []string
+	diagnostics   []Diagnostic
 }
 
 // NewVariableAccessValidator creates a new variable access validator
@@ -22,13 +22,13 @@ func NewVariableAccessValidator(loader *metadata.MetadataLoader, symbolTable *ty
 	return &VariableAccessValidator{
 		loader:      loader,
 		symbolTable: symbolTable,
-		errors:      []string{},
 	}
 }
 
-// Validate validates all variable accesses in a VCL program
+// Validate validates all variable accesses in a VCL program. It returns the
+// findings as messages; Diagnostics returns them with positions.
 func (vav *VariableAccessValidator) Validate(program *ast.Program) []string {
-	vav.errors = []string{}
+	vav.diagnostics = nil
 
 	// Visit all subroutines and validate variable accesses
 	for _, decl := range program.Declarations {
@@ -38,13 +38,18 @@ func (vav *VariableAccessValidator) Validate(program *ast.Program) []string {
 		}
 	}
 
-	return vav.errors
+	return legacyMessages(vav.diagnostics)
+}
+
+// Diagnostics returns the findings of the last Validate call
+func (vav *VariableAccessValidator) Diagnostics() []Diagnostic {
+	return vav.diagnostics
 }
 
 // validateSubroutineVariableAccess validates variable accesses in a subroutine
 func (vav *VariableAccessValidator) validateSubroutineVariableAccess(sub *ast.SubDecl) {
-	// Only validate built-in VCL subroutines
-	if !isBuiltinSubroutine(sub.Name) {
+	// Only validate built-in VCL subroutines the metadata knows
+	if !isKnownBuiltin(vav.loader, sub.Name) {
 		return
 	}
 
@@ -70,9 +75,7 @@ func (vav *VariableAccessValidator) walkStatement(stmt ast.Statement) {
 		// Variable assignment - validate write access
 		varName := vav.extractVariableName(s.Variable)
 		if varName != "" {
-			if err := vav.validateVariableAccess(varName, "write", s.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
+			vav.checkAccess(varName, "write", s.Variable)
 		}
 		// Also validate read access to the value expression
 		vav.walkExpression(s.Value)
@@ -81,9 +84,7 @@ func (vav *VariableAccessValidator) walkStatement(stmt ast.Statement) {
 		// Variable unset - validate unset access
 		varName := vav.extractVariableName(s.Variable)
 		if varName != "" {
-			if err := vav.validateVariableAccess(varName, "unset", s.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
+			vav.checkAccess(varName, "unset", s.Variable)
 		}
 
 	case *ast.IfStatement:
@@ -113,16 +114,27 @@ func (vav *VariableAccessValidator) walkStatement(stmt ast.Statement) {
 		vav.walkExpression(s.Expression)
 
 	case *ast.CallStatement:
-		vav.walkExpression(s.Function)
+		// The operand of call names a subroutine, not a variable
 
 	case *ast.ReturnStatement:
-		if s.Action != nil {
+		// The argument of return (vcl(label)) names a VCL label, not a
+		// variable
+		if s.Action != nil && vclLabelCall(s.Action) == nil {
 			vav.walkExpression(s.Action)
 		}
 
 	case *ast.SyntheticStatement:
 		vav.walkExpression(s.Response)
 
+	case *ast.AddStatement:
+		if varName := vav.extractVariableName(s.Variable); varName != "" {
+			vav.checkAccess(varName, "write", s.Variable)
+		}
+		vav.walkExpression(s.Value)
+
+	case *ast.LogStatement:
+		vav.walkExpression(s.Message)
+
 	case *ast.ErrorStatement:
 		if s.Code != nil {
 			vav.walkExpression(s.Code)
@@ -145,9 +157,7 @@ func (vav *VariableAccessValidator) walkExpression(expr ast.Expression) {
 	case *ast.Identifier:
 		// Simple variable read - but skip if it's a return action, built-in function, or backend
 		if !vav.isReturnActionOrBuiltin(e.Name) && !vav.isBackendOrVMODObject(e.Name) {
-			if err := vav.validateVariableAccess(e.Name, "read", e.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
+			vav.checkAccess(e.Name, "read", e)
 		}
 
 	case *ast.MemberExpression:
@@ -158,14 +168,15 @@ func (vav *VariableAccessValidator) walkExpression(expr ast.Expression) {
 		// Member access like req.url, req.http.host
 		varName := vav.extractMemberVariableName(e)
 		if varName != "" {
-			if err := vav.validateVariableAccess(varName, "read", e.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
+			vav.checkAccess(varName, "read", e)
 		}
 
 	case *ast.CallExpression:
-		// Function call - validate arguments
-		vav.walkExpression(e.Function)
+		// Function call - validate arguments. The name of a built-in
+		// function such as regsub() is not a variable.
+		if ident, ok := e.Function.(*ast.Identifier); !ok || !vav.isBuiltinFunction(ident.Name) {
+			vav.walkExpression(e.Function)
+		}
 		for _, arg := range e.Arguments {
 			vav.walkExpression(arg)
 		}
@@ -191,9 +202,7 @@ func (vav *VariableAccessValidator) walkExpression(expr ast.Expression) {
 		// Validate write access to left side
 		varName := vav.extractVariableName(e.Left)
 		if varName != "" {
-			if err := vav.validateVariableAccess(varName, "write", e.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
+			vav.checkAccess(varName, "write", e)
 		}
 		// Validate read access to right side
 		vav.walkExpression(e.Right)
@@ -206,12 +215,8 @@ func (vav *VariableAccessValidator) walkExpression(expr ast.Expression) {
 		// Increment/decrement operations require both read and write access
 		varName := vav.extractVariableName(e.Operand)
 		if varName != "" {
-			if err := vav.validateVariableAccess(varName, "read", e.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
-			if err := vav.validateVariableAccess(varName, "write", e.StartPos.Line); err != nil {
-				vav.errors = append(vav.errors, err.Error())
-			}
+			vav.checkAccess(varName, "read", e)
+			vav.checkAccess(varName, "write", e)
 		}
 
 	// Literal expressions don't need validation
@@ -250,11 +255,15 @@ func (vav *VariableAccessValidator) isVMODAccess(expr ast.Expression) bool {
 	return false
 }
 
-// isBackendOrVMODObject checks if an identifier refers to a backend or VMOD object
+// isBackendOrVMODObject checks if an identifier refers to a declared object
+// rather than a variable: a backend, ACL, probe or VMOD object
 func (vav *VariableAccessValidator) isBackendOrVMODObject(name string) bool {
 	symbol := vav.symbolTable.Lookup(name)
 	if symbol != nil {
-		return symbol.Kind == types.SymbolBackend || symbol.Kind == types.SymbolVMODObject
+		switch symbol.Kind {
+		case types.SymbolBackend, types.SymbolACL, types.SymbolProbe, types.SymbolVMODObject, types.SymbolDictionary:
+			return true
+		}
 	}
 	return false
 }
@@ -318,24 +327,24 @@ func (vav *VariableAccessValidator) extractMemberVariableName(expr *ast.MemberEx
 	return strings.Join(parts, ".")
 }
 
-// validateVariableAccess validates variable access against metadata
-func (vav *VariableAccessValidator) validateVariableAccess(varName, accessType string, line int) error {
+// checkAccess validates variable access against metadata, reporting a
+// violation at node
+func (vav *VariableAccessValidator) checkAccess(varName, accessType string, node ast.Node) {
 	if err := vav.loader.ValidateVariableAccess(varName, vav.currentMethod, accessType); err != nil {
-		return fmt.Errorf("at line %d: %v", line, err)
+		vav.diagnostics = append(vav.diagnostics, NewDiagnostic(node, SeverityError, "variable-access", err.Error()))
 	}
-	return nil
 }
 
 // isReturnActionOrBuiltin determines if an identifier represents a VCL return action, built-in
-// function, or language keyword rather than a user variable. Uses predefined lists to avoid
-// false positive variable access violations for legitimate VCL language constructs.
+// function, or language keyword rather than a user variable. Return actions are those the
+// metadata allows in any method, so that actions of newer subroutines are recognized too.
 func (vav *VariableAccessValidator) isReturnActionOrBuiltin(name string) bool {
-	// Common return actions
-	returnActions := map[string]bool{
-		"hash": true, "lookup": true, "pass": true, "pipe": true, "fail": true,
-		"synth": true, "restart": true, "deliver": true, "fetch": true,
-		"abandon": true, "error": true, "retry": true, "miss": true,
-		"ok": true, "connect": true, "vcl": true, "purge": true,
+	if methods, err := vav.loader.GetMethods(); err == nil {
+		for _, method := range methods {
+			if method.IsValidReturnAction(name) {
+				return true
+			}
+		}
 	}
 
 	// Built-in functions and keywords
@@ -346,7 +355,14 @@ func (vav *VariableAccessValidator) isReturnActionOrBuiltin(name string) bool {
 		"else": true, "elsif": true, "sub": true, "vcl": true,
 	}
 
-	return returnActions[name] || builtins[name]
+	return builtins[name]
+}
+
+// isBuiltinFunction checks if a name refers to a built-in function such as
+// regsub() or regsuball()
+func (vav *VariableAccessValidator) isBuiltinFunction(name string) bool {
+	symbol := vav.symbolTable.Lookup(name)
+	return symbol != nil && symbol.Kind == types.SymbolFunction
 }
 
 // ValidateVariableAccesses is a convenience function to validate variable accesses in a program
@@ -150,6 +150,18 @@ func TestVariableAccessValidator_ValidateVariableAccesses(t *testing.T) {
 			`,
 			expectError: false,
 		},
+		{
+			name: "call of custom subroutine",
+			vclCode: `vcl 4.1;
+				sub normalize {
+					set req.url = "/";
+				}
+				sub vcl_recv {
+					call normalize;
+				}
+			`,
+			expectError: false,
+		},
 		{
 			name: "assignment expression",
 			vclCode: `vcl 4.1;
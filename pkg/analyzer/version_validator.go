@@ -9,23 +9,33 @@ import (
 	"github.com/perbu/vclparser/pkg/metadata"
 )
 
-// VersionValidator validates VCL version compatibility against metadata
+// VersionValidator validates VCL version compatibility against metadata.
+// With a Varnish release selected, it also reports variables and built-in
+// subroutines the release does not have.
 type VersionValidator struct {
-	loader *metadata.MetadataLoader
-	errors []string
+	loader      *metadata.MetadataLoader
+	release     metadata.Release
+	diagnostics []Diagnostic
 }
 
 // NewVersionValidator creates a new version validator
 func NewVersionValidator(loader *metadata.MetadataLoader) *VersionValidator {
 	return &VersionValidator{
 		loader: loader,
-		errors: []string{},
 	}
 }
 
-// Validate validates version compatibility for all features used in a VCL program
+// SetRelease selects the Varnish release whose variables and subroutines
+// are available. The zero release accepts everything in the metadata.
+func (vv *VersionValidator) SetRelease(release metadata.Release) {
+	vv.release = release
+}
+
+// Validate validates version compatibility for all features used in a VCL
+// program. It returns the findings as messages; Diagnostics returns them
+// with positions.
 func (vv *VersionValidator) Validate(program *ast.Program) []string {
-	vv.errors = []string{}
+	vv.diagnostics = nil
 
 	// Extract VCL version from program
 	vclVersion := vv.extractVCLVersion(program)
@@ -36,8 +46,15 @@ func (vv *VersionValidator) Validate(program *ast.Program) []string {
 
 	// Validate variable usage against version constraints
 	vv.validateVariableVersions(program, vclVersion)
+	vv.validateBackendVersions(program, vclVersion)
+	vv.validateSubroutineReleases(program)
 
-	return vv.errors
+	return legacyMessages(vv.diagnostics)
+}
+
+// Diagnostics returns the findings of the last Validate call
+func (vv *VersionValidator) Diagnostics() []Diagnostic {
+	return vv.diagnostics
 }
 
 // extractVCLVersion extracts and parses the VCL version declaration from the program AST,
@@ -53,19 +70,19 @@ func (vv *VersionValidator) extractVCLVersion(program *ast.Program) int {
 	// Handle common version formats: "4.0", "4.1", etc.
 	parts := strings.Split(version, ".")
 	if len(parts) != 2 {
-		vv.addError(fmt.Sprintf("invalid VCL version format: %s", version))
+		vv.addError(program.VCLVersion, "vcl-version", fmt.Sprintf("invalid VCL version format: %s", version))
 		return 0
 	}
 
 	major, err := strconv.Atoi(parts[0])
 	if err != nil {
-		vv.addError(fmt.Sprintf("invalid VCL major version: %s", parts[0]))
+		vv.addError(program.VCLVersion, "vcl-version", fmt.Sprintf("invalid VCL major version: %s", parts[0]))
 		return 0
 	}
 
 	minor, err := strconv.Atoi(parts[1])
 	if err != nil {
-		vv.addError(fmt.Sprintf("invalid VCL minor version: %s", parts[1]))
+		vv.addError(program.VCLVersion, "vcl-version", fmt.Sprintf("invalid VCL minor version: %s", parts[1]))
 		return 0
 	}
 
@@ -73,6 +90,29 @@ func (vv *VersionValidator) extractVCLVersion(program *ast.Program) int {
 	return major*10 + minor
 }
 
+// programVCLVersion returns the program's VCL version in metadata format
+// (40 for 4.0, 41 for 4.1). Like VersionValidator it assumes 4.0 when the
+// declaration is missing; malformed versions also give 4.0, as
+// VersionValidator reports those.
+func programVCLVersion(program *ast.Program) int {
+	if program.VCLVersion == nil {
+		return 40
+	}
+	parts := strings.Split(program.VCLVersion.Version, ".")
+	if len(parts) != 2 {
+		return 40
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 40
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 40
+	}
+	return major*10 + minor
+}
+
 // validateVariableVersions performs comprehensive version compatibility checking for all variable
 // accesses in the program against the specified VCL version. Validates that variables are available
 // in the target version and haven't been deprecated beyond the version limits.
@@ -85,6 +125,56 @@ func (vv *VersionValidator) validateVariableVersions(program *ast.Program, vclVe
 	}
 }
 
+// validateBackendVersions checks backend declaration attributes against the
+// VCL versions supporting them, such as .path, which requires VCL 4.1
+func (vv *VersionValidator) validateBackendVersions(program *ast.Program, vclVersion int) {
+	schema, err := vv.loader.GetBackendProperties()
+	if err != nil {
+		return
+	}
+	for _, decl := range program.Declarations {
+		backend, ok := decl.(*ast.BackendDecl)
+		if !ok {
+			continue
+		}
+		for _, prop := range backend.Properties {
+			if info, ok := schema[prop.Name]; ok && vclVersion < info.VersionLow {
+				vv.addError(prop, "backend-property-version", fmt.Sprintf("backend property '.%s' requires VCL version %.1f or higher (current: %.1f)",
+					prop.Name, float64(info.VersionLow)/10.0, float64(vclVersion)/10.0))
+			}
+		}
+	}
+}
+
+// validateSubroutineReleases reports built-in subroutines that the selected
+// Varnish release does not have, such as vcl_connect outside Varnish
+// Enterprise. The vcl_ names are reserved, so a subroutine the metadata does
+// not list at all is reported as unknown.
+func (vv *VersionValidator) validateSubroutineReleases(program *ast.Program) {
+	methods, err := vv.loader.GetMethods()
+	if err != nil {
+		return
+	}
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || !isBuiltinSubroutine(sub.Name) {
+			continue
+		}
+		method, ok := methods[extractMethodName(sub.Name)]
+		if !ok {
+			message := fmt.Sprintf("unknown built-in subroutine %s", sub.Name)
+			if !vv.release.IsZero() {
+				message += " for target version " + vv.release.String()
+			}
+			vv.addError(sub, "unknown-subroutine", message+"; names starting with vcl_ are reserved")
+			continue
+		}
+		if err := method.Releases.Check(vv.release); err != nil {
+			vv.addError(sub, "subroutine-release", fmt.Sprintf("subroutine %s %v", sub.Name, err))
+		}
+	}
+}
+
 // validateSubroutineVariableVersions validates variable version compatibility in a subroutine
 func (vv *VersionValidator) validateSubroutineVariableVersions(sub *ast.SubDecl, vclVersion int) {
 	// Walk the AST and find variable accesses
@@ -179,14 +269,18 @@ func (vv *VersionValidator) validateVariableVersion(expr ast.Expression, vclVers
 
 	// Check version compatibility
 	if vclVersion < variable.VersionLow {
-		vv.addError(fmt.Sprintf("variable '%s' requires VCL version %.1f or higher (current: %.1f)",
+		vv.addError(expr, "variable-version", fmt.Sprintf("variable '%s' requires VCL version %.1f or higher (current: %.1f)",
 			varName, float64(variable.VersionLow)/10.0, float64(vclVersion)/10.0))
 	}
 
 	if vclVersion > variable.VersionHigh {
-		vv.addError(fmt.Sprintf("variable '%s' is not available in VCL version %.1f (deprecated after %.1f)",
+		vv.addError(expr, "variable-version", fmt.Sprintf("variable '%s' is not available in VCL version %.1f (deprecated after %.1f)",
 			varName, float64(vclVersion)/10.0, float64(variable.VersionHigh)/10.0))
 	}
+
+	if err := variable.Releases.Check(vv.release); err != nil {
+		vv.addError(expr, "variable-release", fmt.Sprintf("variable '%s' %v", varName, err))
+	}
 }
 
 // extractVariableName extracts the variable name from an expression
@@ -253,7 +347,7 @@ func (vv *VersionValidator) normalizeDynamicVariableName(varName string) string
 	return ""
 }
 
-// addError adds an error message to the validator
-func (vv *VersionValidator) addError(message string) {
-	vv.errors = append(vv.errors, message)
+// addError adds an error spanning node to the validator
+func (vv *VersionValidator) addError(node ast.Node, code, message string) {
+	vv.diagnostics = append(vv.diagnostics, NewDiagnostic(node, SeverityError, code, message))
 }
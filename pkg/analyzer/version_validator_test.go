@@ -6,6 +6,8 @@ import (
 
 	"github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/types"
 )
 
 func TestVersionValidatorExtractVCLVersion(t *testing.T) {
@@ -118,6 +120,170 @@ func TestVersionValidatorValidateVariableVersions(t *testing.T) {
 	}
 }
 
+func TestVersionValidatorBackendProperties(t *testing.T) {
+	tests := []struct {
+		name          string
+		vclCode       string
+		errorContains string
+	}{
+		{
+			name: "path in VCL 4.1",
+			vclCode: `vcl 4.1;
+				backend app { .path = "/run/app.sock"; }`,
+		},
+		{
+			name: "path in VCL 4.0",
+			vclCode: `vcl 4.0;
+				backend app { .path = "/run/app.sock"; }`,
+			errorContains: "backend property '.path' requires VCL version 4.1 or higher (current: 4.0)",
+		},
+		{
+			name: "host in VCL 4.0",
+			vclCode: `vcl 4.0;
+				backend app { .host = "127.0.0.1"; }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parser.Parse(tt.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			errors := NewVersionValidator(metadata.New()).Validate(program)
+			if tt.errorContains == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no errors but got: %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.errorContains) {
+				t.Errorf("Expected one error containing '%s', got %v", tt.errorContains, errors)
+			}
+		})
+	}
+}
+
+func TestVersionValidatorReleases(t *testing.T) {
+	tests := []struct {
+		name          string
+		release       string
+		vclCode       string
+		errorContains string
+	}{
+		{
+			name:    "transit_buffer in 7.5",
+			release: "7.5",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.transit_buffer = 1MB; }`,
+		},
+		{
+			name:    "transit_buffer in 6.0",
+			release: "6.0",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.transit_buffer = 1MB; }`,
+			errorContains: "variable 'beresp.transit_buffer' requires Varnish 7.0 or later (target: 6.0)",
+		},
+		{
+			name:    "last_byte_timeout in trunk",
+			release: "trunk",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_fetch { set bereq.last_byte_timeout = 10s; }`,
+		},
+		{
+			name:    "vcl_connect in Varnish Cache",
+			release: "7.5",
+			vclCode: `vcl 4.1;
+				sub vcl_connect { return (connect); }`,
+			errorContains: "subroutine vcl_connect is only available in Varnish Enterprise (target: 7.5)",
+		},
+		{
+			name:    "vcl_connect in Varnish Enterprise",
+			release: "6.0-enterprise",
+			vclCode: `vcl 4.1;
+				sub vcl_connect { return (connect); }`,
+		},
+		{
+			name:    "vcl_backend_refresh in Varnish Enterprise",
+			release: "6.0-enterprise",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_refresh { set beresp.ttl = 1m; return (merge); }`,
+		},
+		{
+			name:    "unknown built-in subroutine",
+			release: "7.5",
+			vclCode: `vcl 4.1;
+				sub vcl_track { return (ok); }`,
+			errorContains: "unknown built-in subroutine vcl_track for target version 7.5",
+		},
+		{
+			name: "unknown built-in subroutine without release",
+			vclCode: `vcl 4.1;
+				sub vcl_recieve { return (hash); }`,
+			errorContains: "unknown built-in subroutine vcl_recieve; names starting with vcl_ are reserved",
+		},
+		{
+			name: "no release selected",
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.transit_buffer = 1MB; }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parser.Parse(tt.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			validator := NewVersionValidator(metadata.New())
+			if tt.release != "" {
+				release, err := metadata.ParseRelease(tt.release)
+				if err != nil {
+					t.Fatal(err)
+				}
+				validator.SetRelease(release)
+			}
+			errors := validator.Validate(program)
+			if tt.errorContains == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no errors but got: %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.errorContains) {
+				t.Errorf("Expected one error containing '%s', got %v", tt.errorContains, errors)
+			}
+		})
+	}
+}
+
+func TestVersionValidatorOverlaidSubroutine(t *testing.T) {
+	program, err := parser.Parse(`vcl 4.1;
+		sub vcl_track { set req.http.X-Tracked = "1"; return (ok); }`, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+
+	loader := metadata.New()
+	if errors := NewVersionValidator(loader).Validate(program); len(errors) != 1 {
+		t.Fatalf("Expected vcl_track to be unknown, got %v", errors)
+	}
+	if err := loader.MergeOverlay([]byte(`{"vcl_methods": {"track": {"context": "C", "allowed_returns": ["fail", "ok"]}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if errors := NewVersionValidator(loader).Validate(program); len(errors) > 0 {
+		t.Errorf("Expected the overlaid vcl_track to be known, got %v", errors)
+	}
+	if errors := NewReturnActionValidator(loader).Validate(program); len(errors) > 0 {
+		t.Errorf("Expected return (ok) to be allowed in vcl_track, got %v", errors)
+	}
+	if errors := NewVariableAccessValidator(loader, types.NewSymbolTable()).Validate(program); len(errors) > 0 {
+		t.Errorf("Expected req.http.* to be writable in client side vcl_track, got %v", errors)
+	}
+}
+
 func TestVersionValidatorNormalizeDynamicVariableName(t *testing.T) {
 	loader := metadata.New()
 	validator := NewVersionValidator(loader)
@@ -2,9 +2,12 @@ package analyzer
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/metadata"
 	"github.com/perbu/vclparser/pkg/types"
 	"github.com/perbu/vclparser/pkg/vcc"
 	"github.com/perbu/vclparser/pkg/vmod"
@@ -15,28 +18,66 @@ type VMODValidator struct {
 	ast.BaseVisitor
 	registry      *vmod.Registry
 	symbolTable   *types.SymbolTable
-	errors        []string
+	loader        *metadata.MetadataLoader
+	diagnostics   []Diagnostic
 	currentMethod string // Current VCL method context
+	callChecks    map[string][]CallCheck
+	// builtinModules are the modules of the VCL itself, which need no
+	// import and whose functions are not checked
+	builtinModules map[string]bool
+	// subroutines are the names of the subroutines of the program, which
+	// may be passed to SUB parameters before they are declared
+	subroutines map[string]bool
+	// resolutions are the VMOD calls that passed validation
+	resolutions map[*ast.CallExpression]*CallResolution
 }
 
 // NewVMODValidator creates a new VMOD validator
 func NewVMODValidator(registry *vmod.Registry, symbolTable *types.SymbolTable) *VMODValidator {
-	return &VMODValidator{
+	v := &VMODValidator{
 		registry:    registry,
 		symbolTable: symbolTable,
-		errors:      []string{},
+		loader:      metadata.New(),
+	}
+	for _, check := range DefaultCallChecks() {
+		v.RegisterCallCheck(check)
+	}
+	return v
+}
+
+// SetBuiltinModules makes the validator treat the given modules as part of
+// the VCL, as Fastly's std and table are: they need no import and calls of
+// their functions are not checked
+func (v *VMODValidator) SetBuiltinModules(modules []string) {
+	v.builtinModules = make(map[string]bool, len(modules))
+	for _, module := range modules {
+		v.builtinModules[module] = true
 	}
 }
 
-// Validate validates VMOD usage in an AST node
+// Validate validates VMOD usage in an AST node. It returns the errors as
+// messages; Diagnostics returns all findings, including the warnings of
+// call checks, with positions.
 func (v *VMODValidator) Validate(node ast.Node) []string {
-	v.errors = []string{}
+	v.diagnostics = nil
+	v.resolutions = make(map[*ast.CallExpression]*CallResolution)
 	ast.Accept(node, v)
-	return v.errors
+	return v.Errors()
 }
 
 // VisitProgram implements ast.Visitor
 func (v *VMODValidator) VisitProgram(program *ast.Program) interface{} {
+	for module := range v.builtinModules {
+		if v.symbolTable.Lookup(module) == nil {
+			_ = v.symbolTable.DefineModule(module)
+		}
+	}
+	v.subroutines = make(map[string]bool)
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub != nil {
+			v.subroutines[sub.Name] = true
+		}
+	}
 	for _, decl := range program.Declarations {
 		ast.Accept(decl, v)
 	}
@@ -50,6 +91,15 @@ func (v *VMODValidator) VisitSubDecl(sub *ast.SubDecl) interface{} {
 	v.currentMethod = sub.Name
 	defer func() { v.currentMethod = oldMethod }()
 
+	// VCL concatenates repeated definitions of built-in subroutines, so
+	// only the first one declares the symbol
+	if existing := v.symbolTable.LookupLocal(sub.Name); existing == nil {
+		_ = v.symbolTable.Define(&types.Symbol{Name: sub.Name, Kind: types.SymbolSubroutine, Type: types.Void})
+		v.declare(sub.Name, sub)
+	}
+
+	v.symbolTable.EnterScope(sub.Name)
+	defer v.symbolTable.ExitScope()
 	for _, stmt := range sub.Body.Statements {
 		ast.Accept(stmt, v)
 	}
@@ -58,16 +108,17 @@ func (v *VMODValidator) VisitSubDecl(sub *ast.SubDecl) interface{} {
 
 // VisitImportDecl implements ast.Visitor
 func (v *VMODValidator) VisitImportDecl(importDecl *ast.ImportDecl) interface{} {
-	if err := v.registry.ValidateImport(importDecl.Module); err != nil {
-		v.addError(fmt.Sprintf("import validation failed: %v", err))
+	if err := v.registry.ResolveImport(importDecl.Module, importDecl.From); err != nil {
+		v.addError(importDecl, "vmod-import", fmt.Sprintf("import validation failed: %v", err))
 		return nil
 	}
 
 	// Add module to symbol table
 	if err := v.symbolTable.DefineModule(importDecl.Module); err != nil {
-		v.addError(fmt.Sprintf("failed to register module %s: %v", importDecl.Module, err))
+		v.addError(importDecl, "duplicate-symbol", fmt.Sprintf("failed to register module %s: %v", importDecl.Module, err))
 		return nil
 	}
+	v.declare(importDecl.Module, importDecl)
 
 	// Add VMOD functions to symbol table
 	module, exists := v.registry.GetModule(importDecl.Module)
@@ -77,9 +128,11 @@ func (v *VMODValidator) VisitImportDecl(importDecl *ast.ImportDecl) interface{}
 		for _, function := range module.Functions {
 			returnType := v.convertVCCTypeToSymbolType(function.ReturnType)
 			if err := v.symbolTable.DefineVMODFunction(importDecl.Module, function.Name, returnType); err != nil {
-				v.addError(fmt.Sprintf("failed to register VMOD function %s.%s: %v",
+				v.addError(importDecl, "duplicate-symbol", fmt.Sprintf("failed to register VMOD function %s.%s: %v",
 					importDecl.Module, function.Name, err))
+				continue
 			}
+			v.declare(importDecl.Module+"."+function.Name, importDecl)
 		}
 	}
 	return nil
@@ -89,16 +142,103 @@ func (v *VMODValidator) VisitImportDecl(importDecl *ast.ImportDecl) interface{}
 func (v *VMODValidator) VisitBackendDecl(backendDecl *ast.BackendDecl) interface{} {
 	// Add backend to symbol table
 	if err := v.symbolTable.DefineBackend(backendDecl.Name); err != nil {
-		v.addError(fmt.Sprintf("failed to register backend %s: %v", backendDecl.Name, err))
+		v.addError(backendDecl, "duplicate-symbol", fmt.Sprintf("failed to register backend %s: %v", backendDecl.Name, err))
+		return nil
+	}
+	v.declare(backendDecl.Name, backendDecl)
+	return nil
+}
+
+// VisitTableDecl implements ast.Visitor
+func (v *VMODValidator) VisitTableDecl(tableDecl *ast.TableDecl) interface{} {
+	if err := v.symbolTable.DefineDictionary(tableDecl.Name); err != nil {
+		v.addError(tableDecl, "duplicate-symbol", fmt.Sprintf("failed to register table %s: %v", tableDecl.Name, err))
+		return nil
+	}
+	v.declare(tableDecl.Name, tableDecl)
+	return nil
+}
+
+// VisitDirectorDecl implements ast.Visitor. A Fastly director is used
+// where a backend is.
+func (v *VMODValidator) VisitDirectorDecl(directorDecl *ast.DirectorDecl) interface{} {
+	if err := v.symbolTable.DefineBackend(directorDecl.Name); err != nil {
+		v.addError(directorDecl, "duplicate-symbol", fmt.Sprintf("failed to register director %s: %v", directorDecl.Name, err))
+		return nil
+	}
+	v.declare(directorDecl.Name, directorDecl)
+	return nil
+}
+
+// VisitACLDecl implements ast.Visitor
+func (v *VMODValidator) VisitACLDecl(aclDecl *ast.ACLDecl) interface{} {
+	if err := v.symbolTable.DefineACL(aclDecl.Name); err != nil {
+		v.addError(aclDecl, "duplicate-symbol", fmt.Sprintf("failed to register ACL %s: %v", aclDecl.Name, err))
+		return nil
 	}
+	v.declare(aclDecl.Name, aclDecl)
 	return nil
 }
 
+// VisitProbeDecl implements ast.Visitor
+func (v *VMODValidator) VisitProbeDecl(probeDecl *ast.ProbeDecl) interface{} {
+	if err := v.symbolTable.DefineProbe(probeDecl.Name); err != nil {
+		v.addError(probeDecl, "duplicate-symbol", fmt.Sprintf("failed to register probe %s: %v", probeDecl.Name, err))
+		return nil
+	}
+	v.declare(probeDecl.Name, probeDecl)
+	return nil
+}
+
+// declare records decl as the declaration of the symbol just defined and
+// reports names that shadow built-in symbols
+func (v *VMODValidator) declare(name string, decl ast.Node) {
+	symbol := v.symbolTable.Lookup(name)
+	if symbol == nil {
+		return
+	}
+	symbol.Decl = decl
+	symbol.Position = decl.Start()
+	symbol.DefPosition = namePosition(decl)
+
+	kind := strings.ToLower(symbol.Kind.String())
+	if builtin := v.symbolTable.ShadowedBuiltin(name); builtin != nil {
+		v.addError(decl, "shadowed-builtin", fmt.Sprintf("%s %s shadows the built-in %s %s",
+			kind, name, strings.ToLower(builtin.Kind.String()), builtin.Name))
+	} else if name == "default" && symbol.Kind != types.SymbolBackend && symbol.Kind != types.SymbolProbe {
+		// "default" names the default backend and the default probe
+		v.diagnostics = append(v.diagnostics, NewDiagnostic(decl, SeverityWarning, "shadowed-builtin",
+			fmt.Sprintf("%s default is named like the default backend", kind)))
+	}
+}
+
+// namePosition returns where the declared name is written in decl
+func namePosition(decl ast.Node) lexer.Position {
+	switch d := decl.(type) {
+	case *ast.BackendDecl:
+		return d.NamePos
+	case *ast.ACLDecl:
+		return d.NamePos
+	case *ast.ProbeDecl:
+		return d.NamePos
+	case *ast.SubDecl:
+		return d.NamePos
+	case *ast.NewStatement:
+		if d.Name != nil {
+			return d.Name.Start()
+		}
+	}
+	return decl.Start()
+}
+
 // VisitCallExpression implements ast.Visitor
 func (v *VMODValidator) VisitCallExpression(callExpr *ast.CallExpression) interface{} {
 	memberExpr, ok := callExpr.Function.(*ast.MemberExpression)
 	if !ok {
 		// Not a VMOD call, visit children normally
+		if ident, ok := callExpr.Function.(*ast.Identifier); ok {
+			v.runCallChecks(ident.Name, callExpr, callExpr.Arguments)
+		}
 		ast.Accept(callExpr.Function, v)
 		for _, arg := range callExpr.Arguments {
 			ast.Accept(arg, v)
@@ -115,15 +255,15 @@ func (v *VMODValidator) VisitCallExpression(callExpr *ast.CallExpression) interf
 		objectSymbol := v.symbolTable.Lookup(objIdent.Name)
 		if objectSymbol != nil && objectSymbol.Kind == types.SymbolVMODObject {
 			// Object method call: object.method()
-			v.validateObjectMethodCall(memberExpr, callExpr.Arguments, callExpr.NamedArguments)
+			v.validateObjectMethodCall(callExpr, memberExpr)
 		} else {
 			// Treat as module function call: module.function()
 			// This will handle both known and unknown modules appropriately
-			v.validateModuleFunctionCall(memberExpr, callExpr.Arguments, callExpr.NamedArguments)
+			v.validateModuleFunctionCall(callExpr, memberExpr)
 		}
 	} else {
 		// More complex expressions - treat as object method call
-		v.validateObjectMethodCall(memberExpr, callExpr.Arguments, callExpr.NamedArguments)
+		v.validateObjectMethodCall(callExpr, memberExpr)
 	}
 
 	// Visit positional arguments
@@ -146,70 +286,83 @@ func (v *VMODValidator) VisitMemberExpression(memberExpr *ast.MemberExpression)
 	return nil
 }
 
-// validateModuleFunctionCall validates a module function call
-func (v *VMODValidator) validateModuleFunctionCall(memberExpr *ast.MemberExpression, args []ast.Expression, namedArgs map[string]ast.Expression) {
+// validateModuleFunctionCall validates a module function call and runs the
+// call checks registered for the function
+func (v *VMODValidator) validateModuleFunctionCall(callExpr *ast.CallExpression, memberExpr *ast.MemberExpression) {
+	args, namedArgs := callExpr.Arguments, callExpr.NamedArguments
 	moduleIdent := memberExpr.Object.(*ast.Identifier)
 	functionIdent, ok := memberExpr.Property.(*ast.Identifier)
 	if !ok {
-		v.addError("function name must be an identifier")
+		v.addError(memberExpr, "vmod-call", "function name must be an identifier")
 		return
 	}
 
 	moduleName := moduleIdent.Name
 	functionName := functionIdent.Name
+	if v.builtinModules[moduleName] {
+		return
+	}
 
 	// Check if module is imported
 	if !v.symbolTable.IsModuleImported(moduleName) {
-		v.addError(fmt.Sprintf("module %s is not imported", moduleName))
+		v.addError(memberExpr, "vmod-not-imported", fmt.Sprintf("module %s is not imported", moduleName))
 		return
 	}
 
 	// Get function definition to validate named arguments
 	function, err := v.registry.GetFunction(moduleName, functionName)
 	if err != nil {
-		v.addError(fmt.Sprintf("VMOD function call validation failed: %v", err))
+		v.addError(memberExpr, "vmod-call", fmt.Sprintf("VMOD function call validation failed: %v", err))
 		return
 	}
 
 	// Build complete argument list combining positional and named arguments
-	completeArgs, err := v.buildCompleteArgumentList(function, args, namedArgs)
+	completeArgs, states, err := v.buildCompleteArgumentList(function, args, namedArgs)
 	if err != nil {
-		v.addError(fmt.Sprintf("Argument validation failed: %v", err))
+		v.addError(memberExpr, "vmod-call", fmt.Sprintf("Argument validation failed: %v", err))
+		return
+	}
+	params := vcc.VCLParameters(function.Parameters)
+	if !v.validateSubArguments(moduleName+"."+functionName, params, completeArgs) {
 		return
 	}
 
 	// Validate function call with enhanced type inference
 	argTypes := v.extractArgumentTypesWithContext(moduleName, functionName, completeArgs)
 	if err := v.registry.ValidateFunctionCall(moduleName, functionName, argTypes); err != nil {
-		v.addError(fmt.Sprintf("VMOD function call validation failed: %v", err))
+		v.addError(memberExpr, "vmod-call", fmt.Sprintf("VMOD function call validation failed: %v", err))
 		return
 	}
 
 	// Validate function restrictions
-	v.validateFunctionRestrictions(moduleName, functionName)
+	v.validateFunctionRestrictions(memberExpr, moduleName, functionName)
+	v.resolve(callExpr, moduleName+"."+functionName, params, completeArgs, states)
+
+	v.runCallChecks(moduleName+"."+functionName, callExpr, completeArgs)
 }
 
 // validateObjectMethodCall validates an object method call
-func (v *VMODValidator) validateObjectMethodCall(memberExpr *ast.MemberExpression, args []ast.Expression, namedArgs map[string]ast.Expression) {
+func (v *VMODValidator) validateObjectMethodCall(callExpr *ast.CallExpression, memberExpr *ast.MemberExpression) {
+	args, namedArgs := callExpr.Arguments, callExpr.NamedArguments
 	objectIdent, ok := memberExpr.Object.(*ast.Identifier)
 	if !ok {
-		v.addError("object name must be an identifier")
+		v.addError(memberExpr, "vmod-object", "object name must be an identifier")
 		return
 	}
 
 	methodIdent, ok := memberExpr.Property.(*ast.Identifier)
 	if !ok {
-		v.addError("method name must be an identifier")
+		v.addError(memberExpr, "vmod-object", "method name must be an identifier")
 		return
 	}
 
 	objectName := objectIdent.Name
-	_ = methodIdent.Name // methodName - not used in current implementation
+	methodName := methodIdent.Name
 
 	// Look up object in symbol table
 	objectSymbol := v.symbolTable.Lookup(objectName)
 	if objectSymbol == nil {
-		v.addError(fmt.Sprintf("object %s is not defined", objectName))
+		v.addError(memberExpr, "vmod-object", fmt.Sprintf("object %s is not defined", objectName))
 		return
 	}
 
@@ -218,98 +371,75 @@ func (v *VMODValidator) validateObjectMethodCall(memberExpr *ast.MemberExpressio
 		return
 	}
 
-	// TODO: Track the object's module and type by extending the Symbol struct to store more metadata
-	// For this implementation, we'll assume the object is valid if it's in the symbol table
-}
-
-// fillPositionalArgs fills the result slice with positional arguments in their correct parameter positions.
-// This is the first phase of the two-phase argument processing that handles traditional positional arguments
-// before named arguments are processed. It validates that we don't exceed the function's parameter count.
-func (v *VMODValidator) fillPositionalArgs(result []ast.Expression, parameterUsed []bool, function *vcc.Function, positionalArgs []ast.Expression) error {
-	for i, arg := range positionalArgs {
-		if i >= len(function.Parameters) {
-			return fmt.Errorf("too many positional arguments: got %d, function accepts at most %d", len(positionalArgs), len(function.Parameters))
-		}
-		result[i] = arg
-		parameterUsed[i] = true
+	method, err := v.registry.GetMethod(objectSymbol.ModuleName, objectSymbol.ObjectType, methodName)
+	if err != nil {
+		v.addError(memberExpr, "vmod-object", fmt.Sprintf("object %s (%s.%s) has no method %s",
+			objectName, objectSymbol.ModuleName, objectSymbol.ObjectType, methodName))
+		return
 	}
-	return nil
-}
-
-// fillNamedArgs maps named arguments to their correct parameter positions by matching argument names
-// to parameter names in the function definition. This is the second phase of argument processing that
-// validates parameter names exist and prevents duplicate assignments from positional and named args.
-func (v *VMODValidator) fillNamedArgs(result []ast.Expression, parameterUsed []bool, function *vcc.Function, namedArgs map[string]ast.Expression) error {
-	for argName, argValue := range namedArgs {
-		// Find the parameter by name
-		paramIndex := -1
-		for i, param := range function.Parameters {
-			if param.Name == argName {
-				paramIndex = i
-				break
-			}
-		}
 
-		if paramIndex == -1 {
-			return fmt.Errorf("unknown argument '%s'", argName)
-		}
+	completeArgs, states, err := v.buildCompleteArgumentList(&vcc.Function{Name: methodName, Parameters: method.Parameters}, args, namedArgs)
+	if err != nil {
+		v.addError(memberExpr, "vmod-call", fmt.Sprintf("Argument validation failed: %v", err))
+		return
+	}
+	params := vcc.VCLParameters(method.Parameters)
+	if !v.validateSubArguments(objectName+"."+methodName, params, completeArgs) {
+		return
+	}
 
-		if parameterUsed[paramIndex] {
-			return fmt.Errorf("argument '%s' already provided as positional argument", argName)
+	argTypes := v.extractArgumentTypesWithParameters(completeArgs, params)
+	for i, arg := range completeArgs {
+		// Nothing is known about the type of an undefined name
+		if ident, ok := arg.(*ast.Identifier); ok && v.symbolTable.Lookup(ident.Name) == nil {
+			argTypes[i] = params[i].Type
 		}
-
-		result[paramIndex] = argValue
-		parameterUsed[paramIndex] = true
 	}
-	return nil
+	if err := method.ValidateCall(argTypes); err != nil {
+		v.addError(memberExpr, "vmod-call", fmt.Sprintf("VMOD method call validation failed: %v", err))
+		return
+	}
+
+	v.validateRestrictions(memberExpr, "method "+objectName+"."+methodName, method.Restrictions)
+	v.resolve(callExpr, objectName+"."+methodName, params, completeArgs, states)
 }
 
-// applyDefaultArgs validates that all required parameters have been provided and handles default values
-// for optional parameters. This is the final phase of argument processing that ensures function call
-// completeness. Optional parameters without values are left as nil for downstream validation.
-func (v *VMODValidator) applyDefaultArgs(result []ast.Expression, parameterUsed []bool, function *vcc.Function) error {
-	for i, param := range function.Parameters {
-		if !parameterUsed[i] {
-			if !param.Optional && param.DefaultValue == "" {
-				return fmt.Errorf("missing required argument '%s'", param.Name)
-			}
-			// For optional parameters without provided values, we could insert default expressions
-			// but for now we'll just leave them nil and let the existing validation handle it
+// validateSubArguments reports the arguments for SUB parameters that do not
+// name a subroutine of the program. It returns false if there are any.
+func (v *VMODValidator) validateSubArguments(callee string, parameters []vcc.Parameter, args []ast.Expression) bool {
+	valid := true
+	for i, arg := range args {
+		if i >= len(parameters) || parameters[i].Type != vcc.TypeSubroutine || arg == nil {
+			continue
+		}
+		param := parameters[i].Name
+		if param == "" {
+			param = strconv.Itoa(i + 1)
 		}
+		ident, ok := arg.(*ast.Identifier)
+		switch {
+		case !ok:
+			v.addError(arg, "vmod-call", fmt.Sprintf("argument %s of %s must name a subroutine", param, callee))
+		case !v.subroutines[ident.Name]:
+			v.addError(arg, "vmod-call", fmt.Sprintf("argument %s of %s: subroutine %s is not defined", param, callee, ident.Name))
+		default:
+			continue
+		}
+		valid = false
 	}
-	return nil
+	return valid
 }
 
-// buildCompleteArgumentList combines positional and named arguments into a complete, properly ordered
-// argument list that matches the function's parameter signature. Uses a three-phase approach: fill
-// positional args, map named args to positions, then validate required parameters are satisfied.
-func (v *VMODValidator) buildCompleteArgumentList(function *vcc.Function, positionalArgs []ast.Expression, namedArgs map[string]ast.Expression) ([]ast.Expression, error) {
+// buildCompleteArgumentList matches positional and named arguments to the
+// parameters of function with vcc.Bind, returning the arguments in parameter
+// order, nil for the ones left out, and how each parameter gets its value.
+// PRIV_* parameters are supplied by varnishd, so the list follows
+// vcc.VCLParameters.
+func (v *VMODValidator) buildCompleteArgumentList(function *vcc.Function, positionalArgs []ast.Expression, namedArgs map[string]ast.Expression) ([]ast.Expression, []vcc.ArgumentState, error) {
 	if function == nil {
-		return positionalArgs, nil // Fallback if no function definition available
-	}
-
-	// Create a result slice with the same capacity as the function parameters
-	result := make([]ast.Expression, len(function.Parameters))
-	parameterUsed := make([]bool, len(function.Parameters))
-
-	// Phase 1: Fill in positional arguments
-	if err := v.fillPositionalArgs(result, parameterUsed, function, positionalArgs); err != nil {
-		return nil, err
+		return positionalArgs, nil, nil // Fallback if no function definition available
 	}
-
-	// Phase 2: Fill in named arguments
-	if err := v.fillNamedArgs(result, parameterUsed, function, namedArgs); err != nil {
-		return nil, err
-	}
-
-	// Phase 3: Check for missing required parameters and apply defaults
-	if err := v.applyDefaultArgs(result, parameterUsed, function); err != nil {
-		return nil, err
-	}
-
-	// Don't trim the result - we need to maintain positional mapping
-	// The validation logic should handle nil arguments for optional parameters
-	return result, nil
+	return vcc.Bind(vcc.VCLParameters(function.Parameters), positionalArgs, namedArgs)
 }
 
 // validateNewStatement validates a VMOD object instantiation statement
@@ -318,33 +448,33 @@ func (v *VMODValidator) VisitNewStatement(newStmt *ast.NewStatement) interface{}
 	// Extract variable name being assigned
 	varName, ok := newStmt.Name.(*ast.Identifier)
 	if !ok {
-		v.addError("new statement: variable name must be an identifier")
+		v.addError(newStmt, "vmod-object", "new statement: variable name must be an identifier")
 		return nil
 	}
 
 	// Extract VMOD constructor call
 	constructorCall, ok := newStmt.Constructor.(*ast.CallExpression)
 	if !ok {
-		v.addError("new statement: constructor must be a function call")
+		v.addError(newStmt, "vmod-object", "new statement: constructor must be a function call")
 		return nil
 	}
 
 	// Extract module.object() call
 	memberExpr, ok := constructorCall.Function.(*ast.MemberExpression)
 	if !ok {
-		v.addError("new statement: constructor must be a module.object() call")
+		v.addError(newStmt, "vmod-object", "new statement: constructor must be a module.object() call")
 		return nil
 	}
 
 	moduleIdent, ok := memberExpr.Object.(*ast.Identifier)
 	if !ok {
-		v.addError("new statement: module name must be an identifier")
+		v.addError(newStmt, "vmod-object", "new statement: module name must be an identifier")
 		return nil
 	}
 
 	objectIdent, ok := memberExpr.Property.(*ast.Identifier)
 	if !ok {
-		v.addError("new statement: object name must be an identifier")
+		v.addError(newStmt, "vmod-object", "new statement: object name must be an identifier")
 		return nil
 	}
 
@@ -353,22 +483,28 @@ func (v *VMODValidator) VisitNewStatement(newStmt *ast.NewStatement) interface{}
 
 	// Check if module is imported
 	if !v.symbolTable.IsModuleImported(moduleName) {
-		v.addError(fmt.Sprintf("module %s is not imported", moduleName))
+		v.addError(newStmt, "vmod-not-imported", fmt.Sprintf("module %s is not imported", moduleName))
+		return nil
+	}
+
+	if object, err := v.registry.GetObject(moduleName, objectName); err == nil &&
+		!v.validateSubArguments(moduleName+"."+objectName, vcc.VCLParameters(object.Constructor), constructorCall.Arguments) {
 		return nil
 	}
 
 	// Validate object construction with enhanced type inference
 	argTypes := v.extractArgumentTypesWithObjectContext(moduleName, objectName, constructorCall.Arguments)
 	if err := v.registry.ValidateObjectConstruction(moduleName, objectName, argTypes); err != nil {
-		v.addError(fmt.Sprintf("VMOD object construction validation failed: %v", err))
+		v.addError(newStmt, "vmod-object", fmt.Sprintf("VMOD object construction validation failed: %v", err))
 		return nil
 	}
 
 	// Register the object instance in the symbol table
 	if err := v.symbolTable.DefineVMODObject(varName.Name, moduleName, objectName); err != nil {
-		v.addError(fmt.Sprintf("failed to register VMOD object %s: %v", varName.Name, err))
+		v.addError(newStmt, "duplicate-symbol", fmt.Sprintf("failed to register VMOD object %s: %v", varName.Name, err))
 		return nil
 	}
+	v.declare(varName.Name, newStmt)
 
 	// Visit constructor arguments for nested validation
 	for _, arg := range constructorCall.Arguments {
@@ -380,26 +516,45 @@ func (v *VMODValidator) VisitNewStatement(newStmt *ast.NewStatement) interface{}
 // validateFunctionRestrictions validates that VMOD functions are called in allowed VCL method contexts.
 // Checks function restriction metadata against the current subroutine context to ensure functions
 // are only used in appropriate VCL methods (e.g., recv, fetch, deliver, etc.).
-func (v *VMODValidator) validateFunctionRestrictions(moduleName, functionName string) {
+func (v *VMODValidator) validateFunctionRestrictions(node ast.Node, moduleName, functionName string) {
 	function, err := v.registry.GetFunction(moduleName, functionName)
 	if err != nil {
 		return // Error already reported
 	}
+	v.validateRestrictions(node, "function "+moduleName+"."+functionName, function.Restrictions)
+}
 
-	if len(function.Restrictions) == 0 {
-		return // No restrictions
+// validateRestrictions reports calls to a function or method outside the
+// contexts its $Restrict annotation allows. Custom subroutines can be called
+// from any context, so only calls in built-in subroutines are checked.
+func (v *VMODValidator) validateRestrictions(node ast.Node, callee string, restrictions []vcc.Restriction) {
+	if len(restrictions) == 0 || !isBuiltinSubroutine(v.currentMethod) {
+		return
+	}
+	methods, err := v.loader.GetMethods()
+	if err != nil {
+		return
+	}
+	info, ok := methods[extractMethodName(v.currentMethod)]
+	if !ok {
+		return // Unknown subroutines are reported elsewhere
 	}
 
-	// Check if current method is allowed
-	if v.currentMethod != "" {
-		for _, allowedMethod := range function.Restrictions {
-			if strings.EqualFold(allowedMethod, v.currentMethod) {
-				return // Method is allowed
-			}
+	var group vcc.Restriction
+	switch metadata.ContextType(info.Context) {
+	case metadata.ClientContext:
+		group = vcc.RestrictClient
+	case metadata.BackendContext:
+		group = vcc.RestrictBackend
+	case metadata.HousekeepingContext:
+		group = vcc.RestrictHousekeeping
+	}
+	for _, r := range restrictions {
+		if r.Allows(v.currentMethod, group) {
+			return
 		}
-		v.addError(fmt.Sprintf("function %s.%s cannot be used in %s context",
-			moduleName, functionName, v.currentMethod))
 	}
+	v.addError(node, "vmod-restriction", fmt.Sprintf("%s cannot be used in %s context", callee, v.currentMethod))
 }
 
 // extractArgumentTypes extracts VCC types from AST expressions
@@ -448,7 +603,7 @@ func (v *VMODValidator) extractArgumentTypesWithContext(moduleName, functionName
 		return v.extractArgumentTypes(args)
 	}
 
-	return v.extractArgumentTypesWithParameters(args, function.Parameters)
+	return v.extractArgumentTypesWithParameters(args, vcc.VCLParameters(function.Parameters))
 }
 
 // extractArgumentTypesWithObjectContext extracts VCC types from AST expressions using object constructor
@@ -462,7 +617,7 @@ func (v *VMODValidator) extractArgumentTypesWithObjectContext(moduleName, object
 		return v.extractArgumentTypes(args)
 	}
 
-	return v.extractArgumentTypesWithParameters(args, object.Constructor)
+	return v.extractArgumentTypesWithParameters(args, vcc.VCLParameters(object.Constructor))
 }
 
 // inferExpressionType infers the VCC type of an AST expression using both syntactic analysis
@@ -476,6 +631,10 @@ func (v *VMODValidator) inferExpressionType(expr ast.Expression, expectedType ..
 
 	switch e := expr.(type) {
 	case *ast.StringLiteral:
+		// VCC compiles a literal where a REGEX is expected
+		if expected == vcc.TypeRegex {
+			return vcc.TypeRegex
+		}
 		return vcc.TypeString
 	case *ast.IntegerLiteral:
 		// If we have expected type context, check if we can coerce INT to the expected type
@@ -492,6 +651,9 @@ func (v *VMODValidator) inferExpressionType(expr ast.Expression, expectedType ..
 	case *ast.Identifier:
 		// Look up identifier in symbol table first
 		symbol := v.symbolTable.Lookup(e.Name)
+		if (symbol != nil && symbol.Kind == types.SymbolSubroutine) || (symbol == nil && v.subroutines[e.Name]) {
+			return vcc.TypeSubroutine
+		}
 		if symbol != nil {
 			return v.convertSymbolTypeToVCCType(symbol.Type)
 		}
@@ -583,7 +745,7 @@ func (v *VMODValidator) inferCallExpressionReturnType(callExpr *ast.CallExpressi
 // convertVCCTypeToSymbolType converts VCC type to symbol table type
 func (v *VMODValidator) convertVCCTypeToSymbolType(vccType vcc.VCCType) types.Type {
 	switch vccType {
-	case vcc.TypeString, vcc.TypeStringList, vcc.TypeStrands:
+	case vcc.TypeString, vcc.TypeStringList, vcc.TypeStrands, vcc.TypeStringStable, vcc.TypeBody:
 		return types.String
 	case vcc.TypeInt:
 		return types.Int
@@ -637,6 +799,10 @@ func (v *VMODValidator) convertSymbolTypeToVCCType(symbolType types.Type) vcc.VC
 		return vcc.TypeVoid
 	case types.HTTP:
 		return vcc.TypeHTTP
+	case types.ACL:
+		return vcc.TypeACL
+	case types.Probe:
+		return vcc.TypeProbe
 	default:
 		return vcc.TypeString // Default
 	}
@@ -701,6 +867,8 @@ func (v *VMODValidator) inferObjectMethodReturnType(memberExpr *ast.MemberExpres
 
 // VisitBlockStatement implements ast.Visitor
 func (v *VMODValidator) VisitBlockStatement(node *ast.BlockStatement) interface{} {
+	v.symbolTable.EnterScope("block")
+	defer v.symbolTable.ExitScope()
 	for _, stmt := range node.Statements {
 		ast.Accept(stmt, v)
 	}
@@ -730,6 +898,19 @@ func (v *VMODValidator) VisitSetStatement(node *ast.SetStatement) interface{} {
 	return nil
 }
 
+// VisitAddStatement implements ast.Visitor
+func (v *VMODValidator) VisitAddStatement(node *ast.AddStatement) interface{} {
+	ast.Accept(node.Variable, v)
+	ast.Accept(node.Value, v)
+	return nil
+}
+
+// VisitLogStatement implements ast.Visitor
+func (v *VMODValidator) VisitLogStatement(node *ast.LogStatement) interface{} {
+	ast.Accept(node.Message, v)
+	return nil
+}
+
 // VisitUnsetStatement implements ast.Visitor
 func (v *VMODValidator) VisitUnsetStatement(node *ast.UnsetStatement) interface{} {
 	ast.Accept(node.Variable, v)
@@ -746,6 +927,10 @@ func (v *VMODValidator) VisitReturnStatement(node *ast.ReturnStatement) interfac
 
 // VisitCallStatement implements ast.Visitor
 func (v *VMODValidator) VisitCallStatement(node *ast.CallStatement) interface{} {
+	if ident, ok := node.Function.(*ast.Identifier); ok && !v.subroutines[ident.Name] {
+		v.addError(ident, "undefined-subroutine", fmt.Sprintf("undefined subroutine: %s", ident.Name))
+		return nil
+	}
 	ast.Accept(node.Function, v)
 	return nil
 }
@@ -763,12 +948,23 @@ func (v *VMODValidator) VisitUnaryExpression(node *ast.UnaryExpression) interfac
 	return nil
 }
 
-// addError adds a validation error
-func (v *VMODValidator) addError(message string) {
-	v.errors = append(v.errors, message)
+// addError adds a validation error spanning node
+func (v *VMODValidator) addError(node ast.Node, code, message string) {
+	v.diagnostics = append(v.diagnostics, NewDiagnostic(node, SeverityError, code, message))
 }
 
 // Errors returns all validation errors
 func (v *VMODValidator) Errors() []string {
-	return v.errors
+	var errors []Diagnostic
+	for _, diag := range v.diagnostics {
+		if diag.Severity == SeverityError {
+			errors = append(errors, diag)
+		}
+	}
+	return legacyMessages(errors)
+}
+
+// Diagnostics returns the findings of the last Validate call
+func (v *VMODValidator) Diagnostics() []Diagnostic {
+	return v.diagnostics
 }
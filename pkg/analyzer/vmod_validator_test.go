@@ -1,6 +1,9 @@
 package analyzer
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -9,6 +12,7 @@ import (
 	"github.com/perbu/vclparser/pkg/parser"
 	types2 "github.com/perbu/vclparser/pkg/types"
 	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/pkg/vmod"
 )
 
 // Use shared test utilities from test_utils.go
@@ -193,6 +197,73 @@ sub vcl_recv {
 	}
 }
 
+func TestValidateMethodCallErrors(t *testing.T) {
+	registry := setupTestRegistry(t)
+
+	tests := []struct {
+		name     string
+		calls    string
+		expected string
+	}{
+		{
+			name:  "named and optional arguments",
+			calls: `h.add_backend(web1, weight = 2.0); h.add_backend(web1); set req.http.x = h.backend(key = "a");`,
+		},
+		{
+			name:     "unknown method",
+			calls:    `h.add_backends(web1);`,
+			expected: "object h (directors.hash) has no method add_backends",
+		},
+		{
+			name:     "wrong argument type",
+			calls:    `h.add_backend(web1, "heavy");`,
+			expected: "method add_backend argument 2: expected REAL, got STRING",
+		},
+		{
+			name:     "missing argument",
+			calls:    `h.add_backend();`,
+			expected: "missing required argument",
+		},
+		{
+			name:     "unknown named argument",
+			calls:    `h.add_backend(web1, size = 2.0);`,
+			expected: "unknown argument 'size'",
+		},
+		{
+			name:     "too many arguments",
+			calls:    `h.add_backend(web1, 1.0, 2.0);`,
+			expected: "too many positional arguments",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vclCode := `vcl 4.0;
+import directors;
+
+backend web1 {
+    .host = "127.0.0.1";
+}
+
+sub vcl_init {
+    new h = directors.hash();
+    ` + test.calls + `
+}`
+			program := parseVCL(t, vclCode)
+			errors := NewVMODValidator(registry, types2.NewSymbolTable()).Validate(program)
+			if test.expected == "" {
+				if len(errors) != 0 {
+					t.Errorf("Expected no errors, got: %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], test.expected) {
+				t.Errorf("Expected one error containing %q, got: %v", test.expected, errors)
+			}
+		})
+	}
+}
+
 func TestValidateComplexVCL(t *testing.T) {
 	registry := setupTestRegistry(t)
 	symbolTable := types2.NewSymbolTable()
@@ -477,3 +548,284 @@ sub vcl_deliver {
 		})
 	}
 }
+
+func TestVMODRestrictions(t *testing.T) {
+	registry := vmod.NewRegistry()
+
+	tests := []struct {
+		name     string
+		vclCode  string
+		expected []string
+	}{
+		{
+			name: "allowed contexts",
+			vclCode: `vcl 4.1;
+import h2;
+import crypto;
+sub vcl_init {
+    new hmac = crypto.hmac_init(sha256);
+    hmac.set_key(crypto.blob("secret"));
+}
+sub vcl_recv {
+    if (h2.is()) {
+        return (pass);
+    }
+}`,
+		},
+		{
+			name: "function outside its contexts",
+			vclCode: `vcl 4.1;
+import h2;
+sub vcl_backend_fetch {
+    if (h2.is()) {
+        return (abandon);
+    }
+}`,
+			expected: []string{"function h2.is cannot be used in vcl_backend_fetch context"},
+		},
+		{
+			name: "method outside its contexts",
+			vclCode: `vcl 4.1;
+import crypto;
+sub vcl_init {
+    new hmac = crypto.hmac_init(sha256);
+}
+sub vcl_recv {
+    hmac.set_key(crypto.blob("secret"));
+}`,
+			expected: []string{"method hmac.set_key cannot be used in vcl_recv context"},
+		},
+		{
+			name: "custom subroutines are not checked",
+			vclCode: `vcl 4.1;
+import h2;
+sub is_h2 {
+    if (h2.is()) {
+        return (pass);
+    }
+}
+sub vcl_backend_fetch {
+    call is_h2;
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program := parseVCL(t, test.vclCode)
+			errors := NewVMODValidator(registry, types2.NewSymbolTable()).Validate(program)
+			if len(errors) != len(test.expected) {
+				t.Fatalf("Expected %d errors, got %d: %v", len(test.expected), len(errors), errors)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(errors[i], fragment) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errors[i], fragment)
+				}
+			}
+		})
+	}
+}
+
+// modernRegistry returns a registry with a module taking the newer VCC
+// argument types
+func modernRegistry(t *testing.T) *vmod.Registry {
+	vccFile := filepath.Join(t.TempDir(), "modern.vcc")
+	err := os.WriteFile(vccFile, []byte(`$Module modern 3 "Modern argument types"
+$ABI strict
+$Function VOID body(BODY body)
+$Function BOOL match(REGEX re, STRING s)
+$Function VOID keep(STRING_STABLE s)
+$Function VOID run(SUB s)
+$Object caller(SUB s)
+$Method VOID .call_with(SUB s)`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := vmod.NewRegistry()
+	if err := registry.LoadVCCFile(vccFile); err != nil {
+		t.Fatalf("Failed to load modern.vcc: %v", err)
+	}
+	return registry
+}
+
+func TestModernVCCTypes(t *testing.T) {
+	registry := modernRegistry(t)
+
+	tests := []struct {
+		name     string
+		call     string
+		expected string
+	}{
+		{name: "string as body", call: `modern.body("text");`},
+		{name: "regex literal", call: `if (modern.match("^/api", req.url)) {}`},
+		{name: "regex from a variable", call: `if (modern.match(req.http.X-Pattern, req.url)) {}`, expected: "expected REGEX, got STRING"},
+		{name: "stable string", call: `modern.keep("constant");`},
+		{name: "subroutine", call: `modern.run(helper);`},
+		{name: "subroutine declared later", call: `modern.run(later);`},
+		{name: "named subroutine argument", call: `modern.run(s = helper);`},
+		{name: "undefined subroutine", call: `modern.run(missing);`, expected: "argument s of modern.run: subroutine missing is not defined"},
+		{name: "string for a subroutine", call: `modern.run("helper");`, expected: "argument s of modern.run must name a subroutine"},
+		{name: "method taking a subroutine", call: `c.call_with(missing);`, expected: "argument s of c.call_with: subroutine missing is not defined"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program := parseVCL(t, `vcl 4.1;
+import modern;
+sub helper {}
+sub vcl_init {
+    new c = modern.caller(helper);
+}
+sub vcl_recv {
+    `+test.call+`
+}
+sub later {}`)
+			errors := NewVMODValidator(registry, types2.NewSymbolTable()).Validate(program)
+			if test.expected == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], test.expected) {
+				t.Errorf("Expected one error containing %q, got %v", test.expected, errors)
+			}
+		})
+	}
+}
+
+func TestSubArgumentReferences(t *testing.T) {
+	program := parseVCL(t, `vcl 4.1;
+import modern;
+sub on_miss {
+    set req.http.X-Miss = "1";
+}
+sub vcl_recv {
+    modern.run(on_miss);
+}`)
+	a := NewAnalyzer(modernRegistry(t))
+	for _, diag := range a.AnalyzeDiagnostics(program) {
+		if diag.Code == "unreferenced" {
+			t.Errorf("Expected on_miss to be referenced, got %v", diag)
+		}
+	}
+	symbol := a.GetSymbolTable().Lookup("on_miss")
+	if symbol == nil || len(symbol.References) != 1 || symbol.References[0].Sub != "vcl_recv" {
+		t.Errorf("Expected one reference to on_miss in vcl_recv, got %+v", symbol)
+	}
+}
+
+func TestPrivParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     string
+		expected string
+	}{
+		{name: "one argument", call: `std.fileread("/etc/motd")`},
+		{name: "named argument", call: `priv.lookup(key = "x")`},
+		{name: "after a PRIV parameter", call: `priv.lookup("x", 2)`},
+		{name: "PRIV parameter written", call: `std.fileread("/etc/motd", "x")`, expected: "too many positional arguments: got 2, function accepts at most 1"},
+		{name: "missing argument", call: `std.fileread()`, expected: "missing required argument"},
+	}
+
+	vccFile := filepath.Join(t.TempDir(), "priv.vcc")
+	err := os.WriteFile(vccFile, []byte(`$Module priv 3 "PRIV parameters"
+$ABI strict
+$Function STRING lookup(PRIV_TASK, STRING key, PRIV_VCL, INT n = 1)`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := vmod.NewRegistry()
+	for _, file := range []string{"../../vcclib/vmod_std.vcc", vccFile} {
+		if err := registry.LoadVCCFile(file); err != nil {
+			t.Fatalf("Failed to load %s: %v", file, err)
+		}
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program := parseVCL(t, `vcl 4.1;
+import std;
+import priv;
+sub vcl_recv {
+    set req.http.x = `+test.call+`;
+}`)
+			errors := NewVMODValidator(registry, types2.NewSymbolTable()).Validate(program)
+			if test.expected == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], test.expected) {
+				t.Errorf("Expected one error containing %q, got %v", test.expected, errors)
+			}
+		})
+	}
+}
+
+func TestCallResolution(t *testing.T) {
+	vccFile := filepath.Join(t.TempDir(), "cache.vcc")
+	err := os.WriteFile(vccFile, []byte(`$Module cache 3 "Defaulted and optional parameters"
+$ABI strict
+$Function STRING get(PRIV_TASK, STRING key, STRING fallback = "", [INT ttl])
+$Object store()
+$Method VOID .put(STRING key, [DURATION ttl], BOOL replace = 1)`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := vmod.NewRegistry()
+	if err := registry.LoadVCCFile(vccFile); err != nil {
+		t.Fatal(err)
+	}
+
+	program := parseVCL(t, `vcl 4.1;
+import cache;
+sub vcl_init {
+    new s = cache.store();
+}
+sub vcl_recv {
+    set req.http.x = cache.get("k", ttl = 10);
+    s.put(key = "k", ttl = 1m);
+    set req.http.y = cache.get();
+}`)
+	analyzer := NewAnalyzer(registry)
+	errors := analyzer.Analyze(program)
+	if len(errors) != 1 || !strings.Contains(errors[0], "missing required argument 'key'") {
+		t.Fatalf("Expected the missing argument only, got %v", errors)
+	}
+
+	var calls []*ast2.CallExpression
+	walkNodes(program, func(n ast2.Node) {
+		if call, ok := n.(*ast2.CallExpression); ok {
+			calls = append(calls, call)
+		}
+	})
+	if len(calls) != 4 {
+		t.Fatalf("Expected 4 calls, got %d", len(calls))
+	}
+	// The constructor is not resolved, nor the call that failed validation
+	if analyzer.ResolvedCall(calls[0]) != nil || analyzer.ResolvedCall(calls[3]) != nil {
+		t.Errorf("Expected no resolution for the constructor and the invalid call")
+	}
+
+	get := analyzer.ResolvedCall(calls[1])
+	if get == nil || get.Callee != "cache.get" {
+		t.Fatalf("cache.get resolution = %+v", get)
+	}
+	wantStates := []vcc.ArgumentState{vcc.ArgumentGiven, vcc.ArgumentDefaulted, vcc.ArgumentGiven}
+	if len(get.Parameters) != 3 || !slices.Equal(get.States, wantStates) || get.Arguments[1] != nil {
+		t.Errorf("cache.get states = %v, want %v", get.States, wantStates)
+	}
+	if d := get.Defaulted(); len(d) != 1 || d[0] != "fallback" {
+		t.Errorf("Defaulted() = %v, want [fallback]", d)
+	}
+
+	put := analyzer.ResolvedCall(calls[2])
+	if put == nil || put.Callee != "s.put" {
+		t.Fatalf("s.put resolution = %+v", put)
+	}
+	if d, o := put.Defaulted(), put.Omitted(); len(d) != 1 || d[0] != "replace" || len(o) != 0 {
+		t.Errorf("Defaulted(), Omitted() = %v, %v, want [replace], []", d, o)
+	}
+}
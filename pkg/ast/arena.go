@@ -0,0 +1,349 @@
+package ast
+
+import "sync"
+
+// Arena allocates the nodes of a syntax tree in blocks of the same type
+// instead of one by one, for programs that parse many configurations in
+// bulk. It cuts the number of objects the garbage collector has to track
+// by about the block size, and once the tree is released with
+// Program.Release its blocks are reused by the next parse instead of
+// being collected.
+//
+// The trade-offs: a block stays alive as long as any of its nodes is
+// referenced, so keeping a single node of a tree keeps the memory of many;
+// nodes added to a tree later, for instance by Apply, are allocated as
+// usual; and after Release, every node of the tree, including nodes held
+// elsewhere such as in diagnostics, is overwritten by later parses. Only
+// release a tree when nothing refers to it any more.
+//
+// An Arena is not safe for concurrent use; use one per parse, as the
+// parser does when parser.Config.Arena is set.
+type Arena struct {
+	programs    slab[Program]
+	decls       declSlabs
+	statements  statementSlabs
+	expressions expressionSlabs
+	fastly      fastlySlabs
+	identifiers slab[Identifier]
+	strings     slab[StringLiteral]
+	integers    slab[IntegerLiteral]
+	floats      slab[FloatLiteral]
+	booleans    slab[BooleanLiteral]
+	durations   slab[DurationLiteral]
+}
+
+// declSlabs, statementSlabs, expressionSlabs and fastlySlabs hold the
+// blocks of the declaration, statement, expression and Fastly nodes
+type declSlabs struct {
+	versions   slab[VCLVersionDecl]
+	imports    slab[ImportDecl]
+	includes   slab[IncludeDecl]
+	backends   slab[BackendDecl]
+	backendPrp slab[BackendProperty]
+	probes     slab[ProbeDecl]
+	probePrp   slab[ProbeProperty]
+	acls       slab[ACLDecl]
+	aclEntries slab[ACLEntry]
+	subs       slab[SubDecl]
+}
+
+type statementSlabs struct {
+	blocks      slab[BlockStatement]
+	expressions slab[ExpressionStatement]
+	ifs         slab[IfStatement]
+	sets        slab[SetStatement]
+	unsets      slab[UnsetStatement]
+	calls       slab[CallStatement]
+	returns     slab[ReturnStatement]
+	synthetics  slab[SyntheticStatement]
+	errors      slab[ErrorStatement]
+	restarts    slab[RestartStatement]
+	cSources    slab[CSourceStatement]
+	news        slab[NewStatement]
+}
+
+type expressionSlabs struct {
+	binaries    slab[BinaryExpression]
+	unaries     slab[UnaryExpression]
+	calls       slab[CallExpression]
+	members     slab[MemberExpression]
+	parens      slab[ParenthesizedExpression]
+	regexes     slab[RegexMatchExpression]
+	objects     slab[ObjectExpression]
+	properties  slab[Property]
+	ips         slab[IPExpression]
+	times       slab[TimeExpression]
+	errors      slab[ErrorExpression]
+	assignments slab[AssignmentExpression]
+	updates     slab[UpdateExpression]
+	arrays      slab[ArrayExpression]
+	indexes     slab[IndexExpression]
+	variables   slab[VariableExpression]
+}
+
+type fastlySlabs struct {
+	tables    slab[TableDecl]
+	entries   slab[TableEntry]
+	directors slab[DirectorDecl]
+	members   slab[DirectorBackend]
+	declares  slab[DeclareStatement]
+	adds      slab[AddStatement]
+	esis      slab[EsiStatement]
+	logs      slab[LogStatement]
+	gotos     slab[GotoStatement]
+	labels    slab[LabelStatement]
+}
+
+// slabSize is the number of nodes per block
+const slabSize = 256
+
+// slab hands out the nodes of one type from blocks of slabSize
+type slab[T any] struct {
+	blocks [][]T
+	block  int // index of the block being filled
+	used   int // nodes handed out from that block
+}
+
+func (s *slab[T]) alloc() *T {
+	if s.block == len(s.blocks) || s.used == slabSize {
+		if s.block < len(s.blocks) {
+			s.block++
+		}
+		if s.block == len(s.blocks) {
+			s.blocks = append(s.blocks, make([]T, slabSize))
+		}
+		s.used = 0
+	}
+	node := &s.blocks[s.block][s.used]
+	s.used++
+	return node
+}
+
+// reset zeroes the nodes handed out, so they no longer keep anything alive,
+// and makes the blocks available again
+func (s *slab[T]) reset() {
+	for i := 0; i < s.block && i < len(s.blocks); i++ {
+		clear(s.blocks[i])
+	}
+	if s.block < len(s.blocks) {
+		clear(s.blocks[s.block][:s.used])
+	}
+	s.block, s.used = 0, 0
+}
+
+var arenaPool = sync.Pool{New: func() any { return new(Arena) }}
+
+// NewArena returns an empty arena, reusing the blocks of a released one
+// when there is one
+func NewArena() *Arena {
+	return arenaPool.Get().(*Arena)
+}
+
+// Bind makes program.Release release the arena. The parser binds the
+// programs it builds in an arena.
+func (a *Arena) Bind(program *Program) {
+	program.arena = a
+}
+
+// Release makes the nodes of a tree built in an arena available to later
+// parses. The tree, and every node of it, must not be used afterwards. It
+// does nothing for trees not built in an arena.
+func (p *Program) Release() {
+	a := p.arena
+	if a == nil {
+		return
+	}
+	p.arena = nil
+	a.reset()
+	arenaPool.Put(a)
+}
+
+// reset makes all blocks of the arena available again
+func (a *Arena) reset() {
+	a.programs.reset()
+	d := &a.decls
+	d.versions.reset()
+	d.imports.reset()
+	d.includes.reset()
+	d.backends.reset()
+	d.backendPrp.reset()
+	d.probes.reset()
+	d.probePrp.reset()
+	d.acls.reset()
+	d.aclEntries.reset()
+	d.subs.reset()
+	s := &a.statements
+	s.blocks.reset()
+	s.expressions.reset()
+	s.ifs.reset()
+	s.sets.reset()
+	s.unsets.reset()
+	s.calls.reset()
+	s.returns.reset()
+	s.synthetics.reset()
+	s.errors.reset()
+	s.restarts.reset()
+	s.cSources.reset()
+	s.news.reset()
+	e := &a.expressions
+	e.binaries.reset()
+	e.unaries.reset()
+	e.calls.reset()
+	e.members.reset()
+	e.parens.reset()
+	e.regexes.reset()
+	e.objects.reset()
+	e.properties.reset()
+	e.ips.reset()
+	e.times.reset()
+	e.errors.reset()
+	e.assignments.reset()
+	e.updates.reset()
+	e.arrays.reset()
+	e.indexes.reset()
+	e.variables.reset()
+	f := &a.fastly
+	f.tables.reset()
+	f.entries.reset()
+	f.directors.reset()
+	f.members.reset()
+	f.declares.reset()
+	f.adds.reset()
+	f.esis.reset()
+	f.logs.reset()
+	f.gotos.reset()
+	f.labels.reset()
+	a.identifiers.reset()
+	a.strings.reset()
+	a.integers.reset()
+	a.floats.reset()
+	a.booleans.reset()
+	a.durations.reset()
+}
+
+// New returns a node holding value, allocated in a when a is not nil and
+// on the heap otherwise
+func New[T any](a *Arena, value T) *T {
+	var node *T
+	if a == nil {
+		node = new(T)
+		*node = value
+		return node
+	}
+	switch any(node).(type) {
+	case *Program:
+		node = any(a.programs.alloc()).(*T)
+	case *VCLVersionDecl:
+		node = any(a.decls.versions.alloc()).(*T)
+	case *ImportDecl:
+		node = any(a.decls.imports.alloc()).(*T)
+	case *IncludeDecl:
+		node = any(a.decls.includes.alloc()).(*T)
+	case *BackendDecl:
+		node = any(a.decls.backends.alloc()).(*T)
+	case *BackendProperty:
+		node = any(a.decls.backendPrp.alloc()).(*T)
+	case *ProbeDecl:
+		node = any(a.decls.probes.alloc()).(*T)
+	case *ProbeProperty:
+		node = any(a.decls.probePrp.alloc()).(*T)
+	case *ACLDecl:
+		node = any(a.decls.acls.alloc()).(*T)
+	case *ACLEntry:
+		node = any(a.decls.aclEntries.alloc()).(*T)
+	case *SubDecl:
+		node = any(a.decls.subs.alloc()).(*T)
+	case *BlockStatement:
+		node = any(a.statements.blocks.alloc()).(*T)
+	case *ExpressionStatement:
+		node = any(a.statements.expressions.alloc()).(*T)
+	case *IfStatement:
+		node = any(a.statements.ifs.alloc()).(*T)
+	case *SetStatement:
+		node = any(a.statements.sets.alloc()).(*T)
+	case *UnsetStatement:
+		node = any(a.statements.unsets.alloc()).(*T)
+	case *CallStatement:
+		node = any(a.statements.calls.alloc()).(*T)
+	case *ReturnStatement:
+		node = any(a.statements.returns.alloc()).(*T)
+	case *SyntheticStatement:
+		node = any(a.statements.synthetics.alloc()).(*T)
+	case *ErrorStatement:
+		node = any(a.statements.errors.alloc()).(*T)
+	case *RestartStatement:
+		node = any(a.statements.restarts.alloc()).(*T)
+	case *CSourceStatement:
+		node = any(a.statements.cSources.alloc()).(*T)
+	case *NewStatement:
+		node = any(a.statements.news.alloc()).(*T)
+	case *BinaryExpression:
+		node = any(a.expressions.binaries.alloc()).(*T)
+	case *UnaryExpression:
+		node = any(a.expressions.unaries.alloc()).(*T)
+	case *CallExpression:
+		node = any(a.expressions.calls.alloc()).(*T)
+	case *MemberExpression:
+		node = any(a.expressions.members.alloc()).(*T)
+	case *ParenthesizedExpression:
+		node = any(a.expressions.parens.alloc()).(*T)
+	case *RegexMatchExpression:
+		node = any(a.expressions.regexes.alloc()).(*T)
+	case *ObjectExpression:
+		node = any(a.expressions.objects.alloc()).(*T)
+	case *Property:
+		node = any(a.expressions.properties.alloc()).(*T)
+	case *IPExpression:
+		node = any(a.expressions.ips.alloc()).(*T)
+	case *TimeExpression:
+		node = any(a.expressions.times.alloc()).(*T)
+	case *ErrorExpression:
+		node = any(a.expressions.errors.alloc()).(*T)
+	case *AssignmentExpression:
+		node = any(a.expressions.assignments.alloc()).(*T)
+	case *UpdateExpression:
+		node = any(a.expressions.updates.alloc()).(*T)
+	case *ArrayExpression:
+		node = any(a.expressions.arrays.alloc()).(*T)
+	case *IndexExpression:
+		node = any(a.expressions.indexes.alloc()).(*T)
+	case *VariableExpression:
+		node = any(a.expressions.variables.alloc()).(*T)
+	case *TableDecl:
+		node = any(a.fastly.tables.alloc()).(*T)
+	case *TableEntry:
+		node = any(a.fastly.entries.alloc()).(*T)
+	case *DirectorDecl:
+		node = any(a.fastly.directors.alloc()).(*T)
+	case *DirectorBackend:
+		node = any(a.fastly.members.alloc()).(*T)
+	case *DeclareStatement:
+		node = any(a.fastly.declares.alloc()).(*T)
+	case *AddStatement:
+		node = any(a.fastly.adds.alloc()).(*T)
+	case *EsiStatement:
+		node = any(a.fastly.esis.alloc()).(*T)
+	case *LogStatement:
+		node = any(a.fastly.logs.alloc()).(*T)
+	case *GotoStatement:
+		node = any(a.fastly.gotos.alloc()).(*T)
+	case *LabelStatement:
+		node = any(a.fastly.labels.alloc()).(*T)
+	case *Identifier:
+		node = any(a.identifiers.alloc()).(*T)
+	case *StringLiteral:
+		node = any(a.strings.alloc()).(*T)
+	case *IntegerLiteral:
+		node = any(a.integers.alloc()).(*T)
+	case *FloatLiteral:
+		node = any(a.floats.alloc()).(*T)
+	case *BooleanLiteral:
+		node = any(a.booleans.alloc()).(*T)
+	case *DurationLiteral:
+		node = any(a.durations.alloc()).(*T)
+	default:
+		node = new(T)
+	}
+	*node = value
+	return node
+}
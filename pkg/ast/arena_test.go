@@ -0,0 +1,40 @@
+package ast
+
+import "testing"
+
+func TestArena(t *testing.T) {
+	a := NewArena()
+	program := New(a, Program{})
+	a.Bind(program)
+
+	// More identifiers than fit in one block
+	var ids []*Identifier
+	for i := 0; i < slabSize+10; i++ {
+		ids = append(ids, New(a, Identifier{Name: "x"}))
+	}
+	seen := make(map[*Identifier]bool)
+	for _, id := range ids {
+		if seen[id] || id.Name != "x" {
+			t.Fatal("Arena handed out a node twice or lost its value")
+		}
+		seen[id] = true
+	}
+
+	program.Release()
+	if ids[0].Name != "" || ids[slabSize].Name != "" {
+		t.Error("Release did not clear the nodes handed out")
+	}
+
+	// Fastly nodes have slabs of their own too
+	if esi := New(a, EsiStatement{}); a.fastly.esis.used != 1 || esi != &a.fastly.esis.blocks[0][0] {
+		t.Error("EsiStatement not allocated in the arena")
+	}
+
+	// Types without a slab of their own are allocated as usual
+	if part := New(a, DurationPart{Number: "1"}); part.Number != "1" {
+		t.Errorf("New() = %+v", part)
+	}
+	if id := New[Identifier](nil, Identifier{Name: "y"}); id.Name != "y" {
+		t.Errorf("New() without an arena = %+v", id)
+	}
+}
@@ -0,0 +1,467 @@
+// Package astjson converts VCL syntax trees to and from JSON, so tools
+// written in other languages can consume and produce them.
+//
+// A document has the form
+//
+//	{"schemaVersion": 4, "ast": NODE}
+//
+// where NODE is an object with a "type" member naming the node type, such
+// as "SubDecl" or "BinaryExpression", "start" and "end" positions of the form
+// {"line": 1, "column": 1, "offset": 0, "runeColumn": 1} when they are known, and one member
+// per field of the Go type, named like the field in lower camel case:
+// Declarations becomes "declarations" and VCLVersion "vclVersion". Fields
+// holding nodes hold NODE objects or null, slices hold arrays or null, and
+// the named arguments of a CallExpression are an object keyed by parameter
+// name.
+//
+// SchemaVersion changes whenever a change to the AST changes the documents,
+// and Unmarshal rejects documents with a newer version than it knows.
+// Version 2 added "runeColumn" to positions and "long" to string literals,
+// version 3 "returnType" to subroutines and the nodes of the Fastly dialect,
+// version 4 "keyword" to if statements.
+package astjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// SchemaVersion is the version of the documents written by Marshal
+const SchemaVersion = 4
+
+// document is the top-level object of the JSON form
+type document struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	AST           json.RawMessage `json:"ast"`
+}
+
+// nodeTypes lists every node type by the name used in the "type" member
+var nodeTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []ast.Node{
+		&ast.Program{}, &ast.VCLVersionDecl{}, &ast.ImportDecl{}, &ast.IncludeDecl{},
+		&ast.BackendDecl{}, &ast.BackendProperty{}, &ast.ProbeDecl{}, &ast.ProbeProperty{},
+		&ast.ACLDecl{}, &ast.ACLEntry{}, &ast.SubDecl{},
+		&ast.TableDecl{}, &ast.TableEntry{}, &ast.DirectorDecl{}, &ast.DirectorBackend{},
+
+		&ast.BlockStatement{}, &ast.ExpressionStatement{}, &ast.IfStatement{},
+		&ast.SetStatement{}, &ast.UnsetStatement{}, &ast.CallStatement{},
+		&ast.ReturnStatement{}, &ast.SyntheticStatement{}, &ast.ErrorStatement{},
+		&ast.RestartStatement{}, &ast.CSourceStatement{}, &ast.NewStatement{},
+		&ast.DeclareStatement{}, &ast.AddStatement{}, &ast.EsiStatement{},
+		&ast.LogStatement{}, &ast.GotoStatement{}, &ast.LabelStatement{},
+
+		&ast.BinaryExpression{}, &ast.UnaryExpression{}, &ast.CallExpression{},
+		&ast.MemberExpression{}, &ast.IndexExpression{}, &ast.ParenthesizedExpression{},
+		&ast.RegexMatchExpression{}, &ast.AssignmentExpression{}, &ast.UpdateExpression{},
+		&ast.ArrayExpression{}, &ast.ObjectExpression{}, &ast.Property{},
+		&ast.VariableExpression{}, &ast.TimeExpression{}, &ast.IPExpression{},
+		&ast.ErrorExpression{},
+
+		&ast.Identifier{}, &ast.StringLiteral{}, &ast.IntegerLiteral{},
+		&ast.FloatLiteral{}, &ast.BooleanLiteral{}, &ast.DurationLiteral{},
+	} {
+		t := reflect.TypeOf(node).Elem()
+		nodeTypes[t.Name()] = t
+	}
+}
+
+var (
+	nodeType     = reflect.TypeOf((*ast.Node)(nil)).Elem()
+	baseNodeType = reflect.TypeOf(ast.BaseNode{})
+	positionType = reflect.TypeOf(lexer.Position{})
+)
+
+// Marshal returns the JSON document for the tree rooted at node
+func Marshal(node ast.Node) ([]byte, error) {
+	value, err := encode(reflect.ValueOf(node))
+	if err != nil {
+		return nil, err
+	}
+	tree, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document{SchemaVersion: SchemaVersion, AST: tree})
+}
+
+// MarshalIndent is like Marshal but indents the document like
+// json.MarshalIndent
+func MarshalIndent(node ast.Node, prefix, indent string) ([]byte, error) {
+	data, err := Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal parses a JSON document and returns the tree it describes
+func Unmarshal(data []byte) (ast.Node, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", doc.SchemaVersion)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(doc.AST))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	v, err := decode(tree, nodeType, "ast")
+	if err != nil {
+		return nil, err
+	}
+	node, _ := v.Interface().(ast.Node)
+	return node, nil
+}
+
+// UnmarshalProgram is like Unmarshal for documents describing a Program
+func UnmarshalProgram(data []byte) (*ast.Program, error) {
+	node, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	program, ok := node.(*ast.Program)
+	if !ok {
+		return nil, fmt.Errorf("document describes %T, not a Program", node)
+	}
+	return program, nil
+}
+
+// encode converts a value of the AST into the generic form json.Marshal
+// takes
+func encode(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Interface {
+			return encode(v.Elem())
+		}
+		if v.Type().Implements(nodeType) {
+			return encodeNode(v.Elem())
+		}
+		return encode(v.Elem())
+	case reflect.Struct:
+		var obj object
+		if err := encodeFields(v, &obj); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			elem, err := encode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = elem
+		}
+		return list, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		obj := make(object, 0, len(keys))
+		for _, key := range keys {
+			elem, err := encode(v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())))
+			if err != nil {
+				return nil, err
+			}
+			obj.add(key, elem)
+		}
+		return obj, nil
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return v.Interface(), nil
+	default:
+		return nil, fmt.Errorf("cannot encode %s", v.Type())
+	}
+}
+
+func encodeNode(v reflect.Value) (interface{}, error) {
+	name := v.Type().Name()
+	if nodeTypes[name] != v.Type() {
+		return nil, fmt.Errorf("unknown node type %s", v.Type())
+	}
+	obj := object{{"type", name}}
+	if err := encodeFields(v, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func encodeFields(v reflect.Value, obj *object) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Type == baseNodeType {
+			base := v.Field(i).Interface().(ast.BaseNode)
+			if base.StartPos != (lexer.Position{}) {
+				obj.add("start", encodePosition(base.StartPos))
+			}
+			if base.EndPos != (lexer.Position{}) {
+				obj.add("end", encodePosition(base.EndPos))
+			}
+			continue
+		}
+		if field.Type == positionType {
+			obj.add(jsonName(field.Name), encodePosition(v.Field(i).Interface().(lexer.Position)))
+			continue
+		}
+		value, err := encode(v.Field(i))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", v.Type().Name(), field.Name, err)
+		}
+		obj.add(jsonName(field.Name), value)
+	}
+	return nil
+}
+
+func encodePosition(pos lexer.Position) object {
+	return object{{"line", pos.Line}, {"column", pos.Column}, {"offset", pos.Offset}, {"runeColumn", pos.RuneColumn}}
+}
+
+// object is a JSON object that keeps its members in order, so nodes are
+// written with their type first and their fields in declaration order
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+func (o *object) add(key string, value interface{}) {
+	*o = append(*o, member{key, value})
+}
+
+// MarshalJSON implements json.Marshaler
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decode converts the generic form of a JSON value into a value of type t.
+// path names the value in error messages.
+func decode(value interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(t), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected a node object", path)
+		}
+		name, _ := obj["type"].(string)
+		nt, ok := nodeTypes[name]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: unknown node type %q", path, name)
+		}
+		if !reflect.PtrTo(nt).Implements(t) {
+			return reflect.Value{}, fmt.Errorf("%s: a %s is not a valid %s", path, name, t.Name())
+		}
+		node, err := decodeStruct(obj, nt, path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.New(t).Elem()
+		v.Set(node.Addr())
+		return v, nil
+	case reflect.Ptr:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected an object", path)
+		}
+		if name, ok := obj["type"]; ok && name != t.Elem().Name() {
+			return reflect.Value{}, fmt.Errorf("%s: expected a %s, got %v", path, t.Elem().Name(), name)
+		}
+		elem, err := decodeStruct(obj, t.Elem(), path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return elem.Addr(), nil
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected an object", path)
+		}
+		return decodeStruct(obj, t, path)
+	case reflect.Slice:
+		list, ok := value.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected an array", path)
+		}
+		v := reflect.MakeSlice(t, len(list), len(list))
+		for i, elem := range list {
+			ev, err := decode(elem, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected an object", path)
+		}
+		v := reflect.MakeMapWithSize(t, len(obj))
+		for key, elem := range obj {
+			ev, err := decode(elem, t.Elem(), path+"."+key)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), ev)
+		}
+		return v, nil
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected a string", path)
+		}
+		return reflect.ValueOf(s).Convert(t), nil
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected a boolean", path)
+		}
+		return reflect.ValueOf(b).Convert(t), nil
+	case reflect.Int, reflect.Int64:
+		n, ok := value.(json.Number)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected a number", path)
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return reflect.ValueOf(i).Convert(t), nil
+	case reflect.Float64:
+		n, ok := value.(json.Number)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: expected a number", path)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return reflect.ValueOf(f).Convert(t), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("%s: cannot decode %s", path, t)
+	}
+}
+
+// decodeStruct returns an addressable struct of type t filled from obj
+func decodeStruct(obj map[string]interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Type == baseNodeType {
+			base := ast.BaseNode{}
+			var err error
+			if base.StartPos, err = decodePosition(obj["start"], path+".start"); err != nil {
+				return reflect.Value{}, err
+			}
+			if base.EndPos, err = decodePosition(obj["end"], path+".end"); err != nil {
+				return reflect.Value{}, err
+			}
+			v.Field(i).Set(reflect.ValueOf(base))
+			continue
+		}
+		name := jsonName(field.Name)
+		fv, err := decode(obj[name], field.Type, path+"."+name)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Field(i).Set(fv)
+	}
+	return v, nil
+}
+
+func decodePosition(value interface{}, path string) (lexer.Position, error) {
+	var pos lexer.Position
+	if value == nil {
+		return pos, nil
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return pos, fmt.Errorf("%s: expected a position object", path)
+	}
+	for name, dst := range map[string]*int{
+		"line": &pos.Line, "column": &pos.Column, "offset": &pos.Offset, "runeColumn": &pos.RuneColumn,
+	} {
+		if n, ok := obj[name].(json.Number); ok {
+			i, err := n.Int64()
+			if err != nil {
+				return pos, fmt.Errorf("%s.%s: %w", path, name, err)
+			}
+			*dst = int(i)
+		}
+	}
+	return pos, nil
+}
+
+// jsonName converts a Go field name to lower camel case, keeping acronyms
+// together: VCLVersion becomes vclVersion
+func jsonName(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) {
+		// The last capital starts the next word
+		n--
+	}
+	return strings.ToLower(string(runes[:n])) + string(runes[n:])
+}
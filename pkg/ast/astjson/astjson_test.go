@@ -0,0 +1,166 @@
+package astjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+const roundTripVCL = `vcl 4.1;
+
+import std;
+import directors from "/usr/lib/varnish/vmods/libvmod_directors.so";
+include +glob "conf.d/*.vcl";
+
+probe health {
+    .url = "/health";
+    .interval = 1h30m;
+}
+
+backend origin {
+    .host = "origin.example.com";
+    .port = "8080";
+    .probe = health;
+}
+
+acl purgers {
+    "localhost";
+    ! "10.0.0.0"/8;
+}
+
+sub vcl_init {
+    new rr = directors.round_robin();
+    rr.add_backend(origin);
+}
+
+sub normalize {
+    set req.url = regsub(req.url, "\?.*$", "");
+}
+
+sub vcl_recv {
+    if (req.method == "PURGE" && client.ip ~ purgers) {
+        return (purge);
+    } elsif (!req.http.host) {
+        return (synth(400, "Bad Request"));
+    }
+    set req.http.x-ttl = std.duration(req.http.x-ttl, 10s);
+    set req.ttl = -1.5s * 2;
+    if (std.fnmatch("*.png", req.url, pathname = true)) {
+        unset req.http.cookie;
+    }
+    call normalize;
+}
+`
+
+func TestRoundTrip(t *testing.T) {
+	program, err := parser.Parse(roundTripVCL, "roundtrip.vcl")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	data, err := MarshalIndent(program, "", "  ")
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	decoded, err := UnmarshalProgram(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(program, decoded) {
+		t.Errorf("Round trip changed the tree:\n%s", data)
+	}
+
+	// Marshalling is deterministic
+	again, err := MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(again) != string(data) {
+		t.Error("Marshalling the decoded tree gave a different document")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	program, err := parser.Parse("vcl 4.1;\nsub vcl_recv {\n    return (hash);\n}\n", "schema.vcl")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	data, err := Marshal(program)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var doc struct {
+		SchemaVersion int                    `json:"schemaVersion"`
+		AST           map[string]interface{} `json:"ast"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if doc.SchemaVersion != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion, doc.SchemaVersion)
+	}
+	if doc.AST["type"] != "Program" {
+		t.Errorf("Expected a Program, got %v", doc.AST["type"])
+	}
+	version := doc.AST["vclVersion"].(map[string]interface{})
+	if version["type"] != "VCLVersionDecl" || version["version"] != "4.1" {
+		t.Errorf("Unexpected vclVersion %v", version)
+	}
+	sub := doc.AST["declarations"].([]interface{})[0].(map[string]interface{})
+	if sub["type"] != "SubDecl" || sub["name"] != "vcl_recv" {
+		t.Errorf("Unexpected declaration %v", sub)
+	}
+	start := sub["start"].(map[string]interface{})
+	if start["line"] != float64(2) {
+		t.Errorf("Expected the sub to start on line 2, got %v", start)
+	}
+}
+
+func TestUnmarshalHandWritten(t *testing.T) {
+	data := `{"schemaVersion": 1, "ast": {
+		"type": "SetStatement",
+		"variable": {"type": "MemberExpression",
+			"object": {"type": "Identifier", "name": "req"},
+			"property": {"type": "Identifier", "name": "url"}},
+		"operator": "=",
+		"value": {"type": "StringLiteral", "value": "/"}
+	}}`
+	node, err := Unmarshal([]byte(data))
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	set, ok := node.(*ast.SetStatement)
+	if !ok {
+		t.Fatalf("Expected a SetStatement, got %T", node)
+	}
+	if set.Value.(*ast.StringLiteral).Value != "/" || set.Variable.(*ast.MemberExpression).Object.(*ast.Identifier).Name != "req" {
+		t.Errorf("Unexpected statement %+v", set)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"newer schema", `{"schemaVersion": 99, "ast": null}`, "unsupported schema version 99"},
+		{"unknown type", `{"schemaVersion": 1, "ast": {"type": "Nope"}}`, `unknown node type "Nope"`},
+		{"statement as expression", `{"schemaVersion": 1, "ast": {"type": "SetStatement",
+			"value": {"type": "RestartStatement"}}}`, "ast.value: a RestartStatement is not a valid Expression"},
+		{"wrong field type", `{"schemaVersion": 1, "ast": {"type": "IntegerLiteral", "value": "1"}}`, "ast.value: expected a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,562 @@
+// Package astproto converts VCL syntax trees to and from the protocol buffer
+// messages of proto/vclparser/v1, for the gRPC service and for tools that
+// exchange trees in that form.
+//
+// The conversion is lossless: ToProgram(FromProgram(p)) equals p, positions
+// included, for trees from the parser. Nodes that are nil become unset
+// messages, or messages without kind in lists, and back. As the wire format
+// does not tell an empty list or map from none, programs always get a
+// Declarations slice and calls a NamedArguments map, as the parser gives
+// them.
+package astproto
+
+import (
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	pb "github.com/perbu/vclparser/proto/vclparser/v1"
+)
+
+// FromProgram converts a syntax tree to its message. A nil program gives a
+// nil message.
+func FromProgram(program *ast.Program) *pb.Program {
+	if program == nil {
+		return nil
+	}
+	msg := &pb.Program{
+		Span:       fromSpan(program.BaseNode),
+		VclVersion: fromVersion(program.VCLVersion),
+	}
+	for _, decl := range program.Declarations {
+		msg.Declarations = append(msg.Declarations, FromDeclaration(decl))
+	}
+	return msg
+}
+
+// ToProgram converts a message back to a syntax tree. Declarations,
+// statements and expressions without a kind, such as ones of a newer
+// schema, become nil.
+func ToProgram(msg *pb.Program) *ast.Program {
+	if msg == nil {
+		return nil
+	}
+	program := &ast.Program{
+		BaseNode:     toSpan(msg.Span),
+		VCLVersion:   toVersion(msg.VclVersion),
+		Declarations: make([]ast.Declaration, 0, len(msg.Declarations)),
+	}
+	for _, decl := range msg.Declarations {
+		program.Declarations = append(program.Declarations, ToDeclaration(decl))
+	}
+	return program
+}
+
+// FromPosition converts a position
+func FromPosition(pos lexer.Position) *pb.Position {
+	return &pb.Position{
+		Line:       int32(pos.Line),
+		Column:     int32(pos.Column),
+		Offset:     int32(pos.Offset),
+		RuneColumn: int32(pos.RuneColumn),
+	}
+}
+
+// ToPosition converts a position message, the zero position for nil
+func ToPosition(msg *pb.Position) lexer.Position {
+	if msg == nil {
+		return lexer.Position{}
+	}
+	return lexer.Position{
+		Line:       int(msg.Line),
+		Column:     int(msg.Column),
+		Offset:     int(msg.Offset),
+		RuneColumn: int(msg.RuneColumn),
+	}
+}
+
+func fromSpan(base ast.BaseNode) *pb.Span {
+	return &pb.Span{Start: FromPosition(base.StartPos), End: FromPosition(base.EndPos)}
+}
+
+func toSpan(msg *pb.Span) ast.BaseNode {
+	if msg == nil {
+		return ast.BaseNode{}
+	}
+	return ast.BaseNode{StartPos: ToPosition(msg.Start), EndPos: ToPosition(msg.End)}
+}
+
+func fromVersion(decl *ast.VCLVersionDecl) *pb.VCLVersionDecl {
+	if decl == nil {
+		return nil
+	}
+	return &pb.VCLVersionDecl{Span: fromSpan(decl.BaseNode), Version: decl.Version}
+}
+
+func toVersion(msg *pb.VCLVersionDecl) *ast.VCLVersionDecl {
+	if msg == nil {
+		return nil
+	}
+	return &ast.VCLVersionDecl{BaseNode: toSpan(msg.Span), Version: msg.Version}
+}
+
+// FromDeclaration converts a declaration. A nil declaration gives a message
+// without kind.
+func FromDeclaration(decl ast.Declaration) *pb.Declaration {
+	msg := &pb.Declaration{}
+	switch d := decl.(type) {
+	case *ast.VCLVersionDecl:
+		msg.Kind = &pb.Declaration_VclVersion{VclVersion: fromVersion(d)}
+	case *ast.ImportDecl:
+		msg.Kind = &pb.Declaration_Import{Import: &pb.ImportDecl{
+			Span: fromSpan(d.BaseNode), Module: d.Module, Alias: d.Alias, From: d.From,
+		}}
+	case *ast.IncludeDecl:
+		msg.Kind = &pb.Declaration_Include{Include: &pb.IncludeDecl{
+			Span: fromSpan(d.BaseNode), Path: d.Path, Glob: d.Glob,
+		}}
+	case *ast.BackendDecl:
+		msg.Kind = &pb.Declaration_Backend{Backend: &pb.BackendDecl{
+			Span: fromSpan(d.BaseNode), Name: d.Name, NamePos: FromPosition(d.NamePos),
+			Properties: fromBackendProperties(d.Properties),
+		}}
+	case *ast.ProbeDecl:
+		probe := &pb.ProbeDecl{Span: fromSpan(d.BaseNode), Name: d.Name, NamePos: FromPosition(d.NamePos)}
+		for _, prop := range d.Properties {
+			probe.Properties = append(probe.Properties, &pb.Property{
+				Span: fromSpan(prop.BaseNode), Name: prop.Name, Value: FromExpression(prop.Value),
+			})
+		}
+		msg.Kind = &pb.Declaration_Probe{Probe: probe}
+	case *ast.ACLDecl:
+		acl := &pb.ACLDecl{Span: fromSpan(d.BaseNode), Name: d.Name, NamePos: FromPosition(d.NamePos)}
+		for _, entry := range d.Entries {
+			acl.Entries = append(acl.Entries, &pb.ACLEntry{
+				Span:      fromSpan(entry.BaseNode),
+				Negated:   entry.Negated,
+				Network:   FromExpression(entry.Network),
+				Host:      entry.Host,
+				PrefixLen: int32(entry.PrefixLen),
+				Optional:  entry.Optional,
+			})
+		}
+		msg.Kind = &pb.Declaration_Acl{Acl: acl}
+	case *ast.SubDecl:
+		msg.Kind = &pb.Declaration_Sub{Sub: &pb.SubDecl{
+			Span: fromSpan(d.BaseNode), Name: d.Name, NamePos: FromPosition(d.NamePos),
+			Body: fromBlock(d.Body), ReturnType: d.ReturnType,
+		}}
+	case *ast.TableDecl:
+		table := &pb.TableDecl{
+			Span: fromSpan(d.BaseNode), Name: d.Name, NamePos: FromPosition(d.NamePos), ValueType: d.ValueType,
+		}
+		for _, entry := range d.Entries {
+			table.Entries = append(table.Entries, &pb.TableEntry{
+				Span: fromSpan(entry.BaseNode), Key: entry.Key, Value: FromExpression(entry.Value),
+			})
+		}
+		msg.Kind = &pb.Declaration_Table{Table: table}
+	case *ast.DirectorDecl:
+		director := &pb.DirectorDecl{
+			Span: fromSpan(d.BaseNode), Name: d.Name, NamePos: FromPosition(d.NamePos), Policy: d.Policy,
+			Properties: fromBackendProperties(d.Properties),
+		}
+		for _, backend := range d.Backends {
+			director.Backends = append(director.Backends, &pb.DirectorBackend{
+				Span: fromSpan(backend.BaseNode), Properties: fromBackendProperties(backend.Properties),
+			})
+		}
+		msg.Kind = &pb.Declaration_Director{Director: director}
+	}
+	return msg
+}
+
+// ToDeclaration converts a declaration message back. A message without
+// kind gives nil.
+func ToDeclaration(msg *pb.Declaration) ast.Declaration {
+	switch k := msg.GetKind().(type) {
+	case *pb.Declaration_VclVersion:
+		return toVersion(k.VclVersion)
+	case *pb.Declaration_Import:
+		d := k.Import
+		return &ast.ImportDecl{BaseNode: toSpan(d.Span), Module: d.Module, Alias: d.Alias, From: d.From}
+	case *pb.Declaration_Include:
+		d := k.Include
+		return &ast.IncludeDecl{BaseNode: toSpan(d.Span), Path: d.Path, Glob: d.Glob}
+	case *pb.Declaration_Backend:
+		d := k.Backend
+		return &ast.BackendDecl{BaseNode: toSpan(d.Span), Name: d.Name, NamePos: ToPosition(d.NamePos), Properties: toBackendProperties(d.Properties)}
+	case *pb.Declaration_Probe:
+		d := k.Probe
+		probe := &ast.ProbeDecl{BaseNode: toSpan(d.Span), Name: d.Name, NamePos: ToPosition(d.NamePos)}
+		for _, prop := range d.Properties {
+			probe.Properties = append(probe.Properties, &ast.ProbeProperty{BaseNode: toSpan(prop.Span), Name: prop.Name, Value: ToExpression(prop.Value)})
+		}
+		return probe
+	case *pb.Declaration_Acl:
+		d := k.Acl
+		acl := &ast.ACLDecl{BaseNode: toSpan(d.Span), Name: d.Name, NamePos: ToPosition(d.NamePos)}
+		for _, entry := range d.Entries {
+			acl.Entries = append(acl.Entries, &ast.ACLEntry{
+				BaseNode:  toSpan(entry.Span),
+				Negated:   entry.Negated,
+				Network:   ToExpression(entry.Network),
+				Host:      entry.Host,
+				PrefixLen: int(entry.PrefixLen),
+				Optional:  entry.Optional,
+			})
+		}
+		return acl
+	case *pb.Declaration_Sub:
+		d := k.Sub
+		return &ast.SubDecl{BaseNode: toSpan(d.Span), Name: d.Name, NamePos: ToPosition(d.NamePos), ReturnType: d.ReturnType, Body: toBlock(d.Body)}
+	case *pb.Declaration_Table:
+		d := k.Table
+		table := &ast.TableDecl{BaseNode: toSpan(d.Span), Name: d.Name, NamePos: ToPosition(d.NamePos), ValueType: d.ValueType}
+		for _, entry := range d.Entries {
+			table.Entries = append(table.Entries, &ast.TableEntry{BaseNode: toSpan(entry.Span), Key: entry.Key, Value: ToExpression(entry.Value)})
+		}
+		return table
+	case *pb.Declaration_Director:
+		d := k.Director
+		director := &ast.DirectorDecl{
+			BaseNode: toSpan(d.Span), Name: d.Name, NamePos: ToPosition(d.NamePos), Policy: d.Policy,
+			Properties: toBackendProperties(d.Properties),
+		}
+		for _, backend := range d.Backends {
+			director.Backends = append(director.Backends, &ast.DirectorBackend{BaseNode: toSpan(backend.Span), Properties: toBackendProperties(backend.Properties)})
+		}
+		return director
+	}
+	return nil
+}
+
+func fromBackendProperties(props []*ast.BackendProperty) []*pb.Property {
+	var msgs []*pb.Property
+	for _, prop := range props {
+		msgs = append(msgs, &pb.Property{Span: fromSpan(prop.BaseNode), Name: prop.Name, Value: FromExpression(prop.Value)})
+	}
+	return msgs
+}
+
+func toBackendProperties(msgs []*pb.Property) []*ast.BackendProperty {
+	var props []*ast.BackendProperty
+	for _, msg := range msgs {
+		props = append(props, &ast.BackendProperty{BaseNode: toSpan(msg.Span), Name: msg.Name, Value: ToExpression(msg.Value)})
+	}
+	return props
+}
+
+func fromBlock(block *ast.BlockStatement) *pb.BlockStatement {
+	if block == nil {
+		return nil
+	}
+	msg := &pb.BlockStatement{Span: fromSpan(block.BaseNode)}
+	for _, stmt := range block.Statements {
+		msg.Statements = append(msg.Statements, FromStatement(stmt))
+	}
+	return msg
+}
+
+func toBlock(msg *pb.BlockStatement) *ast.BlockStatement {
+	if msg == nil {
+		return nil
+	}
+	block := &ast.BlockStatement{BaseNode: toSpan(msg.Span)}
+	for _, stmt := range msg.Statements {
+		block.Statements = append(block.Statements, ToStatement(stmt))
+	}
+	return block
+}
+
+// FromStatement converts a statement. A nil statement gives nil, so that
+// optional statements such as the else branch of an if stay unset.
+func FromStatement(stmt ast.Statement) *pb.Statement {
+	msg := &pb.Statement{}
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		if s == nil {
+			return nil
+		}
+		msg.Kind = &pb.Statement_Block{Block: fromBlock(s)}
+	case *ast.ExpressionStatement:
+		msg.Kind = &pb.Statement_Expression{Expression: &pb.ExpressionStatement{
+			Span: fromSpan(s.BaseNode), Expression: FromExpression(s.Expression),
+		}}
+	case *ast.IfStatement:
+		msg.Kind = &pb.Statement_If{If: &pb.IfStatement{
+			Span: fromSpan(s.BaseNode), Condition: FromExpression(s.Condition),
+			Then: FromStatement(s.Then), Else: FromStatement(s.Else), Keyword: s.Keyword,
+		}}
+	case *ast.SetStatement:
+		msg.Kind = &pb.Statement_Set{Set: &pb.SetStatement{
+			Span: fromSpan(s.BaseNode), Variable: FromExpression(s.Variable), Operator: s.Operator, Value: FromExpression(s.Value),
+		}}
+	case *ast.UnsetStatement:
+		msg.Kind = &pb.Statement_Unset{Unset: &pb.UnsetStatement{Span: fromSpan(s.BaseNode), Variable: FromExpression(s.Variable)}}
+	case *ast.CallStatement:
+		msg.Kind = &pb.Statement_Call{Call: &pb.CallStatement{Span: fromSpan(s.BaseNode), Function: FromExpression(s.Function)}}
+	case *ast.ReturnStatement:
+		msg.Kind = &pb.Statement_Return{Return: &pb.ReturnStatement{Span: fromSpan(s.BaseNode), Action: FromExpression(s.Action)}}
+	case *ast.SyntheticStatement:
+		msg.Kind = &pb.Statement_Synthetic{Synthetic: &pb.SyntheticStatement{Span: fromSpan(s.BaseNode), Response: FromExpression(s.Response)}}
+	case *ast.ErrorStatement:
+		msg.Kind = &pb.Statement_Error{Error: &pb.ErrorStatement{
+			Span: fromSpan(s.BaseNode), Code: FromExpression(s.Code), Response: FromExpression(s.Response),
+		}}
+	case *ast.RestartStatement:
+		msg.Kind = &pb.Statement_Restart{Restart: &pb.RestartStatement{Span: fromSpan(s.BaseNode)}}
+	case *ast.CSourceStatement:
+		msg.Kind = &pb.Statement_CSource{CSource: &pb.CSourceStatement{Span: fromSpan(s.BaseNode), Code: s.Code}}
+	case *ast.NewStatement:
+		msg.Kind = &pb.Statement_New{New: &pb.NewStatement{
+			Span: fromSpan(s.BaseNode), Name: FromExpression(s.Name), Constructor: FromExpression(s.Constructor),
+		}}
+	case *ast.DeclareStatement:
+		msg.Kind = &pb.Statement_Declare{Declare: &pb.DeclareStatement{Span: fromSpan(s.BaseNode), Variable: FromExpression(s.Variable), Type: s.Type}}
+	case *ast.AddStatement:
+		msg.Kind = &pb.Statement_Add{Add: &pb.AddStatement{
+			Span: fromSpan(s.BaseNode), Variable: FromExpression(s.Variable), Value: FromExpression(s.Value),
+		}}
+	case *ast.EsiStatement:
+		msg.Kind = &pb.Statement_Esi{Esi: &pb.EsiStatement{Span: fromSpan(s.BaseNode)}}
+	case *ast.LogStatement:
+		msg.Kind = &pb.Statement_Log{Log: &pb.LogStatement{Span: fromSpan(s.BaseNode), Message: FromExpression(s.Message)}}
+	case *ast.GotoStatement:
+		msg.Kind = &pb.Statement_Goto{Goto: &pb.GotoStatement{Span: fromSpan(s.BaseNode), Label: s.Label}}
+	case *ast.LabelStatement:
+		msg.Kind = &pb.Statement_Label{Label: &pb.LabelStatement{Span: fromSpan(s.BaseNode), Name: s.Name}}
+	default:
+		return nil
+	}
+	return msg
+}
+
+// ToStatement converts a statement message back. Nil and a message
+// without kind give nil.
+func ToStatement(msg *pb.Statement) ast.Statement {
+	switch k := msg.GetKind().(type) {
+	case *pb.Statement_Block:
+		if k.Block == nil {
+			return nil
+		}
+		return toBlock(k.Block)
+	case *pb.Statement_Expression:
+		s := k.Expression
+		return &ast.ExpressionStatement{BaseNode: toSpan(s.Span), Expression: ToExpression(s.Expression)}
+	case *pb.Statement_If:
+		s := k.If
+		return &ast.IfStatement{
+			BaseNode: toSpan(s.Span), Condition: ToExpression(s.Condition),
+			Then: ToStatement(s.Then), Else: ToStatement(s.Else), Keyword: s.Keyword,
+		}
+	case *pb.Statement_Set:
+		s := k.Set
+		return &ast.SetStatement{BaseNode: toSpan(s.Span), Variable: ToExpression(s.Variable), Operator: s.Operator, Value: ToExpression(s.Value)}
+	case *pb.Statement_Unset:
+		return &ast.UnsetStatement{BaseNode: toSpan(k.Unset.Span), Variable: ToExpression(k.Unset.Variable)}
+	case *pb.Statement_Call:
+		return &ast.CallStatement{BaseNode: toSpan(k.Call.Span), Function: ToExpression(k.Call.Function)}
+	case *pb.Statement_Return:
+		return &ast.ReturnStatement{BaseNode: toSpan(k.Return.Span), Action: ToExpression(k.Return.Action)}
+	case *pb.Statement_Synthetic:
+		return &ast.SyntheticStatement{BaseNode: toSpan(k.Synthetic.Span), Response: ToExpression(k.Synthetic.Response)}
+	case *pb.Statement_Error:
+		s := k.Error
+		return &ast.ErrorStatement{BaseNode: toSpan(s.Span), Code: ToExpression(s.Code), Response: ToExpression(s.Response)}
+	case *pb.Statement_Restart:
+		return &ast.RestartStatement{BaseNode: toSpan(k.Restart.Span)}
+	case *pb.Statement_CSource:
+		return &ast.CSourceStatement{BaseNode: toSpan(k.CSource.Span), Code: k.CSource.Code}
+	case *pb.Statement_New:
+		s := k.New
+		return &ast.NewStatement{BaseNode: toSpan(s.Span), Name: ToExpression(s.Name), Constructor: ToExpression(s.Constructor)}
+	case *pb.Statement_Declare:
+		s := k.Declare
+		return &ast.DeclareStatement{BaseNode: toSpan(s.Span), Variable: ToExpression(s.Variable), Type: s.Type}
+	case *pb.Statement_Add:
+		s := k.Add
+		return &ast.AddStatement{BaseNode: toSpan(s.Span), Variable: ToExpression(s.Variable), Value: ToExpression(s.Value)}
+	case *pb.Statement_Esi:
+		return &ast.EsiStatement{BaseNode: toSpan(k.Esi.Span)}
+	case *pb.Statement_Log:
+		return &ast.LogStatement{BaseNode: toSpan(k.Log.Span), Message: ToExpression(k.Log.Message)}
+	case *pb.Statement_Goto:
+		return &ast.GotoStatement{BaseNode: toSpan(k.Goto.Span), Label: k.Goto.Label}
+	case *pb.Statement_Label:
+		return &ast.LabelStatement{BaseNode: toSpan(k.Label.Span), Name: k.Label.Name}
+	}
+	return nil
+}
+
+// FromExpression converts an expression. A nil expression gives nil.
+func FromExpression(expr ast.Expression) *pb.Expression {
+	if expr == nil {
+		return nil
+	}
+	msg := &pb.Expression{}
+	switch e := expr.(type) {
+	case *ast.BinaryExpression:
+		msg.Kind = &pb.Expression_Binary{Binary: &pb.BinaryExpression{
+			Span: fromSpan(e.BaseNode), Left: FromExpression(e.Left), Operator: e.Operator, Right: FromExpression(e.Right),
+		}}
+	case *ast.UnaryExpression:
+		msg.Kind = &pb.Expression_Unary{Unary: &pb.UnaryExpression{
+			Span: fromSpan(e.BaseNode), Operator: e.Operator, Operand: FromExpression(e.Operand),
+		}}
+	case *ast.CallExpression:
+		call := &pb.CallExpression{Span: fromSpan(e.BaseNode), Function: FromExpression(e.Function)}
+		for _, arg := range e.Arguments {
+			call.Arguments = append(call.Arguments, FromExpression(arg))
+		}
+		if e.NamedArguments != nil {
+			call.NamedArguments = make(map[string]*pb.Expression, len(e.NamedArguments))
+			for name, arg := range e.NamedArguments {
+				call.NamedArguments[name] = FromExpression(arg)
+			}
+		}
+		msg.Kind = &pb.Expression_Call{Call: call}
+	case *ast.MemberExpression:
+		msg.Kind = &pb.Expression_Member{Member: &pb.MemberExpression{
+			Span: fromSpan(e.BaseNode), Object: FromExpression(e.Object), Property: FromExpression(e.Property),
+		}}
+	case *ast.IndexExpression:
+		msg.Kind = &pb.Expression_Index{Index: &pb.IndexExpression{
+			Span: fromSpan(e.BaseNode), Object: FromExpression(e.Object), Index: FromExpression(e.Index),
+		}}
+	case *ast.ParenthesizedExpression:
+		msg.Kind = &pb.Expression_Parenthesized{Parenthesized: &pb.ParenthesizedExpression{
+			Span: fromSpan(e.BaseNode), Expression: FromExpression(e.Expression),
+		}}
+	case *ast.RegexMatchExpression:
+		msg.Kind = &pb.Expression_RegexMatch{RegexMatch: &pb.RegexMatchExpression{
+			Span: fromSpan(e.BaseNode), Left: FromExpression(e.Left), Operator: e.Operator, Right: FromExpression(e.Right),
+		}}
+	case *ast.AssignmentExpression:
+		msg.Kind = &pb.Expression_Assignment{Assignment: &pb.AssignmentExpression{
+			Span: fromSpan(e.BaseNode), Left: FromExpression(e.Left), Operator: e.Operator, Right: FromExpression(e.Right),
+		}}
+	case *ast.UpdateExpression:
+		msg.Kind = &pb.Expression_Update{Update: &pb.UpdateExpression{
+			Span: fromSpan(e.BaseNode), Operator: e.Operator, Operand: FromExpression(e.Operand), Prefix: e.Prefix,
+		}}
+	case *ast.ArrayExpression:
+		array := &pb.ArrayExpression{Span: fromSpan(e.BaseNode)}
+		for _, element := range e.Elements {
+			array.Elements = append(array.Elements, FromExpression(element))
+		}
+		msg.Kind = &pb.Expression_Array{Array: array}
+	case *ast.ObjectExpression:
+		object := &pb.ObjectExpression{Span: fromSpan(e.BaseNode)}
+		for _, prop := range e.Properties {
+			object.Properties = append(object.Properties, &pb.ObjectProperty{
+				Span: fromSpan(prop.BaseNode), Key: FromExpression(prop.Key), Value: FromExpression(prop.Value),
+			})
+		}
+		msg.Kind = &pb.Expression_Object{Object: object}
+	case *ast.VariableExpression:
+		msg.Kind = &pb.Expression_Variable{Variable: &pb.VariableExpression{Span: fromSpan(e.BaseNode), Name: e.Name}}
+	case *ast.TimeExpression:
+		time := &pb.TimeExpression{Span: fromSpan(e.BaseNode), Value: e.Value}
+		for _, part := range e.Parts {
+			time.Parts = append(time.Parts, &pb.DurationPart{Number: part.Number, Unit: part.Unit})
+		}
+		msg.Kind = &pb.Expression_Time{Time: time}
+	case *ast.IPExpression:
+		msg.Kind = &pb.Expression_Ip{Ip: &pb.IPExpression{Span: fromSpan(e.BaseNode), Value: e.Value}}
+	case *ast.ErrorExpression:
+		msg.Kind = &pb.Expression_Error{Error: &pb.ErrorExpression{Span: fromSpan(e.BaseNode), Message: e.Message}}
+	case *ast.Identifier:
+		msg.Kind = &pb.Expression_Identifier{Identifier: &pb.Identifier{Span: fromSpan(e.BaseNode), Name: e.Name}}
+	case *ast.StringLiteral:
+		msg.Kind = &pb.Expression_String_{String_: &pb.StringLiteral{Span: fromSpan(e.BaseNode), Value: e.Value, Long: e.Long}}
+	case *ast.IntegerLiteral:
+		msg.Kind = &pb.Expression_Integer{Integer: &pb.IntegerLiteral{Span: fromSpan(e.BaseNode), Value: e.Value}}
+	case *ast.FloatLiteral:
+		msg.Kind = &pb.Expression_Float{Float: &pb.FloatLiteral{Span: fromSpan(e.BaseNode), Value: e.Value}}
+	case *ast.BooleanLiteral:
+		msg.Kind = &pb.Expression_Boolean{Boolean: &pb.BooleanLiteral{Span: fromSpan(e.BaseNode), Value: e.Value}}
+	case *ast.DurationLiteral:
+		msg.Kind = &pb.Expression_Duration{Duration: &pb.DurationLiteral{Span: fromSpan(e.BaseNode), Value: e.Value}}
+	default:
+		return nil
+	}
+	return msg
+}
+
+// ToExpression converts an expression message back. Nil and a message
+// without kind give nil.
+func ToExpression(msg *pb.Expression) ast.Expression {
+	switch k := msg.GetKind().(type) {
+	case *pb.Expression_Binary:
+		e := k.Binary
+		return &ast.BinaryExpression{BaseNode: toSpan(e.Span), Left: ToExpression(e.Left), Operator: e.Operator, Right: ToExpression(e.Right)}
+	case *pb.Expression_Unary:
+		e := k.Unary
+		return &ast.UnaryExpression{BaseNode: toSpan(e.Span), Operator: e.Operator, Operand: ToExpression(e.Operand)}
+	case *pb.Expression_Call:
+		e := k.Call
+		call := &ast.CallExpression{BaseNode: toSpan(e.Span), Function: ToExpression(e.Function)}
+		for _, arg := range e.Arguments {
+			call.Arguments = append(call.Arguments, ToExpression(arg))
+		}
+		call.NamedArguments = make(map[string]ast.Expression, len(e.NamedArguments))
+		for name, arg := range e.NamedArguments {
+			call.NamedArguments[name] = ToExpression(arg)
+		}
+		return call
+	case *pb.Expression_Member:
+		e := k.Member
+		return &ast.MemberExpression{BaseNode: toSpan(e.Span), Object: ToExpression(e.Object), Property: ToExpression(e.Property)}
+	case *pb.Expression_Index:
+		e := k.Index
+		return &ast.IndexExpression{BaseNode: toSpan(e.Span), Object: ToExpression(e.Object), Index: ToExpression(e.Index)}
+	case *pb.Expression_Parenthesized:
+		e := k.Parenthesized
+		return &ast.ParenthesizedExpression{BaseNode: toSpan(e.Span), Expression: ToExpression(e.Expression)}
+	case *pb.Expression_RegexMatch:
+		e := k.RegexMatch
+		return &ast.RegexMatchExpression{BaseNode: toSpan(e.Span), Left: ToExpression(e.Left), Operator: e.Operator, Right: ToExpression(e.Right)}
+	case *pb.Expression_Assignment:
+		e := k.Assignment
+		return &ast.AssignmentExpression{BaseNode: toSpan(e.Span), Left: ToExpression(e.Left), Operator: e.Operator, Right: ToExpression(e.Right)}
+	case *pb.Expression_Update:
+		e := k.Update
+		return &ast.UpdateExpression{BaseNode: toSpan(e.Span), Operator: e.Operator, Operand: ToExpression(e.Operand), Prefix: e.Prefix}
+	case *pb.Expression_Array:
+		array := &ast.ArrayExpression{BaseNode: toSpan(k.Array.Span)}
+		for _, element := range k.Array.Elements {
+			array.Elements = append(array.Elements, ToExpression(element))
+		}
+		return array
+	case *pb.Expression_Object:
+		object := &ast.ObjectExpression{BaseNode: toSpan(k.Object.Span)}
+		for _, prop := range k.Object.Properties {
+			object.Properties = append(object.Properties, &ast.Property{BaseNode: toSpan(prop.Span), Key: ToExpression(prop.Key), Value: ToExpression(prop.Value)})
+		}
+		return object
+	case *pb.Expression_Variable:
+		return &ast.VariableExpression{BaseNode: toSpan(k.Variable.Span), Name: k.Variable.Name}
+	case *pb.Expression_Time:
+		time := &ast.TimeExpression{BaseNode: toSpan(k.Time.Span), Value: k.Time.Value}
+		for _, part := range k.Time.Parts {
+			time.Parts = append(time.Parts, ast.DurationPart{Number: part.Number, Unit: part.Unit})
+		}
+		return time
+	case *pb.Expression_Ip:
+		return &ast.IPExpression{BaseNode: toSpan(k.Ip.Span), Value: k.Ip.Value}
+	case *pb.Expression_Error:
+		return &ast.ErrorExpression{BaseNode: toSpan(k.Error.Span), Message: k.Error.Message}
+	case *pb.Expression_Identifier:
+		return &ast.Identifier{BaseNode: toSpan(k.Identifier.Span), Name: k.Identifier.Name}
+	case *pb.Expression_String_:
+		return &ast.StringLiteral{BaseNode: toSpan(k.String_.Span), Value: k.String_.Value, Long: k.String_.Long}
+	case *pb.Expression_Integer:
+		return &ast.IntegerLiteral{BaseNode: toSpan(k.Integer.Span), Value: k.Integer.Value}
+	case *pb.Expression_Float:
+		return &ast.FloatLiteral{BaseNode: toSpan(k.Float.Span), Value: k.Float.Value}
+	case *pb.Expression_Boolean:
+		return &ast.BooleanLiteral{BaseNode: toSpan(k.Boolean.Span), Value: k.Boolean.Value}
+	case *pb.Expression_Duration:
+		return &ast.DurationLiteral{BaseNode: toSpan(k.Duration.Span), Value: k.Duration.Value}
+	}
+	return nil
+}
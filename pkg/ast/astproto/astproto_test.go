@@ -0,0 +1,130 @@
+package astproto
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+	pb "github.com/perbu/vclparser/proto/vclparser/v1"
+)
+
+const fastlyVCL = `backend F_origin {
+	.host = "origin.example.com";
+}
+
+table redirects {
+	"/old": "/new",
+}
+
+director pool random {
+	.quorum = 50%;
+	{ .backend = F_origin; .weight = 1; }
+}
+
+sub vcl_recv {
+	declare local var.host STRING;
+	add req.http.X-Added = "host=" req.http.host;
+	log "syslog " req.service_id " logger :: " req.url;
+	goto done;
+	done:
+	error 801 "redirect";
+}
+
+sub vcl_deliver {
+	esi;
+}
+`
+
+// roundTrip converts program to a message, through the wire format and back
+func roundTrip(t *testing.T, program *ast.Program) *ast.Program {
+	t.Helper()
+	data, err := proto.Marshal(FromProgram(program))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var msg pb.Program
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return ToProgram(&msg)
+}
+
+func TestRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("../../../tests/testdata/*.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	includes, err := filepath.Glob("../../../tests/testdata/includes/*.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, includes...)
+	if len(files) == 0 {
+		t.Fatal("No test files found")
+	}
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := parser.ParseDetailed(string(src), file, parser.DefaultConfig())
+			if result.Program == nil {
+				t.Fatalf("Parse failed: %v", result.Errors)
+			}
+			if decoded := roundTrip(t, result.Program); !reflect.DeepEqual(result.Program, decoded) {
+				t.Error("Round trip changed the tree")
+			}
+		})
+	}
+}
+
+func TestRoundTripFastly(t *testing.T) {
+	config := parser.DefaultConfig()
+	config.Dialect = lexer.DialectFastly
+	result := parser.ParseDetailed(fastlyVCL, "fastly.vcl", config)
+	if len(result.Errors) > 0 {
+		t.Fatalf("Parse failed: %v", result.Errors[0])
+	}
+	if decoded := roundTrip(t, result.Program); !reflect.DeepEqual(result.Program, decoded) {
+		t.Error("Round trip changed the tree")
+	}
+}
+
+func TestRoundTripElsif(t *testing.T) {
+	src := "vcl 4.1;\nsub vcl_recv {\n\tif (req.http.a) {\n\t} elsif (req.http.b) {\n\t}\n}\n"
+	result := parser.ParseDetailed(src, "elsif.vcl", parser.DefaultConfig())
+	if len(result.Errors) > 0 {
+		t.Fatalf("Parse failed: %v", result.Errors[0])
+	}
+	decoded := roundTrip(t, result.Program)
+	sub := decoded.Declarations[0].(*ast.SubDecl)
+	if elsif := sub.Body.Statements[0].(*ast.IfStatement).Else.(*ast.IfStatement); elsif.Keyword != "elsif" {
+		t.Errorf("Keyword = %q, want elsif", elsif.Keyword)
+	}
+}
+
+func TestNil(t *testing.T) {
+	if FromProgram(nil) != nil || ToProgram(nil) != nil {
+		t.Error("Expected a nil program to convert to nil")
+	}
+	if ToStatement(&pb.Statement{}) != nil || ToExpression(&pb.Expression{}) != nil || ToDeclaration(&pb.Declaration{}) != nil {
+		t.Error("Expected messages without kind to convert to nil")
+	}
+
+	// An if without else keeps its else unset
+	stmt := &ast.IfStatement{Condition: &ast.BooleanLiteral{Value: true}, Then: &ast.BlockStatement{}}
+	msg := FromStatement(stmt)
+	if msg.GetIf().Else != nil {
+		t.Errorf("Expected no else, got %v", msg.GetIf().Else)
+	}
+	if back := ToStatement(msg); !reflect.DeepEqual(stmt, back) {
+		t.Errorf("Round trip changed the statement: %#v", back)
+	}
+}
@@ -48,6 +48,35 @@ type MemberExpression struct {
 func (me *MemberExpression) String() string  { return "MemberExpression" }
 func (me *MemberExpression) expressionNode() {}
 
+// Unparen strips the parentheses around an expression, however deeply
+// nested
+func Unparen(expr Expression) Expression {
+	for {
+		paren, ok := expr.(*ParenthesizedExpression)
+		if !ok {
+			return expr
+		}
+		expr = paren.Expression
+	}
+}
+
+// VariableName joins an identifier or member expression chain into a dotted
+// name such as "req.backend_hint". It returns "" for anything else.
+func VariableName(expr Expression) string {
+	switch e := expr.(type) {
+	case *Identifier:
+		return e.Name
+	case *MemberExpression:
+		object := VariableName(e.Object)
+		prop, ok := e.Property.(*Identifier)
+		if object == "" || !ok {
+			return ""
+		}
+		return object + "." + prop.Name
+	}
+	return ""
+}
+
 // IndexExpression represents array/map indexing (e.g., headers["Host"])
 type IndexExpression struct {
 	BaseNode
@@ -139,9 +168,19 @@ func (ve *VariableExpression) expressionNode() {}
 // TimeExpression represents time literals with units
 type TimeExpression struct {
 	BaseNode
-	Value string // e.g., "10s", "5m", "1h"
+	Value string         // e.g., "10s", "5m", "1h", "1h30m"
+	Parts []DurationPart // Components of a compound literal such as "1h30m"
 }
 
+// DurationPart is one number and unit pair of a duration literal
+type DurationPart struct {
+	Number string // e.g., "1", "1.5"
+	Unit   string // e.g., "h", "ms"
+}
+
+// IsCompound reports whether the literal combines several units, like "1h30m"
+func (te *TimeExpression) IsCompound() bool { return len(te.Parts) > 1 }
+
 func (te *TimeExpression) String() string  { return "TimeExpression(" + te.Value + ")" }
 func (te *TimeExpression) expressionNode() {}
 
@@ -0,0 +1,45 @@
+package ast
+
+import "testing"
+
+func TestVariableName(t *testing.T) {
+	ident := func(name string) *Identifier { return &Identifier{Name: name} }
+	member := func(object Expression, prop string) *MemberExpression {
+		return &MemberExpression{Object: object, Property: ident(prop)}
+	}
+	tests := []struct {
+		expr Expression
+		want string
+	}{
+		{ident("origin"), "origin"},
+		{member(ident("req"), "url"), "req.url"},
+		{member(member(ident("req"), "http"), "Host"), "req.http.Host"},
+		{&MemberExpression{Object: ident("req"), Property: &StringLiteral{Value: "url"}}, ""},
+		{member(&CallExpression{Function: ident("f")}, "x"), ""},
+		{&StringLiteral{Value: "req.url"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := VariableName(tt.expr); got != tt.want {
+			t.Errorf("VariableName(%v) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestUnparen(t *testing.T) {
+	inner := &Identifier{Name: "req.url"}
+	tests := []struct {
+		expr Expression
+		want Expression
+	}{
+		{inner, inner},
+		{&ParenthesizedExpression{Expression: inner}, inner},
+		{&ParenthesizedExpression{Expression: &ParenthesizedExpression{Expression: inner}}, inner},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := Unparen(tt.expr); got != tt.want {
+			t.Errorf("Unparen(%v) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ast
+
+import "github.com/perbu/vclparser/pkg/lexer"
+
+// The nodes in this file only occur in programs parsed in the Fastly
+// dialect (parser.Config.Dialect)
+
+// TableDecl represents a Fastly edge dictionary,
+// table name [TYPE] { "key": value, ... }
+type TableDecl struct {
+	BaseNode
+	Name      string
+	NamePos   lexer.Position // position of Name
+	ValueType string         // type of the values, "" for the default STRING
+	Entries   []*TableEntry
+}
+
+func (t *TableDecl) String() string   { return "TableDecl(" + t.Name + ")" }
+func (t *TableDecl) declarationNode() {}
+
+// TableEntry represents a "key": value entry of a table
+type TableEntry struct {
+	BaseNode
+	Key   string // the key without quotes
+	Value Expression
+}
+
+func (te *TableEntry) String() string { return "TableEntry(" + te.Key + ")" }
+
+// DirectorDecl represents a Fastly director,
+// director name policy { .quorum = 50%; { .backend = F_origin; .weight = 1; } }
+type DirectorDecl struct {
+	BaseNode
+	Name       string
+	NamePos    lexer.Position // position of Name
+	Policy     string         // random, hash, client, fallback, chash or shield
+	Properties []*BackendProperty
+	Backends   []*DirectorBackend
+}
+
+func (d *DirectorDecl) String() string   { return "DirectorDecl(" + d.Name + ")" }
+func (d *DirectorDecl) declarationNode() {}
+
+// DirectorBackend represents a { .backend = F_origin; .weight = 1; } member
+// of a director. A percentage such as the 50% of .quorum is kept as written
+// in an Identifier.
+type DirectorBackend struct {
+	BaseNode
+	Properties []*BackendProperty
+}
+
+func (db *DirectorBackend) String() string { return "DirectorBackend" }
+
+// DeclareStatement represents the declaration of a local variable,
+// declare local var.name TYPE;
+type DeclareStatement struct {
+	BaseNode
+	Variable Expression // var.name
+	Type     string
+}
+
+func (ds *DeclareStatement) String() string { return "DeclareStatement" }
+func (ds *DeclareStatement) statementNode() {}
+
+// AddStatement represents add req.http.Name = value;, which adds a header
+// without replacing the ones of the same name
+type AddStatement struct {
+	BaseNode
+	Variable Expression
+	Value    Expression
+}
+
+func (as *AddStatement) String() string { return "AddStatement" }
+func (as *AddStatement) statementNode() {}
+
+// EsiStatement represents esi;, which enables ESI processing of the
+// response
+type EsiStatement struct {
+	BaseNode
+}
+
+func (es *EsiStatement) String() string { return "EsiStatement" }
+func (es *EsiStatement) statementNode() {}
+
+// LogStatement represents log message;, which sends message to the logging
+// endpoints
+type LogStatement struct {
+	BaseNode
+	Message Expression
+}
+
+func (ls *LogStatement) String() string { return "LogStatement" }
+func (ls *LogStatement) statementNode() {}
+
+// GotoStatement represents goto label;
+type GotoStatement struct {
+	BaseNode
+	Label string
+}
+
+func (gs *GotoStatement) String() string { return "GotoStatement(" + gs.Label + ")" }
+func (gs *GotoStatement) statementNode() {}
+
+// LabelStatement represents label:, the target of a goto in the same
+// subroutine
+type LabelStatement struct {
+	BaseNode
+	Name string
+}
+
+func (ls *LabelStatement) String() string { return "LabelStatement(" + ls.Name + ")" }
+func (ls *LabelStatement) statementNode() {}
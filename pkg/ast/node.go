@@ -25,6 +25,8 @@ type Program struct {
 	BaseNode
 	VCLVersion   *VCLVersionDecl
 	Declarations []Declaration
+
+	arena *Arena // the arena the tree was built in, for Release
 }
 
 func (p *Program) String() string { return "Program" }
@@ -61,6 +63,7 @@ type ImportDecl struct {
 	BaseNode
 	Module string
 	Alias  string // optional alias
+	From   string // optional path of the VMOD, from import name from "path"
 }
 
 func (i *ImportDecl) String() string   { return "ImportDecl(" + i.Module + ")" }
@@ -70,6 +73,7 @@ func (i *ImportDecl) declarationNode() {}
 type IncludeDecl struct {
 	BaseNode
 	Path string
+	Glob bool // include +glob: Path is a pattern matching the files to include
 }
 
 func (i *IncludeDecl) String() string   { return "IncludeDecl(" + i.Path + ")" }
@@ -79,6 +83,7 @@ func (i *IncludeDecl) declarationNode() {}
 type BackendDecl struct {
 	BaseNode
 	Name       string
+	NamePos    lexer.Position // position of Name
 	Properties []*BackendProperty
 }
 
@@ -98,6 +103,7 @@ func (bp *BackendProperty) String() string { return "BackendProperty(" + bp.Name
 type ProbeDecl struct {
 	BaseNode
 	Name       string
+	NamePos    lexer.Position // position of Name
 	Properties []*ProbeProperty
 }
 
@@ -117,6 +123,7 @@ func (pp *ProbeProperty) String() string { return "ProbeProperty(" + pp.Name + "
 type ACLDecl struct {
 	BaseNode
 	Name    string
+	NamePos lexer.Position // position of Name
 	Entries []*ACLEntry
 }
 
@@ -126,8 +133,11 @@ func (a *ACLDecl) declarationNode() {}
 // ACLEntry represents an entry in an ACL
 type ACLEntry struct {
 	BaseNode
-	Negated bool
-	Network Expression // IP address or CIDR
+	Negated   bool
+	Network   Expression // IP address or CIDR, as written
+	Host      string     // IP address or host name, without quotes
+	PrefixLen int        // length of the /mask, or -1 when there is none
+	Optional  bool       // parenthesized: ignored if the host name does not resolve
 }
 
 func (ae *ACLEntry) String() string { return "ACLEntry" }
@@ -135,8 +145,12 @@ func (ae *ACLEntry) String() string { return "ACLEntry" }
 // SubDecl represents a subroutine declaration
 type SubDecl struct {
 	BaseNode
-	Name string
-	Body *BlockStatement
+	Name    string
+	NamePos lexer.Position // position of Name
+	// ReturnType is the type a Fastly subroutine returns, as in
+	// sub name STRING { ... }, and "" for subroutines without a value
+	ReturnType string
+	Body       *BlockStatement
 }
 
 func (s *SubDecl) String() string   { return "SubDecl(" + s.Name + ")" }
@@ -151,10 +165,14 @@ type Identifier struct {
 func (i *Identifier) String() string  { return "Identifier(" + i.Name + ")" }
 func (i *Identifier) expressionNode() {}
 
-// StringLiteral represents a string literal
+// StringLiteral represents a string literal. Value is the text between the
+// delimiters as written.
 type StringLiteral struct {
 	BaseNode
 	Value string
+	// Long is set for long strings, written {"..."} or """...""", which
+	// may span lines and contain double quotes
+	Long bool
 }
 
 func (s *StringLiteral) String() string  { return "StringLiteral(" + s.Value + ")" }
@@ -0,0 +1,338 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ApplyFunc is called by Apply for each node, before or after its children,
+// with a Cursor describing the node and offering operations on it. Returning
+// false from the pre function skips the children of the node and the post
+// function; returning false from the post function stops the traversal.
+type ApplyFunc func(*Cursor) bool
+
+// Apply walks the tree below root depth-first, calling pre before and post
+// after the children of every non-nil node. Either function may be nil.
+//
+// Unlike the Visitor, Apply lets the functions change the tree while it is
+// walked: Cursor.Replace, Delete, InsertBefore and InsertAfter update the
+// field or slice of the parent that holds the current node. Nodes inserted
+// by pre replacing the current one are walked; inserted nodes are not.
+//
+// Apply returns root, or the node that replaced it.
+func Apply(root Node, pre, post ApplyFunc) (result Node) {
+	parent := &applyRoot{Node: root}
+	defer func() {
+		if r := recover(); r != nil && r != errApplyAbort {
+			panic(r)
+		}
+		result = parent.Node
+	}()
+
+	a := &application{pre: pre, post: post}
+	a.field(parent, "Node", root)
+	return parent.Node
+}
+
+// applyRoot is the parent of the root node, so that it can be replaced like
+// any other
+type applyRoot struct {
+	Node
+}
+
+var errApplyAbort = new(int)
+
+// Cursor describes the node being visited by Apply and where it is held
+type Cursor struct {
+	parent Node
+	name   string
+	iter   *applyIterator // position in the slice holding the node, if any
+	key    string         // parameter name, for named arguments
+	node   Node
+}
+
+// applyIterator is the position of the node in a slice being walked. step is
+// added to index to reach the next node once the current one is done.
+type applyIterator struct {
+	index, step int
+}
+
+// Node returns the current node, or nil once it was deleted
+func (c *Cursor) Node() Node { return c.node }
+
+// Parent returns the node holding the current node, or nil for the root
+func (c *Cursor) Parent() Node {
+	if _, ok := c.parent.(*applyRoot); ok {
+		return nil
+	}
+	return c.parent
+}
+
+// Name returns the name of the field of the parent holding the current node,
+// such as "Condition" or "Statements"
+func (c *Cursor) Name() string { return c.name }
+
+// Index returns the index of the current node in the slice holding it, or
+// -1 if it is not held by a slice
+func (c *Cursor) Index() int {
+	if c.iter == nil {
+		return -1
+	}
+	return c.iter.index
+}
+
+// Key returns the parameter name when the current node is a named argument
+// of a CallExpression, and "" otherwise
+func (c *Cursor) Key() string { return c.key }
+
+// Replace puts n in place of the current node. The type of n must fit the
+// field or slice holding the node; nil clears optional fields.
+func (c *Cursor) Replace(n Node) {
+	v := c.field()
+	switch {
+	case c.key != "":
+		v.SetMapIndex(reflect.ValueOf(c.key), nodeValue(n, v.Type().Elem()))
+	case c.iter != nil:
+		v.Index(c.iter.index).Set(nodeValue(n, v.Type().Elem()))
+	default:
+		v.Set(nodeValue(n, v.Type()))
+	}
+	c.node = n
+}
+
+// Delete removes the current node from the slice holding it, or the named
+// argument from its call. It panics for nodes held by plain fields; use
+// Replace(nil) to clear those.
+func (c *Cursor) Delete() {
+	v := c.field()
+	switch {
+	case c.key != "":
+		v.SetMapIndex(reflect.ValueOf(c.key), reflect.Value{})
+	case c.iter != nil:
+		i, l := c.iter.index, v.Len()
+		reflect.Copy(v.Slice(i, l), v.Slice(i+1, l))
+		v.Index(l - 1).Set(reflect.Zero(v.Type().Elem()))
+		v.SetLen(l - 1)
+		c.iter.step--
+	default:
+		panic("ast: Delete of a node not contained in a slice")
+	}
+	c.node = nil
+}
+
+// InsertAfter inserts n after the current node in the slice holding it. It
+// is not walked.
+func (c *Cursor) InsertAfter(n Node) {
+	v := c.sliceField("InsertAfter")
+	i, l := c.iter.index, v.Len()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	reflect.Copy(v.Slice(i+2, l+1), v.Slice(i+1, l))
+	v.Index(i + 1).Set(nodeValue(n, v.Type().Elem()))
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current node in the slice holding it.
+// It is not walked.
+func (c *Cursor) InsertBefore(n Node) {
+	v := c.sliceField("InsertBefore")
+	i, l := c.iter.index, v.Len()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	reflect.Copy(v.Slice(i+1, l+1), v.Slice(i, l))
+	v.Index(i).Set(nodeValue(n, v.Type().Elem()))
+	c.iter.index++
+}
+
+// field returns the field of the parent holding the current node
+func (c *Cursor) field() reflect.Value {
+	return reflect.Indirect(reflect.ValueOf(c.parent)).FieldByName(c.name)
+}
+
+func (c *Cursor) sliceField(op string) reflect.Value {
+	if c.iter == nil {
+		panic("ast: " + op + " of a node not contained in a slice")
+	}
+	return c.field()
+}
+
+// nodeValue converts n to a value assignable to type t, which is the zero
+// value for nil
+func nodeValue(n Node, t reflect.Type) reflect.Value {
+	if n == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(n)
+}
+
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      applyIterator
+}
+
+// field applies the functions to a node held by a plain field of parent
+func (a *application) field(parent Node, name string, n Node) {
+	a.apply(parent, name, nil, "", n)
+}
+
+func (a *application) apply(parent Node, name string, iter *applyIterator, key string, n Node) {
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		return
+	}
+
+	saved := a.cursor
+	defer func() { a.cursor = saved }()
+	a.cursor = Cursor{parent: parent, name: name, iter: iter, key: key, node: n}
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		return
+	}
+	// Walk the children of the node pre left in place
+	n = a.cursor.node
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		return
+	}
+
+	switch n := n.(type) {
+	case *Program:
+		a.field(n, "VCLVersion", n.VCLVersion)
+		a.list(n, "Declarations")
+	case *BackendDecl:
+		a.list(n, "Properties")
+	case *BackendProperty:
+		a.field(n, "Value", n.Value)
+	case *ProbeDecl:
+		a.list(n, "Properties")
+	case *ProbeProperty:
+		a.field(n, "Value", n.Value)
+	case *ACLDecl:
+		a.list(n, "Entries")
+	case *ACLEntry:
+		a.field(n, "Network", n.Network)
+	case *SubDecl:
+		a.field(n, "Body", n.Body)
+	case *TableDecl:
+		a.list(n, "Entries")
+	case *TableEntry:
+		a.field(n, "Value", n.Value)
+	case *DirectorDecl:
+		a.list(n, "Properties")
+		a.list(n, "Backends")
+	case *DirectorBackend:
+		a.list(n, "Properties")
+
+	case *BlockStatement:
+		a.list(n, "Statements")
+	case *ExpressionStatement:
+		a.field(n, "Expression", n.Expression)
+	case *IfStatement:
+		a.field(n, "Condition", n.Condition)
+		a.field(n, "Then", n.Then)
+		a.field(n, "Else", n.Else)
+	case *SetStatement:
+		a.field(n, "Variable", n.Variable)
+		a.field(n, "Value", n.Value)
+	case *UnsetStatement:
+		a.field(n, "Variable", n.Variable)
+	case *CallStatement:
+		a.field(n, "Function", n.Function)
+	case *ReturnStatement:
+		a.field(n, "Action", n.Action)
+	case *SyntheticStatement:
+		a.field(n, "Response", n.Response)
+	case *ErrorStatement:
+		a.field(n, "Code", n.Code)
+		a.field(n, "Response", n.Response)
+	case *NewStatement:
+		a.field(n, "Name", n.Name)
+		a.field(n, "Constructor", n.Constructor)
+	case *DeclareStatement:
+		a.field(n, "Variable", n.Variable)
+	case *AddStatement:
+		a.field(n, "Variable", n.Variable)
+		a.field(n, "Value", n.Value)
+	case *LogStatement:
+		a.field(n, "Message", n.Message)
+
+	case *BinaryExpression:
+		a.field(n, "Left", n.Left)
+		a.field(n, "Right", n.Right)
+	case *UnaryExpression:
+		a.field(n, "Operand", n.Operand)
+	case *CallExpression:
+		a.field(n, "Function", n.Function)
+		a.list(n, "Arguments")
+		a.namedArguments(n)
+	case *MemberExpression:
+		a.field(n, "Object", n.Object)
+		a.field(n, "Property", n.Property)
+	case *IndexExpression:
+		a.field(n, "Object", n.Object)
+		a.field(n, "Index", n.Index)
+	case *ParenthesizedExpression:
+		a.field(n, "Expression", n.Expression)
+	case *RegexMatchExpression:
+		a.field(n, "Left", n.Left)
+		a.field(n, "Right", n.Right)
+	case *AssignmentExpression:
+		a.field(n, "Left", n.Left)
+		a.field(n, "Right", n.Right)
+	case *UpdateExpression:
+		a.field(n, "Operand", n.Operand)
+	case *ArrayExpression:
+		a.list(n, "Elements")
+	case *ObjectExpression:
+		a.list(n, "Properties")
+	case *Property:
+		a.field(n, "Key", n.Key)
+		a.field(n, "Value", n.Value)
+
+	case *VCLVersionDecl, *ImportDecl, *IncludeDecl,
+		*RestartStatement, *CSourceStatement, *EsiStatement, *GotoStatement, *LabelStatement,
+		*VariableExpression, *TimeExpression, *IPExpression, *ErrorExpression,
+		*Identifier, *StringLiteral, *IntegerLiteral, *FloatLiteral, *BooleanLiteral, *DurationLiteral:
+		// No children
+
+	default:
+		panic(fmt.Sprintf("ast: Apply of unexpected node type %T", n))
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(errApplyAbort)
+	}
+}
+
+// list applies the functions to each node of a slice field of parent. The
+// field is looked up again for every element, as the functions may grow or
+// shrink the slice.
+func (a *application) list(parent Node, name string) {
+	saved := a.iter
+	defer func() { a.iter = saved }()
+
+	a.iter.index = 0
+	for {
+		v := reflect.Indirect(reflect.ValueOf(parent)).FieldByName(name)
+		if a.iter.index >= v.Len() {
+			break
+		}
+		n, _ := v.Index(a.iter.index).Interface().(Node)
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, "", n)
+		a.iter.index += a.iter.step
+	}
+}
+
+// namedArguments applies the functions to the named arguments of a call in
+// the order of their names
+func (a *application) namedArguments(call *CallExpression) {
+	names := make([]string, 0, len(call.NamedArguments))
+	for name := range call.NamedArguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := call.NamedArguments[name]; ok {
+			a.apply(call, "NamedArguments", nil, name, value)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// testProgram builds:
+//
+//	backend origin { .host = "origin"; }
+//	sub vcl_recv {
+//	    set req.backend_hint = origin;
+//	    call normalize;
+//	    if (req.url ~ "^/admin") { return (pass); }
+//	}
+func testProgram() *Program {
+	return &Program{
+		VCLVersion: &VCLVersionDecl{Version: "4.1"},
+		Declarations: []Declaration{
+			&BackendDecl{Name: "origin", Properties: []*BackendProperty{
+				{Name: "host", Value: &StringLiteral{Value: "origin"}},
+			}},
+			&SubDecl{Name: "vcl_recv", Body: &BlockStatement{Statements: []Statement{
+				&SetStatement{
+					Variable: &MemberExpression{Object: &Identifier{Name: "req"}, Property: &Identifier{Name: "backend_hint"}},
+					Operator: "=",
+					Value:    &Identifier{Name: "origin"},
+				},
+				&CallStatement{Function: &Identifier{Name: "normalize"}},
+				&IfStatement{
+					Condition: &RegexMatchExpression{
+						Left:     &MemberExpression{Object: &Identifier{Name: "req"}, Property: &Identifier{Name: "url"}},
+						Operator: "~",
+						Right:    &StringLiteral{Value: "^/admin"},
+					},
+					Then: &BlockStatement{Statements: []Statement{
+						&ReturnStatement{Action: &Identifier{Name: "pass"}},
+					}},
+				},
+			}}},
+		},
+	}
+}
+
+// trace returns the node strings of the tree in walk order
+func trace(root Node) string {
+	var nodes []string
+	Apply(root, func(c *Cursor) bool {
+		nodes = append(nodes, c.Node().String())
+		return true
+	}, nil)
+	return strings.Join(nodes, " ")
+}
+
+func TestApplyWalkOrder(t *testing.T) {
+	program := testProgram()
+	var names []string
+	Apply(program, func(c *Cursor) bool {
+		if _, ok := c.Node().(*Identifier); ok && c.Name() == "Function" {
+			names = append(names, c.Parent().String())
+		}
+		return true
+	}, nil)
+	if len(names) != 1 || names[0] != "CallStatement" {
+		t.Errorf("Expected the call identifier below a CallStatement, got %v", names)
+	}
+
+	// Skipping children with pre, stopping with post
+	var visited []string
+	Apply(program, func(c *Cursor) bool {
+		visited = append(visited, c.Node().String())
+		_, isBackend := c.Node().(*BackendDecl)
+		return !isBackend
+	}, func(c *Cursor) bool {
+		_, isCall := c.Node().(*CallStatement)
+		return !isCall
+	})
+	got := strings.Join(visited, " ")
+	if strings.Contains(got, "BackendProperty") {
+		t.Errorf("Children of the backend should be skipped: %s", got)
+	}
+	if strings.Contains(got, "IfStatement") {
+		t.Errorf("The walk should stop after the call statement: %s", got)
+	}
+}
+
+func TestApplyReplace(t *testing.T) {
+	program := testProgram()
+	Apply(program, func(c *Cursor) bool {
+		switch n := c.Node().(type) {
+		case *BackendDecl:
+			n.Name = "primary"
+		case *Identifier:
+			if n.Name == "origin" && c.Name() == "Value" {
+				c.Replace(&Identifier{Name: "primary"})
+			}
+		case *IfStatement:
+			c.Replace(&CallStatement{Function: &Identifier{Name: "admin"}})
+		}
+		return true
+	}, nil)
+
+	body := program.Declarations[1].(*SubDecl).Body
+	if hint := body.Statements[0].(*SetStatement).Value.(*Identifier).Name; hint != "primary" {
+		t.Errorf("Expected the backend hint to be replaced, got %s", hint)
+	}
+	call, ok := body.Statements[2].(*CallStatement)
+	if !ok || call.Function.(*Identifier).Name != "admin" {
+		t.Errorf("Expected the if statement to be replaced by a call, got %v", body.Statements[2])
+	}
+}
+
+func TestApplyReplaceRoot(t *testing.T) {
+	root := &Identifier{Name: "a"}
+	result := Apply(root, func(c *Cursor) bool {
+		if c.Parent() != nil {
+			t.Errorf("The root should have no parent, got %v", c.Parent())
+		}
+		c.Replace(&Identifier{Name: "b"})
+		return true
+	}, nil)
+	if result.(*Identifier).Name != "b" {
+		t.Errorf("Expected the replaced root, got %v", result)
+	}
+}
+
+func TestApplyDeleteAndInsert(t *testing.T) {
+	program := testProgram()
+	Apply(program, func(c *Cursor) bool {
+		switch n := c.Node().(type) {
+		case *SetStatement:
+			c.InsertBefore(&UnsetStatement{Variable: &Identifier{Name: "before"}})
+			c.InsertAfter(&UnsetStatement{Variable: &Identifier{Name: "after"}})
+		case *CallStatement:
+			c.Delete()
+		case *UnsetStatement:
+			// Inserted nodes are not walked
+			n.Variable.(*Identifier).Name += "-walked"
+		}
+		return true
+	}, nil)
+
+	body := program.Declarations[1].(*SubDecl).Body
+	var got []string
+	for _, stmt := range body.Statements {
+		if unset, ok := stmt.(*UnsetStatement); ok {
+			got = append(got, unset.Variable.(*Identifier).Name)
+		} else {
+			got = append(got, stmt.String())
+		}
+	}
+	want := "before SetStatement after IfStatement"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, " "))
+	}
+}
+
+func TestApplyNamedArguments(t *testing.T) {
+	call := &CallExpression{
+		Function:  &Identifier{Name: "std.fnmatch"},
+		Arguments: []Expression{&StringLiteral{Value: "*"}},
+		NamedArguments: map[string]Expression{
+			"subject":    &StringLiteral{Value: "x"},
+			"pathname":   &BooleanLiteral{Value: true},
+			"noescape":   &BooleanLiteral{Value: false},
+			"positional": nil,
+		},
+	}
+	var keys []string
+	Apply(call, func(c *Cursor) bool {
+		if c.Key() == "" {
+			return true
+		}
+		keys = append(keys, c.Key())
+		switch c.Key() {
+		case "noescape":
+			c.Delete()
+		case "subject":
+			c.Replace(&StringLiteral{Value: "y"})
+		}
+		return true
+	}, nil)
+
+	if strings.Join(keys, ",") != "noescape,pathname,subject" {
+		t.Errorf("Expected named arguments in name order, got %v", keys)
+	}
+	if _, ok := call.NamedArguments["noescape"]; ok {
+		t.Error("Expected noescape to be deleted")
+	}
+	if call.NamedArguments["subject"].(*StringLiteral).Value != "y" {
+		t.Error("Expected subject to be replaced")
+	}
+}
+
+func TestApplyDeletePanicsOutsideSlice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Delete of a field to panic")
+		}
+	}()
+	Apply(&ReturnStatement{Action: &Identifier{Name: "pass"}}, func(c *Cursor) bool {
+		if c.Name() == "Action" {
+			c.Delete()
+		}
+		return true
+	}, nil)
+}
+
+func TestApplyVisitsAllNodes(t *testing.T) {
+	got := trace(testProgram())
+	for _, want := range []string{
+		"VCLVersionDecl(4.1)", "BackendProperty(host)", "StringLiteral(origin)",
+		"Identifier(backend_hint)", "Identifier(normalize)", "RegexMatchExpression(~)",
+		"StringLiteral(^/admin)", "Identifier(pass)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s to be visited in %s", want, got)
+		}
+	}
+}
@@ -24,6 +24,9 @@ type IfStatement struct {
 	Condition Expression
 	Then      Statement
 	Else      Statement // optional
+	// Keyword is "elsif", "elseif" or "elif" for an else branch written
+	// with that keyword, and empty for if and else if
+	Keyword string
 }
 
 func (is *IfStatement) String() string { return "IfStatement" }
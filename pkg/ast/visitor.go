@@ -10,6 +10,8 @@ type Visitor interface {
 	VisitProbeDecl(*ProbeDecl) interface{}
 	VisitACLDecl(*ACLDecl) interface{}
 	VisitSubDecl(*SubDecl) interface{}
+	VisitTableDecl(*TableDecl) interface{}
+	VisitDirectorDecl(*DirectorDecl) interface{}
 
 	VisitBlockStatement(*BlockStatement) interface{}
 	VisitExpressionStatement(*ExpressionStatement) interface{}
@@ -23,6 +25,12 @@ type Visitor interface {
 	VisitRestartStatement(*RestartStatement) interface{}
 	VisitCSourceStatement(*CSourceStatement) interface{}
 	VisitNewStatement(*NewStatement) interface{}
+	VisitDeclareStatement(*DeclareStatement) interface{}
+	VisitAddStatement(*AddStatement) interface{}
+	VisitEsiStatement(*EsiStatement) interface{}
+	VisitLogStatement(*LogStatement) interface{}
+	VisitGotoStatement(*GotoStatement) interface{}
+	VisitLabelStatement(*LabelStatement) interface{}
 
 	VisitBinaryExpression(*BinaryExpression) interface{}
 	VisitUnaryExpression(*UnaryExpression) interface{}
@@ -66,6 +74,10 @@ func Accept(node Node, visitor Visitor) interface{} {
 		return visitor.VisitACLDecl(n)
 	case *SubDecl:
 		return visitor.VisitSubDecl(n)
+	case *TableDecl:
+		return visitor.VisitTableDecl(n)
+	case *DirectorDecl:
+		return visitor.VisitDirectorDecl(n)
 
 	case *BlockStatement:
 		return visitor.VisitBlockStatement(n)
@@ -91,6 +103,18 @@ func Accept(node Node, visitor Visitor) interface{} {
 		return visitor.VisitCSourceStatement(n)
 	case *NewStatement:
 		return visitor.VisitNewStatement(n)
+	case *DeclareStatement:
+		return visitor.VisitDeclareStatement(n)
+	case *AddStatement:
+		return visitor.VisitAddStatement(n)
+	case *EsiStatement:
+		return visitor.VisitEsiStatement(n)
+	case *LogStatement:
+		return visitor.VisitLogStatement(n)
+	case *GotoStatement:
+		return visitor.VisitGotoStatement(n)
+	case *LabelStatement:
+		return visitor.VisitLabelStatement(n)
 
 	case *BinaryExpression:
 		return visitor.VisitBinaryExpression(n)
@@ -151,6 +175,8 @@ func (bv *BaseVisitor) VisitBackendDecl(node *BackendDecl) interface{}
 func (bv *BaseVisitor) VisitProbeDecl(node *ProbeDecl) interface{}                     { return nil }
 func (bv *BaseVisitor) VisitACLDecl(node *ACLDecl) interface{}                         { return nil }
 func (bv *BaseVisitor) VisitSubDecl(node *SubDecl) interface{}                         { return nil }
+func (bv *BaseVisitor) VisitTableDecl(node *TableDecl) interface{}                     { return nil }
+func (bv *BaseVisitor) VisitDirectorDecl(node *DirectorDecl) interface{}               { return nil }
 func (bv *BaseVisitor) VisitBlockStatement(node *BlockStatement) interface{}           { return nil }
 func (bv *BaseVisitor) VisitExpressionStatement(node *ExpressionStatement) interface{} { return nil }
 func (bv *BaseVisitor) VisitIfStatement(node *IfStatement) interface{}                 { return nil }
@@ -163,6 +189,12 @@ func (bv *BaseVisitor) VisitErrorStatement(node *ErrorStatement) interface{}
 func (bv *BaseVisitor) VisitRestartStatement(node *RestartStatement) interface{}       { return nil }
 func (bv *BaseVisitor) VisitCSourceStatement(node *CSourceStatement) interface{}       { return nil }
 func (bv *BaseVisitor) VisitNewStatement(node *NewStatement) interface{}               { return nil }
+func (bv *BaseVisitor) VisitDeclareStatement(node *DeclareStatement) interface{}       { return nil }
+func (bv *BaseVisitor) VisitAddStatement(node *AddStatement) interface{}               { return nil }
+func (bv *BaseVisitor) VisitEsiStatement(node *EsiStatement) interface{}               { return nil }
+func (bv *BaseVisitor) VisitLogStatement(node *LogStatement) interface{}               { return nil }
+func (bv *BaseVisitor) VisitGotoStatement(node *GotoStatement) interface{}             { return nil }
+func (bv *BaseVisitor) VisitLabelStatement(node *LabelStatement) interface{}           { return nil }
 func (bv *BaseVisitor) VisitBinaryExpression(node *BinaryExpression) interface{}       { return nil }
 func (bv *BaseVisitor) VisitUnaryExpression(node *UnaryExpression) interface{}         { return nil }
 func (bv *BaseVisitor) VisitCallExpression(node *CallExpression) interface{}           { return nil }
@@ -0,0 +1,144 @@
+// Package build constructs VCL programs from Go instead of text templates.
+// The builders produce the same syntax trees the parser does, and the
+// printer turns them into formatted VCL:
+//
+//	src, err := build.NewProgram("4.1").
+//		Import("std").
+//		Backend(build.NewBackend("web1").Host("192.0.2.1").Port("80")).
+//		Sub(build.NewSub("vcl_recv").
+//			If(build.Eq(build.Var("req.method"), build.String("PURGE")),
+//				build.Return("purge")).
+//			Set("req.http.X-Forwarded-Proto", build.String("https"))).
+//		VCL()
+//
+// Names are checked when the program is built: an invalid backend name or
+// variable makes AST and VCL return an error instead of VCL that does not
+// parse.
+package build
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+// namePattern matches the names of backends, probes, ACLs, subroutines
+// and modules
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+func checkName(kind, name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid %s name %q", kind, name)
+	}
+	return nil
+}
+
+// declaration is a builder of a top-level declaration
+type declaration interface {
+	declaration() (ast.Declaration, error)
+}
+
+// Program builds a VCL program. Declarations are printed in the order
+// they are added.
+type Program struct {
+	version string
+	decls   []declaration
+}
+
+// NewProgram starts a program for a VCL version such as "4.1"
+func NewProgram(version string) *Program {
+	return &Program{version: version}
+}
+
+type declFunc func() (ast.Declaration, error)
+
+func (f declFunc) declaration() (ast.Declaration, error) { return f() }
+
+// Import adds "import module;"
+func (p *Program) Import(module string) *Program {
+	p.decls = append(p.decls, declFunc(func() (ast.Declaration, error) {
+		return &ast.ImportDecl{Module: module}, checkName("module", module)
+	}))
+	return p
+}
+
+// Include adds "include path;"
+func (p *Program) Include(path string) *Program {
+	p.decls = append(p.decls, declFunc(func() (ast.Declaration, error) {
+		return &ast.IncludeDecl{Path: path}, nil
+	}))
+	return p
+}
+
+// Backend adds a backend declaration
+func (p *Program) Backend(b *Backend) *Program {
+	p.decls = append(p.decls, b)
+	return p
+}
+
+// Probe adds a probe declaration
+func (p *Program) Probe(probe *Probe) *Program {
+	p.decls = append(p.decls, probe)
+	return p
+}
+
+// ACL adds an ACL declaration
+func (p *Program) ACL(a *ACL) *Program {
+	p.decls = append(p.decls, a)
+	return p
+}
+
+// Sub adds a subroutine. Adding a built-in subroutine such as vcl_recv
+// several times is valid VCL: varnishd runs the bodies in order.
+func (p *Program) Sub(s *Sub) *Program {
+	p.decls = append(p.decls, s)
+	return p
+}
+
+// AST returns the syntax tree of the program. Builders can still be
+// changed afterwards and AST called again. The error joins every invalid
+// name and value found.
+func (p *Program) AST() (*ast.Program, error) {
+	program := &ast.Program{}
+	var errs []error
+	if p.version != "" {
+		program.VCLVersion = &ast.VCLVersionDecl{Version: p.version}
+	}
+	for _, d := range p.decls {
+		decl, err := d.declaration()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		program.Declarations = append(program.Declarations, decl)
+	}
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if e, ok := c.Node().(*ast.ErrorExpression); ok {
+			errs = append(errs, errors.New(e.Message))
+		}
+		return true
+	}, nil)
+	return program, errors.Join(errs...)
+}
+
+// VCL returns the program formatted with printer.DefaultConfig()
+func (p *Program) VCL() (string, error) {
+	return p.Format(printer.DefaultConfig())
+}
+
+// Format returns the program formatted with config
+func (p *Program) Format(config *printer.Config) (string, error) {
+	program, err := p.AST()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := config.Fprint(&buf, program); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
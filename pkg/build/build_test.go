@@ -0,0 +1,125 @@
+package build
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestProgram(t *testing.T) {
+	program := NewProgram("4.1").
+		Import("std").
+		Probe(NewProbe("health").URL("/healthz").Interval(5 * time.Second).Window(5).Threshold(3)).
+		Backend(NewBackend("web1").Host("192.0.2.1").Port("80").
+			ConnectTimeout(1500 * time.Millisecond).Probe("health")).
+		Backend(NewBackend("web2").Host("192.0.2.2").Port("80").
+			InlineProbe(NewProbe("").URL("/").Timeout(2 * time.Second))).
+		ACL(NewACL("purgers").Add("127.0.0.1", "10.0.0.0/8", "!10.1.2.3")).
+		Sub(NewSub("vcl_recv").
+			Add(IfElse(Eq(Var("req.method"), String("PURGE")),
+				[]ast.Statement{
+					If(Not(MatchACL(Var("client.ip"), "purgers")),
+						Return("synth", Int(405), String("Not allowed"))),
+					Return("purge"),
+				},
+				If(And(Eq(Var("req.method"), String("GET")), Or(Match(Var("req.url"), `^/static/`), Match(Var("req.url"), `\.css$`))),
+					Unset("req.http.Cookie")))).
+			Set("req.backend_hint", Var("web1")).
+			Set("req.http.X-Forwarded-Proto", String("https")).
+			Do(Func("std.log", Concat(String("url: "), Var("req.url"))))).
+		Sub(NewSub("vcl_backend_response").
+			Set("beresp.ttl", Duration(2*time.Minute)).
+			Set("beresp.grace", Duration(36*time.Hour)).
+			Return("deliver"))
+
+	src, err := program.VCL()
+	if err != nil {
+		t.Fatalf("VCL() failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"vcl 4.1;",
+		"import std;",
+		`= "192.0.2.1";`,
+		"= 1500ms;",
+		"= health;",
+		"= 2s;",
+		`"10.0.0.0"/8;`,
+		`!"10.1.2.3";`,
+		"if (req.method == \"PURGE\") {",
+		"} else if (req.method == \"GET\" && (req.url ~ \"^/static/\" || req.url ~ \"\\.css$\")) {",
+		"if (!(client.ip ~ purgers)) {",
+		`return (synth(405, "Not allowed"));`,
+		`std.log("url: " + req.url);`,
+		"set beresp.ttl = 2m;",
+		"set beresp.grace = 36h;",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Output lacks %q:\n%s", want, src)
+		}
+	}
+
+	parsed, err := parser.Parse(src, "built.vcl")
+	if err != nil {
+		t.Fatalf("Built VCL does not parse: %v\n%s", err, src)
+	}
+	if errs := analyzer.NewAnalyzer(vmod.DefaultRegistry).Analyze(parsed); len(errs) > 0 {
+		t.Errorf("Built VCL does not analyze: %v\n%s", errs, src)
+	}
+}
+
+func TestProgramErrors(t *testing.T) {
+	_, err := NewProgram("4.1").
+		Backend(NewBackend("web 1").Host("192.0.2.1")).
+		ACL(NewACL("local").Add("10.0.0.0/x")).
+		Sub(NewSub("vcl_recv").Set("req.http.X Foo", String("a"))).
+		VCL()
+	if err == nil {
+		t.Fatal("Expected an error for invalid names")
+	}
+	for _, want := range []string{`invalid backend name "web 1"`, `invalid mask in "10.0.0.0/x"`, `invalid name "req.http.X Foo"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error %q lacks %q", err, want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{90 * time.Second, "90s"},
+		{2 * time.Hour, "2h"},
+		{14 * 24 * time.Hour, "2w"},
+		{250 * time.Millisecond, "250ms"},
+		{1500 * time.Microsecond, "0.0015s"},
+	}
+	for _, tt := range tests {
+		got := Duration(tt.d).(*ast.TimeExpression).Value
+		if got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	src, err := NewProgram("4.1").
+		Sub(NewSub("vcl_synth").Add(Synthetic(String(`{"error": "not found"}`)))).
+		VCL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, `synthetic("""{"error": "not found"}""");`) {
+		t.Errorf("Quotes not written as a long string:\n%s", src)
+	}
+	if _, err := parser.Parse(src, "built.vcl"); err != nil {
+		t.Errorf("Built VCL does not parse: %v\n%s", err, src)
+	}
+}
@@ -0,0 +1,192 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Backend builds a backend declaration
+type Backend struct {
+	name       string
+	properties []*ast.BackendProperty
+}
+
+// NewBackend starts a backend called name
+func NewBackend(name string) *Backend {
+	return &Backend{name: name}
+}
+
+// Property sets .name = value, replacing an earlier value of the property
+func (b *Backend) Property(name string, value ast.Expression) *Backend {
+	for _, prop := range b.properties {
+		if prop.Name == name {
+			prop.Value = value
+			return b
+		}
+	}
+	b.properties = append(b.properties, &ast.BackendProperty{Name: name, Value: value})
+	return b
+}
+
+// Host sets .host, an address or host name
+func (b *Backend) Host(host string) *Backend { return b.Property("host", String(host)) }
+
+// Port sets .port
+func (b *Backend) Port(port string) *Backend { return b.Property("port", String(port)) }
+
+// Path sets .path, the Unix domain socket of the backend
+func (b *Backend) Path(path string) *Backend { return b.Property("path", String(path)) }
+
+// HostHeader sets .host_header, the Host header of backend requests
+// without one
+func (b *Backend) HostHeader(host string) *Backend {
+	return b.Property("host_header", String(host))
+}
+
+// ConnectTimeout sets .connect_timeout
+func (b *Backend) ConnectTimeout(d time.Duration) *Backend {
+	return b.Property("connect_timeout", Duration(d))
+}
+
+// FirstByteTimeout sets .first_byte_timeout
+func (b *Backend) FirstByteTimeout(d time.Duration) *Backend {
+	return b.Property("first_byte_timeout", Duration(d))
+}
+
+// BetweenBytesTimeout sets .between_bytes_timeout
+func (b *Backend) BetweenBytesTimeout(d time.Duration) *Backend {
+	return b.Property("between_bytes_timeout", Duration(d))
+}
+
+// MaxConnections sets .max_connections
+func (b *Backend) MaxConnections(n int) *Backend {
+	return b.Property("max_connections", Int(int64(n)))
+}
+
+// Probe sets .probe to the probe declared as name
+func (b *Backend) Probe(name string) *Backend {
+	return b.Property("probe", Var(name))
+}
+
+// InlineProbe sets .probe to a probe written inside the backend. The name
+// of probe is not used.
+func (b *Backend) InlineProbe(probe *Probe) *Backend {
+	obj := &ast.ObjectExpression{}
+	for _, prop := range probe.properties {
+		obj.Properties = append(obj.Properties, &ast.Property{
+			Key:   &ast.Identifier{Name: prop.Name},
+			Value: prop.Value,
+		})
+	}
+	return b.Property("probe", obj)
+}
+
+func (b *Backend) declaration() (ast.Declaration, error) {
+	return &ast.BackendDecl{Name: b.name, Properties: b.properties}, checkName("backend", b.name)
+}
+
+// Probe builds a probe declaration
+type Probe struct {
+	name       string
+	properties []*ast.ProbeProperty
+}
+
+// NewProbe starts a probe called name
+func NewProbe(name string) *Probe {
+	return &Probe{name: name}
+}
+
+// Property sets .name = value, replacing an earlier value of the property
+func (p *Probe) Property(name string, value ast.Expression) *Probe {
+	for _, prop := range p.properties {
+		if prop.Name == name {
+			prop.Value = value
+			return p
+		}
+	}
+	p.properties = append(p.properties, &ast.ProbeProperty{Name: name, Value: value})
+	return p
+}
+
+// URL sets .url
+func (p *Probe) URL(url string) *Probe { return p.Property("url", String(url)) }
+
+// ExpectedResponse sets .expected_response
+func (p *Probe) ExpectedResponse(status int) *Probe {
+	return p.Property("expected_response", Int(int64(status)))
+}
+
+// Timeout sets .timeout
+func (p *Probe) Timeout(d time.Duration) *Probe { return p.Property("timeout", Duration(d)) }
+
+// Interval sets .interval
+func (p *Probe) Interval(d time.Duration) *Probe { return p.Property("interval", Duration(d)) }
+
+// Window sets .window
+func (p *Probe) Window(n int) *Probe { return p.Property("window", Int(int64(n))) }
+
+// Threshold sets .threshold
+func (p *Probe) Threshold(n int) *Probe { return p.Property("threshold", Int(int64(n))) }
+
+// Initial sets .initial
+func (p *Probe) Initial(n int) *Probe { return p.Property("initial", Int(int64(n))) }
+
+func (p *Probe) declaration() (ast.Declaration, error) {
+	return &ast.ProbeDecl{Name: p.name, Properties: p.properties}, checkName("probe", p.name)
+}
+
+// ACL builds an ACL declaration
+type ACL struct {
+	name    string
+	entries []*ast.ACLEntry
+	errs    []error
+}
+
+// NewACL starts an ACL called name
+func NewACL(name string) *ACL {
+	return &ACL{name: name}
+}
+
+// Add adds entries written like in VCL, without the quotes: an address
+// or host name, optionally followed by /mask, and prefixed with ! for
+// addresses the ACL does not match, e.g. "10.0.0.0/8" or "!10.1.2.3"
+func (a *ACL) Add(entries ...string) *ACL {
+	for _, text := range entries {
+		entry := &ast.ACLEntry{PrefixLen: -1}
+		host, negated := strings.CutPrefix(text, "!")
+		entry.Negated = negated
+		if addr, mask, ok := strings.Cut(host, "/"); ok {
+			n, err := strconv.Atoi(mask)
+			if err != nil || n < 0 || n > 128 {
+				a.errs = append(a.errs, fmt.Errorf("acl %s: invalid mask in %q", a.name, text))
+				continue
+			}
+			host, entry.PrefixLen = addr, n
+		}
+		if host == "" {
+			a.errs = append(a.errs, fmt.Errorf("acl %s: empty entry %q", a.name, text))
+			continue
+		}
+		entry.Host = host
+		entry.Network = String(host)
+		if entry.PrefixLen >= 0 {
+			entry.Network = &ast.BinaryExpression{
+				Left:     entry.Network,
+				Operator: "/",
+				Right:    Int(int64(entry.PrefixLen)),
+			}
+		}
+		a.entries = append(a.entries, entry)
+	}
+	return a
+}
+
+func (a *ACL) declaration() (ast.Declaration, error) {
+	errs := append([]error{checkName("acl", a.name)}, a.errs...)
+	return &ast.ACLDecl{Name: a.name, Entries: a.entries}, errors.Join(errs...)
+}
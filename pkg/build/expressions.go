@@ -0,0 +1,157 @@
+package build
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// variablePattern matches dotted names such as req.http.X-Forwarded-For
+var variablePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*(\.[A-Za-z0-9_-]+)*$`)
+
+// Var refers to a variable, VMOD function or declared name, e.g.
+// Var("req.http.Host") or Var("web1")
+func Var(name string) ast.Expression {
+	if !variablePattern.MatchString(name) {
+		return &ast.ErrorExpression{Message: "invalid name " + strconv.Quote(name)}
+	}
+	parts := strings.Split(name, ".")
+	var expr ast.Expression = &ast.Identifier{Name: parts[0]}
+	for _, part := range parts[1:] {
+		expr = &ast.MemberExpression{Object: expr, Property: &ast.Identifier{Name: part}}
+	}
+	return expr
+}
+
+// String is a string literal. Strings with double quotes or line breaks
+// are written as long strings, {"..."} or """...""".
+func String(s string) ast.Expression {
+	if strings.Contains(s, `"}`) && strings.Contains(s, `"""`) {
+		return &ast.ErrorExpression{Message: "string cannot be written in VCL: " + strconv.Quote(s)}
+	}
+	return &ast.StringLiteral{Value: s, Long: strings.ContainsAny(s, "\"\n")}
+}
+
+// Int is an integer literal
+func Int(n int64) ast.Expression {
+	if n < 0 {
+		return &ast.UnaryExpression{Operator: "-", Operand: &ast.IntegerLiteral{Value: -n}}
+	}
+	return &ast.IntegerLiteral{Value: n}
+}
+
+// Bool is true or false
+func Bool(b bool) ast.Expression {
+	return &ast.BooleanLiteral{Value: b}
+}
+
+// durationUnits are the VCL duration units, largest first
+var durationUnits = []struct {
+	unit string
+	d    time.Duration
+}{
+	{"y", 365 * 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+}
+
+// Duration is a duration literal in the largest unit d is a whole number
+// of, such as 2m or 1500ms, or in fractional seconds
+func Duration(d time.Duration) ast.Expression {
+	if d < 0 {
+		return &ast.UnaryExpression{Operator: "-", Operand: Duration(-d)}
+	}
+	number, unit := strconv.FormatFloat(d.Seconds(), 'f', -1, 64), "s"
+	if d == 0 {
+		number = "0"
+	}
+	for _, u := range durationUnits {
+		if d != 0 && d%u.d == 0 {
+			number, unit = strconv.FormatInt(int64(d/u.d), 10), u.unit
+			break
+		}
+	}
+	return &ast.TimeExpression{
+		Value: number + unit,
+		Parts: []ast.DurationPart{{Number: number, Unit: unit}},
+	}
+}
+
+// Func calls a function, e.g. Func("std.tolower", Var("req.url")). Named
+// arguments can be set on the result's NamedArguments.
+func Func(name string, args ...ast.Expression) *ast.CallExpression {
+	return &ast.CallExpression{Function: Var(name), Arguments: args}
+}
+
+// Concat joins strings with +
+func Concat(parts ...ast.Expression) ast.Expression {
+	return chain("+", parts)
+}
+
+func binary(left ast.Expression, op string, right ast.Expression) ast.Expression {
+	return &ast.BinaryExpression{Left: left, Operator: op, Right: right}
+}
+
+// chain combines exprs left to right with a binary operator
+func chain(op string, exprs []ast.Expression) ast.Expression {
+	if len(exprs) == 0 {
+		return &ast.ErrorExpression{Message: "no operands for " + op}
+	}
+	expr := exprs[0]
+	for _, e := range exprs[1:] {
+		expr = binary(expr, op, e)
+	}
+	return expr
+}
+
+// Eq is left == right
+func Eq(left, right ast.Expression) ast.Expression { return binary(left, "==", right) }
+
+// Ne is left != right
+func Ne(left, right ast.Expression) ast.Expression { return binary(left, "!=", right) }
+
+// Lt is left < right
+func Lt(left, right ast.Expression) ast.Expression { return binary(left, "<", right) }
+
+// Le is left <= right
+func Le(left, right ast.Expression) ast.Expression { return binary(left, "<=", right) }
+
+// Gt is left > right
+func Gt(left, right ast.Expression) ast.Expression { return binary(left, ">", right) }
+
+// Ge is left >= right
+func Ge(left, right ast.Expression) ast.Expression { return binary(left, ">=", right) }
+
+// Match is left ~ "pattern". With the name of an ACL as pattern, use
+// MatchACL.
+func Match(left ast.Expression, pattern string) ast.Expression {
+	return &ast.RegexMatchExpression{Left: left, Operator: "~", Right: String(pattern)}
+}
+
+// NotMatch is left !~ "pattern"
+func NotMatch(left ast.Expression, pattern string) ast.Expression {
+	return &ast.RegexMatchExpression{Left: left, Operator: "!~", Right: String(pattern)}
+}
+
+// MatchACL is left ~ acl, e.g. MatchACL(Var("client.ip"), "purgers")
+func MatchACL(left ast.Expression, acl string) ast.Expression {
+	return &ast.RegexMatchExpression{Left: left, Operator: "~", Right: Var(acl)}
+}
+
+// And is a && b && ...; parentheses are added where the operands need them
+func And(exprs ...ast.Expression) ast.Expression { return chain("&&", exprs) }
+
+// Or is a || b || ...
+func Or(exprs ...ast.Expression) ast.Expression { return chain("||", exprs) }
+
+// Not is !e
+func Not(e ast.Expression) ast.Expression {
+	return &ast.UnaryExpression{Operator: "!", Operand: e}
+}
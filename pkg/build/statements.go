@@ -0,0 +1,117 @@
+package build
+
+import (
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Sub builds a subroutine
+type Sub struct {
+	name       string
+	statements []ast.Statement
+}
+
+// NewSub starts a subroutine called name, such as "vcl_recv" or a custom
+// subroutine
+func NewSub(name string) *Sub {
+	return &Sub{name: name}
+}
+
+// Add appends statements to the body
+func (s *Sub) Add(statements ...ast.Statement) *Sub {
+	s.statements = append(s.statements, statements...)
+	return s
+}
+
+// Set appends "set variable = value;"
+func (s *Sub) Set(variable string, value ast.Expression) *Sub {
+	return s.Add(Set(variable, value))
+}
+
+// Unset appends "unset variable;"
+func (s *Sub) Unset(variable string) *Sub { return s.Add(Unset(variable)) }
+
+// Call appends "call sub;"
+func (s *Sub) Call(sub string) *Sub { return s.Add(Call(sub)) }
+
+// Return appends a return statement, see Return
+func (s *Sub) Return(action string, args ...ast.Expression) *Sub {
+	return s.Add(Return(action, args...))
+}
+
+// If appends an if statement running then when cond holds
+func (s *Sub) If(cond ast.Expression, then ...ast.Statement) *Sub {
+	return s.Add(If(cond, then...))
+}
+
+// Do appends an expression statement, such as a VMOD function call:
+// Do(Func("std.log", String("hit")))
+func (s *Sub) Do(expr ast.Expression) *Sub { return s.Add(Do(expr)) }
+
+func (s *Sub) declaration() (ast.Declaration, error) {
+	return &ast.SubDecl{
+		Name: s.name,
+		Body: &ast.BlockStatement{Statements: s.statements},
+	}, checkName("subroutine", s.name)
+}
+
+// Set is "set variable = value;"
+func Set(variable string, value ast.Expression) ast.Statement {
+	return &ast.SetStatement{Variable: Var(variable), Operator: "=", Value: value}
+}
+
+// Unset is "unset variable;"
+func Unset(variable string) ast.Statement {
+	return &ast.UnsetStatement{Variable: Var(variable)}
+}
+
+// Call is "call sub;"
+func Call(sub string) ast.Statement {
+	return &ast.CallStatement{Function: Var(sub)}
+}
+
+// Return is "return (action);", or with arguments
+// "return (action(args...));" as in Return("synth", Int(404)). An empty
+// action is a plain "return;".
+func Return(action string, args ...ast.Expression) ast.Statement {
+	if action == "" {
+		return &ast.ReturnStatement{}
+	}
+	if len(args) > 0 {
+		return &ast.ReturnStatement{Action: Func(action, args...)}
+	}
+	return &ast.ReturnStatement{Action: Var(action)}
+}
+
+// Synthetic is "synthetic(body);"
+func Synthetic(body ast.Expression) ast.Statement {
+	return &ast.SyntheticStatement{Response: body}
+}
+
+// Do is an expression statement, such as a VMOD function call
+func Do(expr ast.Expression) ast.Statement {
+	return &ast.ExpressionStatement{Expression: expr}
+}
+
+// If is an if statement running then when cond holds
+func If(cond ast.Expression, then ...ast.Statement) *ast.IfStatement {
+	return &ast.IfStatement{
+		Condition: cond,
+		Then:      &ast.BlockStatement{Statements: then},
+	}
+}
+
+// IfElse is an if statement with an else branch. An else branch of a
+// single if statement is printed as "else if", so IfElse chains:
+//
+//	IfElse(a, []ast.Statement{x}, IfElse(b, []ast.Statement{y}, z))
+func IfElse(cond ast.Expression, then []ast.Statement, els ...ast.Statement) *ast.IfStatement {
+	stmt := If(cond, then...)
+	if len(els) == 1 {
+		if elseIf, ok := els[0].(*ast.IfStatement); ok {
+			stmt.Else = elseIf
+			return stmt
+		}
+	}
+	stmt.Else = &ast.BlockStatement{Statements: els}
+	return stmt
+}
@@ -0,0 +1,265 @@
+// Package cache memoizes parsing by the content of the parsed file, so
+// tools that see the same sources again, such as a language server
+// resolving a large include tree after every edit, only parse the files
+// that changed:
+//
+//	c, err := cache.New(cache.WithDir(dir, 0))
+//	program, err := c.ResolveFile("main.vcl", include.WithBasePath("/etc/varnish"))
+//
+// Programs returned by a Cache are shared by everyone asking for the same
+// content and must not be modified.
+//
+// Results are kept in memory, dropping the least recently used ones beyond
+// WithMaxEntries. WithDir also keeps the results of parses without errors
+// in a directory shared with later processes, such as the next CI job or a
+// restarted language server, with the tree stored as its protocol buffer
+// message (see pkg/ast/astproto). Mind that the parser is fast: reading a
+// large tree back currently takes about four times as long as parsing its
+// source, much as decoding it with encoding/gob does (see BenchmarkParse),
+// so measure before enabling it for speed.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+const (
+	// DefaultMaxEntries is the number of parse results kept in memory
+	// unless WithMaxEntries says otherwise
+	DefaultMaxEntries = 1024
+	// DefaultMaxBytes is the size the directory of WithDir is kept below
+	// when no size is given
+	DefaultMaxBytes = 64 << 20
+)
+
+// Cache memoizes parse results keyed by a hash of the file name, the
+// content and the parser configuration. It is safe for concurrent use.
+type Cache struct {
+	config     *parser.Config
+	maxEntries int
+	dir        string
+	maxBytes   int64
+	store      *store
+
+	mutex sync.Mutex
+	// entries maps keys to the elements of recent, which holds the entries
+	// with the most recently used first
+	entries map[string]*list.Element
+	recent  *list.List
+	stats   Stats
+}
+
+// entry is the result of parsing one file
+type entry struct {
+	key    string
+	result *parser.ParseResult
+}
+
+// Stats counts how parse requests were served
+type Stats struct {
+	// Hits were served from memory
+	Hits int
+	// DiskHits were read from the directory of WithDir
+	DiskHits int
+	// Misses were parsed
+	Misses int
+	// Evictions are results dropped from memory to stay within
+	// WithMaxEntries
+	Evictions int
+}
+
+// Option configures a Cache
+type Option func(*Cache)
+
+// WithParserConfig parses with config instead of parser.DefaultConfig().
+// Configurations with Arena set are refused by New, as trees allocated
+// in an arena cannot be shared.
+func WithParserConfig(config *parser.Config) Option {
+	return func(c *Cache) {
+		c.config = config
+	}
+}
+
+// WithMaxEntries keeps at most n results in memory, dropping the least
+// recently used ones first (default DefaultMaxEntries). 0 keeps them all.
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) {
+		c.maxEntries = n
+	}
+}
+
+// WithDir also keeps the results of parses without errors in dir, which is
+// created if needed, and reads them back in later processes. Once the
+// files take more than maxBytes, the least recently used are removed; 0
+// selects DefaultMaxBytes. Several processes may share a directory.
+func WithDir(dir string, maxBytes int64) Option {
+	return func(c *Cache) {
+		c.dir = dir
+		c.maxBytes = maxBytes
+	}
+}
+
+// New creates an empty cache. It fails for a parser configuration with
+// Arena set and when the directory of WithDir cannot be created.
+func New(options ...Option) (*Cache, error) {
+	c := &Cache{
+		config:     parser.DefaultConfig(),
+		maxEntries: DefaultMaxEntries,
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	if c.config == nil {
+		c.config = parser.DefaultConfig()
+	}
+	if c.config.Arena {
+		return nil, errors.New("cache: trees allocated in an arena cannot be shared; parse without Arena")
+	}
+	if c.dir != "" {
+		if c.maxBytes <= 0 {
+			c.maxBytes = DefaultMaxBytes
+		}
+		store, err := openStore(c.dir, c.maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		c.store = store
+	}
+	return c, nil
+}
+
+// Parse is parser.ParseWithConfig with the cache's parser configuration,
+// returning the earlier result for content it has parsed before
+func (c *Cache) Parse(input, filename string) (*ast.Program, error) {
+	result := c.ParseDetailed(input, filename)
+	return result.Program, result.Err()
+}
+
+// ParseDetailed is parser.ParseDetailed with the cache's parser
+// configuration, returning the earlier result for content it has parsed
+// before. The result is shared and must not be modified.
+func (c *Cache) ParseDetailed(input, filename string) *parser.ParseResult {
+	key := c.key(input, filename)
+
+	c.mutex.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.recent.MoveToFront(element)
+		c.stats.Hits++
+		c.mutex.Unlock()
+		return element.Value.(*entry).result
+	}
+	c.mutex.Unlock()
+
+	if result, ok := c.store.get(key); ok {
+		return c.add(key, result, func(s *Stats) { s.DiskHits++ })
+	}
+	result := parser.ParseDetailed(input, filename, c.config)
+	c.store.put(key, result)
+	return c.add(key, result, func(s *Stats) { s.Misses++ })
+}
+
+// add records the result for key, counting it with count, and returns it.
+// When another caller added one first, that one is returned instead, so
+// that everyone gets the same program.
+func (c *Cache) add(key string, result *parser.ParseResult, count func(*Stats)) *parser.ParseResult {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	count(&c.stats)
+	if element, ok := c.entries[key]; ok {
+		c.recent.MoveToFront(element)
+		return element.Value.(*entry).result
+	}
+	c.entries[key] = c.recent.PushFront(&entry{key: key, result: result})
+	for c.maxEntries > 0 && c.recent.Len() > c.maxEntries {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+		c.stats.Evictions++
+	}
+	return result
+}
+
+// NewDocument is parser.NewDocument with the cache's parser configuration,
+// taking the tree from the directory of WithDir when it holds the content.
+// As documents change their tree in place on updates, the tree is their
+// own: it is neither served from nor added to the entries in memory.
+func (c *Cache) NewDocument(input, filename string) *parser.Document {
+	key := c.key(input, filename)
+	if result, ok := c.store.get(key); ok {
+		c.mutex.Lock()
+		c.stats.DiskHits++
+		c.mutex.Unlock()
+		return parser.NewDocumentFromResult(result, input, filename, c.config)
+	}
+
+	result := parser.ParseDetailed(input, filename, c.config)
+	c.store.put(key, result)
+	c.mutex.Lock()
+	c.stats.Misses++
+	c.mutex.Unlock()
+	return parser.NewDocumentFromResult(result, input, filename, c.config)
+}
+
+// ResolveFile resolves the includes of a file like an include.Resolver
+// created with options, parsing every file read through the cache. Files
+// are still read, so changes to any file of the include tree are seen.
+func (c *Cache) ResolveFile(filename string, options ...include.Option) (*ast.Program, error) {
+	return c.ResolveFileContext(context.Background(), filename, options...)
+}
+
+// ResolveFileContext is ResolveFile that stops once ctx is done, like
+// include.Resolver.ResolveFileContext
+func (c *Cache) ResolveFileContext(ctx context.Context, filename string, options ...include.Option) (*ast.Program, error) {
+	options = append(options[:len(options):len(options)], include.WithParseFunc(c.Parse))
+	return include.NewResolver(options...).ResolveFileContext(ctx, filename)
+}
+
+// Stats returns how the parse requests so far were served
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stats
+}
+
+// key hashes everything a parse result depends on: the file name, the
+// content, the parser settings shaping the tree (DisableInlineC, MaxErrors,
+// Legacy and Dialect) and the format of the files of WithDir. Arena only
+// changes where nodes are allocated and is refused by New.
+func (c *Cache) key(input, filename string) string {
+	h := sha256.New()
+	var buf [binary.MaxVarintLen64]byte
+	writeInt := func(n int) {
+		h.Write(buf[:binary.PutVarint(buf[:], int64(n))])
+	}
+	writeBool := func(b bool) {
+		if b {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+	}
+	writeString := func(s string) {
+		h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(s)))])
+		h.Write([]byte(s))
+	}
+	writeInt(storeFormat)
+	writeString(filename)
+	writeBool(c.config.DisableInlineC)
+	writeInt(c.config.MaxErrors)
+	writeBool(c.config.Legacy)
+	writeInt(int(c.config.Dialect))
+	writeString(input)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,292 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/internal/benchdata"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// newCache is New for tests
+func newCache(t testing.TB, options ...Option) *Cache {
+	t.Helper()
+	c, err := New(options...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c
+}
+
+func TestParse(t *testing.T) {
+	c := newCache(t)
+	input := "vcl 4.1;\nsub vcl_recv { return (pass); }\n"
+
+	first, err := c.Parse(input, "a.vcl")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	second, err := c.Parse(input, "a.vcl")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if first != second {
+		t.Error("Parsing the same content again returned another program")
+	}
+	if _, err := c.Parse(input+"\n", "a.vcl"); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := c.Parse(input, "b.vcl"); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if stats := c.Stats(); stats != (Stats{Hits: 1, Misses: 3}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	c := newCache(t, WithParserConfig(&parser.Config{DisableInlineC: true}))
+	input := "vcl 4.1;\nsub vcl_recv { C{ int x; }C }\n"
+
+	_, err := c.Parse(input, "c.vcl")
+	if err == nil {
+		t.Fatal("Expected inline C to be rejected")
+	}
+	_, again := c.Parse(input, "c.vcl")
+	if again != err {
+		t.Errorf("Parse() error = %v, want the cached %v", again, err)
+	}
+
+	// Another configuration is another key
+	if _, err := newCache(t).Parse(input, "c.vcl"); err != nil {
+		t.Errorf("Parse() with inline C allowed: %v", err)
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	reader := include.NewMemoryFileReader(map[string]string{
+		"main.vcl":     "vcl 4.1;\ninclude \"backends.vcl\";\ninclude \"recv.vcl\";\n",
+		"backends.vcl": "vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\n",
+		"recv.vcl":     "vcl 4.1;\nsub vcl_recv { return (pass); }\n",
+	})
+	c := newCache(t)
+
+	for i := 0; i < 2; i++ {
+		program, err := c.ResolveFile("main.vcl", include.WithFileReader(reader))
+		if err != nil {
+			t.Fatalf("ResolveFile() error = %v", err)
+		}
+		if len(program.Declarations) != 2 {
+			t.Fatalf("Expected 2 declarations, got %d", len(program.Declarations))
+		}
+	}
+	if stats := c.Stats(); stats != (Stats{Hits: 3, Misses: 3}) {
+		t.Errorf("Stats() after resolving twice = %+v", stats)
+	}
+
+	// Only the changed file is parsed again
+	reader.AddFile("recv.vcl", "vcl 4.1;\nsub vcl_recv { return (hash); }\n")
+	if _, err := c.ResolveFile("main.vcl", include.WithFileReader(reader)); err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	if stats := c.Stats(); stats != (Stats{Hits: 5, Misses: 4}) {
+		t.Errorf("Stats() after changing a file = %+v", stats)
+	}
+}
+
+func TestKey(t *testing.T) {
+	input := "vcl 4.1;\nsub vcl_recv { return (pass); }\n"
+	configs := []*parser.Config{
+		{},
+		{MaxErrors: 1},
+		{DisableInlineC: true},
+		{Legacy: true},
+		{Dialect: lexer.DialectFastly},
+	}
+	keys := map[string]bool{}
+	for _, config := range configs {
+		keys[newCache(t, WithParserConfig(config)).key(input, "a.vcl")] = true
+	}
+	if len(keys) != len(configs) {
+		t.Errorf("Expected a key per configuration, got %d for %d", len(keys), len(configs))
+	}
+}
+
+func TestArenaRefused(t *testing.T) {
+	if _, err := New(WithParserConfig(&parser.Config{Arena: true})); err == nil {
+		t.Error("Expected a configuration with Arena to be refused")
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	c := newCache(t, WithMaxEntries(2))
+	parse := func(name string) *ast.Program {
+		program, err := c.Parse("vcl 4.1;\n", name)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return program
+	}
+	a := parse("a.vcl")
+	parse("b.vcl")
+	if parse("a.vcl") != a {
+		t.Error("Expected a.vcl to be served from memory")
+	}
+	// c.vcl evicts b.vcl, the least recently used
+	parse("c.vcl")
+	if parse("a.vcl") != a {
+		t.Error("Expected a.vcl to be kept")
+	}
+	parse("b.vcl")
+	if stats := c.Stats(); stats != (Stats{Hits: 2, Misses: 4, Evictions: 2}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	input := "vcl 4.1;\n# a comment\nbackend default { .host = \"127.0.0.1\"; }\nsub vcl_recv { return (pass);; }\n"
+	want := parser.ParseDetailed(input, "a.vcl", parser.DefaultConfig())
+
+	first := newCache(t, WithDir(dir, 0))
+	first.ParseDetailed(input, "a.vcl")
+	if _, err := first.Parse("vcl 4.1;\nsub {", "bad.vcl"); err == nil {
+		t.Fatal("Expected a syntax error")
+	}
+
+	// Another process finds the result on disk
+	second := newCache(t, WithDir(dir, 0))
+	got := second.ParseDetailed(input, "a.vcl")
+	if !reflect.DeepEqual(want.Program, got.Program) {
+		t.Error("The program read from disk differs from the parsed one")
+	}
+	if !reflect.DeepEqual(want.Warnings, got.Warnings) || len(got.Warnings) == 0 {
+		t.Errorf("Warnings = %v, want %v", got.Warnings, want.Warnings)
+	}
+	if len(got.Comments) != len(want.Comments) || got.TokenCount != want.TokenCount {
+		t.Errorf("Got %d comments and %d tokens, want %d and %d", len(got.Comments), got.TokenCount, len(want.Comments), want.TokenCount)
+	}
+	// Results with errors are not stored
+	if _, err := second.Parse("vcl 4.1;\nsub {", "bad.vcl"); err == nil {
+		t.Fatal("Expected a syntax error")
+	}
+	if stats := second.Stats(); stats != (Stats{DiskHits: 1, Misses: 1}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	// Documents get a tree of their own
+	doc := second.NewDocument(input, "a.vcl")
+	if doc.Program == got.Program || !reflect.DeepEqual(doc.Program, got.Program) {
+		t.Error("Expected the document to get its own copy of the tree")
+	}
+	doc.Replace(strings.Replace(input, "pass", "hash", 1))
+	if again := second.ParseDetailed(input, "a.vcl"); !reflect.DeepEqual(want.Program, again.Program) {
+		t.Error("Updating the document changed the cached tree")
+	}
+}
+
+func TestDirMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	c := newCache(t, WithDir(dir, 4096))
+	for i := 0; i < 50; i++ {
+		input := fmt.Sprintf("vcl 4.1;\nbackend b%d { .host = \"127.0.0.1\"; }\n", i)
+		if _, err := c.Parse(input, "a.vcl"); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var size int64
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += info.Size()
+	}
+	if size > 4096 || len(files) == 0 {
+		t.Errorf("Expected at most 4096 bytes in some files, got %d bytes in %d files", size, len(files))
+	}
+}
+
+// BenchmarkParse compares parsing a generated configuration of a few hundred
+// KB with serving it from memory, reading it from the directory of WithDir,
+// and decoding a gob-encoded tree
+func BenchmarkParse(b *testing.B) {
+	input := benchdata.Tenants(100)
+	program, err := parser.Parse(input, "bench.vcl")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, node := range nodeTypes {
+		gob.Register(node)
+	}
+	var encoded bytes.Buffer
+	if err := gob.NewEncoder(&encoded).Encode(program); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("parse", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = parser.Parse(input, "bench.vcl")
+		}
+	})
+	b.Run("memory", func(b *testing.B) {
+		c := newCache(b)
+		for i := 0; i < b.N; i++ {
+			_, _ = c.Parse(input, "bench.vcl")
+		}
+	})
+	b.Run("disk", func(b *testing.B) {
+		dir := b.TempDir()
+		newCache(b, WithDir(dir, 0)).Parse(input, "bench.vcl")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// A new cache each time, as a new process would have
+			c := newCache(b, WithDir(dir, 0), WithMaxEntries(1))
+			if _, err := c.Parse(input, "bench.vcl"); err != nil || c.Stats().DiskHits != 1 {
+				b.Fatalf("Expected a disk hit, got %+v: %v", c.Stats(), err)
+			}
+		}
+	})
+	b.Run("gob", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var decoded ast.Program
+			if err := gob.NewDecoder(bytes.NewReader(encoded.Bytes())).Decode(&decoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// nodeTypes are the node types that interface fields of the AST can hold
+var nodeTypes = []ast.Node{
+	&ast.Program{}, &ast.VCLVersionDecl{}, &ast.ImportDecl{}, &ast.IncludeDecl{},
+	&ast.BackendDecl{}, &ast.BackendProperty{}, &ast.ProbeDecl{}, &ast.ProbeProperty{},
+	&ast.ACLDecl{}, &ast.ACLEntry{}, &ast.SubDecl{},
+
+	&ast.BlockStatement{}, &ast.ExpressionStatement{}, &ast.IfStatement{},
+	&ast.SetStatement{}, &ast.UnsetStatement{}, &ast.CallStatement{},
+	&ast.ReturnStatement{}, &ast.SyntheticStatement{}, &ast.ErrorStatement{},
+	&ast.RestartStatement{}, &ast.CSourceStatement{}, &ast.NewStatement{},
+
+	&ast.BinaryExpression{}, &ast.UnaryExpression{}, &ast.CallExpression{},
+	&ast.MemberExpression{}, &ast.IndexExpression{}, &ast.ParenthesizedExpression{},
+	&ast.RegexMatchExpression{}, &ast.AssignmentExpression{}, &ast.UpdateExpression{},
+	&ast.ArrayExpression{}, &ast.ObjectExpression{}, &ast.Property{},
+	&ast.VariableExpression{}, &ast.TimeExpression{}, &ast.IPExpression{},
+	&ast.ErrorExpression{},
+
+	&ast.Identifier{}, &ast.StringLiteral{}, &ast.IntegerLiteral{},
+	&ast.FloatLiteral{}, &ast.BooleanLiteral{}, &ast.DurationLiteral{},
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/perbu/vclparser/pkg/ast/astproto"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+	pb "github.com/perbu/vclparser/proto/vclparser/v1"
+)
+
+// storeFormat is the version of the file format of the store. It is part
+// of the key, so files of other versions are never read and age out.
+const storeFormat = 1
+
+// storeSuffix ends the names of the files of a store
+const storeSuffix = ".vclcache"
+
+// store keeps parse results in a directory, one file per key. A nil store
+// keeps nothing.
+type store struct {
+	dir      string
+	maxBytes int64
+
+	mutex sync.Mutex
+	// size is the size of the files as of the last scan, plus the files
+	// written since
+	size int64
+}
+
+// storedResult is a parse result as written to a file. The tree is kept as
+// its protocol buffer message, which decodes faster than gob does nodes.
+type storedResult struct {
+	Program    []byte
+	Warnings   []parser.Warning
+	Comments   []lexer.Token
+	TokenCount int
+}
+
+func openStore(dir string, maxBytes int64) (*store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &store{dir: dir, maxBytes: maxBytes}
+	if _, err := s.scan(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *store) path(key string) string {
+	return filepath.Join(s.dir, key+storeSuffix)
+}
+
+// get reads the result for key. Files that cannot be decoded are removed.
+func (s *store) get(key string) (*parser.ParseResult, bool) {
+	if s == nil {
+		return nil, false
+	}
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var stored storedResult
+	var msg pb.Program
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&stored); err != nil || proto.Unmarshal(stored.Program, &msg) != nil {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	// The modification time tells which files were used last
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &parser.ParseResult{
+		Program:    astproto.ToProgram(&msg),
+		Warnings:   stored.Warnings,
+		Comments:   stored.Comments,
+		TokenCount: stored.TokenCount,
+	}, true
+}
+
+// put writes result for key unless it has errors, whose trees are partial
+// and which are quick to parse again. Failing to write only loses the
+// entry.
+func (s *store) put(key string, result *parser.ParseResult) {
+	if s == nil || len(result.Errors) > 0 {
+		return
+	}
+	program, err := proto.Marshal(astproto.FromProgram(result.Program))
+	if err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	err = gob.NewEncoder(&buf).Encode(storedResult{
+		Program:    program,
+		Warnings:   result.Warnings,
+		Comments:   result.Comments,
+		TokenCount: result.TokenCount,
+	})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first, so other processes never read a
+	// partial file
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.size += int64(buf.Len())
+	if s.size > s.maxBytes {
+		_, _ = s.prune()
+	}
+}
+
+// storedFile is a file of the store found by scan
+type storedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// scan lists the files of the store, least recently used first, and
+// updates the size
+func (s *store) scan() ([]storedFile, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []storedFile
+	s.size = 0
+	for _, dirEntry := range dirEntries {
+		if !strings.HasSuffix(dirEntry.Name(), storeSuffix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			// Removed by another process meanwhile
+			continue
+		}
+		files = append(files, storedFile{
+			path:    filepath.Join(s.dir, dirEntry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		s.size += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	return files, nil
+}
+
+// prune removes the least recently used files until the store takes at
+// most three quarters of its limit, so that it is not pruned again on the
+// next write. It returns the number of files removed.
+func (s *store) prune() (int, error) {
+	files, err := s.scan()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, file := range files {
+		if s.size <= s.maxBytes/4*3 {
+			break
+		}
+		if err := os.Remove(file.path); err == nil || os.IsNotExist(err) {
+			s.size -= file.size
+			removed++
+		}
+	}
+	return removed, nil
+}
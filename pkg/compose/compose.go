@@ -0,0 +1,340 @@
+// Package compose merges VCL fragments, such as the per-tenant snippets of
+// a hosting platform, into one program.
+//
+// Declarations are laid out in a fixed order: imports, probes, backends,
+// ACLs and other declarations, custom subroutines, and finally the
+// built-in subroutines in the order varnishd runs them. Within each group
+// fragments keep the order they are given in. VCL concatenates repeated
+// definitions of a built-in subroutine, so the first fragment's vcl_recv
+// runs first, and the first fragment's first backend is the default.
+//
+// Custom subroutines with the same name in several fragments are renamed
+// in all but the first, prefixed with the fragment name. Backends, ACLs,
+// probes and VMOD objects cannot be renamed without changing what a
+// fragment means, so duplicates are reported as conflicts instead.
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+// Fragment is a parsed piece of VCL to compose. Name identifies it in
+// conflicts and prefixes its renamed subroutines.
+type Fragment struct {
+	Name    string
+	Program *ast.Program
+}
+
+// Rename records a custom subroutine renamed to avoid a collision
+type Rename struct {
+	Fragment string
+	From     string
+	To       string
+}
+
+// Result is a composed program
+type Result struct {
+	Program *ast.Program
+	Renames []Rename
+}
+
+// Conflict is a name declared by several fragments that cannot be renamed
+// automatically, or fragments disagreeing on the VCL version or an import
+type Conflict struct {
+	// Kind is "backend", "acl", "probe", "object", "import" or "version"
+	Kind      string
+	Name      string
+	Fragments []string
+}
+
+func (c Conflict) String() string {
+	if c.Kind == "version" {
+		return fmt.Sprintf("fragments %s declare different VCL versions", strings.Join(c.Fragments, ", "))
+	}
+	return fmt.Sprintf("%s %s is declared by fragments %s", c.Kind, c.Name, strings.Join(c.Fragments, ", "))
+}
+
+// ConflictError is returned by Compose when fragments conflict
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	messages := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		messages[i] = c.String()
+	}
+	return "conflicting fragments: " + strings.Join(messages, "; ")
+}
+
+// builtinOrder lists the built-in subroutines in the order varnishd runs
+// them, which is the order they are written to the composed program
+var builtinOrder = []string{
+	"vcl_init", "vcl_recv", "vcl_pipe", "vcl_pass", "vcl_hash", "vcl_purge", "vcl_hit",
+	"vcl_miss", "vcl_deliver", "vcl_synth", "vcl_backend_fetch", "vcl_backend_response",
+	"vcl_backend_refresh", "vcl_backend_error", "vcl_fini",
+}
+
+// Compose merges the fragments into one program. The fragments' programs
+// are renamed in place and their declarations reused, so they should not
+// be used afterwards. Conflicts are returned as a *ConflictError.
+func Compose(fragments ...Fragment) (*Result, error) {
+	if err := checkConflicts(fragments); err != nil {
+		return nil, err
+	}
+	renames, err := renameSubroutines(fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	program := &ast.Program{}
+	var imports, probes, backends, others, subs []ast.Declaration
+	builtins := make(map[string][]ast.Declaration)
+	importSeen := make(map[string]bool)
+	for _, f := range fragments {
+		if program.VCLVersion == nil && f.Program.VCLVersion != nil {
+			program.VCLVersion = f.Program.VCLVersion
+		}
+		for _, decl := range f.Program.Declarations {
+			switch d := decl.(type) {
+			case *ast.ImportDecl:
+				if key := importName(d); !importSeen[key] {
+					importSeen[key] = true
+					imports = append(imports, d)
+				}
+			case *ast.ProbeDecl:
+				probes = append(probes, d)
+			case *ast.BackendDecl:
+				backends = append(backends, d)
+			case *ast.SubDecl:
+				if strings.HasPrefix(d.Name, "vcl_") {
+					builtins[d.Name] = append(builtins[d.Name], d)
+				} else {
+					subs = append(subs, d)
+				}
+			default:
+				others = append(others, decl)
+			}
+		}
+	}
+
+	for _, group := range [][]ast.Declaration{imports, probes, backends, others, subs} {
+		program.Declarations = append(program.Declarations, group...)
+	}
+	for _, name := range builtinOrder {
+		program.Declarations = append(program.Declarations, builtins[name]...)
+		delete(builtins, name)
+	}
+	// Built-in subroutines of other Varnish versions go last, by name
+	var rest []string
+	for name := range builtins {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		program.Declarations = append(program.Declarations, builtins[name]...)
+	}
+	return &Result{Program: program, Renames: renames}, nil
+}
+
+// checkConflicts reports names that several fragments declare and that
+// cannot be renamed, imports of different VMODs under one name and
+// differing VCL versions
+func checkConflicts(fragments []Fragment) error {
+	var conflicts []Conflict
+	owners := make(map[string][]string)
+	kinds := make(map[string]string)
+	var order []string
+	declare := func(kind, name, fragment string) {
+		key := kind + " " + name
+		if _, ok := owners[key]; !ok {
+			order = append(order, key)
+			kinds[key] = kind
+		}
+		owners[key] = append(owners[key], fragment)
+	}
+
+	modules := make(map[string]string)
+	var versions []string
+	versionOwners := make(map[string][]string)
+	for _, f := range fragments {
+		if v := f.Program.VCLVersion; v != nil {
+			if _, ok := versionOwners[v.Version]; !ok {
+				versions = append(versions, v.Version)
+			}
+			versionOwners[v.Version] = append(versionOwners[v.Version], f.Name)
+		}
+		for _, decl := range f.Program.Declarations {
+			switch d := decl.(type) {
+			case *ast.BackendDecl:
+				declare("backend", d.Name, f.Name)
+			case *ast.ACLDecl:
+				declare("acl", d.Name, f.Name)
+			case *ast.ProbeDecl:
+				declare("probe", d.Name, f.Name)
+			case *ast.ImportDecl:
+				name := importName(d)
+				if module, ok := modules[name]; ok && module != d.Module {
+					conflicts = append(conflicts, Conflict{Kind: "import", Name: name, Fragments: []string{f.Name}})
+				}
+				modules[name] = d.Module
+			}
+		}
+		for _, name := range objects(f.Program) {
+			declare("object", name, f.Name)
+		}
+	}
+
+	if len(versions) > 1 {
+		var names []string
+		for _, v := range versions {
+			names = append(names, versionOwners[v]...)
+		}
+		conflicts = append(conflicts, Conflict{Kind: "version", Fragments: names})
+	}
+	for _, key := range order {
+		if len(owners[key]) > 1 {
+			name := strings.TrimPrefix(key, kinds[key]+" ")
+			conflicts = append(conflicts, Conflict{Kind: kinds[key], Name: name, Fragments: owners[key]})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// renameSubroutines renames custom subroutines declared by an earlier
+// fragment, or clashing with a symbol of another fragment, to
+// <fragment>_<name>
+func renameSubroutines(fragments []Fragment) ([]Rename, error) {
+	taken := make(map[string]bool)
+	for _, f := range fragments {
+		for _, name := range symbols(f.Program) {
+			taken[name] = true
+		}
+	}
+
+	var renames []Rename
+	subOwner := make(map[string]int)
+	for i, f := range fragments {
+		for _, name := range customSubs(f.Program) {
+			owner, declared := subOwner[name]
+			if !declared && !clashes(fragments, i, name) {
+				subOwner[name] = i
+				continue
+			}
+			if declared && owner == i {
+				continue
+			}
+			newName := uniqueName(prefix(f.Name)+name, taken)
+			if _, _, err := analyzer.Rename(f.Program, types.SymbolSubroutine, name, newName); err != nil {
+				return nil, fmt.Errorf("fragment %s: %w", f.Name, err)
+			}
+			taken[newName] = true
+			renames = append(renames, Rename{Fragment: f.Name, From: name, To: newName})
+		}
+	}
+	return renames, nil
+}
+
+// clashes reports whether name is a symbol other than a custom subroutine
+// in a fragment other than fragments[i]
+func clashes(fragments []Fragment, i int, name string) bool {
+	for j, f := range fragments {
+		if j == i {
+			continue
+		}
+		subs := make(map[string]bool)
+		for _, sub := range customSubs(f.Program) {
+			subs[sub] = true
+		}
+		for _, symbol := range symbols(f.Program) {
+			if symbol == name && !subs[name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var invalidName = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// prefix turns a fragment name into a prefix for subroutine names
+func prefix(fragment string) string {
+	p := strings.Trim(invalidName.ReplaceAllString(fragment, "_"), "_")
+	if p == "" || !(p[0] >= 'A' && p[0] <= 'Z' || p[0] >= 'a' && p[0] <= 'z') {
+		p = "f" + p
+	}
+	return p + "_"
+}
+
+func uniqueName(name string, taken map[string]bool) string {
+	candidate := name
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s_%d", name, n)
+	}
+	return candidate
+}
+
+// customSubs returns the names of the custom subroutines of a program
+func customSubs(program *ast.Program) []string {
+	var names []string
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && !strings.HasPrefix(sub.Name, "vcl_") {
+			names = append(names, sub.Name)
+		}
+	}
+	return names
+}
+
+// symbols returns the names a program declares in the shared namespace of
+// backends, ACLs, probes, subroutines, imports and objects
+func symbols(program *ast.Program) []string {
+	var names []string
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.BackendDecl:
+			names = append(names, d.Name)
+		case *ast.ACLDecl:
+			names = append(names, d.Name)
+		case *ast.ProbeDecl:
+			names = append(names, d.Name)
+		case *ast.SubDecl:
+			names = append(names, d.Name)
+		case *ast.ImportDecl:
+			names = append(names, importName(d))
+		}
+	}
+	return append(names, objects(program)...)
+}
+
+// objects returns the names of the VMOD objects created with new
+func objects(program *ast.Program) []string {
+	var names []string
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if ns, ok := c.Node().(*ast.NewStatement); ok {
+			if ident, ok := ns.Name.(*ast.Identifier); ok {
+				names = append(names, ident.Name)
+			}
+			return false
+		}
+		return true
+	}, nil)
+	return names
+}
+
+// importName is the name an import is known by
+func importName(d *ast.ImportDecl) string {
+	if d.Alias != "" {
+		return d.Alias
+	}
+	return d.Module
+}
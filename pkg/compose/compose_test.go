@@ -0,0 +1,147 @@
+package compose
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+func fragment(t *testing.T, name, src string) Fragment {
+	t.Helper()
+	program, err := parser.Parse(src, name+".vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", name, err)
+	}
+	return Fragment{Name: name, Program: program}
+}
+
+func TestCompose(t *testing.T) {
+	tenantA := fragment(t, "tenant-a", `vcl 4.1;
+import std;
+backend a { .host = "192.0.2.1"; }
+sub normalize { set req.http.X-Tenant = "a"; }
+sub vcl_recv {
+	if (req.http.host == "a.example.com") {
+		set req.backend_hint = a;
+		call normalize;
+	}
+}
+`)
+	tenantB := fragment(t, "tenant-b", `vcl 4.1;
+import std;
+backend b { .host = "192.0.2.2"; }
+sub vcl_deliver { unset resp.http.Server; }
+sub normalize { set req.http.X-Tenant = "b"; }
+sub vcl_recv {
+	if (req.http.host == "b.example.com") {
+		set req.backend_hint = b;
+		call normalize;
+	}
+}
+`)
+
+	result, err := Compose(tenantA, tenantB)
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+	if len(result.Renames) != 1 || result.Renames[0] != (Rename{Fragment: "tenant-b", From: "normalize", To: "tenant_b_normalize"}) {
+		t.Errorf("unexpected renames: %+v", result.Renames)
+	}
+
+	var order []string
+	for _, decl := range result.Program.Declarations {
+		switch d := decl.(type) {
+		case *ast.ImportDecl:
+			order = append(order, "import "+d.Module)
+		case *ast.BackendDecl:
+			order = append(order, "backend "+d.Name)
+		case *ast.SubDecl:
+			order = append(order, "sub "+d.Name)
+		}
+	}
+	want := "import std, backend a, backend b, sub normalize, sub tenant_b_normalize, sub vcl_recv, sub vcl_recv, sub vcl_deliver"
+	if got := strings.Join(order, ", "); got != want {
+		t.Errorf("declaration order:\n got %s\nwant %s", got, want)
+	}
+
+	var out strings.Builder
+	if err := printer.Fprint(&out, result.Program); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "call tenant_b_normalize;") {
+		t.Errorf("call in tenant-b not renamed:\n%s", out.String())
+	}
+	if _, err := parser.Parse(out.String(), "composed.vcl"); err != nil {
+		t.Errorf("composed program does not parse: %v\n%s", err, out.String())
+	}
+}
+
+func TestComposeConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		kind  string
+		label string
+	}{
+		{
+			name: "backend",
+			a:    `vcl 4.1; backend origin { .host = "192.0.2.1"; }`,
+			b:    `vcl 4.1; backend origin { .host = "192.0.2.2"; }`,
+			kind: "backend", label: "origin",
+		},
+		{
+			name: "acl",
+			a:    `vcl 4.1; acl office { "192.0.2.0"/24; }`,
+			b:    `vcl 4.1; acl office { "198.51.100.0"/24; }`,
+			kind: "acl", label: "office",
+		},
+		{
+			name: "version",
+			a:    `vcl 4.0; backend a { .host = "192.0.2.1"; }`,
+			b:    `vcl 4.1; backend b { .host = "192.0.2.2"; }`,
+			kind: "version",
+		},
+		{
+			name: "import alias",
+			a:    `vcl 4.1; import std util;`,
+			b:    `vcl 4.1; import directors util;`,
+			kind: "import", label: "util",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compose(fragment(t, "a", tt.a), fragment(t, "b", tt.b))
+			var conflictErr *ConflictError
+			if !errors.As(err, &conflictErr) {
+				t.Fatalf("expected a ConflictError, got %v", err)
+			}
+			if len(conflictErr.Conflicts) != 1 {
+				t.Fatalf("expected one conflict, got %+v", conflictErr.Conflicts)
+			}
+			c := conflictErr.Conflicts[0]
+			if c.Kind != tt.kind || c.Name != tt.label {
+				t.Errorf("conflict = %+v, want kind %s name %s", c, tt.kind, tt.label)
+			}
+		})
+	}
+}
+
+func TestComposeRenamesSubClashingWithSymbol(t *testing.T) {
+	a := fragment(t, "a", `vcl 4.1;
+sub origin { set req.http.X = "1"; }
+sub vcl_recv { call origin; }
+`)
+	b := fragment(t, "b", `vcl 4.1; backend origin { .host = "192.0.2.1"; }`)
+
+	result, err := Compose(a, b)
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+	if len(result.Renames) != 1 || result.Renames[0].To != "a_origin" {
+		t.Errorf("unexpected renames: %+v", result.Renames)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/lint"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// NewAnalyzer returns an analyzer checking VMOD usage against registry with
+// the settings of c: the Varnish release, the levels of the optional
+// checks, features, labels, header policy, parallelism and metadata
+// overlays. The Fastly dialect gets the Fastly metadata.
+//
+// Settings that fail to apply are reported together in the error. The
+// analyzer is returned with the other settings applied all the same, for
+// callers such as the language server that carry on regardless.
+func (c *Config) NewAnalyzer(registry *vmod.Registry) (*analyzer.Analyzer, error) {
+	a := analyzer.NewAnalyzer(registry)
+	if dialect, _ := lexer.ParseDialect(c.Parser.Dialect); dialect == lexer.DialectFastly {
+		a = analyzer.NewAnalyzerWithMetadata(registry, metadata.NewFastly())
+	}
+
+	var errs []error
+	apply := func(key string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	if c.Analyzer.Features != nil {
+		features, err := analyzer.ParseFeatures(c.Analyzer.Features...)
+		if err == nil {
+			ac := a.Config()
+			ac.Features = features
+			a.SetConfig(ac)
+		}
+		apply("analyzer.features", err)
+	}
+	apply("varnish_version", a.SetVarnishVersion(c.VarnishVersion))
+	apply("analyzer.unreferenced", a.SetUnreferenced(c.Analyzer.Unreferenced))
+	apply("analyzer.fallthrough", a.SetFallthrough(c.Analyzer.Fallthrough))
+	apply("analyzer.backtracking", a.SetBacktracking(c.Analyzer.Backtracking))
+	apply("analyzer.deprecations", a.SetDeprecations(c.Analyzer.Deprecations))
+	apply("analyzer.backend_audit", a.SetBackendAudit(c.Analyzer.BackendAudit))
+	a.SetLabels(c.Analyzer.Labels...)
+	a.SetHeaderPolicy(c.Analyzer.Headers.Allow, c.Analyzer.Headers.Deny)
+	a.SetParallelism(c.Analyzer.Jobs)
+	for _, overlay := range c.Metadata.Overlays {
+		apply("metadata.overlays", a.Metadata().LoadOverlayFile(overlay))
+	}
+	return a, errors.Join(errs...)
+}
+
+// NewLinter returns a linter running the analyzer of NewAnalyzer with the
+// rule levels of c. Errors are reported as by NewAnalyzer, and the linter
+// is returned all the same.
+func (c *Config) NewLinter(registry *vmod.Registry) (*lint.Linter, error) {
+	a, err := c.NewAnalyzer(registry)
+	linter := lint.New(a)
+	if lerr := linter.Configure(c.Lint.Rules); lerr != nil {
+		err = errors.Join(err, fmt.Errorf("lint.rules: %w", lerr))
+	}
+	return linter, err
+}
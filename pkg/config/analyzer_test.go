@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestNewLinter(t *testing.T) {
+	src := `vcl 4.1;
+
+backend origin {
+    .host = "origin.example.com";
+}
+
+backend unused {
+    .host = "unused.example.com";
+}
+
+sub vcl_recv {
+    set req.backend_hint = origin;
+    if (req.url == "/") {
+        return (vcl(missing));
+    }
+}
+`
+	program, err := parser.Parse(src, "main.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Default()
+	cfg.Analyzer.Unreferenced = "error"
+	cfg.Analyzer.Labels = []string{"known"}
+	linter, err := cfg.NewLinter(vmod.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewLinter failed: %v", err)
+	}
+	var unreferenced, label bool
+	for _, d := range linter.Lint(program, "main.vcl", src) {
+		switch {
+		case strings.Contains(d.Message, "unused"):
+			unreferenced = d.Severity == analyzer.SeverityError
+		case strings.Contains(d.Message, "missing"):
+			label = true
+		}
+	}
+	if !unreferenced {
+		t.Error("Expected the unused backend reported as an error")
+	}
+	if !label {
+		t.Error("Expected the unknown label reported")
+	}
+}
+
+func TestNewAnalyzerErrors(t *testing.T) {
+	cfg := Default()
+	cfg.VarnishVersion = "nope"
+	cfg.Analyzer.Fallthrough = "loud"
+	cfg.Lint.Rules = map[string]string{"hash-client-split": "sometimes"}
+
+	linter, err := cfg.NewLinter(vmod.NewRegistry())
+	if linter == nil {
+		t.Fatal("Expected a linter despite the errors")
+	}
+	if err == nil {
+		t.Fatal("Expected errors for the invalid settings")
+	}
+	for _, key := range []string{"varnish_version", "analyzer.fallthrough", "lint.rules"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("Expected an error for %s, got %v", key, err)
+		}
+	}
+}
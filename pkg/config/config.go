@@ -0,0 +1,372 @@
+// Package config discovers and merges vclparser configuration.
+//
+// Settings are layered with increasing precedence: built-in defaults, the
+// user-level config file, the project's .vclparser.yaml, and finally command
+// line flags applied by the caller.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName is the name of the repo-level configuration file
+const ProjectFileName = ".vclparser.yaml"
+
+// Config holds the effective vclparser settings
+type Config struct {
+	// Format is the diagnostic output format used by the CLI
+	Format string `yaml:"format"`
+	// VarnishVersion is the Varnish release to validate against, such as
+	// "7.5" or "6.0-enterprise". Empty accepts everything the metadata knows.
+	VarnishVersion string         `yaml:"varnish_version"`
+	Parser         ParserConfig   `yaml:"parser"`
+	Analyzer       AnalyzerConfig `yaml:"analyzer"`
+	Lint           LintConfig     `yaml:"lint"`
+	VMOD           VMODConfig     `yaml:"vmod"`
+	Metadata       MetadataConfig `yaml:"metadata"`
+	Plugins        PluginConfig   `yaml:"plugins"`
+	Cache          CacheConfig    `yaml:"cache"`
+
+	// Sources lists the files that contributed to this config, lowest
+	// precedence first
+	Sources []string `yaml:"-"`
+}
+
+// ParserConfig mirrors the tunables of parser.Config
+type ParserConfig struct {
+	DisableInlineC bool `yaml:"disable_inline_c"`
+	MaxErrors      int  `yaml:"max_errors"`
+	// Legacy accepts Varnish 2 and 3 VCL, mapped onto current VCL
+	Legacy bool `yaml:"legacy"`
+	// Dialect is the VCL variant to read, varnish (the default) or fastly
+	Dialect string `yaml:"dialect"`
+	// VCLPath lists the directories searched for relative include paths,
+	// like varnishd's vcl_path
+	VCLPath []string `yaml:"vcl_path"`
+	// RemoteIncludes controls includes of http://, https:// and s3:// URLs
+	RemoteIncludes RemoteIncludeConfig `yaml:"remote_includes"`
+}
+
+// RemoteIncludeConfig controls includes fetched from the network
+type RemoteIncludeConfig struct {
+	// Enable allows remote includes. It is off by default because the
+	// included VCL is fetched from the network.
+	Enable bool `yaml:"enable"`
+	// CacheDir keeps fetched files, so pinned files are fetched once and
+	// others can be read when the server is unreachable
+	CacheDir string `yaml:"cache_dir,omitempty"`
+	// Checksums pins URLs to the hex SHA-256 checksum of their content
+	Checksums map[string]string `yaml:"checksums,omitempty"`
+	// RequireChecksums refuses URLs without a pinned checksum
+	RequireChecksums bool `yaml:"require_checksums,omitempty"`
+	// S3Endpoint is the S3-compatible service s3:// URLs are read from; by
+	// default AWS. Credentials come from the AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+	S3Endpoint string `yaml:"s3_endpoint,omitempty"`
+	S3Region   string `yaml:"s3_region,omitempty"`
+}
+
+// AnalyzerConfig controls optional analyzer checks
+type AnalyzerConfig struct {
+	// Unreferenced sets how declarations that are never used are reported:
+	// "warning", "error" as varnishd does, or "off"
+	Unreferenced string `yaml:"unreferenced"`
+	// Fallthrough sets how built-in subroutines that return on some paths
+	// but fall through to the built-in VCL on others are reported: "off",
+	// "info", "warning" or "error"
+	Fallthrough string `yaml:"fallthrough"`
+	// Backtracking sets how regular expressions prone to catastrophic
+	// backtracking are reported: "off", "info", "warning" or "error"
+	Backtracking string `yaml:"backtracking"`
+	// Deprecations sets how deprecated constructs such as req.esi are
+	// reported: "warning" (the default), "info", "error" or "off"
+	Deprecations string `yaml:"deprecations"`
+	// BackendAudit sets how backends without a health probe, unattached
+	// probes and backends requests are never routed to are reported:
+	// "off", "info", "warning" or "error"
+	BackendAudit string `yaml:"backend_audit"`
+	// Features are the optional language features enabled in varnishd,
+	// such as VCL_CONNECT. When unset every feature is accepted; an empty
+	// list enables none.
+	Features []string `yaml:"features"`
+	// Labels are the VCL labels loaded in varnishd that return (vcl(label))
+	// may refer to. When empty, label names are not checked.
+	Labels []string `yaml:"labels"`
+	// Headers is the policy for setting sensitive headers
+	Headers HeaderPolicy `yaml:"headers"`
+	// Jobs is how many analysis passes may run at the same time. 0 and 1
+	// run them one after the other.
+	Jobs int `yaml:"jobs"`
+}
+
+// LintConfig controls the lint rules
+type LintConfig struct {
+	// Rules sets the level of rules by ID, such as "hash-client-split" or a
+	// plugin rule: "info", "warning", "error" or "off"
+	Rules map[string]string `yaml:"rules"`
+}
+
+// HeaderPolicy lists headers that may not be set and the exceptions to them.
+// Entries are header names or variables such as resp.http.X-Backend and may
+// contain * wildcards.
+type HeaderPolicy struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// VMODConfig controls where VMOD definitions are loaded from
+type VMODConfig struct {
+	// VCCPaths are additional VCC files or directories of VCC files loaded on
+	// top of the embedded definitions
+	VCCPaths []string `yaml:"vcc_paths"`
+	// VMODPath lists the directories searched for the VCC files of imported
+	// modules that are not loaded otherwise, like varnishd's vmod_path
+	VMODPath []string `yaml:"vmod_path"`
+	// Conflicts decides which module is used when two files define the same
+	// one: replace (the later file, the default), error, prefer-higher (the
+	// higher $Module version) or prefer-first
+	Conflicts string `yaml:"conflicts,omitempty"`
+	// Profile limits the embedded VMODs to those a Varnish distribution
+	// ships, such as enterprise; all of them are loaded by default
+	Profile string `yaml:"profile,omitempty"`
+}
+
+// MetadataConfig controls the VCL language metadata
+type MetadataConfig struct {
+	// Overlays are JSON files merged over the embedded metadata, for
+	// instance to describe backend properties added by newer releases
+	Overlays []string `yaml:"overlays"`
+}
+
+// PluginConfig controls analyzer plugins
+type PluginConfig struct {
+	// Load enables loading the shared objects listed in Paths. It is off by
+	// default because a shared object runs arbitrary code in the process.
+	Load bool `yaml:"load"`
+	// Paths are Go plugin shared objects providing analyzer plugins
+	Paths []string `yaml:"paths"`
+	// Settings holds per-plugin configuration keyed by plugin name
+	Settings map[string]map[string]interface{} `yaml:"settings"`
+}
+
+// CacheConfig controls the on-disk parse cache of pkg/cache
+type CacheConfig struct {
+	// Dir keeps parse results between runs when set
+	Dir string `yaml:"dir"`
+	// MaxSizeMB is the size in megabytes Dir is kept below; 0 selects the
+	// default of pkg/cache
+	MaxSizeMB int `yaml:"max_size_mb"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Format: "vim",
+		Parser: ParserConfig{
+			DisableInlineC: false,
+			MaxErrors:      8,
+		},
+		Analyzer: AnalyzerConfig{
+			Unreferenced: "warning",
+		},
+	}
+}
+
+// Load builds the effective configuration for a project rooted at or above dir.
+// Missing config files are not an error.
+func Load(dir string) (*Config, error) {
+	cfg := Default()
+
+	if path, err := UserConfigPath(); err == nil {
+		if err := cfg.mergeFileIfExists(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if path, ok := FindProjectConfig(dir); ok {
+		if err := cfg.MergeFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// UserConfigPath returns the location of the user-level config file,
+// honouring XDG_CONFIG_HOME where the platform does
+func UserConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vclparser", "config.yaml"), nil
+}
+
+// FindProjectConfig searches dir and its parents for .vclparser.yaml. The
+// search stops at the first directory containing a .git entry.
+func FindProjectConfig(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		candidate := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// MergeFile overlays the settings in a YAML file onto the config. Keys absent
+// from the file keep their current values.
+func (c *Config) MergeFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := c.Merge(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	// Relative paths are resolved against the file that declared them
+	resolvePaths(c.Parser.VCLPath, filepath.Dir(path))
+	resolvePaths(c.VMOD.VCCPaths, filepath.Dir(path))
+	resolvePaths(c.VMOD.VMODPath, filepath.Dir(path))
+	resolvePaths(c.Metadata.Overlays, filepath.Dir(path))
+	resolvePaths(c.Plugins.Paths, filepath.Dir(path))
+	if dir := c.Parser.RemoteIncludes.CacheDir; dir != "" && !filepath.IsAbs(dir) {
+		c.Parser.RemoteIncludes.CacheDir = filepath.Join(filepath.Dir(path), dir)
+	}
+	if dir := c.Cache.Dir; dir != "" && !filepath.IsAbs(dir) {
+		c.Cache.Dir = filepath.Join(filepath.Dir(path), dir)
+	}
+	c.Sources = append(c.Sources, path)
+	return nil
+}
+
+// Merge overlays YAML-encoded settings onto the config
+func (c *Config) Merge(data []byte) error {
+	// Decoding into a copy of the current values means only keys present in
+	// the document are overwritten, and a bad document leaves c untouched
+	merged := *c
+	if c.Lint.Rules != nil {
+		// Maps are merged into, so decode into a copy of this one too
+		merged.Lint.Rules = make(map[string]string, len(c.Lint.Rules))
+		for id, level := range c.Lint.Rules {
+			merged.Lint.Rules[id] = level
+		}
+	}
+	if c.Parser.RemoteIncludes.Checksums != nil {
+		merged.Parser.RemoteIncludes.Checksums = make(map[string]string, len(c.Parser.RemoteIncludes.Checksums))
+		for u, sum := range c.Parser.RemoteIncludes.Checksums {
+			merged.Parser.RemoteIncludes.Checksums[u] = sum
+		}
+	}
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return err
+	}
+	if err := merged.Validate(); err != nil {
+		return err
+	}
+	*c = merged
+	return nil
+}
+
+// Validate reports settings that can never be valid
+func (c *Config) Validate() error {
+	if c.Parser.MaxErrors < 0 {
+		return fmt.Errorf("parser.max_errors must not be negative, got %d", c.Parser.MaxErrors)
+	}
+	if _, err := lexer.ParseDialect(c.Parser.Dialect); err != nil {
+		return fmt.Errorf("parser.dialect: %w", err)
+	}
+	if c.Cache.MaxSizeMB < 0 {
+		return fmt.Errorf("cache.max_size_mb must not be negative, got %d", c.Cache.MaxSizeMB)
+	}
+	if c.Analyzer.Jobs < 0 {
+		return fmt.Errorf("analyzer.jobs must not be negative, got %d", c.Analyzer.Jobs)
+	}
+	switch c.Analyzer.Unreferenced {
+	case "", "warning", "error", "off":
+	default:
+		return fmt.Errorf("analyzer.unreferenced must be warning, error or off, got %q", c.Analyzer.Unreferenced)
+	}
+	for _, check := range []struct{ name, level string }{
+		{"fallthrough", c.Analyzer.Fallthrough},
+		{"backtracking", c.Analyzer.Backtracking},
+		{"deprecations", c.Analyzer.Deprecations},
+		{"backend_audit", c.Analyzer.BackendAudit},
+	} {
+		switch check.level {
+		case "", "off", "info", "warning", "error":
+		default:
+			return fmt.Errorf("analyzer.%s must be info, warning, error or off, got %q", check.name, check.level)
+		}
+	}
+	for id, level := range c.Lint.Rules {
+		switch level {
+		case "off", "info", "warning", "error":
+		default:
+			return fmt.Errorf("lint.rules.%s must be info, warning, error or off, got %q", id, level)
+		}
+	}
+	for _, pattern := range append(append([]string(nil), c.Analyzer.Headers.Allow...), c.Analyzer.Headers.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("analyzer.headers: invalid pattern %q", pattern)
+		}
+	}
+	for u, sum := range c.Parser.RemoteIncludes.Checksums {
+		if len(sum) != 64 || strings.Trim(strings.ToLower(sum), "0123456789abcdef") != "" {
+			return fmt.Errorf("parser.remote_includes.checksums: %s must be a hex SHA-256 checksum, got %q", u, sum)
+		}
+	}
+	switch c.VMOD.Conflicts {
+	case "", "replace", "error", "prefer-higher", "prefer-first":
+	default:
+		return fmt.Errorf("vmod.conflicts must be replace, error, prefer-higher or prefer-first, got %q", c.VMOD.Conflicts)
+	}
+	if c.VarnishVersion != "" {
+		if _, err := metadata.ParseRelease(c.VarnishVersion); err != nil {
+			return fmt.Errorf("varnish_version: %w", err)
+		}
+	}
+	return nil
+}
+
+// YAML renders the config in the same format it is read from
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func resolvePaths(paths []string, dir string) {
+	for i, p := range paths {
+		if !filepath.IsAbs(p) {
+			paths[i] = filepath.Join(dir, p)
+		}
+	}
+}
+
+func (c *Config) mergeFileIfExists(path string) error {
+	err := c.MergeFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
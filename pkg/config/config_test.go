@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLayering(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv("HOME", filepath.Join(root, "home"))
+
+	userPath, err := UserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, userPath, "format: emacs\nparser:\n  max_errors: 20\n")
+
+	project := filepath.Join(root, "project")
+	writeFile(t, filepath.Join(project, ProjectFileName), "parser:\n  max_errors: 3\n  vcl_path: [/etc/varnish, vcl]\nvmod:\n  vcc_paths: [vmods]\nmetadata:\n  overlays: [backends.json]\ncache:\n  dir: .vcl-cache\n")
+	subdir := filepath.Join(project, "vcl", "sites")
+	if err := os.MkdirAll(subdir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(subdir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Format != "emacs" {
+		t.Errorf("Format = %q, want emacs from user config", cfg.Format)
+	}
+	if cfg.Parser.MaxErrors != 3 {
+		t.Errorf("MaxErrors = %d, want 3 from project config", cfg.Parser.MaxErrors)
+	}
+	if cfg.Parser.DisableInlineC {
+		t.Error("DisableInlineC should keep its default")
+	}
+	if len(cfg.VMOD.VCCPaths) != 1 || cfg.VMOD.VCCPaths[0] != filepath.Join(project, "vmods") {
+		t.Errorf("VCCPaths = %v, want path relative to project config", cfg.VMOD.VCCPaths)
+	}
+	if want := []string{"/etc/varnish", filepath.Join(project, "vcl")}; len(cfg.Parser.VCLPath) != 2 ||
+		cfg.Parser.VCLPath[0] != want[0] || cfg.Parser.VCLPath[1] != want[1] {
+		t.Errorf("VCLPath = %v, want %v", cfg.Parser.VCLPath, want)
+	}
+	if len(cfg.Metadata.Overlays) != 1 || cfg.Metadata.Overlays[0] != filepath.Join(project, "backends.json") {
+		t.Errorf("Overlays = %v, want path relative to project config", cfg.Metadata.Overlays)
+	}
+	if cfg.Cache.Dir != filepath.Join(project, ".vcl-cache") {
+		t.Errorf("Cache.Dir = %q, want path relative to project config", cfg.Cache.Dir)
+	}
+	if len(cfg.Sources) != 2 {
+		t.Errorf("Sources = %v, want user and project config", cfg.Sources)
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Format != "vim" || cfg.Parser.MaxErrors != 8 {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+	if len(cfg.Sources) != 0 {
+		t.Errorf("Sources = %v, want none", cfg.Sources)
+	}
+}
+
+func TestFindProjectConfigStopsAtRepoRoot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ProjectFileName), "format: vim\n")
+	repo := filepath.Join(root, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, ok := FindProjectConfig(repo); ok {
+		t.Errorf("expected search to stop at repo root, found %s", path)
+	}
+	if _, ok := FindProjectConfig(root); !ok {
+		t.Error("expected to find config in start directory")
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	cfg := Default()
+	if err := cfg.Merge([]byte("parser:\n  max_errors: -1\n")); err == nil {
+		t.Error("expected validation error")
+	}
+	if cfg.Parser.MaxErrors != 8 {
+		t.Errorf("failed merge modified config: MaxErrors = %d", cfg.Parser.MaxErrors)
+	}
+	if err := cfg.Merge([]byte("parser: [")); err == nil {
+		t.Error("expected YAML error")
+	}
+	if err := cfg.Merge([]byte("analyzer:\n  unreferenced: fatal\n")); err == nil {
+		t.Error("expected invalid analyzer.unreferenced error")
+	}
+	if err := cfg.Merge([]byte("analyzer:\n  headers:\n    deny: [\"X-[\"]\n")); err == nil {
+		t.Error("expected invalid analyzer.headers error")
+	}
+	if err := cfg.Merge([]byte("lint:\n  rules:\n    deprecated: quiet\n")); err == nil {
+		t.Error("expected invalid lint.rules error")
+	}
+	if cfg.Lint.Rules != nil {
+		t.Errorf("failed merge modified config: Lint.Rules = %v", cfg.Lint.Rules)
+	}
+	if err := cfg.Merge([]byte("vmod:\n  conflicts: newest\n")); err == nil {
+		t.Error("expected invalid vmod.conflicts error")
+	}
+	if err := cfg.Merge([]byte("parser:\n  remote_includes:\n    checksums:\n      https://example.com/a.vcl: abc\n")); err == nil {
+		t.Error("expected invalid parser.remote_includes.checksums error")
+	}
+	if err := cfg.Merge([]byte("varnish_version: latest\n")); err == nil {
+		t.Error("expected invalid varnish_version error")
+	}
+	if err := cfg.Merge([]byte("varnish_version: \"6.0-enterprise\"\n")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
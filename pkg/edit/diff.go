@@ -0,0 +1,220 @@
+package edit
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around changes
+const contextLines = 3
+
+// maxDiffWork bounds the work of the line diff. Beyond it, the differing
+// middle of the texts is shown as removed and added as a whole.
+const maxDiffWork = 1 << 24
+
+// UnifiedFile returns a unified diff of two versions of a file, with the
+// a/ and b/ names git uses, or "" if the texts are equal. The file name is
+// cleaned and made relative, so /etc/varnish/default.vcl becomes
+// a/etc/varnish/default.vcl rather than a//etc/varnish/default.vcl.
+func UnifiedFile(filename, oldText, newText string) string {
+	name := strings.TrimLeft(filepath.ToSlash(filepath.Clean(filename)), "/")
+	return Unified("a/"+name, "b/"+name, oldText, newText)
+}
+
+// Unified returns a unified diff of two texts, with the names given for
+// the --- and +++ lines, or "" if the texts are equal
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	a, b := splitLines(oldText), splitLines(newText)
+	ops := diffLines(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// A hunk runs from the context before a change to the context after
+		// the last change less than two contexts away
+		start := max(i-contextLines, 0)
+		end := i + 1
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*contextLines {
+				break
+			}
+		}
+		end = min(end+contextLines, len(ops))
+		writeHunk(&sb, ops[start:end])
+		i = end
+	}
+	return sb.String()
+}
+
+// op is one line of a diff: ' ' for a line of both texts, '-' for a line
+// of the old text only and '+' for a line of the new text only. Lines are
+// counted from 1.
+type op struct {
+	kind       byte
+	text       string
+	oldN, newN int
+}
+
+func writeHunk(sb *strings.Builder, ops []op) {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+	for _, o := range ops {
+		if o.kind != '+' {
+			if oldCount == 0 {
+				oldStart = o.oldN
+			}
+			oldCount++
+		}
+		if o.kind != '-' {
+			if newCount == 0 {
+				newStart = o.newN
+			}
+			newCount++
+		}
+	}
+	// An empty range is given by the line before it
+	if oldCount == 0 {
+		oldStart = ops[0].oldN - 1
+	}
+	if newCount == 0 {
+		newStart = ops[0].newN - 1
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, o := range ops {
+		sb.WriteByte(o.kind)
+		sb.WriteString(o.text)
+		if !strings.HasSuffix(o.text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits text after each newline
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script turning a into b, with
+// Myers' algorithm
+func diffLines(a, b []string) []op {
+	// Common lines at the start and end are not part of the search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	equal := func(i, j int) {
+		ops = append(ops, op{kind: ' ', text: a[i], oldN: i + 1, newN: j + 1})
+	}
+	for i := 0; i < prefix; i++ {
+		equal(i, i)
+	}
+	ops = append(ops, middle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+	for k := suffix; k > 0; k-- {
+		equal(len(a)-k, len(b)-k)
+	}
+	return ops
+}
+
+// middle diffs the lines between the common prefix and suffix, which start
+// at line offA+1 of the old and offB+1 of the new text
+func middle(a, b []string, offA, offB int) []op {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		if d*(n+m) > maxDiffWork {
+			break
+		}
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // down: a line of b is added
+			} else {
+				x = v[offset+k-1] + 1 // right: a line of a is removed
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	var ops []op
+	if !found {
+		for i, line := range a {
+			ops = append(ops, op{kind: '-', text: line, oldN: offA + i + 1, newN: offB + 1})
+		}
+		for j, line := range b {
+			ops = append(ops, op{kind: '+', text: line, oldN: offA + n + 1, newN: offB + j + 1})
+		}
+		return ops
+	}
+
+	// Walk back through the saved frontiers from the end
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{kind: ' ', text: a[x], oldN: offA + x + 1, newN: offB + y + 1})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, op{kind: '+', text: b[y], oldN: offA + x + 1, newN: offB + y + 1})
+			} else {
+				x--
+				ops = append(ops, op{kind: '-', text: a[x], oldN: offA + x + 1, newN: offB + y + 1})
+			}
+		}
+	}
+	slices.Reverse(ops)
+	return ops
+}
@@ -0,0 +1,489 @@
+// Package edit turns changes made to a syntax tree back into changes of
+// the source it was parsed from. Tools rewriting VCL, such as the
+// rewriter of package ast or the builders of package build, change the
+// tree; Edits compares it with the source and returns the smallest text
+// edits that make the source match it, so comments, blank lines and the
+// formatting of everything that was not changed stay as they were:
+//
+//	program, _ := parser.Parse(src, "default.vcl")
+//	ast.Apply(program, rewrite, nil)
+//	diff, err := edit.Diff(src, "default.vcl", program)
+//
+// Changed nodes are printed by the printer, with the indentation of the
+// source. Nodes are matched to the source by their positions, so nodes
+// moved within a list are printed anew where they end up.
+package edit
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+// Edits returns the edits turning src, the source program was parsed
+// from, into program. The source must parse without errors.
+func Edits(src, filename string, program *ast.Program) ([]analyzer.TextEdit, error) {
+	original, err := parser.Parse(src, filename)
+	if err != nil {
+		return nil, fmt.Errorf("parsing original source: %w", err)
+	}
+	d := &differ{src: newSource(src, filename)}
+	d.printer = printer.DefaultConfig()
+	d.printer.Indent = detectIndent(src, d.printer.Indent)
+
+	edits, ok := d.diff(program, original)
+	if !ok {
+		var b strings.Builder
+		if err := d.printer.Fprint(&b, program); err != nil {
+			return nil, err
+		}
+		edits = []analyzer.TextEdit{d.edit(0, len(src), b.String())}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start.Offset < edits[j].Start.Offset })
+	return edits, nil
+}
+
+// Apply returns src with the changes of program applied, like
+// analyzer.ApplyEdits of the result of Edits
+func Apply(src, filename string, program *ast.Program) (string, error) {
+	edits, err := Edits(src, filename, program)
+	if err != nil {
+		return "", err
+	}
+	return analyzer.ApplyEdits(src, edits)
+}
+
+// Diff returns the changes of program as a unified diff of filename, empty
+// if program matches src
+func Diff(src, filename string, program *ast.Program) (string, error) {
+	modified, err := Apply(src, filename, program)
+	if err != nil {
+		return "", err
+	}
+	return UnifiedFile(filename, src, modified), nil
+}
+
+// detectIndent returns the indentation of the first indented line of src,
+// or fallback if no line is indented
+func detectIndent(src, fallback string) string {
+	for _, line := range strings.Split(src, "\n") {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indent != "" && len(indent) < len(line) {
+			return indent
+		}
+	}
+	return fallback
+}
+
+// differ compares a modified tree with the tree parsed from the source
+type differ struct {
+	src     *source
+	printer *printer.Config
+	decl    ast.Declaration // top-level declaration being compared
+	err     error
+}
+
+var (
+	nodeType     = reflect.TypeOf((*ast.Node)(nil)).Elem()
+	positionType = reflect.TypeOf(lexer.Position{})
+	baseType     = reflect.TypeOf(ast.BaseNode{})
+)
+
+// isNode reports whether values of t are nodes or node interfaces
+func isNode(t reflect.Type) bool {
+	return (t.Kind() == reflect.Interface || t.Kind() == reflect.Pointer) && t.Implements(nodeType)
+}
+
+func isNil(n ast.Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// diff returns the edits turning the text of o into m, which have the same
+// type. It returns false if m differs from o in a way that needs o printed
+// anew as a whole.
+func (d *differ) diff(m, o ast.Node) ([]analyzer.TextEdit, bool) {
+	if reflect.TypeOf(m) != reflect.TypeOf(o) {
+		return nil, false
+	}
+	mv, ov := reflect.ValueOf(m).Elem(), reflect.ValueOf(o).Elem()
+	t := mv.Type()
+
+	// Names, operators and literal values first: if they differ, the
+	// node is printed anew and its children need not be compared
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type == baseType || f.Type == positionType || isNode(f.Type) ||
+			(f.Type.Kind() == reflect.Slice && isNode(f.Type.Elem())) {
+			continue
+		}
+		if f.Type.Kind() == reflect.Map {
+			if !sameKeys(mv.Field(i), ov.Field(i)) {
+				return nil, false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(mv.Field(i).Interface(), ov.Field(i).Interface()) {
+			return nil, false
+		}
+	}
+
+	var edits []analyzer.TextEdit
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		var fieldEdits []analyzer.TextEdit
+		ok := true
+		switch {
+		case !f.IsExported() || f.Type == baseType || f.Type == positionType:
+			continue
+		case isNode(f.Type):
+			mc, _ := mv.Field(i).Interface().(ast.Node)
+			oc, _ := ov.Field(i).Interface().(ast.Node)
+			fieldEdits, ok = d.child(m, mc, oc)
+		case f.Type.Kind() == reflect.Slice && isNode(f.Type.Elem()):
+			fieldEdits, ok = d.list(m, o, f.Name, mv.Field(i), ov.Field(i))
+		case f.Type.Kind() == reflect.Map:
+			for _, key := range mv.Field(i).MapKeys() {
+				mc, _ := mv.Field(i).MapIndex(key).Interface().(ast.Node)
+				oc, _ := ov.Field(i).MapIndex(key).Interface().(ast.Node)
+				var e []analyzer.TextEdit
+				e, ok = d.child(m, mc, oc)
+				fieldEdits = append(fieldEdits, e...)
+				if !ok {
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		edits = append(edits, fieldEdits...)
+	}
+	return edits, true
+}
+
+func sameKeys(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for _, key := range a.MapKeys() {
+		if !b.MapIndex(key).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// isOperator reports whether the operands of n cannot be replaced by an
+// arbitrary expression without checking precedence
+func isOperator(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.BinaryExpression, *ast.RegexMatchExpression, *ast.UnaryExpression, *ast.MemberExpression:
+		return true
+	}
+	return false
+}
+
+// isAtom reports whether an expression can take the place of any operand
+func isAtom(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.Identifier, *ast.StringLiteral, *ast.IntegerLiteral, *ast.FloatLiteral,
+		*ast.BooleanLiteral, *ast.TimeExpression, *ast.IPExpression, *ast.VariableExpression,
+		*ast.ParenthesizedExpression, *ast.CallExpression, *ast.MemberExpression:
+		return true
+	}
+	return false
+}
+
+// child compares the node held by a field of parent
+func (d *differ) child(parent, m, o ast.Node) ([]analyzer.TextEdit, bool) {
+	switch {
+	case isNil(m) && isNil(o):
+		return nil, true
+	case isNil(m) || isNil(o):
+		return nil, false
+	}
+	if edits, ok := d.diff(m, o); ok {
+		return edits, true
+	}
+	if isOperator(parent) && !isAtom(m) {
+		return nil, false
+	}
+	// The parentheses of conditions and return actions are part of the
+	// statement, not of the expression
+	if e, ok := m.(*ast.ParenthesizedExpression); ok {
+		switch parent.(type) {
+		case *ast.IfStatement, *ast.ReturnStatement, *ast.SyntheticStatement:
+			m = e.Expression
+		}
+	}
+	return d.replace(o, m), true
+}
+
+// replace returns the edit printing m in place of o
+func (d *differ) replace(o, m ast.Node) []analyzer.TextEdit {
+	start, end := d.src.extent(o)
+	text := d.print(m, d.src.indentation(start))
+	return []analyzer.TextEdit{d.edit(start, end, text)}
+}
+
+// print formats n for a place in the source indented by indent
+func (d *differ) print(n ast.Node, indent string) string {
+	text, err := d.printer.Node(n)
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+	if indent == "" {
+		return text
+	}
+	// Lines are indented unless they continue a long string or inline C
+	var b strings.Builder
+	for _, tok := range lexer.ScanAll(text, "") {
+		if tok.Type != lexer.WHITESPACE {
+			b.WriteString(tok.Value)
+			continue
+		}
+		lines := strings.Split(tok.Value, "\n")
+		for i, line := range lines {
+			if i > 0 {
+				b.WriteByte('\n')
+				if i == len(lines)-1 {
+					b.WriteString(indent)
+				}
+			}
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+func (d *differ) edit(start, end int, text string) analyzer.TextEdit {
+	return analyzer.TextEdit{
+		Start:   d.src.lines.Position(start),
+		End:     d.src.lines.Position(end),
+		NewText: text,
+		Decl:    d.decl,
+	}
+}
+
+// lineList reports whether the elements of a list field are written one per
+// line, so that elements can be inserted and deleted on their own
+func lineList(parent ast.Node, field string) bool {
+	switch parent.(type) {
+	case *ast.Program, *ast.BlockStatement, *ast.BackendDecl, *ast.ProbeDecl, *ast.ACLDecl,
+		*ast.ObjectExpression:
+		return field == "Declarations" || field == "Statements" || field == "Properties" ||
+			field == "Entries"
+	}
+	return false
+}
+
+// list compares the nodes of a slice field of m and o
+func (d *differ) list(m, o ast.Node, field string, mv, ov reflect.Value) ([]analyzer.TextEdit, bool) {
+	ms := make([]ast.Node, mv.Len())
+	for i := range ms {
+		ms[i], _ = mv.Index(i).Interface().(ast.Node)
+	}
+	os := make([]ast.Node, ov.Len())
+	for i := range os {
+		os[i], _ = ov.Index(i).Interface().(ast.Node)
+	}
+
+	if !lineList(m, field) {
+		if len(ms) != len(os) {
+			return nil, false
+		}
+		var edits []analyzer.TextEdit
+		for i := range ms {
+			e, ok := d.child(m, ms[i], os[i])
+			if !ok {
+				return nil, false
+			}
+			edits = append(edits, e...)
+		}
+		return edits, true
+	}
+
+	// Pair the elements that kept their place in the source, in order
+	match := make([]int, len(ms))
+	next := 0
+	for i, mn := range ms {
+		match[i] = -1
+		if isNil(mn) || mn.Start().Line == 0 {
+			continue
+		}
+		for j := next; j < len(os); j++ {
+			if reflect.TypeOf(os[j]) == reflect.TypeOf(mn) &&
+				os[j].Start().Offset == mn.Start().Offset && os[j].End().Offset == mn.End().Offset {
+				match[i], next = j, j+1
+				break
+			}
+		}
+	}
+
+	matched := make([]bool, len(os))
+	for _, j := range match {
+		if j >= 0 {
+			matched[j] = true
+		}
+	}
+	sep, indent := d.layout(m, o, os)
+
+	if _, ok := m.(*ast.Program); ok {
+		d.decl = nil
+	}
+	if len(ms) > 0 && len(os) > 0 && !slices.Contains(matched, true) {
+		// Nothing was kept: the new elements replace the old ones
+		start, _ := d.src.extent(os[0])
+		_, end := d.src.extent(os[len(os)-1])
+		texts := make([]string, len(ms))
+		for i, n := range ms {
+			d.enter(m, field, n)
+			texts[i] = d.print(n, indent)
+		}
+		return []analyzer.TextEdit{d.edit(start, end, strings.Join(texts, sep))}, true
+	}
+
+	var edits []analyzer.TextEdit
+	for j, on := range os {
+		if !matched[j] {
+			edits = append(edits, d.delete(on))
+		}
+	}
+
+	for i := 0; i < len(ms); {
+		if match[i] >= 0 {
+			d.enter(m, field, ms[i])
+			e, ok := d.diff(ms[i], os[match[i]])
+			if !ok {
+				e = d.replace(os[match[i]], ms[i])
+			}
+			edits = append(edits, e...)
+			i++
+			continue
+		}
+		// A run of new elements goes after the previous element kept, or
+		// before the next one
+		run := i
+		for i < len(ms) && match[i] < 0 {
+			i++
+		}
+		var texts []string
+		for _, n := range ms[run:i] {
+			d.enter(m, field, n)
+			texts = append(texts, d.print(n, indent))
+		}
+		switch {
+		case run > 0:
+			_, end := d.src.extent(os[match[run-1]])
+			edits = append(edits, d.edit(end, end, sep+strings.Join(texts, sep)))
+		case i < len(ms):
+			start, _ := d.src.extent(os[match[i]])
+			edits = append(edits, d.edit(start, start, strings.Join(texts, sep)+sep))
+		default:
+			at, ok := d.emptyListPosition(o)
+			if !ok {
+				return nil, false
+			}
+			text := sep + strings.Join(texts, sep)
+			if !blankLineAt(d.src.text, at) {
+				// The closing brace was on the line of the opening one
+				start, _ := d.src.extent(o)
+				text += "\n" + d.src.indentation(start)
+			}
+			edits = append(edits, d.edit(at, at, text))
+		}
+	}
+	return edits, true
+}
+
+// enter records the top-level declaration an element of the program
+// belongs to
+func (d *differ) enter(parent ast.Node, field string, n ast.Node) {
+	if decl, ok := n.(ast.Declaration); ok && field == "Declarations" {
+		if _, ok := parent.(*ast.Program); ok {
+			d.decl = decl
+		}
+	}
+}
+
+// layout returns the separator written before an element of a line list
+// and the indentation of its lines
+func (d *differ) layout(m, o ast.Node, os []ast.Node) (sep, indent string) {
+	if _, ok := m.(*ast.Program); ok {
+		return "\n\n", ""
+	}
+	if len(os) > 0 {
+		start, _ := d.src.extent(os[0])
+		indent = d.src.indentation(start)
+	} else {
+		start, _ := d.src.extent(o)
+		indent = d.src.indentation(start) + d.printer.Indent
+	}
+	return "\n" + indent, indent
+}
+
+// emptyListPosition returns where elements are inserted into a list that
+// is empty in the source: after the version declaration of a program, or
+// after the opening brace of a block
+func (d *differ) emptyListPosition(o ast.Node) (int, bool) {
+	if program, ok := o.(*ast.Program); ok {
+		if program.VCLVersion == nil {
+			return 0, false
+		}
+		_, end := d.src.extent(program.VCLVersion)
+		return end, true
+	}
+	start, end := d.src.extent(o)
+	brace := strings.IndexByte(d.src.text[start:end], '{')
+	if brace < 0 {
+		return 0, false
+	}
+	return start + brace + 1, true
+}
+
+// delete returns the edit removing o, together with the line it was on
+// if nothing else is written there
+func (d *differ) delete(o ast.Node) analyzer.TextEdit {
+	start, end := d.src.extent(o)
+	lineStart := d.src.lineStart(start)
+	lineEnd := d.src.lineEnd(end)
+	text := d.src.text
+	rest := strings.TrimRight(text[end:lineEnd], "\n")
+	if d.src.onlySpace(lineStart, start) && d.src.onlySpace(end, end+len(rest)) {
+		start, end = lineStart, lineEnd
+		// Do not leave two blank lines where the element was
+		if (start == 0 || blankLineBefore(text, start)) && blankLineAt(text, end) {
+			end = d.src.lineEnd(end)
+		}
+	} else {
+		for end < len(text) && (text[end] == ' ' || text[end] == '\t') {
+			end++
+		}
+	}
+	return d.edit(start, end, "")
+}
+
+func blankLineBefore(text string, offset int) bool {
+	i := strings.LastIndexByte(text[:offset-1], '\n')
+	return strings.TrimSpace(text[i+1:offset]) == ""
+}
+
+func blankLineAt(text string, offset int) bool {
+	i := strings.IndexByte(text[offset:], '\n')
+	return i >= 0 && strings.TrimSpace(text[offset:offset+i]) == ""
+}
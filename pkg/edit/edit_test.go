@@ -0,0 +1,317 @@
+package edit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/build"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+const original = `vcl 4.1;
+
+import std;
+
+# Origin
+backend web1 {
+	.host = "192.0.2.1";   # primary
+	.port = "80";
+}
+
+sub vcl_recv {
+	# Normalize
+	if (req.http.host ~ "^www\.") {
+		set req.http.host = regsub(req.http.host, "^www\.", "");
+	}
+	unset req.http.Cookie;
+	return (hash);
+}
+`
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	program, err := parser.Parse(src, "default.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	return program
+}
+
+func sub(program *ast.Program, name string) *ast.SubDecl {
+	for _, decl := range program.Declarations {
+		if s, ok := decl.(*ast.SubDecl); ok && s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func apply(t *testing.T, program *ast.Program) string {
+	t.Helper()
+	out, err := Apply(original, "default.vcl", program)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if _, err := parser.Parse(out, "out.vcl"); err != nil {
+		t.Fatalf("Result does not parse: %v\n%s", err, out)
+	}
+	return out
+}
+
+func TestEditsUnchanged(t *testing.T) {
+	edits, err := Edits(original, "default.vcl", parse(t, original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 0 {
+		t.Errorf("Expected no edits, got %+v", edits)
+	}
+}
+
+func TestEditsReplace(t *testing.T) {
+	program := parse(t, original)
+	backend := program.Declarations[1].(*ast.BackendDecl)
+	backend.Properties[0].Value = build.String("192.0.2.10")
+
+	edits, err := Edits(original, "default.vcl", program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 || edits[0].NewText != `"192.0.2.10"` {
+		t.Fatalf("Expected one edit of the host, got %+v", edits)
+	}
+	out := apply(t, program)
+	want := strings.Replace(original, `"192.0.2.1"`, `"192.0.2.10"`, 1)
+	if out != want {
+		t.Errorf("Unexpected result:\n%s", out)
+	}
+}
+
+func TestEditsOperands(t *testing.T) {
+	program := parse(t, original)
+	cond := &sub(program, "vcl_recv").Body.Statements[0].(*ast.IfStatement).Condition
+	*cond = build.And(*cond, build.Eq(build.Var("req.method"), build.String("GET")))
+	out := apply(t, program)
+	if !strings.Contains(out, `if (req.http.host ~ "^www\." && req.method == "GET") {`) {
+		t.Errorf("Condition not replaced:\n%s", out)
+	}
+
+	// An operand replaced by an operator of lower precedence needs the
+	// whole expression printed with parentheses
+	program = parse(t, original)
+	match := sub(program, "vcl_recv").Body.Statements[0].(*ast.IfStatement).Condition.(*ast.RegexMatchExpression)
+	match.Left = build.Concat(build.Var("req.http.host"), build.String(":"))
+	out = apply(t, program)
+	if !strings.Contains(out, `if ((req.http.host + ":") ~ "^www\.") {`) && !strings.Contains(out, `if (req.http.host + ":" ~ "^www\.") {`) {
+		t.Errorf("Operand not replaced:\n%s", out)
+	}
+}
+
+func TestEditsInsertDelete(t *testing.T) {
+	program := parse(t, original)
+	recv := sub(program, "vcl_recv")
+	log, err := parser.ParseStatement(`std.log("recv");`, "snippet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop the unset and log before the return
+	recv.Body.Statements = []ast.Statement{recv.Body.Statements[0], log, recv.Body.Statements[2]}
+	program.Declarations = append(program.Declarations[:2], append([]ast.Declaration{
+		&ast.BackendDecl{Name: "web2", Properties: []*ast.BackendProperty{
+			{Name: "host", Value: build.String("192.0.2.2")},
+		}},
+	}, program.Declarations[2:]...)...)
+
+	out := apply(t, program)
+	want := strings.Replace(original, "\tunset req.http.Cookie;\n", "\tstd.log(\"recv\");\n", 1)
+	want = strings.Replace(want, "}\n\nsub vcl_recv", "}\n\nbackend web2 {\n\t.host = \"192.0.2.2\";\n}\n\nsub vcl_recv", 1)
+	if out != want {
+		t.Errorf("Unexpected result:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestEditsEmptyBlock(t *testing.T) {
+	src := "vcl 4.1;\nsub vcl_deliver {}\n"
+	program := parse(t, src)
+	deliver := sub(program, "vcl_deliver")
+	deliver.Body.Statements = append(deliver.Body.Statements, build.Unset("resp.http.Server"))
+	out, err := Apply(src, "default.vcl", program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "vcl 4.1;\nsub vcl_deliver {\n    unset resp.http.Server;\n}\n"; out != want {
+		t.Errorf("Unexpected result:\n%q", out)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	program := parse(t, original)
+	sub(program, "vcl_recv").Body.Statements[2].(*ast.ReturnStatement).Action = build.Var("pass")
+	diff, err := Diff(original, "default.vcl", program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- a/default.vcl
++++ b/default.vcl
+@@ -14,5 +14,5 @@
+ 		set req.http.host = regsub(req.http.host, "^www\.", "");
+ 	}
+ 	unset req.http.Cookie;
+-	return (hash);
++	return (pass);
+ }
+`
+	if diff != want {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+}
+
+func TestUnifiedFile(t *testing.T) {
+	tests := []struct {
+		filename string
+		header   string
+	}{
+		{"default.vcl", "--- a/default.vcl\n+++ b/default.vcl\n"},
+		{"/tmp/x.vcl", "--- a/tmp/x.vcl\n+++ b/tmp/x.vcl\n"},
+		{"./conf.d//recv.vcl", "--- a/conf.d/recv.vcl\n+++ b/conf.d/recv.vcl\n"},
+	}
+	for _, tt := range tests {
+		if got := UnifiedFile(tt.filename, "x\n", "y\n"); !strings.HasPrefix(got, tt.header) {
+			t.Errorf("UnifiedFile(%q) headers:\n%s\nwant:\n%s", tt.filename, got, tt.header)
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	if got := Unified("a", "b", "x\n", "x\n"); got != "" {
+		t.Errorf("Expected no diff for equal texts, got %q", got)
+	}
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20"
+	new := "1\n2\n3\nfour\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n20\n21\n"
+	want := `--- a
++++ b
+@@ -1,7 +1,7 @@
+ 1
+ 2
+ 3
+-4
++four
+ 5
+ 6
+ 7
+@@ -17,4 +17,5 @@
+ 17
+ 18
+ 19
+-20
+\ No newline at end of file
++20
++21
+`
+	if got := Unified("a", "b", old, new); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+}
+
+// forget clears the positions of n and the nodes below it, as if they were
+// built by hand
+func forget(n ast.Node) {
+	ast.Apply(n, func(c *ast.Cursor) bool {
+		v := reflect.ValueOf(c.Node()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.Type() == reflect.TypeOf(ast.BaseNode{}) {
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+		return true
+	}, nil)
+}
+
+// changeStrings changes every other string literal in the subroutines of
+// program
+func changeStrings(program *ast.Program, parity int) {
+	count := 0
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.BackendDecl, *ast.ProbeDecl, *ast.ACLDecl:
+			return false
+		case *ast.StringLiteral:
+			if count++; count%2 == parity && !n.Long {
+				n.Value += "-changed"
+			}
+		}
+		return true
+	}, nil)
+}
+
+// TestEditsTestdata changes every other string of the test files and
+// prints every other declaration and statement anew, and checks that the
+// result means the same
+func TestEditsTestdata(t *testing.T) {
+	files, _ := filepath.Glob("../../tests/testdata/*.vcl")
+	included, _ := filepath.Glob("../../tests/testdata/includes/*.vcl")
+	files = append(files, included...)
+	if len(files) == 0 {
+		t.Skip("no test files")
+	}
+	for _, file := range files {
+		for parity := 0; parity < 2; parity++ {
+			testEditsFile(t, file, parity)
+		}
+	}
+}
+
+func testEditsFile(t *testing.T, file string, parity int) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	program, err := parser.Parse(src, file)
+	if err != nil {
+		return
+	}
+	// Without positions, the printer leaves out the blank lines of the
+	// source, which the new nodes do not keep
+	expected, _ := parser.Parse(src, file)
+	changeStrings(expected, parity)
+	forget(expected)
+	var want bytes.Buffer
+	if err := printer.Fprint(&want, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	changeStrings(program, parity)
+	count := 0
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		switch c.Node().(type) {
+		case ast.Declaration, ast.Statement, *ast.BackendProperty, *ast.ACLEntry:
+			if count++; count%2 == parity {
+				forget(c.Node())
+				return false
+			}
+		}
+		return true
+	}, nil)
+
+	out, err := Apply(src, file, program)
+	if err != nil {
+		t.Errorf("%s: %v", file, err)
+		return
+	}
+	var got bytes.Buffer
+	result, err := parser.Parse(out, file)
+	if err == nil {
+		forget(result)
+		err = printer.Fprint(&got, result)
+	}
+	if err != nil || got.String() != want.String() {
+		t.Errorf("%s: result differs: %v\n%s", file, err, out)
+	}
+}
@@ -0,0 +1,105 @@
+package edit
+
+import (
+	"sort"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// source is the original text with its significant tokens, which tell
+// where the nodes parsed from it end
+type source struct {
+	text   string
+	tokens []lexer.Token // without whitespace and comments
+	lines  *lexer.LineIndex
+}
+
+func newSource(text, filename string) *source {
+	s := &source{text: text, lines: lexer.NewLineIndex(text)}
+	for _, tok := range lexer.ScanAll(text, filename) {
+		switch tok.Type {
+		case lexer.WHITESPACE, lexer.COMMENT, lexer.EOF:
+		default:
+			s.tokens = append(s.tokens, tok)
+		}
+	}
+	return s
+}
+
+// extent returns the byte offsets of the text of a node parsed from the
+// source. The end positions the parser records fall inside or just before
+// the last token of a node, and leave out closing brackets and the
+// terminating semicolon, so the end is found from the tokens instead.
+func (s *source) extent(n ast.Node) (start, end int) {
+	start = n.Start().Offset
+	last := max(n.End().Offset, start+1)
+	// The last token is the last one starting before the recorded end
+	i := sort.Search(len(s.tokens), func(i int) bool { return s.tokens[i].Start.Offset >= last }) - 1
+	if i < 0 {
+		return start, start
+	}
+	end = s.tokens[i].End.Offset
+
+	next := func(t lexer.TokenType) bool {
+		if i+1 < len(s.tokens) && s.tokens[i+1].Type == t {
+			i++
+			end = s.tokens[i].End.Offset
+			return true
+		}
+		return false
+	}
+	switch n.(type) {
+	case *ast.BlockStatement, *ast.IfStatement, *ast.SubDecl, *ast.BackendDecl, *ast.ProbeDecl,
+		*ast.ACLDecl, *ast.ObjectExpression:
+		next(lexer.RBRACE)
+	case *ast.CallExpression, *ast.ParenthesizedExpression:
+		next(lexer.RPAREN)
+	case ast.Statement, *ast.VCLVersionDecl, *ast.ImportDecl, *ast.IncludeDecl,
+		*ast.BackendProperty, *ast.ProbeProperty, *ast.Property, *ast.ACLEntry:
+		for next(lexer.RPAREN) {
+		}
+		next(lexer.SEMICOLON)
+	}
+	return start, end
+}
+
+// lineStart returns the offset of the start of the line holding offset
+func (s *source) lineStart(offset int) int {
+	for offset > 0 && s.text[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// indentation returns the whitespace starting the line holding offset
+func (s *source) indentation(offset int) string {
+	start := s.lineStart(offset)
+	end := start
+	for end < len(s.text) && (s.text[end] == ' ' || s.text[end] == '\t') {
+		end++
+	}
+	return s.text[start:end]
+}
+
+// onlySpace reports whether the text between two offsets is blanks
+func (s *source) onlySpace(from, to int) bool {
+	for _, c := range s.text[from:to] {
+		if c != ' ' && c != '\t' && c != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// lineEnd returns the offset just past the newline ending the line holding
+// offset, or the end of the text
+func (s *source) lineEnd(offset int) int {
+	for offset < len(s.text) {
+		offset++
+		if s.text[offset-1] == '\n' {
+			break
+		}
+	}
+	return offset
+}
@@ -0,0 +1,363 @@
+// Package graph describes the structure of a VCL program as a graph for
+// documentation and review: the subroutines and the calls between them,
+// the state transitions their return actions lead to, and the backends and
+// directors requests are sent to. WriteDOT, WriteMermaid and WriteJSON
+// render it.
+package graph
+
+import (
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Node kinds
+const (
+	KindSubroutine = "subroutine"
+	KindBackend    = "backend"
+	KindDirector   = "director"
+)
+
+// Edge kinds
+const (
+	// EdgeCall is a call statement
+	EdgeCall = "call"
+	// EdgeReturn is a return action leading to the next built-in
+	// subroutine; the label is the action
+	EdgeReturn = "return"
+	// EdgeMember links a director to a backend or director added to it
+	EdgeMember = "member"
+	// EdgeBackend links a subroutine to a backend or director it assigns
+	// to req.backend_hint or bereq.backend
+	EdgeBackend = "backend"
+)
+
+// Node is a subroutine, backend or director
+type Node struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Builtin marks the subroutines varnishd calls
+	Builtin bool `json:"builtin,omitempty"`
+	// Implicit marks built-in subroutines the program does not declare,
+	// which only run the built-in VCL
+	Implicit bool `json:"implicit,omitempty"`
+	// Default marks the backend used when none is assigned
+	Default bool `json:"default,omitempty"`
+	// Type is the constructor of a director, e.g. directors.round_robin
+	Type string `json:"type,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// Edge is a directed relation between two nodes
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Kind  string `json:"kind"`
+	Label string `json:"label,omitempty"`
+}
+
+// Graph is the structure of a program. Nodes are in declaration order,
+// followed by implicit built-in subroutines.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// transitions maps the return actions of each built-in subroutine to the
+// subroutines varnishd runs next. Actions ending the transaction, such as
+// deliver in vcl_deliver, have no successor.
+var transitions = map[string]map[string][]string{
+	"vcl_recv": {
+		"hash": {"vcl_hash"}, "pass": {"vcl_pass"}, "pipe": {"vcl_pipe"},
+		"purge": {"vcl_purge"}, "synth": {"vcl_synth"}, "restart": {"vcl_recv"},
+	},
+	"vcl_hash":    {"lookup": {"vcl_hit", "vcl_miss"}},
+	"vcl_hit":     {"deliver": {"vcl_deliver"}, "pass": {"vcl_pass"}, "synth": {"vcl_synth"}, "restart": {"vcl_recv"}},
+	"vcl_miss":    {"fetch": {"vcl_backend_fetch"}, "pass": {"vcl_pass"}, "synth": {"vcl_synth"}, "restart": {"vcl_recv"}},
+	"vcl_pass":    {"fetch": {"vcl_backend_fetch"}, "synth": {"vcl_synth"}, "restart": {"vcl_recv"}},
+	"vcl_pipe":    {"synth": {"vcl_synth"}},
+	"vcl_purge":   {"synth": {"vcl_synth"}, "restart": {"vcl_recv"}},
+	"vcl_deliver": {"synth": {"vcl_synth"}, "restart": {"vcl_recv"}},
+	"vcl_synth":   {"restart": {"vcl_recv"}},
+	"vcl_backend_fetch": {
+		"fetch": {"vcl_backend_response"}, "error": {"vcl_backend_error"},
+	},
+	"vcl_backend_response": {"retry": {"vcl_backend_fetch"}, "error": {"vcl_backend_error"}},
+	"vcl_backend_refresh":  {"retry": {"vcl_backend_fetch"}, "error": {"vcl_backend_error"}},
+	"vcl_backend_error":    {"retry": {"vcl_backend_fetch"}},
+}
+
+// builtinActions are the actions the built-in VCL returns from each
+// subroutine leading to another one
+var builtinActions = map[string][]string{
+	"vcl_recv":             {"synth", "pipe", "pass", "hash"},
+	"vcl_hash":             {"lookup"},
+	"vcl_hit":              {"deliver"},
+	"vcl_miss":             {"fetch"},
+	"vcl_pass":             {"fetch"},
+	"vcl_purge":            {"synth"},
+	"vcl_backend_fetch":    {"fetch"},
+	"vcl_backend_response": {"deliver"},
+}
+
+// Build returns the graph of a program
+func Build(program *ast.Program) *Graph {
+	b := &builder{graph: &Graph{}, ids: make(map[string]bool)}
+	calls := analyzer.NewCallGraph(program)
+
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.BackendDecl:
+			b.addNode(Node{ID: backendID(d.Name), Kind: KindBackend, Name: d.Name, Line: d.Start().Line})
+		case *ast.SubDecl:
+			b.addNode(Node{
+				ID: subID(d.Name), Kind: KindSubroutine, Name: d.Name,
+				Builtin: isBuiltin(d.Name), Line: d.Start().Line,
+			})
+		}
+	}
+	for i := range b.graph.Nodes {
+		if b.graph.Nodes[i].Kind == KindBackend {
+			b.graph.Nodes[i].Default = true
+			break
+		}
+	}
+	b.directors(program)
+
+	for _, name := range calls.Subroutines() {
+		for _, callee := range calls.Callees(name) {
+			b.addEdge(Edge{From: subID(name), To: subID(callee), Kind: EdgeCall})
+		}
+		for _, sub := range calls.Declarations(name) {
+			b.backendAssignments(name, sub)
+		}
+	}
+
+	// Return actions taken in a custom subroutine count for every built-in
+	// subroutine calling it. Built-in subroutines that may end without
+	// returning continue into the built-in VCL, as do the implicit ones.
+	var queue []string
+	for _, name := range calls.Subroutines() {
+		if isBuiltin(name) {
+			queue = append(queue, name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		actions := returnActions(calls, name)
+		if subs := calls.Declarations(name); len(subs) == 0 || !endsWithReturn(subs[len(subs)-1]) {
+			actions = append(actions, builtinActions[name]...)
+		}
+		for _, action := range actions {
+			for _, next := range nextStates(name, action) {
+				if !b.ids[subID(next)] {
+					b.addNode(Node{ID: subID(next), Kind: KindSubroutine, Name: next, Builtin: true, Implicit: true})
+					queue = append(queue, next)
+				}
+				b.addEdge(Edge{From: subID(name), To: subID(next), Kind: EdgeReturn, Label: action})
+			}
+		}
+	}
+	return b.graph
+}
+
+// nextStates returns the subroutines an action in a built-in subroutine
+// leads to. fail ends in vcl_synth on the client side and in
+// vcl_backend_error on the backend side.
+func nextStates(sub, action string) []string {
+	if action == "fail" {
+		if strings.HasPrefix(sub, "vcl_backend_") {
+			return []string{"vcl_backend_error"}
+		}
+		if sub != "vcl_synth" && strings.HasPrefix(sub, "vcl_") && transitions[sub] != nil {
+			return []string{"vcl_synth"}
+		}
+		return nil
+	}
+	return transitions[sub][action]
+}
+
+// returnActions lists the distinct actions returned by a subroutine and the
+// custom subroutines it calls, in the order they first appear
+func returnActions(calls *analyzer.CallGraph, name string) []string {
+	var actions []string
+	seen := make(map[string]bool)
+	for _, reached := range append([]string{name}, calls.ReachableFrom(name)...) {
+		if reached != name && isBuiltin(reached) {
+			continue
+		}
+		for _, sub := range calls.Declarations(reached) {
+			inspect(sub.Body, func(n ast.Node) bool {
+				ret, ok := n.(*ast.ReturnStatement)
+				if !ok {
+					return true
+				}
+				if action := actionName(ret.Action); action != "" && !seen[action] {
+					seen[action] = true
+					actions = append(actions, action)
+				}
+				return false
+			})
+		}
+	}
+	return actions
+}
+
+// endsWithReturn reports whether the last statement of a subroutine is a
+// return, so it never continues into the built-in VCL
+func endsWithReturn(sub *ast.SubDecl) bool {
+	if sub.Body == nil || len(sub.Body.Statements) == 0 {
+		return false
+	}
+	_, ok := sub.Body.Statements[len(sub.Body.Statements)-1].(*ast.ReturnStatement)
+	return ok
+}
+
+type builder struct {
+	graph *Graph
+	ids   map[string]bool
+	edges map[Edge]bool
+}
+
+func (b *builder) addNode(n Node) {
+	if b.ids[n.ID] {
+		return
+	}
+	b.ids[n.ID] = true
+	b.graph.Nodes = append(b.graph.Nodes, n)
+}
+
+func (b *builder) addEdge(e Edge) {
+	if b.edges == nil {
+		b.edges = make(map[Edge]bool)
+	}
+	if b.edges[e] || !b.ids[e.From] || !b.ids[e.To] {
+		return
+	}
+	b.edges[e] = true
+	b.graph.Edges = append(b.graph.Edges, e)
+}
+
+// directors adds the VMOD objects used as directors: objects created in
+// vcl_init that backends are added to or that hand out backends. Backends
+// and directors added with add_backend() become members.
+func (b *builder) directors(program *ast.Program) {
+	objects := make(map[string]string)
+	var order []string
+	var members []Edge
+	isDirector := make(map[string]bool)
+
+	inspect(program, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.NewStatement:
+			name := ast.VariableName(s.Name)
+			if call, ok := s.Constructor.(*ast.CallExpression); ok && name != "" {
+				objects[name] = ast.VariableName(call.Function)
+				order = append(order, name)
+				if strings.HasPrefix(objects[name], "directors.") {
+					isDirector[name] = true
+				}
+			}
+		case *ast.CallExpression:
+			object, method := methodCall(s)
+			switch method {
+			case "add_backend":
+				isDirector[object] = true
+				if len(s.Arguments) > 0 {
+					if target := b.backendTarget(s.Arguments[0]); target != "" {
+						members = append(members, Edge{From: directorID(object), To: target, Kind: EdgeMember})
+					}
+				}
+			case "backend":
+				isDirector[object] = true
+			}
+		}
+		return true
+	})
+
+	for _, name := range order {
+		if isDirector[name] {
+			b.addNode(Node{ID: directorID(name), Kind: KindDirector, Name: name, Type: objects[name]})
+		}
+	}
+	for _, edge := range members {
+		b.addEdge(edge)
+	}
+}
+
+// backendAssignments adds an edge for each backend or director the
+// subroutine assigns to the request
+func (b *builder) backendAssignments(name string, sub *ast.SubDecl) {
+	inspect(sub.Body, func(n ast.Node) bool {
+		set, ok := n.(*ast.SetStatement)
+		if !ok {
+			return true
+		}
+		switch strings.ToLower(ast.VariableName(set.Variable)) {
+		case "req.backend_hint", "bereq.backend":
+			if target := b.backendTarget(set.Value); target != "" {
+				b.addEdge(Edge{From: subID(name), To: target, Kind: EdgeBackend})
+			}
+		}
+		return false
+	})
+}
+
+// backendTarget returns the node a backend expression refers to: a
+// backend by name or a director's backend() method
+func (b *builder) backendTarget(expr ast.Expression) string {
+	if ident, ok := expr.(*ast.Identifier); ok && b.ids[backendID(ident.Name)] {
+		return backendID(ident.Name)
+	}
+	if call, ok := expr.(*ast.CallExpression); ok {
+		if object, method := methodCall(call); method == "backend" {
+			return directorID(object)
+		}
+	}
+	return ""
+}
+
+// methodCall splits a call like vdir.add_backend(b1) into object and method
+func methodCall(call *ast.CallExpression) (object, method string) {
+	name := ast.VariableName(call.Function)
+	if i := strings.LastIndex(name, "."); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", ""
+}
+
+func subID(name string) string      { return "sub_" + name }
+func backendID(name string) string  { return "backend_" + name }
+func directorID(name string) string { return "director_" + name }
+
+func isBuiltin(name string) bool {
+	return strings.HasPrefix(name, "vcl_")
+}
+
+// inspect calls fn for node and everything below it, skipping the children
+// of nodes for which fn returns false
+func inspect(node ast.Node, fn func(ast.Node) bool) {
+	ast.Apply(node, func(c *ast.Cursor) bool {
+		if c.Node() == nil {
+			return true
+		}
+		return fn(c.Node())
+	}, nil)
+}
+
+// actionName extracts the action from return (action) or
+// return (action(args))
+func actionName(expr ast.Expression) string {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return e.Name
+	case *ast.CallExpression:
+		return ast.VariableName(e.Function)
+	case *ast.ParenthesizedExpression:
+		return actionName(e.Expression)
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+const testVCL = `vcl 4.1;
+
+import directors;
+
+backend b1 { .host = "192.0.2.1"; }
+backend b2 { .host = "192.0.2.2"; }
+
+sub vcl_init {
+	new vdir = directors.round_robin();
+	vdir.add_backend(b1);
+	vdir.add_backend(b2);
+}
+
+sub normalize {
+	if (req.url ~ "^/admin") {
+		return (pass);
+	}
+}
+
+sub vcl_recv {
+	set req.backend_hint = vdir.backend();
+	call normalize;
+	return (hash);
+}
+
+sub vcl_backend_fetch {
+	set bereq.backend = b2;
+}
+`
+
+func buildTestGraph(t *testing.T) *Graph {
+	t.Helper()
+	program, err := parser.Parse(testVCL, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	return Build(program)
+}
+
+func TestBuild(t *testing.T) {
+	g := buildTestGraph(t)
+
+	nodes := make(map[string]Node)
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	edges := make(map[Edge]bool)
+	for _, e := range g.Edges {
+		edges[e] = true
+	}
+
+	tests := []struct {
+		name string
+		node string
+		kind string
+	}{
+		{"backend", "backend_b1", KindBackend},
+		{"director", "director_vdir", KindDirector},
+		{"custom sub", "sub_normalize", KindSubroutine},
+		{"implicit sub", "sub_vcl_hash", KindSubroutine},
+		{"reached through built-in VCL", "sub_vcl_miss", KindSubroutine},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, ok := nodes[tt.node]
+			if !ok {
+				t.Fatalf("node %s missing", tt.node)
+			}
+			if n.Kind != tt.kind {
+				t.Errorf("node %s has kind %s, want %s", tt.node, n.Kind, tt.kind)
+			}
+		})
+	}
+	if !nodes["backend_b1"].Default || nodes["backend_b2"].Default {
+		t.Errorf("only the first backend should be the default")
+	}
+	if !nodes["sub_vcl_hash"].Implicit || nodes["sub_vcl_recv"].Implicit {
+		t.Errorf("only undeclared built-in subroutines should be implicit")
+	}
+	if nodes["director_vdir"].Type != "directors.round_robin" {
+		t.Errorf("director type = %q", nodes["director_vdir"].Type)
+	}
+
+	want := []Edge{
+		{From: "sub_vcl_recv", To: "sub_normalize", Kind: EdgeCall},
+		{From: "sub_vcl_recv", To: "sub_vcl_hash", Kind: EdgeReturn, Label: "hash"},
+		{From: "sub_vcl_recv", To: "sub_vcl_pass", Kind: EdgeReturn, Label: "pass"},
+		{From: "sub_vcl_hash", To: "sub_vcl_miss", Kind: EdgeReturn, Label: "lookup"},
+		{From: "sub_vcl_backend_fetch", To: "sub_vcl_backend_response", Kind: EdgeReturn, Label: "fetch"},
+		{From: "director_vdir", To: "backend_b1", Kind: EdgeMember},
+		{From: "director_vdir", To: "backend_b2", Kind: EdgeMember},
+		{From: "sub_vcl_recv", To: "director_vdir", Kind: EdgeBackend},
+		{From: "sub_vcl_backend_fetch", To: "backend_b2", Kind: EdgeBackend},
+	}
+	for _, e := range want {
+		if !edges[e] {
+			t.Errorf("edge %+v missing", e)
+		}
+	}
+	// vcl_recv ends with a return, so the built-in actions are not added
+	if edges[Edge{From: "sub_vcl_recv", To: "sub_vcl_pipe", Kind: EdgeReturn, Label: "pipe"}] {
+		t.Errorf("unexpected pipe transition from vcl_recv")
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := buildTestGraph(t)
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"digraph vcl {", `sub_vcl_recv -> sub_vcl_hash [label="hash", style=bold];`, "director_vdir -> backend_b1 [style=dotted];"} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output lacks %q:\n%s", want, dot.String())
+		}
+	}
+
+	var mermaid bytes.Buffer
+	if err := g.WriteMermaid(&mermaid); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"flowchart LR", `sub_vcl_recv ==>|"hash"| sub_vcl_hash`, `backend_b1[("b1<br>(default)")]`} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Errorf("Mermaid output lacks %q:\n%s", want, mermaid.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Nodes) != len(g.Nodes) || len(decoded.Edges) != len(g.Edges) {
+		t.Errorf("JSON round trip changed the graph")
+	}
+}
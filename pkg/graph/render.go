@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the graph in the Graphviz DOT language. Subroutines are
+// boxes, with implicit built-in subroutines dashed; backends are cylinders
+// and directors hexagons. Return transitions are bold, calls plain and
+// backend relations dotted.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph vcl {\n\trankdir=LR;\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(n.label())}
+		switch n.Kind {
+		case KindSubroutine:
+			attrs = append(attrs, "shape=box")
+			if n.Implicit {
+				attrs = append(attrs, "style=dashed")
+			} else if n.Builtin {
+				attrs = append(attrs, "style=bold")
+			}
+		case KindBackend:
+			attrs = append(attrs, "shape=cylinder")
+		case KindDirector:
+			attrs = append(attrs, "shape=hexagon")
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.Label))
+		}
+		switch e.Kind {
+		case EdgeReturn:
+			attrs = append(attrs, "style=bold")
+		case EdgeMember, EdgeBackend:
+			attrs = append(attrs, "style=dotted")
+		}
+		fmt.Fprintf(&b, "\t%s -> %s", e.From, e.To)
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, which renders in
+// Markdown on GitHub and GitLab
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := mermaidQuote(n.label())
+		switch n.Kind {
+		case KindBackend:
+			fmt.Fprintf(&b, "    %s[(%s)]\n", n.ID, label)
+		case KindDirector:
+			fmt.Fprintf(&b, "    %s{{%s}}\n", n.ID, label)
+		default:
+			fmt.Fprintf(&b, "    %s[%s]\n", n.ID, label)
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		switch e.Kind {
+		case EdgeReturn:
+			arrow = "==>"
+		case EdgeMember, EdgeBackend:
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			fmt.Fprintf(&b, "    %s %s|%s| %s\n", e.From, arrow, mermaidQuote(e.Label), e.To)
+		} else {
+			fmt.Fprintf(&b, "    %s %s %s\n", e.From, arrow, e.To)
+		}
+	}
+	for _, n := range g.Nodes {
+		if n.Implicit {
+			fmt.Fprintf(&b, "    style %s stroke-dasharray: 5 5\n", n.ID)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the graph as indented JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// label is the text shown for a node
+func (n Node) label() string {
+	switch {
+	case n.Kind == KindDirector && n.Type != "":
+		return n.Name + "\n" + n.Type
+	case n.Default:
+		return n.Name + "\n(default)"
+	}
+	return n.Name
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	return `"` + strings.ReplaceAll(s, "\n", "<br>") + `"`
+}
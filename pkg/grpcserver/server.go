@@ -0,0 +1,194 @@
+// Package grpcserver implements the VCLParser gRPC service of
+// proto/vclparser/v1 on top of the parser, the analyzer and the include
+// resolver, for services in other languages running the parser as a
+// sidecar.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast/astproto"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+	pb "github.com/perbu/vclparser/proto/vclparser/v1"
+)
+
+// Server implements pb.VCLParserServer. It is safe for concurrent use.
+type Server struct {
+	pb.UnimplementedVCLParserServer
+	registry *vmod.Registry
+	root     string
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithRegistry sets the VMOD registry Analyze checks imports and calls
+// against (default: vmod.NewRegistry, the embedded VCC files)
+func WithRegistry(registry *vmod.Registry) Option {
+	return func(s *Server) {
+		s.registry = registry
+	}
+}
+
+// WithRoot lets ResolveIncludes read the files of requests that carry none
+// from the file system, below dir. The base_path of a request is taken
+// relative to dir and includes may not leave it. Without a root such
+// requests are refused.
+func WithRoot(dir string) Option {
+	return func(s *Server) {
+		s.root = dir
+	}
+}
+
+// New returns a server with the given options
+func New(options ...Option) *Server {
+	s := &Server{}
+	for _, option := range options {
+		option(s)
+	}
+	if s.registry == nil {
+		s.registry = vmod.NewRegistry()
+	}
+	return s
+}
+
+// Register registers the server with a gRPC server
+func (s *Server) Register(server grpc.ServiceRegistrar) {
+	pb.RegisterVCLParserServer(server, s)
+}
+
+// Parse parses one file and returns its syntax tree and parse errors
+func (s *Server) Parse(ctx context.Context, req *pb.ParseRequest) (*pb.ParseResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	config := parser.DefaultConfig()
+	config.MaxErrors = int(req.MaxErrors)
+	result := parser.ParseDetailed(req.Source, req.Filename, config)
+	return &pb.ParseResponse{
+		Program:     astproto.FromProgram(result.Program),
+		Diagnostics: fromDiagnostics(analyzer.ParseDiagnostics(result)),
+	}, nil
+}
+
+// Analyze parses one file and returns the diagnostics of parsing and, if it
+// parsed, of semantic analysis
+func (s *Server) Analyze(ctx context.Context, req *pb.AnalyzeRequest) (*pb.AnalyzeResponse, error) {
+	a := analyzer.NewAnalyzer(s.registry)
+	if req.VarnishVersion != "" {
+		if err := a.SetVarnishVersion(req.VarnishVersion); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if req.Unreferenced != "" {
+		if err := a.SetUnreferenced(req.Unreferenced); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	a.SetLabels(req.Labels...)
+
+	result := parser.ParseDetailed(req.Source, req.Filename, parser.DefaultConfig())
+	diags := analyzer.ParseDiagnostics(result)
+	if len(result.Errors) == 0 {
+		found, err := a.AnalyzeDiagnosticsContext(ctx, result.Program)
+		if err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		for i := range found {
+			if found[i].Filename == "" {
+				found[i].Filename = req.Filename
+			}
+		}
+		diags = append(diags, found...)
+	}
+	return &pb.AnalyzeResponse{Diagnostics: fromDiagnostics(diags)}, nil
+}
+
+// ResolveIncludes parses the main file and merges the files it includes
+// into a single program
+func (s *Server) ResolveIncludes(ctx context.Context, req *pb.ResolveIncludesRequest) (*pb.ResolveIncludesResponse, error) {
+	options := []include.Option{include.WithSearchPath(req.SearchPath...)}
+	if req.MaxDepth > 0 {
+		options = append(options, include.WithMaxDepth(int(req.MaxDepth)))
+	}
+	if len(req.Files) > 0 {
+		options = append(options,
+			include.WithBasePath(req.BasePath),
+			include.WithFileReader(include.NewMemoryFileReader(req.Files)))
+	} else {
+		if s.root == "" {
+			return nil, status.Error(codes.FailedPrecondition, "request carries no files and the server reads none from disk")
+		}
+		if req.BasePath != "" && !filepath.IsLocal(req.BasePath) {
+			return nil, status.Errorf(codes.InvalidArgument, "base path %q leaves the server root", req.BasePath)
+		}
+		for _, dir := range req.SearchPath {
+			if !filepath.IsLocal(dir) {
+				return nil, status.Errorf(codes.InvalidArgument, "search path %q leaves the server root", dir)
+			}
+		}
+		options = append(options,
+			include.WithBasePath(filepath.Join(s.root, req.BasePath)),
+			include.WithUnsafePath(false))
+	}
+
+	resolver := include.NewResolver(options...)
+	program, err := resolver.ResolveFileContext(ctx, req.MainFile)
+	if err != nil {
+		return nil, resolveError(err)
+	}
+	resp := &pb.ResolveIncludesResponse{Program: astproto.FromProgram(program)}
+	sources := resolver.SourceMap()
+	for _, decl := range program.Declarations {
+		source, _ := sources.Lookup(decl)
+		resp.Sources = append(resp.Sources, &pb.Source{File: source.File, Chain: source.Chain})
+	}
+	return resp, nil
+}
+
+// resolveError maps an include resolution error to a gRPC status
+func resolveError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	var notFound *include.FileNotFoundError
+	if errors.As(err, &notFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func fromDiagnostics(diags []analyzer.Diagnostic) []*pb.Diagnostic {
+	msgs := make([]*pb.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		msgs = append(msgs, &pb.Diagnostic{
+			Filename:     d.Filename,
+			Position:     astproto.FromPosition(d.Position),
+			EndPosition:  astproto.FromPosition(d.EndPosition),
+			Severity:     fromSeverity(d.Severity),
+			Code:         d.Code,
+			Message:      d.Message,
+			IncludeChain: d.IncludeChain,
+		})
+	}
+	return msgs
+}
+
+func fromSeverity(severity analyzer.Severity) pb.Severity {
+	switch severity {
+	case analyzer.SeverityWarning:
+		return pb.Severity_SEVERITY_WARNING
+	case analyzer.SeverityInfo:
+		return pb.Severity_SEVERITY_INFO
+	}
+	return pb.Severity_SEVERITY_ERROR
+}
@@ -0,0 +1,176 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/ast/astproto"
+	"github.com/perbu/vclparser/pkg/parser"
+	pb "github.com/perbu/vclparser/proto/vclparser/v1"
+)
+
+// newClient serves s on a local TCP port and returns a client connected to
+// it
+func newClient(t *testing.T, s *Server) pb.VCLParserClient {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	s.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewVCLParserClient(conn)
+}
+
+const source = `vcl 4.1;
+
+backend origin {
+    .host = "origin.example.com";
+}
+
+sub vcl_recv {
+    set req.backend_hint = origin;
+    if (req.url ~ "^/admin") {
+        return (synth(403));
+    }
+}
+`
+
+func TestParse(t *testing.T) {
+	client := newClient(t, New())
+	ctx := context.Background()
+
+	resp, err := client.Parse(ctx, &pb.ParseRequest{Filename: "main.vcl", Source: source})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(resp.Diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", resp.Diagnostics)
+	}
+	want, err := parser.Parse(source, "main.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := astproto.ToProgram(resp.Program); !reflect.DeepEqual(want, got) {
+		t.Error("The returned tree differs from the one of the parser")
+	}
+
+	resp, err = client.Parse(ctx, &pb.ParseRequest{Filename: "bad.vcl", Source: "vcl 4.1;\nsub vcl_recv {\n    set = 1;\n}\n"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(resp.Diagnostics) == 0 {
+		t.Fatal("Expected a syntax error")
+	}
+	d := resp.Diagnostics[0]
+	if d.Severity != pb.Severity_SEVERITY_ERROR || d.Filename != "bad.vcl" || d.Position.GetLine() != 3 {
+		t.Errorf("Unexpected diagnostic %v", d)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	client := newClient(t, New())
+	ctx := context.Background()
+
+	src := "vcl 4.1;\n\nbackend origin {\n    .host = \"origin.example.com\";\n}\n\nbackend unused {\n    .host = \"unused.example.com\";\n}\n\nsub vcl_recv {\n    set req.backend_hint = origin;\n    return (vcl(missing));\n}\n"
+	resp, err := client.Analyze(ctx, &pb.AnalyzeRequest{
+		Filename:     "main.vcl",
+		Source:       src,
+		Unreferenced: "warning",
+		Labels:       []string{"missing"},
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic, got %v", resp.Diagnostics)
+	}
+	d := resp.Diagnostics[0]
+	if d.Severity != pb.Severity_SEVERITY_WARNING || d.Filename != "main.vcl" || d.Position.GetLine() != 7 {
+		t.Errorf("Unexpected diagnostic %v", d)
+	}
+
+	_, err = client.Analyze(ctx, &pb.AnalyzeRequest{Filename: "main.vcl", Source: src, VarnishVersion: "nope"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown version, got %v", err)
+	}
+}
+
+func TestResolveIncludes(t *testing.T) {
+	client := newClient(t, New(WithRoot("../../tests/testdata")))
+	ctx := context.Background()
+
+	files := map[string]string{
+		"main.vcl":          "vcl 4.1;\ninclude \"conf/backends.vcl\";\nsub vcl_recv {\n    set req.backend_hint = origin;\n}\n",
+		"conf/backends.vcl": "vcl 4.1;\ninclude \"conf/probe.vcl\";\nbackend origin {\n    .host = \"origin.example.com\";\n    .probe = health;\n}\n",
+		"conf/probe.vcl":    "vcl 4.1;\nprobe health {\n    .url = \"/health\";\n}\n",
+		"conf/not-included": "junk",
+	}
+	resp, err := client.ResolveIncludes(ctx, &pb.ResolveIncludesRequest{MainFile: "main.vcl", Files: files})
+	if err != nil {
+		t.Fatalf("ResolveIncludes failed: %v", err)
+	}
+	program := astproto.ToProgram(resp.Program)
+	if len(program.Declarations) != 3 || len(resp.Sources) != 3 {
+		t.Fatalf("Expected three declarations with sources, got %d and %d", len(program.Declarations), len(resp.Sources))
+	}
+	if _, ok := program.Declarations[0].(*ast.ProbeDecl); !ok {
+		t.Errorf("Expected the probe first, got %T", program.Declarations[0])
+	}
+	probe := resp.Sources[0]
+	if probe.File != "conf/probe.vcl" || !reflect.DeepEqual(probe.Chain, []string{"main.vcl", "conf/backends.vcl"}) {
+		t.Errorf("Unexpected source of the probe: %v", probe)
+	}
+	if resp.Sources[2].File != "main.vcl" || len(resp.Sources[2].Chain) != 0 {
+		t.Errorf("Unexpected source of vcl_recv: %v", resp.Sources[2])
+	}
+
+	// Files on disk, below the root
+	resp, err = client.ResolveIncludes(ctx, &pb.ResolveIncludesRequest{MainFile: "main.vcl", BasePath: "includes"})
+	if err != nil {
+		t.Fatalf("ResolveIncludes from disk failed: %v", err)
+	}
+	if len(resp.Program.Declarations) == 0 || len(resp.Sources) != len(resp.Program.Declarations) {
+		t.Errorf("Expected declarations with sources, got %d and %d", len(resp.Program.Declarations), len(resp.Sources))
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.ResolveIncludesRequest
+		code codes.Code
+	}{
+		{"missing include", &pb.ResolveIncludesRequest{MainFile: "main.vcl", Files: map[string]string{"main.vcl": "vcl 4.1;\ninclude \"gone.vcl\";\n"}}, codes.NotFound},
+		{"base path outside the root", &pb.ResolveIncludesRequest{MainFile: "main.vcl", BasePath: "../.."}, codes.InvalidArgument},
+		{"search path outside the root", &pb.ResolveIncludesRequest{MainFile: "main.vcl", BasePath: "includes", SearchPath: []string{"/etc"}}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ResolveIncludes(ctx, tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("Expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+
+	// Without a root, requests must carry their files
+	_, err = newClient(t, New()).ResolveIncludes(ctx, &pb.ResolveIncludesRequest{MainFile: "main.vcl"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition without a root, got %v", err)
+	}
+}
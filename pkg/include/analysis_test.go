@@ -0,0 +1,101 @@
+package include_test
+
+// The analyzer imports include, so tests analyzing resolved programs live in
+// the external test package
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+func TestResolver_CrossFileCalls(t *testing.T) {
+	reader := include.NewMemoryFileReader(map[string]string{
+		"main.vcl": `vcl 4.1;
+include "tenants/a.vcl";
+
+sub vcl_recv {
+	call tenant_a;
+}
+`,
+		"missing.vcl": `vcl 4.1;
+include "tenants/a.vcl";
+
+sub vcl_recv {
+	call tenant_a;
+	call tenant_b;
+}
+`,
+		"tenants/a.vcl": "vcl 4.1;\nsub tenant_a {\n\tset req.http.X-Tenant = \"a\";\n}\n",
+	})
+
+	program, err := include.NewResolver(include.WithFileReader(reader)).ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	if errs := analyzer.NewAnalyzer(vmod.NewEmptyRegistry()).Analyze(program); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	// Subroutines defined nowhere are reported once the includes are merged
+	program, err = include.NewResolver(include.WithFileReader(reader)).ResolveFile("missing.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	errs := analyzer.NewAnalyzer(vmod.NewEmptyRegistry()).Analyze(program)
+	if len(errs) != 1 || !strings.Contains(errs[0], "undefined subroutine: tenant_b") {
+		t.Errorf("Expected an undefined subroutine error for tenant_b, got %v", errs)
+	}
+}
+
+func TestResolver_GlobCalls(t *testing.T) {
+	reader := include.NewMemoryFileReader(map[string]string{
+		"paths.vcl": `vcl 4.1;
+include "conf.d/*.vcl";
+
+sub vcl_recv {
+	call a;
+	call b;
+}
+`,
+		"variables.vcl": `vcl 4.1;
+include "${env}/recv.vcl";
+include +glob "features/*.vcl";
+
+sub vcl_recv {
+	call geoip;
+	call prod;
+}
+`,
+		"conf.d/a.vcl":       "vcl 4.1;\nsub a {\n}\n",
+		"conf.d/b.vcl":       "vcl 4.1;\nsub b {\n}\n",
+		"prod/recv.vcl":      "vcl 4.1;\nsub prod {\n}\n",
+		"features/geoip.vcl": "vcl 4.1;\nsub geoip {\n}\n",
+		"features/waf.vcl":   "vcl 4.1;\nsub waf {\n\tcall missing;\n}\n",
+	})
+	tests := []struct {
+		file    string
+		options []include.Option
+	}{
+		{"paths.vcl", []include.Option{include.WithGlobPaths(true)}},
+		{"variables.vcl", []include.Option{
+			include.WithVariables(map[string]string{"env": "prod"}),
+			include.WithIncludeFilter(func(path string) bool { return path != "features/waf.vcl" }),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			options := append([]include.Option{include.WithFileReader(reader)}, tt.options...)
+			program, err := include.NewResolver(options...).ResolveFile(tt.file)
+			if err != nil {
+				t.Fatalf("Failed to resolve includes: %v", err)
+			}
+			if errs := analyzer.NewAnalyzer(vmod.NewEmptyRegistry()).Analyze(program); len(errs) != 0 {
+				t.Errorf("Expected no errors, got %v", errs)
+			}
+		})
+	}
+}
@@ -29,12 +29,17 @@ func (e *MaxDepthError) Error() string {
 
 // FileNotFoundError represents a missing include file error
 type FileNotFoundError struct {
-	Path     string
-	BasePath string
-	Cause    error
+	Path       string
+	BasePath   string
+	SearchPath []string
+	Cause      error
 }
 
 func (e *FileNotFoundError) Error() string {
+	if len(e.SearchPath) > 0 {
+		return fmt.Sprintf("failed to read include file %s (search path: %s): %v", e.Path,
+			strings.Join(e.SearchPath, ":"), e.Cause)
+	}
 	if e.BasePath != "" {
 		return fmt.Sprintf("failed to read include file %s (base: %s): %v", e.Path, e.BasePath, e.Cause)
 	}
@@ -45,6 +50,16 @@ func (e *FileNotFoundError) Unwrap() error {
 	return e.Cause
 }
 
+// UnsafePathError represents an include path that is absolute or contains
+// "..", which is rejected when unsafe paths are disallowed
+type UnsafePathError struct {
+	Path string
+}
+
+func (e *UnsafePathError) Error() string {
+	return fmt.Sprintf("include path %s is unsafe: absolute paths and .. are not allowed", e.Path)
+}
+
 // ParseError represents an error parsing an included file
 type ParseError struct {
 	Path  string
@@ -58,3 +73,24 @@ func (e *ParseError) Error() string {
 func (e *ParseError) Unwrap() error {
 	return e.Cause
 }
+
+// UndefinedVariableError represents an include path with a ${name}
+// placeholder that has no value
+type UndefinedVariableError struct {
+	Path string
+	Name string
+}
+
+func (e *UndefinedVariableError) Error() string {
+	return fmt.Sprintf("include path %s uses undefined variable %s", e.Path, e.Name)
+}
+
+// RemoteIncludeError represents an include of a URL by a resolver that was
+// not created with WithRemoteIncludes
+type RemoteIncludeError struct {
+	Path string
+}
+
+func (e *RemoteIncludeError) Error() string {
+	return fmt.Sprintf("include of URL %s is not allowed: remote includes are disabled", e.Path)
+}
@@ -1,8 +1,13 @@
 package include
 
 import (
+	"context"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 // FileReader provides an interface for reading files, allowing for easier testing
@@ -11,6 +16,20 @@ type FileReader interface {
 	ReadFile(path string) ([]byte, error)
 }
 
+// ContextFileReader is implemented by file readers whose reads can be
+// cancelled, such as RemoteFileReader. Resolving with a context reads files
+// with ReadFileContext.
+type ContextFileReader interface {
+	ReadFileContext(ctx context.Context, path string) ([]byte, error)
+}
+
+// Globber is implemented by file readers that can list the files matching a
+// pattern, which include +glob needs. Patterns use the syntax of
+// filepath.Match and matches are returned sorted.
+type Globber interface {
+	Glob(pattern string) ([]string, error)
+}
+
 // OSFileReader implements FileReader using the standard os package
 type OSFileReader struct {
 	basePath string
@@ -34,6 +53,56 @@ func (r *OSFileReader) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(fullPath)
 }
 
+// Glob returns the files matching a pattern, resolving relative patterns
+// against the base path. The matches are relative to the base path again.
+func (r *OSFileReader) Glob(pattern string) ([]string, error) {
+	if filepath.IsAbs(pattern) {
+		return filepath.Glob(pattern)
+	}
+	matches, err := filepath.Glob(filepath.Join(r.basePath, pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i, match := range matches {
+		if rel, err := filepath.Rel(r.basePath, match); err == nil {
+			matches[i] = rel
+		}
+	}
+	return matches, nil
+}
+
+// FSFileReader implements FileReader and Globber on an fs.FS, such as an
+// embed.FS, a zip.Reader or os.DirFS. Paths are used in fs.FS form,
+// slash-separated and with the leading slash of absolute paths removed, so
+// absolute includes work on os.DirFS("/").
+type FSFileReader struct {
+	fsys fs.FS
+}
+
+// NewFSFileReader creates a file reader for fsys
+func NewFSFileReader(fsys fs.FS) *FSFileReader {
+	return &FSFileReader{fsys: fsys}
+}
+
+// ReadFile reads a file from the file system
+func (r *FSFileReader) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(r.fsys, fsPath(name))
+}
+
+// Glob returns the files of the file system matching a pattern
+func (r *FSFileReader) Glob(pattern string) ([]string, error) {
+	return fs.Glob(r.fsys, fsPath(pattern))
+}
+
+// fsPath converts an operating system path to the form fs.FS expects
+func fsPath(name string) string {
+	name = path.Clean(filepath.ToSlash(name))
+	if name == "/" {
+		return "."
+	}
+	return strings.TrimPrefix(name, "/")
+}
+
 // MemoryFileReader implements FileReader using an in-memory map for testing
 type MemoryFileReader struct {
 	files map[string]string
@@ -57,3 +126,19 @@ func (r *MemoryFileReader) ReadFile(path string) ([]byte, error) {
 func (r *MemoryFileReader) AddFile(path, content string) {
 	r.files[path] = content
 }
+
+// Glob returns the files in memory matching a pattern
+func (r *MemoryFileReader) Glob(pattern string) ([]string, error) {
+	var matches []string
+	for path := range r.files {
+		ok, err := filepath.Match(pattern, path)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, path)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
@@ -0,0 +1,23 @@
+package include
+
+import (
+	"testing"
+)
+
+func FuzzResolve(f *testing.F) {
+	f.Add("vcl 4.1;\ninclude \"a.vcl\";\nsub vcl_recv { include \"b.vcl\"; }", "sub vcl_deliver { }", "set req.http.x = \"1\";")
+	f.Add("vcl 4.1;\ninclude \"a.vcl\";", "include \"b.vcl\";", "include \"a.vcl\";")
+	f.Add("vcl 4.1;\ninclude \"*.vcl\";", "include \"../../etc/passwd\";", "include \"${dir}/x.vcl\";")
+
+	f.Fuzz(func(t *testing.T, main, a, b string) {
+		reader := NewMemoryFileReader(map[string]string{
+			"main.vcl": main,
+			"a.vcl":    a,
+			"b.vcl":    b,
+		})
+		resolver := NewResolver(WithFileReader(reader), WithGlobPaths(true), WithMaxDepth(8))
+		if _, err := resolver.ResolveFile("main.vcl"); err != nil {
+			_ = err.Error()
+		}
+	})
+}
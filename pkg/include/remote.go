@@ -0,0 +1,371 @@
+package include
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRemoteSize limits the size of a remote include
+const maxRemoteSize = 16 << 20
+
+// isURL reports whether an include path is a URL read by RemoteFileReader
+func isURL(path string) bool {
+	for _, scheme := range []string{"http://", "https://", "s3://"} {
+		if strings.HasPrefix(path, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteFileReader reads includes given as http://, https:// or s3:// URLs
+// and passes all other paths to a local FileReader. Resolvers only follow
+// URLs when enabled with WithRemoteIncludes.
+//
+// Fetched files are kept for the lifetime of the reader, and in a cache
+// directory if one is set. Files pinned with a SHA-256 checksum are served
+// from the cache directory while it holds the pinned content; others are
+// fetched again by each new reader, falling back to the cache directory
+// when the fetch fails.
+type RemoteFileReader struct {
+	local            FileReader
+	client           *http.Client
+	cacheDir         string
+	checksums        map[string]string
+	requireChecksums bool
+	s3               S3Config
+	now              func() time.Time
+
+	mutex  sync.Mutex
+	cached map[string][]byte
+}
+
+// S3Config describes an S3-compatible endpoint for s3://bucket/key URLs.
+// Requests are signed with AWS Signature Version 4 when an access key is
+// set, and sent unsigned for public buckets otherwise.
+type S3Config struct {
+	// Endpoint is the base URL of the service, such as
+	// https://minio.example.com; by default the AWS endpoint of Region
+	Endpoint string
+	// Region defaults to us-east-1
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// RemoteOption configures a RemoteFileReader
+type RemoteOption func(*RemoteFileReader)
+
+// WithHTTPClient sets the client used for requests (default: a client with
+// a 30 second timeout)
+func WithHTTPClient(client *http.Client) RemoteOption {
+	return func(r *RemoteFileReader) {
+		r.client = client
+	}
+}
+
+// WithCacheDir keeps fetched files in dir
+func WithCacheDir(dir string) RemoteOption {
+	return func(r *RemoteFileReader) {
+		r.cacheDir = dir
+	}
+}
+
+// WithChecksums pins URLs to the hex-encoded SHA-256 checksum of their
+// content. A file not matching its checksum is a ChecksumError.
+func WithChecksums(checksums map[string]string) RemoteOption {
+	return func(r *RemoteFileReader) {
+		r.checksums = make(map[string]string, len(checksums))
+		for u, sum := range checksums {
+			r.checksums[u] = strings.ToLower(sum)
+		}
+	}
+}
+
+// WithRequireChecksums refuses URLs without a pinned checksum
+func WithRequireChecksums(require bool) RemoteOption {
+	return func(r *RemoteFileReader) {
+		r.requireChecksums = require
+	}
+}
+
+// WithS3 sets the endpoint and credentials used for s3:// URLs
+func WithS3(config S3Config) RemoteOption {
+	return func(r *RemoteFileReader) {
+		r.s3 = config
+	}
+}
+
+// NewRemoteFileReader creates a reader fetching URLs and reading other
+// paths with local
+func NewRemoteFileReader(local FileReader, options ...RemoteOption) *RemoteFileReader {
+	r := &RemoteFileReader{
+		local:  local,
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+		cached: make(map[string][]byte),
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// ChecksumError reports remote content that does not match its pinned
+// checksum, or a URL without one when checksums are required
+type ChecksumError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Expected == "" {
+		return fmt.Sprintf("remote include %s has no pinned checksum", e.URL)
+	}
+	return fmt.Sprintf("remote include %s has checksum %s, expected %s", e.URL, e.Actual, e.Expected)
+}
+
+// ReadFile fetches URLs and reads other paths from the local reader
+func (r *RemoteFileReader) ReadFile(path string) ([]byte, error) {
+	return r.ReadFileContext(context.Background(), path)
+}
+
+// ReadFileContext is ReadFile that cancels fetching a URL once ctx is done
+func (r *RemoteFileReader) ReadFileContext(ctx context.Context, path string) ([]byte, error) {
+	if !isURL(path) {
+		if local, ok := r.local.(ContextFileReader); ok {
+			return local.ReadFileContext(ctx, path)
+		}
+		return r.local.ReadFile(path)
+	}
+	expected := r.checksums[path]
+	if expected == "" && r.requireChecksums {
+		return nil, &ChecksumError{URL: path}
+	}
+
+	r.mutex.Lock()
+	data, ok := r.cached[path]
+	r.mutex.Unlock()
+	if ok {
+		return data, nil
+	}
+
+	if expected != "" {
+		if data, err := r.readCache(path); err == nil && checksum(data) == expected {
+			r.remember(path, data)
+			return data, nil
+		}
+	}
+
+	data, err := r.fetch(ctx, path)
+	if err != nil {
+		// Unpinned files may come from an earlier fetch when the server
+		// cannot be reached
+		if expected == "" && ctx.Err() == nil {
+			if cached, cacheErr := r.readCache(path); cacheErr == nil {
+				r.remember(path, cached)
+				return cached, nil
+			}
+		}
+		return nil, err
+	}
+	if expected != "" && checksum(data) != expected {
+		return nil, &ChecksumError{URL: path, Expected: expected, Actual: checksum(data)}
+	}
+	r.writeCache(path, data)
+	r.remember(path, data)
+	return data, nil
+}
+
+// Glob passes patterns to the local reader; URLs cannot be globbed
+func (r *RemoteFileReader) Glob(pattern string) ([]string, error) {
+	if isURL(pattern) {
+		return nil, fmt.Errorf("cannot glob remote include %s", pattern)
+	}
+	globber, ok := r.local.(Globber)
+	if !ok {
+		return nil, fmt.Errorf("the file reader does not support include +glob")
+	}
+	return globber.Glob(pattern)
+}
+
+func (r *RemoteFileReader) remember(path string, data []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cached[path] = data
+}
+
+func (r *RemoteFileReader) fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := r.request(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close() // Ignore error in defer
+	}()
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s: %w", rawURL, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("%s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteSize {
+		return nil, fmt.Errorf("%s: larger than %d bytes", rawURL, maxRemoteSize)
+	}
+	return data, nil
+}
+
+// request builds the GET request for a URL, translating s3:// URLs to
+// path-style requests to the S3 endpoint
+func (r *RemoteFileReader) request(ctx context.Context, rawURL string) (*http.Request, error) {
+	if !strings.HasPrefix(rawURL, "s3://") {
+		return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(rawURL, "s3://"), "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("invalid S3 URL %s, expected s3://bucket/key", rawURL)
+	}
+	region := r.s3.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSuffix(r.s3.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/"+s3Escape(bucket+"/"+key), nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.s3.AccessKeyID != "" {
+		signS3(req, r.s3, region, r.now().UTC())
+	}
+	return req, nil
+}
+
+// signS3 adds an AWS Signature Version 4 authorization to a GET request
+// without a body
+func signS3(req *http.Request, config S3Config, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": "UNSIGNED-PAYLOAD",
+		"x-amz-date":           amzDate,
+	}
+	if config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", config.SessionToken)
+		headers = append(headers, "x-amz-security-token")
+		values["x-amz-security-token"] = config.SessionToken
+	}
+
+	var canonical strings.Builder
+	fmt.Fprintf(&canonical, "GET\n%s\n\n", req.URL.EscapedPath())
+	for _, h := range headers {
+		fmt.Fprintf(&canonical, "%s:%s\n", h, values[h])
+	}
+	signed := strings.Join(headers, ";")
+	fmt.Fprintf(&canonical, "\n%s\nUNSIGNED-PAYLOAD", signed)
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + checksum([]byte(canonical.String()))
+	key := []byte("AWS4" + config.SecretAccessKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		config.AccessKeyID, scope, signed, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape percent-encodes an object path the way S3 signatures expect:
+// everything but unreserved characters and the slashes between segments
+func s3Escape(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachePath returns the file caching a URL, named by the hash of the URL
+func (r *RemoteFileReader) cachePath(rawURL string) string {
+	name := checksum([]byte(rawURL))
+	if u, err := url.Parse(rawURL); err == nil {
+		if ext := filepath.Ext(u.Path); ext != "" && len(ext) <= 8 {
+			name += ext
+		}
+	}
+	return filepath.Join(r.cacheDir, name)
+}
+
+func (r *RemoteFileReader) readCache(rawURL string) ([]byte, error) {
+	if r.cacheDir == "" {
+		return nil, os.ErrNotExist
+	}
+	return os.ReadFile(r.cachePath(rawURL))
+}
+
+// writeCache stores a fetched file; failing to is not an error, the file
+// is only fetched again
+func (r *RemoteFileReader) writeCache(rawURL string, data []byte) {
+	if r.cacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(r.cacheDir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(r.cacheDir, ".fetch-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), r.cachePath(rawURL)) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
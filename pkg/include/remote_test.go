@@ -0,0 +1,195 @@
+package include
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const remoteVCL = "vcl 4.1;\nbackend shared { .host = \"192.0.2.1\"; }\n"
+
+func newRemoteServer(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch r.URL.Path {
+		case "/shared.vcl", "/bucket/vcl/shared.vcl":
+			_, _ = w.Write([]byte(remoteVCL))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolver_RemoteIncludes(t *testing.T) {
+	var requests int32
+	server := newRemoteServer(t, &requests)
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl": "vcl 4.1;\ninclude \"" + server.URL + "/shared.vcl\";\n",
+	})
+
+	// Remote includes are off unless enabled
+	_, err := NewResolver(WithFileReader(reader)).ResolveFile("main.vcl")
+	var disabled *RemoteIncludeError
+	if !errors.As(err, &disabled) {
+		t.Fatalf("Expected a RemoteIncludeError, got %v", err)
+	}
+
+	resolver := NewResolver(WithFileReader(reader), WithRemoteIncludes())
+	program, err := resolver.ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve remote include: %v", err)
+	}
+	if findDeclarationByName(program, "backend", "shared") == nil {
+		t.Error("Expected the backend of the remote file")
+	}
+	if source, _ := resolver.SourceMap().Lookup(findDeclarationByName(program, "backend", "shared")); source.Path != server.URL+"/shared.vcl" {
+		t.Errorf("Expected the URL as path, got %q", source.Path)
+	}
+}
+
+func TestRemoteFileReader_Checksums(t *testing.T) {
+	var requests int32
+	server := newRemoteServer(t, &requests)
+	url := server.URL + "/shared.vcl"
+	local := NewMemoryFileReader(map[string]string{})
+
+	tests := []struct {
+		name    string
+		options []RemoteOption
+		wantErr bool
+	}{
+		{"unpinned", nil, false},
+		{"pinned", []RemoteOption{WithChecksums(map[string]string{url: checksum([]byte(remoteVCL))})}, false},
+		{"wrong checksum", []RemoteOption{WithChecksums(map[string]string{url: strings.Repeat("0", 64)})}, true},
+		{"required", []RemoteOption{WithRequireChecksums(true)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRemoteFileReader(local, tt.options...).ReadFile(url)
+			var checksumErr *ChecksumError
+			if tt.wantErr != errors.As(err, &checksumErr) {
+				t.Errorf("ReadFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoteFileReader_Cache(t *testing.T) {
+	var requests int32
+	server := newRemoteServer(t, &requests)
+	url := server.URL + "/shared.vcl"
+	dir := t.TempDir()
+	pinned := WithChecksums(map[string]string{url: checksum([]byte(remoteVCL))})
+
+	reader := NewRemoteFileReader(nil, WithCacheDir(dir), pinned)
+	for i := 0; i < 2; i++ {
+		if _, err := reader.ReadFile(url); err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected one request for two reads, got %d", requests)
+	}
+
+	// Pinned files are served from the cache directory by new readers,
+	// unpinned ones when the server is gone
+	server.Close()
+	if data, err := NewRemoteFileReader(nil, WithCacheDir(dir), pinned).ReadFile(url); err != nil || string(data) != remoteVCL {
+		t.Errorf("Expected the pinned file from the cache, got %q, %v", data, err)
+	}
+	if data, err := NewRemoteFileReader(nil, WithCacheDir(dir)).ReadFile(url); err != nil || string(data) != remoteVCL {
+		t.Errorf("Expected the cached file when the fetch fails, got %q, %v", data, err)
+	}
+	if _, err := NewRemoteFileReader(nil).ReadFile(url); err == nil {
+		t.Error("Expected an error without server and cache")
+	}
+}
+
+func TestRemoteFileReader_S3(t *testing.T) {
+	var authorization, amzDate string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization, amzDate = r.Header.Get("Authorization"), r.Header.Get("X-Amz-Date")
+		if r.URL.Path != "/bucket/vcl/shared.vcl" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(remoteVCL))
+	}))
+	defer server.Close()
+
+	reader := NewRemoteFileReader(nil, WithS3(S3Config{
+		Endpoint:        server.URL,
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	}))
+	reader.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	data, err := reader.ReadFile("s3://bucket/vcl/shared.vcl")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != remoteVCL {
+		t.Errorf("Unexpected content %q", data)
+	}
+	if amzDate != "20240501T120000Z" {
+		t.Errorf("Unexpected X-Amz-Date %q", amzDate)
+	}
+	prefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/eu-west-1/s3/aws4_request, " +
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="
+	if !strings.HasPrefix(authorization, prefix) || len(authorization) != len(prefix)+64 {
+		t.Errorf("Unexpected Authorization header %q", authorization)
+	}
+
+	if _, err := reader.ReadFile("s3://bucket"); err == nil {
+		t.Error("Expected an error for an S3 URL without key")
+	}
+}
+
+func TestS3Escape(t *testing.T) {
+	tests := map[string]string{
+		"bucket/vcl/shared.vcl": "bucket/vcl/shared.vcl",
+		"bucket/a b+c.vcl":      "bucket/a%20b%2Bc.vcl",
+		"bucket/~user/x_y-z":    "bucket/~user/x_y-z",
+	}
+	for in, want := range tests {
+		if got := s3Escape(in); got != want {
+			t.Errorf("s3Escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestResolver_Context(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl": "vcl 4.1;\ninclude \"" + server.URL + "/slow.vcl\";\n",
+	})
+	resolver := NewResolver(WithFileReader(reader), WithRemoteIncludes())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := resolver.ResolveFileContext(ctx, "main.vcl"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to stop the fetch, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Resolving took %s after the deadline", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := resolver.ResolveFileContext(cancelled, "main.vcl"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled before reading any file, got %v", err)
+	}
+}
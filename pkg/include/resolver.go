@@ -9,8 +9,14 @@
 package include
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"path/filepath"
+	"regexp"
+	"strings"
 
+	"github.com/perbu/vclparser/internal/pathutil"
 	"github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/parser"
 )
@@ -19,10 +25,22 @@ import (
 type Resolver struct {
 	fileReader   FileReader
 	basePath     string
+	searchPath   []string
+	unsafePath   bool
+	globPaths    bool
+	variables    map[string]string
+	filter       func(path string) bool
+	parse        func(input, filename string) (*ast.Program, error)
+	remote       []RemoteOption
 	visitedFiles map[string]bool
 	includeChain []string
+	// pathChain holds the paths the files of includeChain were read from
+	pathChain    []string
+	files        []string
 	maxDepth     int
 	currentDepth int
+	sourceMap    *SourceMap
+	ctx          context.Context
 }
 
 // Option represents a configuration option for the Resolver
@@ -35,6 +53,79 @@ func WithBasePath(basePath string) Option {
 	}
 }
 
+// WithSearchPath sets the directories searched, in order, for relative
+// include paths, like varnishd's vcl_path. Without a search path, relative
+// paths are resolved against the base path.
+func WithSearchPath(dirs ...string) Option {
+	return func(r *Resolver) {
+		r.searchPath = append([]string(nil), dirs...)
+	}
+}
+
+// WithUnsafePath sets whether include paths may be absolute or contain
+// "..", like varnishd's vcc_unsafe_path (default: true)
+func WithUnsafePath(allow bool) Option {
+	return func(r *Resolver) {
+		r.unsafePath = allow
+	}
+}
+
+// WithGlobPaths sets whether plain include paths containing *, ? or [ are
+// expanded like include +glob, as some configuration generators expect
+// (default: false)
+func WithGlobPaths(allow bool) Option {
+	return func(r *Resolver) {
+		r.globPaths = allow
+	}
+}
+
+// WithVariables sets the values of ${name} placeholders in include paths,
+// so templated configurations such as include "${env}/backends.vcl" can be
+// resolved. A placeholder without a value is an UndefinedVariableError.
+func WithVariables(vars map[string]string) Option {
+	return func(r *Resolver) {
+		r.variables = vars
+	}
+}
+
+// WithIncludeFilter sets a predicate deciding which files are included,
+// for configurations whose includes depend on feature flags. It is called
+// with the path of every file to include, after placeholders are replaced
+// and glob patterns expanded; files it returns false for are left out.
+func WithIncludeFilter(filter func(path string) bool) Option {
+	return func(r *Resolver) {
+		r.filter = filter
+	}
+}
+
+// WithParseFunc parses included files with parse instead of parser.Parse,
+// for instance to have them parsed through a cache. Resolving does not
+// modify the programs parse returns.
+func WithParseFunc(parse func(input, filename string) (*ast.Program, error)) Option {
+	return func(r *Resolver) {
+		r.parse = parse
+	}
+}
+
+// WithFS reads files from fsys, such as an embed.FS or a zip.Reader,
+// instead of the operating system. Relative includes are resolved within
+// fsys, so the base path is usually left unset.
+func WithFS(fsys fs.FS) Option {
+	return func(r *Resolver) {
+		r.fileReader = NewFSFileReader(fsys)
+	}
+}
+
+// WithRemoteIncludes allows includes of http://, https:// and s3:// URLs,
+// read by a RemoteFileReader configured with options on top of the file
+// reader. Without it such includes are a RemoteIncludeError, so VCL cannot
+// pull in code from the network unless the caller asks for it.
+func WithRemoteIncludes(options ...RemoteOption) Option {
+	return func(r *Resolver) {
+		r.remote = append([]RemoteOption{}, options...)
+	}
+}
+
 // WithMaxDepth sets the maximum include depth (default: 10)
 func WithMaxDepth(maxDepth int) Option {
 	return func(r *Resolver) {
@@ -56,6 +147,10 @@ func NewResolver(options ...Option) *Resolver {
 		includeChain: make([]string, 0),
 		maxDepth:     10,
 		currentDepth: 0,
+		unsafePath:   true,
+		parse:        parser.Parse,
+		sourceMap:    newSourceMap(),
+		ctx:          context.Background(),
 	}
 
 	// Apply options
@@ -67,32 +162,73 @@ func NewResolver(options ...Option) *Resolver {
 	if resolver.fileReader == nil {
 		resolver.fileReader = NewOSFileReader(resolver.basePath)
 	}
+	if resolver.remote != nil {
+		resolver.fileReader = NewRemoteFileReader(resolver.fileReader, resolver.remote...)
+	}
 
 	return resolver
 }
 
 // ResolveFile parses a VCL file and recursively resolves all include statements
 func (r *Resolver) ResolveFile(filename string) (*ast.Program, error) {
+	return r.ResolveFileContext(context.Background(), filename)
+}
+
+// ResolveFileContext is ResolveFile that stops reading files once ctx is
+// done and returns ctx.Err(). Remote includes are fetched with ctx, and so
+// are files of other readers implementing ContextFileReader.
+func (r *Resolver) ResolveFileContext(ctx context.Context, filename string) (*ast.Program, error) {
 	// Reset state for new resolution
+	r.ctx = ctx
 	r.visitedFiles = make(map[string]bool)
 	r.includeChain = make([]string, 0)
+	r.pathChain = nil
+	r.files = nil
 	r.currentDepth = 0
+	r.sourceMap = newSourceMap()
 
 	return r.resolveFile(filename)
 }
 
 // Resolve takes an already-parsed program and resolves any include statements
 func (r *Resolver) Resolve(program *ast.Program) (*ast.Program, error) {
+	return r.ResolveContext(context.Background(), program)
+}
+
+// ResolveContext is Resolve that stops reading files once ctx is done, like
+// ResolveFileContext
+func (r *Resolver) ResolveContext(ctx context.Context, program *ast.Program) (*ast.Program, error) {
 	// Reset state
+	r.ctx = ctx
 	r.visitedFiles = make(map[string]bool)
 	r.includeChain = make([]string, 0)
+	r.pathChain = nil
+	r.files = nil
 	r.currentDepth = 0
+	r.sourceMap = newSourceMap()
 
 	return r.processIncludes(program)
 }
 
+// SourceMap returns the origin of every declaration of the program returned
+// by the last call to ResolveFile or Resolve
+func (r *Resolver) SourceMap() *SourceMap {
+	return r.sourceMap
+}
+
+// Files returns the paths of the files read by the last call to ResolveFile
+// or Resolve, in the order they were included. Relative paths are joined to
+// the base path or the search path directory the file was found in.
+func (r *Resolver) Files() []string {
+	return append([]string(nil), r.files...)
+}
+
 // resolveFile parses a single file and resolves its includes
 func (r *Resolver) resolveFile(filename string) (*ast.Program, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Check depth limit
 	if r.currentDepth > r.maxDepth {
 		return nil, &MaxDepthError{
@@ -102,13 +238,36 @@ func (r *Resolver) resolveFile(filename string) (*ast.Program, error) {
 		}
 	}
 
-	// Convert to absolute path for tracking
-	absPath, err := filepath.Abs(filepath.Join(r.basePath, filename))
+	if !r.unsafePath && pathutil.IsUnsafe(filename) {
+		return nil, &UnsafePathError{Path: filename}
+	}
+	if isURL(filename) && r.remote == nil {
+		return nil, &RemoteIncludeError{Path: filename}
+	}
+
+	// Read the file
+	path, content, err := r.readFile(filename)
 	if err != nil {
 		return nil, &FileNotFoundError{
-			Path:     filename,
-			BasePath: r.basePath,
-			Cause:    err,
+			Path:       filename,
+			BasePath:   r.basePath,
+			SearchPath: r.searchPath,
+			Cause:      err,
+		}
+	}
+
+	// Convert to absolute path for tracking
+	if !filepath.IsAbs(path) && !isURL(path) {
+		path = filepath.Join(r.basePath, path)
+	}
+	absPath := path
+	if !isURL(path) {
+		if absPath, err = filepath.Abs(path); err != nil {
+			return nil, &FileNotFoundError{
+				Path:     filename,
+				BasePath: r.basePath,
+				Cause:    err,
+			}
 		}
 	}
 
@@ -120,18 +279,8 @@ func (r *Resolver) resolveFile(filename string) (*ast.Program, error) {
 		}
 	}
 
-	// Read the file
-	content, err := r.fileReader.ReadFile(filename)
-	if err != nil {
-		return nil, &FileNotFoundError{
-			Path:     filename,
-			BasePath: r.basePath,
-			Cause:    err,
-		}
-	}
-
 	// Parse the file
-	program, err := parser.Parse(string(content), filename)
+	program, err := r.parse(string(content), filename)
 	if err != nil {
 		return nil, &ParseError{
 			Path:  filename,
@@ -142,6 +291,8 @@ func (r *Resolver) resolveFile(filename string) (*ast.Program, error) {
 	// Mark this file as visited and add to chain
 	r.visitedFiles[absPath] = true
 	r.includeChain = append(r.includeChain, filename)
+	r.pathChain = append(r.pathChain, path)
+	r.files = append(r.files, path)
 	r.currentDepth++
 
 	// Process includes in this file
@@ -153,27 +304,148 @@ func (r *Resolver) resolveFile(filename string) (*ast.Program, error) {
 	// Clean up state for this file
 	r.currentDepth--
 	r.includeChain = r.includeChain[:len(r.includeChain)-1]
+	r.pathChain = r.pathChain[:len(r.pathChain)-1]
 
 	return resolvedProgram, nil
 }
 
+// readFile reads an include file, trying each search path directory in turn
+// for relative paths. It returns the path the file was found at.
+func (r *Resolver) readFile(filename string) (string, []byte, error) {
+	if len(r.searchPath) == 0 || filepath.IsAbs(filename) || isURL(filename) {
+		content, err := r.read(filename)
+		return filename, content, err
+	}
+
+	var firstErr error
+	for _, dir := range r.searchPath {
+		path := filepath.Join(dir, filename)
+		content, err := r.read(path)
+		if err == nil {
+			return path, content, nil
+		}
+		// Keep looking only if the file is missing from this directory
+		if !errors.Is(err, fs.ErrNotExist) {
+			return path, nil, err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return filename, nil, firstErr
+}
+
+// read reads a file with the file reader, passing it the context when it
+// supports one
+func (r *Resolver) read(path string) ([]byte, error) {
+	if reader, ok := r.fileReader.(ContextFileReader); ok {
+		return reader.ReadFileContext(r.ctx, path)
+	}
+	return r.fileReader.ReadFile(path)
+}
+
+// glob expands the pattern of include +glob into the matching files in sorted
+// order, searching the directories of the search path in turn for relative
+// patterns. Matching no file is an error, as for a plain include.
+func (r *Resolver) glob(pattern string) ([]string, error) {
+	if !r.unsafePath && pathutil.IsUnsafe(pattern) {
+		return nil, &UnsafePathError{Path: pattern}
+	}
+	globber, ok := r.fileReader.(Globber)
+	if !ok {
+		return nil, &FileNotFoundError{
+			Path:     pattern,
+			BasePath: r.basePath,
+			Cause:    errors.New("the file reader does not support include +glob"),
+		}
+	}
+
+	patterns := []string{pattern}
+	if len(r.searchPath) > 0 && !filepath.IsAbs(pattern) {
+		patterns = make([]string, len(r.searchPath))
+		for i, dir := range r.searchPath {
+			patterns[i] = filepath.Join(dir, pattern)
+		}
+	}
+	for _, p := range patterns {
+		matches, err := globber.Glob(p)
+		if err != nil {
+			return nil, &FileNotFoundError{Path: pattern, BasePath: r.basePath, Cause: err}
+		}
+		if len(matches) > 0 {
+			return matches, nil
+		}
+	}
+	return nil, &FileNotFoundError{
+		Path:       pattern,
+		BasePath:   r.basePath,
+		SearchPath: r.searchPath,
+		Cause:      fs.ErrNotExist,
+	}
+}
+
+// placeholder matches ${name} in include paths
+var placeholder = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)\}`)
+
+// expand replaces the placeholders of an include path with their values
+func (r *Resolver) expand(path string) (string, error) {
+	var undefined string
+	expanded := placeholder.ReplaceAllStringFunc(path, func(m string) string {
+		name := placeholder.FindStringSubmatch(m)[1]
+		value, ok := r.variables[name]
+		if !ok && undefined == "" {
+			undefined = name
+		}
+		return value
+	})
+	if undefined != "" {
+		return "", &UndefinedVariableError{Path: path, Name: undefined}
+	}
+	return expanded, nil
+}
+
 // processIncludes walks through the AST and resolves include statements
 func (r *Resolver) processIncludes(program *ast.Program) (*ast.Program, error) {
 	var newDeclarations []ast.Declaration
 
+	// The file being processed is the last one in the chain; a program
+	// passed to Resolve has no file name
+	file, path, parents := "", "", r.includeChain
+	if len(r.includeChain) > 0 {
+		file, parents = r.includeChain[len(r.includeChain)-1], r.includeChain[:len(r.includeChain)-1]
+		path = r.pathChain[len(r.pathChain)-1]
+	}
+
 	for _, decl := range program.Declarations {
 		if includeDecl, ok := decl.(*ast.IncludeDecl); ok {
-			// Parse the included file
-			includedProgram, err := r.resolveFile(includeDecl.Path)
+			pattern, err := r.expand(includeDecl.Path)
 			if err != nil {
 				return nil, err
 			}
+			paths := []string{pattern}
+			if includeDecl.Glob || r.globPaths && strings.ContainsAny(pattern, "*?[") {
+				if paths, err = r.glob(pattern); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, path := range paths {
+				if r.filter != nil && !r.filter(path) {
+					continue
+				}
+				// Parse the included file
+				includedProgram, err := r.resolveFile(path)
+				if err != nil {
+					return nil, err
+				}
 
-			// Add declarations from included file (preserving order)
-			newDeclarations = append(newDeclarations, includedProgram.Declarations...)
+				// Add declarations from included file (preserving order)
+				newDeclarations = append(newDeclarations, includedProgram.Declarations...)
+			}
 		} else {
 			// Keep non-include declarations
 			newDeclarations = append(newDeclarations, decl)
+			r.sourceMap.record(decl, file, path, parents)
 		}
 	}
 
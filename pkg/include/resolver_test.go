@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/parser"
@@ -432,3 +433,271 @@ func BenchmarkResolver_Nested(b *testing.B) {
 		}
 	}
 }
+
+func TestResolver_SourceMap(t *testing.T) {
+	reader := createTestFiles()
+	resolver := NewResolver(WithFileReader(reader))
+
+	program, err := resolver.ResolveFile("nested_main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve nested includes: %v", err)
+	}
+
+	sourceMap := resolver.SourceMap()
+	if sourceMap.Len() != len(program.Declarations) {
+		t.Errorf("Expected %d declarations in the source map, got %d", len(program.Declarations), sourceMap.Len())
+	}
+
+	tests := []struct {
+		declType string
+		name     string
+		file     string
+		chain    []string
+		line     int
+	}{
+		{"backend", "level2_backend", "nested_level2.vcl", []string{"nested_main.vcl", "nested_level1.vcl"}, 3},
+		{"acl", "level2_acl", "nested_level2.vcl", []string{"nested_main.vcl", "nested_level1.vcl"}, 7},
+		{"subroutine", "level1_sub", "nested_level1.vcl", []string{"nested_main.vcl"}, 3},
+	}
+	for _, tt := range tests {
+		decl := findDeclarationByName(program, tt.declType, tt.name)
+		if decl == nil {
+			t.Fatalf("Expected to find %s %s", tt.declType, tt.name)
+		}
+		source, ok := sourceMap.Lookup(decl)
+		if !ok {
+			t.Fatalf("No source recorded for %s", tt.name)
+		}
+		if source.File != tt.file || strings.Join(source.Chain, ",") != strings.Join(tt.chain, ",") {
+			t.Errorf("%s: got %s via %v, expected %s via %v", tt.name, source.File, source.Chain, tt.file, tt.chain)
+		}
+		// Positions stay relative to the file the declaration came from
+		if line := decl.Start().Line; line != tt.line {
+			t.Errorf("%s: expected line %d, got %d", tt.name, tt.line, line)
+		}
+	}
+
+	// Top-level declarations have no include chain
+	program, err = resolver.ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	source, ok := resolver.SourceMap().Lookup(findDeclarationByName(program, "subroutine", "vcl_recv"))
+	if !ok || source.File != "main.vcl" || len(source.Chain) != 0 {
+		t.Errorf("Expected vcl_recv from main.vcl without chain, got %+v", source)
+	}
+}
+
+func TestResolver_SearchPath(t *testing.T) {
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl": `vcl 4.1;
+include "common.vcl";
+include "site.vcl";`,
+		"/etc/varnish/site.vcl":         "vcl 4.1;\nsub site_local {\n}\n",
+		"/etc/varnish/common.vcl":       "vcl 4.1;\nsub common_local {\n}\n",
+		"/usr/share/varnish/common.vcl": "vcl 4.1;\nsub common_shared {\n}\n",
+	})
+	resolver := NewResolver(WithFileReader(reader), WithSearchPath("/usr/share/varnish", "/etc/varnish"))
+
+	// The main file itself is found through the search path too
+	if _, err := resolver.ResolveFile("main.vcl"); err == nil {
+		t.Fatal("Expected main.vcl not to be found in the search path")
+	}
+	reader.AddFile("/etc/varnish/main.vcl", reader.files["main.vcl"])
+
+	program, err := resolver.ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	// Earlier directories take precedence
+	for _, name := range []string{"common_shared", "site_local"} {
+		if findDeclarationByName(program, "subroutine", name) == nil {
+			t.Errorf("Expected to find %s", name)
+		}
+	}
+	if findDeclarationByName(program, "subroutine", "common_local") != nil {
+		t.Error("common.vcl should be read from the first search directory")
+	}
+	// Files and the source map give the paths the files were found at
+	files := strings.Join(resolver.Files(), ",")
+	if files != "/etc/varnish/main.vcl,/usr/share/varnish/common.vcl,/etc/varnish/site.vcl" {
+		t.Errorf("Unexpected files: %s", files)
+	}
+	if path := resolver.SourceMap().Path(findDeclarationByName(program, "subroutine", "common_shared")); path != "/usr/share/varnish/common.vcl" {
+		t.Errorf("Expected common_shared from /usr/share/varnish/common.vcl, got %q", path)
+	}
+
+	_, err = NewResolver(WithFileReader(reader), WithSearchPath("/srv")).ResolveFile("main.vcl")
+	var notFound *FileNotFoundError
+	if !errors.As(err, &notFound) || !strings.Contains(err.Error(), "search path: /srv") {
+		t.Errorf("Expected a FileNotFoundError naming the search path, got %v", err)
+	}
+}
+
+func TestResolver_UnsafePath(t *testing.T) {
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl":             "vcl 4.1;\ninclude \"/etc/varnish/abs.vcl\";\n",
+		"dotted.vcl":           "vcl 4.1;\ninclude \"../up.vcl\";\n",
+		"/etc/varnish/abs.vcl": "vcl 4.1;\nsub abs {\n}\n",
+		"../up.vcl":            "vcl 4.1;\nsub up {\n}\n",
+	})
+
+	// Allowed by default
+	for _, file := range []string{"main.vcl", "dotted.vcl"} {
+		if _, err := NewResolver(WithFileReader(reader)).ResolveFile(file); err != nil {
+			t.Errorf("%s: unexpected error: %v", file, err)
+		}
+	}
+
+	for _, file := range []string{"main.vcl", "dotted.vcl"} {
+		_, err := NewResolver(WithFileReader(reader), WithUnsafePath(false)).ResolveFile(file)
+		var unsafe *UnsafePathError
+		if !errors.As(err, &unsafe) {
+			t.Errorf("%s: expected an UnsafePathError, got %v", file, err)
+		}
+	}
+}
+
+func TestResolver_Glob(t *testing.T) {
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl":       "vcl 4.1;\ninclude +glob \"conf.d/*.vcl\";\n",
+		"conf.d/b.vcl":   "vcl 4.1;\nsub b {\n}\n",
+		"conf.d/a.vcl":   "vcl 4.1;\nsub a {\n}\n",
+		"conf.d/c.txt":   "not vcl",
+		"empty.vcl":      "vcl 4.1;\ninclude +glob \"none/*.vcl\";\n",
+		"dotted.vcl":     "vcl 4.1;\ninclude +glob \"../*.vcl\";\n",
+		"../outside.vcl": "vcl 4.1;\nsub outside {\n}\n",
+	})
+
+	program, err := NewResolver(WithFileReader(reader)).ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	// Matches are included in sorted order
+	var names []string
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok {
+			names = append(names, sub.Name)
+		}
+	}
+	if strings.Join(names, ",") != "a,b" {
+		t.Errorf("Expected subroutines a,b, got %v", names)
+	}
+
+	_, err = NewResolver(WithFileReader(reader)).ResolveFile("empty.vcl")
+	var notFound *FileNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Expected a FileNotFoundError for a pattern matching nothing, got %v", err)
+	}
+
+	_, err = NewResolver(WithFileReader(reader), WithUnsafePath(false)).ResolveFile("dotted.vcl")
+	var unsafe *UnsafePathError
+	if !errors.As(err, &unsafe) {
+		t.Errorf("Expected an UnsafePathError, got %v", err)
+	}
+}
+
+func TestResolver_GlobPaths(t *testing.T) {
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl":     "vcl 4.1;\ninclude \"conf.d/*.vcl\";\nsub vcl_recv {\n\tcall a;\n\tcall b;\n}\n",
+		"conf.d/a.vcl": "vcl 4.1;\nsub a {\n}\n",
+		"conf.d/b.vcl": "vcl 4.1;\nsub b {\n}\n",
+	})
+
+	// Without the option the pattern is a file name
+	_, err := NewResolver(WithFileReader(reader)).ResolveFile("main.vcl")
+	var notFound *FileNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Expected a FileNotFoundError, got %v", err)
+	}
+
+	program, err := NewResolver(WithFileReader(reader), WithGlobPaths(true)).ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	// main.vcl calls the subroutines of both matches
+	for _, name := range []string{"a", "b", "vcl_recv"} {
+		if findDeclarationByName(program, "subroutine", name) == nil {
+			t.Errorf("Expected to find %s", name)
+		}
+	}
+}
+
+func TestResolver_VariablesAndFilter(t *testing.T) {
+	reader := NewMemoryFileReader(map[string]string{
+		"main.vcl": `vcl 4.1;
+include "${env}/backends.vcl";
+include +glob "features/*.vcl";
+
+sub vcl_recv {
+	call geoip;
+}
+`,
+		"prod/backends.vcl":    "vcl 4.1;\nbackend prod { .host = \"192.0.2.1\"; }\n",
+		"staging/backends.vcl": "vcl 4.1;\nbackend staging { .host = \"192.0.2.2\"; }\n",
+		"features/geoip.vcl":   "vcl 4.1;\nsub geoip {\n}\n",
+		"features/waf.vcl":     "vcl 4.1;\nsub waf {\n}\n",
+	})
+	flags := map[string]bool{"features/geoip.vcl": true}
+	enabled := func(path string) bool {
+		return !strings.HasPrefix(path, "features/") || flags[path]
+	}
+
+	program, err := NewResolver(
+		WithFileReader(reader),
+		WithVariables(map[string]string{"env": "prod"}),
+		WithIncludeFilter(enabled),
+	).ResolveFile("main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	tests := []struct {
+		declType string
+		name     string
+		found    bool
+	}{
+		{"backend", "prod", true},
+		{"backend", "staging", false},
+		{"subroutine", "geoip", true},
+		{"subroutine", "waf", false},
+		{"subroutine", "vcl_recv", true},
+	}
+	for _, tt := range tests {
+		if found := findDeclarationByName(program, tt.declType, tt.name) != nil; found != tt.found {
+			t.Errorf("%s %s: found = %v, expected %v", tt.declType, tt.name, found, tt.found)
+		}
+	}
+
+	_, err = NewResolver(WithFileReader(reader)).ResolveFile("main.vcl")
+	var undefined *UndefinedVariableError
+	if !errors.As(err, &undefined) || undefined.Name != "env" {
+		t.Errorf("Expected an UndefinedVariableError for env, got %v", err)
+	}
+}
+
+func TestResolver_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vcl/main.vcl":        &fstest.MapFile{Data: []byte("vcl 4.1;\ninclude \"vcl/backends.vcl\";\ninclude +glob \"vcl/conf.d/*.vcl\";\n")},
+		"vcl/backends.vcl":    &fstest.MapFile{Data: []byte("vcl 4.1;\nbackend origin { .host = \"192.0.2.1\"; }\n")},
+		"vcl/conf.d/recv.vcl": &fstest.MapFile{Data: []byte("vcl 4.1;\nsub vcl_recv {\n}\n")},
+		"etc/varnish/abs.vcl": &fstest.MapFile{Data: []byte("vcl 4.1;\nsub absolute {\n}\n")},
+		"vcl/absolute.vcl":    &fstest.MapFile{Data: []byte("vcl 4.1;\ninclude \"/etc/varnish/abs.vcl\";\n")},
+	}
+
+	program, err := NewResolver(WithFS(fsys)).ResolveFile("vcl/main.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve includes: %v", err)
+	}
+	if findDeclarationByName(program, "backend", "origin") == nil || findDeclarationByName(program, "subroutine", "vcl_recv") == nil {
+		t.Error("Expected declarations from the included files")
+	}
+
+	// Absolute paths are relative to the root of the file system
+	program, err = NewResolver(WithFS(fsys)).ResolveFile("vcl/absolute.vcl")
+	if err != nil {
+		t.Fatalf("Failed to resolve absolute include: %v", err)
+	}
+	if findDeclarationByName(program, "subroutine", "absolute") == nil {
+		t.Error("Expected to find absolute")
+	}
+}
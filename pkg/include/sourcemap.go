@@ -0,0 +1,64 @@
+package include
+
+import "github.com/perbu/vclparser/pkg/ast"
+
+// Source records where a merged declaration was read from
+type Source struct {
+	// File is the path of the file containing the declaration, as written in
+	// the include statement or passed to ResolveFile. It is empty for
+	// declarations of a program passed to Resolve.
+	File string
+	// Path is where File was read from: File joined to the base path or to
+	// the search path directory it was found in
+	Path string
+	// Chain lists the files that led to File being included, outermost
+	// first. It is empty for declarations of the top-level file.
+	Chain []string
+}
+
+// SourceMap maps the declarations of a resolved program back to the files
+// they came from. Positions of merged declarations are relative to their own
+// file, so the map is needed to report them against the right source.
+type SourceMap struct {
+	sources map[ast.Declaration]Source
+}
+
+func newSourceMap() *SourceMap {
+	return &SourceMap{sources: make(map[ast.Declaration]Source)}
+}
+
+// Lookup returns the source of a declaration of the resolved program
+func (m *SourceMap) Lookup(decl ast.Declaration) (Source, bool) {
+	if m == nil {
+		return Source{}, false
+	}
+	source, ok := m.sources[decl]
+	return source, ok
+}
+
+// Locate returns the file and include chain of a declaration. It lets a
+// SourceMap be passed to analyzer.Analyzer.SetSourceLocator.
+func (m *SourceMap) Locate(decl ast.Declaration) (string, []string, bool) {
+	source, ok := m.Lookup(decl)
+	return source.File, source.Chain, ok
+}
+
+// Path returns the path of the file a declaration was read from, or "" if
+// the declaration is not in the map. Passed to analyzer.EditsByFile it
+// sends the edits of a refactoring to the files they belong in.
+func (m *SourceMap) Path(decl ast.Declaration) string {
+	source, _ := m.Lookup(decl)
+	return source.Path
+}
+
+// Len returns the number of declarations in the map
+func (m *SourceMap) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.sources)
+}
+
+func (m *SourceMap) record(decl ast.Declaration, file, path string, chain []string) {
+	m.sources[decl] = Source{File: file, Path: path, Chain: append([]string(nil), chain...)}
+}
@@ -0,0 +1,38 @@
+package lexer
+
+import "fmt"
+
+// Dialect selects the VCL variant a lexer and parser accept
+type Dialect int
+
+const (
+	// DialectVarnish is the VCL of Varnish Cache and Varnish Enterprise
+	DialectVarnish Dialect = iota
+	// DialectFastly is the VCL of the Fastly CDN, which adds ':' for
+	// header subfields such as req.http.Cookie:session, and table entries
+	DialectFastly
+)
+
+// String returns the name ParseDialect accepts for d
+func (d Dialect) String() string {
+	switch d {
+	case DialectVarnish:
+		return "varnish"
+	case DialectFastly:
+		return "fastly"
+	default:
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+}
+
+// ParseDialect returns the dialect with the given name, "varnish" or
+// "fastly". The empty name is Varnish.
+func ParseDialect(name string) (Dialect, error) {
+	switch name {
+	case "", "varnish":
+		return DialectVarnish, nil
+	case "fastly":
+		return DialectFastly, nil
+	}
+	return DialectVarnish, fmt.Errorf("unknown VCL dialect %q (want varnish or fastly)", name)
+}
@@ -0,0 +1,36 @@
+package lexer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// addSeeds adds the VCL files of the test data to the corpus of a fuzz test
+func addSeeds(f *testing.F) {
+	files, _ := filepath.Glob("../../tests/testdata/*.vcl")
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(string(data))
+		}
+	}
+}
+
+func FuzzScanAll(f *testing.F) {
+	addSeeds(f)
+	f.Add("synthetic({\"a\"} + \"\"\"b\"\"\");\nC{ x }C /* c */ # d")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var text strings.Builder
+		for _, tok := range ScanAll(input, "fuzz.vcl") {
+			if input[tok.Start.Offset:tok.End.Offset] != tok.Value {
+				t.Fatalf("token %s %q spans %q", tok.Type, tok.Value, input[tok.Start.Offset:tok.End.Offset])
+			}
+			text.WriteString(tok.Value)
+		}
+		if text.String() != input {
+			t.Fatalf("tokens concatenate to %q", text.String())
+		}
+	})
+}
@@ -1,5 +1,10 @@
 package lexer
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 // Lexer tokenizes VCL source code
 type Lexer struct {
 	input    string
@@ -7,8 +12,11 @@ type Lexer struct {
 	pos      int  // current position in input (points to current char)
 	readPos  int  // current reading position in input (after current char)
 	ch       byte // current char under examination
-	line     int  // current line number (1-indexed)
-	column   int  // current column number (1-indexed)
+	line     int  // line of ch (1-indexed)
+	column   int  // column of ch in bytes (1-indexed)
+	// runeColumn is column counted in characters
+	runeColumn int
+	dialect    Dialect
 }
 
 // New creates a new lexer instance
@@ -17,14 +25,41 @@ func New(input, filename string) *Lexer {
 		input:    input,
 		filename: filename,
 		line:     1,
-		column:   1,
 	}
 	l.readChar() // Initialize first character
 	return l
 }
 
-// readChar reads the next character and advances position in input
+// NewAt creates a lexer that starts scanning input at pos, which must be the
+// start position of a token reported by a lexer over the same input. It lets
+// a parser resume in the middle of a document, for instance to reparse a
+// single declaration.
+func NewAt(input, filename string, pos Position) *Lexer {
+	l := &Lexer{
+		input:    input,
+		filename: filename,
+		readPos:  pos.Offset,
+		line:     pos.Line,
+		column:   pos.Column - 1,
+	}
+	// Positions from before rune columns were tracked have none, so count
+	// them from the start of the line
+	lineStart := strings.LastIndexByte(input[:pos.Offset], '\n') + 1
+	l.runeColumn = utf8.RuneCountInString(input[lineStart:pos.Offset])
+	l.readChar()
+	return l
+}
+
+// SetDialect makes the lexer scan the given VCL dialect. It must be called
+// before the first NextToken.
+func (l *Lexer) SetDialect(d Dialect) {
+	l.dialect = d
+}
+
+// readChar reads the next character and advances position in input. The
+// character after a newline starts the next line.
 func (l *Lexer) readChar() {
+	newline := l.ch == '\n'
 	if l.readPos >= len(l.input) {
 		l.ch = 0 // EOF
 	} else {
@@ -33,11 +68,15 @@ func (l *Lexer) readChar() {
 	l.pos = l.readPos
 	l.readPos++
 
-	if l.ch == '\n' {
+	if newline {
 		l.line++
 		l.column = 1
-	} else {
-		l.column++
+		l.runeColumn = 1
+		return
+	}
+	l.column++
+	if utf8.RuneStart(l.ch) {
+		l.runeColumn++
 	}
 }
 
@@ -52,9 +91,10 @@ func (l *Lexer) peekChar() byte {
 // currentPosition returns the current position
 func (l *Lexer) currentPosition() Position {
 	return Position{
-		Line:   l.line,
-		Column: l.column,
-		Offset: l.pos,
+		Line:       l.line,
+		Column:     l.column,
+		Offset:     l.pos,
+		RuneColumn: l.runeColumn,
 	}
 }
 
@@ -151,7 +191,11 @@ func (l *Lexer) NextToken() Token {
 			tok = l.makeToken(PIPE)
 		}
 	case '{':
-		tok = l.makeToken(LBRACE)
+		if l.peekChar() == '"' {
+			tok = l.readLongString(`"}`)
+		} else {
+			tok = l.makeToken(LBRACE)
+		}
 	case '}':
 		tok = l.makeToken(RBRACE)
 	case '(':
@@ -162,6 +206,12 @@ func (l *Lexer) NextToken() Token {
 		tok = l.makeToken(SEMICOLON)
 	case ',':
 		tok = l.makeToken(COMMA)
+	case ':':
+		if l.dialect == DialectFastly {
+			tok = l.makeToken(COLON)
+		} else {
+			tok = l.makeToken(ILLEGAL)
+		}
 	case '.':
 		tok = l.makeToken(DOT)
 	case '%':
@@ -169,7 +219,11 @@ func (l *Lexer) NextToken() Token {
 	case '~':
 		tok = l.makeToken(TILDE)
 	case '"':
-		tok = l.readString()
+		if strings.HasPrefix(l.input[l.pos:], `"""`) {
+			tok = l.readLongString(`"""`)
+		} else {
+			tok = l.readString()
+		}
 	case 'C':
 		// Check for C{ ... }C block
 		if l.peekChar() == '{' {
@@ -182,6 +236,11 @@ func (l *Lexer) NextToken() Token {
 		// Shell-style comment
 		tok = l.readLineComment()
 	case 0:
+		if l.pos < len(l.input) {
+			// A NUL byte in the input, which VCL does not allow
+			tok = l.makeToken(ILLEGAL)
+			break
+		}
 		tok.Type = EOF
 		tok.Value = ""
 	default:
@@ -201,11 +260,12 @@ func (l *Lexer) NextToken() Token {
 	return tok
 }
 
-// makeToken creates a token with the current character
+// makeToken creates a token with the current character. Like all token
+// values, its value is a slice of the input rather than a copy.
 func (l *Lexer) makeToken(tokenType TokenType) Token {
 	return Token{
 		Type:     tokenType,
-		Value:    string(l.ch),
+		Value:    l.input[l.pos : l.pos+1],
 		Start:    l.currentPosition(),
 		Filename: l.filename,
 	}
@@ -213,22 +273,27 @@ func (l *Lexer) makeToken(tokenType TokenType) Token {
 
 // makeTwoCharToken creates a token with current and next character
 func (l *Lexer) makeTwoCharToken(tokenType TokenType) Token {
-	ch := l.ch
+	start := l.pos
 	l.readChar()
 	return Token{
 		Type:     tokenType,
-		Value:    string(ch) + string(l.ch),
+		Value:    l.input[start : l.pos+1],
 		Start:    l.currentPosition(),
 		Filename: l.filename,
 	}
 }
 
-// readIdentifier reads an identifier or keyword
+// readIdentifier reads an identifier or keyword. As in VCC, identifiers may
+// contain '-' after the first character, so header names such as
+// req.http.X-Forwarded-For lex as identifiers rather than subtractions.
+// Fastly identifiers may also contain a ':' followed by a subfield name, as
+// in req.http.Cookie:session.
 func (l *Lexer) readIdentifier() Token {
 	start := l.currentPosition()
 	startPos := l.pos
 
-	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '_' {
+	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '_' || l.ch == '-' ||
+		(l.ch == ':' && l.dialect == DialectFastly && (isLetter(l.peekChar()) || isDigit(l.peekChar()))) {
 		l.readChar()
 	}
 
@@ -291,6 +356,43 @@ func (l *Lexer) readNumber() Token {
 	}
 }
 
+// readLongString reads a long string, which runs from its opening {" or
+// """ to the first closing delimiter and may hold quotes and newlines. The
+// token value includes the delimiters, and the lexer is left on the last
+// character of the closing one.
+func (l *Lexer) readLongString(closing string) Token {
+	start := l.currentPosition()
+	startPos := l.pos
+
+	// Consume the opening delimiter, which is as long as the closing one
+	for i := 0; i < len(closing); i++ {
+		l.readChar()
+	}
+	for l.pos >= len(l.input) || !strings.HasPrefix(l.input[l.pos:], closing) {
+		if l.pos >= len(l.input) {
+			return Token{
+				Type:     ILLEGAL,
+				Value:    "unterminated long string",
+				Start:    start,
+				End:      l.currentPosition(),
+				Filename: l.filename,
+			}
+		}
+		l.readChar()
+	}
+	for i := 1; i < len(closing); i++ {
+		l.readChar()
+	}
+
+	return Token{
+		Type:     LSTR,
+		Value:    l.input[startPos : l.pos+1],
+		Start:    start,
+		End:      l.currentPosition(),
+		Filename: l.filename,
+	}
+}
+
 // readString reads a string literal
 func (l *Lexer) readString() Token {
 	start := l.currentPosition()
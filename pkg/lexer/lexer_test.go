@@ -1,7 +1,13 @@
 package lexer
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/perbu/vclparser/internal/benchdata"
 )
 
 func TestNextToken(t *testing.T) {
@@ -180,6 +186,44 @@ func TestCBlock(t *testing.T) {
 	}
 }
 
+func TestLongStrings(t *testing.T) {
+	input := "synthetic({\"<p class=\"x\">\n</p>\"} + \"\"\"say \"hi\" {\"x\"}\"\"\");"
+
+	tests := []struct {
+		expectedType  TokenType
+		expectedValue string
+		line, column  int
+	}{
+		{SYNTHETIC_KW, "synthetic", 1, 1},
+		{LPAREN, "(", 1, 10},
+		{LSTR, "{\"<p class=\"x\">\n</p>\"}", 1, 11},
+		{PLUS, "+", 2, 8},
+		{LSTR, `"""say "hi" {"x"}"""`, 2, 10},
+		{RPAREN, ")", 2, 30},
+		{SEMICOLON, ";", 2, 31},
+		{EOF, "", 2, 32},
+	}
+
+	l := New(input, "test.vcl")
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Value != tt.expectedValue {
+			t.Fatalf("tests[%d] - got %s %q, want %s %q", i, tok.Type, tok.Value, tt.expectedType, tt.expectedValue)
+		}
+		if tok.Start.Line != tt.line || tok.Start.Column != tt.column {
+			t.Errorf("tests[%d] - %q starts at %d:%d, want %d:%d", i, tok.Value,
+				tok.Start.Line, tok.Start.Column, tt.line, tt.column)
+		}
+	}
+
+	for _, input := range []string{`{"never closed`, `"""never closed""`} {
+		tok := New(input, "test.vcl").NextToken()
+		if tok.Type != ILLEGAL || tok.Value != "unterminated long string" {
+			t.Errorf("%q: got %s %q, want an unterminated long string", input, tok.Type, tok.Value)
+		}
+	}
+}
+
 func TestNumbers(t *testing.T) {
 	input := `123 456.789 3.14e10 2E-5`
 
@@ -244,3 +288,247 @@ func TestKeywords(t *testing.T) {
 		}
 	}
 }
+
+func TestHyphenatedIdentifiers(t *testing.T) {
+	input := `req.http.X-Forwarded-For x-backend a - b`
+
+	tests := []struct {
+		expectedType  TokenType
+		expectedValue string
+	}{
+		{ID, "req"},
+		{DOT, "."},
+		{ID, "http"},
+		{DOT, "."},
+		{ID, "X-Forwarded-For"},
+		{ID, "x-backend"},
+		{ID, "a"},
+		{MINUS, "-"},
+		{ID, "b"},
+		{EOF, ""},
+	}
+
+	l := New(input, "test.vcl")
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("test[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Value != tt.expectedValue {
+			t.Fatalf("test[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedValue, tok.Value)
+		}
+	}
+}
+
+func TestFastlyDialect(t *testing.T) {
+	input := `req.http.Cookie:session "a": 1`
+
+	l := New(input, "test.vcl")
+	l.SetDialect(DialectFastly)
+	want := []struct {
+		typ   TokenType
+		value string
+	}{
+		{ID, "req"}, {DOT, "."}, {ID, "http"}, {DOT, "."}, {ID, "Cookie:session"},
+		{CSTR, `"a"`}, {COLON, ":"}, {CNUM, "1"}, {EOF, ""},
+	}
+	for i, tt := range want {
+		if tok := l.NextToken(); tok.Type != tt.typ || tok.Value != tt.value {
+			t.Fatalf("token %d: expected %s %q, got %s %q", i, tt.typ, tt.value, tok.Type, tok.Value)
+		}
+	}
+
+	// Varnish has no colon
+	l = New("a:b", "test.vcl")
+	for i, tt := range []TokenType{ID, ILLEGAL, ID, EOF} {
+		if tok := l.NextToken(); tok.Type != tt {
+			t.Fatalf("token %d: expected %s, got %s %q", i, tt, tok.Type, tok.Value)
+		}
+	}
+
+	for name, want := range map[string]Dialect{"": DialectVarnish, "varnish": DialectVarnish, "fastly": DialectFastly} {
+		if d, err := ParseDialect(name); err != nil || d != want {
+			t.Errorf("ParseDialect(%q) = %v, %v", name, d, err)
+		}
+	}
+	if _, err := ParseDialect("akamai"); err == nil {
+		t.Error("Expected an error for an unknown dialect")
+	}
+}
+
+func TestNULByte(t *testing.T) {
+	// A NUL byte is an illegal character, not the end of the input
+	l := New("a\x00b", "test.vcl")
+	want := []TokenType{ID, ILLEGAL, ID, EOF}
+	for i, tt := range want {
+		if tok := l.NextToken(); tok.Type != tt {
+			t.Fatalf("token %d: expected %s, got %s %q", i, tt, tok.Type, tok.Value)
+		}
+	}
+}
+
+func TestNewAt(t *testing.T) {
+	input := "vcl 4.1;\n\nsub vcl_recv {\n    set req.url = \"/\";\n}\n"
+
+	var full []Token
+	l := New(input, "test.vcl")
+	for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		full = append(full, tok)
+	}
+
+	// Resuming at any token yields the same tokens as scanning from the start
+	for i, start := range full {
+		l := NewAt(input, "test.vcl", start.Start)
+		for j, want := range full[i:] {
+			tok := l.NextToken()
+			if tok != want {
+				t.Fatalf("resuming at token %d: token %d = %+v, want %+v", i, i+j, tok, want)
+			}
+		}
+		if tok := l.NextToken(); tok.Type != EOF {
+			t.Fatalf("resuming at token %d: expected EOF, got %+v", i, tok)
+		}
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	inputs := []string{
+		"vcl 4.1;\r\n\n# comment\nsub vcl_recv {\n\tif (req.url ~ \"^/a\" && req.http.x != \"y\") { set req.http.n += 1; }\n}\n",
+		"/* block\n comment */ backend b { .host = \"h\"; } // trailing",
+		"synthetic({\"<p>\n\"} + \"\"\"x\"\"\");\nC{\n  int x;\n}C\n",
+		"set x = \"unterminated",
+		"  @ ",
+		"",
+	}
+	files, _ := filepath.Glob("../../tests/testdata/*.vcl")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, string(data))
+	}
+
+	for _, input := range inputs {
+		tokens := ScanAll(input, "test.vcl")
+		var text strings.Builder
+		var types []TokenType
+		for _, tok := range tokens {
+			text.WriteString(tok.Value)
+			if input[tok.Start.Offset:tok.End.Offset] != tok.Value {
+				t.Errorf("%q: token %s %q spans %q", input, tok.Type, tok.Value, input[tok.Start.Offset:tok.End.Offset])
+			}
+			if tok.Type == WHITESPACE && strings.TrimSpace(tok.Value) != "" {
+				t.Errorf("%q: whitespace token %q", input, tok.Value)
+			}
+			if tok.Type != WHITESPACE {
+				types = append(types, tok.Type)
+			}
+		}
+		if text.String() != input {
+			t.Errorf("tokens of %q concatenate to %q", input, text.String())
+		}
+		if last := tokens[len(tokens)-1]; last.Type != EOF {
+			t.Errorf("%q: last token is %s, want EOF", input, last.Type)
+		}
+
+		var want []TokenType
+		for _, tok := range New(input, "test.vcl").TokenizeAll() {
+			want = append(want, tok.Type)
+		}
+		if fmt.Sprint(types) != fmt.Sprint(want) {
+			t.Errorf("%q: token types %v, want %v", input, types, want)
+		}
+	}
+
+	tokens := ScanAll("vcl 4.1;\n  # x\n", "test.vcl")
+	comment := tokens[5]
+	if comment.Type != COMMENT || comment.Value != "# x" || comment.Start.Line != 2 || comment.Start.Column != 3 {
+		t.Errorf("comment token = %s %q at %d:%d, want COMMENT \"# x\" at 2:3",
+			comment.Type, comment.Value, comment.Start.Line, comment.Start.Column)
+	}
+}
+
+func TestRuneColumns(t *testing.T) {
+	input := "# Ærøskøbing\nset x = \"π€\" + y;"
+	tokens := New(input, "test.vcl").TokenizeAll()
+
+	tests := []struct {
+		value              string
+		column, runeColumn int
+	}{
+		{"set", 1, 1},
+		{`"π€"`, 9, 9},
+		{"+", 17, 14},
+		{"y", 19, 16},
+	}
+	for _, tt := range tests {
+		var found *Token
+		for i := range tokens {
+			if tokens[i].Value == tt.value {
+				found = &tokens[i]
+			}
+		}
+		if found == nil {
+			t.Fatalf("no token %q", tt.value)
+		}
+		if found.Start.Column != tt.column || found.Start.RuneColumn != tt.runeColumn {
+			t.Errorf("%q starts at column %d, rune column %d, want %d and %d",
+				tt.value, found.Start.Column, found.Start.RuneColumn, tt.column, tt.runeColumn)
+		}
+		if pos := NewLineIndex(input).Position(found.Start.Offset); pos != found.Start {
+			t.Errorf("LineIndex position of %q = %+v, want %+v", tt.value, pos, found.Start)
+		}
+	}
+
+	resumed := NewAt(input, "test.vcl", tokens[len(tokens)-4].Start).NextToken()
+	if resumed.Value != "+" || resumed.Start.Column != 17 || resumed.Start.RuneColumn != 14 {
+		t.Errorf("resumed token = %q at %d:%d, want \"+\" at 2:17", resumed.Value, resumed.Start.Line, resumed.Start.Column)
+	}
+}
+
+func TestLineIndexUTF16(t *testing.T) {
+	input := "a\nx = \"😀é\" b\n"
+	x := NewLineIndex(input)
+
+	tests := []struct {
+		offset          int
+		line, character int
+	}{
+		{0, 0, 0},
+		{2, 1, 0},
+		{7, 1, 5},   // the emoji, two UTF-16 units
+		{11, 1, 7},  // é
+		{15, 1, 10}, // b
+		{len(input), 2, 0},
+	}
+	for _, tt := range tests {
+		line, character := x.UTF16(tt.offset)
+		if line != tt.line || character != tt.character {
+			t.Errorf("UTF16(%d) = %d:%d, want %d:%d", tt.offset, line, character, tt.line, tt.character)
+		}
+		if offset := x.OffsetUTF16(tt.line, tt.character); offset != tt.offset {
+			t.Errorf("OffsetUTF16(%d, %d) = %d, want %d", tt.line, tt.character, offset, tt.offset)
+		}
+	}
+	if offset := x.OffsetUTF16(1, 100); offset != 16 {
+		t.Errorf("OffsetUTF16 past the end of a line = %d, want 16", offset)
+	}
+}
+
+func BenchmarkNextToken(b *testing.B) {
+	input := benchdata.Tenants(100)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := New(input, "bench.vcl")
+		for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package lexer
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// LineIndex converts between the byte offsets of a source text, Positions,
+// and the line and character pairs of the Language Server Protocol, which
+// counts characters in UTF-16 code units from 0
+type LineIndex struct {
+	text       string
+	lineStarts []int // byte offset of the start of every line
+}
+
+// NewLineIndex indexes the lines of text
+func NewLineIndex(text string) *LineIndex {
+	x := &LineIndex{text: text, lineStarts: []int{0}}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			x.lineStarts = append(x.lineStarts, i+1)
+		}
+	}
+	return x
+}
+
+// Lines returns the number of lines, counting the text after the last
+// newline as a line even when it is empty
+func (x *LineIndex) Lines() int {
+	return len(x.lineStarts)
+}
+
+// LineStart returns the byte offset of a 0-indexed line, clamped to the
+// first and last line
+func (x *LineIndex) LineStart(line int) int {
+	return x.lineStarts[max(0, min(line, len(x.lineStarts)-1))]
+}
+
+// line returns the 0-indexed line containing a byte offset
+func (x *LineIndex) line(offset int) int {
+	return sort.Search(len(x.lineStarts), func(i int) bool { return x.lineStarts[i] > offset }) - 1
+}
+
+// Position returns the position of a byte offset, clamped to the text,
+// with lines and columns numbered as the lexer numbers them
+func (x *LineIndex) Position(offset int) Position {
+	offset = max(0, min(offset, len(x.text)))
+	line := x.line(offset)
+	prefix := x.text[x.lineStarts[line]:offset]
+	return Position{
+		Line:       line + 1,
+		Column:     len(prefix) + 1,
+		RuneColumn: utf8.RuneCountInString(prefix) + 1,
+		Offset:     offset,
+	}
+}
+
+// UTF16 returns the 0-indexed line and the UTF-16 character of a byte
+// offset, as Language Server Protocol positions count them
+func (x *LineIndex) UTF16(offset int) (line, character int) {
+	offset = max(0, min(offset, len(x.text)))
+	line = x.line(offset)
+	for _, r := range x.text[x.lineStarts[line]:offset] {
+		character += utf16Len(r)
+	}
+	return line, character
+}
+
+// OffsetUTF16 returns the byte offset of a 0-indexed line and UTF-16
+// character, clamping positions past the end of a line or the text
+func (x *LineIndex) OffsetUTF16(line, character int) int {
+	if line < 0 {
+		return 0
+	}
+	if line >= len(x.lineStarts) {
+		return len(x.text)
+	}
+	offset := x.lineStarts[line]
+	for units := 0; units < character && offset < len(x.text) && x.text[offset] != '\n'; {
+		r, size := utf8.DecodeRuneInString(x.text[offset:])
+		units += utf16Len(r)
+		offset += size
+	}
+	return offset
+}
+
+// utf16Len returns the number of UTF-16 code units encoding r
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
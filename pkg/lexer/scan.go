@@ -0,0 +1,51 @@
+package lexer
+
+import "strings"
+
+// ScanAll returns every token of input, including comments and the
+// whitespace between tokens, for syntax highlighters and other tools that
+// need the source text back. The values of the tokens, which end with an
+// EOF token, concatenate to exactly input.
+//
+// Unlike NextToken, ScanAll sets each token's Value to its text in input,
+// also for ILLEGAL tokens, and End to the position just past that text, so
+// that input[tok.Start.Offset:tok.End.Offset] == tok.Value. Lines and
+// columns are numbered as NextToken numbers them.
+func ScanAll(input, filename string) []Token {
+	l := New(input, filename)
+	lines := NewLineIndex(input)
+	var tokens []Token
+	emit := func(tokenType TokenType, start, end int) {
+		tokens = append(tokens, Token{
+			Type:     tokenType,
+			Value:    input[start:end],
+			Start:    lines.Position(start),
+			End:      lines.Position(end),
+			Filename: filename,
+		})
+	}
+
+	offset := 0
+	for {
+		l.skipWhitespace()
+		start := min(l.pos, len(input))
+		if start > offset {
+			emit(WHITESPACE, offset, start)
+		}
+
+		tok := l.NextToken()
+		if tok.Type == EOF {
+			emit(EOF, start, start)
+			return tokens
+		}
+		end := min(l.pos, len(input))
+		// Some tokens take up trailing characters, such as the newline
+		// ending a # comment; leave those to the whitespace that follows
+		if text := input[start:end]; tok.Type != ILLEGAL && len(tok.Value) < len(text) &&
+			strings.HasPrefix(text, tok.Value) && strings.TrimSpace(text[len(tok.Value):]) == "" {
+			end = start + len(tok.Value)
+		}
+		emit(tok.Type, start, end)
+		offset = end
+	}
+}
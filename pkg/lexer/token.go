@@ -8,15 +8,21 @@ type TokenType int
 // Position represents a position in the source code
 type Position struct {
 	Line   int // Line number (1-indexed)
-	Column int // Column number (1-indexed)
+	Column int // Column number (1-indexed), counted in bytes
 	Offset int // Byte offset (0-indexed)
+	// RuneColumn is Column counted in characters rather than bytes, which
+	// differs on lines with multi-byte UTF-8 characters. LineIndex converts
+	// offsets to the UTF-16 columns editors use.
+	RuneColumn int
 }
 
 func (p Position) String() string {
 	return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
-// Token represents a single VCL token
+// Token represents a single VCL token. Its Value is a slice of the lexer's
+// input rather than a copy, except for the messages of some ILLEGAL tokens,
+// so lexing allocates nothing per token and tokens keep the input alive.
 type Token struct {
 	Type     TokenType
 	Value    string
@@ -116,6 +122,12 @@ const (
 	OK_KW
 	FAIL_KW
 	NEW_KW
+
+	// Token types added later are appended, so the values above stay the
+	// same
+	WHITESPACE // only returned by ScanAll
+	LSTR       // long string literal, {"..."} or """..."""
+	COLON      // : (Fastly only)
 )
 
 // String returns the string representation of a token type
@@ -127,6 +139,8 @@ func (t TokenType) String() string {
 		return "EOF"
 	case COMMENT:
 		return "COMMENT"
+	case WHITESPACE:
+		return "WHITESPACE"
 	case ID:
 		return "ID"
 	case CNUM:
@@ -135,6 +149,8 @@ func (t TokenType) String() string {
 		return "FNUM"
 	case CSTR:
 		return "CSTR"
+	case LSTR:
+		return "LSTR"
 	case CSRC:
 		return "CSRC"
 	case INC:
@@ -205,6 +221,8 @@ func (t TokenType) String() string {
 		return "~"
 	case COMMA:
 		return ","
+	case COLON:
+		return ":"
 	case VCL_KW:
 		return "vcl"
 	case BACKEND_KW:
@@ -331,7 +349,7 @@ func (t TokenType) IsKeyword() bool {
 
 // IsLiteral returns true if the token type represents a literal value
 func (t TokenType) IsLiteral() bool {
-	return t == ID || t == CNUM || t == FNUM || t == CSTR
+	return t == ID || t == CNUM || t == FNUM || t == CSTR || t == LSTR
 }
 
 // IsOperator returns true if the token type represents an operator
@@ -0,0 +1,96 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// subs returns the definitions of the subroutine name. VCL concatenates
+// repeated definitions of a built-in subroutine, so there may be several.
+func subs(program *ast.Program, name string) []*ast.SubDecl {
+	var result []*ast.SubDecl
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Name == name && sub.Body != nil {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
+// inspect calls fn for node and everything below it, skipping the children
+// of nodes for which fn returns false
+func inspect(node ast.Node, fn func(ast.Node) bool) {
+	ast.Apply(node, func(c *ast.Cursor) bool {
+		if c.Node() == nil {
+			return true
+		}
+		return fn(c.Node())
+	}, nil)
+}
+
+// find returns the nodes below the subroutines for which match is true
+func find(subs []*ast.SubDecl, match func(ast.Node) bool) []ast.Node {
+	var found []ast.Node
+	for _, sub := range subs {
+		inspect(sub.Body, func(n ast.Node) bool {
+			if match(n) {
+				found = append(found, n)
+			}
+			return true
+		})
+	}
+	return found
+}
+
+// isVariable reports whether n refers to the variable name, ignoring case
+// as header names do
+func isVariable(n ast.Node, name string) bool {
+	expr, ok := n.(ast.Expression)
+	return ok && strings.EqualFold(ast.VariableName(expr), name)
+}
+
+// mentions reports whether the variable name is referred to anywhere below n
+func mentions(n ast.Node, name string) bool {
+	found := false
+	inspect(n, func(inner ast.Node) bool {
+		if isVariable(inner, name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// assigns reports whether n sets or unsets the variable name
+func assigns(n ast.Node, name string) bool {
+	switch s := n.(type) {
+	case *ast.SetStatement:
+		return isVariable(s.Variable, name)
+	case *ast.UnsetStatement:
+		return isVariable(s.Variable, name)
+	}
+	return false
+}
+
+// returnAction returns the action of a return statement, such as "hash"
+// for return (hash), or "" if n is not a return statement
+func returnAction(n ast.Node) string {
+	ret, ok := n.(*ast.ReturnStatement)
+	if !ok {
+		return ""
+	}
+	action := ret.Action
+	if paren, ok := action.(*ast.ParenthesizedExpression); ok {
+		action = paren.Expression
+	}
+	switch a := action.(type) {
+	case *ast.Identifier:
+		return a.Name
+	case *ast.CallExpression:
+		if ident, ok := a.Function.(*ast.Identifier); ok {
+			return ident.Name
+		}
+	}
+	return ""
+}
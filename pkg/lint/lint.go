@@ -0,0 +1,204 @@
+// Package lint runs configurable rules over VCL programs. The analyzer's
+// checks take part as rules named by their diagnostic codes, such as
+// "unreferenced" or "hash-client-split", next to the rules registered with
+// Register. Each rule can be given another severity or turned off, and its
+// findings can be suppressed with comments in the VCL source:
+//
+//	set req.esi = true; // vclparser:disable deprecated
+//
+//	# vclparser:disable-next-line unreferenced
+//	backend spare { .host = "192.0.2.1"; }
+//
+//	// vclparser:disable-file hash-client-split, header-case
+//
+// A comment without rule IDs suppresses every rule.
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Rule is a lint check. It is the analyzer's plugin rule, so rules written
+// for a plugin can be registered here as well.
+type Rule = analyzer.Rule
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{}
+)
+
+// Register makes a rule part of every Linter. Registering two rules with the
+// same ID is an error.
+func Register(rule Rule) error {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	id := rule.ID()
+	if id == "" {
+		return fmt.Errorf("rule has no ID")
+	}
+	if _, exists := rules[id]; exists {
+		return fmt.Errorf("rule '%s' is already registered", id)
+	}
+	rules[id] = rule
+	return nil
+}
+
+// Unregister removes a previously registered rule
+func Unregister(id string) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	delete(rules, id)
+}
+
+// Rules returns the registered rules sorted by ID
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	result := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
+
+// analyzerChecks are the analyzer checks that have their own level setting,
+// as some of them are off by default. Setting the level of one of these
+// rules passes it on to the analyzer.
+var analyzerChecks = map[string]func(a *analyzer.Analyzer, level string) error{
+	"unreferenced": func(a *analyzer.Analyzer, level string) error {
+		if level == "info" {
+			// The analyzer reports unreferenced declarations as warnings
+			// at least; Lint lowers them
+			level = "warning"
+		}
+		return a.SetUnreferenced(level)
+	},
+	"fallthrough":        (*analyzer.Analyzer).SetFallthrough,
+	"regex-backtracking": (*analyzer.Analyzer).SetBacktracking,
+	"deprecated":         (*analyzer.Analyzer).SetDeprecations,
+	"backend-audit":      (*analyzer.Analyzer).SetBackendAudit,
+}
+
+// level is the configured reporting of a rule
+type level struct {
+	off      bool
+	severity analyzer.Severity
+}
+
+// parseLevel parses "off", "info", "warning" or "error"
+func parseLevel(id, s string) (level, error) {
+	switch s {
+	case "off":
+		return level{off: true}, nil
+	case "info":
+		return level{severity: analyzer.SeverityInfo}, nil
+	case "warning":
+		return level{severity: analyzer.SeverityWarning}, nil
+	case "error":
+		return level{severity: analyzer.SeverityError}, nil
+	}
+	return level{}, fmt.Errorf("unknown level %q for rule %s, expected info, warning, error or off", s, id)
+}
+
+// Linter runs an analyzer together with the registered rules and applies
+// rule levels and suppression comments to their findings
+type Linter struct {
+	analyzer *analyzer.Analyzer
+	levels   map[string]level
+}
+
+// New creates a linter running the checks of a, which keeps its own
+// settings such as the target Varnish release
+func New(a *analyzer.Analyzer) *Linter {
+	return &Linter{
+		analyzer: a,
+		levels:   make(map[string]level),
+	}
+}
+
+// SetLevel sets how the findings of a rule, or of analyzer findings with
+// that code, are reported: "info", "warning", "error" or "off"
+func (l *Linter) SetLevel(id, s string) error {
+	lvl, err := parseLevel(id, s)
+	if err != nil {
+		return err
+	}
+	if set, ok := analyzerChecks[id]; ok {
+		if err := set(l.analyzer, s); err != nil {
+			return err
+		}
+	}
+	l.levels[id] = lvl
+	return nil
+}
+
+// Configure sets the levels of several rules, keyed by rule ID
+func (l *Linter) Configure(levels map[string]string) error {
+	ids := make([]string, 0, len(levels))
+	for id := range levels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := l.SetLevel(id, levels[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetSourceLocator makes the linter attribute findings to the included file
+// they were found in, as analyzer.Analyzer.SetSourceLocator does
+func (l *Linter) SetSourceLocator(locator analyzer.SourceLocator) {
+	l.analyzer.SetSourceLocator(locator)
+}
+
+// Lint analyzes program, runs the registered rules over it and returns the
+// findings that are neither turned off nor suppressed by comments in source.
+// Findings without a file name are attributed to filename; suppression
+// comments only apply to findings in that file.
+func (l *Linter) Lint(program *ast.Program, filename, source string) []analyzer.Diagnostic {
+	diags, _ := l.LintContext(context.Background(), program, filename, source)
+	return diags
+}
+
+// LintContext is Lint that stops running checks once ctx is done. It then
+// returns the findings of the checks that ran together with ctx.Err().
+func (l *Linter) LintContext(ctx context.Context, program *ast.Program, filename, source string) ([]analyzer.Diagnostic, error) {
+	diags, err := l.analyzer.AnalyzeDiagnosticsContext(ctx, program)
+	if err == nil {
+		var ruleDiags []analyzer.Diagnostic
+		ruleDiags, err = l.analyzer.RunRulesContext(ctx, program, Rules())
+		diags = append(diags, ruleDiags...)
+	}
+
+	suppressions := ParseSuppressions(source)
+	result := diags[:0]
+	for _, diag := range diags {
+		if diag.Filename == "" {
+			diag.Filename = filename
+		}
+		if lvl, ok := l.levels[diag.Code]; ok {
+			if lvl.off {
+				continue
+			}
+			diag.Severity = lvl.severity
+		}
+		if diag.Filename == filename && suppressions.Suppressed(diag.Code, diag.Position.Line) {
+			continue
+		}
+		result = append(result, diag)
+	}
+	return result, err
+}
@@ -0,0 +1,155 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// debugSubRule flags subroutines named "debug"
+type debugSubRule struct{}
+
+func (debugSubRule) ID() string          { return "test/debug-sub" }
+func (debugSubRule) Description() string { return "flags subroutines named debug" }
+
+func (debugSubRule) Check(program *ast.Program, ctx *analyzer.Context) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok && sub.Name == "debug" {
+			diags = append(diags, analyzer.Diagnostic{
+				Position: sub.Start(),
+				Severity: analyzer.SeverityWarning,
+				Message:  "debug subroutine left in",
+			})
+		}
+	}
+	return diags
+}
+
+func registerTestRule(t *testing.T, rule Rule) {
+	t.Helper()
+	if err := Register(rule); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	t.Cleanup(func() { Unregister(rule.ID()) })
+}
+
+const lintVCL = `vcl 4.1;
+
+backend default { .host = "192.0.2.0"; }
+backend spare { .host = "192.0.2.1"; }
+
+sub debug {
+}
+
+sub vcl_recv {
+	call debug;
+	return (hash);
+}
+`
+
+func lintCodes(t *testing.T, source string, levels map[string]string) map[string]analyzer.Severity {
+	t.Helper()
+	program, err := parser.Parse(source, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	linter := New(analyzer.NewAnalyzer(vmod.NewRegistry()))
+	if err := linter.Configure(levels); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	codes := make(map[string]analyzer.Severity)
+	for _, diag := range linter.Lint(program, "test.vcl", source) {
+		if diag.Filename != "test.vcl" {
+			t.Errorf("diagnostic %q has file name %q", diag.Message, diag.Filename)
+		}
+		codes[diag.Code] = diag.Severity
+	}
+	return codes
+}
+
+func TestLintRules(t *testing.T) {
+	registerTestRule(t, debugSubRule{})
+
+	codes := lintCodes(t, lintVCL, nil)
+	if codes["test/debug-sub"] != analyzer.SeverityWarning {
+		t.Errorf("expected the registered rule to report a warning, got %v", codes)
+	}
+	if codes["unreferenced"] != analyzer.SeverityWarning {
+		t.Errorf("expected the analyzer's unreferenced warning, got %v", codes)
+	}
+
+	codes = lintCodes(t, lintVCL, map[string]string{
+		"test/debug-sub": "error",
+		"unreferenced":   "off",
+	})
+	if codes["test/debug-sub"] != analyzer.SeverityError {
+		t.Errorf("expected the rule to be raised to an error, got %v", codes)
+	}
+	if _, ok := codes["unreferenced"]; ok {
+		t.Errorf("expected unreferenced to be off, got %v", codes)
+	}
+}
+
+func TestLintAnalyzerChecks(t *testing.T) {
+	source := `vcl 4.1;
+sub vcl_recv {
+	if (req.url ~ "^/admin") {
+		return (pass);
+	}
+}
+`
+	if _, ok := lintCodes(t, source, nil)["fallthrough"]; ok {
+		t.Error("fallthrough is off by default")
+	}
+	if codes := lintCodes(t, source, map[string]string{"fallthrough": "info"}); codes["fallthrough"] != analyzer.SeverityInfo {
+		t.Errorf("expected fallthrough as info, got %v", codes)
+	}
+}
+
+func TestLintSuppressions(t *testing.T) {
+	registerTestRule(t, debugSubRule{})
+
+	source := `vcl 4.1;
+
+backend default { .host = "192.0.2.0"; }
+backend spare { .host = "192.0.2.1"; } # vclparser:disable unreferenced
+
+// vclparser:disable test/debug-sub
+sub debug {
+}
+
+sub vcl_recv {
+	call debug;
+	return (hash);
+}
+`
+	codes := lintCodes(t, source, nil)
+	if _, ok := codes["unreferenced"]; ok {
+		t.Errorf("expected unreferenced to be suppressed on its line, got %v", codes)
+	}
+	if _, ok := codes["test/debug-sub"]; ok {
+		t.Errorf("expected the rule to be suppressed on the next line, got %v", codes)
+	}
+
+	if codes := lintCodes(t, "/* vclparser:disable-file */\n"+lintVCL, nil); len(codes) != 0 {
+		t.Errorf("expected every finding to be suppressed, got %v", codes)
+	}
+}
+
+func TestLintConfigureErrors(t *testing.T) {
+	linter := New(analyzer.NewAnalyzer(vmod.NewRegistry()))
+	if err := linter.SetLevel("unreferenced", "loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if err := Register(debugSubRule{}); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister(debugSubRule{}.ID())
+	if err := Register(debugSubRule{}); err == nil {
+		t.Error("expected an error registering a rule twice")
+	}
+}
@@ -0,0 +1,225 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// BestPractices returns the rules for common VCL mistakes that every Linter
+// runs. They can be turned off individually like any other rule.
+func BestPractices() []Rule {
+	return []Rule{
+		hostNormalizationRule{},
+		cookieHashRule{},
+		errorTTLRule{},
+		hashFallthroughRule{},
+		ipLiteralRule{},
+	}
+}
+
+func init() {
+	for _, rule := range BestPractices() {
+		if err := Register(rule); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// hostNormalizationRule reports VCL that handles requests without ever
+// setting req.http.host. The built-in vcl_hash hashes the Host header, so
+// Example.com, example.com and example.com:80 are cached separately.
+type hostNormalizationRule struct{}
+
+func (hostNormalizationRule) ID() string { return "host-normalization" }
+func (hostNormalizationRule) Description() string {
+	return "vcl_recv should normalize the Host header before it becomes part of the hash"
+}
+
+func (r hostNormalizationRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	recv := subs(program, "vcl_recv")
+	if len(recv) == 0 {
+		return nil
+	}
+	// Normalization may happen in a custom subroutine called from vcl_recv
+	for _, decl := range program.Declarations {
+		if mentionsAssignment(decl, "req.http.host") {
+			return nil
+		}
+	}
+	return []analyzer.Diagnostic{analyzer.NewDiagnostic(recv[0], analyzer.SeverityInfo, r.ID(),
+		"req.http.host is never normalized, so variants such as Example.com and example.com:80 are cached separately; lowercase it and strip the default port in vcl_recv")}
+}
+
+// mentionsAssignment reports whether n contains a set or unset of name
+func mentionsAssignment(n ast.Node, name string) bool {
+	found := false
+	inspect(n, func(inner ast.Node) bool {
+		if assigns(inner, name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// cookieHashRule reports a vcl_recv that looks at cookies and then caches
+// the request with return (hash) while vcl_hash ignores the Cookie header.
+// The built-in vcl_recv passes requests with cookies, so returning hash
+// shares responses that depend on the cookie between users.
+type cookieHashRule struct{}
+
+func (cookieHashRule) ID() string { return "cookie-not-hashed" }
+func (cookieHashRule) Description() string {
+	return "requests handled by cookie in vcl_recv are cached without the cookie in the hash"
+}
+
+func (r cookieHashRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	recv := subs(program, "vcl_recv")
+	usesCookie, dropsCookie := false, false
+	for _, sub := range recv {
+		usesCookie = usesCookie || mentions(sub.Body, "req.http.cookie")
+		inspect(sub.Body, func(n ast.Node) bool {
+			if unset, ok := n.(*ast.UnsetStatement); ok && isVariable(unset.Variable, "req.http.cookie") {
+				dropsCookie = true
+			}
+			return true
+		})
+	}
+	if !usesCookie || dropsCookie {
+		return nil
+	}
+	hashed := find(subs(program, "vcl_hash"), func(n ast.Node) bool {
+		call := analyzer.HashDataCall(n)
+		return call != nil && mentions(call, "req.http.cookie")
+	})
+	if len(hashed) > 0 {
+		return nil
+	}
+
+	lookups := find(recv, func(n ast.Node) bool { return returnAction(n) == "hash" })
+	if len(lookups) == 0 {
+		return nil
+	}
+	return []analyzer.Diagnostic{analyzer.NewDiagnostic(lookups[0], analyzer.SeverityWarning, r.ID(),
+		"vcl_recv handles req.http.Cookie and returns (hash), but vcl_hash does not hash the cookie: responses that depend on it are shared between users; unset the header or hash_data() the relevant cookie")}
+}
+
+// errorTTLRule reports beresp.ttl set in vcl_backend_response without any
+// look at beresp.status. Varnish does not cache most error responses by
+// default, but an explicit TTL caches 5xx responses as well.
+type errorTTLRule struct{}
+
+func (errorTTLRule) ID() string { return "ttl-on-errors" }
+func (errorTTLRule) Description() string {
+	return "beresp.ttl should not be set for 5xx responses"
+}
+
+func (r errorTTLRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	response := subs(program, "vcl_backend_response")
+	checked := find(response, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStatement)
+		return ok && mentions(ifStmt.Condition, "beresp.status")
+	})
+	if len(checked) > 0 {
+		return nil
+	}
+
+	var diags []analyzer.Diagnostic
+	for _, set := range find(response, func(n ast.Node) bool {
+		set, ok := n.(*ast.SetStatement)
+		return ok && isVariable(set.Variable, "beresp.ttl")
+	}) {
+		diags = append(diags, analyzer.NewDiagnostic(set, analyzer.SeverityWarning, r.ID(),
+			"beresp.ttl is set without checking beresp.status, so 5xx responses are cached too; set a short TTL or beresp.uncacheable for errors"))
+	}
+	return diags
+}
+
+// hashFallthroughRule reports a vcl_hash that hashes data but never
+// returns. The built-in vcl_hash then runs as well and adds req.url and the
+// Host header, which is easy to overlook.
+type hashFallthroughRule struct{}
+
+func (hashFallthroughRule) ID() string { return "hash-without-lookup" }
+func (hashFallthroughRule) Description() string {
+	return "vcl_hash without return (lookup) also hashes what the built-in vcl_hash does"
+}
+
+func (r hashFallthroughRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	hash := subs(program, "vcl_hash")
+	calls := find(hash, func(n ast.Node) bool { return analyzer.HashDataCall(n) != nil })
+	returns := find(hash, func(n ast.Node) bool {
+		_, ok := n.(*ast.ReturnStatement)
+		return ok
+	})
+	if len(calls) == 0 || len(returns) > 0 {
+		return nil
+	}
+	return []analyzer.Diagnostic{analyzer.NewDiagnostic(hash[0], analyzer.SeverityInfo, r.ID(),
+		"vcl_hash falls through to the built-in vcl_hash, which also hashes req.url and the Host header or server.ip; end it with return (lookup) to hash only the data given here")}
+}
+
+// ipVariables hold addresses that should be matched against ACLs
+var ipVariables = map[string]bool{
+	"client.ip": true,
+	"remote.ip": true,
+	"local.ip":  true,
+	"server.ip": true,
+}
+
+// ipHeaders carry client addresses as strings, often matched with regular
+// expressions instead of an ACL and std.ip()
+var ipHeaders = map[string]bool{
+	"req.http.x-forwarded-for": true,
+	"req.http.x-real-ip":       true,
+	"req.http.true-client-ip":  true,
+}
+
+// addressLiteral matches literals that look like IPv4 addresses or
+// patterns for them
+var addressLiteral = regexp.MustCompile(`^\^?\d{1,3}\\?\.`)
+
+// ipLiteralRule reports addresses written into comparisons instead of
+// ACLs, which are easier to maintain and also match networks
+type ipLiteralRule struct{}
+
+func (ipLiteralRule) ID() string { return "ip-literal" }
+func (ipLiteralRule) Description() string {
+	return "client addresses should be matched with ACLs, not literals"
+}
+
+func (r ipLiteralRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	inspect(program, func(n ast.Node) bool {
+		var left, right ast.Expression
+		switch e := n.(type) {
+		case *ast.BinaryExpression:
+			if e.Operator != "==" && e.Operator != "!=" {
+				return true
+			}
+			left, right = e.Left, e.Right
+		case *ast.RegexMatchExpression:
+			left, right = e.Left, e.Right
+		default:
+			return true
+		}
+		if _, ok := left.(*ast.StringLiteral); ok {
+			left, right = right, left
+		}
+		lit, ok := right.(*ast.StringLiteral)
+		if !ok {
+			return true
+		}
+		name := strings.ToLower(ast.VariableName(left))
+		if ipVariables[name] || ipHeaders[name] && addressLiteral.MatchString(lit.Value) {
+			diags = append(diags, analyzer.NewDiagnostic(n, analyzer.SeverityWarning, r.ID(), fmt.Sprintf(
+				"%s is compared with the address %q; list addresses in an ACL and match with ~ instead", ast.VariableName(left), lit.Value)))
+		}
+		return true
+	})
+	return diags
+}
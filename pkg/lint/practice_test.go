@@ -0,0 +1,115 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestBestPractices(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		vclCode  string
+		expected []string
+	}{
+		{
+			name: "host normalized",
+			rule: hostNormalizationRule{},
+			vclCode: `vcl 4.1;
+				sub normalize { set req.http.host = regsub(req.http.host, ":80$", ""); }
+				sub vcl_recv { call normalize; }`,
+		},
+		{
+			name:     "host not normalized",
+			rule:     hostNormalizationRule{},
+			vclCode:  `vcl 4.1; sub vcl_recv { set req.http.x-ok = "1"; }`,
+			expected: []string{"req.http.host is never normalized"},
+		},
+		{
+			name: "cookie checked and hashed",
+			rule: cookieHashRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv { if (req.http.Cookie ~ "lang=") { return (hash); } }
+				sub vcl_hash { hash_data(req.url); hash_data(req.http.Cookie); }`,
+		},
+		{
+			name: "cookie checked and removed",
+			rule: cookieHashRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv { if (req.http.Cookie ~ "session=") { return (pass); } unset req.http.Cookie; return (hash); }`,
+		},
+		{
+			name: "cookie checked, not hashed",
+			rule: cookieHashRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv { if (req.http.Cookie ~ "lang=") { return (hash); } }`,
+			expected: []string{"vcl_hash does not hash the cookie"},
+		},
+		{
+			name: "ttl set for good responses",
+			rule: errorTTLRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { if (beresp.status >= 500) { set beresp.ttl = 1s; return (deliver); } set beresp.ttl = 1h; }`,
+		},
+		{
+			name: "ttl set regardless of status",
+			rule: errorTTLRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.ttl = 1h; }`,
+			expected: []string{"without checking beresp.status"},
+		},
+		{
+			name: "vcl_hash returns lookup",
+			rule: hashFallthroughRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_hash { hash_data(req.url); return (lookup); }`,
+		},
+		{
+			name: "vcl_hash falls through",
+			rule: hashFallthroughRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_hash { hash_data(req.http.X-Country); }`,
+			expected: []string{"falls through to the built-in vcl_hash"},
+		},
+		{
+			name: "ACL match",
+			rule: ipLiteralRule{},
+			vclCode: `vcl 4.1;
+				acl office { "192.0.2.0"/24; }
+				sub vcl_recv { if (client.ip ~ office || req.http.X-Forwarded-For ~ "bot") { return (pass); } }`,
+		},
+		{
+			name: "magic addresses",
+			rule: ipLiteralRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv {
+					if (client.ip == "192.0.2.10" || req.http.X-Forwarded-For ~ "^10\.") { return (pass); }
+				}`,
+			expected: []string{`client.ip is compared with the address "192.0.2.10"`, "req.http.X-Forwarded-For"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := test.rule.Check(program, nil)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.rule.ID() {
+					t.Errorf("diagnostic %d code = %q, want %q", i, diags[i].Code, test.rule.ID())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Security returns the rules for VCL patterns that open the cache to abuse,
+// such as cache poisoning, reflected content and unrestricted purging.
+// Every Linter runs them.
+func Security() []Rule {
+	return []Rule{
+		setCookieRule{},
+		hashHeaderRule{},
+		syntheticReflectionRule{},
+		purgeACLRule{},
+		forwardedForRule{},
+	}
+}
+
+func init() {
+	for _, rule := range Security() {
+		if err := Register(rule); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// setCookieRule reports a vcl_backend_response that delivers without ever
+// looking at Set-Cookie. return (deliver) skips the built-in
+// vcl_backend_response, which makes responses setting cookies uncacheable,
+// so one client's session cookie would be served to everyone.
+type setCookieRule struct{}
+
+func (setCookieRule) ID() string { return "cache-set-cookie" }
+func (setCookieRule) Description() string {
+	return "responses with Set-Cookie should not be cached"
+}
+
+func (r setCookieRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	response := subs(program, "vcl_backend_response")
+	for _, sub := range response {
+		if mentions(sub.Body, "beresp.http.set-cookie") || mentionsAssignment(sub.Body, "beresp.uncacheable") {
+			return nil
+		}
+	}
+	delivers := find(response, func(n ast.Node) bool { return returnAction(n) == "deliver" })
+	if len(delivers) == 0 {
+		return nil
+	}
+	return []analyzer.Diagnostic{analyzer.NewDiagnostic(delivers[0], analyzer.SeverityWarning, r.ID(),
+		"return (deliver) skips the built-in vcl_backend_response, which keeps responses with Set-Cookie out of the cache; check beresp.http.Set-Cookie or one client's cookies are served to everyone")}
+}
+
+// clientSplitHeaders are hashed headers the analyzer already reports as
+// splitting the cache per client
+var clientSplitHeaders = map[string]bool{
+	"req.http.host":            true,
+	"req.http.cookie":          true,
+	"req.http.user-agent":      true,
+	"req.http.x-forwarded-for": true,
+}
+
+// hashHeaderRule reports request headers hashed in vcl_hash that the VCL
+// neither sets nor looks at. Clients choose their value freely, so they
+// can create any number of cache objects for a URL, or a poisoned variant
+// other requests carrying the same header get.
+type hashHeaderRule struct{}
+
+func (hashHeaderRule) ID() string { return "hash-unvalidated-header" }
+func (hashHeaderRule) Description() string {
+	return "request headers should be validated or normalized before they are hashed"
+}
+
+func (r hashHeaderRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	for _, n := range find(subs(program, "vcl_hash"), func(n ast.Node) bool { return analyzer.HashDataCall(n) != nil }) {
+		inspect(n, func(inner ast.Node) bool {
+			expr, ok := inner.(ast.Expression)
+			if !ok {
+				return true
+			}
+			name := ast.VariableName(expr)
+			lower := strings.ToLower(name)
+			if !strings.HasPrefix(lower, "req.http.") || clientSplitHeaders[lower] {
+				return name == ""
+			}
+			if !validated(program, name) {
+				diags = append(diags, analyzer.NewDiagnostic(expr, analyzer.SeverityWarning, r.ID(), fmt.Sprintf(
+					"%s is hashed as sent by the client and never validated or normalized; clients can create arbitrary cache variants with it", name)))
+			}
+			return false
+		})
+	}
+	return diags
+}
+
+// validated reports whether the VCL sets or unsets the variable name, or
+// tests it in a condition
+func validated(program *ast.Program, name string) bool {
+	found := false
+	inspect(program, func(n ast.Node) bool {
+		if assigns(n, name) {
+			found = true
+		}
+		if ifStmt, ok := n.(*ast.IfStatement); ok && mentions(ifStmt.Condition, name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// syntheticReflectionRule reports request data copied into synthetic
+// response bodies as is. A URL or header containing markup is reflected to
+// the browser, allowing cross-site scripting on the cached domain.
+type syntheticReflectionRule struct{}
+
+func (syntheticReflectionRule) ID() string { return "synthetic-reflection" }
+func (syntheticReflectionRule) Description() string {
+	return "request data should be escaped before it is written into synthetic bodies"
+}
+
+func (r syntheticReflectionRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	inspect(program, func(n ast.Node) bool {
+		var body ast.Expression
+		switch s := n.(type) {
+		case *ast.SyntheticStatement:
+			body = s.Response
+		case *ast.SetStatement:
+			if isVariable(s.Variable, "resp.body") || isVariable(s.Variable, "beresp.body") {
+				body = s.Value
+			}
+		}
+		if body == nil {
+			return true
+		}
+		if name := reflectedRequestData(body); name != "" {
+			diags = append(diags, analyzer.NewDiagnostic(n, analyzer.SeverityWarning, r.ID(), fmt.Sprintf(
+				"%s is written into the response body unescaped, so markup in it reaches the browser; escape it or leave it out", name)))
+		}
+		return false
+	})
+	return diags
+}
+
+// reflectedRequestData returns the first request URL or header used in
+// expr outside a function call. Calls are assumed to escape their
+// arguments.
+func reflectedRequestData(expr ast.Expression) string {
+	reflected := ""
+	inspect(expr, func(n ast.Node) bool {
+		if reflected != "" {
+			return false
+		}
+		if _, ok := n.(*ast.CallExpression); ok {
+			return false
+		}
+		e, ok := n.(ast.Expression)
+		if !ok {
+			return true
+		}
+		name := ast.VariableName(e)
+		switch lower := strings.ToLower(name); {
+		case lower == "req.url", lower == "bereq.url",
+			strings.HasPrefix(lower, "req.http."), strings.HasPrefix(lower, "bereq.http."):
+			reflected = name
+		}
+		return name == ""
+	})
+	return reflected
+}
+
+// purgeACLRule reports return (purge) in vcl_recv that is not guarded by an
+// ACL match, letting anyone on the internet purge the cache
+type purgeACLRule struct{}
+
+func (purgeACLRule) ID() string { return "purge-without-acl" }
+func (purgeACLRule) Description() string {
+	return "purging should be restricted to clients matched by an ACL"
+}
+
+func (r purgeACLRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	acls := make(map[string]bool)
+	for _, decl := range program.Declarations {
+		if acl, ok := decl.(*ast.ACLDecl); ok {
+			acls[acl.Name] = true
+		}
+	}
+
+	var diags []analyzer.Diagnostic
+	var walk func(stmts []ast.Statement, guarded bool)
+	walkOne := func(stmt ast.Statement, guarded bool) {
+		if block, ok := stmt.(*ast.BlockStatement); ok {
+			walk(block.Statements, guarded)
+		} else if stmt != nil {
+			walk([]ast.Statement{stmt}, guarded)
+		}
+	}
+	walk = func(stmts []ast.Statement, guarded bool) {
+		for _, stmt := range stmts {
+			switch s := stmt.(type) {
+			case *ast.IfStatement:
+				matches := matchesACL(s.Condition, acls)
+				walkOne(s.Then, guarded || matches)
+				walkOne(s.Else, guarded || matches)
+				// A guard like if (client.ip !~ purgers) { return (synth(405)); }
+				// protects the statements that follow it
+				guarded = guarded || matches
+			case *ast.BlockStatement:
+				walk(s.Statements, guarded)
+			case *ast.ReturnStatement:
+				if !guarded && returnAction(s) == "purge" {
+					diags = append(diags, analyzer.NewDiagnostic(s, analyzer.SeverityWarning, r.ID(),
+						"return (purge) is not restricted by an ACL, so any client can purge the cache; match client.ip against an ACL first"))
+				}
+			}
+		}
+	}
+	for _, sub := range subs(program, "vcl_recv") {
+		walk(sub.Body.Statements, false)
+	}
+	return diags
+}
+
+// matchesACL reports whether expr matches something against one of acls
+func matchesACL(expr ast.Expression, acls map[string]bool) bool {
+	found := false
+	inspect(expr, func(n ast.Node) bool {
+		if m, ok := n.(*ast.RegexMatchExpression); ok {
+			if ident, ok := m.Right.(*ast.Identifier); ok && acls[ident.Name] {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// forwardedForRule reports decisions based on the value of headers like
+// X-Forwarded-For. Clients can send any value, so unless a trusted proxy
+// in front of Varnish overwrites them, matching on them is spoofable.
+type forwardedForRule struct{}
+
+func (forwardedForRule) ID() string { return "xff-trust" }
+func (forwardedForRule) Description() string {
+	return "client-supplied address headers should not be trusted for access decisions"
+}
+
+func (r forwardedForRule) Check(program *ast.Program, _ *analyzer.Context) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	inspect(program, func(n ast.Node) bool {
+		ifStmt, ok := n.(*ast.IfStatement)
+		if !ok {
+			return true
+		}
+		if header := comparedAddressHeader(ifStmt.Condition); header != "" {
+			diags = append(diags, analyzer.NewDiagnostic(ifStmt.Condition, analyzer.SeverityWarning, r.ID(), fmt.Sprintf(
+				"%s is sent by the client and can be spoofed; base the decision on client.ip, or on the header only when a trusted proxy sets it (PROXY protocol)", header)))
+		}
+		return true
+	})
+	return diags
+}
+
+// comparedAddressHeader returns the first address header whose value is
+// compared or matched in cond. Testing whether the header is present, as
+// when appending to it, is fine.
+func comparedAddressHeader(cond ast.Expression) string {
+	header := ""
+	check := func(operands ...ast.Expression) {
+		for _, operand := range operands {
+			inspect(operand, func(n ast.Node) bool {
+				if e, ok := n.(ast.Expression); ok && header == "" && ipHeaders[strings.ToLower(ast.VariableName(e))] {
+					header = ast.VariableName(e)
+				}
+				return header == ""
+			})
+		}
+	}
+	inspect(cond, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.BinaryExpression:
+			if e.Operator == "==" || e.Operator == "!=" {
+				check(e.Left, e.Right)
+			}
+		case *ast.RegexMatchExpression:
+			check(e.Left, e.Right)
+		}
+		return header == ""
+	})
+	return header
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestSecurityRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		vclCode  string
+		expected []string
+	}{
+		{
+			name: "deliver after checking Set-Cookie",
+			rule: setCookieRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { if (beresp.http.Set-Cookie) { set beresp.uncacheable = true; } return (deliver); }`,
+		},
+		{
+			name: "falling through to the built-in",
+			rule: setCookieRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.grace = 1h; }`,
+		},
+		{
+			name: "deliver ignoring Set-Cookie",
+			rule: setCookieRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_backend_response { set beresp.ttl = 1h; return (deliver); }`,
+			expected: []string{"keeps responses with Set-Cookie out of the cache"},
+		},
+		{
+			name: "hashing a normalized header",
+			rule: hashHeaderRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv { if (req.http.X-Lang !~ "^(en|de)$") { unset req.http.X-Lang; } }
+				sub vcl_hash { hash_data(req.url); hash_data(req.http.X-Lang); }`,
+		},
+		{
+			name: "hashing a raw header",
+			rule: hashHeaderRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_hash { hash_data(req.url); hash_data(req.http.Host); hash_data(req.http.X-Forwarded-Host); }`,
+			expected: []string{"req.http.X-Forwarded-Host is hashed as sent by the client"},
+		},
+		{
+			name: "escaped request data",
+			rule: syntheticReflectionRule{},
+			vclCode: `vcl 4.1;
+				import std;
+				sub vcl_synth { synthetic("Not found: " + std.tolower(req.url)); return (deliver); }`,
+		},
+		{
+			name: "reflected request data",
+			rule: syntheticReflectionRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_synth { synthetic("<h1>Not found: " + req.url + "</h1>"); return (deliver); }
+				sub vcl_backend_error { set beresp.body = "Error for " + bereq.http.Host; return (deliver); }`,
+			expected: []string{"req.url is written into the response body unescaped", "bereq.http.Host"},
+		},
+		{
+			name: "purge behind an ACL guard",
+			rule: purgeACLRule{},
+			vclCode: `vcl 4.1;
+				acl purgers { "127.0.0.1"; }
+				sub vcl_recv {
+					if (req.method == "PURGE") {
+						if (!(client.ip ~ purgers)) { return (synth(405)); }
+						return (purge);
+					}
+				}`,
+		},
+		{
+			name: "purge inside an ACL condition",
+			rule: purgeACLRule{},
+			vclCode: `vcl 4.1;
+				acl purgers { "127.0.0.1"; }
+				sub vcl_recv { if (req.method == "PURGE" && client.ip ~ purgers) { return (purge); } }`,
+		},
+		{
+			name: "open purge",
+			rule: purgeACLRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv { if (req.method == "PURGE") { return (purge); } }`,
+			expected: []string{"return (purge) is not restricted by an ACL"},
+		},
+		{
+			name: "appending to X-Forwarded-For",
+			rule: forwardedForRule{},
+			vclCode: `vcl 4.1;
+				sub vcl_recv { if (req.http.X-Forwarded-For) { set req.http.X-Forwarded-For = req.http.X-Forwarded-For + ", " + client.ip; } }`,
+		},
+		{
+			name: "trusting X-Forwarded-For",
+			rule: forwardedForRule{},
+			vclCode: `vcl 4.1;
+				import std;
+				acl office { "192.0.2.0"/24; }
+				sub vcl_recv { if (std.ip(req.http.X-Forwarded-For, "0.0.0.0") ~ office) { return (pass); } }`,
+			expected: []string{"req.http.X-Forwarded-For is sent by the client and can be spoofed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+
+			diags := test.rule.Check(program, nil)
+			if len(diags) != len(test.expected) {
+				t.Fatalf("Expected %d diagnostics, got %d: %v", len(test.expected), len(diags), diags)
+			}
+			for i, fragment := range test.expected {
+				if !strings.Contains(diags[i].Message, fragment) {
+					t.Errorf("diagnostic %d = %q, want it to contain %q", i, diags[i].Message, fragment)
+				}
+				if diags[i].Code != test.rule.ID() {
+					t.Errorf("diagnostic %d code = %q, want %q", i, diags[i].Code, test.rule.ID())
+				}
+			}
+		})
+	}
+}
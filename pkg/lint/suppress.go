@@ -0,0 +1,81 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// suppressComment matches a suppression in a //, # or /* comment, capturing
+// its kind and the rule IDs following it
+var suppressComment = regexp.MustCompile(`(//|#|/\*)\s*vclparser:(disable-file|disable-next-line|disable)\b([^\n]*)`)
+
+// allRules stands for every rule in a suppression without rule IDs
+const allRules = "*"
+
+// Suppressions records the rules disabled by comments in a VCL source
+type Suppressions struct {
+	file  map[string]bool
+	lines map[int]map[string]bool
+}
+
+// ParseSuppressions finds the suppression comments in source:
+// "vclparser:disable" applies to its own line, or to the line below when
+// the comment is alone on its line, "vclparser:disable-next-line" to the
+// line below and "vclparser:disable-file" to the whole file. The rule IDs
+// follow, separated by commas or spaces.
+func ParseSuppressions(source string) *Suppressions {
+	s := &Suppressions{
+		file:  make(map[string]bool),
+		lines: make(map[int]map[string]bool),
+	}
+	for i, text := range strings.Split(source, "\n") {
+		m := suppressComment.FindStringSubmatchIndex(text)
+		if m == nil {
+			continue
+		}
+		kind := text[m[4]:m[5]]
+		ids := suppressedRules(text[m[6]:m[7]])
+
+		line := i + 1
+		switch {
+		case kind == "disable-file":
+			for _, id := range ids {
+				s.file[id] = true
+			}
+			continue
+		case kind == "disable-next-line", strings.TrimSpace(text[:m[0]]) == "":
+			line++
+		}
+		if s.lines[line] == nil {
+			s.lines[line] = make(map[string]bool)
+		}
+		for _, id := range ids {
+			s.lines[line][id] = true
+		}
+	}
+	return s
+}
+
+// suppressedRules splits the rule IDs of a suppression comment, returning
+// allRules when there are none
+func suppressedRules(text string) []string {
+	if end := strings.Index(text, "*/"); end >= 0 {
+		text = text[:end]
+	}
+	ids := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\r'
+	})
+	if len(ids) == 0 {
+		return []string{allRules}
+	}
+	return ids
+}
+
+// Suppressed reports whether findings of rule id on line are suppressed
+func (s *Suppressions) Suppressed(id string, line int) bool {
+	if s.file[id] || s.file[allRules] {
+		return true
+	}
+	disabled := s.lines[line]
+	return disabled[id] || disabled[allRules]
+}
@@ -0,0 +1,33 @@
+package lint
+
+import "testing"
+
+func TestParseSuppressions(t *testing.T) {
+	source := `vcl 4.1; // vclparser:disable deprecated
+# vclparser:disable-next-line hash-client-split, header-case
+sub vcl_hash {
+	hash_data(req.http.Cookie); /* vclparser:disable */
+	// vclparser:disable-file unreferenced
+}
+`
+	s := ParseSuppressions(source)
+	tests := []struct {
+		id         string
+		line       int
+		suppressed bool
+	}{
+		{"deprecated", 1, true},
+		{"deprecated", 2, false},
+		{"hash-client-split", 3, true},
+		{"header-case", 3, true},
+		{"hash-client-split", 2, false},
+		{"anything", 4, true},
+		{"anything", 5, false},
+		{"unreferenced", 42, true},
+	}
+	for _, test := range tests {
+		if got := s.Suppressed(test.id, test.line); got != test.suppressed {
+			t.Errorf("Suppressed(%s, %d) = %v, want %v", test.id, test.line, got, test.suppressed)
+		}
+	}
+}
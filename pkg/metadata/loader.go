@@ -11,6 +11,9 @@ import (
 //go:embed metadata.json
 var embeddedMetadata []byte
 
+//go:embed fastly.json
+var embeddedFastly []byte
+
 // MetadataLoader handles loading and caching VCL metadata
 type MetadataLoader struct {
 	metadata *VCLMetadata
@@ -19,8 +22,22 @@ type MetadataLoader struct {
 
 // New creates a new metadata instance
 func New() *MetadataLoader {
+	return load(embeddedMetadata)
+}
+
+// NewFastly returns the metadata of Fastly VCL: its subroutines with their
+// return actions, and the common variables with the methods they can be
+// used in. The types of the variables are given by their Varnish names, so
+// that type checks written for Varnish apply. It is far less complete than
+// the Varnish metadata and meant for linting Fastly configurations.
+func NewFastly() *MetadataLoader {
+	return load(embeddedFastly)
+}
+
+// load parses embedded metadata
+func load(data []byte) *MetadataLoader {
 	var metadata VCLMetadata
-	if err := json.Unmarshal(embeddedMetadata, &metadata); err != nil {
+	if err := json.Unmarshal(data, &metadata); err != nil {
 		panic("failed to parse embedded metadata: " + err.Error() + "")
 	}
 	return &MetadataLoader{
@@ -85,6 +102,27 @@ func (ml *MetadataLoader) GetStorageVariables() ([]StorageVariable, error) {
 	return metadata.StorageVariables, nil
 }
 
+// GetBackendProperties returns the backend declaration attributes, including
+// any added by overlays
+func (ml *MetadataLoader) GetBackendProperties() (map[string]BackendProperty, error) {
+	metadata, err := ml.GetMetadata()
+	if err != nil {
+		return nil, err
+	}
+	return metadata.BackendProperties, nil
+}
+
+// LookupBackendProperty returns the schema for a backend attribute. The name
+// may be given with or without its leading dot.
+func (ml *MetadataLoader) LookupBackendProperty(name string) (BackendProperty, bool) {
+	props, err := ml.GetBackendProperties()
+	if err != nil {
+		return BackendProperty{}, false
+	}
+	prop, ok := props[strings.TrimPrefix(name, ".")]
+	return prop, ok
+}
+
 // ValidateReturnAction checks if a return action is valid for a given method
 func (ml *MetadataLoader) ValidateReturnAction(method, action string) error {
 	methods, err := ml.GetMethods()
@@ -105,6 +143,26 @@ func (ml *MetadataLoader) ValidateReturnAction(method, action string) error {
 	return nil
 }
 
+// ValidateReturnActionForRelease checks a return action like
+// ValidateReturnAction and additionally that the target Varnish release
+// supports it. A zero release only performs the static check.
+func (ml *MetadataLoader) ValidateReturnActionForRelease(method, action string, release Release) error {
+	if err := ml.ValidateReturnAction(method, action); err != nil {
+		return err
+	}
+
+	methods, err := ml.GetMethods()
+	if err != nil {
+		return err
+	}
+	if rr, ok := methods[method].ReturnReleases[action]; ok {
+		if err := rr.Check(release); err != nil {
+			return fmt.Errorf("return action '%s' in method '%s' %v", action, method, err)
+		}
+	}
+	return nil
+}
+
 // normalizeDynamicVariable converts dynamic variables to their pattern forms
 func normalizeDynamicVariable(variable string) string {
 	// Handle HTTP header patterns: req.http.*, bereq.http.*, beresp.http.*, resp.http.*, obj.http.*
@@ -115,6 +173,11 @@ func normalizeDynamicVariable(variable string) string {
 		}
 	}
 
+	// Fastly's local variables, declared with declare local var.name
+	if strings.HasPrefix(variable, "var.") {
+		return "var."
+	}
+
 	// Handle storage.<name>.* patterns
 	if strings.HasPrefix(variable, "storage.") {
 		parts := strings.Split(variable, ".")
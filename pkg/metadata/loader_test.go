@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -61,6 +62,130 @@ func TestMetadataLoader_ValidateReturnAction(t *testing.T) {
 	}
 }
 
+func TestMetadataLoader_ValidateReturnActionForRelease(t *testing.T) {
+	loader := New()
+
+	tests := []struct {
+		method   string
+		action   string
+		release  string
+		expected string // error fragment, "" if valid
+	}{
+		{"hit", "miss", "", ""},
+		{"hit", "miss", "6.0", ""},
+		{"hit", "miss", "7.5", "was removed in Varnish 7.0"},
+		{"hit", "deliver", "7.5", ""},
+		{"backend_fetch", "error", "6.0", "requires Varnish 6.3 or later"},
+		{"backend_fetch", "error", "6.3", ""},
+		{"recv", "connect", "7.5", "only available in Varnish Enterprise"},
+		{"recv", "connect", "6.0-enterprise", ""},
+		{"recv", "lookup", "7.5", "not allowed"},
+	}
+
+	for _, test := range tests {
+		var release Release
+		if test.release != "" {
+			var err error
+			if release, err = ParseRelease(test.release); err != nil {
+				t.Fatalf("ParseRelease(%q): %v", test.release, err)
+			}
+		}
+
+		err := loader.ValidateReturnActionForRelease(test.method, test.action, release)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("Expected %s+%s to be valid in %q, got error: %v", test.method, test.action, test.release, err)
+		case test.expected != "" && err == nil:
+			t.Errorf("Expected %s+%s to be invalid in %q, but validation passed", test.method, test.action, test.release)
+		case test.expected != "" && !strings.Contains(err.Error(), test.expected):
+			t.Errorf("Expected error containing %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestParseRelease(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Release
+		hasError bool
+	}{
+		{"7.5", Release{Major: 7, Minor: 5}, false},
+		{"6.0.13", Release{Major: 6, Minor: 0}, false},
+		{"6.0-enterprise", Release{Major: 6, Minor: 0, Enterprise: true}, false},
+		{"6.0.11r3", Release{Major: 6, Minor: 0, Enterprise: true}, false},
+		{"7", Release{}, true},
+		{"trunk", Release{Trunk: true}, false},
+		{"", Release{}, true},
+	}
+
+	for _, test := range tests {
+		release, err := ParseRelease(test.input)
+		if test.hasError {
+			if err == nil {
+				t.Errorf("ParseRelease(%q) expected error but got %v", test.input, release)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRelease(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		if release != test.expected {
+			t.Errorf("ParseRelease(%q) = %+v, expected %+v", test.input, release, test.expected)
+		}
+	}
+}
+
+func TestReleaseRangeTrunk(t *testing.T) {
+	trunk, _ := ParseRelease("trunk")
+	if err := (ReleaseRange{Introduced: "7.6"}).Check(trunk); err != nil {
+		t.Errorf("trunk should have features introduced in 7.6: %v", err)
+	}
+	if err := (ReleaseRange{Removed: "7.0"}).Check(trunk); err == nil {
+		t.Error("trunk should not have features removed in 7.0")
+	}
+	if !(Release{Major: 7, Minor: 6}).Before(trunk) || trunk.Before(Release{Major: 7, Minor: 6}) {
+		t.Error("trunk should come after 7.6")
+	}
+}
+
+func TestMetadataLoader_Fastly(t *testing.T) {
+	loader := NewFastly()
+
+	tests := []struct {
+		method   string
+		action   string
+		expected bool
+	}{
+		{"recv", "lookup", true},
+		{"recv", "hash", false},
+		{"fetch", "deliver", true},
+		{"error", "deliver", true},
+		{"synth", "deliver", false}, // Fastly has no vcl_synth
+	}
+	for _, test := range tests {
+		err := loader.ValidateReturnAction(test.method, test.action)
+		if test.expected != (err == nil) {
+			t.Errorf("Expected %s+%s valid=%v, got %v", test.method, test.action, test.expected, err)
+		}
+	}
+
+	if err := loader.ValidateVariableAccess("var.host", "recv", "write"); err != nil {
+		t.Errorf("Expected local variables to be writable: %v", err)
+	}
+	if err := loader.ValidateVariableAccess("fastly.error", "deliver", "read"); err != nil {
+		t.Errorf("Expected fastly.error to be readable: %v", err)
+	}
+
+	metadata, err := loader.GetMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata.Modules) == 0 {
+		t.Error("Expected the built-in Fastly modules")
+	}
+}
+
 func TestMetadataLoader_ValidateVariableAccess(t *testing.T) {
 	loader := New()
 
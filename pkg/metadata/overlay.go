@@ -0,0 +1,129 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Overlay holds user-supplied additions to the embedded metadata. It uses the
+// same JSON layout as metadata.json; only the sections below may be
+// overlaid.
+//
+// A backend property mapped to null is removed from the schema, which
+// allows dropping an attribute the target release does not support:
+//
+//	{
+//	  "backend_properties": {
+//	    "keepalive_timeout": {"type": "DURATION", "releases": {"introduced": "8.0"}},
+//	    "preamble": null
+//	  }
+//	}
+//
+// Built-in subroutines are overlaid the same way, named without the vcl_
+// prefix, so that subroutines of newer releases are recognized:
+//
+//	{
+//	  "vcl_methods": {
+//	    "track": {"context": "C", "allowed_returns": ["fail", "ok"]}
+//	  }
+//	}
+type Overlay struct {
+	BackendProperties map[string]*BackendProperty `json:"backend_properties"`
+	VCLMethods        map[string]*VCLMethod       `json:"vcl_methods"`
+}
+
+// MergeOverlay applies a JSON-encoded Overlay. Entries replace existing ones
+// of the same name. A malformed overlay leaves the metadata untouched.
+func (ml *MetadataLoader) MergeOverlay(data []byte) error {
+	var overlay Overlay
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&overlay); err != nil {
+		return fmt.Errorf("invalid metadata overlay: %w", err)
+	}
+	for name, prop := range overlay.BackendProperties {
+		if strings.HasPrefix(name, ".") {
+			return fmt.Errorf("invalid metadata overlay: backend property %q must be named without the leading dot", name)
+		}
+		if prop != nil && prop.Type == "" {
+			return fmt.Errorf("invalid metadata overlay: backend property %q has no type", name)
+		}
+	}
+	for name, method := range overlay.VCLMethods {
+		if strings.HasPrefix(name, "vcl_") {
+			return fmt.Errorf("invalid metadata overlay: subroutine %q must be named without the vcl_ prefix", name)
+		}
+		if method == nil {
+			continue
+		}
+		switch ContextType(method.Context) {
+		case ClientContext, BackendContext, HousekeepingContext:
+		default:
+			return fmt.Errorf("invalid metadata overlay: subroutine %q has context %q, expected C, B or H", name, method.Context)
+		}
+	}
+
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	if ml.metadata == nil {
+		return fmt.Errorf("metadata not available - failed to initialize embedded metadata")
+	}
+
+	// Copy before modifying so metadata handed out earlier is not changed
+	// underneath its users
+	merged := *ml.metadata
+	merged.BackendProperties = make(map[string]BackendProperty, len(ml.metadata.BackendProperties))
+	for name, prop := range ml.metadata.BackendProperties {
+		merged.BackendProperties[name] = prop
+	}
+	for name, prop := range overlay.BackendProperties {
+		if prop == nil {
+			delete(merged.BackendProperties, name)
+			continue
+		}
+		merged.BackendProperties[name] = *prop
+	}
+	if len(overlay.VCLMethods) > 0 {
+		merged.VCLMethods = make(map[string]VCLMethod, len(ml.metadata.VCLMethods))
+		for name, method := range ml.metadata.VCLMethods {
+			merged.VCLMethods[name] = method
+		}
+		for name, method := range overlay.VCLMethods {
+			if method == nil {
+				delete(merged.VCLMethods, name)
+				continue
+			}
+			merged.VCLMethods[name] = *method
+		}
+	}
+	ml.metadata = &merged
+	return nil
+}
+
+// LoadOverlayFile applies the overlay stored in a JSON file
+func (ml *MetadataLoader) LoadOverlayFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := ml.MergeOverlay(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadOverlayFS applies the overlay stored in the JSON file name of fsys
+func (ml *MetadataLoader) LoadOverlayFS(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	if err := ml.MergeOverlay(data); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
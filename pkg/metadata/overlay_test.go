@@ -0,0 +1,147 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMetadataLoader_BackendProperties(t *testing.T) {
+	loader := New()
+
+	prop, ok := loader.LookupBackendProperty("first_byte_timeout")
+	if !ok || prop.Type != "DURATION" {
+		t.Errorf("Expected first_byte_timeout to be a DURATION property, got %+v (found: %v)", prop, ok)
+	}
+	if _, ok := loader.LookupBackendProperty(".host"); !ok {
+		t.Error("Expected .host to be found with its leading dot")
+	}
+	if _, ok := loader.LookupBackendProperty("hots"); ok {
+		t.Error("Expected unknown property hots not to be found")
+	}
+
+	prop, _ = loader.LookupBackendProperty("last_byte_timeout")
+	release, _ := ParseRelease("7.4")
+	if err := prop.Releases.Check(release); err == nil {
+		t.Error("Expected last_byte_timeout to be unavailable in 7.4")
+	}
+}
+
+func TestMetadataLoader_MergeOverlay(t *testing.T) {
+	loader := New()
+	before, _ := loader.GetBackendProperties()
+
+	overlay := `{
+		"backend_properties": {
+			"keepalive_timeout": {"type": "DURATION", "releases": {"introduced": "8.0"}},
+			"host_header": {"type": "STRING", "description": "overridden"},
+			"preamble": null
+		}
+	}`
+	if err := loader.MergeOverlay([]byte(overlay)); err != nil {
+		t.Fatalf("MergeOverlay failed: %v", err)
+	}
+
+	prop, ok := loader.LookupBackendProperty("keepalive_timeout")
+	if !ok || prop.Type != "DURATION" || prop.Releases.Introduced != "8.0" {
+		t.Errorf("Expected keepalive_timeout to be added, got %+v (found: %v)", prop, ok)
+	}
+	if prop, _ := loader.LookupBackendProperty("host_header"); prop.Description != "overridden" {
+		t.Errorf("Expected host_header to be replaced, got %+v", prop)
+	}
+	if _, ok := loader.LookupBackendProperty("preamble"); ok {
+		t.Error("Expected preamble to be removed by a null entry")
+	}
+	if _, ok := loader.LookupBackendProperty("host"); !ok {
+		t.Error("Expected properties absent from the overlay to be kept")
+	}
+	if _, ok := before["preamble"]; !ok {
+		t.Error("Expected metadata returned before the merge to be unchanged")
+	}
+
+	// Subroutines are overlaid the same way
+	if err := loader.MergeOverlay([]byte(`{"vcl_methods": {"track": {"context": "C", "allowed_returns": ["ok"]}, "connect": null}}`)); err != nil {
+		t.Fatalf("MergeOverlay failed: %v", err)
+	}
+	methods, _ := loader.GetMethods()
+	if method, ok := methods["track"]; !ok || !method.IsValidReturnAction("ok") {
+		t.Errorf("Expected track to be added, got %+v (found: %v)", method, ok)
+	}
+	if _, ok := methods["connect"]; ok {
+		t.Error("Expected connect to be removed by a null entry")
+	}
+	if _, ok := methods["recv"]; !ok {
+		t.Error("Expected subroutines absent from the overlay to be kept")
+	}
+
+	// Overlays apply to one loader only
+	if _, ok := New().LookupBackendProperty("keepalive_timeout"); ok {
+		t.Error("Expected a new loader not to see another loader's overlay")
+	}
+}
+
+func TestMetadataLoader_MergeOverlayInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		overlay  string
+		expected string
+	}{
+		{"malformed", `{"backend_properties": `, "invalid metadata overlay"},
+		{"unknown section", `{"vcl_types": {}}`, "unknown field"},
+		{"missing type", `{"backend_properties": {"foo": {"description": "x"}}}`, `"foo" has no type`},
+		{"leading dot", `{"backend_properties": {".foo": {"type": "INT"}}}`, "without the leading dot"},
+		{"subroutine prefix", `{"vcl_methods": {"vcl_track": {"context": "C"}}}`, "without the vcl_ prefix"},
+		{"subroutine context", `{"vcl_methods": {"track": {"context": "X"}}}`, `"track" has context "X"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := New()
+			err := loader.MergeOverlay([]byte(tt.overlay))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+			if _, ok := loader.LookupBackendProperty("host"); !ok {
+				t.Error("Expected a rejected overlay to leave the schema intact")
+			}
+		})
+	}
+}
+
+func TestMetadataLoader_LoadOverlayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overlay.json")
+	if err := os.WriteFile(path, []byte(`{"backend_properties": {"foo": {"type": "INT"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := New()
+	if err := loader.LoadOverlayFile(path); err != nil {
+		t.Fatalf("LoadOverlayFile failed: %v", err)
+	}
+	if _, ok := loader.LookupBackendProperty("foo"); !ok {
+		t.Error("Expected foo to be loaded from the overlay file")
+	}
+
+	if err := loader.LoadOverlayFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing overlay file")
+	}
+}
+
+func TestMetadataLoader_LoadOverlayFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/overlay.json": &fstest.MapFile{Data: []byte(`{"backend_properties": {"foo": {"type": "INT"}}}`)},
+	}
+
+	loader := New()
+	if err := loader.LoadOverlayFS(fsys, "config/overlay.json"); err != nil {
+		t.Fatalf("LoadOverlayFS failed: %v", err)
+	}
+	if _, ok := loader.LookupBackendProperty("foo"); !ok {
+		t.Error("Expected foo to be loaded from the overlay file")
+	}
+	if err := loader.LoadOverlayFS(fsys, "config/missing.json"); err == nil {
+		t.Error("Expected an error for a missing overlay file")
+	}
+}
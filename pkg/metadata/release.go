@@ -0,0 +1,103 @@
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// releasePattern matches Varnish release strings such as "7.5", "6.0.13",
+// "6.0-enterprise" and "6.0.11r3" (the r suffix marks Varnish Enterprise)
+var releasePattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.\d+)?(r\d+|-enterprise)?$`)
+
+// Release identifies a Varnish release that VCL is validated against. The
+// zero value means no particular release: release-specific checks are
+// skipped.
+type Release struct {
+	Major      int
+	Minor      int
+	Enterprise bool
+	// Trunk is the development branch, later than every numbered release
+	Trunk bool
+}
+
+// ParseRelease parses a Varnish release such as "7.5", "6.0.13",
+// "6.0-enterprise" or "trunk". Patch levels are accepted and ignored.
+func ParseRelease(s string) (Release, error) {
+	if strings.TrimSpace(s) == "trunk" {
+		return Release{Trunk: true}, nil
+	}
+	m := releasePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Release{}, fmt.Errorf("invalid Varnish release %q: expected a version such as 7.5, 6.0-enterprise or trunk", s)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return Release{
+		Major:      major,
+		Minor:      minor,
+		Enterprise: m[3] != "",
+	}, nil
+}
+
+// IsZero reports whether no release is selected
+func (r Release) IsZero() bool {
+	return r == Release{}
+}
+
+// String returns the release as accepted by ParseRelease
+func (r Release) String() string {
+	if r.IsZero() {
+		return ""
+	}
+	if r.Trunk {
+		return "trunk"
+	}
+	s := fmt.Sprintf("%d.%d", r.Major, r.Minor)
+	if r.Enterprise {
+		s += "-enterprise"
+	}
+	return s
+}
+
+// Before reports whether r is an earlier release than other, ignoring the
+// edition
+func (r Release) Before(other Release) bool {
+	if r.Trunk || other.Trunk {
+		return !r.Trunk && other.Trunk
+	}
+	if r.Major != other.Major {
+		return r.Major < other.Major
+	}
+	return r.Minor < other.Minor
+}
+
+// ReleaseRange describes which Varnish releases support a feature
+type ReleaseRange struct {
+	Introduced string `json:"introduced,omitempty"` // First release with the feature
+	Removed    string `json:"removed,omitempty"`    // First release without it
+	Enterprise bool   `json:"enterprise,omitempty"` // Only available in Varnish Enterprise
+}
+
+// Check returns an error describing why the feature is unavailable in
+// release, or nil if it is available. A zero release always passes.
+func (rr ReleaseRange) Check(release Release) error {
+	if release.IsZero() {
+		return nil
+	}
+	if rr.Enterprise && !release.Enterprise {
+		return fmt.Errorf("is only available in Varnish Enterprise (target: %s)", release)
+	}
+	if rr.Introduced != "" {
+		if introduced, err := ParseRelease(rr.Introduced); err == nil && release.Before(introduced) {
+			return fmt.Errorf("requires Varnish %s or later (target: %s)", rr.Introduced, release)
+		}
+	}
+	if rr.Removed != "" {
+		if removed, err := ParseRelease(rr.Removed); err == nil && !release.Before(removed) {
+			return fmt.Errorf("was removed in Varnish %s (target: %s)", rr.Removed, release)
+		}
+	}
+	return nil
+}
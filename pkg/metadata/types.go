@@ -9,12 +9,34 @@ type VCLMetadata struct {
 	VCLTypes         map[string]VCLType     `json:"vcl_types"`
 	VCLTokens        map[string]string      `json:"vcl_tokens"`
 	StorageVariables []StorageVariable      `json:"storage_variables"`
+
+	// BackendProperties describes the attributes accepted in backend
+	// declarations, keyed by name without the leading dot
+	BackendProperties map[string]BackendProperty `json:"backend_properties,omitempty"`
+
+	// Deprecations lists constructs other than variables that still work
+	// but have a replacement, keyed by construct: "vcl 4.0" for a VCL
+	// version declaration, where the replacement is only suggested, and
+	// "ban()" for a built-in function
+	Deprecations map[string]Deprecation `json:"deprecations,omitempty"`
+
+	// Modules lists the function namespaces built into the language, such
+	// as Fastly's std and table, which are used without import
+	Modules []string `json:"modules,omitempty"`
 }
 
 // VCLMethod represents a VCL method with its context and allowed returns
 type VCLMethod struct {
 	Context        string   `json:"context"`         // "C" (client), "B" (backend), "H" (housekeeping)
 	AllowedReturns []string `json:"allowed_returns"` // List of allowed return actions
+
+	// ReturnReleases restricts allowed return actions to the Varnish
+	// releases that support them. Actions not listed are available in all
+	// releases.
+	ReturnReleases map[string]ReleaseRange `json:"return_releases,omitempty"`
+
+	// Releases lists the Varnish releases with this subroutine
+	Releases ReleaseRange `json:"releases,omitempty"`
 }
 
 // VCLVariable represents a VCL variable with its type and access permissions
@@ -25,6 +47,20 @@ type VCLVariable struct {
 	UnsetableFrom []string `json:"unsetable_from"` // VCL methods where variable can be unset
 	VersionLow    int      `json:"version_low"`    // Minimum VCL version
 	VersionHigh   int      `json:"version_high"`   // Maximum VCL version
+
+	// Releases lists the Varnish releases with this variable
+	Releases ReleaseRange `json:"releases,omitempty"`
+
+	// DeprecatedSince is the Varnish release that deprecated the variable,
+	// and Replacement what to use instead
+	DeprecatedSince string `json:"deprecated_since,omitempty"`
+	Replacement     string `json:"replacement,omitempty"`
+}
+
+// Deprecation describes a deprecated construct
+type Deprecation struct {
+	DeprecatedSince string `json:"deprecated_since"`      // Varnish release that deprecated it
+	Replacement     string `json:"replacement,omitempty"` // What to use instead
 }
 
 // VCLType represents a VCL type definition
@@ -42,6 +78,15 @@ type StorageVariable struct {
 	Docstring   string `json:"docstring"`   // Detailed documentation
 }
 
+// BackendProperty describes an attribute of a backend declaration, such as
+// .host or .first_byte_timeout
+type BackendProperty struct {
+	Type        string       `json:"type"`                  // VCL type of the value
+	Description string       `json:"description,omitempty"` // Short description
+	Releases    ReleaseRange `json:"releases,omitempty"`    // Varnish releases supporting the property
+	VersionLow  int          `json:"version_low,omitempty"` // Minimum VCL version, 0 for all
+}
+
 // ContextType represents the execution context for VCL methods
 type ContextType string
 
@@ -0,0 +1,393 @@
+// Package migrate upgrades VCL 4.0 programs to VCL 4.1. It changes the
+// version declaration and replaces the variables VCL 4.1 removed with their
+// successors from the metadata, such as beresp.storage_hint with
+// beresp.storage. Uses it cannot rewrite safely, such as req.esi, whose
+// successor resp.do_esi belongs in vcl_deliver, or beresp.backend.ip, which
+// has no successor, are reported for manual attention instead. VCL from
+// before 4.0, which has no version declaration, is read in the parser's
+// legacy mode, which maps it onto current VCL. The result comes with the
+// edits to the source and a unified diff:
+//
+//	result, err := migrate.Migrate(src, "default.vcl")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(result.Diff)
+//	for _, d := range result.Manual {
+//		fmt.Printf("%s:%d: %s\n", d.Filename, d.Position.Line, d.Message)
+//	}
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/build"
+	"github.com/perbu/vclparser/pkg/edit"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/metadata"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+// Version is the VCL version programs are migrated to
+const Version = "4.1"
+
+// targetVersion is Version in the metadata's form
+const targetVersion = 41
+
+// Diagnostic codes of the findings
+const (
+	// CodeMigrated marks a change the migration made
+	CodeMigrated = "migrated"
+	// CodeManual marks a construct the migration left for manual attention
+	CodeManual = "manual-migration"
+)
+
+// Result is a migrated VCL file
+type Result struct {
+	// Program is the migrated syntax tree
+	Program *ast.Program
+	// Source is the migrated text, which is the original text with Edits
+	// applied
+	Source string
+	Edits  []analyzer.TextEdit
+	// Diff is a unified diff from the original to the migrated text, or ""
+	// if nothing changed
+	Diff string
+	// Changes lists the rewrites made, as informational diagnostics with
+	// the code "migrated"
+	Changes []analyzer.Diagnostic
+	// Manual lists the constructs that need manual attention, as warnings
+	// with the code "manual-migration", at their positions in the
+	// original text
+	Manual []analyzer.Diagnostic
+}
+
+// Migrate parses a VCL 4.0 file and migrates it to VCL 4.1. A file
+// declaring VCL 4.1 already is only checked for removed variables, and a
+// file without version declaration is taken for VCL 3. Any other version
+// is an error.
+func Migrate(src, filename string) (*Result, error) {
+	if !hasVersion(src, filename) {
+		return migrateLegacy(src, filename)
+	}
+	program, err := parser.Parse(src, filename)
+	if err != nil {
+		return nil, err
+	}
+	changes, manual, err := Program(program)
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := edit.Edits(src, filename, program)
+	if err != nil {
+		return nil, err
+	}
+	out, err := analyzer.ApplyEdits(src, edits)
+	if err != nil {
+		return nil, err
+	}
+	return newResult(src, filename, program, out, edits, changes, manual), nil
+}
+
+// migrateLegacy migrates VCL from before 4.0. The nodes the legacy mode
+// maps no longer match the text, so the program is printed anew, keeping
+// its comments, and replaces the whole text.
+func migrateLegacy(src, filename string) (*Result, error) {
+	parsed := parser.ParseDetailed(src, filename, &parser.Config{Legacy: true})
+	if err := parsed.Err(); err != nil {
+		return nil, err
+	}
+	program := parsed.Program
+	program.VCLVersion = &ast.VCLVersionDecl{Version: Version}
+
+	changes := []analyzer.Diagnostic{{
+		Severity: analyzer.SeverityInfo,
+		Code:     CodeMigrated,
+		Message:  fmt.Sprintf("vcl %s declaration added", Version),
+	}}
+	for _, w := range parsed.Warnings {
+		if w.Code == "legacy-syntax" {
+			changes = append(changes, analyzer.Diagnostic{
+				Position:    w.Position,
+				EndPosition: w.EndPosition,
+				Severity:    analyzer.SeverityInfo,
+				Code:        CodeMigrated,
+				Message:     w.Message,
+			})
+		}
+	}
+	more, manual, err := Program(program)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, more...)
+
+	config := printer.DefaultConfig()
+	config.Indent = indentation(src)
+	var buf bytes.Buffer
+	if err := config.FprintComments(&buf, program, parsed.Comments); err != nil {
+		return nil, err
+	}
+	out := buf.String()
+	lines := lexer.NewLineIndex(src)
+	edits := []analyzer.TextEdit{{Start: lines.Position(0), End: lines.Position(len(src)), NewText: out}}
+	return newResult(src, filename, program, out, edits, changes, manual), nil
+}
+
+func newResult(src, filename string, program *ast.Program, out string, edits []analyzer.TextEdit, changes, manual []analyzer.Diagnostic) *Result {
+	for _, list := range [][]analyzer.Diagnostic{changes, manual} {
+		for i := range list {
+			list[i].Filename = filename
+		}
+	}
+	return &Result{
+		Program: program,
+		Source:  out,
+		Edits:   edits,
+		Diff:    edit.UnifiedFile(filename, src, out),
+		Changes: changes,
+		Manual:  manual,
+	}
+}
+
+// indentation returns the indentation of the first indented line of src,
+// or the printer's default
+func indentation(src string) string {
+	for _, line := range strings.Split(src, "\n") {
+		if indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]; indent != "" && indent != line {
+			return indent
+		}
+	}
+	return printer.DefaultConfig().Indent
+}
+
+// hasVersion reports whether src starts with a vcl version declaration
+func hasVersion(src, filename string) bool {
+	for _, tok := range lexer.ScanAll(src, filename) {
+		switch tok.Type {
+		case lexer.WHITESPACE, lexer.COMMENT:
+		default:
+			return tok.Type == lexer.VCL_KW
+		}
+	}
+	return false
+}
+
+// Program migrates a parsed program to VCL 4.1 in place. A program without
+// version declaration gets none. It returns the changes made and the
+// constructs needing manual attention, which carry the positions the nodes
+// had before the migration.
+func Program(program *ast.Program) (changes, manual []analyzer.Diagnostic, err error) {
+	meta, err := metadata.New().GetMetadata()
+	if err != nil {
+		return nil, nil, err
+	}
+	m := &migration{
+		meta:    meta,
+		methods: subroutineMethods(program),
+		handled: make(map[ast.Node]bool),
+	}
+
+	if decl := program.VCLVersion; decl != nil {
+		switch decl.Version {
+		case "4.0":
+			m.change(decl, fmt.Sprintf("vcl 4.0 changed to vcl %s", Version))
+			decl.Version = Version
+		case Version:
+		default:
+			return nil, nil, fmt.Errorf("cannot migrate VCL %s to VCL %s", decl.Version, Version)
+		}
+	}
+
+	ast.Apply(program, m.pre, nil)
+	return m.changes, m.manual, nil
+}
+
+// migration holds the state of one Program call
+type migration struct {
+	meta *metadata.VCLMetadata
+	// methods maps subroutine names to the built-in subroutines they run
+	// in, as metadata method names such as "recv"
+	methods map[string][]string
+	// sub is the name of the subroutine being walked
+	sub string
+	// handled holds the variables already migrated as the target of a set
+	// or unset statement
+	handled map[ast.Node]bool
+
+	changes, manual []analyzer.Diagnostic
+}
+
+func (m *migration) pre(c *ast.Cursor) bool {
+	switch n := c.Node().(type) {
+	case *ast.SubDecl:
+		m.sub = n.Name
+	case *ast.BackendDecl, *ast.ProbeDecl, *ast.ACLDecl:
+		return false
+	case *ast.SetStatement:
+		if n.Operator == "=" {
+			n.Variable, n.Value = m.variable(n.Variable, n.Value, "write")
+		} else {
+			n.Variable, _ = m.variable(n.Variable, nil, "write")
+		}
+		m.handled[n.Variable] = true
+	case *ast.UnsetStatement:
+		n.Variable, _ = m.variable(n.Variable, nil, "unset")
+		m.handled[n.Variable] = true
+	case *ast.ReturnStatement:
+		m.returnAction(n)
+	case *ast.MemberExpression:
+		if !m.handled[n] {
+			if expr, _ := m.variable(n, nil, "read"); expr != ast.Expression(n) {
+				c.Replace(expr)
+			}
+		}
+		// The parts of a variable name are not variables themselves
+		return false
+	}
+	return true
+}
+
+// variable migrates one use of a variable. access is "read", "write" or
+// "unset", and value is the value assigned with =, if any. It returns the
+// variable and value to use in their place, which are the ones given when
+// nothing changes.
+func (m *migration) variable(expr, value ast.Expression, access string) (ast.Expression, ast.Expression) {
+	name := ast.VariableName(expr)
+	old, ok := m.meta.VCLVariables[name]
+	if !ok || old.IsAvailableInVersion(targetVersion) {
+		return expr, value
+	}
+
+	removed := fmt.Sprintf("%s was removed in VCL %s", name, Version)
+	if old.Replacement == "" {
+		m.report(expr, removed+" and has no replacement")
+		return expr, value
+	}
+	replacement, ok := m.meta.VCLVariables[old.Replacement]
+	if !ok {
+		m.report(expr, fmt.Sprintf("%s; use %s instead", removed, old.Replacement))
+		return expr, value
+	}
+
+	methods := m.methods[m.sub]
+	if len(methods) == 0 {
+		m.report(expr, fmt.Sprintf("%s; replace it with %s by hand, as no built-in subroutine calls %s",
+			removed, old.Replacement, m.sub))
+		return expr, value
+	}
+	for _, method := range methods {
+		if !accessible(&replacement, access, method, m.meta.VCLMethods) {
+			m.report(expr, fmt.Sprintf("%s; its replacement %s cannot be %s in vcl_%s",
+				removed, old.Replacement, pastTense[access], method))
+			return expr, value
+		}
+	}
+
+	if replacement.Type != old.Type {
+		converted := convert(value, old.Type, replacement.Type)
+		if converted == nil {
+			m.report(expr, fmt.Sprintf("%s; use %s instead, which is a %s rather than a %s",
+				removed, old.Replacement, replacement.Type, old.Type))
+			return expr, value
+		}
+		value = converted
+	}
+
+	m.change(expr, fmt.Sprintf("%s replaced with %s", name, old.Replacement))
+	return build.Var(old.Replacement), value
+}
+
+// returnAction reports a return action the subroutine may not take, such
+// as return (purge) outside vcl_recv, which a VCL 3 purge statement becomes
+func (m *migration) returnAction(stmt *ast.ReturnStatement) {
+	action := ast.VariableName(stmt.Action)
+	if call, ok := stmt.Action.(*ast.CallExpression); ok {
+		action = ast.VariableName(call.Function)
+	}
+	if action == "" || !strings.HasPrefix(m.sub, "vcl_") {
+		return
+	}
+	method, ok := m.meta.VCLMethods[strings.TrimPrefix(m.sub, "vcl_")]
+	if ok && !method.IsValidReturnAction(action) {
+		m.report(stmt, fmt.Sprintf("return (%s) is not allowed in %s", action, m.sub))
+	}
+}
+
+// storageName matches the names of storage backends
+var storageName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// convert returns value converted from one type to another, or nil if it
+// cannot be. Only assigned values can be converted, and a STEVEDORE only
+// from a string naming the storage.
+func convert(value ast.Expression, from, to string) ast.Expression {
+	if from == "STRING" && to == "STEVEDORE" {
+		if s, ok := value.(*ast.StringLiteral); ok && storageName.MatchString(s.Value) {
+			return build.Var("storage." + s.Value)
+		}
+	}
+	return nil
+}
+
+var pastTense = map[string]string{
+	"read":  "read",
+	"write": "set",
+	"unset": "unset",
+}
+
+func accessible(v *metadata.VCLVariable, access, method string, methods map[string]metadata.VCLMethod) bool {
+	switch access {
+	case "write":
+		return v.IsWritableInMethod(method, methods)
+	case "unset":
+		return v.IsUnsetableInMethod(method, methods)
+	default:
+		return v.IsReadableInMethod(method, methods)
+	}
+}
+
+func (m *migration) change(node ast.Node, message string) {
+	m.changes = append(m.changes, diagnostic(node, analyzer.SeverityInfo, CodeMigrated, message))
+}
+
+func (m *migration) report(node ast.Node, message string) {
+	m.manual = append(m.manual, diagnostic(node, analyzer.SeverityWarning, CodeManual, message))
+}
+
+func diagnostic(node ast.Node, severity analyzer.Severity, code, message string) analyzer.Diagnostic {
+	return analyzer.Diagnostic{
+		Position:    node.Start(),
+		EndPosition: node.End(),
+		Severity:    severity,
+		Code:        code,
+		Message:     message,
+	}
+}
+
+// subroutineMethods maps each subroutine to the built-in subroutines it
+// runs in: itself for a built-in one, and the built-in ones calling it for
+// a custom one. Unreachable subroutines run in none.
+func subroutineMethods(program *ast.Program) map[string][]string {
+	calls := analyzer.NewCallGraph(program)
+	methods := make(map[string][]string)
+	for _, name := range calls.Subroutines() {
+		if !strings.HasPrefix(name, "vcl_") {
+			continue
+		}
+		for _, reached := range calls.ReachableFrom(name) {
+			methods[reached] = append(methods[reached], strings.TrimPrefix(name, "vcl_"))
+		}
+	}
+	for _, list := range methods {
+		sort.Strings(list)
+	}
+	return methods
+}
@@ -0,0 +1,189 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+const legacy = `vcl 4.0;
+
+backend default {
+	.host = "192.0.2.1";
+}
+
+sub pick_storage {
+	set beresp.storage_hint = "memory";
+}
+
+sub vcl_recv {
+	set req.esi = false;
+}
+
+sub vcl_backend_response {
+	call pick_storage;
+	if (beresp.backend.ip ~ client.ip) {
+		set beresp.http.X-Loop = "1";
+	}
+}
+
+sub vcl_backend_error {
+	call pick_storage;
+}
+`
+
+func TestMigrate(t *testing.T) {
+	result, err := Migrate(legacy, "default.vcl")
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	want := strings.NewReplacer(
+		"vcl 4.0;", "vcl 4.1;",
+		`set beresp.storage_hint = "memory";`, "set beresp.storage = storage.memory;",
+	).Replace(legacy)
+	if result.Source != want {
+		t.Errorf("Unexpected result:\n%s", result.Source)
+	}
+	if !strings.Contains(result.Diff, "-vcl 4.0;\n+vcl 4.1;\n") || !strings.HasPrefix(result.Diff, "--- a/default.vcl\n+++ b/default.vcl\n") {
+		t.Errorf("Unexpected diff:\n%s", result.Diff)
+	}
+	if len(result.Changes) != 2 || result.Changes[1].Message != "beresp.storage_hint replaced with beresp.storage" {
+		t.Errorf("Unexpected changes: %+v", result.Changes)
+	}
+
+	// req.esi belongs in another subroutine as resp.do_esi, and
+	// beresp.backend.ip has no replacement
+	if len(result.Manual) != 2 {
+		t.Fatalf("Expected 2 findings, got %+v", result.Manual)
+	}
+	for i, want := range []struct {
+		line    int
+		message string
+	}{
+		{12, "req.esi was removed in VCL 4.1; its replacement resp.do_esi cannot be set in vcl_recv"},
+		{17, "beresp.backend.ip was removed in VCL 4.1 and has no replacement"},
+	} {
+		d := result.Manual[i]
+		if d.Position.Line != want.line || d.Message != want.message || d.Code != CodeManual || d.Filename != "default.vcl" {
+			t.Errorf("Finding %d: got %d: %s (%s)", i, d.Position.Line, d.Message, d.Code)
+		}
+	}
+
+	program, err := parser.Parse(result.Source, "default.vcl")
+	if err != nil {
+		t.Fatalf("Result does not parse: %v", err)
+	}
+	a := analyzer.NewAnalyzer(vmod.DefaultRegistry)
+	for _, msg := range a.Analyze(program) {
+		if strings.Contains(msg, "storage") {
+			t.Errorf("Unexpected finding for the migrated variable: %s", msg)
+		}
+	}
+}
+
+func TestMigrateAbsolutePath(t *testing.T) {
+	result, err := Migrate(legacy, "/tmp/x.vcl")
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if !strings.HasPrefix(result.Diff, "--- a/tmp/x.vcl\n+++ b/tmp/x.vcl\n") {
+		t.Errorf("Unexpected diff headers:\n%s", result.Diff)
+	}
+}
+
+func TestMigrateStorage(t *testing.T) {
+	// A value that does not name a storage cannot become a STEVEDORE
+	src := `vcl 4.0;
+sub vcl_backend_response {
+	set beresp.storage_hint = req.http.X-Storage;
+}
+`
+	result, err := Migrate(src, "default.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Manual) != 1 || !strings.Contains(result.Manual[0].Message, "which is a STEVEDORE rather than a STRING") {
+		t.Errorf("Expected a finding about the type, got %+v", result.Manual)
+	}
+	if !strings.Contains(result.Source, "set beresp.storage_hint = req.http.X-Storage;") {
+		t.Errorf("Expected the assignment to be left alone:\n%s", result.Source)
+	}
+}
+
+func TestMigrateVersions(t *testing.T) {
+	current := "vcl 4.1;\nsub vcl_recv {\n\treturn (hash);\n}\n"
+	result, err := Migrate(current, "current.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Diff != "" || len(result.Edits) != 0 || len(result.Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", result)
+	}
+
+	// A subroutine that no built-in one calls could run anywhere
+	unused := "vcl 4.0;\nsub unused {\n\tset beresp.storage_hint = \"file\";\n}\n"
+	result, err = Migrate(unused, "unused.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Manual) != 1 || len(result.Changes) != 1 {
+		t.Errorf("Expected the version change and one finding, got %+v %+v", result.Changes, result.Manual)
+	}
+
+	if _, err := Migrate("vcl 3.0;\n", "old.vcl"); err == nil {
+		t.Error("Expected an error for VCL 3.0")
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	src := `# Varnish 3
+sub vcl_recv {
+	if (req.request == "PURGE") {
+		return (lookup);
+	}
+}
+
+sub vcl_hit {
+	if (req.request == "PURGE") {
+		purge;
+		error 200 "Purged.";
+	}
+}
+`
+	result, err := Migrate(src, "old.vcl")
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	want := `vcl 4.1;
+
+# Varnish 3
+sub vcl_recv {
+	if (req.method == "PURGE") {
+		return (hash);
+	}
+}
+
+sub vcl_hit {
+	if (req.method == "PURGE") {
+		return (purge);
+		return (synth(200, "Purged."));
+	}
+}
+`
+	if result.Source != want {
+		t.Errorf("Unexpected result:\n%s", result.Source)
+	}
+	if len(result.Changes) != 7 {
+		t.Errorf("Expected 7 changes, got %+v", result.Changes)
+	}
+	if len(result.Manual) != 1 || result.Manual[0].Message != "return (purge) is not allowed in vcl_hit" || result.Manual[0].Position.Line != 10 {
+		t.Errorf("Expected a finding for the purge, got %+v", result.Manual)
+	}
+	if _, err := parser.Parse(result.Source, "old.vcl"); err != nil {
+		t.Errorf("Result does not parse: %v", err)
+	}
+}
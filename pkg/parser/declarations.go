@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/lexer"
@@ -10,17 +12,18 @@ import (
 
 // parseBackendDecl parses a backend declaration
 func (p *Parser) parseBackendDecl() *ast.BackendDecl {
-	decl := &ast.BackendDecl{
+	decl := ast.New(p.arena, ast.BackendDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectPeek(lexer.ID) {
 		return nil
 	}
 
 	decl.Name = p.currentToken.Value
+	decl.NamePos = p.currentToken.Start
 
 	if !p.expectPeek(lexer.LBRACE) {
 		return nil
@@ -35,6 +38,7 @@ func (p *Parser) parseBackendDecl() *ast.BackendDecl {
 			continue
 		}
 
+		start := p.currentToken.Start.Offset
 		prop := p.parseBackendProperty()
 		if prop != nil {
 			decl.Properties = append(decl.Properties, prop)
@@ -44,6 +48,8 @@ func (p *Parser) parseBackendDecl() *ast.BackendDecl {
 			p.skipToSynchronizationPoint(lexer.DOT, lexer.RBRACE, lexer.SEMICOLON)
 			if p.currentTokenIs(lexer.SEMICOLON) {
 				p.nextToken() // consume semicolon and continue
+			} else if p.currentToken.Start.Offset == start && !p.currentTokenIs(lexer.RBRACE) {
+				p.nextToken() // the property failed where it began; don't retry it forever
 			}
 		}
 	}
@@ -66,11 +72,11 @@ func (p *Parser) parseBackendProperty() *ast.BackendProperty {
 		return nil
 	}
 
-	prop := &ast.BackendProperty{
+	prop := ast.New(p.arena, ast.BackendProperty{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move to the property name token
 
@@ -103,6 +109,10 @@ func (p *Parser) parseBackendProperty() *ast.BackendProperty {
 		p.nextToken() // move past semicolon
 	} else {
 		prop.EndPos = p.currentToken.End
+		// VCC writes inline probes without a semicolon after the '}'
+		if _, ok := prop.Value.(*ast.ObjectExpression); ok {
+			p.nextToken() // move past '}'
+		}
 	}
 
 	return prop
@@ -110,17 +120,18 @@ func (p *Parser) parseBackendProperty() *ast.BackendProperty {
 
 // parseProbeDecl parses a probe declaration
 func (p *Parser) parseProbeDecl() *ast.ProbeDecl {
-	decl := &ast.ProbeDecl{
+	decl := ast.New(p.arena, ast.ProbeDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectPeek(lexer.ID) {
 		return nil
 	}
 
 	decl.Name = p.currentToken.Value
+	decl.NamePos = p.currentToken.Start
 
 	if !p.expectPeek(lexer.LBRACE) {
 		return nil
@@ -160,11 +171,11 @@ func (p *Parser) parseProbeProperty() *ast.ProbeProperty {
 		return nil
 	}
 
-	prop := &ast.ProbeProperty{
+	prop := ast.New(p.arena, ast.ProbeProperty{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectPeek(lexer.ID) {
 		return nil
@@ -193,11 +204,11 @@ func (p *Parser) parseProbeProperty() *ast.ProbeProperty {
 
 // parseACLDecl parses an ACL declaration
 func (p *Parser) parseACLDecl() *ast.ACLDecl {
-	decl := &ast.ACLDecl{
+	decl := ast.New(p.arena, ast.ACLDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // Move past 'acl'
 
@@ -208,6 +219,7 @@ func (p *Parser) parseACLDecl() *ast.ACLDecl {
 	}
 
 	decl.Name = p.currentToken.Value
+	decl.NamePos = p.currentToken.Start
 
 	if !p.expectPeek(lexer.LBRACE) {
 		return nil
@@ -240,11 +252,11 @@ func (p *Parser) parseACLDecl() *ast.ACLDecl {
 
 // parseACLEntry parses an ACL entry
 func (p *Parser) parseACLEntry() *ast.ACLEntry {
-	entry := &ast.ACLEntry{
+	entry := ast.New(p.arena, ast.ACLEntry{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	// Check for negation
 	if p.currentTokenIs(lexer.BANG) {
@@ -255,6 +267,7 @@ func (p *Parser) parseACLEntry() *ast.ACLEntry {
 	// Parse the network specification
 	entry.Network = p.parseExpression()
 	entry.EndPos = p.currentToken.End
+	p.setACLNetwork(entry)
 
 	// Consume semicolon if present
 	if p.peekTokenIs(lexer.SEMICOLON) {
@@ -264,22 +277,94 @@ func (p *Parser) parseACLEntry() *ast.ACLEntry {
 	return entry
 }
 
+// setACLNetwork fills in the host, mask and optional flag of an ACL entry
+// from its network expression, which is "host", "host"/mask or either of
+// them in parentheses, and checks the address and mask
+func (p *Parser) setACLNetwork(entry *ast.ACLEntry) {
+	entry.PrefixLen = -1
+	network := entry.Network
+	if paren, ok := network.(*ast.ParenthesizedExpression); ok {
+		entry.Optional = true
+		network = paren.Expression
+	}
+
+	var host *ast.StringLiteral
+	switch n := network.(type) {
+	case *ast.StringLiteral:
+		host = n
+	case *ast.BinaryExpression:
+		lit, ok := n.Left.(*ast.StringLiteral)
+		mask, isInt := n.Right.(*ast.IntegerLiteral)
+		if n.Operator != "/" || !ok || !isInt {
+			break
+		}
+		host = lit
+		entry.PrefixLen = int(mask.Value)
+	}
+	if host == nil {
+		if network != nil {
+			p.addError("ACL entry must be a quoted address or host name, optionally followed by /mask")
+		}
+		return
+	}
+	entry.Host = host.Value
+
+	if ip := net.ParseIP(entry.Host); ip != nil {
+		bits := 128
+		if ip.To4() != nil {
+			bits = 32
+		}
+		if entry.PrefixLen > bits {
+			p.addError(fmt.Sprintf("ACL mask /%d is too long for %s, the maximum is /%d", entry.PrefixLen, entry.Host, bits))
+		}
+		return
+	}
+	if strings.Contains(entry.Host, ":") || strings.Trim(entry.Host, "0123456789.") == "" {
+		p.addError(fmt.Sprintf("invalid IP address %q in ACL", entry.Host))
+		return
+	}
+	if !validHostName(entry.Host) {
+		p.addError(fmt.Sprintf("invalid host name %q in ACL", entry.Host))
+	}
+}
+
+// validHostName reports whether name is a syntactically valid DNS name
+func validHostName(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // parseSubDecl parses subroutine declarations and registers them in the symbol table.
 // Creates a new scope for the subroutine body and validates that subroutine names
 // are unique within the current scope. Supports both built-in VCL subroutines
 // (vcl_recv, vcl_backend_fetch) and user-defined subroutines.
 func (p *Parser) parseSubDecl() *ast.SubDecl {
-	decl := &ast.SubDecl{
+	decl := ast.New(p.arena, ast.SubDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectPeek(lexer.ID) {
 		return nil
 	}
 
 	decl.Name = p.currentToken.Value
+	decl.NamePos = p.currentToken.Start
+	p.parseSubReturnType(decl)
 
 	if !p.expectPeek(lexer.LBRACE) {
 		return nil
@@ -292,8 +377,13 @@ func (p *Parser) parseSubDecl() *ast.SubDecl {
 		Type:     types.Void,
 		Position: p.currentToken.Start,
 	}
-	if err := p.symbolTable.Define(symbol); err != nil {
-		p.addError(fmt.Sprintf("subroutine %s already defined: %s", decl.Name, err.Error()))
+	// VCL concatenates repeated definitions of built-in subroutines
+	existing := p.symbolTable.Lookup(decl.Name)
+	repeated := existing != nil && existing.Kind == types.SymbolSubroutine && strings.HasPrefix(decl.Name, "vcl_")
+	if !repeated {
+		if err := p.symbolTable.Define(symbol); err != nil {
+			p.addError(fmt.Sprintf("subroutine %s already defined: %s", decl.Name, err.Error()))
+		}
 	}
 
 	// Parse the subroutine body
@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
 )
 
 // Duration units mapping to seconds (similar to Varnish's VNUM_duration_unit)
@@ -23,9 +27,23 @@ func IsDurationUnit(unit string) bool {
 	return exists
 }
 
-// ParseDuration parses a duration string (e.g., "30s", "1.5h") and returns the value in seconds
+// durationUnitOrder ranks units from largest to smallest, used to require
+// that compound durations list their units in decreasing order
+var durationUnitOrder = map[string]int{
+	"y": 0, "w": 1, "d": 2, "h": 3, "m": 4, "s": 5, "ms": 6,
+}
+
+// normalizeUnits are the units a normalized duration may be written in,
+// largest first
+var normalizeUnits = []string{"y", "w", "d", "h", "m", "s", "ms"}
+
+// ParseDuration parses a duration string (e.g., "30s", "1.5h", "1h30m") and returns the value in seconds
 // This mimics Varnish's duration parsing behavior
 func ParseDuration(durationStr string) (float64, error) {
+	if parts, err := SplitDuration(durationStr); err == nil && len(parts) > 1 {
+		return durationSeconds(parts)
+	}
+
 	// Check for units in order of length (longest first) to avoid "ms" being matched as "m"
 	units := []string{"ms", "s", "m", "h", "d", "w", "y"}
 
@@ -64,11 +82,14 @@ func GetSupportedDurationUnits() []string {
 }
 
 // ValidateDurationString checks if a complete duration string is valid
-// Returns true for strings like "30s", "1.5h", "0ms", etc.
+// Returns true for strings like "30s", "1.5h", "0ms", "1h30m", etc.
 func ValidateDurationString(durationStr string) bool {
 	if durationStr == "" {
 		return false
 	}
+	if parts, err := SplitDuration(durationStr); err == nil && len(parts) > 1 {
+		return true
+	}
 
 	// Check for units in order of length (longest first) to avoid "ms" being matched as "m"
 	units := []string{"ms", "s", "m", "h", "d", "w", "y"}
@@ -96,3 +117,101 @@ func ValidateDurationString(durationStr string) bool {
 	_, err := strconv.ParseFloat(numPart, 64)
 	return err == nil
 }
+
+// SplitDuration splits a duration literal into its number and unit
+// components. "1h30m" yields 1h and 30m; a simple literal like "30s" yields a
+// single part. Units must be distinct and in decreasing order.
+func SplitDuration(durationStr string) ([]ast.DurationPart, error) {
+	var parts []ast.DurationPart
+	rest := durationStr
+	for rest != "" {
+		i := 0
+		for i < len(rest) && isDurationDigit(rest[i]) {
+			i++
+		}
+		if i < len(rest) && rest[i] == '.' {
+			i++
+			fraction := i
+			for i < len(rest) && isDurationDigit(rest[i]) {
+				i++
+			}
+			if i == fraction {
+				return nil, fmt.Errorf("invalid duration %q: missing digits after '.'", durationStr)
+			}
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("invalid duration %q: expected a number", durationStr)
+		}
+		number := rest[:i]
+		rest = rest[i:]
+
+		j := 0
+		for j < len(rest) && rest[j] >= 'a' && rest[j] <= 'z' {
+			j++
+		}
+		unit := rest[:j]
+		rest = rest[j:]
+		if !IsDurationUnit(unit) {
+			return nil, fmt.Errorf("invalid duration %q: unknown unit %q", durationStr, unit)
+		}
+		if len(parts) > 0 && durationUnitOrder[unit] <= durationUnitOrder[parts[len(parts)-1].Unit] {
+			return nil, fmt.Errorf("invalid duration %q: units must be in decreasing order", durationStr)
+		}
+		parts = append(parts, ast.DurationPart{Number: number, Unit: unit})
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("invalid duration %q", durationStr)
+	}
+	return parts, nil
+}
+
+// NormalizeDuration rewrites a compound duration literal as a single-unit
+// literal that Varnish accepts everywhere, using the largest unit that
+// represents the value exactly: "1h30m" becomes "90m" and "1m30s" becomes
+// "90s". Simple literals are returned unchanged.
+func NormalizeDuration(durationStr string) (string, error) {
+	parts, err := SplitDuration(durationStr)
+	if err != nil {
+		return "", err
+	}
+	if len(parts) == 1 {
+		return durationStr, nil
+	}
+
+	// Work in whole milliseconds when possible so the result is exact
+	total := 0.0
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(part.Number, 64)
+		if err != nil {
+			return "", err
+		}
+		total += value * durationUnits[part.Unit] * 1000
+	}
+	if total == math.Trunc(total) && total < 1<<53 {
+		ms := int64(total)
+		for _, unit := range normalizeUnits {
+			size := int64(durationUnits[unit] * 1000)
+			if ms%size == 0 {
+				return strconv.FormatInt(ms/size, 10) + unit, nil
+			}
+		}
+	}
+	return strconv.FormatFloat(total/1000, 'f', -1, 64) + "s", nil
+}
+
+// durationSeconds sums the parts of a compound duration
+func durationSeconds(parts []ast.DurationPart) (float64, error) {
+	total := 0.0
+	for _, part := range parts {
+		value, err := strconv.ParseFloat(part.Number, 64)
+		if err != nil {
+			return 0, err
+		}
+		total += value * durationUnits[part.Unit]
+	}
+	return total, nil
+}
+
+func isDurationDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
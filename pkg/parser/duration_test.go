@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -67,6 +68,11 @@ func TestParseDuration(t *testing.T) {
 		{"0s", 0, false},
 		{"0.0s", 0, false},
 
+		// Compound durations
+		{"1h30m", 5400, false},
+		{"1m30s", 90, false},
+		{"1s500ms", 1.5, false},
+
 		// Invalid inputs
 		{"", 0, false},       // No unit, returns 0 without error
 		{"10", 0, false},     // No unit, returns 0 without error
@@ -108,6 +114,8 @@ func TestValidateDurationString(t *testing.T) {
 		{"7d", true},
 		{"2w", true},
 		{"1y", true},
+		{"1h30m", true},
+		{"2d12h30m", true},
 
 		// Invalid duration strings
 		{"", false},
@@ -117,6 +125,9 @@ func TestValidateDurationString(t *testing.T) {
 		{"abc", false},
 		{"10.5.5s", false},
 		{"-5s", true}, // Negative durations are valid numbers technically
+		{"30m1h", false},
+		{"1h1h", false},
+		{"1h30", false},
 	}
 
 	for _, test := range tests {
@@ -151,3 +162,75 @@ func TestGetSupportedDurationUnits(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"30s", "30s", false},
+		{"1h30m", "1h 30m", false},
+		{"1.5h30m", "1.5h 30m", false},
+		{"1w2d3h4m5s6ms", "1w 2d 3h 4m 5s 6ms", false},
+		{"", "", true},
+		{"h30m", "", true},
+		{"1h30", "", true},
+		{"1.h", "", true},
+		{"1s1m", "", true},
+		{"1x2s", "", true},
+	}
+
+	for _, test := range tests {
+		parts, err := SplitDuration(test.input)
+		if test.hasError {
+			if err == nil {
+				t.Errorf("SplitDuration(%q) expected error but got %v", test.input, parts)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitDuration(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		var rendered []string
+		for _, part := range parts {
+			rendered = append(rendered, part.Number+part.Unit)
+		}
+		if got := strings.Join(rendered, " "); got != test.expected {
+			t.Errorf("SplitDuration(%q) = %q, expected %q", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestNormalizeDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"30s", "30s"},
+		{"1.5h", "1.5h"},
+		{"1h30m", "90m"},
+		{"1m30s", "90s"},
+		{"1h0m", "1h"},
+		{"1d12h", "36h"},
+		{"1s500ms", "1500ms"},
+		{"1w0d", "1w"},
+		{"1.5m1s", "91s"},
+	}
+
+	for _, test := range tests {
+		result, err := NormalizeDuration(test.input)
+		if err != nil {
+			t.Errorf("NormalizeDuration(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("NormalizeDuration(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+
+	if _, err := NormalizeDuration("30m1h"); err == nil {
+		t.Errorf("NormalizeDuration(%q) expected error but got none", "30m1h")
+	}
+}
@@ -14,6 +14,9 @@ type DetailedError struct {
 	Token    lexer.Token
 	Filename string
 	Source   string // Full VCL source code for context
+	// Suggestion is what the offending token was probably meant to be, such
+	// as return for retrun, or "" if nothing close is known
+	Suggestion string
 }
 
 // Error implements the error interface with rich context formatting.
@@ -45,9 +48,10 @@ func (e DetailedError) Error() string {
 	if errorLine >= 0 && errorLine < len(lines) {
 		result.WriteString(fmt.Sprintf("%3d | %s\n", errorLine+1, lines[errorLine]))
 
-		// Add caret pointer to exact error position
-		spaces := strings.Repeat(" ", 6+e.Position.Column-1) // "nnn | " + column offset
-		result.WriteString(fmt.Sprintf("%s^\n", spaces))
+		// Underline the offending token, or point at the error position
+		column := e.column()
+		spaces := strings.Repeat(" ", 6+column) // "nnn | " + column offset
+		result.WriteString(fmt.Sprintf("%s^%s\n", spaces, strings.Repeat("~", e.underline(lines[errorLine], column)-1)))
 	}
 
 	// Show line after error (if exists)
@@ -57,17 +61,31 @@ func (e DetailedError) Error() string {
 
 	// Add blank line and error message
 	result.WriteString(fmt.Sprintf("\nError: %s\n", e.Message))
+	if e.Suggestion != "" {
+		result.WriteString(fmt.Sprintf("Hint: did you mean %q?\n", e.Suggestion))
+	}
 
 	return result.String()
 }
 
-// ParseError represents a basic parsing error (for backward compatibility)
-type ParseError struct {
-	Message  string
-	Position lexer.Position
-	Token    lexer.Token
+// column returns the 0-based byte column of the error within its line,
+// taken from the offset when it lies in the source
+func (e DetailedError) column() int {
+	offset := e.Position.Offset
+	if offset < 0 || offset > len(e.Source) || (offset == 0 && e.Position.Line > 1) {
+		return max(e.Position.Column-1, 0)
+	}
+	return offset - strings.LastIndexByte(e.Source[:offset], '\n') - 1
 }
 
-func (e ParseError) Error() string {
-	return fmt.Sprintf("parse error at %s: %s (got %s)", e.Position, e.Message, e.Token.Type)
+// underline returns the number of columns of line to mark from column: the
+// width of the offending token when it starts at the error position and
+// fits on the line, otherwise 1
+func (e DetailedError) underline(line string, column int) int {
+	width := len(e.Token.Value)
+	if e.Token.Start != e.Position || width == 0 || strings.Contains(e.Token.Value, "\n") ||
+		column+width > len(line) {
+		return 1
+	}
+	return width
 }
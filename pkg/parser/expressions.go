@@ -94,13 +94,13 @@ func (p *Parser) parseExpression() ast2.Expression {
 // semicolons (statement end), parentheses/braces (grouping end), commas (argument separator).
 func (p *Parser) parseExpressionWithPrecedence(precedence int) ast2.Expression {
 	if p.maxErrorsReached {
-		return &ast2.ErrorExpression{
+		return ast2.New(p.arena, ast2.ErrorExpression{
 			BaseNode: ast2.BaseNode{
 				StartPos: p.currentToken.Start,
 				EndPos:   p.currentToken.End,
 			},
 			Message: "max errors reached",
-		}
+		})
 	}
 
 	if p.panicMode {
@@ -109,28 +109,31 @@ func (p *Parser) parseExpressionWithPrecedence(precedence int) ast2.Expression {
 			lexer.RBRACE, lexer.CAND, lexer.COR,
 		)
 		p.synchronize()
-		return &ast2.ErrorExpression{
+		return ast2.New(p.arena, ast2.ErrorExpression{
 			BaseNode: ast2.BaseNode{
 				StartPos: p.currentToken.Start,
 				EndPos:   p.currentToken.End,
 			},
 			Message: "expression recovery",
-		}
+		})
 	}
 
 	left := p.parsePrefixExpression()
-	if left == nil {
+	if isNilNode(left) {
 		return nil
 	}
 
 	for !p.peekTokenIs(lexer.SEMICOLON) && !p.peekTokenIs(lexer.RPAREN) &&
-		!p.peekTokenIs(lexer.RBRACE) && !p.peekTokenIs(lexer.COMMA) &&
-		precedence < p.peekPrecedence() {
-		if left == nil {
-			break
+		!p.peekTokenIs(lexer.RBRACE) && !p.peekTokenIs(lexer.COMMA) {
+		switch {
+		case precedence < TERM && p.implicitConcatenation():
+			left = p.parseImplicitConcatenation(left)
+		case precedence < p.peekPrecedence():
+			left = p.parseInfixExpression(left)
+		default:
+			return left
 		}
-		left = p.parseInfixExpression(left)
-		if left == nil {
+		if isNilNode(left) {
 			return nil
 		}
 	}
@@ -148,13 +151,20 @@ func (p *Parser) parsePrefixExpression() ast2.Expression {
 		lexer.HIT_KW, lexer.MISS_KW, lexer.DELIVER_KW, lexer.PURGE_KW,
 		lexer.SYNTH_KW, lexer.ABANDON_KW, lexer.RETRY_KW, lexer.OK_KW, lexer.FAIL_KW,
 		lexer.ERROR_KW, lexer.RESTART_KW, lexer.ACL_KW, lexer.LOOKUP_KW, lexer.VCL_KW:
-		return &ast2.Identifier{
+		return ast2.New(p.arena, ast2.Identifier{
 			BaseNode: ast2.BaseNode{
 				StartPos: p.currentToken.Start,
 				EndPos:   p.currentToken.End,
 			},
 			Name: p.currentToken.Value,
+		})
+	case lexer.IF_KW:
+		// Fastly's if(cond, a, b) is a function
+		if !p.fastly() || !p.peekTokenIs(lexer.LPAREN) {
+			p.addError("unexpected token in expression: " + p.currentToken.Type.String())
+			return nil
 		}
+		return p.parseIdentifier()
 	case lexer.CNUM:
 		// Check if this number is followed by a time unit (like "30s")
 		if p.isNumberFollowedByTimeUnit() {
@@ -167,7 +177,7 @@ func (p *Parser) parsePrefixExpression() ast2.Expression {
 			return p.parseTimeExpressionFromNumber()
 		}
 		return p.parseFloatLiteral()
-	case lexer.CSTR:
+	case lexer.CSTR, lexer.LSTR:
 		return p.parseStringLiteral()
 	case lexer.BANG, lexer.MINUS, lexer.PLUS:
 		return p.parseUnaryExpression()
@@ -209,30 +219,35 @@ func (p *Parser) parseInfixExpression(left ast2.Expression) ast2.Expression {
 
 // parseIdentifier parses an identifier
 func (p *Parser) parseIdentifier() *ast2.Identifier {
-	return &ast2.Identifier{
+	return ast2.New(p.arena, ast2.Identifier{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Name: p.currentToken.Value,
-	}
+	})
 }
 
 // parseIntegerLiteral parses an integer literal
 func (p *Parser) parseIntegerLiteral() *ast2.IntegerLiteral {
-	value, err := strconv.ParseInt(p.currentToken.Value, 0, 64)
+	// VCL integers are decimal, even with leading zeros
+	value, err := strconv.ParseInt(p.currentToken.Value, 10, 64)
 	if err != nil {
 		p.addError("could not parse " + p.currentToken.Value + " as integer")
 		return nil
 	}
+	if len(p.currentToken.Value) > 1 && p.currentToken.Value[0] == '0' {
+		p.addWarning("numeric-literal", fmt.Sprintf(
+			"integer %s has a leading zero; VCL reads it as decimal %d, not octal", p.currentToken.Value, value), p.currentToken)
+	}
 
-	return &ast2.IntegerLiteral{
+	return ast2.New(p.arena, ast2.IntegerLiteral{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Value: value,
-	}
+	})
 }
 
 // parseFloatLiteral parses a float literal
@@ -243,37 +258,54 @@ func (p *Parser) parseFloatLiteral() *ast2.FloatLiteral {
 		return nil
 	}
 
-	return &ast2.FloatLiteral{
+	return ast2.New(p.arena, ast2.FloatLiteral{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Value: value,
-	}
+	})
 }
 
 // parseStringLiteral parses a string literal
 func (p *Parser) parseStringLiteral() *ast2.StringLiteral {
+	if p.currentTokenIs(lexer.LSTR) {
+		// Remove the {" "} or """ """ delimiters, keeping quotes inside
+		value := p.currentToken.Value
+		delimiter := 3
+		if strings.HasPrefix(value, "{") {
+			delimiter = 2
+		}
+		return ast2.New(p.arena, ast2.StringLiteral{
+			BaseNode: ast2.BaseNode{
+				StartPos: p.currentToken.Start,
+				EndPos:   p.currentToken.End,
+			},
+			Value: value[delimiter : len(value)-delimiter],
+			Long:  true,
+		})
+	}
+
 	// Remove quotes from string literal
 	value := strings.Trim(p.currentToken.Value, `"`)
 
-	return &ast2.StringLiteral{
+	return ast2.New(p.arena, ast2.StringLiteral{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Value: value,
-	}
+	})
 }
 
 // parseUnaryExpression parses a unary expression
 func (p *Parser) parseUnaryExpression() *ast2.UnaryExpression {
-	expr := &ast2.UnaryExpression{
+	expr := ast2.New(p.arena, ast2.UnaryExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
 		Operator: p.currentToken.Value,
-	}
+	})
 
 	p.nextToken() // move past operator
 	expr.Operand = p.parseExpressionWithPrecedence(UNARY)
@@ -284,11 +316,11 @@ func (p *Parser) parseUnaryExpression() *ast2.UnaryExpression {
 
 // parseGroupedExpression parses a parenthesized expression
 func (p *Parser) parseGroupedExpression() *ast2.ParenthesizedExpression {
-	expr := &ast2.ParenthesizedExpression{
+	expr := ast2.New(p.arena, ast2.ParenthesizedExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move past '('
 	expr.Expression = p.parseExpression()
@@ -308,16 +340,16 @@ func (p *Parser) parseBinaryExpression(left ast2.Expression) *ast2.BinaryExpress
 		return nil
 	}
 
-	expr := &ast2.BinaryExpression{
+	expr := ast2.New(p.arena, ast2.BinaryExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: left.Start(),
 		},
 		Left: left,
-	}
+	})
 
-	precedence := p.currentPrecedence()
 	p.nextToken() // move to operator
 	expr.Operator = p.currentToken.Value
+	precedence := p.currentPrecedence()
 	p.nextToken() // move past operator
 
 	expr.Right = p.parseExpressionWithPrecedence(precedence)
@@ -328,12 +360,12 @@ func (p *Parser) parseBinaryExpression(left ast2.Expression) *ast2.BinaryExpress
 
 // parseRegexMatchExpression parses regex match expressions
 func (p *Parser) parseRegexMatchExpression(left ast2.Expression) *ast2.RegexMatchExpression {
-	expr := &ast2.RegexMatchExpression{
+	expr := ast2.New(p.arena, ast2.RegexMatchExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: left.Start(),
 		},
 		Left: left,
-	}
+	})
 
 	p.nextToken() // move to operator
 	expr.Operator = p.currentToken.Value
@@ -355,13 +387,13 @@ func (p *Parser) parseCallExpression(fn ast2.Expression) *ast2.CallExpression {
 		return nil
 	}
 
-	expr := &ast2.CallExpression{
+	expr := ast2.New(p.arena, ast2.CallExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: fn.Start(),
 		},
 		Function:       fn,
 		NamedArguments: make(map[string]ast2.Expression),
-	}
+	})
 
 	p.nextToken() // move to '('
 
@@ -440,12 +472,12 @@ func (p *Parser) parseCallExpression(fn ast2.Expression) *ast2.CallExpression {
 
 // parseMemberExpression parses member access expressions
 func (p *Parser) parseMemberExpression(obj ast2.Expression) *ast2.MemberExpression {
-	expr := &ast2.MemberExpression{
+	expr := ast2.New(p.arena, ast2.MemberExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: obj.Start(),
 		},
 		Object: obj,
-	}
+	})
 
 	p.nextToken() // move to '.'
 	p.nextToken() // move past '.'
@@ -461,11 +493,11 @@ func (p *Parser) parseMemberExpression(obj ast2.Expression) *ast2.MemberExpressi
 // and complex nested structures. Properties are separated by semicolons following
 // VCL syntax conventions.
 func (p *Parser) parseObjectExpression() *ast2.ObjectExpression {
-	expr := &ast2.ObjectExpression{
+	expr := ast2.New(p.arena, ast2.ObjectExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move past '{'
 
@@ -481,11 +513,11 @@ func (p *Parser) parseObjectExpression() *ast2.ObjectExpression {
 			continue
 		}
 
-		prop := &ast2.Property{
+		prop := ast2.New(p.arena, ast2.Property{
 			BaseNode: ast2.BaseNode{
 				StartPos: p.currentToken.Start,
 			},
-		}
+		})
 
 		// Parse key - in VCL, object properties start with a dot (e.g., .url)
 		if p.currentTokenIs(lexer.DOT) {
@@ -495,13 +527,13 @@ func (p *Parser) parseObjectExpression() *ast2.ObjectExpression {
 				return nil
 			}
 			// Create an identifier for the property name (without the dot)
-			prop.Key = &ast2.Identifier{
+			prop.Key = ast2.New(p.arena, ast2.Identifier{
 				BaseNode: ast2.BaseNode{
 					StartPos: p.currentToken.Start,
 					EndPos:   p.currentToken.End,
 				},
 				Name: p.currentToken.Value,
-			}
+			})
 		} else {
 			// Fallback to parsing as a general expression
 			prop.Key = p.parseExpression()
@@ -538,24 +570,18 @@ func (p *Parser) parseObjectExpression() *ast2.ObjectExpression {
 
 // parseTimeExpression parses time/duration expressions
 func (p *Parser) parseTimeExpression() *ast2.TimeExpression {
-	return &ast2.TimeExpression{
-		BaseNode: ast2.BaseNode{
-			StartPos: p.currentToken.Start,
-			EndPos:   p.currentToken.End,
-		},
-		Value: p.currentToken.Value,
-	}
+	return p.newTimeExpression(p.currentToken.Start, p.currentToken.End, p.currentToken.Value)
 }
 
 // parseIPExpression parses IP address expressions
 func (p *Parser) parseIPExpression() *ast2.IPExpression {
-	return &ast2.IPExpression{
+	return ast2.New(p.arena, ast2.IPExpression{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Value: p.currentToken.Value,
-	}
+	})
 }
 
 // Helper functions to detect literal types
@@ -573,7 +599,23 @@ func (p *Parser) isNumberFollowedByTimeUnit() bool {
 	}
 
 	// Use the new duration validation utility
-	return IsDurationUnit(p.peekToken.Value)
+	if IsDurationUnit(p.peekToken.Value) {
+		return true
+	}
+
+	// Compound durations like "1h30m" lex as the number "1" followed by the
+	// identifier "h30m"
+	return isCompoundDurationTail(p.peekToken.Value)
+}
+
+// isCompoundDurationTail reports whether an identifier directly following a
+// number starts like the rest of a compound duration: a unit and a digit
+func isCompoundDurationTail(value string) bool {
+	i := 0
+	for i < len(value) && value[i] >= 'a' && value[i] <= 'z' {
+		i++
+	}
+	return i < len(value) && IsDurationUnit(value[:i]) && isDurationDigit(value[i])
 }
 
 // parseTimeExpressionFromNumber parses time expressions from number + unit (e.g., "30" + "s")
@@ -585,13 +627,52 @@ func (p *Parser) parseTimeExpressionFromNumber() *ast2.TimeExpression {
 	unitValue := p.currentToken.Value
 	endPos := p.currentToken.End
 
-	return &ast2.TimeExpression{
+	if !IsDurationUnit(unitValue) {
+		if _, err := SplitDuration(numberValue + unitValue); err != nil {
+			p.addError(err.Error())
+		}
+	}
+
+	// "1h 30m" would otherwise end the expression after 1h and leave 30m
+	// as a statement of its own
+	value := numberValue + unitValue
+	spaced := value
+	for p.peekTokenIs(lexer.CNUM) || p.peekTokenIs(lexer.FNUM) {
+		p.nextToken() // move to the number
+		part := p.currentToken.Value
+		if p.peekTokenIs(lexer.ID) && (IsDurationUnit(p.peekToken.Value) || isCompoundDurationTail(p.peekToken.Value)) {
+			p.nextToken() // move to the unit
+			part += p.currentToken.Value
+		}
+		value += part
+		spaced += " " + part
+		endPos = p.currentToken.End
+	}
+	if spaced != value {
+		message := fmt.Sprintf("duration %q must be written without spaces", spaced)
+		if _, err := SplitDuration(value); err == nil {
+			message += ", as " + value
+		}
+		p.addError(message)
+	}
+
+	return p.newTimeExpression(startPos, endPos, value) // combine "30" + "s" = "30s"
+}
+
+// newTimeExpression builds a TimeExpression, splitting compound literals
+// into their parts
+func (p *Parser) newTimeExpression(start, end lexer.Position, value string) *ast2.TimeExpression {
+	expr := ast2.New(p.arena, ast2.TimeExpression{
 		BaseNode: ast2.BaseNode{
-			StartPos: startPos,
-			EndPos:   endPos,
+			StartPos: start,
+			EndPos:   end,
 		},
-		Value: numberValue + unitValue, // combine "30" + "s" = "30s"
+		Value: value,
+	})
+	if parts, err := SplitDuration(value); err == nil && len(parts) > 1 {
+		expr.Parts = parts
 	}
+	return expr
 }
 
 // isTimeOrDurationLiteral checks if current token looks like a time/duration literal
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	ast2 "github.com/perbu/vclparser/pkg/ast"
@@ -306,6 +307,24 @@ func TestDurationParsing(t *testing.T) {
 			expected: "2.5m",
 			wantErr:  false,
 		},
+		{
+			name:     "compound hours and minutes",
+			input:    `vcl 4.0; sub test { set req.ttl = 1h30m; }`,
+			expected: "1h30m",
+			wantErr:  false,
+		},
+		{
+			name:     "compound minutes and seconds",
+			input:    `vcl 4.0; sub test { set req.ttl = 1m30s; }`,
+			expected: "1m30s",
+			wantErr:  false,
+		},
+		{
+			name:     "compound with units out of order",
+			input:    `vcl 4.0; sub test { set req.ttl = 30m1h; }`,
+			expected: "",
+			wantErr:  true,
+		},
 	}
 
 	for _, test := range tests {
@@ -341,6 +360,58 @@ func TestDurationParsing(t *testing.T) {
 	}
 }
 
+func TestCompoundDurationParts(t *testing.T) {
+	input := `vcl 4.1; sub vcl_backend_response { set beresp.ttl = 1d12h; set beresp.grace = 10s; }`
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	sub := program.Declarations[0].(*ast2.SubDecl)
+	compound := sub.Body.Statements[0].(*ast2.SetStatement).Value.(*ast2.TimeExpression)
+	if !compound.IsCompound() {
+		t.Fatalf("Expected 1d12h to be compound")
+	}
+	want := []ast2.DurationPart{{Number: "1", Unit: "d"}, {Number: "12", Unit: "h"}}
+	if len(compound.Parts) != len(want) || compound.Parts[0] != want[0] || compound.Parts[1] != want[1] {
+		t.Errorf("Expected parts %v, got %v", want, compound.Parts)
+	}
+
+	simple := sub.Body.Statements[1].(*ast2.SetStatement).Value.(*ast2.TimeExpression)
+	if simple.IsCompound() || simple.Parts != nil {
+		t.Errorf("Expected 10s to be a simple literal, got parts %v", simple.Parts)
+	}
+}
+
+func TestSpacedDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{`set beresp.ttl = 1h 30m;`, `duration "1h 30m" must be written without spaces, as 1h30m`},
+		{`set beresp.ttl = 1d 2h 30m;`, `duration "1d 2h 30m" must be written without spaces, as 1d2h30m`},
+		{`set beresp.ttl = 30m 1h;`, `duration "30m 1h" must be written without spaces`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			input := "vcl 4.1; sub vcl_backend_response { " + tt.input + " }"
+			l := lexer.New(input, "test.vcl")
+			p := New(l, input, "test.vcl")
+			program := p.ParseProgram()
+
+			if len(p.errors) != 1 || !strings.Contains(p.errors[0].Message, tt.message) {
+				t.Fatalf("Expected one error containing %q, got %v", tt.message, p.errors)
+			}
+			// The whole duration is one expression, not 1h followed by a
+			// statement 30m
+			sub := program.Declarations[0].(*ast2.SubDecl)
+			if len(sub.Body.Statements) != 1 {
+				t.Errorf("Expected one statement, got %d", len(sub.Body.Statements))
+			}
+		})
+	}
+}
+
 func TestDurationInFunctionCalls(t *testing.T) {
 	// Test that durations work correctly when passed as function arguments
 	tests := []struct {
@@ -429,3 +500,36 @@ func TestDurationInFunctionCalls(t *testing.T) {
 		})
 	}
 }
+
+func TestBinaryOperatorPrecedence(t *testing.T) {
+	input := `vcl 4.1;
+sub vcl_recv {
+	if (req.method == "GET" || req.method == "HEAD" && req.url == "/") {
+		return (hash);
+	}
+}`
+
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	sub := program.Declarations[0].(*ast2.SubDecl)
+	cond := sub.Body.Statements[0].(*ast2.IfStatement).Condition
+
+	or, ok := cond.(*ast2.BinaryExpression)
+	if !ok || or.Operator != "||" {
+		t.Fatalf("top-level operator should be ||, got %T %v", cond, cond)
+	}
+	left, ok := or.Left.(*ast2.BinaryExpression)
+	if !ok || left.Operator != "==" {
+		t.Errorf("left of || should be ==, got %T %v", or.Left, or.Left)
+	}
+	right, ok := or.Right.(*ast2.BinaryExpression)
+	if !ok || right.Operator != "&&" {
+		t.Fatalf("right of || should be &&, got %T %v", or.Right, or.Right)
+	}
+	if inner, ok := right.Left.(*ast2.BinaryExpression); !ok || inner.Operator != "==" {
+		t.Errorf("left of && should be ==, got %T %v", right.Left, right.Left)
+	}
+}
@@ -0,0 +1,446 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// The Fastly dialect, selected with Config.Dialect, accepts the VCL of the
+// Fastly CDN so that its configurations can be read and partially checked,
+// for instance while moving them to Varnish. On top of the VCL it shares
+// with Varnish it parses:
+//
+//   - programs without a vcl version declaration
+//   - table name [TYPE] { "key": value, ... } into ast.TableDecl
+//   - director name policy { .quorum = 50%; { .backend = F; } } into
+//     ast.DirectorDecl
+//   - declare local var.name TYPE; add, esi; log, goto and labels into the
+//     statements of the same names
+//   - error 503 "reason"; into an ast.ErrorStatement
+//   - remove, which is unset
+//   - typed subroutines, sub name STRING { return expr; }
+//   - header subfields, req.http.Cookie:name, and if(cond, a, b)
+//   - strings concatenated without +
+//
+// penaltybox and ratecounter declarations are not supported.
+
+// fastly reports whether the parser reads the Fastly dialect
+func (p *Parser) fastly() bool {
+	return p.config.Dialect == lexer.DialectFastly
+}
+
+// parseFastlyDeclaration parses the declarations only Fastly knows. It
+// returns false if the current token starts none of them.
+func (p *Parser) parseFastlyDeclaration() (ast.Declaration, bool) {
+	if !p.currentTokenIs(lexer.ID) {
+		return nil, false
+	}
+	switch p.currentToken.Value {
+	case "table":
+		return p.parseTableDecl(), true
+	case "director":
+		return p.parseDirectorDecl(), true
+	case "penaltybox", "ratecounter":
+		p.reportError(fmt.Sprintf("Fastly %s declarations are not supported", p.currentToken.Value))
+		return nil, true
+	}
+	return nil, false
+}
+
+// parseTableDecl parses a table declaration
+func (p *Parser) parseTableDecl() *ast.TableDecl {
+	decl := ast.New(p.arena, ast.TableDecl{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	if !p.expectPeek(lexer.ID) {
+		return nil
+	}
+	decl.Name = p.currentToken.Value
+	decl.NamePos = p.currentToken.Start
+
+	if p.peekTokenIs(lexer.ID) || p.peekTokenIs(lexer.ACL_KW) {
+		p.nextToken()
+		decl.ValueType = p.currentToken.Value
+		p.checkFastlyType()
+	}
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+	p.nextToken() // move past '{'
+
+	for !p.currentTokenIs(lexer.RBRACE) && !p.currentTokenIs(lexer.EOF) {
+		entry := p.parseTableEntry()
+		if entry == nil {
+			p.skipToSynchronizationPoint(lexer.COMMA, lexer.RBRACE)
+		} else {
+			decl.Entries = append(decl.Entries, entry)
+		}
+		if p.currentTokenIs(lexer.COMMA) {
+			p.nextToken()
+		}
+	}
+
+	if !p.expectToken(lexer.RBRACE) {
+		return nil
+	}
+	decl.EndPos = p.currentToken.End
+	return decl
+}
+
+// parseTableEntry parses a "key": value entry, leaving the current token
+// on the ',' or '}' after it
+func (p *Parser) parseTableEntry() *ast.TableEntry {
+	if !p.currentTokenIs(lexer.CSTR) && !p.currentTokenIs(lexer.LSTR) {
+		p.addError(fmt.Sprintf("expected a quoted table key, got %s", p.currentToken.Type))
+		return nil
+	}
+	entry := ast.New(p.arena, ast.TableEntry{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+		Key: p.parseStringLiteral().Value,
+	})
+
+	if !p.expectPeek(lexer.COLON) {
+		return nil
+	}
+	p.nextToken() // move to the value
+	entry.Value = p.parseExpression()
+	if isNilNode(entry.Value) {
+		return nil
+	}
+	entry.EndPos = p.currentToken.End
+
+	if !p.peekTokenIs(lexer.COMMA) && !p.peekTokenIs(lexer.RBRACE) {
+		p.addPeekError(fmt.Sprintf("expected ',' or '}' after table entry, got %s", p.peekToken.Type))
+		return nil
+	}
+	p.nextToken()
+	return entry
+}
+
+// parseDirectorDecl parses a director declaration
+func (p *Parser) parseDirectorDecl() *ast.DirectorDecl {
+	decl := ast.New(p.arena, ast.DirectorDecl{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	if !p.expectPeek(lexer.ID) {
+		return nil
+	}
+	decl.Name = p.currentToken.Value
+	decl.NamePos = p.currentToken.Start
+
+	if !p.expectPeek(lexer.ID) {
+		return nil
+	}
+	decl.Policy = p.currentToken.Value
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+	p.nextToken() // move past '{'
+
+	for !p.currentTokenIs(lexer.RBRACE) && !p.currentTokenIs(lexer.EOF) {
+		start := p.currentToken.Start.Offset
+		switch p.currentToken.Type {
+		case lexer.DOT:
+			if prop := p.parseDirectorProperty(); prop != nil {
+				decl.Properties = append(decl.Properties, prop)
+			}
+		case lexer.LBRACE:
+			if backend := p.parseDirectorBackend(); backend != nil {
+				decl.Backends = append(decl.Backends, backend)
+			}
+		default:
+			p.addError(fmt.Sprintf("expected a director property or backend, got %s", p.currentToken.Type))
+		}
+		if p.currentToken.Start.Offset == start {
+			p.skipToSynchronizationPoint(lexer.DOT, lexer.LBRACE, lexer.RBRACE)
+			if p.currentToken.Start.Offset == start {
+				p.nextToken()
+			}
+		}
+	}
+
+	if !p.expectToken(lexer.RBRACE) {
+		return nil
+	}
+	decl.EndPos = p.currentToken.End
+	return decl
+}
+
+// parseDirectorBackend parses a { .backend = F_origin; ... } member of a
+// director, moving past its closing brace
+func (p *Parser) parseDirectorBackend() *ast.DirectorBackend {
+	backend := ast.New(p.arena, ast.DirectorBackend{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+	p.nextToken() // move past '{'
+
+	for p.currentTokenIs(lexer.DOT) {
+		prop := p.parseDirectorProperty()
+		if prop == nil {
+			return nil
+		}
+		backend.Properties = append(backend.Properties, prop)
+	}
+
+	if !p.expectToken(lexer.RBRACE) {
+		return nil
+	}
+	backend.EndPos = p.currentToken.End
+	p.nextToken() // move past '}'
+	return backend
+}
+
+// parseDirectorProperty parses a .name = value; property of a director or
+// one of its backends, moving past the semicolon. A percentage, as in
+// .quorum = 50%;, becomes an Identifier holding the text.
+func (p *Parser) parseDirectorProperty() *ast.BackendProperty {
+	prop := ast.New(p.arena, ast.BackendProperty{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	p.nextToken() // move to the property name, which may be a keyword
+	if !p.currentTokenIs(lexer.ID) && !p.currentToken.Type.IsKeyword() {
+		p.addError("expected property name after '.'")
+		return nil
+	}
+	prop.Name = p.currentToken.Value
+	if !p.expectPeek(lexer.ASSIGN) {
+		return nil
+	}
+	p.nextToken() // move to the value
+
+	if p.currentTokenIs(lexer.CNUM) && p.peekTokenIs(lexer.PERCENT) {
+		number := p.currentToken
+		p.nextToken()
+		prop.Value = ast.New(p.arena, ast.Identifier{
+			BaseNode: ast.BaseNode{StartPos: number.Start, EndPos: p.currentToken.End},
+			Name:     number.Value + "%",
+		})
+	} else {
+		prop.Value = p.parseExpression()
+		if isNilNode(prop.Value) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.SEMICOLON) {
+		return nil
+	}
+	prop.EndPos = p.currentToken.End
+	p.nextToken() // move past ';'
+	return prop
+}
+
+// parseSubReturnType parses the type of a typed Fastly subroutine, which
+// follows its name
+func (p *Parser) parseSubReturnType(decl *ast.SubDecl) {
+	if p.fastly() && p.peekTokenIs(lexer.ID) {
+		p.nextToken()
+		decl.ReturnType = p.currentToken.Value
+		p.checkFastlyType()
+	}
+}
+
+// parseFastlyStatement parses the statements only Fastly knows. It returns
+// false if the current token starts none of them.
+func (p *Parser) parseFastlyStatement() (ast.Statement, bool) {
+	switch {
+	case p.currentTokenIs(lexer.ERROR_KW) && !p.peekTokenIs(lexer.LPAREN):
+		return p.parseFastlyErrorStatement(), true
+	case p.currentTokenIs(lexer.RETURN_KW) && !p.peekTokenIs(lexer.LPAREN) && !p.peekTokenIs(lexer.SEMICOLON):
+		return p.parseFastlyReturnStatement(), true
+	case !p.currentTokenIs(lexer.ID):
+		return nil, false
+	case p.peekTokenIs(lexer.COLON):
+		stmt := ast.New(p.arena, ast.LabelStatement{
+			BaseNode: ast.BaseNode{StartPos: p.currentToken.Start},
+			Name:     p.currentToken.Value,
+		})
+		p.nextToken() // move to ':'
+		stmt.EndPos = p.currentToken.End
+		return stmt, true
+	}
+
+	switch p.currentToken.Value {
+	case "declare":
+		return p.parseDeclareStatement(), true
+	case "add":
+		return p.parseAddStatement(), true
+	case "esi":
+		stmt := ast.New(p.arena, ast.EsiStatement{
+			BaseNode: ast.BaseNode{StartPos: p.currentToken.Start, EndPos: p.currentToken.End},
+		})
+		p.skipSemicolon()
+		stmt.EndPos = p.currentToken.End
+		return stmt, true
+	case "log":
+		return p.parseLogStatement(), true
+	case "goto":
+		stmt := ast.New(p.arena, ast.GotoStatement{
+			BaseNode: ast.BaseNode{StartPos: p.currentToken.Start},
+		})
+		if !p.expectPeek(lexer.ID) {
+			return nil, true
+		}
+		stmt.Label = p.currentToken.Value
+		p.skipSemicolon()
+		stmt.EndPos = p.currentToken.End
+		return stmt, true
+	case "remove":
+		return p.parseUnsetStatement(), true
+	}
+	return nil, false
+}
+
+// parseDeclareStatement parses declare local var.name TYPE;
+func (p *Parser) parseDeclareStatement() *ast.DeclareStatement {
+	stmt := ast.New(p.arena, ast.DeclareStatement{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	if !p.expectPeek(lexer.ID) {
+		return nil
+	}
+	if p.currentToken.Value != "local" {
+		p.addError(fmt.Sprintf("expected 'local' after 'declare', got %q", p.currentToken.Value))
+		return nil
+	}
+	p.nextToken() // move past 'local'
+	stmt.Variable = p.parseExpression()
+	if isNilNode(stmt.Variable) {
+		return nil
+	}
+	if name := ast.VariableName(stmt.Variable); len(name) < 5 || name[:4] != "var." {
+		p.addError("local variable names must start with var.")
+		return nil
+	}
+	if !p.expectPeek(lexer.ID) {
+		return nil
+	}
+	stmt.Type = p.currentToken.Value
+	p.checkFastlyType()
+	p.skipSemicolon()
+	stmt.EndPos = p.currentToken.End
+	return stmt
+}
+
+// parseAddStatement parses add req.http.Name = value;
+func (p *Parser) parseAddStatement() *ast.AddStatement {
+	stmt := ast.New(p.arena, ast.AddStatement{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	p.nextToken() // move past 'add'
+	stmt.Variable = p.parseExpression()
+	if isNilNode(stmt.Variable) || !p.expectPeek(lexer.ASSIGN) {
+		return nil
+	}
+	p.nextToken() // move to the value
+	stmt.Value = p.parseExpression()
+	if isNilNode(stmt.Value) {
+		return nil
+	}
+	p.skipSemicolon()
+	stmt.EndPos = p.currentToken.End
+	return stmt
+}
+
+// parseLogStatement parses log message;
+func (p *Parser) parseLogStatement() *ast.LogStatement {
+	stmt := ast.New(p.arena, ast.LogStatement{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	p.nextToken() // move past 'log'
+	stmt.Message = p.parseExpression()
+	if isNilNode(stmt.Message) {
+		return nil
+	}
+	p.skipSemicolon()
+	stmt.EndPos = p.currentToken.End
+	return stmt
+}
+
+// parseFastlyErrorStatement parses error with an optional status code and
+// reason written without parentheses, as in error 503 "Unavailable";
+func (p *Parser) parseFastlyErrorStatement() *ast.ErrorStatement {
+	stmt := ast.New(p.arena, ast.ErrorStatement{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	if !p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken() // move past 'error'
+		stmt.Code = p.parsePrefixExpression()
+		if isNilNode(stmt.Code) {
+			return nil
+		}
+		if !p.peekTokenIs(lexer.SEMICOLON) && !p.peekTokenIs(lexer.RBRACE) {
+			p.nextToken()
+			stmt.Response = p.parseExpression()
+			if isNilNode(stmt.Response) {
+				return nil
+			}
+		}
+	}
+
+	stmt.EndPos = p.currentToken.End
+	p.skipSemicolon()
+	return stmt
+}
+
+// parseFastlyReturnStatement parses the return of a typed subroutine, whose
+// value needs no parentheses
+func (p *Parser) parseFastlyReturnStatement() *ast.ReturnStatement {
+	stmt := ast.New(p.arena, ast.ReturnStatement{
+		BaseNode: ast.BaseNode{
+			StartPos: p.currentToken.Start,
+		},
+	})
+
+	p.nextToken() // move past 'return'
+	stmt.Action = p.parseExpression()
+	if isNilNode(stmt.Action) {
+		return nil
+	}
+	stmt.EndPos = p.currentToken.End
+	p.skipSemicolon()
+	return stmt
+}
+
+// fastlyTypes are the value types of typed subroutines, local variables and
+// tables
+var fastlyTypes = map[string]bool{
+	"ACL": true, "BACKEND": true, "BOOL": true, "FLOAT": true, "INTEGER": true,
+	"IP": true, "RTIME": true, "STRING": true, "TIME": true,
+}
+
+// checkFastlyType warns about a current token that names no Fastly type
+func (p *Parser) checkFastlyType() {
+	if !fastlyTypes[p.currentToken.Value] {
+		p.addWarning("unknown-type", "unknown Fastly type "+strconv.Quote(p.currentToken.Value), p.currentToken)
+	}
+}
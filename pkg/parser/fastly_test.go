@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+const fastlyVCL = `backend F_origin {
+	.host = "origin.example.com";
+	.port = "443";
+}
+
+table redirects {
+	"/old": "/new",
+	"/a": "/b",
+}
+
+director pool random {
+	.quorum = 50%;
+	{ .backend = F_origin; .weight = 1; }
+}
+
+sub normalize STRING {
+	declare local var.host STRING;
+	set var.host = std.tolower(req.http.host);
+	return var.host;
+}
+
+sub vcl_recv {
+#FASTLY recv
+	if (table.lookup(redirects, req.url.path)) {
+		error 801 "redirect";
+	}
+	set req.http.X-Session = req.http.Cookie:session;
+	set req.http.X-Host = if(req.http.host, req.http.host, "none");
+	add req.http.X-Added = "host=" req.http.host;
+	remove req.http.X-Debug;
+	log "syslog " req.service_id " logger :: " req.url;
+	goto done;
+	done:
+	return(lookup);
+}
+
+sub vcl_deliver {
+	esi;
+}
+`
+
+func fastlyConfig() *Config {
+	config := DefaultConfig()
+	config.Dialect = lexer.DialectFastly
+	return config
+}
+
+func TestFastly(t *testing.T) {
+	if _, err := Parse(fastlyVCL, "main.vcl"); err == nil {
+		t.Fatal("Expected Fastly VCL to fail in the Varnish dialect")
+	}
+
+	result := ParseDetailed(fastlyVCL, "main.vcl", fastlyConfig())
+	if len(result.Errors) > 0 {
+		t.Fatalf("Fastly parse failed: %v", result.Errors[0])
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", result.Warnings)
+	}
+
+	decls := result.Program.Declarations
+	table := decls[1].(*ast.TableDecl)
+	if table.Name != "redirects" || len(table.Entries) != 2 || table.Entries[1].Key != "/a" {
+		t.Errorf("Unexpected table %+v", table)
+	}
+	director := decls[2].(*ast.DirectorDecl)
+	if director.Policy != "random" || len(director.Backends) != 1 || len(director.Backends[0].Properties) != 2 {
+		t.Errorf("Unexpected director %+v", director)
+	}
+	if quorum := director.Properties[0].Value.(*ast.Identifier).Name; quorum != "50%" {
+		t.Errorf("Expected a quorum of 50%%, got %s", quorum)
+	}
+
+	normalize := decls[3].(*ast.SubDecl)
+	if normalize.ReturnType != "STRING" {
+		t.Errorf("Expected a STRING subroutine, got %q", normalize.ReturnType)
+	}
+	if declare := normalize.Body.Statements[0].(*ast.DeclareStatement); ast.VariableName(declare.Variable) != "var.host" || declare.Type != "STRING" {
+		t.Errorf("Unexpected declaration %+v", declare)
+	}
+	if ret := normalize.Body.Statements[2].(*ast.ReturnStatement); ast.VariableName(ret.Action) != "var.host" {
+		t.Errorf("Expected return var.host, got %+v", ret.Action)
+	}
+
+	recv := decls[4].(*ast.SubDecl).Body.Statements
+	if e := recv[0].(*ast.IfStatement).Then.(*ast.BlockStatement).Statements[0].(*ast.ErrorStatement); e.Code == nil || e.Response == nil {
+		t.Errorf("Expected error with code and reason, got %+v", e)
+	}
+	if name := ast.VariableName(recv[1].(*ast.SetStatement).Value); name != "req.http.Cookie:session" {
+		t.Errorf("Expected a header subfield, got %q", name)
+	}
+	if call := recv[2].(*ast.SetStatement).Value.(*ast.CallExpression); ast.VariableName(call.Function) != "if" || len(call.Arguments) != 3 {
+		t.Errorf("Expected if(), got %+v", call)
+	}
+	if add := recv[3].(*ast.AddStatement); add.Value.(*ast.BinaryExpression).Operator != "+" {
+		t.Errorf("Expected a concatenation, got %+v", add.Value)
+	}
+	for i, want := range []string{"UnsetStatement", "LogStatement", "GotoStatement(done)", "LabelStatement(done)", "ReturnStatement"} {
+		if got := recv[4+i].(interface{ String() string }).String(); got != want {
+			t.Errorf("Statement %d: expected %s, got %s", 4+i, want, got)
+		}
+	}
+	if _, ok := decls[5].(*ast.SubDecl).Body.Statements[0].(*ast.EsiStatement); !ok {
+		t.Errorf("Expected esi, got %T", decls[5].(*ast.SubDecl).Body.Statements[0])
+	}
+}
+
+func TestFastlyErrors(t *testing.T) {
+	tests := []struct {
+		name, src string
+	}{
+		{"table key", "table t { foo: \"bar\" }\n"},
+		{"table separator", "table t { \"a\" \"b\" }\n"},
+		{"declare", "sub vcl_recv { declare var.x STRING; }\n"},
+		{"local name", "sub vcl_recv { declare local req.x STRING; }\n"},
+		{"penaltybox", "penaltybox pb {}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseDetailed(tt.src, "main.vcl", fastlyConfig())
+			if len(result.Errors) == 0 {
+				t.Errorf("Expected an error for %q", tt.src)
+			}
+		})
+	}
+
+	result := ParseDetailed("sub f NUMBER { return 1; }\n", "main.vcl", fastlyConfig())
+	if len(result.Errors) != 0 || len(result.Warnings) != 1 || result.Warnings[0].Code != "unknown-type" {
+		t.Errorf("Expected a warning about the type, got %v %v", result.Errors, result.Warnings)
+	}
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+func FuzzParse(f *testing.F) {
+	files, _ := filepath.Glob("../../tests/testdata/*.vcl")
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(string(data))
+		}
+	}
+	f.Add("vcl 4.1;\nsub vcl_recv { if (req.url ~ \"x\" && !(a || b)) { set req.http.x = std.tolower(req.url, y = 1); } }")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		p := NewWithConfig(lexer.New(input, "fuzz.vcl"), input, "fuzz.vcl", &Config{MaxErrors: 0})
+		program := p.ParseProgram()
+		for _, err := range p.Errors() {
+			_ = err.Error()
+		}
+		// Tools such as the language server take the positions of every
+		// node of partial trees
+		ast.Apply(program, func(c *ast.Cursor) bool {
+			if node := c.Node(); node != nil {
+				_, _ = node.Start(), node.End()
+			}
+			return true
+		}, nil)
+	})
+}
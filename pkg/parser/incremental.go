@@ -0,0 +1,300 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/types"
+)
+
+// Edit replaces the bytes from Start up to End of a document's source with
+// Text. Start and End are byte offsets.
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Changes describes what an update of a Document parsed anew
+type Changes struct {
+	// Declarations holds the indexes into Program.Declarations of the
+	// declarations that were parsed anew. All other declarations are the
+	// nodes of the previous parse, with positions adjusted to the new source.
+	Declarations []int
+	// Full reports that the whole source was reparsed, which happens when the
+	// edit touches the VCL version declaration, the previous parse had errors,
+	// or the edit changes which subroutines are defined before later
+	// declarations.
+	Full bool
+}
+
+// Document is a parsed VCL source that can be updated incrementally, as an
+// editor does on every keystroke. Only the declarations touched by a change
+// are parsed again; the result is the same as parsing the new source from
+// scratch.
+type Document struct {
+	Filename string
+	Source   string
+	Program  *ast.Program
+	Errors   []DetailedError
+
+	config *Config
+}
+
+// NewDocument parses input into a Document. A nil config selects the
+// default configuration. Documents do not use arenas, as declarations are
+// kept from one parse to the next.
+func NewDocument(input, filename string, config *Config) *Document {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.Arena {
+		withoutArena := *config
+		withoutArena.Arena = false
+		config = &withoutArena
+	}
+	d := &Document{Filename: filename, config: config}
+	d.parseAll(input)
+	return d
+}
+
+// NewDocumentFromResult makes a Document of result, the outcome of parsing
+// input with config, without parsing it again, as for a result kept by a
+// cache. The document takes over result.Program, which must not be shared:
+// updates change it in place.
+func NewDocumentFromResult(result *ParseResult, input, filename string, config *Config) *Document {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.Arena {
+		withoutArena := *config
+		withoutArena.Arena = false
+		config = &withoutArena
+	}
+	return &Document{
+		Filename: filename,
+		Source:   input,
+		Program:  result.Program,
+		Errors:   result.Errors,
+		config:   config,
+	}
+}
+
+// Update applies edits in order, each relative to the source left by the
+// previous one, and reparses the affected declarations
+func (d *Document) Update(edits ...Edit) (Changes, error) {
+	source := d.Source
+	for _, e := range edits {
+		if e.Start < 0 || e.Start > e.End || e.End > len(source) {
+			return Changes{}, fmt.Errorf("edit %d-%d is outside the document (length %d)", e.Start, e.End, len(source))
+		}
+		source = source[:e.Start] + e.Text + source[e.End:]
+	}
+	return d.Replace(source), nil
+}
+
+// Replace sets a new source, reparsing only the declarations in the part
+// that differs from the previous one.
+//
+// Declarations that are reused are moved into the new Program and their
+// positions are updated in place, so nodes of the previous Program must not
+// be used afterwards.
+func (d *Document) Replace(source string) Changes {
+	if source == d.Source {
+		return Changes{}
+	}
+	if changes, ok := d.reparse(source); ok {
+		return changes
+	}
+
+	d.parseAll(source)
+	changes := Changes{Full: true, Declarations: make([]int, len(d.Program.Declarations))}
+	for i := range changes.Declarations {
+		changes.Declarations[i] = i
+	}
+	return changes
+}
+
+func (d *Document) parseAll(source string) {
+	p := NewWithConfig(lexer.New(source, d.Filename), source, d.Filename, d.config)
+	d.Source = source
+	d.Program = p.ParseProgram()
+	d.Errors = p.Errors()
+}
+
+// reparse parses the declarations overlapping the changed part of the
+// source. It reports false when the change cannot be handled locally.
+//
+// Declaration i owns the source from its start up to the start of the next
+// one, including any comments in between. The changed part of the old
+// source lies between the common prefix and suffix of both versions.
+func (d *Document) reparse(source string) (Changes, bool) {
+	old := d.Source
+	decls := d.Program.Declarations
+	if len(d.Errors) > 0 || d.Program.VCLVersion == nil || len(decls) == 0 {
+		return Changes{}, false
+	}
+
+	prefix := commonPrefix(old, source)
+	suffix := commonSuffix(old[prefix:], source[prefix:])
+	changedEnd := len(old) - suffix
+
+	if prefix < decls[0].Start().Offset {
+		// The change touches the version declaration or what precedes the
+		// first declaration
+		return Changes{}, false
+	}
+
+	// first and last are the indexes of the old declarations to replace
+	first, last := 0, 0
+	for i, decl := range decls {
+		if decl.Start().Offset <= prefix {
+			first = i
+		}
+		if decl.Start().Offset < changedEnd {
+			last = i
+		}
+	}
+	last = max(first, last)
+
+	regionStart := decls[first].Start()
+	regionEnd := len(old)
+	if last+1 < len(decls) {
+		regionEnd = decls[last+1].Start().Offset
+		// Declarations after the region keep their columns only if it ends
+		// at the start of a line
+		if old[regionEnd-1] != '\n' {
+			return Changes{}, false
+		}
+	}
+	delta := len(source) - len(old)
+
+	p := NewWithConfig(lexer.NewAt(source, d.Filename, regionStart), source, d.Filename, d.config)
+	for _, decl := range decls[:first] {
+		if sub, ok := decl.(*ast.SubDecl); ok {
+			_ = p.symbolTable.Define(&types.Symbol{Name: sub.Name, Kind: types.SymbolSubroutine, Type: types.Void})
+		}
+	}
+	parsed := p.parseDeclarations(regionEnd + delta)
+
+	if last+1 < len(decls) {
+		// The region must end where the next reused declaration starts, and
+		// later declarations must follow exactly the same subroutines as before
+		if p.currentTokenIs(lexer.EOF) || p.currentToken.Start.Offset != regionEnd+delta {
+			return Changes{}, false
+		}
+		if !sameSubNames(decls[first:last+1], parsed) {
+			return Changes{}, false
+		}
+	} else if !p.currentTokenIs(lexer.EOF) {
+		return Changes{}, false
+	}
+
+	lineDelta := strings.Count(source[regionStart.Offset:regionEnd+delta], "\n") -
+		strings.Count(old[regionStart.Offset:regionEnd], "\n")
+	reused := decls[last+1:]
+	for _, decl := range reused {
+		shiftPositions(reflect.ValueOf(decl), lineDelta, delta)
+	}
+
+	program := &ast.Program{
+		BaseNode:     d.Program.BaseNode,
+		VCLVersion:   d.Program.VCLVersion,
+		Declarations: make([]ast.Declaration, 0, first+len(parsed)+len(reused)),
+	}
+	program.Declarations = append(program.Declarations, decls[:first]...)
+	program.Declarations = append(program.Declarations, parsed...)
+	program.Declarations = append(program.Declarations, reused...)
+	if len(reused) > 0 {
+		program.EndPos.Line += lineDelta
+		program.EndPos.Offset += delta
+	} else {
+		program.EndPos = p.currentToken.End
+	}
+
+	changes := Changes{Declarations: make([]int, len(parsed))}
+	for i := range parsed {
+		changes.Declarations[i] = first + i
+	}
+
+	d.Source = source
+	d.Program = program
+	d.Errors = p.Errors()
+	return changes, true
+}
+
+func commonPrefix(a, b string) int {
+	n := min(len(a), len(b))
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+func commonSuffix(a, b string) int {
+	n := min(len(a), len(b))
+	i := 0
+	for i < n && a[len(a)-1-i] == b[len(b)-1-i] {
+		i++
+	}
+	return i
+}
+
+// sameSubNames reports whether two declaration lists define the same
+// subroutines in the same order
+func sameSubNames(a, b []ast.Declaration) bool {
+	names := func(decls []ast.Declaration) []string {
+		var result []string
+		for _, decl := range decls {
+			// Declarations that failed to parse can be typed nils
+			if sub, ok := decl.(*ast.SubDecl); ok && sub != nil {
+				result = append(result, sub.Name)
+			}
+		}
+		return result
+	}
+	return reflect.DeepEqual(names(a), names(b))
+}
+
+var positionType = reflect.TypeOf(lexer.Position{})
+
+// shiftPositions moves every position set in the tree rooted at v by the
+// given number of lines and bytes
+func shiftPositions(v reflect.Value, lines, offset int) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			shiftPositions(v.Elem(), lines, offset)
+		}
+	case reflect.Struct:
+		if v.Type() == positionType {
+			if v.CanSet() {
+				pos := v.Addr().Interface().(*lexer.Position)
+				if pos.Line > 0 {
+					pos.Line += lines
+					pos.Offset += offset
+				}
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			shiftPositions(v.Field(i), lines, offset)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			shiftPositions(v.Index(i), lines, offset)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			shiftPositions(iter.Value(), lines, offset)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	ast2 "github.com/perbu/vclparser/pkg/ast"
+)
+
+const incrementalInput = `vcl 4.1;
+
+import std;
+
+backend default {
+    .host = "127.0.0.1";
+    .port = "8080";
+}
+
+# Normalize the request
+sub normalize {
+    set req.http.host = std.tolower(req.http.host);
+}
+
+sub vcl_recv {
+    call normalize;
+    if (req.url ~ "^/admin") {
+        return (pass);
+    }
+}
+
+acl purgers {
+    "127.0.0.1";
+}
+
+sub vcl_deliver {
+    set resp.http.x-served-by = "varnish";
+}
+`
+
+// checkMatchesFullParse verifies that an incrementally updated document is
+// identical to parsing its source from scratch
+func checkMatchesFullParse(t *testing.T, d *Document) {
+	t.Helper()
+	fresh := NewDocument(d.Source, d.Filename, d.config)
+	if !reflect.DeepEqual(d.Program, fresh.Program) {
+		t.Fatalf("incremental program differs from a full parse of:\n%s", d.Source)
+	}
+	if !reflect.DeepEqual(d.Errors, fresh.Errors) {
+		t.Fatalf("incremental errors %v differ from full parse errors %v for:\n%s", d.Errors, fresh.Errors, d.Source)
+	}
+}
+
+func TestDocumentUpdateReparsesOnlyTouchedDeclaration(t *testing.T) {
+	d := NewDocument(incrementalInput, "test.vcl", nil)
+	if len(d.Errors) > 0 {
+		t.Fatalf("unexpected parse errors: %v", d.Errors)
+	}
+	before := append([]ast2.Declaration(nil), d.Program.Declarations...)
+
+	// Add a line to vcl_recv
+	at := strings.Index(incrementalInput, "    if (req.url")
+	changes, err := d.Update(Edit{Start: at, End: at, Text: "    set req.http.x-a = \"1\";\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMatchesFullParse(t, d)
+
+	if changes.Full || !reflect.DeepEqual(changes.Declarations, []int{3}) {
+		t.Fatalf("expected only declaration 3 to be reparsed, got %+v", changes)
+	}
+	for i, decl := range d.Program.Declarations {
+		if reused := decl == before[i]; reused == (i == 3) {
+			t.Errorf("declaration %d: reused = %v", i, reused)
+		}
+	}
+	if line := d.Program.Declarations[5].Start().Line; line != 27 {
+		t.Errorf("expected vcl_deliver to move to line 27, got %d", line)
+	}
+}
+
+func TestDocumentUpdateFallsBackToFullParse(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+	}{
+		{"version declaration", "vcl 4.1;", "vcl 4.0;"},
+		{"renamed called subroutine", "sub normalize {", "sub normalise {"},
+		{"unbalanced brace", "        return (pass);\n    }\n", "        return (pass);\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDocument(incrementalInput, "test.vcl", nil)
+			changes := d.Replace(strings.Replace(incrementalInput, tt.old, tt.new, 1))
+			if !changes.Full {
+				t.Errorf("expected a full reparse, got %+v", changes)
+			}
+			checkMatchesFullParse(t, d)
+		})
+	}
+}
+
+func TestDocumentUpdateAfterErrors(t *testing.T) {
+	d := NewDocument(incrementalInput, "test.vcl", nil)
+	at := strings.Index(incrementalInput, "set resp.http")
+
+	if _, err := d.Update(Edit{Start: at, End: at + 3, Text: "sett"}); err != nil {
+		t.Fatal(err)
+	}
+	checkMatchesFullParse(t, d)
+	if len(d.Errors) == 0 {
+		t.Fatal("expected a parse error")
+	}
+
+	changes, err := d.Update(Edit{Start: at, End: at + 4, Text: "set"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMatchesFullParse(t, d)
+	if len(d.Errors) > 0 || !changes.Full {
+		t.Errorf("expected a clean full reparse after fixing the error, got %+v, %v", changes, d.Errors)
+	}
+}
+
+func TestDocumentUpdateInvalidEdit(t *testing.T) {
+	d := NewDocument(incrementalInput, "test.vcl", nil)
+	if _, err := d.Update(Edit{Start: 10, End: len(incrementalInput) + 1}); err == nil {
+		t.Error("expected an error for an edit past the end")
+	}
+	if d.Source != incrementalInput {
+		t.Error("a rejected edit must leave the document unchanged")
+	}
+}
+
+// TestDocumentRandomEdits types, deletes and pastes at random places and
+// compares every intermediate state with a full parse
+func TestDocumentRandomEdits(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	snippets := []string{"x", ";", "}", "{", "\n", " ", "#", "\"", "sub foo {\n}\n", "set req.url = \"/\";\n", "call normalize;"}
+
+	d := NewDocument(incrementalInput, "test.vcl", nil)
+	for i := 0; i < 500; i++ {
+		start := rng.Intn(len(d.Source) + 1)
+		end := min(len(d.Source), start+rng.Intn(3))
+		text := ""
+		if rng.Intn(2) == 0 {
+			text = snippets[rng.Intn(len(snippets))]
+		}
+		if _, err := d.Update(Edit{Start: start, End: end, Text: text}); err != nil {
+			t.Fatal(err)
+		}
+		checkMatchesFullParse(t, d)
+
+		// Start over now and then so the source stays mostly valid
+		if i%20 == 19 {
+			d.Replace(incrementalInput)
+			checkMatchesFullParse(t, d)
+		}
+	}
+}
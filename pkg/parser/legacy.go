@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// The legacy mode, selected with Config.Legacy, accepts the VCL of Varnish
+// 2 and 3 and maps it onto the nodes of current VCL, reporting each old
+// construct as a "legacy-syntax" warning:
+//
+//   - a program without vcl version declaration
+//   - error 503 "Reason"; which becomes return (synth(503, "Reason"));
+//   - remove, which becomes unset
+//   - purge; which becomes return (purge);
+//   - strings concatenated without +, as in "Host: " req.http.host
+//   - set req.hash += x; which becomes hash_data(x);
+//   - purge_url(x), ban_url(x) and purge(x), which become ban()
+//   - sub vcl_error and vcl_fetch, which become vcl_synth and
+//     vcl_backend_response, with obj.* in vcl_error becoming resp.* and
+//     req.* in vcl_fetch becoming bereq.*
+//   - req.request and *.response, which become req.method and *.reason
+//   - return (lookup) in vcl_recv and return (hash) in vcl_hash, which
+//     swapped places
+
+// legacyCode is the warning code of the constructs the legacy mode maps
+const legacyCode = "legacy-syntax"
+
+// addLegacyWarning reports an old construct spanning node
+func (p *Parser) addLegacyWarning(node ast.Node, message string) {
+	p.warnings = append(p.warnings, Warning{
+		Code:        legacyCode,
+		Message:     message,
+		Position:    node.Start(),
+		EndPosition: node.End(),
+		Filename:    p.filename,
+	})
+}
+
+// parseLegacyStatement parses the statements only the legacy mode knows.
+// It returns false if the current token starts none of them.
+func (p *Parser) parseLegacyStatement() (ast.Statement, bool) {
+	switch {
+	case p.currentTokenIs(lexer.ERROR_KW) && !p.peekTokenIs(lexer.LPAREN):
+		return p.parseLegacyErrorStatement(), true
+	case p.currentTokenIs(lexer.PURGE_KW) && p.peekTokenIs(lexer.SEMICOLON):
+		stmt := ast.New(p.arena, ast.ReturnStatement{
+			BaseNode: ast.BaseNode{StartPos: p.currentToken.Start, EndPos: p.currentToken.End},
+			Action:   p.parseIdentifier(),
+		})
+		p.nextToken()
+		p.addLegacyWarning(stmt, "the purge statement was removed in VCL 4.0; use return (purge) in vcl_recv")
+		return stmt, true
+	case p.currentTokenIs(lexer.ID) && p.currentToken.Value == "remove":
+		start := p.currentToken
+		stmt := p.parseUnsetStatement()
+		if stmt != nil {
+			p.addLegacyWarning(stmt, "remove was removed in VCL 4.0; use unset")
+		} else {
+			p.addWarning(legacyCode, "remove was removed in VCL 4.0; use unset", start)
+		}
+		return stmt, true
+	}
+	return nil, false
+}
+
+// parseLegacyErrorStatement parses error with a status code and an optional
+// reason into the return (synth(...)) that replaced it
+func (p *Parser) parseLegacyErrorStatement() ast.Statement {
+	start := p.currentToken.Start
+	name := p.currentToken
+	p.nextToken() // move past 'error'
+
+	call := ast.New(p.arena, ast.CallExpression{
+		BaseNode: ast.BaseNode{StartPos: start},
+		Function: ast.New(p.arena, ast.Identifier{
+			BaseNode: ast.BaseNode{StartPos: name.Start, EndPos: name.End},
+			Name:     "synth",
+		}),
+	})
+	if p.currentTokenIs(lexer.SEMICOLON) {
+		p.addError("expected a status code after 'error'")
+		return nil
+	}
+	code := p.parsePrefixExpression()
+	if isNilNode(code) {
+		return nil
+	}
+	call.Arguments = append(call.Arguments, code)
+	if !p.peekTokenIs(lexer.SEMICOLON) && !p.peekTokenIs(lexer.RBRACE) && !p.peekTokenIs(lexer.EOF) {
+		p.nextToken()
+		reason := p.parseExpression()
+		if isNilNode(reason) {
+			return nil
+		}
+		call.Arguments = append(call.Arguments, reason)
+	}
+	call.EndPos = p.currentToken.End
+
+	stmt := ast.New(p.arena, ast.ReturnStatement{
+		BaseNode: ast.BaseNode{StartPos: start, EndPos: p.currentToken.End},
+		Action:   call,
+	})
+	p.skipSemicolon()
+	p.addLegacyWarning(stmt, "the error statement was removed in VCL 4.0; use return (synth(...))")
+	return stmt
+}
+
+// implicitConcatenation reports whether the next token continues the
+// expression before it as a string concatenated without +, which VCL 3 and
+// Fastly allow
+func (p *Parser) implicitConcatenation() bool {
+	if !p.config.Legacy && !p.fastly() {
+		return false
+	}
+	if p.peekTokenIs(lexer.CSTR) || p.peekTokenIs(lexer.LSTR) {
+		return true
+	}
+	// A variable can follow a string, but not another variable
+	return (p.currentTokenIs(lexer.CSTR) || p.currentTokenIs(lexer.LSTR)) && p.peekTokenIs(lexer.ID)
+}
+
+// parseImplicitConcatenation parses the operand following left as the
+// right-hand side of a +
+func (p *Parser) parseImplicitConcatenation(left ast.Expression) ast.Expression {
+	expr := ast.New(p.arena, ast.BinaryExpression{
+		BaseNode: ast.BaseNode{StartPos: left.Start()},
+		Left:     left,
+		Operator: "+",
+	})
+	p.nextToken() // move to the operand
+	expr.Right = p.parseExpressionWithPrecedence(TERM)
+	if isNilNode(expr.Right) {
+		return nil
+	}
+	expr.EndPos = p.currentToken.End
+	if p.config.Legacy {
+		p.addLegacyWarning(expr.Right, "strings concatenated without + are not VCL 4.0; use +")
+	}
+	return expr
+}
+
+// legacySubroutines maps the built-in subroutines of VCL 3 to the ones that
+// replaced them
+var legacySubroutines = map[string]string{
+	"vcl_error": "vcl_synth",
+	"vcl_fetch": "vcl_backend_response",
+}
+
+// legacyBans maps the VCL 2 and 3 purge functions to the ban expression
+// prefix their argument is appended to
+var legacyBans = map[string]string{
+	"purge_url": "req.url ~ ",
+	"ban_url":   "req.url ~ ",
+	"purge":     "",
+}
+
+// modernize rewrites the parts of a program parsed in legacy mode that are
+// only recognized once the statement or subroutine around them is known
+func (p *Parser) modernize(program *ast.Program) {
+	sub := ""
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.SubDecl:
+			sub = n.Name
+			if name, ok := legacySubroutines[n.Name]; ok {
+				p.addLegacyWarning(n, fmt.Sprintf("%s was renamed %s in VCL 4.0", n.Name, name))
+				n.Name = name
+			}
+		case *ast.BackendDecl, *ast.ProbeDecl, *ast.ACLDecl:
+			return false
+		case *ast.SetStatement:
+			if ast.VariableName(n.Variable) == "req.hash" && n.Operator == "+=" {
+				p.addLegacyWarning(n, "set req.hash += was removed in VCL 3.0; use hash_data()")
+				c.Replace(ast.New(p.arena, ast.ExpressionStatement{
+					BaseNode: n.BaseNode,
+					Expression: ast.New(p.arena, ast.CallExpression{
+						BaseNode:  n.BaseNode,
+						Function:  ast.New(p.arena, ast.Identifier{BaseNode: n.Variable.(*ast.MemberExpression).BaseNode, Name: "hash_data"}),
+						Arguments: []ast.Expression{n.Value},
+					}),
+				}))
+			}
+		case *ast.ReturnStatement:
+			// vcl_recv and vcl_hash swapped their actions
+			if ident, ok := n.Action.(*ast.Identifier); ok {
+				switch {
+				case ident.Name == "lookup" && sub == "vcl_recv":
+					p.addLegacyWarning(n, "return (lookup) in vcl_recv is return (hash) in VCL 4.0")
+					ident.Name = "hash"
+				case ident.Name == "hash" && sub == "vcl_hash":
+					p.addLegacyWarning(n, "return (hash) in vcl_hash is return (lookup) in VCL 4.0")
+					ident.Name = "lookup"
+				}
+			}
+		case *ast.CallExpression:
+			if ident, ok := n.Function.(*ast.Identifier); ok && len(n.Arguments) == 1 {
+				if prefix, ok := legacyBans[ident.Name]; ok {
+					p.addLegacyWarning(n, fmt.Sprintf("%s() was removed in VCL 4.0; use ban()", ident.Name))
+					if prefix != "" {
+						n.Arguments[0] = ast.New(p.arena, ast.BinaryExpression{
+							BaseNode: n.BaseNode,
+							Left:     ast.New(p.arena, ast.StringLiteral{BaseNode: ident.BaseNode, Value: prefix}),
+							Operator: "+",
+							Right:    n.Arguments[0],
+						})
+					}
+					ident.Name = "ban"
+				}
+			}
+		case *ast.MemberExpression:
+			if name := legacyVariable(sub, ast.VariableName(n)); name != "" {
+				p.addLegacyWarning(n, fmt.Sprintf("%s is %s in VCL 4.0", ast.VariableName(n), name))
+				c.Replace(memberExpression(p.arena, name, n.BaseNode))
+			}
+			// The parts of a variable name are not variables themselves
+			return false
+		}
+		return true
+	}, nil)
+}
+
+// legacyVariable returns the current name of a VCL 3 variable used in the
+// subroutine sub, or "" if the name did not change
+func legacyVariable(sub, name string) string {
+	object, rest, found := strings.Cut(name, ".")
+	if !found {
+		return ""
+	}
+	switch {
+	case sub == "vcl_error" && object == "obj":
+		object = "resp"
+	case sub == "vcl_fetch" && object == "req":
+		object = "bereq"
+	}
+	switch rest {
+	case "request":
+		if object == "req" || object == "bereq" {
+			rest = "method"
+		}
+	case "response":
+		rest = "reason"
+	}
+	if renamed := object + "." + rest; renamed != name {
+		return renamed
+	}
+	return ""
+}
+
+// memberExpression builds the member expression for a dotted variable
+// name, with every part at pos
+func memberExpression(arena *ast.Arena, name string, pos ast.BaseNode) ast.Expression {
+	parts := strings.Split(name, ".")
+	var expr ast.Expression = ast.New(arena, ast.Identifier{BaseNode: pos, Name: parts[0]})
+	for _, part := range parts[1:] {
+		expr = ast.New(arena, ast.MemberExpression{
+			BaseNode: pos,
+			Object:   expr,
+			Property: ast.New(arena, ast.Identifier{BaseNode: pos, Name: part}),
+		})
+	}
+	return expr
+}
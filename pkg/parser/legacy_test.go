@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+const varnish3 = `backend default {
+	.host = "127.0.0.1";
+}
+
+sub vcl_recv {
+	if (req.request == "BAN") {
+		ban_url(req.url);
+		error 200 "Banned";
+	}
+	remove req.http.Cookie;
+	set req.http.X-Host = "host: " req.http.host;
+	return (lookup);
+}
+
+sub vcl_hash {
+	set req.hash += req.url;
+	return (hash);
+}
+
+sub vcl_fetch {
+	set beresp.http.X-URL = req.url;
+}
+
+sub vcl_error {
+	synthetic {"<h1>"} obj.status " " obj.response {"</h1>"};
+	return (deliver);
+}
+`
+
+func legacyConfig() *Config {
+	config := DefaultConfig()
+	config.Legacy = true
+	return config
+}
+
+func TestLegacy(t *testing.T) {
+	if _, err := Parse(varnish3, "old.vcl"); err == nil {
+		t.Fatal("Expected VCL 3 to fail without the legacy mode")
+	}
+
+	result := ParseDetailed(varnish3, "old.vcl", legacyConfig())
+	if len(result.Errors) > 0 {
+		t.Fatalf("Legacy parse failed: %v", result.Errors[0])
+	}
+	for i, w := range result.Warnings {
+		if w.Code != legacyCode {
+			t.Errorf("Unexpected warning %s", w)
+		}
+		if i > 0 && w.Position.Offset < result.Warnings[i-1].Position.Offset {
+			t.Errorf("Warnings out of order: %s after %s", w, result.Warnings[i-1])
+		}
+	}
+	if len(result.Warnings) != 18 {
+		t.Errorf("Expected 18 warnings, got %d", len(result.Warnings))
+	}
+
+	program := result.Program
+	if program.VCLVersion != nil {
+		t.Errorf("Expected no version declaration, got %+v", program.VCLVersion)
+	}
+	subs := map[string]*ast.SubDecl{}
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok {
+			subs[sub.Name] = sub
+		}
+	}
+	for _, name := range []string{"vcl_recv", "vcl_hash", "vcl_backend_response", "vcl_synth"} {
+		if subs[name] == nil {
+			t.Fatalf("Expected sub %s, got %v", name, subs)
+		}
+	}
+
+	recv := subs["vcl_recv"].Body.Statements
+	cond := recv[0].(*ast.IfStatement)
+	if name := ast.VariableName(cond.Condition.(*ast.BinaryExpression).Left); name != "req.method" {
+		t.Errorf("Expected req.method, got %s", name)
+	}
+	body := cond.Then.(*ast.BlockStatement).Statements
+	ban := body[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	if ast.VariableName(ban.Function) != "ban" {
+		t.Errorf("Expected ban(), got %s", ast.VariableName(ban.Function))
+	}
+	synth := body[1].(*ast.ReturnStatement).Action.(*ast.CallExpression)
+	if ast.VariableName(synth.Function) != "synth" || len(synth.Arguments) != 2 {
+		t.Errorf("Expected synth(200, \"Banned\"), got %+v", synth)
+	}
+	if _, ok := recv[1].(*ast.UnsetStatement); !ok {
+		t.Errorf("Expected remove as unset, got %T", recv[1])
+	}
+	if concat, ok := recv[2].(*ast.SetStatement).Value.(*ast.BinaryExpression); !ok || concat.Operator != "+" {
+		t.Errorf("Expected a concatenation, got %T", recv[2].(*ast.SetStatement).Value)
+	}
+	if action := ast.VariableName(recv[3].(*ast.ReturnStatement).Action); action != "hash" {
+		t.Errorf("Expected return (hash), got %s", action)
+	}
+
+	hash := subs["vcl_hash"].Body.Statements
+	if call := hash[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression); ast.VariableName(call.Function) != "hash_data" {
+		t.Errorf("Expected hash_data(), got %s", ast.VariableName(call.Function))
+	}
+	if action := ast.VariableName(hash[1].(*ast.ReturnStatement).Action); action != "lookup" {
+		t.Errorf("Expected return (lookup), got %s", action)
+	}
+	fetch := subs["vcl_backend_response"].Body.Statements[0].(*ast.SetStatement)
+	if name := ast.VariableName(fetch.Value); name != "bereq.url" {
+		t.Errorf("Expected bereq.url in vcl_fetch, got %s", name)
+	}
+
+	var names []string
+	ast.Apply(subs["vcl_synth"], func(c *ast.Cursor) bool {
+		if name := ast.VariableName(asExpression(c.Node())); strings.Contains(name, ".") {
+			names = append(names, name)
+			return false
+		}
+		return true
+	}, nil)
+	if strings.Join(names, ",") != "resp.status,resp.reason" {
+		t.Errorf("Expected resp variables in vcl_error, got %v", names)
+	}
+}
+
+func asExpression(n ast.Node) ast.Expression {
+	expr, _ := n.(ast.Expression)
+	return expr
+}
+
+func TestLegacyModernVCL(t *testing.T) {
+	// VCL 4 means the same in the legacy mode
+	src := `vcl 4.1;
+sub vcl_recv {
+	set req.http.X = "a" + req.url;
+	if (req.url ~ "^/admin" && req.method == "GET") {
+		return (synth(403, "Forbidden"));
+	}
+}
+`
+	result := ParseDetailed(src, "new.vcl", legacyConfig())
+	if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+		t.Errorf("Expected a clean parse, got %v %v", result.Errors, result.Warnings)
+	}
+
+	if result := ParseDetailed("vcl 4.1;\nsub vcl_recv {\n\terror;\n}\n", "bad.vcl", legacyConfig()); len(result.Errors) == 0 {
+		t.Error("Expected an error for error without status code")
+	}
+}
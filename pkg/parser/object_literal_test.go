@@ -250,3 +250,42 @@ backend simple {
 		t.Fatalf("probe object does not contain 1 property. got=%d", len(probeObj.Properties))
 	}
 }
+
+// TestInlineProbeWithoutSemicolon tests the VCC form where no semicolon
+// follows the inline probe's closing brace
+func TestInlineProbeWithoutSemicolon(t *testing.T) {
+	input := `vcl 4.1;
+
+backend api {
+    .host = "api.example.com";
+    .probe = {
+        .url = "/healthz";
+    }
+    .max_connections = 100;
+}
+
+backend last {
+    .host = "127.0.0.1";
+    .probe = { .url = "/"; }
+}`
+
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Declarations) != 2 {
+		t.Fatalf("program.Declarations does not contain 2 declarations. got=%d",
+			len(program.Declarations))
+	}
+
+	for i, want := range []int{3, 2} {
+		decl, ok := program.Declarations[i].(*ast2.BackendDecl)
+		if !ok {
+			t.Fatalf("program.Declarations[%d] is not *ast.BackendDecl. got=%T", i, program.Declarations[i])
+		}
+		if len(decl.Properties) != want {
+			t.Errorf("backend %s does not contain %d properties. got=%d", decl.Name, want, len(decl.Properties))
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/perbu/vclparser/pkg/ast"
@@ -15,6 +18,18 @@ type Config struct {
 	DisableInlineC bool
 	// MaxErrors limits the number of errors before stopping parsing (0 = no limit)
 	MaxErrors int
+	// Arena allocates the nodes of the tree in an ast.Arena, which
+	// Program.Release hands to later parses. See ast.Arena for the
+	// trade-offs.
+	Arena bool
+	// Legacy accepts the VCL of Varnish 2 and 3, such as error statements
+	// and vcl_error, and maps it onto current VCL with a "legacy-syntax"
+	// warning for each old construct. See legacy.go for what is mapped.
+	Legacy bool
+	// Dialect selects the VCL variant to read. The Fastly dialect needs no
+	// version declaration and adds tables, directors and the statements
+	// listed in fastly.go.
+	Dialect lexer.Dialect
 }
 
 // DefaultConfig returns the default parser configuration
@@ -29,10 +44,15 @@ func DefaultConfig() *Config {
 type Parser struct {
 	lexer       *lexer.Lexer
 	errors      []DetailedError
+	warnings    []Warning
+	comments    []lexer.Token
+	tokenCount  int
 	input       string // Store original VCL source for error context
 	filename    string // Store filename for error reporting
 	symbolTable *types.SymbolTable
 	config      *Config // Parser configuration
+	arena       *ast.Arena
+	ctx         context.Context
 
 	currentToken lexer.Token
 	peekToken    lexer.Token
@@ -62,6 +82,10 @@ func NewWithConfig(l *lexer.Lexer, input, filename string, config *Config) *Pars
 		symbolTable: types.NewSymbolTable(),
 		config:      config,
 	}
+	if config.Arena {
+		p.arena = ast.NewArena()
+	}
+	l.SetDialect(config.Dialect)
 
 	// Read two tokens, so currentToken and peekToken are both set
 	p.nextToken()
@@ -89,6 +113,24 @@ func ParseWithConfig(input, filename string, config *Config) (*ast.Program, erro
 	return program, nil
 }
 
+// ParseContext is ParseWithConfig that stops at the next top-level
+// declaration once ctx is done. It then returns the declarations parsed so
+// far together with ctx.Err().
+func ParseContext(ctx context.Context, input, filename string, config *Config) (*ast.Program, error) {
+	l := lexer.New(input, filename)
+	p := NewWithConfig(l, input, filename, config)
+	p.ctx = ctx
+	program := p.ParseProgram()
+
+	if err := ctx.Err(); err != nil {
+		return program, err
+	}
+	if len(p.errors) > 0 {
+		return program, p.errors[0]
+	}
+	return program, nil
+}
+
 // ParseWithVMODValidation parses VCL input and performs VMOD validation
 func ParseWithVMODValidation(input, filename string) (*ast.Program, []string, error) {
 	// Parse the VCL code
@@ -110,17 +152,54 @@ func (p *Parser) Errors() []DetailedError {
 	return p.errors
 }
 
+// Warnings returns the non-fatal issues found while parsing
+func (p *Parser) Warnings() []Warning {
+	return p.warnings
+}
+
+// Comments returns the comment tokens read so far, in source order
+func (p *Parser) Comments() []lexer.Token {
+	return p.comments
+}
+
+// TokenCount returns the number of tokens read so far, including comments
+// but not the end of input
+func (p *Parser) TokenCount() int {
+	return p.tokenCount
+}
+
+// isNilNode reports whether n is nil or a nil pointer. Parse functions
+// return nil pointers of their node type when they fail, which must not end
+// up in the tree as non-nil interfaces.
+func isNilNode(n ast.Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
 // nextToken advances to the next token
 func (p *Parser) nextToken() {
 	p.currentToken = p.peekToken
-	p.peekToken = p.lexer.NextToken()
+	p.peekToken = p.readToken()
 
-	// Skip comments during parsing
+	// Skip comments during parsing, keeping them for Comments
 	for p.peekToken.Type == lexer.COMMENT {
-		p.peekToken = p.lexer.NextToken()
+		p.comments = append(p.comments, p.peekToken)
+		p.peekToken = p.readToken()
 	}
 }
 
+// readToken returns the next token of the lexer, counting it
+func (p *Parser) readToken() lexer.Token {
+	tok := p.lexer.NextToken()
+	if tok.Type != lexer.EOF {
+		p.tokenCount++
+	}
+	return tok
+}
+
 // addError adds a parsing error
 func (p *Parser) addError(message string) {
 	p.errors = append(p.errors, DetailedError{
@@ -135,6 +214,13 @@ func (p *Parser) addError(message string) {
 	}
 }
 
+// addErrorWithSuggestion adds a parsing error for a current token that is
+// probably a misspelling of suggestion
+func (p *Parser) addErrorWithSuggestion(message, suggestion string) {
+	p.addError(message)
+	p.errors[len(p.errors)-1].Suggestion = suggestion
+}
+
 // addPeekError adds a parsing error using the peek token's position
 func (p *Parser) addPeekError(message string) {
 	p.errors = append(p.errors, DetailedError{
@@ -157,6 +243,15 @@ func (p *Parser) reportError(message string) {
 	}
 }
 
+// reportErrorWithSuggestion is reportError for a probable misspelling of
+// suggestion
+func (p *Parser) reportErrorWithSuggestion(message, suggestion string) {
+	p.addErrorWithSuggestion(message, suggestion)
+	if !p.synchronizing {
+		p.panicMode = true
+	}
+}
+
 // synchronize exits panic mode when reaching a recovery point.
 // Resets parser state to normal operation after error recovery,
 // allowing parsing to continue from a stable syntactic position.
@@ -202,9 +297,10 @@ func (p *Parser) peekTokenIs(t lexer.TokenType) bool {
 	return p.peekToken.Type == t
 }
 
-// skipSemicolon optionally skips a semicolon
+// skipSemicolon moves onto an optional trailing semicolon, so the block
+// loop continues with the next statement
 func (p *Parser) skipSemicolon() {
-	if p.currentTokenIs(lexer.SEMICOLON) {
+	if p.peekTokenIs(lexer.SEMICOLON) {
 		p.nextToken()
 	}
 }
@@ -226,11 +322,14 @@ func (p *Parser) skipToSynchronizationPoint(syncTokens ...lexer.TokenType) {
 
 // ParseProgram parses the entire VCL program
 func (p *Parser) ParseProgram() *ast.Program {
-	program := &ast.Program{
+	program := ast.New(p.arena, ast.Program{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
 		Declarations: []ast.Declaration{},
+	})
+	if p.arena != nil {
+		p.arena.Bind(program)
 	}
 
 	// Skip any initial comments
@@ -245,13 +344,38 @@ func (p *Parser) ParseProgram() *ast.Program {
 			return program
 		}
 		p.nextToken() // Move past the semicolon
+	} else if p.fastly() {
+		// Fastly VCL has no version declaration
+	} else if p.config.Legacy {
+		p.addWarning(legacyCode, "VCL before 4.0 has no version declaration", p.currentToken)
 	} else {
 		p.addError("VCL program must start with version declaration")
 		return program
 	}
 
-	// Parse declarations
+	program.Declarations = append(program.Declarations, p.parseDeclarations(-1)...)
+	if p.config.Legacy {
+		p.modernize(program)
+		sort.SliceStable(p.warnings, func(i, j int) bool {
+			return p.warnings[i].Position.Offset < p.warnings[j].Position.Offset
+		})
+	}
+
+	program.EndPos = p.currentToken.End
+	return program
+}
+
+// parseDeclarations parses top-level declarations until EOF or, if end is
+// not negative, the first token starting at or after byte offset end
+func (p *Parser) parseDeclarations(end int) []ast.Declaration {
+	var decls []ast.Declaration
 	for !p.currentTokenIs(lexer.EOF) && !p.maxErrorsReached {
+		if end >= 0 && p.currentToken.Start.Offset >= end {
+			break
+		}
+		if p.ctx != nil && p.ctx.Err() != nil {
+			break
+		}
 		if p.currentTokenIs(lexer.COMMENT) {
 			p.nextToken()
 			continue
@@ -259,7 +383,7 @@ func (p *Parser) ParseProgram() *ast.Program {
 
 		decl := p.parseDeclaration()
 		if decl != nil {
-			program.Declarations = append(program.Declarations, decl)
+			decls = append(decls, decl)
 		}
 
 		// Don't advance token if we're at EOF
@@ -267,13 +391,21 @@ func (p *Parser) ParseProgram() *ast.Program {
 			p.nextToken()
 		}
 	}
-
-	program.EndPos = p.currentToken.End
-	return program
+	return decls
 }
 
-// parseDeclaration parses a top-level declaration
+// parseDeclaration parses a top-level declaration, returning nil if it
+// cannot
 func (p *Parser) parseDeclaration() ast.Declaration {
+	decl := p.parseDeclarationNode()
+	if isNilNode(decl) {
+		return nil
+	}
+	return decl
+}
+
+// parseDeclarationNode dispatches on the declaration keyword
+func (p *Parser) parseDeclarationNode() ast.Declaration {
 	if p.maxErrorsReached {
 		return nil
 	}
@@ -290,6 +422,12 @@ func (p *Parser) parseDeclaration() ast.Declaration {
 		}
 	}
 
+	if p.fastly() {
+		if decl, ok := p.parseFastlyDeclaration(); ok {
+			return decl
+		}
+	}
+
 	switch p.currentToken.Type {
 	case lexer.IMPORT_KW:
 		return p.parseImportDecl()
@@ -304,6 +442,12 @@ func (p *Parser) parseDeclaration() ast.Declaration {
 	case lexer.SUB_KW:
 		return p.parseSubDecl()
 	default:
+		if p.currentTokenIs(lexer.ID) {
+			if keyword := suggestKeyword(p.currentToken.Value, declarationKeywords); keyword != "" {
+				p.reportErrorWithSuggestion(fmt.Sprintf("unknown declaration %q", p.currentToken.Value), keyword)
+				return nil
+			}
+		}
 		p.reportError(fmt.Sprintf("unexpected token %s", p.currentToken.Type))
 		return nil
 	}
@@ -311,11 +455,11 @@ func (p *Parser) parseDeclaration() ast.Declaration {
 
 // parseVCLVersionDecl parses a VCL version declaration
 func (p *Parser) parseVCLVersionDecl() *ast.VCLVersionDecl {
-	decl := &ast.VCLVersionDecl{
+	decl := ast.New(p.arena, ast.VCLVersionDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectToken(lexer.VCL_KW) {
 		return nil
@@ -340,11 +484,11 @@ func (p *Parser) parseVCLVersionDecl() *ast.VCLVersionDecl {
 
 // parseImportDecl parses an import declaration
 func (p *Parser) parseImportDecl() *ast.ImportDecl {
-	decl := &ast.ImportDecl{
+	decl := ast.New(p.arena, ast.ImportDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectPeek(lexer.ID) {
 		return nil
@@ -352,10 +496,15 @@ func (p *Parser) parseImportDecl() *ast.ImportDecl {
 
 	decl.Module = p.currentToken.Value
 
-	// Check for optional alias
+	// Check for optional alias or from "path"
 	if p.peekTokenIs(lexer.ID) {
 		p.nextToken()
-		decl.Alias = p.currentToken.Value
+		if p.currentToken.Value == "from" && p.peekTokenIs(lexer.CSTR) {
+			p.nextToken()
+			decl.From = strings.Trim(p.currentToken.Value, `"`)
+		} else {
+			decl.Alias = p.currentToken.Value
+		}
 	}
 
 	decl.EndPos = p.currentToken.End
@@ -370,10 +519,23 @@ func (p *Parser) parseImportDecl() *ast.ImportDecl {
 
 // parseIncludeDecl parses an include declaration
 func (p *Parser) parseIncludeDecl() *ast.IncludeDecl {
-	decl := &ast.IncludeDecl{
+	decl := ast.New(p.arena, ast.IncludeDecl{
 		BaseNode: ast.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
+	})
+
+	// include +glob "pattern" includes every matching file
+	if p.peekTokenIs(lexer.PLUS) {
+		p.nextToken()
+		if !p.expectPeek(lexer.ID) {
+			return nil
+		}
+		if p.currentToken.Value != "glob" {
+			p.addError(fmt.Sprintf("unknown include flag +%s", p.currentToken.Value))
+			return nil
+		}
+		decl.Glob = true
 	}
 
 	if !p.expectPeek(lexer.CSTR) {
@@ -1,8 +1,16 @@
 package parser
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/perbu/vclparser/internal/benchdata"
 	ast2 "github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/lexer"
 )
@@ -25,6 +33,62 @@ func TestVCLVersionDeclaration(t *testing.T) {
 	}
 }
 
+func TestImportDeclaration(t *testing.T) {
+	input := `vcl 4.1;
+import std;
+import directors from "/usr/lib/varnish/vmods/libvmod_directors.so";`
+
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+
+	checkParserErrors(t, p)
+
+	if len(program.Declarations) != 2 {
+		t.Fatalf("expected 2 declarations, got %d", len(program.Declarations))
+	}
+	std, ok := program.Declarations[0].(*ast2.ImportDecl)
+	if !ok || std.Module != "std" || std.From != "" {
+		t.Errorf("unexpected first import: %+v", program.Declarations[0])
+	}
+	directors, ok := program.Declarations[1].(*ast2.ImportDecl)
+	if !ok || directors.Module != "directors" || directors.From != "/usr/lib/varnish/vmods/libvmod_directors.so" {
+		t.Errorf("unexpected second import: %+v", program.Declarations[1])
+	}
+}
+
+func TestIncludeDeclaration(t *testing.T) {
+	input := `vcl 4.1;
+include "backends.vcl";
+include +glob "conf.d/*.vcl";`
+
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+
+	checkParserErrors(t, p)
+
+	if len(program.Declarations) != 2 {
+		t.Fatalf("expected 2 declarations, got %d", len(program.Declarations))
+	}
+	plain, ok := program.Declarations[0].(*ast2.IncludeDecl)
+	if !ok || plain.Path != "backends.vcl" || plain.Glob {
+		t.Errorf("unexpected first include: %+v", program.Declarations[0])
+	}
+	glob, ok := program.Declarations[1].(*ast2.IncludeDecl)
+	if !ok || glob.Path != "conf.d/*.vcl" || !glob.Glob {
+		t.Errorf("unexpected second include: %+v", program.Declarations[1])
+	}
+
+	input = `vcl 4.1;
+include +optional "backends.vcl";`
+	p = New(lexer.New(input, "test.vcl"), input, "test.vcl")
+	p.ParseProgram()
+	if len(p.Errors()) == 0 {
+		t.Error("expected an error for an unknown include flag")
+	}
+}
+
 func TestBackendDeclaration(t *testing.T) {
 	input := `vcl 4.0;
 
@@ -176,6 +240,68 @@ acl purge {
 	}
 }
 
+func TestACLEntryNetwork(t *testing.T) {
+	input := `vcl 4.1;
+
+acl local {
+    "localhost";
+    "192.168.0.0"/16;
+    !("fd00::1");
+}`
+
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+
+	checkParserErrors(t, p)
+
+	decl := program.Declarations[0].(*ast2.ACLDecl)
+	tests := []struct {
+		host      string
+		prefixLen int
+		optional  bool
+	}{
+		{"localhost", -1, false},
+		{"192.168.0.0", 16, false},
+		{"fd00::1", -1, true},
+	}
+	for i, tt := range tests {
+		entry := decl.Entries[i]
+		if entry.Host != tt.host || entry.PrefixLen != tt.prefixLen || entry.Optional != tt.optional {
+			t.Errorf("entry %d = {%q %d %v}, want {%q %d %v}", i,
+				entry.Host, entry.PrefixLen, entry.Optional, tt.host, tt.prefixLen, tt.optional)
+		}
+	}
+}
+
+func TestACLEntryErrors(t *testing.T) {
+	tests := []struct {
+		entry    string
+		expected string
+	}{
+		{`"10.0.0.0"/33;`, "ACL mask /33 is too long for 10.0.0.0, the maximum is /32"},
+		{`"10.0.300.1";`, `invalid IP address "10.0.300.1" in ACL`},
+		{`"bad host";`, `invalid host name "bad host" in ACL`},
+		{`req.http.host;`, "ACL entry must be a quoted address or host name"},
+	}
+
+	for _, tt := range tests {
+		input := "vcl 4.1;\nacl a {\n    " + tt.entry + "\n}"
+		l := lexer.New(input, "test.vcl")
+		p := New(l, input, "test.vcl")
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("%s: expected an error", tt.entry)
+			continue
+		}
+		if !strings.Contains(errors[0].Error(), tt.expected) {
+			t.Errorf("%s: error = %q, want it to contain %q", tt.entry, errors[0].Error(), tt.expected)
+		}
+	}
+}
+
 func TestExpressionInCondition(t *testing.T) {
 	input := `vcl 4.0; sub test { if (req.method) { return (hash); } }`
 
@@ -340,3 +466,364 @@ sub vcl_init {
 		t.Errorf("functionIdent.Name = %q, want %q", functionIdent.Name, "foo")
 	}
 }
+
+func TestStatementsEndingInSemicolon(t *testing.T) {
+	input := `vcl 4.0;
+
+sub vcl_synth {
+    synthetic("<h1>" + resp.reason + "</h1>");
+    error(503, "Service unavailable");
+    restart;
+    set resp.http.Content-Type = "text/html";
+}`
+
+	l := lexer.New(input, "test.vcl")
+	p := New(l, input, "test.vcl")
+	program := p.ParseProgram()
+
+	checkParserErrors(t, p)
+
+	subDecl := program.Declarations[0].(*ast2.SubDecl)
+	if len(subDecl.Body.Statements) != 4 {
+		t.Fatalf("subDecl.Body.Statements does not contain 4 statements. got=%d",
+			len(subDecl.Body.Statements))
+	}
+
+	if _, ok := subDecl.Body.Statements[0].(*ast2.SyntheticStatement); !ok {
+		t.Errorf("Statements[0] is not *ast.SyntheticStatement. got=%T", subDecl.Body.Statements[0])
+	}
+	if _, ok := subDecl.Body.Statements[1].(*ast2.ErrorStatement); !ok {
+		t.Errorf("Statements[1] is not *ast.ErrorStatement. got=%T", subDecl.Body.Statements[1])
+	}
+	if _, ok := subDecl.Body.Statements[2].(*ast2.RestartStatement); !ok {
+		t.Errorf("Statements[2] is not *ast.RestartStatement. got=%T", subDecl.Body.Statements[2])
+	}
+
+	setStmt, ok := subDecl.Body.Statements[3].(*ast2.SetStatement)
+	if !ok {
+		t.Fatalf("Statements[3] is not *ast.SetStatement. got=%T", subDecl.Body.Statements[3])
+	}
+	member, ok := setStmt.Variable.(*ast2.MemberExpression)
+	if !ok {
+		t.Fatalf("setStmt.Variable is not *ast.MemberExpression. got=%T", setStmt.Variable)
+	}
+	if prop, ok := member.Property.(*ast2.Identifier); !ok || prop.Name != "Content-Type" {
+		t.Errorf("header name = %v, want Content-Type", member.Property)
+	}
+}
+
+func TestRepeatedSubroutines(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "built-in subroutines are concatenated",
+			input: `vcl 4.1; sub vcl_recv { set req.http.A = "1"; } sub vcl_recv { set req.http.B = "1"; }`,
+		},
+		{
+			name:    "custom subroutines are unique",
+			input:   `vcl 4.1; sub normalize { } sub normalize { }`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input, "test.vcl")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestErrorSuggestions(t *testing.T) {
+	tests := []struct {
+		input      string
+		message    string
+		suggestion string
+		excerpt    string
+	}{
+		{
+			"vcl 4.1;\nsub vcl_recv {\n    retrun (pass);\n}",
+			`unknown statement "retrun"`, "return",
+			"  3 |     retrun (pass);\n          ^~~~~~\n",
+		},
+		{
+			"vcl 4.1;\nsub vcl_recv {\n    sett req.http.x = \"1\";\n}",
+			`unknown statement "sett"`, "set",
+			"  3 |     sett req.http.x = \"1\";\n          ^~~~\n",
+		},
+		{
+			"vcl 4.1;\nbackedn default { .host = \"a\"; }",
+			`unknown declaration "backedn"`, "backend",
+			"  2 | backedn default { .host = \"a\"; }\n      ^~~~~~~\n",
+		},
+		{
+			"vcl 4.1;\nsub vcl_recv {\n    foo(1);\n}",
+			"", "", "",
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input, "test.vcl"), tt.input, "test.vcl")
+		p.ParseProgram()
+
+		var found *DetailedError
+		for i, err := range p.Errors() {
+			if err.Suggestion != "" {
+				found = &p.Errors()[i]
+				break
+			}
+		}
+		if tt.suggestion == "" {
+			if found != nil {
+				t.Errorf("%q: unexpected suggestion %q", tt.input, found.Suggestion)
+			}
+			continue
+		}
+		if found == nil {
+			t.Errorf("%q: expected an error suggesting %q, got %v", tt.input, tt.suggestion, p.Errors())
+			continue
+		}
+		if found.Message != tt.message || found.Suggestion != tt.suggestion {
+			t.Errorf("%q: error = %q suggesting %q, want %q suggesting %q",
+				tt.input, found.Message, found.Suggestion, tt.message, tt.suggestion)
+		}
+		text := found.Error()
+		if !strings.Contains(text, tt.excerpt) {
+			t.Errorf("%q: error text %q does not contain excerpt %q", tt.input, text, tt.excerpt)
+		}
+		if !strings.Contains(text, fmt.Sprintf("Hint: did you mean %q?", tt.suggestion)) {
+			t.Errorf("%q: error text %q has no hint", tt.input, text)
+		}
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	tests := []struct {
+		input        string
+		line, column int
+	}{
+		{"", 1, 1},
+		{"vcl 4.1;\nsub vcl_recv {\n  sett req.url = \"/\";\n}", 3, 3},
+		{"vcl 4.1;\nsub vcl_recv {\n\tsett req.url = \"/\";\n}", 3, 2},
+		{"vcl 4.1;\nbackedn default { .host = \"a\"; }", 2, 1},
+		{"vcl 4.1;\nsub vcl_recv {\n  set req.url = \"é\" +;\n}", 3, 23},
+	}
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input, "test.vcl"), tt.input, "test.vcl")
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("%q: expected an error", tt.input)
+			continue
+		}
+		if pos := p.Errors()[0].Position; pos.Line != tt.line || pos.Column != tt.column {
+			t.Errorf("%q: error %q at %d:%d, want %d:%d", tt.input, p.Errors()[0].Message,
+				pos.Line, pos.Column, tt.line, tt.column)
+		}
+	}
+}
+
+func TestSuggestKeyword(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"retrun", "return"},
+		{"unst", "unset"},
+		{"Synthtic", "synthetic"},
+		{"ste", "set"},
+		{"req", ""},
+		{"std.log", ""},
+		{"normalize", ""},
+	}
+	for _, tt := range tests {
+		if got := suggestKeyword(tt.word, statementKeywords); got != tt.expected {
+			t.Errorf("suggestKeyword(%q) = %q, want %q", tt.word, got, tt.expected)
+		}
+	}
+}
+
+func TestLongStrings(t *testing.T) {
+	input := "vcl 4.1;\nsub vcl_synth {\n    synthetic({\"<p class=\"x\">\n\"} + resp.reason + \"\"\"</p> {\"}\"\"\");\n}"
+
+	program, err := Parse(input, "test.vcl")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	sub := program.Declarations[0].(*ast2.SubDecl)
+	synth := sub.Body.Statements[0].(*ast2.SyntheticStatement)
+	outer := synth.Response.(*ast2.BinaryExpression)
+	first := outer.Left.(*ast2.BinaryExpression).Left.(*ast2.StringLiteral)
+	last := outer.Right.(*ast2.StringLiteral)
+
+	tests := []struct {
+		lit   *ast2.StringLiteral
+		value string
+		line  int
+	}{
+		{first, "<p class=\"x\">\n", 3},
+		{last, `</p> {"}`, 4},
+	}
+	for _, tt := range tests {
+		if !tt.lit.Long || tt.lit.Value != tt.value {
+			t.Errorf("literal = %q (long %v), want long %q", tt.lit.Value, tt.lit.Long, tt.value)
+		}
+		if tt.lit.Start().Line != tt.line {
+			t.Errorf("%q starts on line %d, want %d", tt.value, tt.lit.Start().Line, tt.line)
+		}
+	}
+}
+
+// BenchmarkParse parses a generated multi-tenant configuration of a few
+// hundred KB built from the tenant template in the test data. Taking token
+// values from the input instead of building one- and two-character strings
+// took it from 58087 to 35086 allocations per parse; the lexer on its own
+// went from 23002 to 1 (BenchmarkNextToken).
+func BenchmarkParse(b *testing.B) {
+	input := benchdata.Tenants(100)
+
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := New(lexer.New(input, "bench.vcl"), input, "bench.vcl")
+		p.ParseProgram()
+		if len(p.Errors()) > 0 {
+			b.Fatal(p.Errors()[0])
+		}
+	}
+}
+
+func TestArena(t *testing.T) {
+	files, err := filepath.Glob("../../tests/testdata/*.vcl")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("No test data: %v", err)
+	}
+	inputs := map[string]string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs[file] = string(data)
+	}
+	inputs["fastly.vcl"] = fastlyVCL
+
+	for file, input := range inputs {
+		config := &Config{MaxErrors: 8}
+		if file == "fastly.vcl" {
+			config = fastlyConfig()
+		}
+		want, err := ParseWithConfig(input, file, config)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		// Parse twice, so the second parse reuses the released blocks
+		arenaConfig := *config
+		arenaConfig.Arena = true
+		for i := 0; i < 2; i++ {
+			got, err := ParseWithConfig(input, file, &arenaConfig)
+			if err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			// The programs differ in the arena they are bound to
+			if !reflect.DeepEqual(got.BaseNode, want.BaseNode) ||
+				!reflect.DeepEqual(got.VCLVersion, want.VCLVersion) ||
+				!reflect.DeepEqual(got.Declarations, want.Declarations) {
+				t.Fatalf("%s: the tree built in an arena differs", file)
+			}
+			got.Release()
+		}
+	}
+
+	// Releasing a tree not built in an arena does nothing
+	program, _ := Parse("vcl 4.1;", "test.vcl")
+	program.Release()
+	if program.VCLVersion == nil || program.VCLVersion.Version != "4.1" {
+		t.Error("Release changed a tree not built in an arena")
+	}
+}
+
+// BenchmarkParseArena is BenchmarkParse with the tree built in an arena and
+// released after each parse
+func BenchmarkParseArena(b *testing.B) {
+	input := benchdata.Tenants(100)
+	config := &Config{Arena: true}
+
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := NewWithConfig(lexer.New(input, "bench.vcl"), input, "bench.vcl", config)
+		program := p.ParseProgram()
+		if len(p.Errors()) > 0 {
+			b.Fatal(p.Errors()[0])
+		}
+		program.Release()
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	input := "vcl 4.1;\nbackend a { .host = \"192.0.2.1\"; }\nbackend b { .host = \"192.0.2.2\"; }\n"
+
+	program, err := ParseContext(context.Background(), input, "test.vcl", nil)
+	if err != nil || len(program.Declarations) != 2 {
+		t.Fatalf("Expected 2 declarations, got %v (err %v)", program, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	program, err = ParseContext(ctx, input, "test.vcl", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if program == nil || program.VCLVersion == nil || len(program.Declarations) != 0 {
+		t.Errorf("Expected the tree parsed before the cancellation, got %v", program)
+	}
+}
+
+func TestParseDetailed(t *testing.T) {
+	input := `vcl 4.1;
+# leading comment
+sub vcl_recv {
+	set req.http.x = "1";;
+	if (req.restarts > 010) {
+		return (pass);
+	};
+}
+`
+	result := ParseDetailed(input, "test.vcl", nil)
+	if err := result.Err(); err != nil {
+		t.Fatalf("Expected warnings only, got %v", err)
+	}
+	var codes []string
+	for _, w := range result.Warnings {
+		codes = append(codes, fmt.Sprintf("%s@%d", w.Code, w.Position.Line))
+	}
+	want := []string{"empty-statement@4", "numeric-literal@5", "empty-statement@7"}
+	if !reflect.DeepEqual(codes, want) {
+		t.Errorf("Expected warnings %v, got %v", want, codes)
+	}
+	if len(result.Comments) != 1 || result.Comments[0].Value != "# leading comment" {
+		t.Errorf("Expected the comment, got %v", result.Comments)
+	}
+	if result.TokenCount != len(lexer.New(input, "test.vcl").TokenizeAll())-1 {
+		t.Errorf("Unexpected token count %d", result.TokenCount)
+	}
+
+	// Integers are decimal regardless of leading zeros
+	ifStmt := result.Program.Declarations[0].(*ast2.SubDecl).Body.Statements[1].(*ast2.IfStatement)
+	literal := ifStmt.Condition.(*ast2.BinaryExpression).Right.(*ast2.IntegerLiteral)
+	if literal.Value != 10 {
+		t.Errorf("Expected 010 to be 10, got %d", literal.Value)
+	}
+
+	result = ParseDetailed("vcl 4.1;\nsub vcl_recv {\n", "test.vcl", nil)
+	if result.Err() == nil || len(result.Errors) == 0 {
+		t.Error("Expected a syntax error")
+	}
+}
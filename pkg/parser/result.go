@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// Warning is a non-fatal issue found while parsing, such as an empty
+// statement or an integer with a leading zero. The program is parsed as
+// varnishd would parse it.
+type Warning struct {
+	// Code identifies the kind of issue: "empty-statement" or
+	// "numeric-literal"
+	Code     string
+	Message  string
+	Position lexer.Position
+	// EndPosition is where the offending token ends
+	EndPosition lexer.Position
+	Filename    string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s:%s: %s", w.Filename, w.Position, w.Message)
+}
+
+// ParseResult is everything a parse produces besides the tree
+type ParseResult struct {
+	Program *ast.Program
+	// Errors are the syntax errors, in source order. The program is
+	// incomplete when there are any.
+	Errors []DetailedError
+	// Warnings are the non-fatal issues, in source order
+	Warnings []Warning
+	// Comments are the comment tokens of the source, which the tree does
+	// not hold
+	Comments []lexer.Token
+	// TokenCount is the number of tokens read, including comments
+	TokenCount int
+}
+
+// Err returns the first syntax error, or nil if there is none, as
+// ParseWithConfig does
+func (r *ParseResult) Err() error {
+	if len(r.Errors) > 0 {
+		return r.Errors[0]
+	}
+	return nil
+}
+
+// ParseDetailed parses the input like ParseWithConfig and returns all
+// errors together with the warnings, comments and token count, so callers
+// can report non-fatal issues without failing the parse. A nil config
+// selects DefaultConfig().
+func ParseDetailed(input, filename string, config *Config) *ParseResult {
+	p := NewWithConfig(lexer.New(input, filename), input, filename, config)
+	program := p.ParseProgram()
+	return &ParseResult{
+		Program:    program,
+		Errors:     p.Errors(),
+		Warnings:   p.Warnings(),
+		Comments:   p.Comments(),
+		TokenCount: p.TokenCount(),
+	}
+}
+
+// addWarning records a non-fatal issue at tok
+func (p *Parser) addWarning(code, message string, tok lexer.Token) {
+	p.warnings = append(p.warnings, Warning{
+		Code:        code,
+		Message:     message,
+		Position:    tok.Start,
+		EndPosition: tok.End,
+		Filename:    p.filename,
+	})
+}
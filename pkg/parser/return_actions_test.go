@@ -94,6 +94,7 @@ func TestReturnActionsWithArguments(t *testing.T) {
 		{"synth with args", `vcl 4.1; sub test { return (synth(404, "Not Found")); }`},
 		{"error with args", `vcl 4.1; sub test { return (error(500)); }`},
 		{"parenthesized synth with args", `vcl 4.1; sub test { return (synth(404, "Not Found")); }`},
+		{"vcl label", `vcl 4.1; sub test { return (vcl(other_label)); }`},
 	}
 
 	for _, tt := range tests {
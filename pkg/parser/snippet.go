@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// Snippet is a fragment of VCL parsed by ParseSnippet: either top-level
+// declarations or the statements of a subroutine body
+type Snippet struct {
+	// VCLVersion is set when the fragment starts with a version
+	// declaration
+	VCLVersion   *ast.VCLVersionDecl
+	Declarations []ast.Declaration
+	Statements   []ast.Statement
+}
+
+// newSnippetParser returns a parser for a fragment of VCL. Positions in
+// the nodes it returns are relative to the fragment.
+func newSnippetParser(input, filename string) *Parser {
+	return NewWithConfig(lexer.New(input, filename), input, filename, DefaultConfig())
+}
+
+// err returns the first syntax error, as ParseWithConfig does
+func (p *Parser) err() error {
+	if len(p.errors) > 0 {
+		return p.errors[0]
+	}
+	return nil
+}
+
+// expectEnd reports anything following a complete fragment
+func (p *Parser) expectEnd(what string) {
+	if len(p.errors) == 0 && !p.currentTokenIs(lexer.EOF) {
+		p.addError(fmt.Sprintf("unexpected %s after %s", p.currentToken.Type, what))
+	}
+}
+
+// ParseExpression parses a single expression, such as a condition for an
+// if statement: `req.http.host ~ "^www\."`
+func ParseExpression(input, filename string) (ast.Expression, error) {
+	p := newSnippetParser(input, filename)
+	if p.currentTokenIs(lexer.EOF) {
+		p.addError("expected an expression")
+		return nil, p.err()
+	}
+	expr := p.parseExpression()
+	p.nextToken()
+	p.expectEnd("expression")
+	if isNilNode(expr) {
+		expr = nil
+	}
+	return expr, p.err()
+}
+
+// ParseStatement parses a single statement as written in a subroutine,
+// such as a set statement or an if statement with all its branches
+func ParseStatement(input, filename string) (ast.Statement, error) {
+	p := newSnippetParser(input, filename)
+	if p.currentTokenIs(lexer.EOF) {
+		p.addError("expected a statement")
+		return nil, p.err()
+	}
+	stmt := p.parseStatement()
+	if stmt != nil {
+		p.nextToken()
+		p.expectEnd("statement")
+	}
+	return stmt, p.err()
+}
+
+// ParseSnippet parses a fragment of VCL for insertion into a program, for
+// instance with ast.Apply. A fragment starting with a declaration keyword
+// such as backend, sub or import holds declarations and needs no version
+// declaration; any other fragment holds statements. The snippet parsed so
+// far is returned together with the first syntax error.
+func ParseSnippet(input, filename string) (*Snippet, error) {
+	p := newSnippetParser(input, filename)
+	snippet := &Snippet{}
+
+	switch p.currentToken.Type {
+	case lexer.VCL_KW:
+		snippet.VCLVersion = p.parseVCLVersionDecl()
+		if snippet.VCLVersion == nil {
+			return snippet, p.err()
+		}
+		p.nextToken()
+		snippet.Declarations = p.parseDeclarations(-1)
+	case lexer.IMPORT_KW, lexer.INCLUDE_KW, lexer.BACKEND_KW, lexer.PROBE_KW, lexer.ACL_KW, lexer.SUB_KW:
+		snippet.Declarations = p.parseDeclarations(-1)
+	default:
+		snippet.Statements = p.parseStatementList()
+		p.expectEnd("statements")
+	}
+	return snippet, p.err()
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+func TestParseExpression(t *testing.T) {
+	expr, err := ParseExpression(`req.http.host ~ "^www\." && req.method == "GET"`, "expr")
+	if err != nil {
+		t.Fatalf("ParseExpression failed: %v", err)
+	}
+	bin, ok := expr.(*ast.BinaryExpression)
+	if !ok || bin.Operator != "&&" {
+		t.Fatalf("Expected && expression, got %#v", expr)
+	}
+	if _, ok := bin.Left.(*ast.RegexMatchExpression); !ok {
+		t.Errorf("Expected regex match on the left, got %T", bin.Left)
+	}
+
+	for _, input := range []string{"", "req.url req.method", `"a" +`} {
+		if _, err := ParseExpression(input, "expr"); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	stmt, err := ParseStatement(`if (req.url ~ "^/admin") {
+	return (pass);
+} else {
+	unset req.http.Cookie;
+}`, "stmt")
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+	ifStmt, ok := stmt.(*ast.IfStatement)
+	if !ok || ifStmt.Else == nil {
+		t.Fatalf("Expected an if statement with an else branch, got %#v", stmt)
+	}
+
+	for _, input := range []string{"", "set req.url = ;", "unset req.http.A; unset req.http.B;"} {
+		if _, err := ParseStatement(input, "stmt"); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestParseSnippet(t *testing.T) {
+	snippet, err := ParseSnippet(`backend web1 { .host = "192.0.2.1"; }
+sub vcl_recv { set req.backend_hint = web1; }`, "decls")
+	if err != nil {
+		t.Fatalf("ParseSnippet failed: %v", err)
+	}
+	if len(snippet.Declarations) != 2 || len(snippet.Statements) != 0 || snippet.VCLVersion != nil {
+		t.Errorf("Expected two declarations, got %+v", snippet)
+	}
+
+	snippet, err = ParseSnippet(`set req.http.X-A = "1";
+# comment
+unset req.http.Cookie;`, "stmts")
+	if err != nil {
+		t.Fatalf("ParseSnippet failed: %v", err)
+	}
+	if len(snippet.Statements) != 2 || len(snippet.Declarations) != 0 {
+		t.Errorf("Expected two statements, got %+v", snippet)
+	}
+
+	if _, err := ParseSnippet("set req.url = \"/\"; }", "stmts"); err == nil {
+		t.Error("Expected an error for an unbalanced brace")
+	}
+	if _, err := ParseSnippet("backend { }", "decls"); err == nil {
+		t.Error("Expected an error for a backend without name")
+	}
+}
+
+func TestParseSnippetInsert(t *testing.T) {
+	program, err := Parse(`vcl 4.1;
+sub vcl_recv {
+	return (hash);
+}`, "main.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snippet, err := ParseSnippet(`unset req.http.Cookie;`, "snippet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if _, ok := c.Node().(*ast.ReturnStatement); ok {
+			for _, stmt := range snippet.Statements {
+				c.InsertBefore(stmt)
+			}
+			return false
+		}
+		return true
+	}, nil)
+
+	body := program.Declarations[0].(*ast.SubDecl).Body.Statements
+	if len(body) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(body))
+	}
+	if _, ok := body[0].(*ast.UnsetStatement); !ok {
+		t.Errorf("Expected the snippet first, got %T", body[0])
+	}
+}
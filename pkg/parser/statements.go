@@ -5,11 +5,19 @@ import (
 
 	ast2 "github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/lexer"
-	"github.com/perbu/vclparser/pkg/types"
 )
 
-// parseStatement parses a statement
+// parseStatement parses a statement, returning nil if it cannot
 func (p *Parser) parseStatement() ast2.Statement {
+	stmt := p.parseStatementNode()
+	if isNilNode(stmt) {
+		return nil
+	}
+	return stmt
+}
+
+// parseStatementNode dispatches on the statement keyword
+func (p *Parser) parseStatementNode() ast2.Statement {
 	if p.maxErrorsReached {
 		return nil
 	}
@@ -29,6 +37,17 @@ func (p *Parser) parseStatement() ast2.Statement {
 		}
 	}
 
+	if p.config.Legacy {
+		if stmt, ok := p.parseLegacyStatement(); ok {
+			return stmt
+		}
+	}
+	if p.fastly() {
+		if stmt, ok := p.parseFastlyStatement(); ok {
+			return stmt
+		}
+	}
+
 	switch p.currentToken.Type {
 	case lexer.IF_KW:
 		return p.parseIfStatement()
@@ -57,6 +76,12 @@ func (p *Parser) parseStatement() ast2.Statement {
 		}
 		return p.parseCSourceStatement()
 	default:
+		if p.currentTokenIs(lexer.ID) {
+			if keyword := suggestKeyword(p.currentToken.Value, statementKeywords); keyword != "" {
+				p.addErrorWithSuggestion(fmt.Sprintf("unknown statement %q", p.currentToken.Value), keyword)
+				return nil
+			}
+		}
 		// Try to parse as expression statement
 		return p.parseExpressionStatement()
 	}
@@ -67,27 +92,48 @@ func (p *Parser) parseStatement() ast2.Statement {
 // individual statements fail to parse, allowing the parser to continue
 // processing the remaining statements in the block.
 func (p *Parser) parseBlockStatement() *ast2.BlockStatement {
-	stmt := &ast2.BlockStatement{
+	stmt := ast2.New(p.arena, ast2.BlockStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectToken(lexer.LBRACE) {
 		return nil
 	}
 
 	p.nextToken() // move past '{'
+	stmt.Statements = p.parseStatementList()
 
+	if !p.expectToken(lexer.RBRACE) {
+		return nil
+	}
+
+	stmt.EndPos = p.currentToken.End
+	return stmt
+}
+
+// parseStatementList parses statements up to a closing brace or the end of
+// the input, leaving the current token there
+func (p *Parser) parseStatementList() []ast2.Statement {
+	var statements []ast2.Statement
 	for !p.currentTokenIs(lexer.RBRACE) && !p.currentTokenIs(lexer.EOF) && !p.maxErrorsReached {
 		if p.currentTokenIs(lexer.COMMENT) {
 			p.nextToken()
 			continue
 		}
+		// Like varnishd, accept empty statements such as the second ';'
+		// of "set req.http.x = 1;;"
+		if p.currentTokenIs(lexer.SEMICOLON) {
+			p.addWarning("empty-statement", "empty statement: stray ';'", p.currentToken)
+			p.nextToken()
+			continue
+		}
 
+		start := p.currentToken.Start.Offset
 		statement := p.parseStatement()
 		if statement != nil {
-			stmt.Statements = append(stmt.Statements, statement)
+			statements = append(statements, statement)
 			p.nextToken()
 		} else {
 			// Error recovery: skip to next statement or closing brace
@@ -97,28 +143,25 @@ func (p *Parser) parseBlockStatement() *ast2.BlockStatement {
 			)
 			if p.currentTokenIs(lexer.SEMICOLON) {
 				p.nextToken() // consume semicolon and continue
+			} else if p.currentToken.Start.Offset == start && !p.currentTokenIs(lexer.RBRACE) {
+				p.nextToken() // the statement failed where it began; don't retry it forever
 			}
 		}
 	}
-
-	if !p.expectToken(lexer.RBRACE) {
-		return nil
-	}
-
-	stmt.EndPos = p.currentToken.End
-	return stmt
+	return statements
 }
 
 // parseIfStatement parses if/else conditional statements.
 // Supports multiple else variants (else, elseif, elsif, elif) and handles
 // nested if-else chains. Automatically converts "else if" into nested
-// IfStatement structures for consistent AST representation.
+// IfStatement structures for consistent AST representation, keeping the
+// keyword of elseif, elsif and elif in the nested statement.
 func (p *Parser) parseIfStatement() *ast2.IfStatement {
-	stmt := &ast2.IfStatement{
+	stmt := ast2.New(p.arena, ast2.IfStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if !p.expectPeek(lexer.LPAREN) {
 		return nil
@@ -156,7 +199,12 @@ func (p *Parser) parseIfStatement() *ast2.IfStatement {
 			}
 		} else {
 			// elseif/elsif/elif
-			stmt.Else = p.parseIfStatement()
+			keyword := p.currentToken.Value
+			elseIf := p.parseIfStatement()
+			if elseIf != nil {
+				elseIf.Keyword = keyword
+			}
+			stmt.Else = elseIf
 		}
 	}
 
@@ -169,11 +217,11 @@ func (p *Parser) parseIfStatement() *ast2.IfStatement {
 // types of value modifications. Handles both simple and complex expressions
 // as assignment targets and values.
 func (p *Parser) parseSetStatement() *ast2.SetStatement {
-	stmt := &ast2.SetStatement{
+	stmt := ast2.New(p.arena, ast2.SetStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move past 'set'
 	stmt.Variable = p.parseExpression()
@@ -186,6 +234,9 @@ func (p *Parser) parseSetStatement() *ast2.SetStatement {
 		stmt.Operator = p.currentToken.Value
 		p.nextToken()
 		stmt.Value = p.parseExpression()
+		if stmt.Value == nil {
+			return nil
+		}
 	} else {
 		p.addError("expected assignment operator")
 		return nil
@@ -209,14 +260,17 @@ func (p *Parser) parseSetStatement() *ast2.SetStatement {
 
 // parseUnsetStatement parses an unset statement
 func (p *Parser) parseUnsetStatement() *ast2.UnsetStatement {
-	stmt := &ast2.UnsetStatement{
+	stmt := ast2.New(p.arena, ast2.UnsetStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move past 'unset'
 	stmt.Variable = p.parseExpression()
+	if stmt.Variable == nil {
+		return nil
+	}
 
 	// Set end position safely
 	if p.currentToken.Type != lexer.EOF {
@@ -236,11 +290,11 @@ func (p *Parser) parseUnsetStatement() *ast2.UnsetStatement {
 
 // parseCallStatement parses a call statement
 func (p *Parser) parseCallStatement() *ast2.CallStatement {
-	stmt := &ast2.CallStatement{
+	stmt := ast2.New(p.arena, ast2.CallStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move past 'call'
 
@@ -251,22 +305,16 @@ func (p *Parser) parseCallStatement() *ast2.CallStatement {
 	}
 
 	// Create identifier for the subroutine name
-	stmt.Function = &ast2.Identifier{
+	stmt.Function = ast2.New(p.arena, ast2.Identifier{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Name: p.currentToken.Value,
-	}
+	})
 
-	// Validate that the called subroutine exists
-	if symbol := p.symbolTable.Lookup(p.currentToken.Value); symbol == nil {
-		p.addError(fmt.Sprintf("undefined subroutine: %s", p.currentToken.Value))
-		return nil
-	} else if symbol.Kind != types.SymbolSubroutine {
-		p.addError(fmt.Sprintf("'%s' is not a subroutine", p.currentToken.Value))
-		return nil
-	}
+	// Whether the subroutine exists is checked by the analyzer, as it may
+	// be defined in an included file
 
 	stmt.EndPos = p.currentToken.End
 
@@ -281,11 +329,11 @@ func (p *Parser) parseCallStatement() *ast2.CallStatement {
 
 // parseReturnStatement parses a return statement
 func (p *Parser) parseReturnStatement() *ast2.ReturnStatement {
-	stmt := &ast2.ReturnStatement{
+	stmt := ast2.New(p.arena, ast2.ReturnStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if p.peekTokenIs(lexer.LPAREN) {
 		p.nextToken() // move past 'return'
@@ -309,11 +357,11 @@ func (p *Parser) parseReturnStatement() *ast2.ReturnStatement {
 
 // parseSyntheticStatement parses a synthetic statement
 func (p *Parser) parseSyntheticStatement() *ast2.SyntheticStatement {
-	stmt := &ast2.SyntheticStatement{
+	stmt := ast2.New(p.arena, ast2.SyntheticStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if p.peekTokenIs(lexer.LPAREN) {
 		p.nextToken() // move past 'synthetic'
@@ -335,11 +383,11 @@ func (p *Parser) parseSyntheticStatement() *ast2.SyntheticStatement {
 
 // parseErrorStatement parses an error statement
 func (p *Parser) parseErrorStatement() *ast2.ErrorStatement {
-	stmt := &ast2.ErrorStatement{
+	stmt := ast2.New(p.arena, ast2.ErrorStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	if p.peekTokenIs(lexer.LPAREN) {
 		p.nextToken() // move past 'error'
@@ -366,12 +414,12 @@ func (p *Parser) parseErrorStatement() *ast2.ErrorStatement {
 
 // parseRestartStatement parses a restart statement
 func (p *Parser) parseRestartStatement() *ast2.RestartStatement {
-	stmt := &ast2.RestartStatement{
+	stmt := ast2.New(p.arena, ast2.RestartStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
-	}
+	})
 
 	p.skipSemicolon()
 	return stmt
@@ -379,24 +427,24 @@ func (p *Parser) parseRestartStatement() *ast2.RestartStatement {
 
 // parseCSourceStatement parses a C source statement
 func (p *Parser) parseCSourceStatement() *ast2.CSourceStatement {
-	stmt := &ast2.CSourceStatement{
+	stmt := ast2.New(p.arena, ast2.CSourceStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 			EndPos:   p.currentToken.End,
 		},
 		Code: p.currentToken.Value,
-	}
+	})
 
 	return stmt
 }
 
 // parseNewStatement parses a new statement for VMOD object instantiation
 func (p *Parser) parseNewStatement() *ast2.NewStatement {
-	stmt := &ast2.NewStatement{
+	stmt := ast2.New(p.arena, ast2.NewStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	p.nextToken() // move past 'new'
 	stmt.Name = p.parseExpression()
@@ -437,11 +485,11 @@ func (p *Parser) parseNewStatement() *ast2.NewStatement {
 
 // parseExpressionStatement parses an expression statement
 func (p *Parser) parseExpressionStatement() *ast2.ExpressionStatement {
-	stmt := &ast2.ExpressionStatement{
+	stmt := ast2.New(p.arena, ast2.ExpressionStatement{
 		BaseNode: ast2.BaseNode{
 			StartPos: p.currentToken.Start,
 		},
-	}
+	})
 
 	stmt.Expression = p.parseExpression()
 	if stmt.Expression == nil {
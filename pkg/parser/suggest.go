@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/perbu/vclparser/internal/strutil"
+)
+
+// statementKeywords are the words that can start a statement
+var statementKeywords = []string{
+	"call", "else", "elsif", "if", "new", "restart", "return", "set", "synthetic", "unset",
+}
+
+// declarationKeywords are the words that can start a top-level declaration
+var declarationKeywords = []string{
+	"acl", "backend", "import", "include", "probe", "sub",
+}
+
+// suggestKeyword returns the keyword word is most likely a misspelling of,
+// or "" if none is close. Longer words may be further from the keyword:
+// one edit for words of up to four letters, two otherwise.
+func suggestKeyword(word string, keywords []string) string {
+	if word == "" || strings.Contains(word, ".") {
+		return ""
+	}
+	lower := strings.ToLower(word)
+	limit := 1
+	if len(lower) > 4 {
+		limit = 2
+	}
+	best, bestDistance := "", limit+1
+	for _, keyword := range keywords {
+		if d := strutil.EditDistance(lower, keyword); d < bestDistance {
+			best, bestDistance = keyword, d
+		}
+	}
+	return best
+}
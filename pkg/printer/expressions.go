@@ -0,0 +1,239 @@
+package printer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// Operator precedence, mirroring the parser. Higher binds tighter.
+const (
+	precLowest = iota
+	precOr
+	precAnd
+	precEquality
+	precComparison
+	precRegex
+	precTerm
+	precFactor
+	precUnary
+)
+
+var binaryPrecedence = map[string]int{
+	"||": precOr,
+	"&&": precAnd,
+	"==": precEquality,
+	"!=": precEquality,
+	"<":  precComparison,
+	">":  precComparison,
+	"<=": precComparison,
+	">=": precComparison,
+	"~":  precRegex,
+	"!~": precRegex,
+	"+":  precTerm,
+	"-":  precTerm,
+	"*":  precFactor,
+	"/":  precFactor,
+	"%":  precFactor,
+}
+
+// expr formats an expression on a single line
+func (p *printer) expr(e ast.Expression) string {
+	var sb strings.Builder
+	p.writeExpr(&sb, e, precLowest)
+	return sb.String()
+}
+
+// writeExpr formats e, adding parentheses if it binds less tightly than
+// the surrounding operator. The parser keeps the parentheses of the source
+// as ParenthesizedExpression nodes, so they are only added here for trees
+// built by hand.
+func (p *printer) writeExpr(sb *strings.Builder, e ast.Expression, outer int) {
+	switch e := e.(type) {
+	case *ast.Identifier:
+		sb.WriteString(e.Name)
+	case *ast.VariableExpression:
+		sb.WriteString(e.Name)
+	case *ast.StringLiteral:
+		if e.Long {
+			sb.WriteString(longQuote(e.Value))
+		} else {
+			sb.WriteString(quote(e.Value))
+		}
+	case *ast.IntegerLiteral:
+		sb.WriteString(strconv.FormatInt(e.Value, 10))
+	case *ast.FloatLiteral:
+		s := strconv.FormatFloat(e.Value, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		sb.WriteString(s)
+	case *ast.BooleanLiteral:
+		sb.WriteString(strconv.FormatBool(e.Value))
+	case *ast.DurationLiteral:
+		sb.WriteString(p.duration(e.Value))
+	case *ast.TimeExpression:
+		sb.WriteString(p.duration(e.Value))
+	case *ast.IPExpression:
+		sb.WriteString(e.Value)
+	case *ast.ParenthesizedExpression:
+		sb.WriteByte('(')
+		p.writeExpr(sb, e.Expression, precLowest)
+		sb.WriteByte(')')
+	case *ast.UnaryExpression:
+		p.wrap(sb, outer > precUnary, func() {
+			sb.WriteString(e.Operator)
+			p.writeExpr(sb, e.Operand, precUnary)
+		})
+	case *ast.BinaryExpression:
+		p.binary(sb, e.Left, e.Operator, e.Right, outer)
+	case *ast.RegexMatchExpression:
+		p.binary(sb, e.Left, e.Operator, e.Right, outer)
+	case *ast.MemberExpression:
+		p.writeExpr(sb, e.Object, precUnary+1)
+		sb.WriteByte('.')
+		p.writeExpr(sb, e.Property, precUnary+1)
+	case *ast.IndexExpression:
+		p.writeExpr(sb, e.Object, precUnary+1)
+		sb.WriteByte('[')
+		p.writeExpr(sb, e.Index, precLowest)
+		sb.WriteByte(']')
+	case *ast.CallExpression:
+		p.call(sb, e)
+	case *ast.AssignmentExpression:
+		p.writeExpr(sb, e.Left, precLowest)
+		sb.WriteString(" " + e.Operator + " ")
+		p.writeExpr(sb, e.Right, precLowest)
+	case *ast.UpdateExpression:
+		if e.Prefix {
+			sb.WriteString(e.Operator)
+		}
+		p.writeExpr(sb, e.Operand, precUnary+1)
+		if !e.Prefix {
+			sb.WriteString(e.Operator)
+		}
+	case *ast.ArrayExpression:
+		sb.WriteByte('[')
+		for i, elem := range e.Elements {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			p.writeExpr(sb, elem, precLowest)
+		}
+		sb.WriteByte(']')
+	case *ast.ObjectExpression:
+		// Multi-line objects are printed by property; this is the inline
+		// form used inside other expressions
+		sb.WriteString("{")
+		for _, prop := range e.Properties {
+			sb.WriteString(" " + p.propertyKey(prop.Key) + " = ")
+			p.writeExpr(sb, prop.Value, precLowest)
+			sb.WriteString(";")
+		}
+		sb.WriteString(" }")
+	default:
+		p.fail(e)
+	}
+}
+
+func (p *printer) binary(sb *strings.Builder, left ast.Expression, op string, right ast.Expression, outer int) {
+	prec, ok := binaryPrecedence[op]
+	if !ok {
+		prec = precLowest
+	}
+	// Operators associate to the left, so a right operand of the same
+	// precedence needs parentheses
+	p.wrap(sb, outer > prec, func() {
+		p.writeExpr(sb, left, prec)
+		sb.WriteString(" " + op + " ")
+		p.writeExpr(sb, right, prec+1)
+	})
+}
+
+// wrap writes the output of fn, in parentheses if paren is set
+func (p *printer) wrap(sb *strings.Builder, paren bool, fn func()) {
+	if paren {
+		sb.WriteByte('(')
+	}
+	fn()
+	if paren {
+		sb.WriteByte(')')
+	}
+}
+
+// call formats a function or method call. Named arguments follow the
+// positional ones in source order, or by name for trees built by hand.
+func (p *printer) call(sb *strings.Builder, call *ast.CallExpression) {
+	p.writeExpr(sb, call.Function, precUnary+1)
+	sb.WriteByte('(')
+
+	for i, arg := range call.Arguments {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		p.writeExpr(sb, arg, precLowest)
+	}
+
+	names := make([]string, 0, len(call.NamedArguments))
+	for name := range call.NamedArguments {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := call.NamedArguments[names[i]].Start(), call.NamedArguments[names[j]].Start()
+		if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+		return names[i] < names[j]
+	})
+	for i, name := range names {
+		if i > 0 || len(call.Arguments) > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name + " = ")
+		p.writeExpr(sb, call.NamedArguments[name], precLowest)
+	}
+
+	sb.WriteByte(')')
+}
+
+// propertyKey formats the key of an object property such as .url
+func (p *printer) propertyKey(key ast.Expression) string {
+	if id, ok := key.(*ast.Identifier); ok {
+		return "." + strings.TrimPrefix(id.Name, ".")
+	}
+	return p.expr(key)
+}
+
+// duration formats a duration literal, rewriting compound literals if the
+// config asks for it
+func (p *printer) duration(value string) string {
+	if !p.cfg.NormalizeDurations {
+		return value
+	}
+	if normalized, err := parser.NormalizeDuration(value); err == nil {
+		return normalized
+	}
+	return value
+}
+
+// quote formats a string literal. The parser keeps the text between the
+// quotes as written, so it is printed back unchanged; text that cannot
+// appear in a "..." string uses the long string form.
+func quote(s string) string {
+	if strings.ContainsAny(s, "\"\n") {
+		return longQuote(s)
+	}
+	return `"` + s + `"`
+}
+
+// longQuote formats a long string literal, using the """...""" form for
+// text containing "}
+func longQuote(s string) string {
+	if strings.Contains(s, `"}`) {
+		return `"""` + s + `"""`
+	}
+	return `{"` + s + `"}`
+}
@@ -0,0 +1,580 @@
+// Package printer formats VCL syntax trees as source text.
+//
+// The output is canonical: indentation, brace placement and spacing are
+// decided by the Config rather than the original source, so printing a
+// parsed program and parsing the result yields an equivalent tree. Format
+// additionally carries the comments of the original source over.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// BraceStyle selects where opening braces are placed
+type BraceStyle int
+
+const (
+	// BraceSameLine puts the opening brace at the end of the line that
+	// introduces the block, as in "sub vcl_recv {"
+	BraceSameLine BraceStyle = iota
+	// BraceNextLine puts the opening brace on a line of its own
+	BraceNextLine
+)
+
+// Config holds the formatting options
+type Config struct {
+	// Indent is the string used for each indentation level
+	Indent string
+	// BraceStyle selects where opening braces are placed
+	BraceStyle BraceStyle
+	// AlignProperties pads backend, probe and object property names so
+	// their '=' line up
+	AlignProperties bool
+	// NormalizeDurations rewrites compound durations such as 1h30m as a
+	// single unit (90m), which releases without compound literals accept
+	NormalizeDurations bool
+}
+
+// DefaultConfig returns the canonical formatting options
+func DefaultConfig() *Config {
+	return &Config{
+		Indent:          "    ",
+		BraceStyle:      BraceSameLine,
+		AlignProperties: true,
+	}
+}
+
+// Fprint writes a program formatted with the default options
+func Fprint(w io.Writer, program *ast.Program) error {
+	return DefaultConfig().Fprint(w, program)
+}
+
+// Format parses VCL source and returns it formatted with the default
+// options, keeping its comments
+func Format(src, filename string) (string, error) {
+	return DefaultConfig().Format(src, filename)
+}
+
+// Expression formats an expression on a single line as it appears in
+// formatted VCL
+func Expression(e ast.Expression) string {
+	p := &printer{cfg: DefaultConfig()}
+	return p.expr(e)
+}
+
+// Node formats a single declaration, statement, expression, backend,
+// probe or object property or ACL entry as it appears in a formatted
+// program, at the outermost indentation and without a final newline
+func (c *Config) Node(node ast.Node) (string, error) {
+	p := &printer{cfg: c, suppressBlank: true}
+	switch n := node.(type) {
+	case ast.Expression:
+		text := p.expr(n)
+		return text, p.err
+	case *ast.VCLVersionDecl:
+		p.line("vcl "+n.Version+";", n.End())
+	case ast.Declaration:
+		p.declaration(n)
+	case ast.Statement:
+		p.statement(n)
+	case *ast.BackendProperty:
+		p.property("."+n.Name, 0, n.Value, n.End())
+	case *ast.ProbeProperty:
+		p.property("."+n.Name, 0, n.Value, n.End())
+	case *ast.Property:
+		p.property(p.propertyKey(n.Key), 0, n.Value, n.End())
+	case *ast.ACLEntry:
+		p.aclEntry(n)
+	default:
+		p.fail(node)
+	}
+	p.endLine(lexer.Position{Offset: math.MaxInt})
+	if p.err != nil {
+		return "", p.err
+	}
+	return strings.TrimSuffix(p.buf.String(), "\n"), nil
+}
+
+// Fprint writes a program formatted according to the config. Comments are
+// not part of the syntax tree, so they are not printed; use Format to keep
+// them.
+func (c *Config) Fprint(w io.Writer, program *ast.Program) error {
+	return c.fprint(w, program, nil)
+}
+
+// Format parses VCL source and returns it formatted according to the
+// config. Comments are kept on their own line before the declaration,
+// statement or property that follows them, or at the end of the line they
+// trailed.
+func (c *Config) Format(src, filename string) (string, error) {
+	program, err := parser.Parse(src, filename)
+	if err != nil {
+		return "", err
+	}
+
+	var comments []lexer.Token
+	for _, tok := range lexer.New(src, filename).TokenizeAll() {
+		if tok.Type == lexer.COMMENT {
+			comments = append(comments, tok)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.fprint(&buf, program, comments); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// FprintComments is Fprint for a program parsed from source whose comment
+// tokens are given, which are placed as Format places them. It prints
+// programs changed after parsing, such as by a rewrite, with their comments.
+func (c *Config) FprintComments(w io.Writer, program *ast.Program, comments []lexer.Token) error {
+	return c.fprint(w, program, comments)
+}
+
+func (c *Config) fprint(w io.Writer, program *ast.Program, comments []lexer.Token) error {
+	p := &printer{
+		cfg:           c,
+		comments:      comments,
+		suppressBlank: true,
+	}
+	p.program(program)
+	if p.err != nil {
+		return p.err
+	}
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// printer holds the state of one formatting run
+type printer struct {
+	cfg      *Config
+	buf      bytes.Buffer
+	depth    int
+	comments []lexer.Token
+	next     int // index of the next comment to print
+	// lineOpen is set while the last line written has no newline yet; openEnd
+	// is the source position its node ends at
+	lineOpen bool
+	openEnd  lexer.Position
+	// lastLine is the source line of the last node or comment printed, used
+	// to keep blank lines; it is 0 for nodes without positions
+	lastLine int
+	// suppressBlank is set at the start of a block and after a blank line,
+	// where another blank line is not wanted
+	suppressBlank bool
+	err           error
+}
+
+func (p *printer) program(program *ast.Program) {
+	if program.VCLVersion != nil {
+		p.before(program.VCLVersion.Start())
+		p.line("vcl "+program.VCLVersion.Version+";", program.VCLVersion.End())
+	}
+
+	var prev ast.Declaration
+	for _, decl := range program.Declarations {
+		// Consecutive imports and includes stay grouped as in the source;
+		// everything else is separated by a blank line
+		if program.VCLVersion != nil || prev != nil {
+			if !isPreamble(prev) || !isPreamble(decl) {
+				p.blankLine()
+			}
+		}
+		p.before(decl.Start())
+		p.declaration(decl)
+		prev = decl
+	}
+
+	p.flushComments(lexer.Position{Offset: math.MaxInt})
+	p.endLine(lexer.Position{Offset: math.MaxInt})
+}
+
+// isPreamble reports whether a declaration belongs in the import/include
+// group at the top of a file
+func isPreamble(decl ast.Declaration) bool {
+	switch decl.(type) {
+	case *ast.ImportDecl, *ast.IncludeDecl:
+		return true
+	}
+	return false
+}
+
+func (p *printer) declaration(decl ast.Declaration) {
+	switch d := decl.(type) {
+	case *ast.ImportDecl:
+		text := "import " + d.Module
+		if d.Alias != "" {
+			text += " " + d.Alias
+		}
+		if d.From != "" {
+			text += " from " + quote(d.From)
+		}
+		p.line(text+";", d.End())
+	case *ast.IncludeDecl:
+		text := "include "
+		if d.Glob {
+			text += "+glob "
+		}
+		p.line(text+quote(d.Path)+";", d.End())
+	case *ast.BackendDecl:
+		p.open("backend " + d.Name)
+		names := make([]string, len(d.Properties))
+		for i, prop := range d.Properties {
+			names[i] = "." + prop.Name
+		}
+		width := p.nameWidth(names)
+		for i, prop := range d.Properties {
+			p.before(prop.Start())
+			p.property(names[i], width, prop.Value, prop.End())
+		}
+		p.close(d.End())
+	case *ast.ProbeDecl:
+		p.open("probe " + d.Name)
+		names := make([]string, len(d.Properties))
+		for i, prop := range d.Properties {
+			names[i] = "." + prop.Name
+		}
+		width := p.nameWidth(names)
+		for i, prop := range d.Properties {
+			p.before(prop.Start())
+			p.property(names[i], width, prop.Value, prop.End())
+		}
+		p.close(d.End())
+	case *ast.ACLDecl:
+		p.open("acl " + d.Name)
+		for _, entry := range d.Entries {
+			p.before(entry.Start())
+			p.aclEntry(entry)
+		}
+		p.close(d.End())
+	case *ast.SubDecl:
+		head := "sub " + d.Name
+		if d.ReturnType != "" {
+			head += " " + d.ReturnType
+		}
+		p.open(head)
+		p.body(d.Body)
+		p.close(d.End())
+	case *ast.TableDecl:
+		head := "table " + d.Name
+		if d.ValueType != "" {
+			head += " " + d.ValueType
+		}
+		p.open(head)
+		for _, entry := range d.Entries {
+			p.before(entry.Start())
+			p.line(quote(entry.Key)+": "+p.expr(entry.Value)+",", entry.End())
+		}
+		p.close(d.End())
+	case *ast.DirectorDecl:
+		p.open("director " + d.Name + " " + d.Policy)
+		for _, prop := range d.Properties {
+			p.before(prop.Start())
+			p.property("."+prop.Name, 0, prop.Value, prop.End())
+		}
+		for _, backend := range d.Backends {
+			p.before(backend.Start())
+			text := "{"
+			for _, prop := range backend.Properties {
+				text += " ." + prop.Name + " = " + p.expr(prop.Value) + ";"
+			}
+			p.line(text+" }", backend.End())
+		}
+		p.close(d.End())
+	default:
+		p.fail(decl)
+	}
+}
+
+// property prints a ".name = value;" line, or a nested object for inline
+// probes
+func (p *printer) property(name string, width int, value ast.Expression, end lexer.Position) {
+	if pad := width - len(name); pad > 0 {
+		name += strings.Repeat(" ", pad)
+	}
+	obj, ok := value.(*ast.ObjectExpression)
+	if !ok {
+		p.line(name+" = "+p.expr(value)+";", end)
+		return
+	}
+
+	p.line(name+" = {", lexer.Position{})
+	p.depth++
+	p.suppressBlank = true
+	names := make([]string, len(obj.Properties))
+	for i, prop := range obj.Properties {
+		names[i] = p.propertyKey(prop.Key)
+	}
+	nested := p.nameWidth(names)
+	for i, prop := range obj.Properties {
+		p.before(prop.Start())
+		p.property(names[i], nested, prop.Value, prop.End())
+	}
+	p.flushComments(obj.End())
+	p.depth--
+	p.line("}", end)
+}
+
+// nameWidth returns the column to pad property names to
+func (p *printer) nameWidth(names []string) int {
+	if !p.cfg.AlignProperties {
+		return 0
+	}
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	return width
+}
+
+// aclEntry prints an ACL entry line
+func (p *printer) aclEntry(entry *ast.ACLEntry) {
+	text := p.aclNetwork(entry.Network)
+	if entry.Negated {
+		text = "!" + text
+	}
+	p.line(text+";", entry.End())
+}
+
+// aclNetwork prints an ACL entry, keeping a mask attached to its address
+func (p *printer) aclNetwork(network ast.Expression) string {
+	if bin, ok := network.(*ast.BinaryExpression); ok && bin.Operator == "/" {
+		return p.expr(bin.Left) + "/" + p.expr(bin.Right)
+	}
+	return p.expr(network)
+}
+
+// open starts a block introduced by head
+func (p *printer) open(head string) {
+	if p.cfg.BraceStyle == BraceNextLine {
+		p.line(head, lexer.Position{})
+		p.line("{", lexer.Position{})
+	} else {
+		p.line(head+" {", lexer.Position{})
+	}
+	p.depth++
+	p.suppressBlank = true
+}
+
+// close ends a block opened with open, printing the comments inside it
+// that have not been printed yet
+func (p *printer) close(end lexer.Position) {
+	p.flushComments(end)
+	p.depth--
+	p.line("}", end)
+}
+
+// body prints the statements of a block without its braces
+func (p *printer) body(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	for _, stmt := range block.Statements {
+		p.before(stmt.Start())
+		p.statement(stmt)
+	}
+	p.flushComments(block.End())
+}
+
+func (p *printer) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		p.line("{", lexer.Position{})
+		p.depth++
+		p.suppressBlank = true
+		p.body(s)
+		p.depth--
+		p.line("}", s.End())
+	case *ast.IfStatement:
+		p.ifStatement(s, "")
+	case *ast.SetStatement:
+		p.line("set "+p.expr(s.Variable)+" "+s.Operator+" "+p.expr(s.Value)+";", s.End())
+	case *ast.UnsetStatement:
+		p.line("unset "+p.expr(s.Variable)+";", s.End())
+	case *ast.CallStatement:
+		p.line("call "+p.expr(s.Function)+";", s.End())
+	case *ast.ReturnStatement:
+		if s.Action == nil {
+			p.line("return;", s.End())
+		} else {
+			p.line("return ("+p.expr(ast.Unparen(s.Action))+");", s.End())
+		}
+	case *ast.SyntheticStatement:
+		p.line("synthetic("+p.expr(ast.Unparen(s.Response))+");", s.End())
+	case *ast.ErrorStatement:
+		switch {
+		case s.Code == nil:
+			p.line("error;", s.End())
+		case s.Response == nil:
+			p.line("error("+p.expr(s.Code)+");", s.End())
+		default:
+			p.line("error("+p.expr(s.Code)+", "+p.expr(s.Response)+");", s.End())
+		}
+	case *ast.RestartStatement:
+		p.line("restart;", s.End())
+	case *ast.NewStatement:
+		p.line("new "+p.expr(s.Name)+" = "+p.expr(s.Constructor)+";", s.End())
+	case *ast.ExpressionStatement:
+		p.line(p.expr(s.Expression)+";", s.End())
+	case *ast.CSourceStatement:
+		// Inline C is opaque and printed exactly as written
+		p.line(s.Code, s.End())
+	case *ast.DeclareStatement:
+		p.line("declare local "+p.expr(s.Variable)+" "+s.Type+";", s.End())
+	case *ast.AddStatement:
+		p.line("add "+p.expr(s.Variable)+" = "+p.expr(s.Value)+";", s.End())
+	case *ast.EsiStatement:
+		p.line("esi;", s.End())
+	case *ast.LogStatement:
+		p.line("log "+p.expr(s.Message)+";", s.End())
+	case *ast.GotoStatement:
+		p.line("goto "+s.Label+";", s.End())
+	case *ast.LabelStatement:
+		p.line(s.Name+":", s.End())
+	default:
+		p.fail(stmt)
+	}
+}
+
+// ifStatement prints an if/else chain. prefix is "} else " when an else-if
+// continues on the closing brace line of the previous branch.
+func (p *printer) ifStatement(s *ast.IfStatement, prefix string) {
+	keyword := "if"
+	if s.Keyword != "" {
+		keyword = s.Keyword
+	}
+	p.open(prefix + keyword + " (" + p.expr(ast.Unparen(s.Condition)) + ")")
+	p.branch(s.Then)
+
+	switch e := s.Else.(type) {
+	case nil:
+		p.close(s.End())
+	case *ast.IfStatement:
+		p.flushComments(e.Start())
+		p.depth--
+		// elsif and its variants replace "else if"
+		elseIf := "else "
+		if e.Keyword != "" {
+			elseIf = ""
+		}
+		if p.cfg.BraceStyle == BraceNextLine {
+			p.line("}", lexer.Position{})
+			p.ifStatement(e, elseIf)
+		} else {
+			p.ifStatement(e, "} "+elseIf)
+		}
+	default:
+		p.flushComments(e.Start())
+		p.depth--
+		if p.cfg.BraceStyle == BraceNextLine {
+			p.line("}", lexer.Position{})
+			p.open("else")
+		} else {
+			p.open("} else")
+		}
+		p.branch(e)
+		p.close(s.End())
+	}
+}
+
+// branch prints the body of an if or else branch
+func (p *printer) branch(stmt ast.Statement) {
+	if block, ok := stmt.(*ast.BlockStatement); ok {
+		p.body(block)
+		return
+	}
+	if stmt != nil {
+		p.before(stmt.Start())
+		p.statement(stmt)
+	}
+}
+
+// line starts one indented line. The line is left open so that a comment
+// trailing end in the source can still be appended to it.
+func (p *printer) line(text string, end lexer.Position) {
+	p.endLine(lexer.Position{Offset: math.MaxInt})
+	p.buf.WriteString(strings.Repeat(p.cfg.Indent, p.depth))
+	p.buf.WriteString(text)
+	p.lineOpen = true
+	p.openEnd = end
+	p.suppressBlank = false
+	if end.Line > 0 {
+		p.lastLine = end.Line
+	}
+}
+
+// endLine terminates the open line, first appending the comments that
+// trailed it in the source and start before next
+func (p *printer) endLine(next lexer.Position) {
+	if !p.lineOpen {
+		return
+	}
+	for p.openEnd.Line > 0 && p.next < len(p.comments) {
+		c := p.comments[p.next]
+		if c.Start.Line != p.openEnd.Line || c.Start.Offset < p.openEnd.Offset ||
+			c.Start.Offset >= next.Offset || strings.Contains(c.Value, "\n") {
+			break
+		}
+		p.buf.WriteString(" " + strings.TrimRight(c.Value, " \t\r"))
+		p.next++
+	}
+	p.buf.WriteByte('\n')
+	p.lineOpen = false
+}
+
+// blankLine separates what follows from what was printed before
+func (p *printer) blankLine() {
+	p.endLine(lexer.Position{Offset: math.MaxInt})
+	if !p.suppressBlank {
+		p.buf.WriteByte('\n')
+		p.suppressBlank = true
+	}
+}
+
+// before prints the comments preceding pos and keeps a blank line before
+// the node at pos if the source had one
+func (p *printer) before(pos lexer.Position) {
+	p.flushComments(pos)
+	p.gap(pos.Line)
+}
+
+// flushComments prints the pending comments that start before pos. Those
+// not trailing the open line get a line of their own.
+func (p *printer) flushComments(pos lexer.Position) {
+	p.endLine(pos)
+	for p.next < len(p.comments) && p.comments[p.next].Start.Offset < pos.Offset {
+		c := p.comments[p.next]
+		p.next++
+		p.gap(c.Start.Line)
+		p.line(strings.TrimRight(c.Value, " \t\r"), lexer.Position{})
+		p.lastLine = c.Start.Line + strings.Count(c.Value, "\n")
+	}
+}
+
+// gap prints a blank line if the source had one before line
+func (p *printer) gap(line int) {
+	if p.lastLine > 0 && line > p.lastLine+1 {
+		p.blankLine()
+	}
+}
+
+// fail records the first node that cannot be printed
+func (p *printer) fail(node ast.Node) {
+	if p.err == nil {
+		if e, ok := node.(*ast.ErrorExpression); ok {
+			p.err = fmt.Errorf("cannot print a program with parse errors: %s at %s", e.Message, e.Start())
+			return
+		}
+		p.err = fmt.Errorf("cannot print %T", node)
+	}
+}
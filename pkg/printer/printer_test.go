@@ -0,0 +1,405 @@
+package printer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/lexer"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "declarations",
+			input: `vcl 4.1;
+import std;
+import directors from "libvmod_directors.so";
+include +glob "conf.d/*.vcl";
+backend default { .host="127.0.0.1"; .first_byte_timeout = 30s;
+  .probe = { .url = "/health"; .interval=5s; } }
+acl local { "localhost"; ! "10.0.0.0"/8; }
+sub vcl_recv { return(hash); }`,
+			expected: `vcl 4.1;
+
+import std;
+import directors from "libvmod_directors.so";
+include +glob "conf.d/*.vcl";
+
+backend default {
+    .host               = "127.0.0.1";
+    .first_byte_timeout = 30s;
+    .probe              = {
+        .url      = "/health";
+        .interval = 5s;
+    }
+}
+
+acl local {
+    "localhost";
+    !"10.0.0.0"/8;
+}
+
+sub vcl_recv {
+    return (hash);
+}
+`,
+		},
+		{
+			name: "statements",
+			input: `vcl 4.1;
+sub helper { set req.http.x-helper = "1"; }
+sub vcl_recv {
+  if (req.http.x) { return(pass); } elsif (req.url ~ "^/a" && !(req.http.y || req.http.z)) {
+    set req.http.y = "a" + req.http.z;
+  } else { call helper; unset req.http.x; }
+  std.log(std.integer(s="1", fallback=2));
+  return (synth(200, "ok"));
+}
+sub vcl_synth { synthetic("body"); restart; }`,
+			expected: `vcl 4.1;
+
+sub helper {
+    set req.http.x-helper = "1";
+}
+
+sub vcl_recv {
+    if (req.http.x) {
+        return (pass);
+    } elsif (req.url ~ "^/a" && !(req.http.y || req.http.z)) {
+        set req.http.y = "a" + req.http.z;
+    } else {
+        call helper;
+        unset req.http.x;
+    }
+    std.log(std.integer(s = "1", fallback = 2));
+    return (synth(200, "ok"));
+}
+
+sub vcl_synth {
+    synthetic("body");
+    restart;
+}
+`,
+		},
+		{
+			name: "else if keywords",
+			input: `vcl 4.1;
+sub vcl_recv { if (req.http.a) { return (pass); } else if (req.http.b) { return (hash); }
+elseif (req.http.c) { return (pipe); } elif (req.http.d) { return (synth(404)); } else { return (lookup); } }`,
+			expected: `vcl 4.1;
+
+sub vcl_recv {
+    if (req.http.a) {
+        return (pass);
+    } else if (req.http.b) {
+        return (hash);
+    } elseif (req.http.c) {
+        return (pipe);
+    } elif (req.http.d) {
+        return (synth(404));
+    } else {
+        return (lookup);
+    }
+}
+`,
+		},
+		{
+			name: "comments and blank lines",
+			input: `# Site config
+vcl 4.1;
+import std;   // logging
+acl office { "192.168.0.1"; "192.168.0.2"; # both desks
+}
+/* Entry point */
+sub vcl_recv {
+  // normalize
+  unset req.http.cookie;
+
+
+  set req.http.x = "1"; # marker
+  // done
+}`,
+			expected: `# Site config
+vcl 4.1;
+
+import std; // logging
+
+acl office {
+    "192.168.0.1";
+    "192.168.0.2"; # both desks
+}
+
+/* Entry point */
+sub vcl_recv {
+    // normalize
+    unset req.http.cookie;
+
+    set req.http.x = "1"; # marker
+    // done
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.input, "test.vcl")
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Format output mismatch\ngot:\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatConfig(t *testing.T) {
+	input := `vcl 4.1;
+backend default { .host = "127.0.0.1"; .connect_timeout = 1m30s; }
+sub vcl_recv { if (req.http.a) { return (pass); } else { return (hash); } }`
+
+	tests := []struct {
+		name     string
+		cfg      *Config
+		expected string
+	}{
+		{
+			name: "next line braces, tabs, no alignment",
+			cfg:  &Config{Indent: "\t", BraceStyle: BraceNextLine},
+			expected: "vcl 4.1;\n\nbackend default\n{\n\t.host = \"127.0.0.1\";\n\t.connect_timeout = 1m30s;\n}\n\n" +
+				"sub vcl_recv\n{\n\tif (req.http.a)\n\t{\n\t\treturn (pass);\n\t}\n\telse\n\t{\n\t\treturn (hash);\n\t}\n}\n",
+		},
+		{
+			name: "normalized durations",
+			cfg:  &Config{Indent: "  ", NormalizeDurations: true},
+			expected: "vcl 4.1;\n\nbackend default {\n  .host = \"127.0.0.1\";\n  .connect_timeout = 90s;\n}\n\n" +
+				"sub vcl_recv {\n  if (req.http.a) {\n    return (pass);\n  } else {\n    return (hash);\n  }\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.Format(input, "test.vcl")
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Format output mismatch\ngot:\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFprintAddsParentheses(t *testing.T) {
+	ident := func(name string) *ast.Identifier { return &ast.Identifier{Name: name} }
+
+	// (a || b) && c, built without ParenthesizedExpression nodes
+	cond := &ast.BinaryExpression{
+		Left:     &ast.BinaryExpression{Left: ident("a"), Operator: "||", Right: ident("b")},
+		Operator: "&&",
+		Right:    ident("c"),
+	}
+	// x - (y - 1)
+	value := &ast.BinaryExpression{
+		Left:     ident("x"),
+		Operator: "-",
+		Right:    &ast.BinaryExpression{Left: ident("y"), Operator: "-", Right: &ast.IntegerLiteral{Value: 1}},
+	}
+	program := &ast.Program{
+		VCLVersion: &ast.VCLVersionDecl{Version: "4.1"},
+		Declarations: []ast.Declaration{
+			&ast.SubDecl{Name: "vcl_recv", Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.IfStatement{
+					Condition: cond,
+					Then: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.SetStatement{Variable: ident("req.ttl"), Operator: "=", Value: value},
+					}},
+				},
+			}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Fprint(&buf, program); err != nil {
+		t.Fatalf("Fprint failed: %v", err)
+	}
+	expected := `vcl 4.1;
+
+sub vcl_recv {
+    if ((a || b) && c) {
+        set req.ttl = x - (y - 1);
+    }
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Fprint output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestExpression(t *testing.T) {
+	cond := &ast.BinaryExpression{
+		Left:     &ast.BinaryExpression{Left: &ast.Identifier{Name: "a"}, Operator: "||", Right: &ast.Identifier{Name: "b"}},
+		Operator: "&&",
+		Right:    &ast.StringLiteral{Value: "c"},
+	}
+	if got := Expression(cond); got != `(a || b) && "c"` {
+		t.Errorf("Expression: got %s", got)
+	}
+}
+
+func TestNode(t *testing.T) {
+	tests := []struct {
+		node ast.Node
+		want string
+	}{
+		{&ast.UnsetStatement{Variable: &ast.Identifier{Name: "req.http.Cookie"}}, "unset req.http.Cookie;"},
+		{&ast.IfStatement{
+			Condition: &ast.Identifier{Name: "req.http.X"},
+			Then: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ReturnStatement{Action: &ast.Identifier{Name: "pass"}},
+			}},
+		}, "if (req.http.X) {\n    return (pass);\n}"},
+		{&ast.BackendProperty{Name: "port", Value: &ast.StringLiteral{Value: "80"}}, `.port = "80";`},
+		{&ast.ACLEntry{Negated: true, Network: &ast.StringLiteral{Value: "10.0.0.1"}}, `!"10.0.0.1";`},
+		{&ast.VCLVersionDecl{Version: "4.1"}, "vcl 4.1;"},
+		{&ast.ImportDecl{Module: "std"}, "import std;"},
+		{&ast.IntegerLiteral{Value: 3}, "3"},
+	}
+	for _, tt := range tests {
+		got, err := DefaultConfig().Node(tt.node)
+		if err != nil || got != tt.want {
+			t.Errorf("Node(%s) = %q, %v; want %q", tt.node, got, err, tt.want)
+		}
+	}
+
+	if _, err := DefaultConfig().Node(&ast.Program{}); err == nil {
+		t.Error("Expected an error for a program")
+	}
+}
+
+func TestFprintFastly(t *testing.T) {
+	src := `table t STRING { "a":"b", }
+director d random { .quorum=50%; { .backend=F; .weight=1; } }
+sub f STRING { declare local var.x STRING; set var.x = "a" req.url; return var.x; }
+sub vcl_recv { add req.http.X = "1"; esi; log "a" req.url; goto x; x: error 801 "r"; }
+`
+	expected := `table t STRING {
+    "a": "b",
+}
+
+director d random {
+    .quorum = 50%;
+    { .backend = F; .weight = 1; }
+}
+
+sub f STRING {
+    declare local var.x STRING;
+    set var.x = "a" + req.url;
+    return (var.x);
+}
+
+sub vcl_recv {
+    add req.http.X = "1";
+    esi;
+    log "a" + req.url;
+    goto x;
+    x:
+    error(801, "r");
+}
+`
+	config := parser.DefaultConfig()
+	config.Dialect = lexer.DialectFastly
+	for _, input := range []string{src, expected} {
+		program, err := parser.ParseWithConfig(input, "main.vcl", config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err := Fprint(&out, program); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != expected {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+	}
+}
+
+func TestFprintErrors(t *testing.T) {
+	program := &ast.Program{
+		VCLVersion: &ast.VCLVersionDecl{Version: "4.1"},
+		Declarations: []ast.Declaration{
+			&ast.SubDecl{Name: "vcl_recv", Body: &ast.BlockStatement{Statements: []ast.Statement{
+				&ast.ExpressionStatement{Expression: &ast.ErrorExpression{Message: "expression recovery"}},
+			}}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Fprint(&buf, program); err == nil || !strings.Contains(err.Error(), "parse errors") {
+		t.Errorf("Expected a parse error complaint, got %v", err)
+	}
+
+	if _, err := Format("vcl 4.1; sub vcl_recv { set = ; }", "test.vcl"); err == nil {
+		t.Error("Expected Format to fail on invalid VCL")
+	}
+}
+
+// TestFormatRoundTrip checks that formatting the test data is stable and
+// does not change the meaning of the program
+func TestFormatRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("../../tests/testdata/*.vcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	more, _ := filepath.Glob("../../tests/testdata/includes/*.vcl")
+	files = append(files, more...)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			src, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original, err := parser.Parse(string(src), file)
+			if err != nil {
+				t.Skipf("test data does not parse on its own: %v", err)
+			}
+
+			formatted, err := Format(string(src), file)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			again, err := Format(formatted, file)
+			if err != nil {
+				t.Fatalf("formatted output does not parse: %v\n%s", err, formatted)
+			}
+			if again != formatted {
+				t.Errorf("formatting is not idempotent\nfirst:\n%s\nsecond:\n%s", formatted, again)
+			}
+
+			reparsed, err := parser.Parse(formatted, file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got bytes.Buffer
+			if err := Fprint(&want, original); err != nil {
+				t.Fatal(err)
+			}
+			if err := Fprint(&got, reparsed); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("formatting changed the program\nbefore:\n%s\nafter:\n%s", want.String(), got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Package report renders parser and analyzer diagnostics in formats understood
+// by editors and other tooling.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+)
+
+// Format selects how diagnostics are rendered
+type Format string
+
+const (
+	// FormatVim produces "file:line:col: severity: message" lines that match
+	// Vim's default errorformat, so :make works without configuration.
+	FormatVim Format = "vim"
+	// FormatEmacs produces GNU-style "file:line.col: severity: message" lines
+	// as recognised by Emacs compilation-mode.
+	FormatEmacs Format = "emacs"
+	// FormatJSON produces a JSON array with one object per diagnostic, for
+	// CI jobs and other tools.
+	FormatJSON Format = "json"
+	// FormatSARIF produces a SARIF 2.1.0 log, which GitHub code scanning
+	// and GitLab CI display as annotations.
+	FormatSARIF Format = "sarif"
+)
+
+// Formats lists the supported output formats
+var Formats = []Format{FormatVim, FormatEmacs, FormatJSON, FormatSARIF}
+
+// ParseFormat converts a format name into a Format
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q", name)
+}
+
+// Write renders diagnostics to w in the given format, one per line. The
+// JSON and SARIF formats write a single document, so all diagnostics should
+// be written with one call.
+func Write(w io.Writer, format Format, diags []analyzer.Diagnostic) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, diags)
+	case FormatSARIF:
+		return writeSARIF(w, diags)
+	}
+	for _, d := range diags {
+		var line string
+		switch format {
+		case FormatVim:
+			line = vimLine(d)
+		case FormatEmacs:
+			line = emacsLine(d)
+		default:
+			return fmt.Errorf("unknown output format %q", format)
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vimLine formats a diagnostic as file:line:col: severity: message [code]
+func vimLine(d analyzer.Diagnostic) string {
+	if d.Position.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", filename(d), d.Severity, message(d))
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", filename(d), d.Position.Line, column(d),
+		d.Severity, message(d))
+}
+
+// emacsLine formats a diagnostic following the GNU coding standards
+func emacsLine(d analyzer.Diagnostic) string {
+	if d.Position.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", filename(d), d.Severity, message(d))
+	}
+	return fmt.Sprintf("%s:%d.%d: %s: %s", filename(d), d.Position.Line, column(d),
+		d.Severity, message(d))
+}
+
+// message returns the message on one line, followed by the diagnostic code
+// in brackets as compilers do for warning flags
+func message(d analyzer.Diagnostic) string {
+	if d.Code == "" {
+		return oneLine(d.Message)
+	}
+	return oneLine(d.Message) + " [" + d.Code + "]"
+}
+
+func filename(d analyzer.Diagnostic) string {
+	if d.Filename == "" {
+		return "<stdin>"
+	}
+	return d.Filename
+}
+
+// column returns the 1-based column, defaulting to 1 when it is unknown
+func column(d analyzer.Diagnostic) int {
+	if d.Position.Column < 1 {
+		return 1
+	}
+	return d.Position.Column
+}
+
+// oneLine keeps multi-line messages from breaking line-oriented consumers
+func oneLine(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
+
+// jsonDiagnostic is the JSON form of a diagnostic. Positions are omitted
+// when they are unknown.
+type jsonDiagnostic struct {
+	File         string   `json:"file"`
+	Line         int      `json:"line,omitempty"`
+	Column       int      `json:"column,omitempty"`
+	EndLine      int      `json:"endLine,omitempty"`
+	EndColumn    int      `json:"endColumn,omitempty"`
+	Severity     string   `json:"severity"`
+	Code         string   `json:"code,omitempty"`
+	Message      string   `json:"message"`
+	IncludeChain []string `json:"includeChain,omitempty"`
+}
+
+func writeJSON(w io.Writer, diags []analyzer.Diagnostic) error {
+	out := make([]jsonDiagnostic, len(diags))
+	for i, d := range diags {
+		out[i] = jsonDiagnostic{
+			File:         filename(d),
+			Line:         d.Position.Line,
+			Column:       d.Position.Column,
+			EndLine:      d.EndPosition.Line,
+			EndColumn:    d.EndPosition.Column,
+			Severity:     d.Severity.String(),
+			Code:         d.Code,
+			Message:      d.Message,
+			IncludeChain: d.IncludeChain,
+		}
+	}
+	return encodeJSON(w, out)
+}
+
+func encodeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
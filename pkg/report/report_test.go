@@ -0,0 +1,155 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+func TestWrite(t *testing.T) {
+	diags := []analyzer.Diagnostic{
+		{
+			Filename: "default.vcl",
+			Position: lexer.Position{Line: 12, Column: 5},
+			Severity: analyzer.SeverityError,
+			Message:  "unexpected token",
+		},
+		{
+			Filename: "default.vcl",
+			Position: lexer.Position{Line: 3},
+			Severity: analyzer.SeverityWarning,
+			Message:  "message spanning\nseveral lines",
+		},
+		{
+			Severity: analyzer.SeverityError,
+			Code:     "vmod-not-imported",
+			Message:  "module not imported",
+		},
+	}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: FormatVim,
+			expected: "default.vcl:12:5: error: unexpected token\n" +
+				"default.vcl:3:1: warning: message spanning several lines\n" +
+				"<stdin>: error: module not imported [vmod-not-imported]\n",
+		},
+		{
+			format: FormatEmacs,
+			expected: "default.vcl:12.5: error: unexpected token\n" +
+				"default.vcl:3.1: warning: message spanning several lines\n" +
+				"<stdin>: error: module not imported [vmod-not-imported]\n",
+		},
+		{
+			format: FormatJSON,
+			expected: `[
+  {
+    "file": "default.vcl",
+    "line": 12,
+    "column": 5,
+    "severity": "error",
+    "message": "unexpected token"
+  },
+  {
+    "file": "default.vcl",
+    "line": 3,
+    "severity": "warning",
+    "message": "message spanning\nseveral lines"
+  },
+  {
+    "file": "<stdin>",
+    "severity": "error",
+    "code": "vmod-not-imported",
+    "message": "module not imported"
+  }
+]
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, test.format, diags); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if buf.String() != test.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("VIM"); err != nil || f != FormatVim {
+		t.Errorf("ParseFormat(VIM) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	diags := []analyzer.Diagnostic{
+		{
+			Filename:     "conf.d/site.vcl",
+			Position:     lexer.Position{Line: 4, Column: 5},
+			EndPosition:  lexer.Position{Line: 4, Column: 24, RuneColumn: 20},
+			Severity:     analyzer.SeverityWarning,
+			Code:         "unreferenced",
+			Message:      "backend spare is declared but never used",
+			IncludeChain: []string{"main.vcl"},
+		},
+		{
+			Filename: "main.vcl",
+			Severity: analyzer.SeverityError,
+			Message:  "unexpected end of file",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, diags); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected log: %s", buf.String())
+	}
+	run := log.Runs[0]
+	if run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("columnKind = %q, want unicodeCodePoints", run.ColumnKind)
+	}
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "unreferenced" || run.Tool.Driver.Rules[1].ID != "vcl" {
+		t.Errorf("Unexpected rules %v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+
+	first := run.Results[0]
+	loc := first.Locations[0].PhysicalLocation
+	if first.RuleID != "unreferenced" || first.Level != "warning" || loc.ArtifactLocation.URI != "conf.d/site.vcl" {
+		t.Errorf("Unexpected result %+v", first)
+	}
+	if loc.Region == nil || *loc.Region != (sarifRegion{StartLine: 4, StartColumn: 5, EndLine: 4, EndColumn: 20}) {
+		t.Errorf("Unexpected region %+v", loc.Region)
+	}
+	if len(first.RelatedLocations) != 1 || first.RelatedLocations[0].PhysicalLocation.ArtifactLocation.URI != "main.vcl" {
+		t.Errorf("Expected the including file as related location, got %+v", first.RelatedLocations)
+	}
+
+	second := run.Results[1]
+	if second.RuleID != "vcl" || second.Level != "error" || second.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("Unexpected result without position %+v", second)
+	}
+}
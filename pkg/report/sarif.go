@@ -0,0 +1,166 @@
+package report
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/lexer"
+)
+
+// The subset of SARIF 2.1.0 needed to report diagnostics, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	// defaultRuleID is the rule of diagnostics without a code
+	defaultRuleID = "vcl"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// writeSARIF writes the diagnostics as a SARIF log with a single run. Every
+// diagnostic code becomes a rule, and the files that included the file of a
+// finding are listed as related locations.
+func writeSARIF(w io.Writer, diags []analyzer.Diagnostic) error {
+	results := make([]sarifResult, len(diags))
+	ruleIDs := make(map[string]bool)
+	for i, d := range diags {
+		ruleID := d.Code
+		if ruleID == "" {
+			ruleID = defaultRuleID
+		}
+		ruleIDs[ruleID] = true
+
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(filename(d))},
+		}}
+		if d.Position.Line > 0 {
+			region := &sarifRegion{StartLine: d.Position.Line, StartColumn: runeColumn(d.Position)}
+			// SARIF end columns are exclusive, like the end positions of
+			// the AST
+			if d.EndPosition.Line >= d.Position.Line {
+				region.EndLine = d.EndPosition.Line
+				region.EndColumn = runeColumn(d.EndPosition)
+			}
+			location.PhysicalLocation.Region = region
+		}
+
+		result := sarifResult{
+			RuleID:    ruleID,
+			Level:     sarifLevel(d.Severity),
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{location},
+		}
+		for _, file := range d.IncludeChain {
+			result.RelatedLocations = append(result.RelatedLocations, sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(file)},
+				},
+				Message: &sarifMessage{Text: "included from " + file},
+			})
+		}
+		results[i] = result
+	}
+
+	rules := make([]sarifRule, 0, len(ruleIDs))
+	for id := range ruleIDs {
+		rules = append(rules, sarifRule{ID: id})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	return encodeJSON(w, sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "vclparse",
+				InformationURI: "https://github.com/perbu/vclparser",
+				Rules:          rules,
+			}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	})
+}
+
+func sarifLevel(severity analyzer.Severity) string {
+	switch severity {
+	case analyzer.SeverityError:
+		return "error"
+	case analyzer.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// runeColumn returns the column of a position in characters, as the run's
+// columnKind declares, falling back to the byte column for positions that
+// do not carry one
+func runeColumn(pos lexer.Position) int {
+	if pos.RuneColumn > 0 {
+		return pos.RuneColumn
+	}
+	return pos.Column
+}
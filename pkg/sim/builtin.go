@@ -0,0 +1,130 @@
+package sim
+
+import (
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+// builtinVCL is the part of varnishd's builtin.vcl that decides return
+// actions and the hash, without the bodies vcl_synth and
+// vcl_backend_error generate
+const builtinVCL = `vcl 4.1;
+
+sub vcl_recv {
+	if (req.method == "PRI") {
+		return (synth(405));
+	}
+	if (!req.http.host && req.esi_level == 0 && req.proto ~ "^(?i)HTTP/1.1") {
+		return (synth(400));
+	}
+	if (req.method != "GET" &&
+	    req.method != "HEAD" &&
+	    req.method != "PUT" &&
+	    req.method != "POST" &&
+	    req.method != "TRACE" &&
+	    req.method != "OPTIONS" &&
+	    req.method != "DELETE" &&
+	    req.method != "PATCH") {
+		return (pipe);
+	}
+	if (req.method != "GET" && req.method != "HEAD") {
+		return (pass);
+	}
+	if (req.http.Authorization || req.http.Cookie) {
+		return (pass);
+	}
+	return (hash);
+}
+
+sub vcl_pipe {
+	return (pipe);
+}
+
+sub vcl_pass {
+	return (fetch);
+}
+
+sub vcl_hash {
+	hash_data(req.url);
+	if (req.http.host) {
+		hash_data(req.http.host);
+	} else {
+		hash_data(server.ip);
+	}
+	return (lookup);
+}
+
+sub vcl_purge {
+	return (synth(200, "Purged"));
+}
+
+sub vcl_hit {
+	return (deliver);
+}
+
+sub vcl_miss {
+	return (fetch);
+}
+
+sub vcl_deliver {
+	return (deliver);
+}
+
+sub vcl_synth {
+	set resp.http.Content-Type = "text/html; charset=utf-8";
+	set resp.http.Retry-After = "5";
+	return (deliver);
+}
+
+sub vcl_backend_fetch {
+	if (bereq.method == "GET") {
+		unset bereq.body;
+	}
+	return (fetch);
+}
+
+sub vcl_backend_response {
+	if (bereq.uncacheable) {
+		return (deliver);
+	}
+	if (beresp.ttl <= 0s ||
+	    beresp.http.Set-Cookie ||
+	    beresp.http.Surrogate-control ~ "(?i)no-store" ||
+	    (!beresp.http.Surrogate-Control &&
+	      beresp.http.Cache-Control ~ "(?i:no-cache|no-store|private)") ||
+	    beresp.http.Vary == "*") {
+		set beresp.ttl = 120s;
+		set beresp.uncacheable = true;
+	}
+	return (deliver);
+}
+
+sub vcl_backend_error {
+	set beresp.http.Content-Type = "text/html; charset=utf-8";
+	set beresp.http.Retry-After = "5";
+	return (deliver);
+}
+
+sub vcl_init {
+	return (ok);
+}
+
+sub vcl_fini {
+	return (ok);
+}
+`
+
+// builtinSubs parses the built-in VCL
+func builtinSubs() map[string][]*ast.SubDecl {
+	program, err := parser.Parse(builtinVCL, "builtin.vcl")
+	if err != nil {
+		panic("sim: built-in VCL does not parse: " + err.Error())
+	}
+	subs := make(map[string][]*ast.SubDecl)
+	for _, decl := range program.Declarations {
+		if sub, ok := decl.(*ast.SubDecl); ok {
+			subs[sub.Name] = []*ast.SubDecl{sub}
+		}
+	}
+	return subs
+}
@@ -0,0 +1,265 @@
+package sim
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Coverage records which statements and branches of a program's
+// subroutines the simulator executed, over any number of runs. The
+// built-in VCL is not covered.
+type Coverage struct {
+	subs       []*ast.SubDecl
+	calls      map[*ast.SubDecl]int
+	statements map[ast.Statement]int
+	// branches counts how often the then and else branch of each if
+	// statement were taken; the else branch of an if without else is
+	// taken when the condition is false
+	branches map[*ast.IfStatement]*[2]int
+}
+
+// NewCoverage creates an empty coverage record for program
+func NewCoverage(program *ast.Program) *Coverage {
+	c := &Coverage{
+		calls:      make(map[*ast.SubDecl]int),
+		statements: make(map[ast.Statement]int),
+		branches:   make(map[*ast.IfStatement]*[2]int),
+	}
+	for _, decl := range program.Declarations {
+		sub, ok := decl.(*ast.SubDecl)
+		if !ok || sub.Body == nil {
+			continue
+		}
+		c.subs = append(c.subs, sub)
+		c.calls[sub] = 0
+		for _, stmt := range subStatements(sub.Body) {
+			c.statements[stmt] = 0
+			if ifStmt, ok := stmt.(*ast.IfStatement); ok {
+				c.branches[ifStmt] = &[2]int{}
+			}
+		}
+	}
+	return c
+}
+
+// SetCoverage makes the simulator record its runs in c, or stop recording
+// if c is nil
+func (s *Simulator) SetCoverage(c *Coverage) {
+	s.coverage = c
+}
+
+// subStatements returns the statements of a block and all blocks nested in
+// it, without the blocks themselves
+func subStatements(stmt ast.Statement) []ast.Statement {
+	switch s := stmt.(type) {
+	case nil:
+		return nil
+	case *ast.BlockStatement:
+		var stmts []ast.Statement
+		for _, inner := range s.Statements {
+			stmts = append(stmts, subStatements(inner)...)
+		}
+		return stmts
+	case *ast.IfStatement:
+		return append(append([]ast.Statement{s}, subStatements(s.Then)...), subStatements(s.Else)...)
+	}
+	return []ast.Statement{stmt}
+}
+
+func (c *Coverage) enterSub(sub *ast.SubDecl) {
+	if _, ok := c.calls[sub]; ok {
+		c.calls[sub]++
+	}
+}
+
+func (c *Coverage) executed(stmt ast.Statement) {
+	if _, ok := c.statements[stmt]; ok {
+		c.statements[stmt]++
+	}
+}
+
+func (c *Coverage) branch(stmt *ast.IfStatement, taken bool) {
+	if counts := c.branches[stmt]; counts != nil {
+		if taken {
+			counts[0]++
+		} else {
+			counts[1]++
+		}
+	}
+}
+
+// SubCoverage summarizes the coverage of one subroutine definition
+type SubCoverage struct {
+	Name string
+	Line int
+	// Calls counts the runs of the subroutine
+	Calls             int
+	Statements        int
+	StatementsCovered int
+	Branches          int
+	BranchesCovered   int
+	// Uncovered lists the lines of statements that never ran
+	Uncovered []int
+}
+
+// Subs returns the coverage of each subroutine in source order
+func (c *Coverage) Subs() []SubCoverage {
+	var result []SubCoverage
+	for _, sub := range c.subs {
+		sc := SubCoverage{Name: sub.Name, Line: sub.Start().Line, Calls: c.calls[sub]}
+		uncovered := make(map[int]int)
+		for _, stmt := range subStatements(sub.Body) {
+			sc.Statements++
+			if c.statements[stmt] > 0 {
+				sc.StatementsCovered++
+			} else {
+				uncovered[stmt.Start().Line]++
+			}
+			if counts := c.branches[stmtIf(stmt)]; counts != nil {
+				sc.Branches += 2
+				for _, n := range counts {
+					if n > 0 {
+						sc.BranchesCovered++
+					}
+				}
+			}
+		}
+		sc.Uncovered = sortedLines(uncovered)
+		result = append(result, sc)
+	}
+	return result
+}
+
+func stmtIf(stmt ast.Statement) *ast.IfStatement {
+	ifStmt, _ := stmt.(*ast.IfStatement)
+	return ifStmt
+}
+
+// Lines returns how often each line with a statement ran. A line holding
+// several statements gets the highest count among them.
+func (c *Coverage) Lines() map[int]int {
+	lines := make(map[int]int)
+	for stmt, n := range c.statements {
+		line := stmt.Start().Line
+		if current, ok := lines[line]; !ok || n > current {
+			lines[line] = n
+		}
+	}
+	return lines
+}
+
+// WriteText writes a summary line per subroutine with the lines that never
+// ran, followed by the total
+func (c *Coverage) WriteText(w io.Writer) error {
+	var statements, statementsCovered, branches, branchesCovered int
+	for _, sc := range c.Subs() {
+		statements += sc.Statements
+		statementsCovered += sc.StatementsCovered
+		branches += sc.Branches
+		branchesCovered += sc.BranchesCovered
+
+		line := fmt.Sprintf("%-24s %s statements  %s branches", sc.Name,
+			ratio(sc.StatementsCovered, sc.Statements), ratio(sc.BranchesCovered, sc.Branches))
+		if len(sc.Uncovered) > 0 {
+			line += "  not run: lines " + formatRanges(sc.Uncovered)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%-24s %s statements  %s branches\n", "total",
+		ratio(statementsCovered, statements), ratio(branchesCovered, branches))
+	return err
+}
+
+// WriteLCOV writes the coverage in the lcov tracefile format read by
+// genhtml and most CI coverage services, attributing it to filename
+func (c *Coverage) WriteLCOV(w io.Writer, filename string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TN:\nSF:%s\n", filename)
+
+	subs := c.Subs()
+	hit := 0
+	for _, sc := range subs {
+		fmt.Fprintf(&b, "FN:%d,%s\n", sc.Line, sc.Name)
+	}
+	for _, sc := range subs {
+		fmt.Fprintf(&b, "FNDA:%d,%s\n", sc.Calls, sc.Name)
+		if sc.Calls > 0 {
+			hit++
+		}
+	}
+	fmt.Fprintf(&b, "FNF:%d\nFNH:%d\n", len(subs), hit)
+
+	var ifs []*ast.IfStatement
+	for stmt := range c.branches {
+		ifs = append(ifs, stmt)
+	}
+	sort.Slice(ifs, func(i, j int) bool { return ifs[i].Start().Offset < ifs[j].Start().Offset })
+	branchesHit := 0
+	for i, stmt := range ifs {
+		for branch, n := range c.branches[stmt] {
+			taken := "-"
+			if c.statements[stmt] > 0 {
+				taken = fmt.Sprint(n)
+			}
+			if n > 0 {
+				branchesHit++
+			}
+			fmt.Fprintf(&b, "BRDA:%d,%d,%d,%s\n", stmt.Start().Line, i, branch, taken)
+		}
+	}
+	fmt.Fprintf(&b, "BRF:%d\nBRH:%d\n", 2*len(ifs), branchesHit)
+
+	lines := c.Lines()
+	linesHit := 0
+	for _, line := range sortedLines(lines) {
+		fmt.Fprintf(&b, "DA:%d,%d\n", line, lines[line])
+		if lines[line] > 0 {
+			linesHit++
+		}
+	}
+	fmt.Fprintf(&b, "LF:%d\nLH:%d\nend_of_record\n", len(lines), linesHit)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func ratio(covered, total int) string {
+	if total == 0 {
+		return fmt.Sprintf("%d/%d (  -  )", covered, total)
+	}
+	return fmt.Sprintf("%d/%d (%5.1f%%)", covered, total, 100*float64(covered)/float64(total))
+}
+
+// sortedLines returns the lines of a line count map in order
+func sortedLines(lines map[int]int) []int {
+	sorted := make([]int, 0, len(lines))
+	for line := range lines {
+		sorted = append(sorted, line)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+// formatRanges writes sorted line numbers as 3, 7-9, 12
+func formatRanges(lines []int) string {
+	var parts []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, fmt.Sprint(lines[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,92 @@
+package sim
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+func TestCoverage(t *testing.T) {
+	vclCode := `vcl 4.1;
+
+sub vcl_recv {
+	if (req.url ~ "^/static/") {
+		unset req.http.Cookie;
+		return (hash);
+	}
+	if (req.method == "PURGE") {
+		return (purge);
+	}
+}
+
+sub vcl_deliver {
+	set resp.http.X-Cache = "HIT";
+}
+`
+	program, err := parser.Parse(vclCode, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	simulator := New(program)
+	coverage := NewCoverage(program)
+	simulator.SetCoverage(coverage)
+
+	for _, url := range []string{"/static/a.css", "/index.html"} {
+		state := NewState()
+		state.SetString("req.url", url)
+		state.SetString("req.method", "GET")
+		state.SetString("req.http.host", "example.com")
+		if _, err := simulator.Run("vcl_recv", state); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+
+	subs := coverage.Subs()
+	if len(subs) != 2 {
+		t.Fatalf("Expected 2 subroutines, got %+v", subs)
+	}
+	recv := subs[0]
+	if recv.Calls != 2 || recv.Statements != 5 || recv.StatementsCovered != 4 ||
+		recv.Branches != 4 || recv.BranchesCovered != 3 {
+		t.Errorf("vcl_recv coverage = %+v", recv)
+	}
+	if len(recv.Uncovered) != 1 || recv.Uncovered[0] != 9 {
+		t.Errorf("Uncovered = %v, want [9]", recv.Uncovered)
+	}
+	if deliver := subs[1]; deliver.Calls != 0 || deliver.StatementsCovered != 0 {
+		t.Errorf("vcl_deliver coverage = %+v", deliver)
+	}
+
+	var text bytes.Buffer
+	if err := coverage.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{
+		"vcl_recv                 4/5 ( 80.0%) statements  3/4 ( 75.0%) branches  not run: lines 9",
+		"vcl_deliver              0/1 (  0.0%) statements  0/0 (  -  ) branches  not run: lines 14",
+		"total                    4/6 ( 66.7%) statements",
+	} {
+		if !strings.Contains(text.String(), fragment) {
+			t.Errorf("text report does not contain %q:\n%s", fragment, text.String())
+		}
+	}
+
+	var lcov bytes.Buffer
+	if err := coverage.WriteLCOV(&lcov, "test.vcl"); err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{
+		"SF:test.vcl\n",
+		"FN:3,vcl_recv\n",
+		"FNDA:2,vcl_recv\nFNDA:0,vcl_deliver\nFNF:2\nFNH:1\n",
+		"BRDA:4,0,0,1\nBRDA:4,0,1,1\nBRDA:8,1,0,0\nBRDA:8,1,1,1\nBRF:4\nBRH:3\n",
+		"DA:9,0\n",
+		"LF:6\nLH:4\nend_of_record\n",
+	} {
+		if !strings.Contains(lcov.String(), fragment) {
+			t.Errorf("lcov report does not contain %q:\n%s", fragment, lcov.String())
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package sim
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// eval computes the value of an expression
+func (m *machine) eval(expr ast.Expression) (Value, error) {
+	switch e := expr.(type) {
+	case *ast.StringLiteral:
+		return e.Value, nil
+	case *ast.IntegerLiteral:
+		return e.Value, nil
+	case *ast.FloatLiteral:
+		return e.Value, nil
+	case *ast.BooleanLiteral:
+		return e.Value, nil
+	case *ast.TimeExpression:
+		return durationValue(e, e.Value)
+	case *ast.DurationLiteral:
+		return durationValue(e, e.Value)
+	case *ast.IPExpression:
+		return e.Value, nil
+	case *ast.ParenthesizedExpression:
+		return m.eval(e.Expression)
+	case *ast.Identifier:
+		switch e.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if v, ok := m.state.Get(e.Name); ok {
+			return v, nil
+		}
+		// Backends, ACLs and other symbols evaluate to their name
+		return e.Name, nil
+	case *ast.MemberExpression:
+		name := ast.VariableName(e)
+		if name == "" {
+			return nil, errorAt(e, "unsupported expression")
+		}
+		v, _ := m.state.Get(name)
+		return v, nil
+	case *ast.UnaryExpression:
+		operand, err := m.eval(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Operator {
+		case "!":
+			return !truthy(operand), nil
+		case "-":
+			return arithmetic("-", int64(0), operand)
+		}
+		return nil, errorAt(e, "unsupported operator %s", e.Operator)
+	case *ast.BinaryExpression:
+		return m.binary(e)
+	case *ast.RegexMatchExpression:
+		return m.match(e)
+	case *ast.CallExpression:
+		return m.call(e)
+	}
+	return nil, errorAt(expr, "%s is not simulated", expr.String())
+}
+
+func durationValue(node ast.Node, s string) (Value, error) {
+	d, err := parseDuration(s)
+	if err != nil {
+		return nil, errorAt(node, "%v", err)
+	}
+	return d, nil
+}
+
+func (m *machine) binary(e *ast.BinaryExpression) (Value, error) {
+	left, err := m.eval(e.Left)
+	if err != nil {
+		return nil, err
+	}
+	// && and || short-circuit like in VCL
+	switch e.Operator {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := m.eval(e.Right)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := m.eval(e.Right)
+		return truthy(right), err
+	}
+
+	right, err := m.eval(e.Right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Operator {
+	case "==":
+		return compare(left, right) == 0, nil
+	case "!=":
+		return compare(left, right) != 0, nil
+	case "<":
+		return compare(left, right) < 0, nil
+	case "<=":
+		return compare(left, right) <= 0, nil
+	case ">":
+		return compare(left, right) > 0, nil
+	case ">=":
+		return compare(left, right) >= 0, nil
+	}
+	v, err := arithmetic(e.Operator, left, right)
+	if err != nil {
+		return nil, errorAt(e, "%v", err)
+	}
+	return v, nil
+}
+
+// match evaluates ~ and !~ against a regular expression or an ACL
+func (m *machine) match(e *ast.RegexMatchExpression) (Value, error) {
+	subject, err := m.eval(e.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched bool
+	if ident, ok := e.Right.(*ast.Identifier); ok && m.sim.acls[ident.Name] != nil {
+		matched = matchACL(m.sim.acls[ident.Name], subject)
+	} else {
+		pattern, err := m.eval(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		re, err := compileRegex(toString(pattern))
+		if err != nil {
+			return nil, errorAt(e, "%v", err)
+		}
+		matched = subject != nil && re.MatchString(toString(subject))
+	}
+	return matched == (e.Operator == "~"), nil
+}
+
+// compileRegex compiles a VCL regular expression. Patterns using PCRE
+// features Go lacks, such as backreferences, fail to compile.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("regex %q is not supported by the simulator: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// matchACL matches an address against an ACL. As in varnishd, the most
+// specific matching entry decides.
+func matchACL(acl *ast.ACLDecl, subject Value) bool {
+	ip := net.ParseIP(toString(subject))
+	if ip == nil {
+		return false
+	}
+	best, matched := -1, false
+	for _, entry := range acl.Entries {
+		network := net.ParseIP(entry.Host)
+		if network == nil {
+			continue
+		}
+		bits := 8 * net.IPv6len
+		if network.To4() != nil {
+			bits = 8 * net.IPv4len
+			network = network.To4()
+		}
+		prefix := entry.PrefixLen
+		if prefix < 0 {
+			prefix = bits
+		}
+		cidr := &net.IPNet{IP: network, Mask: net.CIDRMask(prefix, bits)}
+		if cidr.Contains(ip) && prefix > best {
+			best, matched = prefix, !entry.Negated
+		}
+	}
+	return matched
+}
+
+// call evaluates the functions the simulator knows
+func (m *machine) call(e *ast.CallExpression) (Value, error) {
+	name := ast.VariableName(e.Function)
+	args := make([]Value, len(e.Arguments))
+	for i, arg := range e.Arguments {
+		v, err := m.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	arg := func(i int) string {
+		if i < len(args) {
+			return toString(args[i])
+		}
+		return ""
+	}
+
+	switch name {
+	case "hash_data":
+		m.result.Hash = append(m.result.Hash, arg(0))
+		return nil, nil
+	case "regsub", "regsuball":
+		if len(args) != 3 {
+			return nil, errorAt(e, "%s takes 3 arguments", name)
+		}
+		re, err := compileRegex(arg(1))
+		if err != nil {
+			return nil, errorAt(e, "%v", err)
+		}
+		return regsub(re, arg(0), arg(2), name == "regsuball"), nil
+	case "std.tolower":
+		return strings.ToLower(arg(0)), nil
+	case "std.toupper":
+		return strings.ToUpper(arg(0)), nil
+	case "std.strlen":
+		return int64(len(arg(0))), nil
+	case "std.integer":
+		if n, err := strconv.ParseInt(arg(0), 10, 64); err == nil {
+			return n, nil
+		}
+		if len(args) > 1 {
+			return args[1], nil
+		}
+		return nil, errorAt(e, "std.integer: %q is not an integer", arg(0))
+	case "std.duration":
+		if d, err := parseDuration(arg(0)); err == nil {
+			return d, nil
+		}
+		if len(args) > 1 {
+			return args[1], nil
+		}
+		return nil, errorAt(e, "std.duration: %q is not a duration", arg(0))
+	case "std.log", "std.syslog", "std.collect":
+		return nil, nil
+	}
+	return nil, errorAt(e, "function %s is not simulated", name)
+}
+
+// regsub replaces the first or every match of re, translating the \1
+// references of VCL substitutions
+func regsub(re *regexp.Regexp, subject, substitution string, all bool) string {
+	var template strings.Builder
+	for i := 0; i < len(substitution); i++ {
+		c := substitution[i]
+		switch {
+		case c == '$':
+			template.WriteString("$$")
+		case c == '\\' && i+1 < len(substitution) && substitution[i+1] >= '0' && substitution[i+1] <= '9':
+			i++
+			template.WriteString("${" + string(substitution[i]) + "}")
+		default:
+			template.WriteByte(c)
+		}
+	}
+
+	if all {
+		return re.ReplaceAllString(subject, template.String())
+	}
+	loc := re.FindStringSubmatchIndex(subject)
+	if loc == nil {
+		return subject
+	}
+	replaced := re.ExpandString(nil, template.String(), subject, loc)
+	return subject[:loc[0]] + string(replaced) + subject[loc[1]:]
+}
+
+// truthy converts a value to BOOL the way VCL does: strings and headers
+// are true when set
+func truthy(v Value) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case int64:
+		return x != 0
+	case float64:
+		return x != 0
+	case time.Duration:
+		return x > 0
+	}
+	return true
+}
+
+// toString converts a value to STRING
+func toString(v Value) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case time.Duration:
+		return strconv.FormatFloat(x.Seconds(), 'f', 3, 64)
+	case float64:
+		return strconv.FormatFloat(x, 'f', 3, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// Format returns the string form of a value, as VCL would print it
+func Format(v Value) string {
+	return toString(v)
+}
+
+// parseNumber parses an INT or REAL
+func parseNumber(s string) (Value, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// numeric returns a value as a number, converting strings that hold one.
+// Unset values count as zero.
+func numeric(v Value) (Value, bool) {
+	switch x := v.(type) {
+	case nil:
+		return int64(0), true
+	case int64, float64, time.Duration:
+		return v, true
+	case bool:
+		if x {
+			return int64(1), true
+		}
+		return int64(0), true
+	case string:
+		if d, err := parseDuration(x); err == nil {
+			return d, true
+		}
+		return parseNumber(x)
+	}
+	return nil, false
+}
+
+func toFloat(v Value) float64 {
+	switch x := v.(type) {
+	case int64:
+		return float64(x)
+	case float64:
+		return x
+	case time.Duration:
+		return x.Seconds()
+	}
+	return 0
+}
+
+// compare orders two values, numerically when both are numbers or one is
+// a number and the other a string holding one, and as strings otherwise
+func compare(a, b Value) int {
+	_, aString := a.(string)
+	_, bString := b.(string)
+	if !aString || !bString {
+		x, xOK := numeric(a)
+		y, yOK := numeric(b)
+		if xOK && yOK {
+			switch fx, fy := toFloat(x), toFloat(y); {
+			case fx < fy:
+				return -1
+			case fx > fy:
+				return 1
+			}
+			return 0
+		}
+	}
+	if a == nil && b != nil || a != nil && b == nil {
+		// An unset header equals nothing, not even ""
+		return 1
+	}
+	return strings.Compare(toString(a), toString(b))
+}
+
+// arithmetic applies +, -, * or / to numbers and durations, and + to
+// strings, which concatenates them
+func arithmetic(operator string, a, b Value) (Value, error) {
+	_, aString := a.(string)
+	_, bString := b.(string)
+	if operator == "+" && (aString || bString) {
+		return toString(a) + toString(b), nil
+	}
+
+	x, xOK := numeric(a)
+	y, yOK := numeric(b)
+	if !xOK || !yOK {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", operator, toString(a), toString(b))
+	}
+	ix, xInt := x.(int64)
+	iy, yInt := y.(int64)
+	if xInt && yInt {
+		switch operator {
+		case "+":
+			return ix + iy, nil
+		case "-":
+			return ix - iy, nil
+		case "*":
+			return ix * iy, nil
+		case "/":
+			if iy == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return ix / iy, nil
+		}
+	}
+
+	fx, fy := toFloat(x), toFloat(y)
+	var result float64
+	switch operator {
+	case "+":
+		result = fx + fy
+	case "-":
+		result = fx - fy
+	case "*":
+		result = fx * fy
+	case "/":
+		if fy == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		result = fx / fy
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", operator)
+	}
+
+	_, xDuration := x.(time.Duration)
+	_, yDuration := y.(time.Duration)
+	if (xDuration || yDuration) && !(xDuration && yDuration && operator == "/") {
+		return time.Duration(result * float64(time.Second)), nil
+	}
+	return result, nil
+}
+
+// Equal reports whether v equals the value written as s, comparing
+// numbers and durations by value, so 120s equals 2m
+func Equal(v Value, s string) bool {
+	return compare(v, s) == 0
+}
@@ -0,0 +1,369 @@
+// Package sim runs VCL subroutines against a simulated request, so the
+// decisions a VCL program makes can be checked without a running Varnish.
+//
+// The simulator interprets the statements of a subroutine: set, unset, if,
+// call, return, restart, synthetic() and hash_data(), string, number,
+// duration and regex expressions, ACL matches and a few functions such as
+// regsub() and std.tolower(). VMOD objects and most VMOD functions are not
+// simulated; using them is an error. A built-in subroutine that does not
+// return continues with the built-in VCL, as in varnishd.
+package sim
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// maxCallDepth bounds nested subroutine calls. VCC rejects recursion, but
+// the simulator also runs programs that were never compiled.
+const maxCallDepth = 64
+
+// Value is the value of a VCL variable or expression: a string, int64,
+// float64, bool or time.Duration. Unset variables have the value nil.
+type Value interface{}
+
+// State holds the variables of a simulated request. Header names are case
+// insensitive, like in VCL.
+type State struct {
+	vars map[string]Value
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{vars: make(map[string]Value)}
+}
+
+// Get returns the value of a variable and whether it is set
+func (s *State) Get(name string) (Value, bool) {
+	v, ok := s.vars[canonicalName(name)]
+	return v, ok
+}
+
+// Set sets a variable. Headers are converted to strings.
+func (s *State) Set(name string, value Value) {
+	name = canonicalName(name)
+	if value != nil && strings.Contains(name, ".http.") {
+		value = toString(value)
+	}
+	s.vars[name] = value
+}
+
+// SetString sets a variable from its string form as it would appear in a
+// test case. Headers always hold strings; other variables holding
+// something that parses as a number, duration or boolean get that type.
+func (s *State) SetString(name, value string) {
+	if strings.Contains(canonicalName(name), ".http.") {
+		s.Set(name, value)
+		return
+	}
+	s.Set(name, parseValue(value))
+}
+
+// Unset removes a variable
+func (s *State) Unset(name string) {
+	delete(s.vars, canonicalName(name))
+}
+
+// Result describes how a subroutine ended
+type Result struct {
+	// Action is the return action, such as "hash" or "synth", or "" when
+	// the subroutine ended without one
+	Action string
+	// Args are the arguments of the action, as in synth(404, "Not Found")
+	Args []Value
+	// Hash holds the hash_data() inputs in the order they were added
+	Hash []string
+	// Body is the text passed to synthetic()
+	Body string
+}
+
+// Simulator runs the subroutines of a program
+type Simulator struct {
+	subs     map[string][]*ast.SubDecl
+	builtin  map[string][]*ast.SubDecl
+	acls     map[string]*ast.ACLDecl
+	coverage *Coverage
+}
+
+// New creates a simulator for program. Built-in subroutines continue with
+// the built-in VCL when they do not return.
+func New(program *ast.Program) *Simulator {
+	s := &Simulator{
+		subs:    make(map[string][]*ast.SubDecl),
+		builtin: builtinSubs(),
+		acls:    make(map[string]*ast.ACLDecl),
+	}
+	for _, decl := range program.Declarations {
+		switch d := decl.(type) {
+		case *ast.SubDecl:
+			if d.Body != nil {
+				s.subs[d.Name] = append(s.subs[d.Name], d)
+			}
+		case *ast.ACLDecl:
+			s.acls[d.Name] = d
+		}
+	}
+	return s
+}
+
+// SetBuiltin sets whether built-in subroutines continue with the built-in
+// VCL when they do not return. It is on by default.
+func (s *Simulator) SetBuiltin(enabled bool) {
+	if enabled {
+		s.builtin = builtinSubs()
+	} else {
+		s.builtin = nil
+	}
+}
+
+// Run runs the named subroutine with state, which it modifies
+func (s *Simulator) Run(sub string, state *State) (*Result, error) {
+	if len(s.subs[sub]) == 0 && len(s.builtin[sub]) == 0 {
+		return nil, fmt.Errorf("subroutine %s is not defined", sub)
+	}
+	m := &machine{sim: s, state: state, result: &Result{}}
+	if _, err := m.runSub(sub, 0); err != nil {
+		return nil, err
+	}
+	return m.result, nil
+}
+
+// control says how execution continues after a statement
+type control int
+
+const (
+	next control = iota
+	leaveSub
+	leaveState
+)
+
+// machine is one run of a subroutine
+type machine struct {
+	sim    *Simulator
+	state  *State
+	result *Result
+}
+
+// runSub runs the definitions of a subroutine in order, followed by the
+// built-in VCL for it
+func (m *machine) runSub(name string, depth int) (control, error) {
+	if depth > maxCallDepth {
+		return leaveState, fmt.Errorf("calls nested deeper than %d subroutines, probably recursion", maxCallDepth)
+	}
+	bodies := append(append([]*ast.SubDecl{}, m.sim.subs[name]...), m.sim.builtin[name]...)
+	for _, sub := range bodies {
+		if m.sim.coverage != nil {
+			m.sim.coverage.enterSub(sub)
+		}
+		ctl, err := m.block(sub.Body.Statements, depth)
+		if err != nil || ctl != next {
+			return ctl, err
+		}
+	}
+	return next, nil
+}
+
+func (m *machine) block(stmts []ast.Statement, depth int) (control, error) {
+	for _, stmt := range stmts {
+		ctl, err := m.statement(stmt, depth)
+		if err != nil || ctl != next {
+			return ctl, err
+		}
+	}
+	return next, nil
+}
+
+func (m *machine) statement(stmt ast.Statement, depth int) (control, error) {
+	if m.sim.coverage != nil {
+		m.sim.coverage.executed(stmt)
+	}
+	switch s := stmt.(type) {
+	case *ast.BlockStatement:
+		return m.block(s.Statements, depth)
+	case *ast.IfStatement:
+		cond, err := m.eval(s.Condition)
+		if err != nil {
+			return leaveState, err
+		}
+		if m.sim.coverage != nil {
+			m.sim.coverage.branch(s, truthy(cond))
+		}
+		if truthy(cond) {
+			return m.statement(s.Then, depth)
+		}
+		if s.Else != nil {
+			return m.statement(s.Else, depth)
+		}
+	case *ast.SetStatement:
+		return next, m.set(s)
+	case *ast.UnsetStatement:
+		name := ast.VariableName(s.Variable)
+		if name == "" {
+			return leaveState, errorAt(s, "cannot unset this expression")
+		}
+		m.state.Unset(name)
+	case *ast.CallStatement:
+		name := ast.VariableName(s.Function)
+		if len(m.sim.subs[name]) == 0 {
+			return leaveState, errorAt(s, "call to undefined subroutine %s", name)
+		}
+		ctl, err := m.runSub(name, depth+1)
+		if ctl == leaveSub {
+			ctl = next
+		}
+		return ctl, err
+	case *ast.ReturnStatement:
+		if s.Action == nil {
+			return leaveSub, nil
+		}
+		return leaveState, m.action(s.Action)
+	case *ast.RestartStatement:
+		m.result.Action = "restart"
+		return leaveState, nil
+	case *ast.ErrorStatement:
+		m.result.Action = "synth"
+		for _, arg := range []ast.Expression{s.Code, s.Response} {
+			if arg == nil {
+				continue
+			}
+			v, err := m.eval(arg)
+			if err != nil {
+				return leaveState, err
+			}
+			m.result.Args = append(m.result.Args, v)
+		}
+		return leaveState, nil
+	case *ast.SyntheticStatement:
+		v, err := m.eval(s.Response)
+		if err != nil {
+			return leaveState, err
+		}
+		m.result.Body += toString(v)
+	case *ast.ExpressionStatement:
+		_, err := m.eval(s.Expression)
+		return next, err
+	case *ast.NewStatement, *ast.CSourceStatement:
+		return leaveState, errorAt(stmt, "%s is not simulated", describeStatement(stmt))
+	}
+	return next, nil
+}
+
+// action records the action of return (action) or return (action(args))
+func (m *machine) action(expr ast.Expression) error {
+	switch e := expr.(type) {
+	case *ast.ParenthesizedExpression:
+		return m.action(e.Expression)
+	case *ast.Identifier:
+		m.result.Action = e.Name
+		return nil
+	case *ast.CallExpression:
+		m.result.Action = ast.VariableName(e.Function)
+		for _, arg := range e.Arguments {
+			v, err := m.eval(arg)
+			if err != nil {
+				return err
+			}
+			m.result.Args = append(m.result.Args, v)
+		}
+		return nil
+	}
+	return errorAt(expr, "unsupported return action")
+}
+
+func (m *machine) set(s *ast.SetStatement) error {
+	name := ast.VariableName(s.Variable)
+	if name == "" {
+		return errorAt(s, "cannot assign to this expression")
+	}
+	value, err := m.eval(s.Value)
+	if err != nil {
+		return err
+	}
+	if s.Operator != "" && s.Operator != "=" {
+		current, _ := m.state.Get(name)
+		if value, err = arithmetic(strings.TrimSuffix(s.Operator, "="), current, value); err != nil {
+			return errorAt(s, "%v", err)
+		}
+	}
+	m.state.Set(name, value)
+	return nil
+}
+
+// errorAt prefixes an error with the line of node
+func errorAt(node ast.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", node.Start().Line, fmt.Sprintf(format, args...))
+}
+
+func describeStatement(stmt ast.Statement) string {
+	switch stmt.(type) {
+	case *ast.NewStatement:
+		return "new"
+	case *ast.CSourceStatement:
+		return "inline C"
+	}
+	return stmt.String()
+}
+
+// canonicalName lowercases the header part of a variable name
+func canonicalName(name string) string {
+	if i := strings.Index(name, ".http."); i >= 0 {
+		return name[:i+6] + strings.ToLower(name[i+6:])
+	}
+	return name
+}
+
+// parseValue converts the string form of a value to its most specific type
+func parseValue(s string) Value {
+	if d, err := parseDuration(s); err == nil {
+		return d
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, ok := parseNumber(s); ok {
+		return n
+	}
+	return s
+}
+
+// parseDuration parses VCL durations such as 10s, 1.5h or 2w
+func parseDuration(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+		"d":  24 * time.Hour,
+		"w":  7 * 24 * time.Hour,
+		"y":  365 * 24 * time.Hour,
+	}
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.' || i == 0 && rest[i] == '-') {
+			i++
+		}
+		j := i
+		for j < len(rest) && rest[j] >= 'a' && rest[j] <= 'z' {
+			j++
+		}
+		number, ok := parseNumber(rest[:i])
+		unit, known := units[rest[i:j]]
+		if !ok || !known {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(toFloat(number) * float64(unit))
+		rest = rest[j:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return total, nil
+}
@@ -0,0 +1,199 @@
+package sim
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+const testVCL = `vcl 4.1;
+
+acl purgers {
+	"127.0.0.1";
+	"192.0.2.0"/24;
+	!"192.0.2.66";
+}
+
+sub normalize {
+	set req.http.host = std.tolower(regsub(req.http.host, ":[0-9]+$", ""));
+	if (req.url ~ "\?$") {
+		set req.url = regsub(req.url, "\?$", "");
+	}
+}
+
+sub vcl_recv {
+	call normalize;
+	if (req.method == "PURGE") {
+		if (client.ip !~ purgers) {
+			return (synth(405, "Not allowed"));
+		}
+		return (purge);
+	}
+	if (req.url ~ "^/static/") {
+		unset req.http.Cookie;
+	}
+	set req.http.X-Restarts = req.restarts + 1;
+}
+
+sub vcl_hash {
+	hash_data(req.http.X-Lang);
+}
+
+sub vcl_backend_response {
+	if (beresp.status >= 500) {
+		set beresp.ttl = 10s;
+		return (deliver);
+	}
+	set beresp.ttl = beresp.ttl * 2;
+}
+
+sub vcl_synth {
+	synthetic("Error " + resp.status);
+	return (deliver);
+}
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		sub    string
+		vars   map[string]string
+		action string
+		args   []Value
+		hash   []string
+		body   string
+		check  map[string]Value
+		unset  []string
+	}{
+		{
+			name:   "static file without cookie",
+			sub:    "vcl_recv",
+			vars:   map[string]string{"req.method": "GET", "req.url": "/static/app.css?", "req.http.Host": "Example.COM:8080", "req.http.Cookie": "a=1", "req.restarts": "0"},
+			action: "hash",
+			check:  map[string]Value{"req.http.host": "example.com", "req.url": "/static/app.css", "req.http.x-restarts": "1"},
+			unset:  []string{"req.http.cookie"},
+		},
+		{
+			name:   "cookie passes in the built-in VCL",
+			sub:    "vcl_recv",
+			vars:   map[string]string{"req.method": "GET", "req.url": "/account", "req.http.host": "example.com", "req.http.Cookie": "a=1"},
+			action: "pass",
+		},
+		{
+			name:   "purge from an allowed address",
+			sub:    "vcl_recv",
+			vars:   map[string]string{"req.method": "PURGE", "req.url": "/", "client.ip": "192.0.2.10"},
+			action: "purge",
+		},
+		{
+			name:   "purge from an excluded address",
+			sub:    "vcl_recv",
+			vars:   map[string]string{"req.method": "PURGE", "req.url": "/", "client.ip": "192.0.2.66"},
+			action: "synth",
+			args:   []Value{int64(405), "Not allowed"},
+		},
+		{
+			name:   "hash continues with the built-in VCL",
+			sub:    "vcl_hash",
+			vars:   map[string]string{"req.url": "/", "req.http.host": "example.com", "req.http.X-Lang": "de"},
+			action: "lookup",
+			hash:   []string{"de", "/", "example.com"},
+		},
+		{
+			name:   "error response",
+			sub:    "vcl_backend_response",
+			vars:   map[string]string{"beresp.status": "503", "beresp.ttl": "2m"},
+			action: "deliver",
+			check:  map[string]Value{"beresp.ttl": 10 * time.Second},
+		},
+		{
+			name:   "duration arithmetic",
+			sub:    "vcl_backend_response",
+			vars:   map[string]string{"beresp.status": "200", "beresp.ttl": "2m"},
+			action: "deliver",
+			check:  map[string]Value{"beresp.ttl": 4 * time.Minute},
+		},
+		{
+			name:   "synthetic body",
+			sub:    "vcl_synth",
+			vars:   map[string]string{"resp.status": "404"},
+			action: "deliver",
+			body:   "Error 404",
+		},
+	}
+
+	program, err := parser.Parse(testVCL, "test.vcl")
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	simulator := New(program)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := NewState()
+			for name, value := range test.vars {
+				state.SetString(name, value)
+			}
+			result, err := simulator.Run(test.sub, state)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result.Action != test.action {
+				t.Errorf("action = %q, want %q", result.Action, test.action)
+			}
+			if len(test.args) > 0 && Format(result.Args) != Format(test.args) {
+				t.Errorf("args = %v, want %v", result.Args, test.args)
+			}
+			if test.hash != nil && strings.Join(result.Hash, "|") != strings.Join(test.hash, "|") {
+				t.Errorf("hash = %q, want %q", result.Hash, test.hash)
+			}
+			if result.Body != test.body {
+				t.Errorf("body = %q, want %q", result.Body, test.body)
+			}
+			for name, want := range test.check {
+				if got, _ := state.Get(name); got != want {
+					t.Errorf("%s = %v (%T), want %v (%T)", name, got, got, want, want)
+				}
+			}
+			for _, name := range test.unset {
+				if got, ok := state.Get(name); ok {
+					t.Errorf("%s = %v, want it unset", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		vclCode string
+		message string
+	}{
+		{
+			name:    "unknown function",
+			vclCode: `vcl 4.1; import cookie; sub vcl_recv { cookie.parse(req.http.cookie); }`,
+			message: "line 1: function cookie.parse is not simulated",
+		},
+		{
+			name:    "recursion",
+			vclCode: `vcl 4.1; sub loop { call loop; } sub vcl_recv { call loop; }`,
+			message: "probably recursion",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := parser.Parse(test.vclCode, "test.vcl")
+			if err != nil {
+				t.Fatalf("Failed to parse VCL: %v", err)
+			}
+			_, err = New(program).Run("vcl_recv", NewState())
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Errorf("error = %v, want it to contain %q", err, test.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package types
+
+// The implicit conversions and operator rules of VCC, the VCL compiler in
+// varnishd. Types are given by name, as in VCC files and the metadata.
+
+// stringTypes hold text and can start a string concatenation
+var stringTypes = map[string]bool{
+	"STRING":      true,
+	"STRINGS":     true,
+	"STRANDS":     true,
+	"STRING_LIST": true,
+	"HEADER":      true,
+}
+
+// stringConvertible are converted to their text form where a STRING is
+// expected, for instance when assigned to a header or concatenated
+var stringConvertible = map[string]bool{
+	"INT":       true,
+	"REAL":      true,
+	"DURATION":  true,
+	"TIME":      true,
+	"IP":        true,
+	"BACKEND":   true,
+	"BOOL":      true,
+	"BYTES":     true,
+	"STEVEDORE": true,
+	"ENUM":      true,
+}
+
+// boolConvertible are tested for being set, non-empty or non-zero where a
+// BOOL is expected
+var boolConvertible = map[string]bool{
+	"STRING":   true,
+	"HEADER":   true,
+	"INT":      true,
+	"REAL":     true,
+	"DURATION": true,
+	"TIME":     true,
+	"BACKEND":  true,
+}
+
+// additions lists the operand types + and - accept other than strings, and
+// the type of the result
+var additions = []struct {
+	op, left, right, result string
+}{
+	{"+", "BYTES", "BYTES", "BYTES"},
+	{"-", "BYTES", "BYTES", "BYTES"},
+	{"+", "DURATION", "DURATION", "DURATION"},
+	{"-", "DURATION", "DURATION", "DURATION"},
+	{"+", "INT", "INT", "INT"},
+	{"-", "INT", "INT", "INT"},
+	{"+", "INT", "REAL", "REAL"},
+	{"-", "INT", "REAL", "REAL"},
+	{"+", "REAL", "INT", "REAL"},
+	{"-", "REAL", "INT", "REAL"},
+	{"+", "REAL", "REAL", "REAL"},
+	{"-", "REAL", "REAL", "REAL"},
+	{"-", "TIME", "TIME", "DURATION"},
+	{"+", "TIME", "DURATION", "TIME"},
+	{"-", "TIME", "DURATION", "TIME"},
+}
+
+// multipliers maps the types * and / accept on the left to the type they
+// may be scaled by besides INT
+var multipliers = map[string]string{
+	"INT":      "INT",
+	"REAL":     "REAL",
+	"DURATION": "REAL",
+	"BYTES":    "REAL",
+}
+
+// IsStringType reports whether a type holds text, such as STRING or HEADER
+func IsStringType(name string) bool {
+	return stringTypes[name]
+}
+
+// CanCoerce reports whether a value of type from is accepted where a value of
+// type to is expected, either as is or by an implicit conversion
+func CanCoerce(from, to string) bool {
+	switch {
+	case from == to:
+		return true
+	case stringTypes[to]:
+		return stringTypes[from] || stringConvertible[from]
+	case to == "BODY":
+		return stringTypes[from] || stringConvertible[from] || from == "BLOB"
+	case to == "REAL":
+		return from == "INT"
+	case to == "BOOL":
+		return boolConvertible[from]
+	}
+	return false
+}
+
+// BinaryResult returns the type of left op right for the arithmetic
+// operators +, -, *, / and %. A + starting with a string concatenates and
+// converts the right operand to a string, but a string can not be added to
+// anything else: "x" + 5s is a STRING while 5s + "x" is not possible. The
+// second result is false when VCC rejects the combination.
+func BinaryResult(op, left, right string) (string, bool) {
+	switch op {
+	case "+", "-":
+		if op == "+" && stringTypes[left] {
+			if CanCoerce(right, "STRING") {
+				return "STRING", true
+			}
+			return "", false
+		}
+		for _, a := range additions {
+			if a.op == op && a.left == left && a.right == right {
+				return a.result, true
+			}
+		}
+	case "*", "/":
+		if scale, ok := multipliers[left]; ok && (right == "INT" || right == scale) {
+			return left, true
+		}
+	case "%":
+		if left == "INT" && right == "INT" {
+			return "INT", true
+		}
+	}
+	return "", false
+}
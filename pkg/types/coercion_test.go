@@ -0,0 +1,62 @@
+package types
+
+import "testing"
+
+func TestCanCoerce(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected bool
+	}{
+		{"STRING", "STRING", true},
+		{"DURATION", "STRING", true},
+		{"BACKEND", "HEADER", true},
+		{"STRANDS", "STRING", true},
+		{"BLOB", "STRING", false},
+		{"BLOB", "BODY", true},
+		{"VOID", "STRING", false},
+		{"ACL", "STRING", false},
+		{"INT", "REAL", true},
+		{"REAL", "INT", false},
+		{"INT", "DURATION", false},
+		{"STRING", "DURATION", false},
+		{"STRING", "BOOL", true},
+		{"IP", "BOOL", false},
+	}
+
+	for _, test := range tests {
+		if got := CanCoerce(test.from, test.to); got != test.expected {
+			t.Errorf("CanCoerce(%s, %s) = %v, want %v", test.from, test.to, got, test.expected)
+		}
+	}
+}
+
+func TestBinaryResult(t *testing.T) {
+	tests := []struct {
+		left, op, right string
+		expected        string
+	}{
+		{"STRING", "+", "DURATION", "STRING"},
+		{"HEADER", "+", "INT", "STRING"},
+		{"STRING", "+", "ACL", ""},
+		{"DURATION", "+", "STRING", ""},
+		{"STRING", "-", "STRING", ""},
+		{"TIME", "-", "TIME", "DURATION"},
+		{"TIME", "+", "DURATION", "TIME"},
+		{"DURATION", "+", "TIME", ""},
+		{"INT", "+", "REAL", "REAL"},
+		{"DURATION", "*", "INT", "DURATION"},
+		{"DURATION", "/", "REAL", "DURATION"},
+		{"INT", "*", "DURATION", ""},
+		{"INT", "*", "REAL", ""},
+		{"REAL", "*", "INT", "REAL"},
+		{"INT", "%", "INT", "INT"},
+		{"REAL", "%", "INT", ""},
+	}
+
+	for _, test := range tests {
+		got, ok := BinaryResult(test.op, test.left, test.right)
+		if ok != (test.expected != "") || got != test.expected {
+			t.Errorf("BinaryResult(%s %s %s) = %q, %v, want %q", test.left, test.op, test.right, got, ok, test.expected)
+		}
+	}
+}
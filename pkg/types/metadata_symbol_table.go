@@ -18,11 +18,7 @@ type MetadataSymbolTable struct {
 // NewMetadataSymbolTable creates a new symbol table using VCL metadata
 func NewMetadataSymbolTable(loader *metadata.MetadataLoader, typeSystem *MetadataTypeSystem) *MetadataSymbolTable {
 	// Create base symbol table without built-ins
-	global := NewScope("global", nil)
-	st := &SymbolTable{
-		currentScope: global,
-		globalScope:  global,
-	}
+	st := newScopedTable()
 
 	mst := &MetadataSymbolTable{
 		SymbolTable: st,
@@ -92,7 +88,7 @@ func (mst *MetadataSymbolTable) defineMetadataVariable(name string, varInfo meta
 		Methods:   readableMethods, // Use readable methods as default
 	}
 
-	return mst.Define(symbol)
+	return mst.builtinScope.Define(symbol)
 }
 
 // defineStorageVariable creates symbols for storage-specific variables
@@ -115,7 +111,7 @@ func (mst *MetadataSymbolTable) defineStorageVariable(storageVar metadata.Storag
 		Methods:  []string{"all"}, // Available in all methods
 	}
 
-	return mst.Define(symbol)
+	return mst.builtinScope.Define(symbol)
 }
 
 // resolveMethodPermissions converts metadata permission strings to specific method names
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+// Symbols returns the symbols visible from the current scope, sorted by
+// name. With kinds given, only symbols of those kinds are returned, e.g.
+// Symbols(SymbolBackend) lists the backends. A symbol shadows symbols of
+// the same name in enclosing scopes.
+func (st *SymbolTable) Symbols(kinds ...SymbolKind) []*Symbol {
+	seen := make(map[string]bool)
+	var result []*Symbol
+	for scope := st.currentScope; scope != nil; scope = scope.Parent {
+		for name, symbol := range scope.Symbols {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if len(kinds) == 0 || hasKind(kinds, symbol.Kind) {
+				result = append(result, symbol)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func hasKind(kinds []SymbolKind, kind SymbolKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the symbol a dotted name refers to, such as "req.url",
+// "std.log" or the name of a backend. Members of a symbol resolve to it,
+// so "req.http.Host" resolves to req.http. With sub set to a built-in
+// subroutine such as "vcl_recv", Resolve also checks that the symbol is
+// available there. Custom subroutines can be called from anywhere, so for
+// them, and for an empty sub, only the name is resolved.
+func (st *SymbolTable) Resolve(name, sub string) (*Symbol, error) {
+	symbol := st.Lookup(name)
+	for prefix := name; symbol == nil; {
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			return nil, fmt.Errorf("undefined symbol: %s", name)
+		}
+		prefix = prefix[:i]
+		symbol = st.Lookup(prefix)
+	}
+
+	method, builtin := strings.CutPrefix(sub, "vcl_")
+	if !builtin || len(symbol.Methods) == 0 {
+		return symbol, nil
+	}
+	for _, m := range symbol.Methods {
+		if m == method || m == "all" {
+			return symbol, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not available in %s", name, sub)
+}
+
+// Declaration returns the declaration that introduced the symbol called
+// name, such as the *ast.BackendDecl of a backend or the *ast.NewStatement
+// of a VMOD object. It returns nil for built-in symbols and names that are
+// not defined.
+func (st *SymbolTable) Declaration(name string) ast.Node {
+	if symbol := st.Lookup(name); symbol != nil {
+		return symbol.Decl
+	}
+	return nil
+}
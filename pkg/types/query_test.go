@@ -0,0 +1,121 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/ast"
+)
+
+func TestSymbolTable_Symbols(t *testing.T) {
+	st := NewSymbolTable()
+	if err := st.DefineBackend("web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.DefineBackend("api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.DefineACL("purgers"); err != nil {
+		t.Fatal(err)
+	}
+
+	backends := st.Symbols(SymbolBackend)
+	if len(backends) != 2 || backends[0].Name != "api" || backends[1].Name != "web" {
+		t.Errorf("Expected backends api and web, got %v", backends)
+	}
+	if acls := st.Symbols(SymbolACL); len(acls) != 1 || acls[0].Name != "purgers" {
+		t.Errorf("Expected ACL purgers, got %v", acls)
+	}
+	if all := st.Symbols(); len(all) <= 3 {
+		t.Errorf("Expected built-in symbols to be listed too, got %d symbols", len(all))
+	}
+
+	st.EnterScope("sub")
+	defer st.ExitScope()
+	if err := st.Define(&Symbol{Name: "web", Kind: SymbolVariable}); err != nil {
+		t.Fatal(err)
+	}
+	if backends := st.Symbols(SymbolBackend); len(backends) != 1 || backends[0].Name != "api" {
+		t.Errorf("Expected the shadowed backend to be hidden, got %v", backends)
+	}
+}
+
+func TestSymbolTable_Resolve(t *testing.T) {
+	st := NewSymbolTable()
+
+	symbol, err := st.Resolve("req.http.Host", "vcl_recv")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if symbol.Name != "req.http" {
+		t.Errorf("Expected req.http, got %s", symbol.Name)
+	}
+	if _, err := st.Resolve("resp.status", "vcl_recv"); err == nil {
+		t.Error("Expected resp.status to be unavailable in vcl_recv")
+	}
+	if _, err := st.Resolve("resp.status", "my_sub"); err != nil {
+		t.Errorf("Expected resp.status to resolve in a custom sub: %v", err)
+	}
+	if _, err := st.Resolve("nosuch.thing", ""); err == nil {
+		t.Error("Expected an error for an undefined name")
+	}
+}
+
+func TestSymbolTable_Declaration(t *testing.T) {
+	st := NewSymbolTable()
+	decl := &ast.BackendDecl{Name: "web"}
+	if err := st.Define(&Symbol{Name: "web", Kind: SymbolBackend, Type: Backend, Decl: decl}); err != nil {
+		t.Fatal(err)
+	}
+	if got := st.Declaration("web"); got != decl {
+		t.Errorf("Expected the backend declaration, got %v", got)
+	}
+	if got := st.Declaration("req.url"); got != nil {
+		t.Errorf("Expected no declaration for a built-in, got %v", got)
+	}
+}
+
+func TestSymbolTable_Scopes(t *testing.T) {
+	st := NewSymbolTable()
+	st.EnterScope("vcl_init")
+	if err := st.DefineVMODObject("dir", "directors", "round_robin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Define(&Symbol{Name: "local", Kind: SymbolVariable}); err != nil {
+		t.Fatal(err)
+	}
+	if st.LookupLocal("dir") != nil {
+		t.Error("Expected the VMOD object to be defined in the global scope")
+	}
+	if st.LookupLocal("local") == nil || st.Lookup("req.url") == nil {
+		t.Error("Expected local and built-in symbols to resolve")
+	}
+	st.ExitScope()
+	st.ExitScope()
+
+	if st.CurrentScope() != "global" {
+		t.Errorf("Expected to stay in the global scope, got %s", st.CurrentScope())
+	}
+	if symbol := st.Lookup("dir"); symbol == nil || symbol.Scope != "global" {
+		t.Errorf("Expected dir in the global scope, got %v", symbol)
+	}
+	if st.Lookup("local") != nil {
+		t.Error("Expected the local symbol to be gone with its scope")
+	}
+
+	// Program declarations may shadow built-ins
+	if err := st.DefineBackend("req"); err != nil {
+		t.Fatalf("DefineBackend: %v", err)
+	}
+	if symbol := st.Lookup("req"); symbol.Kind != SymbolBackend {
+		t.Errorf("Expected the backend to shadow req, got %v", symbol)
+	}
+	if builtin := st.ShadowedBuiltin("req"); builtin == nil || builtin.Kind != SymbolVariable {
+		t.Errorf("Expected req to shadow the built-in variable, got %v", builtin)
+	}
+	if builtin := st.ShadowedBuiltin("obj"); builtin == nil || builtin.Name != "obj.http" {
+		t.Errorf("Expected obj to shadow obj.http, got %v", builtin)
+	}
+	if builtin := st.ShadowedBuiltin("web"); builtin != nil {
+		t.Errorf("Expected no built-in for web, got %v", builtin)
+	}
+}
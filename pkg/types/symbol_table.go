@@ -2,7 +2,9 @@ package types
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/perbu/vclparser/pkg/ast"
 	"github.com/perbu/vclparser/pkg/lexer"
 )
 
@@ -19,6 +21,7 @@ const (
 	SymbolModule
 	SymbolVMODFunction
 	SymbolVMODObject
+	SymbolDictionary // a Fastly table
 )
 
 func (sk SymbolKind) String() string {
@@ -41,6 +44,8 @@ func (sk SymbolKind) String() string {
 		return "VMOD Function"
 	case SymbolVMODObject:
 		return "VMOD Object"
+	case SymbolDictionary:
+		return "Dictionary"
 	default:
 		return "Unknown"
 	}
@@ -57,6 +62,15 @@ type Symbol struct {
 	Writable  bool
 	Unsetable bool
 	Methods   []string // VCL methods where this symbol is accessible
+	// Decl is the declaration that introduced the symbol, nil for built-in
+	// symbols. Position is where it starts.
+	Decl ast.Node
+	// DefPosition is where the name is written in Decl, or where Decl
+	// starts if the tree does not record it, as for imports
+	DefPosition lexer.Position
+	// References are the uses of the symbol in the analyzed program, in
+	// source order
+	References []Reference
 
 	// VMOD-specific metadata
 	ModuleName  string   // For VMOD objects and functions
@@ -64,6 +78,16 @@ type Symbol struct {
 	VMODMethods []string // Available methods on VMOD objects
 }
 
+// Reference is a use of a symbol
+type Reference struct {
+	// Node is the identifier naming the symbol
+	Node     ast.Node
+	Position lexer.Position
+	// Sub is the subroutine the use is in, empty for uses outside of
+	// subroutines such as the .probe of a backend
+	Sub string
+}
+
 func (s *Symbol) String() string {
 	return fmt.Sprintf("%s %s: %s", s.Kind, s.Name, s.Type)
 }
@@ -105,22 +129,37 @@ func (s *Scope) Lookup(name string) *Symbol {
 	return nil
 }
 
-// SymbolTable manages symbols and scopes
+// SymbolTable manages symbols and scopes. Built-in symbols live in the
+// outermost scope, declarations of the program in the "global" scope below
+// it, and analyses enter further scopes for subroutines and blocks. A
+// symbol defined in an inner scope shadows symbols of the same name in the
+// enclosing ones.
 type SymbolTable struct {
 	currentScope *Scope
 	globalScope  *Scope
+	builtinScope *Scope
 }
 
-// NewSymbolTable creates a new symbol table
-func NewSymbolTable() *SymbolTable {
-	global := NewScope("global", nil)
-	st := &SymbolTable{
+// newScopedTable returns a symbol table with an empty built-in scope and the
+// global scope entered
+func newScopedTable() *SymbolTable {
+	builtin := NewScope("builtin", nil)
+	global := NewScope("global", builtin)
+	return &SymbolTable{
 		currentScope: global,
 		globalScope:  global,
+		builtinScope: builtin,
 	}
+}
+
+// NewSymbolTable creates a new symbol table
+func NewSymbolTable() *SymbolTable {
+	st := newScopedTable()
 
 	// Define built-in symbols
+	st.currentScope = st.builtinScope
 	st.defineBuiltins()
+	st.currentScope = st.globalScope
 
 	return st
 }
@@ -131,9 +170,9 @@ func (st *SymbolTable) EnterScope(name string) {
 	st.currentScope = newScope
 }
 
-// ExitScope exits the current scope
+// ExitScope exits the current scope. The global scope is never left.
 func (st *SymbolTable) ExitScope() {
-	if st.currentScope.Parent != nil {
+	if st.currentScope != st.globalScope && st.currentScope.Parent != nil {
 		st.currentScope = st.currentScope.Parent
 	}
 }
@@ -143,11 +182,40 @@ func (st *SymbolTable) Define(symbol *Symbol) error {
 	return st.currentScope.Define(symbol)
 }
 
+// DefineGlobal adds a symbol to the global scope, whatever the current
+// scope is. VCL declares VMOD objects inside vcl_init, for instance, but
+// they are visible everywhere.
+func (st *SymbolTable) DefineGlobal(symbol *Symbol) error {
+	return st.globalScope.Define(symbol)
+}
+
 // Lookup finds a symbol in the current scope or parent scopes
 func (st *SymbolTable) Lookup(name string) *Symbol {
 	return st.currentScope.Lookup(name)
 }
 
+// LookupLocal finds a symbol in the current scope only
+func (st *SymbolTable) LookupLocal(name string) *Symbol {
+	return st.currentScope.Symbols[name]
+}
+
+// ShadowedBuiltin returns the built-in symbol a declaration called name
+// would shadow, or nil if there is none. Besides built-ins of the same name
+// this covers the namespaces of built-in variables, so a backend called
+// "client" shadows client.ip.
+func (st *SymbolTable) ShadowedBuiltin(name string) *Symbol {
+	if symbol, exists := st.builtinScope.Symbols[name]; exists {
+		return symbol
+	}
+	var shadowed *Symbol
+	for builtinName, symbol := range st.builtinScope.Symbols {
+		if strings.HasPrefix(builtinName, name+".") && (shadowed == nil || builtinName < shadowed.Name) {
+			shadowed = symbol
+		}
+	}
+	return shadowed
+}
+
 // CurrentScope returns the current scope name
 func (st *SymbolTable) CurrentScope() string {
 	return st.currentScope.Name
@@ -441,11 +509,10 @@ func (st *SymbolTable) DefineVMODFunction(moduleName, functionName string, retur
 
 // DefineVMODObject adds a VMOD object instance to the symbol table
 func (st *SymbolTable) DefineVMODObject(objectName, moduleName, objectType string) error {
-	return st.Define(&Symbol{
+	return st.DefineGlobal(&Symbol{
 		Name:       objectName,
 		Kind:       SymbolVMODObject,
 		Type:       Object,
-		Scope:      st.currentScope.Name,
 		ModuleName: moduleName,
 		ObjectType: objectType,
 		// VMODMethods will be populated from VCC registry if needed
@@ -461,6 +528,33 @@ func (st *SymbolTable) DefineBackend(backendName string) error {
 	})
 }
 
+// DefineACL adds an ACL declaration to the symbol table
+func (st *SymbolTable) DefineACL(aclName string) error {
+	return st.Define(&Symbol{
+		Name: aclName,
+		Kind: SymbolACL,
+		Type: ACL,
+	})
+}
+
+// DefineDictionary adds a Fastly table to the symbol table
+func (st *SymbolTable) DefineDictionary(tableName string) error {
+	return st.Define(&Symbol{
+		Name: tableName,
+		Kind: SymbolDictionary,
+		Type: Dictionary,
+	})
+}
+
+// DefineProbe adds a probe declaration to the symbol table
+func (st *SymbolTable) DefineProbe(probeName string) error {
+	return st.Define(&Symbol{
+		Name: probeName,
+		Kind: SymbolProbe,
+		Type: Probe,
+	})
+}
+
 // LookupVMODFunction looks up a VMOD function by module and function name
 func (st *SymbolTable) LookupVMODFunction(moduleName, functionName string) *Symbol {
 	fullName := moduleName + "." + functionName
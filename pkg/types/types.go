@@ -44,9 +44,11 @@ var (
 	Header   = &BasicType{Name: "HEADER"}
 	Void     = &BasicType{Name: "VOID"}
 	Module   = &BasicType{Name: "MODULE"}
-	Object   = &BasicType{Name: "OBJECT"}
-	Bytes    = &BasicType{Name: "BYTES"}
-	HTTP     = &BasicType{Name: "HTTP"}
+	// Dictionary is the type of Fastly tables
+	Dictionary = &BasicType{Name: "TABLE"}
+	Object     = &BasicType{Name: "OBJECT"}
+	Bytes      = &BasicType{Name: "BYTES"}
+	HTTP       = &BasicType{Name: "HTTP"}
 )
 
 // HeaderType represents header variables
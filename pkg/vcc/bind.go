@@ -0,0 +1,88 @@
+package vcc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ArgumentState tells how a parameter of a call gets its value
+type ArgumentState int
+
+const (
+	// ArgumentGiven is an argument written in the call
+	ArgumentGiven ArgumentState = iota
+	// ArgumentDefaulted is an argument left out of the call, for which the
+	// default value of the parameter is passed
+	ArgumentDefaulted
+	// ArgumentOmitted is an argument left out of the call for an optional
+	// parameter without default value: the VMOD is told it is missing
+	ArgumentOmitted
+)
+
+// String returns the name of the state
+func (s ArgumentState) String() string {
+	switch s {
+	case ArgumentGiven:
+		return "given"
+	case ArgumentDefaulted:
+		return "defaulted"
+	case ArgumentOmitted:
+		return "omitted"
+	}
+	return fmt.Sprintf("ArgumentState(%d)", int(s))
+}
+
+// Bind matches the positional and named arguments of a call to params, the
+// parameters written in VCL as returned by VCLParameters. It returns the
+// arguments in parameter order, with the zero value for the ones left out,
+// and the state of each. Leaving out a required parameter, naming an
+// unknown one or giving one twice is an error.
+func Bind[T any](params []Parameter, positional []T, named map[string]T) ([]T, []ArgumentState, error) {
+	if len(positional) > len(params) {
+		return nil, nil, fmt.Errorf("too many positional arguments: got %d, function accepts at most %d", len(positional), len(params))
+	}
+	args := make([]T, len(params))
+	states := make([]ArgumentState, len(params))
+	given := make([]bool, len(params))
+	copy(args, positional)
+	for i := range positional {
+		given[i] = true
+	}
+
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		index := -1
+		for i, param := range params {
+			if param.Name == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, nil, fmt.Errorf("unknown argument '%s'", name)
+		}
+		if given[index] {
+			return nil, nil, fmt.Errorf("argument '%s' already provided as positional argument", name)
+		}
+		args[index] = named[name]
+		given[index] = true
+	}
+
+	for i, param := range params {
+		switch {
+		case given[i]:
+			states[i] = ArgumentGiven
+		case param.HasDefault:
+			states[i] = ArgumentDefaulted
+		case param.Optional:
+			states[i] = ArgumentOmitted
+		default:
+			return nil, nil, fmt.Errorf("missing required argument '%s'", param.Name)
+		}
+	}
+	return args, states, nil
+}
@@ -0,0 +1,66 @@
+package vcc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	module, err := NewParser(strings.NewReader(`$Module bind 3 "Binding"
+$Function STRING get(STRING key, STRING fallback = "", [INT ttl], [INT retries = 3])`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := module.Functions[0].Parameters
+	if params[1].Optional || !params[1].HasDefault || params[1].Required() {
+		t.Errorf("fallback = %+v, want a defaulted parameter outside brackets", params[1])
+	}
+	if !params[2].Optional || params[2].HasDefault || params[2].Required() {
+		t.Errorf("ttl = %+v, want an optional parameter without default", params[2])
+	}
+
+	tests := []struct {
+		name       string
+		positional []string
+		named      map[string]string
+		args       []string
+		states     []ArgumentState
+		err        string
+	}{
+		{
+			name:       "only required",
+			positional: []string{"k"},
+			args:       []string{"k", "", "", ""},
+			states:     []ArgumentState{ArgumentGiven, ArgumentDefaulted, ArgumentOmitted, ArgumentDefaulted},
+		},
+		{
+			name:       "named",
+			positional: []string{"k"},
+			named:      map[string]string{"ttl": "10", "fallback": "f"},
+			args:       []string{"k", "f", "10", ""},
+			states:     []ArgumentState{ArgumentGiven, ArgumentGiven, ArgumentGiven, ArgumentDefaulted},
+		},
+		{name: "missing", named: map[string]string{"ttl": "10"}, err: "missing required argument 'key'"},
+		{name: "unknown", positional: []string{"k"}, named: map[string]string{"zz": "1", "aa": "1"}, err: "unknown argument 'aa'"},
+		{name: "twice", positional: []string{"k"}, named: map[string]string{"key": "k"}, err: "argument 'key' already provided as positional argument"},
+		{name: "too many", positional: []string{"1", "2", "3", "4", "5"}, err: "too many positional arguments: got 5, function accepts at most 4"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args, states, err := Bind(params, test.positional, test.named)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("Bind() error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(args, test.args) || !reflect.DeepEqual(states, test.states) {
+				t.Errorf("Bind() = %q, %v, want %q, %v", args, states, test.args, test.states)
+			}
+		})
+	}
+}
@@ -64,7 +64,7 @@ func TestParseBracketParameters(t *testing.T) {
 			name:         "named parameter without brackets",
 			input:        "BYTES buf_size = 32768",
 			expectError:  false,
-			expectedOpt:  true,
+			expectedOpt:  false,
 			expectedName: "buf_size",
 			expectedType: TypeBytes,
 			expectedDef:  "32768",
@@ -0,0 +1,117 @@
+package vcc
+
+import (
+	"strings"
+)
+
+// extractDoc returns the RST documentation between line start and the
+// current token, with $ directives such as $Restrict left out, together with
+// examples followed by the literal blocks of its example sections. It
+// returns examples unchanged when the parser does not keep the source.
+func (p *Parser) extractDoc(start int, examples []string) (string, []string) {
+	if p.source == nil {
+		return "", examples
+	}
+
+	lines := strings.Split(p.source.String(), "\n")
+	end := len(lines) + 1
+	if p.currentToken.Type != EOF {
+		end = p.currentToken.Line
+	}
+	if start < 1 {
+		start = 1
+	}
+
+	var doc []string
+	for n := start; n < end && n <= len(lines); n++ {
+		line := strings.TrimRight(lines[n-1], " \t\r")
+		if strings.HasPrefix(line, "$") {
+			continue
+		}
+		doc = append(doc, line)
+	}
+	doc = dedent(trimBlankLines(doc))
+
+	return strings.Join(doc, "\n"), append(examples, exampleBlocks(doc)...)
+}
+
+// exampleBlocks returns the literal blocks that follow example headings,
+// which VMOD documentation writes in several ways:
+//
+//	Example::
+//
+//		set req.http.x = std.toupper(req.http.x);
+//
+//	Example
+//		new vdir = directors.round_robin();
+//
+// A heading is an unindented line starting with "Example", and its block
+// the indented lines after it, skipping blank lines and a lone "::".
+func exampleBlocks(lines []string) []string {
+	var blocks []string
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "Example") {
+			continue
+		}
+
+		j := i + 1
+		for j < len(lines) && (strings.TrimSpace(lines[j]) == "" || lines[j] == "::") {
+			j++
+		}
+		var block []string
+		for ; j < len(lines); j++ {
+			if lines[j] != "" && !isIndented(lines[j]) {
+				break
+			}
+			block = append(block, lines[j])
+		}
+		if block = trimBlankLines(block); len(block) > 0 {
+			blocks = append(blocks, strings.Join(dedent(block), "\n"))
+		}
+		i = j - 1
+	}
+	return blocks
+}
+
+// trimBlankLines drops the blank lines at the start and end of lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// dedent removes the indentation all non-blank lines have in common
+func dedent(lines []string) []string {
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if prefix == "" {
+		return lines
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimPrefix(line, prefix)
+	}
+	return out
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
@@ -0,0 +1,43 @@
+package vcc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	files, _ := filepath.Glob("../../tests/testdata/*.vcc")
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			f.Add(string(data))
+		}
+	}
+	f.Add("$Module m 3 \"x\"\n$ABI strict\n$Object o(STRING s = \"a\", [INT n])\n$Method VOID .m(ENUM {A, B} e)\n$Function BACKEND f(PRIV_TASK, [REAL r = 0.5])\n$Restrict vcl_recv backend\n$Alias .n o.m\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		module, err := NewParser(strings.NewReader(input)).Parse()
+		if err != nil {
+			return
+		}
+		// Whatever parses must generate a .vcc file that parses again
+		var buf bytes.Buffer
+		if err := NewGenerator().Generate(&buf, module); err != nil {
+			return
+		}
+		if _, err := NewParser(&buf).Parse(); err != nil {
+			t.Fatalf("generated module does not parse: %v\n%s", err, buf.String())
+		}
+	})
+}
+
+func FuzzParseJSON(f *testing.F) {
+	f.Add(`[["$VMOD","1.0","m","Vmod_m_Func","0","0","0"],["$FUNC","f",[["STRING"],"Vmod_m_Func.f",""]]]`)
+	f.Add(`[["$OBJ","o",{},"struct",["$INIT",[["VOID"],"x","",["STRING","s","\"a\""]]],["$METHOD","m",[["INT"],"y",""]]]]`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		_, _ = ParseJSON([]byte(input))
+	})
+}
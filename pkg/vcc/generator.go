@@ -0,0 +1,215 @@
+package vcc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Generator writes a Module as a .vcc file, so VMODs described in Go can be
+// loaded into a registry or documented the same way as .vcc sources. The
+// output parses back into an equivalent Module.
+type Generator struct {
+	errors []string
+}
+
+// NewGenerator creates a new VCC generator
+func NewGenerator() *Generator {
+	return &Generator{}
+}
+
+// Generate writes module to w in VCC syntax. It returns an error, and writes
+// nothing, when the module cannot be expressed as a .vcc file, such as when
+// it has no name or a parameter has no type.
+func (g *Generator) Generate(w io.Writer, module *Module) error {
+	g.errors = nil
+	g.check(module)
+	if len(g.errors) > 0 {
+		return fmt.Errorf("generate errors: %s", strings.Join(g.errors, "; "))
+	}
+
+	out := bufio.NewWriter(w)
+	description := module.Description
+	if strings.Contains(description, "\n") {
+		// A multi-line description only fits in a DESCRIPTION section
+		description = ""
+	}
+	fmt.Fprintf(out, "$Module %s %d %s\n", module.Name, module.Version, quoteVCC(description))
+	if module.ABI != "" {
+		fmt.Fprintf(out, "$ABI %s\n", module.ABI)
+	}
+	if module.Prefix != "" {
+		fmt.Fprintf(out, "$Prefix %s\n", module.Prefix)
+	}
+	if module.Synopsis != "" {
+		fmt.Fprintf(out, "$Synopsis %s\n", module.Synopsis)
+	}
+	if description == "" && module.Description != "" {
+		fmt.Fprintf(out, "\nDESCRIPTION\n\n%s\n", strings.TrimRight(module.Description, "\n"))
+	}
+	for _, event := range module.Events {
+		fmt.Fprintf(out, "\n$Event %s\n", event.Name)
+	}
+
+	for _, function := range module.Functions {
+		fmt.Fprintf(out, "\n$Function %s %s(%s)\n", function.ReturnType, function.Name, formatParameters(function.Parameters))
+		writeRestrictions(out, function.Restrictions)
+		writeDoc(out, function.Doc, function.Description)
+	}
+
+	for _, object := range module.Objects {
+		fmt.Fprintf(out, "\n$Object %s(%s)\n", object.Name, formatParameters(object.Constructor))
+		writeDoc(out, object.Doc, object.Description)
+		for _, method := range object.Methods {
+			fmt.Fprintf(out, "\n$Method %s .%s(%s)\n", method.ReturnType, method.Name, formatParameters(method.Parameters))
+			writeRestrictions(out, method.Restrictions)
+			writeDoc(out, method.Doc, method.Description)
+		}
+		for _, alias := range sortedKeys(object.Aliases) {
+			fmt.Fprintf(out, "\n$Alias .%s %s.%s\n", alias, object.Name, object.Aliases[alias])
+		}
+	}
+
+	for _, alias := range sortedKeys(module.Aliases) {
+		fmt.Fprintf(out, "\n$Alias %s %s\n", alias, module.Aliases[alias])
+	}
+
+	return out.Flush()
+}
+
+// check reports the parts of module that have no VCC form
+func (g *Generator) check(module *Module) {
+	if module.Name == "" {
+		g.addError("module has no name")
+	}
+	g.checkDoc("module "+module.Name, module.Description)
+	for _, function := range module.Functions {
+		g.checkSignature("function "+function.Name, function.Name, function.ReturnType, function.Parameters)
+		g.checkDoc("function "+function.Name, function.Doc, function.Description)
+	}
+	for _, object := range module.Objects {
+		g.checkSignature("object "+object.Name, object.Name, TypeVoid, object.Constructor)
+		g.checkDoc("object "+object.Name, object.Doc, object.Description)
+		for _, method := range object.Methods {
+			g.checkSignature("method "+object.Name+"."+method.Name, method.Name, method.ReturnType, method.Parameters)
+			g.checkDoc("method "+object.Name+"."+method.Name, method.Doc, method.Description)
+		}
+	}
+}
+
+// checkDoc reports documentation that would read back as directives, such
+// as text mentioning $Method outside a string
+func (g *Generator) checkDoc(what string, texts ...string) {
+	for _, text := range texts {
+		lexer := NewSimpleLexer(strings.NewReader(text))
+		for tok := lexer.NextToken(); tok.Type != EOF; tok = lexer.NextToken() {
+			if isTopLevelDirective(tok.Type) || tok.Type == METHOD || tok.Type == RESTRICT {
+				g.addError(fmt.Sprintf("documentation of %s reads as a %s directive", what, tok.Literal))
+				return
+			}
+		}
+	}
+}
+
+func (g *Generator) checkSignature(what, name string, returnType VCCType, params []Parameter) {
+	if name == "" {
+		g.addError(fmt.Sprintf("%s has no name", strings.TrimSpace(what)))
+	}
+	if returnType == "" {
+		g.addError(fmt.Sprintf("%s has no return type", what))
+	}
+	for i, param := range params {
+		switch {
+		case param.Type == "":
+			g.addError(fmt.Sprintf("parameter %d of %s has no type", i+1, what))
+		case param.Type == TypeEnum && (param.Enum == nil || len(param.Enum.Values) == 0):
+			g.addError(fmt.Sprintf("ENUM parameter %d of %s has no values", i+1, what))
+		}
+	}
+}
+
+func (g *Generator) addError(msg string) {
+	g.errors = append(g.errors, msg)
+}
+
+// formatParameters formats a parameter list the way .vcc files write it,
+// such as "STRING s, [INT n], ENUM {A, B} e=\"A\""
+func formatParameters(params []Parameter) string {
+	parts := make([]string, len(params))
+	for i, param := range params {
+		var s strings.Builder
+		if param.Type == TypeEnum && param.Enum != nil {
+			fmt.Fprintf(&s, "ENUM {%s}", strings.Join(param.Enum.Values, ", "))
+		} else {
+			s.WriteString(string(param.Type))
+		}
+		if param.Name != "" {
+			s.WriteString(" " + param.Name)
+		}
+		if param.HasDefault {
+			s.WriteString("=" + formatDefault(param))
+		}
+		if param.Optional {
+			parts[i] = "[" + s.String() + "]"
+			continue
+		}
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDefault returns the default value of the parameter as a VCC file
+// declares it, quoting the values of string and enum parameters, which the
+// parser stores without quotes
+func (p Parameter) FormatDefault() string {
+	return formatDefault(p)
+}
+
+// formatDefault quotes the default value of string and enum parameters,
+// which the parser stores without quotes
+func formatDefault(param Parameter) string {
+	switch param.Type {
+	case TypeString, TypeStringList, TypeStrands, TypeStringStable, TypeBody, TypeRegex, TypeEnum, TypeHeader:
+		return quoteVCC(param.DefaultValue)
+	}
+	return param.DefaultValue
+}
+
+// quoteVCC quotes s as a VCC string, escaping quotes and backslashes
+func quoteVCC(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func writeRestrictions(out *bufio.Writer, restrictions []Restriction) {
+	if len(restrictions) == 0 {
+		return
+	}
+	contexts := make([]string, len(restrictions))
+	for i, r := range restrictions {
+		contexts[i] = string(r)
+	}
+	fmt.Fprintf(out, "$Restrict %s\n", strings.Join(contexts, " "))
+}
+
+// writeDoc writes the documentation following a directive, preferring the
+// RST of Doc over the plain Description
+func writeDoc(out *bufio.Writer, doc, description string) {
+	text := doc
+	if text == "" {
+		text = description
+	}
+	if text = strings.TrimRight(text, "\n"); text != "" {
+		fmt.Fprintf(out, "\n%s\n", text)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
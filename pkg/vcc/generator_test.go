@@ -0,0 +1,143 @@
+package vcc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	module := &Module{
+		Name:        "example",
+		Version:     3,
+		Description: "Example VMOD",
+		ABI:         "vrt",
+		Events:      []Event{{Name: "event_function"}},
+		Functions: []Function{
+			{
+				Name:       "encode",
+				ReturnType: TypeString,
+				Parameters: []Parameter{
+					{Name: "encoding", Type: TypeEnum, Enum: &Enum{Values: []string{"BASE64", "HEX"}}, DefaultValue: "HEX", HasDefault: true},
+					{Name: "s", Type: TypeString},
+					{Name: "n", Type: TypeInt, Optional: true},
+				},
+				Restrictions: []Restriction{RestrictClient, RestrictBackend},
+				Doc:          "Encodes *s*.\n\nExample::\n\n\tset req.http.x = example.encode(s=\"x\");",
+			},
+		},
+		Objects: []Object{
+			{
+				Name:        "counter",
+				Constructor: []Parameter{{Name: "start", Type: TypeInt, DefaultValue: "0", HasDefault: true}},
+				Methods:     []Method{{Name: "get", ReturnType: TypeInt, Description: "Returns the count."}},
+				Aliases:     map[string]string{"value": "get"},
+			},
+		},
+		Aliases: map[string]string{"enc": "encode"},
+	}
+
+	var out bytes.Buffer
+	if err := NewGenerator().Generate(&out, module); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{
+		`$Module example 3 "Example VMOD"`,
+		`$Function STRING encode(ENUM {BASE64, HEX} encoding="HEX", STRING s, [INT n])`,
+		"$Restrict client backend",
+		"$Object counter(INT start=0)",
+		"$Method INT .get()",
+		"$Alias .value counter.get",
+		"$Alias enc encode",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	parsed, err := NewDocsParser(&out).Parse()
+	if err != nil {
+		t.Fatalf("Parse() of generated VCC error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Functions[0].Parameters, module.Functions[0].Parameters) {
+		t.Errorf("parameters = %+v, want %+v", parsed.Functions[0].Parameters, module.Functions[0].Parameters)
+	}
+	if parsed.Functions[0].Doc != module.Functions[0].Doc {
+		t.Errorf("Doc = %q, want %q", parsed.Functions[0].Doc, module.Functions[0].Doc)
+	}
+	if parsed.FindFunction("enc") == nil || parsed.FindObject("counter").FindMethod("value") == nil {
+		t.Error("aliases were not preserved")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	module := &Module{
+		Functions: []Function{{Name: "f", ReturnType: TypeVoid, Parameters: []Parameter{{Name: "e", Type: TypeEnum}}}},
+		Objects:   []Object{{Name: "o", Description: "Usage:\n\n  $ curl localhost\n$Method VOID .m()"}},
+	}
+
+	var out bytes.Buffer
+	err := NewGenerator().Generate(&out, module)
+	if err == nil || !strings.Contains(err.Error(), "module has no name") ||
+		!strings.Contains(err.Error(), "ENUM parameter 1 of function f has no values") ||
+		!strings.Contains(err.Error(), "documentation of object o reads as a $Method directive") {
+		t.Errorf("Generate() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Generate() wrote output on error: %q", out.String())
+	}
+}
+
+// TestGenerateRoundTrip regenerates every module of vcclib and checks that
+// the signatures, restrictions and docs survive
+func TestGenerateRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("../../vcclib/*.vcc")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no vcclib files found: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			f, err := os.Open(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			module, err := NewDocsParser(f).Parse()
+			if err != nil {
+				t.Skipf("vcclib file does not parse: %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := NewGenerator().Generate(&out, module); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			parsed, err := NewDocsParser(&out).Parse()
+			if err != nil {
+				t.Fatalf("Parse() of generated VCC error = %v", err)
+			}
+
+			if len(parsed.Functions) != len(module.Functions) || len(parsed.Objects) != len(module.Objects) {
+				t.Fatalf("got %d functions and %d objects, want %d and %d",
+					len(parsed.Functions), len(parsed.Objects), len(module.Functions), len(module.Objects))
+			}
+			for i, want := range module.Functions {
+				got := parsed.Functions[i]
+				if !reflect.DeepEqual(got.Parameters, want.Parameters) || !reflect.DeepEqual(got.Restrictions, want.Restrictions) ||
+					got.Doc != want.Doc {
+					t.Errorf("function %s does not round-trip", want.Name)
+				}
+			}
+			for i, want := range module.Objects {
+				got := parsed.Objects[i]
+				if !reflect.DeepEqual(got.Constructor, want.Constructor) || len(got.Methods) != len(want.Methods) ||
+					got.Doc != want.Doc {
+					t.Errorf("object %s does not round-trip", want.Name)
+				}
+			}
+		})
+	}
+}
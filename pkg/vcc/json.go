@@ -0,0 +1,326 @@
+package vcc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonSpecMarker starts the JSON description vmodtool.py embeds in a VMOD
+// shared object since Varnish 7; the JSON ends at the following jsonSpecEnd
+const (
+	jsonSpecMarker = "VMOD_JSON_SPEC\x02"
+	jsonSpecEnd    = "\x03"
+)
+
+// ExtractJSON finds the JSON description of the VMOD in the contents of a
+// compiled shared object, such as libvmod_std.so. vmodtool.py stores it as a
+// C string, wrapped in VMOD_JSON_SPEC markers since Varnish 7 and bare in
+// older releases.
+func ExtractJSON(object []byte) ([]byte, error) {
+	if start := bytes.Index(object, []byte(jsonSpecMarker)); start >= 0 {
+		spec := object[start+len(jsonSpecMarker):]
+		if end := bytes.Index(spec, []byte(jsonSpecEnd)); end >= 0 {
+			return spec[:end], nil
+		}
+		return nil, fmt.Errorf("VMOD JSON is not terminated")
+	}
+
+	// Older releases: the C string holding "$VMOD"
+	at := bytes.Index(object, []byte(`"$VMOD"`))
+	if at < 0 {
+		return nil, fmt.Errorf("no VMOD JSON found")
+	}
+	start := bytes.LastIndexByte(object[:at], 0) + 1
+	end := bytes.IndexByte(object[at:], 0)
+	if end < 0 {
+		end = len(object) - at
+	}
+	return object[start : at+end], nil
+}
+
+// ParseJSON converts the JSON description of a VMOD, as embedded in its
+// shared object by vmodtool.py, into a Module. The description carries
+// signatures, restrictions and aliases but no documentation. data may
+// include the VMOD_JSON_SPEC markers.
+func ParseJSON(data []byte) (*Module, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte(jsonSpecMarker))
+	data = bytes.TrimSuffix(data, []byte(jsonSpecEnd))
+
+	var stanzas []json.RawMessage
+	if err := json.Unmarshal(data, &stanzas); err != nil {
+		return nil, fmt.Errorf("invalid VMOD JSON: %v", err)
+	}
+
+	module := &Module{
+		Functions: []Function{},
+		Objects:   []Object{},
+		Events:    []Event{},
+	}
+	var aliases [][2]string
+	for _, raw := range stanzas {
+		var stanza []json.RawMessage
+		if err := json.Unmarshal(raw, &stanza); err != nil || len(stanza) == 0 {
+			return nil, fmt.Errorf("invalid VMOD JSON stanza: %s", raw)
+		}
+		var kind string
+		if err := json.Unmarshal(stanza[0], &kind); err != nil {
+			return nil, fmt.Errorf("invalid VMOD JSON stanza: %s", raw)
+		}
+
+		var err error
+		switch kind {
+		case "$VMOD":
+			err = parseJSONModule(module, stanza[1:])
+		case "$FUNC":
+			var function *Function
+			if function, err = parseJSONFunction(stanza[1:]); err == nil {
+				module.Functions = append(module.Functions, *function)
+			}
+		case "$OBJ":
+			var object *Object
+			if object, err = parseJSONObject(stanza[1:]); err == nil {
+				module.Objects = append(module.Objects, *object)
+			}
+		case "$EVENT":
+			var cname string
+			if err = unmarshalAt(stanza, 1, &cname); err == nil {
+				module.Events = append(module.Events, Event{Name: cname[strings.LastIndex(cname, ".")+1:]})
+			}
+		case "$ALIAS":
+			var alias, target string
+			if err = unmarshalAt(stanza, 1, &alias); err == nil {
+				err = unmarshalAt(stanza, 2, &target)
+			}
+			aliases = append(aliases, [2]string{alias, target})
+		default:
+			// $CPROTO and later additions carry nothing the registry uses
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s stanza: %v", kind, err)
+		}
+	}
+
+	if module.Name == "" {
+		return nil, fmt.Errorf("VMOD JSON has no $VMOD stanza")
+	}
+	for _, a := range aliases {
+		addJSONAlias(module, a[0], a[1])
+	}
+	return module, nil
+}
+
+// parseJSONModule reads ["1.0", name, func struct, file id, varnish version,
+// vrt major, vrt minor]. Strict ABI modules record VRT version 0.0.
+func parseJSONModule(module *Module, fields []json.RawMessage) error {
+	var values []string
+	for _, field := range fields {
+		var s string
+		if err := json.Unmarshal(field, &s); err != nil {
+			return fmt.Errorf("expected strings, got %s", field)
+		}
+		values = append(values, s)
+	}
+	if len(values) < 2 {
+		return fmt.Errorf("expected a module name")
+	}
+	module.Name = values[1]
+	if len(values) >= 7 {
+		module.ABI = "vrt"
+		if values[5] == "0" && values[6] == "0" {
+			module.ABI = "strict"
+		}
+	}
+	return nil
+}
+
+// parseJSONFunction reads [name, prototype] followed by an optional
+// ["$RESTRICT", [contexts]]
+func parseJSONFunction(fields []json.RawMessage) (*Function, error) {
+	function := &Function{Examples: []string{}, Restrictions: []Restriction{}}
+	if err := unmarshalAt(fields, 0, &function.Name); err != nil {
+		return nil, err
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("function %s has no prototype", function.Name)
+	}
+	returnType, params, err := parseJSONPrototype(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %v", function.Name, err)
+	}
+	function.ReturnType, function.Parameters = returnType, params
+	if function.Restrictions, err = parseJSONRestrictions(fields[2:]); err != nil {
+		return nil, fmt.Errorf("function %s: %v", function.Name, err)
+	}
+	return function, nil
+}
+
+// parseJSONObject reads [name, flags, struct name, ["$INIT", prototype],
+// ["$FINI", prototype], ["$METHOD", name, prototype, ...]...]
+func parseJSONObject(fields []json.RawMessage) (*Object, error) {
+	object := &Object{Constructor: []Parameter{}, Methods: []Method{}, Examples: []string{}}
+	if err := unmarshalAt(fields, 0, &object.Name); err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields[1:] {
+		var member []json.RawMessage
+		var kind string
+		if json.Unmarshal(field, &member) != nil || len(member) < 2 || json.Unmarshal(member[0], &kind) != nil {
+			continue
+		}
+		switch kind {
+		case "$INIT":
+			_, params, err := parseJSONPrototype(member[1])
+			if err != nil {
+				return nil, fmt.Errorf("object %s: %v", object.Name, err)
+			}
+			object.Constructor = params
+		case "$METHOD":
+			method := Method{Examples: []string{}, Restrictions: []Restriction{}}
+			if err := unmarshalAt(member, 1, &method.Name); err != nil || len(member) < 3 {
+				return nil, fmt.Errorf("object %s: invalid method %s", object.Name, field)
+			}
+			method.Name = method.Name[strings.LastIndex(method.Name, ".")+1:]
+			returnType, params, err := parseJSONPrototype(member[2])
+			if err != nil {
+				return nil, fmt.Errorf("method %s.%s: %v", object.Name, method.Name, err)
+			}
+			method.ReturnType, method.Parameters = returnType, params
+			if method.Restrictions, err = parseJSONRestrictions(member[3:]); err != nil {
+				return nil, fmt.Errorf("method %s.%s: %v", object.Name, method.Name, err)
+			}
+			object.Methods = append(object.Methods, method)
+		}
+	}
+	return object, nil
+}
+
+// parseJSONPrototype reads [[return type], C function, argument struct,
+// argument...], where each argument is [type, name, default, enum values,
+// optional] with trailing nulls left out
+func parseJSONPrototype(raw json.RawMessage) (VCCType, []Parameter, error) {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || len(fields) == 0 {
+		return "", nil, fmt.Errorf("invalid prototype %s", raw)
+	}
+
+	var ret []json.RawMessage
+	var retName string
+	if err := json.Unmarshal(fields[0], &ret); err != nil || len(ret) == 0 || json.Unmarshal(ret[0], &retName) != nil {
+		return "", nil, fmt.Errorf("invalid return type %s", fields[0])
+	}
+	returnType, _, err := ParseVCCType(retName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	params := []Parameter{}
+	for i := 3; i < len(fields); i++ {
+		param, err := parseJSONArgument(fields[i])
+		if err != nil {
+			return "", nil, err
+		}
+		params = append(params, param)
+	}
+	return returnType, params, nil
+}
+
+func parseJSONArgument(raw json.RawMessage) (Parameter, error) {
+	var fields []json.RawMessage
+	var param Parameter
+	var typeName string
+	if err := json.Unmarshal(raw, &fields); err != nil || len(fields) == 0 || json.Unmarshal(fields[0], &typeName) != nil {
+		return param, fmt.Errorf("invalid argument %s", raw)
+	}
+	vccType, _, err := ParseVCCType(typeName)
+	if err != nil {
+		return param, err
+	}
+	param.Type = vccType
+
+	if len(fields) > 1 {
+		_ = json.Unmarshal(fields[1], &param.Name)
+	}
+	if len(fields) > 2 {
+		var defaultValue string
+		if json.Unmarshal(fields[2], &defaultValue) == nil && defaultValue != "" {
+			// Defaults are kept as written in the VCC file, quotes included
+			param.DefaultValue = strings.Trim(defaultValue, `"`)
+			param.HasDefault = true
+		}
+	}
+	if len(fields) > 3 && param.Type == TypeEnum {
+		enum := &Enum{Values: []string{}}
+		_ = json.Unmarshal(fields[3], &enum.Values)
+		param.Enum = enum
+	}
+	if len(fields) > 4 {
+		var optional bool
+		if json.Unmarshal(fields[4], &optional) == nil && optional {
+			param.Optional = true
+		}
+	}
+	return param, nil
+}
+
+// parseJSONRestrictions reads an optional ["$RESTRICT", [contexts]] among
+// the fields following a prototype
+func parseJSONRestrictions(fields []json.RawMessage) ([]Restriction, error) {
+	restrictions := []Restriction{}
+	for _, field := range fields {
+		var stanza []json.RawMessage
+		var kind string
+		if json.Unmarshal(field, &stanza) != nil || len(stanza) < 2 ||
+			json.Unmarshal(stanza[0], &kind) != nil || kind != "$RESTRICT" {
+			continue
+		}
+		var contexts []string
+		if err := json.Unmarshal(stanza[1], &contexts); err != nil {
+			return nil, fmt.Errorf("invalid $RESTRICT %s", stanza[1])
+		}
+		for _, context := range contexts {
+			r, err := ParseRestriction(context)
+			if err != nil {
+				return nil, err
+			}
+			if !containsRestriction(restrictions, r) {
+				restrictions = append(restrictions, r)
+			}
+		}
+	}
+	return restrictions, nil
+}
+
+// addJSONAlias records an alias the way resolveAliases does for $Alias,
+// ignoring aliases of names the module does not declare
+func addJSONAlias(module *Module, alias, target string) {
+	if strings.HasPrefix(alias, ".") {
+		objectName, methodName, ok := strings.Cut(target, ".")
+		object := module.FindObject(objectName)
+		if !ok || object == nil {
+			return
+		}
+		if object.Aliases == nil {
+			object.Aliases = make(map[string]string)
+		}
+		object.Aliases[strings.TrimPrefix(alias, ".")] = methodName
+		return
+	}
+	if module.Aliases == nil {
+		module.Aliases = make(map[string]string)
+	}
+	module.Aliases[alias] = target
+}
+
+// unmarshalAt decodes fields[i] into v
+func unmarshalAt(fields []json.RawMessage, i int, v interface{}) error {
+	if i >= len(fields) {
+		return fmt.Errorf("missing field %d", i+1)
+	}
+	if err := json.Unmarshal(fields[i], v); err != nil {
+		return fmt.Errorf("field %d: %v", i+1, err)
+	}
+	return nil
+}
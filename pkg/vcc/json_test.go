@@ -0,0 +1,117 @@
+package vcc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exampleJSON = `[
+	["$VMOD", "1.0", "example", "Vmod_example_Func", "e3b0c442", "Varnish 7.5.0", "0", "0"],
+	["$CPROTO", "struct vmod_example_counter;"],
+	["$FUNC", "encode",
+		[["STRING"], "Vmod_example_Func.f_encode", "struct VARGS(encode)",
+			["ENUM", "encoding", "\"HEX\"", ["BASE64", "HEX"]],
+			["STRING", "s"],
+			["INT", "n", null, null, true]
+		],
+		["$RESTRICT", ["client", "backend"]]
+	],
+	["$OBJ", "counter", {"NULL_OK": false}, "struct vmod_example_counter",
+		["$INIT", [["VOID"], "Vmod_example_Func.counter__init", "", ["INT", "start", "0"]]],
+		["$FINI", [["VOID"], "Vmod_example_Func.counter__fini", ""]],
+		["$METHOD", "get", [["INT"], "Vmod_example_Func.counter_get", "", ["PRIV_TASK"]]]
+	],
+	["$EVENT", "Vmod_example_Func._event"],
+	["$ALIAS", "enc", "encode"]
+]`
+
+func TestParseJSON(t *testing.T) {
+	module, err := ParseJSON([]byte(exampleJSON))
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	if module.Name != "example" || module.ABI != "strict" {
+		t.Errorf("Name, ABI = %s, %s, want example, strict", module.Name, module.ABI)
+	}
+
+	function := module.FindFunction("enc")
+	if function == nil || function.Name != "encode" || function.ReturnType != TypeString {
+		t.Fatalf("FindFunction(enc) = %+v, want encode returning STRING", function)
+	}
+	wantParams := []Parameter{
+		{Name: "encoding", Type: TypeEnum, Enum: &Enum{Values: []string{"BASE64", "HEX"}}, DefaultValue: "HEX", HasDefault: true},
+		{Name: "s", Type: TypeString},
+		{Name: "n", Type: TypeInt, Optional: true},
+	}
+	if !reflect.DeepEqual(function.Parameters, wantParams) {
+		t.Errorf("parameters = %+v, want %+v", function.Parameters, wantParams)
+	}
+	if !reflect.DeepEqual(function.Restrictions, []Restriction{RestrictClient, RestrictBackend}) {
+		t.Errorf("restrictions = %v, want [client backend]", function.Restrictions)
+	}
+
+	object := module.FindObject("counter")
+	if object == nil || len(object.Constructor) != 1 || object.Constructor[0].DefaultValue != "0" {
+		t.Fatalf("counter = %+v", object)
+	}
+	method := object.FindMethod("get")
+	if method == nil || method.ReturnType != TypeInt || len(method.Parameters) != 1 || method.Parameters[0].Type != TypePrivTask {
+		t.Errorf("counter.get = %+v", method)
+	}
+
+	if len(module.Events) != 1 || module.Events[0].Name != "_event" {
+		t.Errorf("events = %+v", module.Events)
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected string
+	}{
+		{"not JSON", "VMOD", "invalid VMOD JSON"},
+		{"no module", `[["$FUNC", "f", [["VOID"], "f", ""]]]`, "no $VMOD stanza"},
+		{"unknown type", `[["$VMOD", "1.0", "m"], ["$FUNC", "f", [["WIDGET"], "f", ""]]]`, "$FUNC stanza"},
+		{"unknown restriction", `[["$VMOD", "1.0", "m"], ["$FUNC", "f", [["VOID"], "f", ""], ["$RESTRICT", ["recv"]]]]`,
+			`unknown $Restrict context "recv"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSON([]byte(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ParseJSON() error = %v, want it to contain %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	spec := `[["$VMOD", "1.0", "example"]]`
+	tests := []struct {
+		name   string
+		object string
+	}{
+		{"marked", "\x7fELF\x00\x01" + jsonSpecMarker + spec + jsonSpecEnd + "\x00more"},
+		{"bare C string", "\x7fELF\x00\x01\x00" + spec + "\x00more"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSON([]byte(tt.object))
+			if err != nil {
+				t.Fatalf("ExtractJSON() error = %v", err)
+			}
+			if string(got) != spec {
+				t.Errorf("ExtractJSON() = %q, want %q", got, spec)
+			}
+		})
+	}
+
+	if _, err := ExtractJSON([]byte("\x7fELF\x00")); err == nil {
+		t.Error("ExtractJSON() of an object without VMOD JSON should fail")
+	}
+}
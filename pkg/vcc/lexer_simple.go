@@ -25,6 +25,9 @@ const (
 	RESTRICT // $Restrict
 	ABI      // $ABI
 	LICENSE  // $License
+	ALIAS    // $Alias
+	PREFIX   // $Prefix
+	SYNOPSIS // $Synopsis
 
 	// Literals
 	IDENT    // identifiers, type names
@@ -83,6 +86,12 @@ func (t TokenType) String() string {
 		return "ABI"
 	case LICENSE:
 		return "LICENSE"
+	case ALIAS:
+		return "ALIAS"
+	case PREFIX:
+		return "PREFIX"
+	case SYNOPSIS:
+		return "SYNOPSIS"
 	case IDENT:
 		return "IDENT"
 	case STRING:
@@ -362,6 +371,12 @@ func (l *SimpleLexer) lookupDirective(literal string) TokenType {
 		return ABI
 	case "$License":
 		return LICENSE
+	case "$Alias":
+		return ALIAS
+	case "$Prefix":
+		return PREFIX
+	case "$Synopsis":
+		return SYNOPSIS
 	default:
 		return IDENT
 	}
@@ -1,6 +1,7 @@
 package vcc
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strconv"
@@ -17,6 +18,16 @@ type Parser struct {
 	lexer        VCCLexer
 	errors       []string
 	currentToken Token
+	previousLine int // line of the token before currentToken
+	aliases      []alias
+	source       *bytes.Buffer // source text read so far, kept by docs parsers
+}
+
+// alias is an $Alias directive waiting to be resolved
+type alias struct {
+	name   string
+	target string
+	line   int
 }
 
 // NewParser creates a new VCC parser
@@ -30,8 +41,23 @@ func NewParser(r io.Reader) *Parser {
 	return p
 }
 
+// NewDocsParser creates a VCC parser that also extracts the RST
+// documentation of every function, object and method into their Doc and
+// Examples fields
+func NewDocsParser(r io.Reader) *Parser {
+	source := &bytes.Buffer{}
+	p := &Parser{
+		lexer:  NewSimpleLexer(io.TeeReader(r, source)),
+		errors: []string{},
+		source: source,
+	}
+	p.nextToken()
+	return p
+}
+
 // nextToken advances to the next token
 func (p *Parser) nextToken() {
+	p.previousLine = p.currentToken.Line
 	p.currentToken = p.lexer.NextToken()
 }
 
@@ -64,6 +90,8 @@ func (p *Parser) Parse() (*Module, error) {
 		case EVENT:
 			if event, err := p.parseEvent(); err != nil {
 				p.addError(err.Error())
+			} else if len(module.Events) > 0 {
+				p.addError(fmt.Sprintf("only one $Event is allowed per module, %s follows %s", event.Name, module.Events[0].Name))
 			} else {
 				module.Events = append(module.Events, *event)
 			}
@@ -71,6 +99,17 @@ func (p *Parser) Parse() (*Module, error) {
 			if err := p.parseABI(module); err != nil {
 				p.addError(err.Error())
 			}
+		case ALIAS:
+			if err := p.parseAlias(); err != nil {
+				p.addError(err.Error())
+			}
+		case PREFIX:
+			module.Prefix = p.readDirectiveWord("$Prefix")
+		case SYNOPSIS:
+			module.Synopsis = p.readDirectiveWord("$Synopsis")
+			if module.Synopsis != "" && module.Synopsis != "auto" && module.Synopsis != "manual" {
+				p.addError(fmt.Sprintf("$Synopsis must be auto or manual, got %s", module.Synopsis))
+			}
 		case COMMENT:
 			// Skip comments
 			p.nextToken()
@@ -87,6 +126,8 @@ func (p *Parser) Parse() (*Module, error) {
 		}
 	}
 
+	p.resolveAliases(module)
+
 	if len(p.errors) > 0 {
 		return module, fmt.Errorf("parse errors: %s", strings.Join(p.errors, "; "))
 	}
@@ -134,27 +175,24 @@ func (p *Parser) parseFunction() (*Function, error) {
 	function := &Function{
 		Parameters:   []Parameter{},
 		Examples:     []string{},
-		Restrictions: []string{},
+		Restrictions: []Restriction{},
 	}
 
 	// Parse function signature: RETURN_TYPE name(params)
 	if err := p.parseFunctionSignatureTokens(function); err != nil {
 		return nil, err
 	}
+	docStart := p.previousLine + 1
 
 	// Parse description and examples
 	for p.currentToken.Type != EOF {
 		// Stop if we hit another directive
-		if p.currentToken.Type == MODULE || p.currentToken.Type == FUNCTION ||
-			p.currentToken.Type == OBJECT || p.currentToken.Type == METHOD ||
-			p.currentToken.Type == EVENT || p.currentToken.Type == ABI {
+		if isTopLevelDirective(p.currentToken.Type) || p.currentToken.Type == METHOD {
 			break
 		}
 
 		if p.currentToken.Type == RESTRICT {
-			p.nextToken()
-			restriction := p.readUntilNewline()
-			function.Restrictions = append(function.Restrictions, restriction)
+			function.Restrictions = append(function.Restrictions, p.readRestrictions()...)
 		} else {
 			// Read description text
 			line := p.readUntilNewline()
@@ -167,6 +205,7 @@ func (p *Parser) parseFunction() (*Function, error) {
 			}
 		}
 	}
+	function.Doc, function.Examples = p.extractDoc(docStart, function.Examples)
 
 	return function, nil
 }
@@ -186,18 +225,31 @@ func (p *Parser) parseObject() (*Object, error) {
 	if err := p.parseObjectSignatureTokens(object); err != nil {
 		return nil, err
 	}
+	docStart := p.previousLine + 1
+	docDone := false
 
 	// Parse description and methods
 	for p.currentToken.Type != EOF {
 		token := p.currentToken
 
 		// Stop if we hit another top-level directive
-		if token.Type == MODULE || token.Type == FUNCTION || token.Type == OBJECT ||
-			token.Type == EVENT || token.Type == ABI {
+		if isTopLevelDirective(token.Type) {
 			break
 		}
 
+		if token.Type == RESTRICT {
+			// Objects have no restrictions of their own
+			p.addError(fmt.Sprintf("$Restrict must follow a $Function or $Method, not $Object %s", object.Name))
+			p.readRestrictions()
+			continue
+		}
+
 		if token.Type == METHOD {
+			// The object's documentation ends at its first method
+			if !docDone {
+				object.Doc, object.Examples = p.extractDoc(docStart, object.Examples)
+				docDone = true
+			}
 			method, err := p.parseMethod()
 			if err != nil {
 				return nil, err
@@ -215,6 +267,9 @@ func (p *Parser) parseObject() (*Object, error) {
 			}
 		}
 	}
+	if !docDone {
+		object.Doc, object.Examples = p.extractDoc(docStart, object.Examples)
+	}
 
 	return object, nil
 }
@@ -227,28 +282,26 @@ func (p *Parser) parseMethod() (*Method, error) {
 	method := &Method{
 		Parameters:   []Parameter{},
 		Examples:     []string{},
-		Restrictions: []string{},
+		Restrictions: []Restriction{},
 	}
 
 	// Parse method signature: RETURN_TYPE .name(params)
 	if err := p.parseMethodSignatureTokens(method); err != nil {
 		return nil, err
 	}
+	docStart := p.previousLine + 1
 
 	// Parse description and restrictions
 	for p.currentToken.Type != EOF {
 		token := p.currentToken
 
 		// Stop if we hit another directive
-		if token.Type == MODULE || token.Type == FUNCTION || token.Type == OBJECT ||
-			token.Type == METHOD || token.Type == EVENT || token.Type == ABI {
+		if isTopLevelDirective(token.Type) || token.Type == METHOD {
 			break
 		}
 
 		if token.Type == RESTRICT {
-			p.nextToken()
-			restriction := p.readUntilNewline()
-			method.Restrictions = append(method.Restrictions, restriction)
+			method.Restrictions = append(method.Restrictions, p.readRestrictions()...)
 		} else {
 			// Read description text
 			line := p.readUntilNewline()
@@ -261,6 +314,7 @@ func (p *Parser) parseMethod() (*Method, error) {
 			}
 		}
 	}
+	method.Doc, method.Examples = p.extractDoc(docStart, method.Examples)
 
 	return method, nil
 }
@@ -294,9 +348,94 @@ func (p *Parser) parseABI(module *Module) error {
 	module.ABI = p.currentToken.Literal
 	p.nextToken()
 
+	if module.ABI != "strict" && module.ABI != "vrt" {
+		return fmt.Errorf("$ABI must be strict or vrt, got %s", module.ABI)
+	}
+	return nil
+}
+
+// parseAlias parses an $Alias directive, which gives a function or object
+// another name, or a method when both names start with a dot:
+//
+//	$Alias alias function
+//	$Alias .alias object.method
+//
+// Aliases are resolved once the whole file has been read.
+func (p *Parser) parseAlias() error {
+	line := p.currentToken.Line
+	words := p.readLineWords()
+	if len(words) != 2 {
+		return fmt.Errorf("line %d: expected $Alias <alias> <name>, got %d words", line, len(words))
+	}
+	p.aliases = append(p.aliases, alias{name: words[0], target: words[1], line: line})
 	return nil
 }
 
+// resolveAliases records the aliases read by parseAlias in the module and
+// its objects, reporting those naming nothing the module declares
+func (p *Parser) resolveAliases(module *Module) {
+	for _, a := range p.aliases {
+		if strings.HasPrefix(a.name, ".") {
+			objectName, methodName, ok := strings.Cut(a.target, ".")
+			object := module.FindObject(objectName)
+			if !ok || object == nil || object.FindMethod(methodName) == nil {
+				p.addError(fmt.Sprintf("line %d: $Alias %s refers to unknown method %s", a.line, a.name, a.target))
+				continue
+			}
+			if object.Aliases == nil {
+				object.Aliases = make(map[string]string)
+			}
+			object.Aliases[strings.TrimPrefix(a.name, ".")] = methodName
+			continue
+		}
+
+		if module.FindFunction(a.target) == nil && module.FindObject(a.target) == nil {
+			p.addError(fmt.Sprintf("line %d: $Alias %s refers to unknown function or object %s", a.line, a.name, a.target))
+			continue
+		}
+		if module.Aliases == nil {
+			module.Aliases = make(map[string]string)
+		}
+		module.Aliases[a.name] = a.target
+	}
+}
+
+// readDirectiveWord reads the single word following a directive such as
+// $Prefix, reporting a missing word
+func (p *Parser) readDirectiveWord(directive string) string {
+	line := p.currentToken.Line
+	words := p.readLineWords()
+	if len(words) != 1 {
+		p.addError(fmt.Sprintf("line %d: expected one word after %s, got %d", line, directive, len(words)))
+		return ""
+	}
+	return words[0]
+}
+
+// readLineWords reads the rest of the line of the current directive token
+// as whitespace-separated words, joining adjacent tokens such as obj, . and
+// method back together
+func (p *Parser) readLineWords() []string {
+	line := p.currentToken.Line
+	p.nextToken()
+
+	var words []string
+	end := -1
+	for p.currentToken.Type != EOF && p.currentToken.Line == line {
+		token := p.currentToken
+		if token.Type != COMMENT {
+			if token.Column == end && len(words) > 0 {
+				words[len(words)-1] += token.Literal
+			} else {
+				words = append(words, token.Literal)
+			}
+			end = token.Column + len(token.Literal)
+		}
+		p.nextToken()
+	}
+	return words
+}
+
 // parseDescription parses a DESCRIPTION section
 func (p *Parser) parseDescription() (string, error) {
 	p.nextToken() // consume DESCRIPTION
@@ -308,8 +447,7 @@ func (p *Parser) parseDescription() (string, error) {
 		token := p.currentToken
 
 		// Stop if we hit a directive
-		if token.Type == MODULE || token.Type == FUNCTION || token.Type == OBJECT ||
-			token.Type == METHOD || token.Type == EVENT || token.Type == ABI {
+		if isTopLevelDirective(token.Type) || token.Type == METHOD || token.Type == RESTRICT {
 			break
 		}
 
@@ -449,7 +587,7 @@ func (p *Parser) parseParameterTokens() (Parameter, error) {
 		p.nextToken()
 		if p.currentToken.Type == STRING || p.currentToken.Type == IDENT || p.currentToken.Type == NUMBER || p.currentToken.Type == BOOL_LIT {
 			param.DefaultValue = p.currentToken.Literal
-			param.Optional = true
+			param.HasDefault = true
 			p.nextToken()
 		} else {
 			return param, fmt.Errorf("expected default value after '=', got %s", p.currentToken.Type)
@@ -537,7 +675,7 @@ func (p *Parser) parseParameter(paramStr string) (Parameter, error) {
 	parts := strings.SplitN(paramStr, "=", 2)
 	if len(parts) == 2 {
 		param.DefaultValue = strings.TrimSpace(strings.Trim(parts[1], `"`))
-		param.Optional = true
+		param.HasDefault = true
 		paramStr = strings.TrimSpace(parts[0])
 	}
 
@@ -632,8 +770,7 @@ func (p *Parser) readUntilNewline() string {
 		token := p.currentToken
 
 		// Check if we've hit a new directive (which starts a new logical line)
-		if token.Type == MODULE || token.Type == FUNCTION || token.Type == OBJECT ||
-			token.Type == METHOD || token.Type == EVENT || token.Type == ABI || token.Type == RESTRICT {
+		if isTopLevelDirective(token.Type) || token.Type == METHOD || token.Type == RESTRICT {
 			break
 		}
 
@@ -655,6 +792,47 @@ func (p *Parser) readUntilNewline() string {
 	return line.String()
 }
 
+// readRestrictions reads the contexts listed on the same line as the current
+// $Restrict token, such as "client backend" or "vcl_recv vcl_deliver".
+// Unknown contexts are reported and left out, as are repeated ones.
+func (p *Parser) readRestrictions() []Restriction {
+	line := p.currentToken.Line
+	p.nextToken()
+
+	var restrictions []Restriction
+	for p.currentToken.Type != EOF && p.currentToken.Line == line {
+		if p.currentToken.Type != COMMENT {
+			r, err := ParseRestriction(p.currentToken.Literal)
+			if err != nil {
+				p.addError(err.Error())
+			} else if !containsRestriction(restrictions, r) {
+				restrictions = append(restrictions, r)
+			}
+		}
+		p.nextToken()
+	}
+	return restrictions
+}
+
+func containsRestriction(restrictions []Restriction, r Restriction) bool {
+	for _, existing := range restrictions {
+		if existing == r {
+			return true
+		}
+	}
+	return false
+}
+
+// isTopLevelDirective reports whether t is a directive that ends the
+// description of a preceding function, object or method
+func isTopLevelDirective(t TokenType) bool {
+	switch t {
+	case MODULE, FUNCTION, OBJECT, EVENT, ABI, ALIAS, PREFIX, SYNOPSIS:
+		return true
+	}
+	return false
+}
+
 // addError adds an error to the error list
 func (p *Parser) addError(msg string) {
 	p.errors = append(p.errors, msg)
@@ -1,6 +1,7 @@
 package vcc
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -146,6 +147,212 @@ Description
 	}
 }
 
+func TestParseRestrictions(t *testing.T) {
+	vccContent := `$Module example 3 "Example"
+$ABI strict
+
+$Function BOOL is_h2()
+$Restrict client backend
+
+Returns true for HTTP/2 sessions.
+
+$Object store()
+
+$Method VOID .set_key(STRING key)
+$Restrict vcl_init
+
+Sets the key.`
+
+	module, err := NewParser(strings.NewReader(vccContent)).Parse()
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	if got := fmt.Sprint(module.Functions[0].Restrictions); got != "[client backend]" {
+		t.Errorf("function restrictions = %s, want [client backend]", got)
+	}
+	if got := fmt.Sprint(module.Objects[0].Methods[0].Restrictions); got != "[vcl_init]" {
+		t.Errorf("method restrictions = %s, want [vcl_init]", got)
+	}
+	if !strings.Contains(module.Functions[0].Description, "sessions") {
+		t.Errorf("description = %q, want it to keep the text after $Restrict", module.Functions[0].Description)
+	}
+}
+
+func TestParseInvalidRestriction(t *testing.T) {
+	vccContent := `$Module example 3 "Example"
+
+$Function VOID f()
+$Restrict client client recv`
+
+	module, err := NewParser(strings.NewReader(vccContent)).Parse()
+	if err == nil || !strings.Contains(err.Error(), `unknown $Restrict context "recv"`) {
+		t.Errorf("Expected an unknown context error, got %v", err)
+	}
+	if got := fmt.Sprint(module.Functions[0].Restrictions); got != "[client]" {
+		t.Errorf("restrictions = %s, want [client]", got)
+	}
+}
+
+func TestRestrictionAllows(t *testing.T) {
+	tests := []struct {
+		restriction Restriction
+		sub         string
+		group       Restriction
+		expected    bool
+	}{
+		{RestrictClient, "vcl_recv", RestrictClient, true},
+		{RestrictClient, "vcl_backend_fetch", RestrictBackend, false},
+		{RestrictHousekeeping, "vcl_init", RestrictHousekeeping, true},
+		{"vcl_miss", "vcl_miss", RestrictClient, true},
+		{"vcl_miss", "vcl_hit", RestrictClient, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.restriction.Allows(tt.sub, tt.group); got != tt.expected {
+			t.Errorf("%s.Allows(%s, %s) = %v, want %v", tt.restriction, tt.sub, tt.group, got, tt.expected)
+		}
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	vccContent := `$Module example 3 "Example"
+$ABI vrt
+$Prefix xmpl
+$Synopsis manual
+
+$Event event_function
+
+$Function STRING hello(STRING name)
+
+Say hello.
+
+$Alias greet hello
+
+$Object counter()
+$Method INT .get()
+$Alias .value counter.get`
+
+	module, err := NewParser(strings.NewReader(vccContent)).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if module.ABI != "vrt" || module.Prefix != "xmpl" || module.Synopsis != "manual" {
+		t.Errorf("ABI, Prefix, Synopsis = %s, %s, %s", module.ABI, module.Prefix, module.Synopsis)
+	}
+	if desc := module.Functions[0].Description; strings.Contains(desc, "Alias") {
+		t.Errorf("description of hello includes the $Alias line: %q", desc)
+	}
+	if f := module.FindFunction("greet"); f == nil || f.Name != "hello" {
+		t.Errorf("FindFunction(greet) = %v, want hello", f)
+	}
+	if m := module.FindObject("counter").FindMethod("value"); m == nil || m.Name != "get" {
+		t.Errorf("FindMethod(value) = %v, want get", m)
+	}
+}
+
+func TestParseDirectiveErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "unknown ABI",
+			content:  "$ABI loose",
+			expected: "$ABI must be strict or vrt, got loose",
+		},
+		{
+			name:     "unknown synopsis",
+			content:  "$Synopsis short",
+			expected: "$Synopsis must be auto or manual, got short",
+		},
+		{
+			name:     "second event",
+			content:  "$Event first\n$Event second",
+			expected: "only one $Event is allowed per module, second follows first",
+		},
+		{
+			name:     "alias of unknown function",
+			content:  "$Alias greet hello",
+			expected: "$Alias greet refers to unknown function or object hello",
+		},
+		{
+			name:     "alias of unknown method",
+			content:  "$Object counter()\n$Alias .value counter.get",
+			expected: "$Alias .value refers to unknown method counter.get",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "$Module example 3 \"Example\"\n" + tt.content
+			_, err := NewParser(strings.NewReader(content)).Parse()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDocs(t *testing.T) {
+	vccContent := "$Module example 3 \"Example\"\n" +
+		"\n" +
+		"$Function STRING toupper(STRING s)\n" +
+		"$Restrict client\n" +
+		"\n" +
+		"Converts *s* to upper case.\n" +
+		"\n" +
+		"Example::\n" +
+		"\n" +
+		"\tset req.http.x = example.toupper(req.http.x);\n" +
+		"\n" +
+		"$Object counter()\n" +
+		"\n" +
+		"Description\n" +
+		"\tCounts things.\n" +
+		"Example\n" +
+		"\tnew c = example.counter();\n" +
+		"\n" +
+		"$Method INT .get()\n" +
+		"\n" +
+		"Returns the count.\n"
+
+	module, err := NewDocsParser(strings.NewReader(vccContent)).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	function := module.Functions[0]
+	wantDoc := "Converts *s* to upper case.\n\nExample::\n\n\tset req.http.x = example.toupper(req.http.x);"
+	if function.Doc != wantDoc {
+		t.Errorf("function Doc = %q, want %q", function.Doc, wantDoc)
+	}
+	if len(function.Examples) != 1 || function.Examples[0] != "set req.http.x = example.toupper(req.http.x);" {
+		t.Errorf("function Examples = %q", function.Examples)
+	}
+
+	object := module.Objects[0]
+	if object.Doc != "Description\n\tCounts things.\nExample\n\tnew c = example.counter();" {
+		t.Errorf("object Doc = %q", object.Doc)
+	}
+	if len(object.Examples) != 1 || object.Examples[0] != "new c = example.counter();" {
+		t.Errorf("object Examples = %q", object.Examples)
+	}
+	if doc := object.Methods[0].Doc; doc != "Returns the count." {
+		t.Errorf("method Doc = %q, want %q", doc, "Returns the count.")
+	}
+
+	// The default parser leaves Doc empty
+	module, err = NewParser(strings.NewReader(vccContent)).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if module.Functions[0].Doc != "" || len(module.Functions[0].Examples) != 0 {
+		t.Errorf("NewParser filled in docs: %q", module.Functions[0].Doc)
+	}
+}
+
 func TestParseEnumParameters(t *testing.T) {
 	vccContent := `$Module blob 3 "Blob module"
 
@@ -247,6 +454,33 @@ func TestFunctionValidation(t *testing.T) {
 	}
 }
 
+func TestFunctionValidationPriv(t *testing.T) {
+	function := Function{
+		Name:       "fileread",
+		ReturnType: TypeString,
+		Parameters: []Parameter{
+			{Type: TypePrivCall},
+			{Name: "path", Type: TypeString},
+			{Name: "task", Type: TypePrivTask},
+		},
+	}
+
+	if err := function.ValidateCall([]VCCType{TypeString}); err != nil {
+		t.Errorf("PRIV parameters should not be counted: %v", err)
+	}
+	if err := function.ValidateCall([]VCCType{TypeString, TypeString}); err == nil || !strings.Contains(err.Error(), "at most 1 arguments") {
+		t.Errorf("Expected an error for a second argument, got %v", err)
+	}
+	if got := VCLParameters(function.Parameters); len(got) != 1 || got[0].Name != "path" {
+		t.Errorf("VCLParameters() = %+v", got)
+	}
+
+	object := Object{Name: "obj", Constructor: []Parameter{{Type: TypePrivVCL}, {Name: "name", Type: TypeString}}}
+	if err := object.ValidateConstruction([]VCCType{TypeString}); err != nil {
+		t.Errorf("PRIV constructor parameters should not be counted: %v", err)
+	}
+}
+
 func TestModuleFindFunctions(t *testing.T) {
 	module := Module{
 		Name: "test",
@@ -300,6 +534,10 @@ func TestParseVCCType(t *testing.T) {
 		{"REAL", TypeReal, false},
 		{"BOOL", TypeBool, false},
 		{"STRING_LIST", TypeStringList, false},
+		{"STRING_STABLE", TypeStringStable, false},
+		{"BODY", TypeBody, false},
+		{"REGEX", TypeRegex, false},
+		{"SUB", TypeSubroutine, false},
 		{"ENUM {A, B, C}", TypeEnum, true},
 	}
 
@@ -378,3 +616,25 @@ $Function REAL calculate(DURATION window = 2h)`
 		t.Errorf("Expected default value '2h', got '%s'", windowParam.DefaultValue)
 	}
 }
+
+func TestIsCompatibleTypeModern(t *testing.T) {
+	tests := []struct {
+		actual, expected VCCType
+		compatible       bool
+	}{
+		{TypeString, TypeBody, true},
+		{TypeBlob, TypeBody, true},
+		{TypeStrands, TypeBody, true},
+		{TypeInt, TypeBody, false},
+		{TypeString, TypeStringStable, true},
+		{TypeStringStable, TypeString, false},
+		{TypeRegex, TypeRegex, true},
+		{TypeString, TypeRegex, false},
+	}
+
+	for _, test := range tests {
+		if got := IsCompatibleType(test.actual, test.expected); got != test.compatible {
+			t.Errorf("IsCompatibleType(%s, %s) = %v, want %v", test.actual, test.expected, got, test.compatible)
+		}
+	}
+}
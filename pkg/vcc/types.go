@@ -34,6 +34,15 @@ const (
 	TypeStevedore  VCCType = "STEVEDORE"
 	TypePrivTop    VCCType = "PRIV_TOP"
 	TypeBereq      VCCType = "BEREQ"
+	// TypeBody is the body of synthetic() and similar functions, built
+	// from strings or a BLOB
+	TypeBody VCCType = "BODY"
+	// TypeStringStable is a STRING that stays valid for the whole task,
+	// which VMODs may keep without copying
+	TypeStringStable VCCType = "STRING_STABLE"
+	// TypeRegex is a regular expression compiled by VCC, written as a
+	// string literal
+	TypeRegex VCCType = "REGEX"
 )
 
 // IsCompatibleType checks if two VCC types are compatible
@@ -67,6 +76,16 @@ func IsCompatibleType(actual, expected VCCType) bool {
 		return true
 	}
 
+	// STRING_STABLE can accept STRING
+	if expected == TypeStringStable && actual == TypeString {
+		return true
+	}
+
+	// BODY can accept strings and BLOB
+	if expected == TypeBody && (actual == TypeString || actual == TypeStringList || actual == TypeStrands || actual == TypeBlob) {
+		return true
+	}
+
 	return false
 }
 
@@ -81,8 +100,18 @@ type Parameter struct {
 	Name         string
 	Type         VCCType
 	Enum         *Enum  // Non-nil for ENUM types
-	DefaultValue string // Optional default value
-	Optional     bool   // Whether parameter is optional
+	DefaultValue string // Value passed when the argument is left out
+	HasDefault   bool   // Whether DefaultValue applies; it may be ""
+	// Optional is set for parameters in [brackets]. The C function is
+	// passed a valid_<name> flag telling whether the argument was given,
+	// rather than a default value.
+	Optional bool
+}
+
+// Required reports whether every call must give the argument: it is
+// neither in brackets nor has a default value
+func (p Parameter) Required() bool {
+	return !p.Optional && !p.HasDefault
 }
 
 // Function represents a VCC function definition
@@ -91,8 +120,9 @@ type Function struct {
 	ReturnType   VCCType
 	Parameters   []Parameter
 	Description  string
-	Examples     []string
-	Restrictions []string // VCL contexts where function can be used
+	Doc          string        // RST documentation, filled in by a docs parser
+	Examples     []string      // Example blocks of Doc
+	Restrictions []Restriction // VCL contexts where function can be used
 }
 
 // Method represents a VCC object method
@@ -101,8 +131,45 @@ type Method struct {
 	ReturnType   VCCType
 	Parameters   []Parameter
 	Description  string
-	Examples     []string
-	Restrictions []string
+	Doc          string        // RST documentation, filled in by a docs parser
+	Examples     []string      // Example blocks of Doc
+	Restrictions []Restriction // VCL contexts where method can be used
+}
+
+// Restriction is a context listed by $Restrict: a built-in subroutine such as
+// vcl_recv, or one of the groups of subroutines client, backend and
+// housekeeping
+type Restriction string
+
+// The $Restrict groups, matching the VCL_MET_TASK_* masks of VCC
+const (
+	RestrictClient       Restriction = "client"       // vcl_recv through vcl_synth
+	RestrictBackend      Restriction = "backend"      // vcl_backend_fetch, vcl_backend_response and vcl_backend_error
+	RestrictHousekeeping Restriction = "housekeeping" // vcl_init and vcl_fini
+)
+
+// ParseRestriction parses one context of a $Restrict line
+func ParseRestriction(s string) (Restriction, error) {
+	r := Restriction(s)
+	if r.IsGroup() || (strings.HasPrefix(s, "vcl_") && len(s) > len("vcl_")) {
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown $Restrict context %q, expected a vcl_* subroutine, client, backend or housekeeping", s)
+}
+
+// IsGroup reports whether r names a group of subroutines rather than a
+// single one
+func (r Restriction) IsGroup() bool {
+	return r == RestrictClient || r == RestrictBackend || r == RestrictHousekeeping
+}
+
+// Allows reports whether r allows calls from the built-in subroutine sub,
+// which belongs to group
+func (r Restriction) Allows(sub string, group Restriction) bool {
+	if r.IsGroup() {
+		return r == group
+	}
+	return string(r) == sub
 }
 
 // Object represents a VCC object definition
@@ -111,7 +178,9 @@ type Object struct {
 	Constructor []Parameter // Parameters for object instantiation
 	Methods     []Method
 	Description string
-	Examples    []string
+	Doc         string            // RST documentation, filled in by a docs parser
+	Examples    []string          // Example blocks of Doc
+	Aliases     map[string]string // Method aliases from $Alias, mapped to method names
 }
 
 // Event represents a VCC event handler
@@ -128,7 +197,10 @@ type Module struct {
 	Functions   []Function
 	Objects     []Object
 	Events      []Event
-	ABI         string // ABI specification
+	ABI         string            // ABI specification, strict or vrt
+	Prefix      string            // C symbol prefix from $Prefix
+	Synopsis    string            // auto or manual, from $Synopsis
+	Aliases     map[string]string // Function and object aliases from $Alias, mapped to their names
 }
 
 // String returns a string representation of the module
@@ -137,7 +209,7 @@ func (m *Module) String() string {
 		m.Name, m.Version, len(m.Functions), len(m.Objects))
 }
 
-// FindFunction finds a function by name
+// FindFunction finds a function by name or $Alias
 func (m *Module) FindFunction(name string) *Function {
 	//nolint:nilaway // receiver m is validated by caller
 	for i := range m.Functions {
@@ -145,10 +217,13 @@ func (m *Module) FindFunction(name string) *Function {
 			return &m.Functions[i]
 		}
 	}
+	if target, ok := m.Aliases[name]; ok && target != name {
+		return m.FindFunction(target)
+	}
 	return nil
 }
 
-// FindObject finds an object by name
+// FindObject finds an object by name or $Alias
 func (m *Module) FindObject(name string) *Object {
 	//nolint:nilaway // receiver m is validated by caller
 	for i := range m.Objects {
@@ -156,130 +231,98 @@ func (m *Module) FindObject(name string) *Object {
 			return &m.Objects[i]
 		}
 	}
+	if target, ok := m.Aliases[name]; ok && target != name {
+		return m.FindObject(target)
+	}
 	return nil
 }
 
-// FindMethod finds a method on an object by name
+// FindMethod finds a method on an object by name or $Alias
 func (o *Object) FindMethod(name string) *Method {
 	for i := range o.Methods {
 		if o.Methods[i].Name == name {
 			return &o.Methods[i]
 		}
 	}
+	if target, ok := o.Aliases[name]; ok && target != name {
+		return o.FindMethod(target)
+	}
 	return nil
 }
 
-// ValidateCall validates a function call against the function signature
-func (f *Function) ValidateCall(args []VCCType) error {
-	// Check if we have the required number of arguments
-	requiredParams := 0
-	for _, param := range f.Parameters {
-		if !param.Optional && param.DefaultValue == "" {
-			requiredParams++
-		}
-	}
-
-	if len(args) < requiredParams {
-		return fmt.Errorf("function %s requires at least %d arguments, got %d",
-			f.Name, requiredParams, len(args))
-	}
-
-	if len(args) > len(f.Parameters) {
-		return fmt.Errorf("function %s accepts at most %d arguments, got %d",
-			f.Name, len(f.Parameters), len(args))
-	}
+// IsPriv reports whether the parameter is a PRIV_* handle such as
+// PRIV_CALL or PRIV_TASK, which varnishd supplies and VCL callers do not
+// write
+func (p Parameter) IsPriv() bool {
+	return strings.HasPrefix(string(p.Type), "PRIV_")
+}
 
-	// Validate argument types
-	for i, arg := range args {
-		expected := f.Parameters[i].Type
-		if !f.isCompatibleType(arg, expected) {
-			return fmt.Errorf("function %s argument %d: expected %s, got %s",
-				f.Name, i+1, expected, arg)
+// VCLParameters returns the parameters written in VCL, leaving out the
+// PRIV_* ones
+func VCLParameters(params []Parameter) []Parameter {
+	result := make([]Parameter, 0, len(params))
+	for _, param := range params {
+		if !param.IsPriv() {
+			result = append(result, param)
 		}
 	}
+	return result
+}
 
-	return nil
+// ValidateCall validates a function call against the function signature.
+// args are the types of the arguments written in VCL, without PRIV_*
+// parameters.
+func (f *Function) ValidateCall(args []VCCType) error {
+	return validateArguments("function "+f.Name, f.Parameters, args)
 }
 
-// ValidateCall validates a method call against the method signature
+// ValidateCall validates a method call against the method signature, like
+// Function.ValidateCall
 func (m *Method) ValidateCall(args []VCCType) error {
-	// Similar validation logic as Function.ValidateCall
-	requiredParams := 0
-	for _, param := range m.Parameters {
-		if !param.Optional && param.DefaultValue == "" {
-			requiredParams++
-		}
-	}
-
-	if len(args) < requiredParams {
-		return fmt.Errorf("method %s requires at least %d arguments, got %d",
-			m.Name, requiredParams, len(args))
-	}
-
-	if len(args) > len(m.Parameters) {
-		return fmt.Errorf("method %s accepts at most %d arguments, got %d",
-			m.Name, len(m.Parameters), len(args))
-	}
-
-	// Validate argument types
-	for i, arg := range args {
-		expected := m.Parameters[i].Type
-		if !m.isCompatibleType(arg, expected) {
-			return fmt.Errorf("method %s argument %d: expected %s, got %s",
-				m.Name, i+1, expected, arg)
-		}
-	}
-
-	return nil
+	return validateArguments("method "+m.Name, m.Parameters, args)
 }
 
-// ValidateConstruction validates object construction against constructor parameters
+// ValidateConstruction validates object construction against constructor
+// parameters, like Function.ValidateCall
 func (o *Object) ValidateConstruction(args []VCCType) error {
+	return validateArguments("object "+o.Name+" constructor", o.Constructor, args)
+}
+
+// validateArguments checks the number and types of the arguments of a call
+// to what
+func validateArguments(what string, params []Parameter, args []VCCType) error {
+	params = VCLParameters(params)
+
 	// Check if we have the required number of arguments
 	requiredParams := 0
-	for _, param := range o.Constructor {
-		if !param.Optional && param.DefaultValue == "" {
+	for _, param := range params {
+		if param.Required() {
 			requiredParams++
 		}
 	}
 
 	if len(args) < requiredParams {
-		return fmt.Errorf("object %s constructor requires at least %d arguments, got %d",
-			o.Name, requiredParams, len(args))
+		return fmt.Errorf("%s requires at least %d arguments, got %d",
+			what, requiredParams, len(args))
 	}
 
-	if len(args) > len(o.Constructor) {
-		return fmt.Errorf("object %s constructor accepts at most %d arguments, got %d",
-			o.Name, len(o.Constructor), len(args))
+	if len(args) > len(params) {
+		return fmt.Errorf("%s accepts at most %d arguments, got %d",
+			what, len(params), len(args))
 	}
 
 	// Validate argument types
 	for i, arg := range args {
-		expected := o.Constructor[i].Type
-		if !o.isCompatibleType(arg, expected) {
-			return fmt.Errorf("object %s constructor argument %d: expected %s, got %s",
-				o.Name, i+1, expected, arg)
+		expected := params[i].Type
+		if !IsCompatibleType(arg, expected) {
+			return fmt.Errorf("%s argument %d: expected %s, got %s",
+				what, i+1, expected, arg)
 		}
 	}
 
 	return nil
 }
 
-// isCompatibleType checks if two types are compatible
-func (f *Function) isCompatibleType(actual, expected VCCType) bool {
-	return IsCompatibleType(actual, expected)
-}
-
-// isCompatibleType checks if two types are compatible for methods
-func (m *Method) isCompatibleType(actual, expected VCCType) bool {
-	return IsCompatibleType(actual, expected)
-}
-
-// isCompatibleType checks if two types are compatible for object constructors
-func (o *Object) isCompatibleType(actual, expected VCCType) bool {
-	return IsCompatibleType(actual, expected)
-}
-
 // ParseVCCType parses a VCC type string, handling complex types like ENUM
 func ParseVCCType(typeStr string) (VCCType, *Enum, error) {
 	typeStr = strings.TrimSpace(typeStr)
@@ -339,6 +382,12 @@ func ParseVCCType(typeStr string) (VCCType, *Enum, error) {
 		return TypePrivTop, nil, nil
 	case "BEREQ":
 		return TypeBereq, nil, nil
+	case "BODY":
+		return TypeBody, nil, nil
+	case "STRING_STABLE":
+		return TypeStringStable, nil, nil
+	case "REGEX":
+		return TypeRegex, nil, nil
 	default:
 		return VCCType(typeStr), nil, fmt.Errorf("unknown VCC type: %s", typeStr)
 	}
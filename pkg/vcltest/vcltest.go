@@ -0,0 +1,243 @@
+// Package vcltest unit-tests VCL programs with the simulator in pkg/sim.
+// A test case describes a request, runs one subroutine with it and checks
+// the return action and the variables the subroutine left behind. Cases
+// can be written in Go or loaded from YAML files like this one:
+//
+//	vcl: default.vcl
+//	tests:
+//	  - name: static files are cached without cookies
+//	    request:
+//	      url: /static/app.css
+//	      headers:
+//	        Host: example.com
+//	        Cookie: session=1
+//	    expect:
+//	      action: hash
+//	      unset: [req.http.Cookie]
+//	  - name: errors are cached briefly
+//	    sub: vcl_backend_response
+//	    vars:
+//	      beresp.status: 503
+//	    expect:
+//	      vars:
+//	        beresp.ttl: 10s
+package vcltest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/sim"
+)
+
+// Suite is a set of test cases for one VCL file
+type Suite struct {
+	// VCL is the file under test, relative to the suite file
+	VCL   string `yaml:"vcl"`
+	Tests []Case `yaml:"tests"`
+}
+
+// Case is a single test
+type Case struct {
+	Name string `yaml:"name"`
+	// Sub is the subroutine to run, vcl_recv by default
+	Sub     string  `yaml:"sub"`
+	Request Request `yaml:"request"`
+	// Vars sets further variables before the run, such as beresp.status
+	Vars   map[string]string `yaml:"vars"`
+	Expect Expect            `yaml:"expect"`
+}
+
+// Request is the client request of a case. In vcl_backend_* subroutines it
+// is the backend request, bereq.
+type Request struct {
+	Method   string            `yaml:"method"` // GET by default
+	URL      string            `yaml:"url"`    // / by default
+	Proto    string            `yaml:"proto"`  // HTTP/1.1 by default
+	Headers  map[string]string `yaml:"headers"`
+	ClientIP string            `yaml:"client_ip"` // 127.0.0.1 by default
+}
+
+// Expect is what a case checks after the run. Empty fields are not checked.
+type Expect struct {
+	// Action is the return action, such as hash or synth
+	Action string `yaml:"action"`
+	// Status is the status code of synth(), or of error in VCL 3
+	Status int `yaml:"status"`
+	// Vars are variables and their expected values. Numbers and
+	// durations are compared by value.
+	Vars map[string]string `yaml:"vars"`
+	// Unset are variables that must not be set
+	Unset []string `yaml:"unset"`
+	// Hash is the expected list of hash_data() inputs
+	Hash []string `yaml:"hash"`
+	// Body is text the synthetic body must contain
+	Body string `yaml:"body"`
+}
+
+// Outcome is the result of running one case
+type Outcome struct {
+	Name string
+	// Failures lists the expectations that were not met
+	Failures []string
+	// Err is set when the case could not be run
+	Err error
+}
+
+// Passed reports whether the case ran and met all its expectations
+func (o Outcome) Passed() bool {
+	return o.Err == nil && len(o.Failures) == 0
+}
+
+// Load reads a suite from a YAML file. The VCL path is made relative to
+// the directory of the file.
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	suite, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if suite.VCL != "" && !filepath.IsAbs(suite.VCL) {
+		suite.VCL = filepath.Join(filepath.Dir(path), suite.VCL)
+	}
+	return suite, nil
+}
+
+// Parse decodes a suite from YAML, rejecting unknown keys so typos in
+// expectations do not silently pass
+func Parse(data []byte) (*Suite, error) {
+	var suite Suite
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&suite); err != nil {
+		return nil, err
+	}
+	for i, c := range suite.Tests {
+		if c.Name == "" {
+			return nil, fmt.Errorf("test %d has no name", i+1)
+		}
+	}
+	return &suite, nil
+}
+
+// Run runs the cases against program
+func Run(program *ast.Program, cases []Case) []Outcome {
+	simulator := sim.New(program)
+	outcomes := make([]Outcome, len(cases))
+	for i, c := range cases {
+		outcomes[i] = RunCase(simulator, c)
+	}
+	return outcomes
+}
+
+// RunCase runs a single case with simulator
+func RunCase(simulator *sim.Simulator, c Case) Outcome {
+	outcome := Outcome{Name: c.Name}
+	sub := c.Sub
+	if sub == "" {
+		sub = "vcl_recv"
+	}
+
+	state := newState(sub, c)
+	result, err := simulator.Run(sub, state)
+	if err != nil {
+		outcome.Err = err
+		return outcome
+	}
+
+	fail := func(format string, args ...interface{}) {
+		outcome.Failures = append(outcome.Failures, fmt.Sprintf(format, args...))
+	}
+	want := c.Expect
+	if want.Action != "" && result.Action != want.Action {
+		fail("return action is %s, want %s", describeAction(result.Action), want.Action)
+	}
+	if want.Status != 0 {
+		if len(result.Args) == 0 || !sim.Equal(result.Args[0], fmt.Sprint(want.Status)) {
+			fail("status is %s, want %d", describeStatus(result), want.Status)
+		}
+	}
+	for _, name := range sortedKeys(want.Vars) {
+		value, ok := state.Get(name)
+		switch {
+		case !ok:
+			fail("%s is unset, want %q", name, want.Vars[name])
+		case !sim.Equal(value, want.Vars[name]):
+			fail("%s is %q, want %q", name, sim.Format(value), want.Vars[name])
+		}
+	}
+	for _, name := range want.Unset {
+		if value, ok := state.Get(name); ok {
+			fail("%s is %q, want it unset", name, sim.Format(value))
+		}
+	}
+	if want.Hash != nil && strings.Join(result.Hash, "\x00") != strings.Join(want.Hash, "\x00") {
+		fail("hash_data() inputs are %q, want %q", result.Hash, want.Hash)
+	}
+	if want.Body != "" && !strings.Contains(result.Body, want.Body) {
+		fail("synthetic body %q does not contain %q", result.Body, want.Body)
+	}
+	return outcome
+}
+
+// newState sets up the variables of a case
+func newState(sub string, c Case) *sim.State {
+	prefix := "req."
+	if strings.HasPrefix(sub, "vcl_backend_") {
+		prefix = "bereq."
+	}
+	orDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+
+	state := sim.NewState()
+	state.SetString(prefix+"method", orDefault(c.Request.Method, "GET"))
+	state.SetString(prefix+"url", orDefault(c.Request.URL, "/"))
+	state.SetString(prefix+"proto", orDefault(c.Request.Proto, "HTTP/1.1"))
+	for name, value := range c.Request.Headers {
+		state.SetString(prefix+"http."+name, value)
+	}
+	state.SetString("client.ip", orDefault(c.Request.ClientIP, "127.0.0.1"))
+	state.SetString("req.restarts", "0")
+	state.SetString("req.esi_level", "0")
+	for _, name := range sortedKeys(c.Vars) {
+		state.SetString(name, c.Vars[name])
+	}
+	return state
+}
+
+func describeAction(action string) string {
+	if action == "" {
+		return "none"
+	}
+	return action
+}
+
+func describeStatus(result *sim.Result) string {
+	if len(result.Args) == 0 {
+		return "not set"
+	}
+	return sim.Format(result.Args[0])
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
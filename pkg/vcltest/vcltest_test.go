@@ -0,0 +1,127 @@
+package vcltest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perbu/vclparser/pkg/parser"
+)
+
+const testVCL = `vcl 4.1;
+
+sub vcl_recv {
+	if (req.url ~ "^/static/") {
+		unset req.http.Cookie;
+		return (hash);
+	}
+	if (req.url ~ "^/admin") {
+		return (synth(403, "Forbidden"));
+	}
+}
+
+sub vcl_backend_response {
+	if (beresp.status >= 500) {
+		set beresp.ttl = 10s;
+	}
+}
+`
+
+const testSuite = `vcl: default.vcl
+tests:
+  - name: static files are cached without cookies
+    request:
+      url: /static/app.css
+      headers:
+        Host: example.com
+        Cookie: session=1
+    expect:
+      action: hash
+      unset: [req.http.Cookie]
+  - name: admin is forbidden
+    request:
+      url: /admin/users
+    expect:
+      action: synth
+      status: 403
+  - name: cookies pass through the built-in VCL
+    request:
+      url: /account
+      headers:
+        Host: example.com
+        Cookie: session=1
+    expect:
+      action: pass
+  - name: errors are cached briefly
+    sub: vcl_backend_response
+    vars:
+      beresp.status: 503
+      beresp.ttl: 1h
+    expect:
+      vars:
+        beresp.ttl: 10s
+  - name: wrong expectations
+    request:
+      url: /admin
+    expect:
+      action: pass
+      status: 404
+      vars:
+        req.http.X-Missing: "1"
+`
+
+func TestSuite(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "default.vcl"), []byte(testVCL), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	suitePath := filepath.Join(dir, "default.yaml")
+	if err := os.WriteFile(suitePath, []byte(testSuite), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	suite, err := Load(suitePath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if suite.VCL != filepath.Join(dir, "default.vcl") {
+		t.Errorf("VCL = %q, want it next to the suite", suite.VCL)
+	}
+
+	program, err := parser.Parse(testVCL, suite.VCL)
+	if err != nil {
+		t.Fatalf("Failed to parse VCL: %v", err)
+	}
+	outcomes := Run(program, suite.Tests)
+	if len(outcomes) != 5 {
+		t.Fatalf("Expected 5 outcomes, got %d", len(outcomes))
+	}
+	for _, outcome := range outcomes[:4] {
+		if !outcome.Passed() {
+			t.Errorf("%s failed: %v %v", outcome.Name, outcome.Err, outcome.Failures)
+		}
+	}
+
+	failed := outcomes[4]
+	want := []string{
+		"return action is synth, want pass",
+		"status is 403, want 404",
+		"req.http.X-Missing is unset",
+	}
+	if len(failed.Failures) != len(want) {
+		t.Fatalf("Expected %d failures, got %v", len(want), failed.Failures)
+	}
+	for i, fragment := range want {
+		if !strings.Contains(failed.Failures[i], fragment) {
+			t.Errorf("failure %d = %q, want it to contain %q", i, failed.Failures[i], fragment)
+		}
+	}
+}
+
+func TestParseRejectsUnknownKeys(t *testing.T) {
+	_, err := Parse([]byte("tests:\n  - name: typo\n    expect:\n      acton: hash\n"))
+	if err == nil || !strings.Contains(err.Error(), "acton") {
+		t.Errorf("error = %v, want it to name the unknown key", err)
+	}
+}
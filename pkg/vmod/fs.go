@@ -0,0 +1,66 @@
+package vmod
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SetFS makes the registry read VCC files and shared objects from fsys
+// instead of the operating system: LoadVCCFile, LoadVCCPath,
+// LoadSharedObject and the vmod_path lookups of ResolveImport all go
+// through it. Paths are used in fs.FS form, slash-separated and with the
+// leading slash of absolute paths removed, so os.DirFS("/") behaves like
+// the default. A nil fsys restores the default.
+func (r *Registry) SetFS(fsys fs.FS) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.fsys = fsys
+}
+
+func (r *Registry) filesystem() fs.FS {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.fsys
+}
+
+// open opens a file of the registry's file system
+func (r *Registry) open(name string) (fs.File, error) {
+	if fsys := r.filesystem(); fsys != nil {
+		return fsys.Open(fsPath(name))
+	}
+	return os.Open(name)
+}
+
+func (r *Registry) readFile(name string) ([]byte, error) {
+	if fsys := r.filesystem(); fsys != nil {
+		return fs.ReadFile(fsys, fsPath(name))
+	}
+	return os.ReadFile(name)
+}
+
+func (r *Registry) stat(name string) (fs.FileInfo, error) {
+	if fsys := r.filesystem(); fsys != nil {
+		return fs.Stat(fsys, fsPath(name))
+	}
+	return os.Stat(name)
+}
+
+// vccFiles returns the .vcc files of a directory in sorted order
+func (r *Registry) vccFiles(dir string) ([]string, error) {
+	if fsys := r.filesystem(); fsys != nil {
+		return fs.Glob(fsys, path.Join(fsPath(dir), "*.vcc"))
+	}
+	return filepath.Glob(filepath.Join(dir, "*.vcc"))
+}
+
+// fsPath converts an operating system path to the form fs.FS expects
+func fsPath(name string) string {
+	name = path.Clean(filepath.ToSlash(name))
+	if name == "/" {
+		return "."
+	}
+	return strings.TrimPrefix(name, "/")
+}
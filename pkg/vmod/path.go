@@ -0,0 +1,115 @@
+package vmod
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/perbu/vclparser/internal/pathutil"
+)
+
+// SetVMODPath sets the directories searched, in order, for the VCC file of
+// an imported module, like varnishd's vmod_path. A module is looked up as
+// NAME.vcc or vmod_NAME.vcc, or under the name given with import ... from.
+func (r *Registry) SetVMODPath(dirs ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.vmodPath = append([]string(nil), dirs...)
+}
+
+// SetUnsafePath sets whether import ... from may name absolute paths or
+// paths containing "..", like varnishd's vcc_unsafe_path. It is allowed by
+// default.
+func (r *Registry) SetUnsafePath(allow bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.unsafePath = allow
+}
+
+// ResolveImport makes the module of an import statement available,
+// loading its VCC file from the vmod_path if it is not loaded yet. from is
+// the path of import name from "path", or empty; relative paths are
+// resolved against the vmod_path. For a path to a shared object such as
+// libvmod_foo.so, the VCC file is looked up next to it as foo.vcc or
+// vmod_foo.vcc. Without a VCC file, the module is read from the JSON
+// embedded in its shared object, libvmod_foo.so or the one named by from.
+func (r *Registry) ResolveImport(moduleName, from string) error {
+	r.mutex.RLock()
+	vmodPath, unsafePath := r.vmodPath, r.unsafePath
+	r.mutex.RUnlock()
+
+	if from != "" && !unsafePath && pathutil.IsUnsafe(from) {
+		return fmt.Errorf("import path %s is unsafe: absolute paths and .. are not allowed", from)
+	}
+
+	if from == "" {
+		if r.ModuleExists(moduleName) {
+			return nil
+		}
+		if file, ok := r.findFile(vmodPath, vccNames(moduleName)); ok {
+			if err := r.LoadVCCFile(file); err != nil {
+				return err
+			}
+		} else if file, ok := r.findFile(vmodPath, []string{"libvmod_" + moduleName + ".so"}); ok {
+			if err := r.LoadSharedObject(file); err != nil {
+				return err
+			}
+		}
+		return r.ValidateImport(moduleName)
+	}
+
+	dir, base := filepath.Split(from)
+	names := []string{base}
+	if filepath.Ext(base) != ".vcc" {
+		names = vccNames(moduleName)
+	}
+	dirs := []string{dir}
+	if !filepath.IsAbs(from) {
+		dirs = make([]string, len(vmodPath))
+		for i, searchDir := range vmodPath {
+			dirs[i] = filepath.Join(searchDir, dir)
+		}
+	}
+	file, ok := r.findFile(dirs, names)
+	load := r.loadVCCFile
+	if !ok {
+		// Modules already loaded, such as the embedded ones, are
+		// described well enough without their VCC file
+		if r.ModuleExists(moduleName) {
+			return nil
+		}
+		if filepath.Ext(base) != ".so" {
+			return fmt.Errorf("module %s: no VCC file for %s found", moduleName, from)
+		}
+		if file, ok = r.findFile(dirs, []string{base}); !ok {
+			return fmt.Errorf("module %s: no VCC file or shared object for %s found", moduleName, from)
+		}
+		load = r.loadSharedObject
+	}
+	module, err := load(file)
+	if err != nil {
+		return err
+	}
+	if module.Name != moduleName {
+		return fmt.Errorf("module %s: %s describes module %s instead", moduleName, file, module.Name)
+	}
+	return nil
+}
+
+// vccNames returns the file names a module's VCC file may have
+func vccNames(moduleName string) []string {
+	return []string{moduleName + ".vcc", "vmod_" + moduleName + ".vcc"}
+}
+
+// findFile returns the first of names that exists in dirs, trying every
+// name in a directory before the next directory
+func (r *Registry) findFile(dirs, names []string) (string, bool) {
+	for _, dir := range dirs {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if info, err := r.stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
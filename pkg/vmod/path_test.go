@@ -0,0 +1,122 @@
+package vmod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func writeVCC(t *testing.T, path, module string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "$Module " + module + " 3 \"Test\"\n$ABI strict\n\n$Function VOID f()\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeSharedObject writes a stand-in for a compiled VMOD, holding only the
+// JSON description vmodtool.py embeds
+func writeSharedObject(t *testing.T, path, module string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "\x7fELF\x00VMOD_JSON_SPEC\x02[[\"$VMOD\", \"1.0\", \"" + module +
+		"\"], [\"$FUNC\", \"f\", [[\"VOID\"], \"f\", \"\"]]]\x03\x00"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryResolveImport(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeVCC(t, filepath.Join(first, "one.vcc"), "one")
+	writeVCC(t, filepath.Join(second, "vmod_two.vcc"), "two")
+	writeVCC(t, filepath.Join(second, "sub", "vmod_three.vcc"), "three")
+	writeSharedObject(t, filepath.Join(second, "libvmod_five.so"), "five")
+	writeSharedObject(t, filepath.Join(second, "sub", "libvmod_six.so"), "six")
+
+	registry := NewEmptyRegistry()
+	registry.SetVMODPath(first, second)
+
+	tests := []struct {
+		module string
+		from   string
+		err    string
+	}{
+		{"one", "", ""},
+		{"two", "", ""},
+		{"three", "sub/libvmod_three.so", ""},
+		{"three", filepath.Join(second, "sub", "vmod_three.vcc"), ""},
+		{"four", "", "module four is not available"},
+		{"four", "libvmod_four.so", "no VCC file"},
+		{"five", "", ""},
+		{"six", "sub/libvmod_six.so", ""},
+		{"one", "vmod_two.vcc", "describes module two instead"},
+	}
+	for _, tt := range tests {
+		err := registry.ResolveImport(tt.module, tt.from)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("ResolveImport(%s, %q): %v", tt.module, tt.from, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("ResolveImport(%s, %q) = %v, want error containing %q", tt.module, tt.from, err, tt.err)
+		}
+	}
+}
+
+func TestRegistryResolveImportUnsafePath(t *testing.T) {
+	dir := t.TempDir()
+	writeVCC(t, filepath.Join(dir, "vmod_one.vcc"), "one")
+
+	registry := NewEmptyRegistry()
+	registry.SetVMODPath(filepath.Join(dir, "sub"))
+	if err := registry.ResolveImport("one", filepath.Join(dir, "libvmod_one.so")); err != nil {
+		t.Fatalf("Expected absolute paths to be allowed by default: %v", err)
+	}
+
+	registry = NewEmptyRegistry()
+	registry.SetVMODPath(filepath.Join(dir, "sub"))
+	registry.SetUnsafePath(false)
+	for _, from := range []string{filepath.Join(dir, "libvmod_one.so"), "../libvmod_one.so"} {
+		err := registry.ResolveImport("one", from)
+		if err == nil || !strings.Contains(err.Error(), "unsafe") {
+			t.Errorf("ResolveImport(one, %q) = %v, want an unsafe path error", from, err)
+		}
+	}
+}
+
+func TestRegistrySetFS(t *testing.T) {
+	vcc := func(module string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("$Module " + module + " 3 \"Test\"\n$ABI strict\n\n$Function VOID f()\n")}
+	}
+	fsys := fstest.MapFS{
+		"usr/lib/vmods/vmod_one.vcc": vcc("one"),
+		"vcc/two.vcc":                vcc("two"),
+		"vcc/three.vcc":              vcc("three"),
+	}
+
+	registry := NewEmptyRegistry()
+	registry.SetFS(fsys)
+	registry.SetVMODPath("/usr/lib/vmods")
+
+	if err := registry.ResolveImport("one", ""); err != nil {
+		t.Errorf("ResolveImport(one) failed: %v", err)
+	}
+	if err := registry.LoadVCCPath("vcc"); err != nil {
+		t.Fatalf("LoadVCCPath failed: %v", err)
+	}
+	for _, module := range []string{"one", "two", "three"} {
+		if !registry.ModuleExists(module) {
+			t.Errorf("Expected module %s to be loaded from the file system", module)
+		}
+	}
+	if err := registry.LoadVCCFile("vcc/missing.vcc"); err == nil {
+		t.Error("Expected an error for a file missing from the file system")
+	}
+}
@@ -0,0 +1,52 @@
+package vmod
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// profiles lists the embedded modules each Varnish distribution ships, so a
+// registry can hold just the VMODs a deployment has. Every embedded VCC file
+// comes from Varnish Enterprise 6.0 (see the vcclib manifest), so only that
+// distribution has a profile; for Varnish Cache, load the VCC files of the
+// deployed release with LoadVCCPath, or refresh vcclib from it with
+// cmd/vccsync.
+var profiles = map[string][]string{
+	// Varnish Enterprise 6, including the open source VMODs it bundles
+	"enterprise": {
+		"accept", "accounting", "aclplus", "activedns", "akamai", "asn", "blob", "brotli", "cookieplus",
+		"crypto", "curl", "deviceatlas", "deviceatlas3", "digest", "directors", "edgestash", "file", "format",
+		"geoip", "goto", "h2", "headerplus", "http", "image", "json", "jwt", "kv", "kvstore", "leastconn",
+		"memcached", "mmdb", "mse", "mse4", "nodes", "otel", "paywall", "prng", "probe_proxy", "proxy",
+		"purge", "ratelimit", "resolver", "rewrite", "rtstatus", "s3", "session", "slicer", "sqlite3",
+		"stale", "stat", "std", "str", "synthbackend", "tls", "udo", "unix", "uri", "urlplus", "utils",
+		"vha", "vtc", "xbody", "ykey",
+	},
+}
+
+// Profiles returns the names LoadProfile accepts
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProfile loads the embedded VCC files of the modules a Varnish
+// distribution ships, such as "enterprise". Combined with NewEmptyRegistry,
+// VCL importing a VMOD the distribution lacks fails to validate.
+func (r *Registry) LoadProfile(name string) error {
+	modules, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown VMOD profile %q, expected one of %s", name, strings.Join(Profiles(), ", "))
+	}
+	for _, module := range modules {
+		if err := r.loadEmbeddedVCC("vmod_" + module + ".vcc"); err != nil {
+			return fmt.Errorf("profile %s: %v", name, err)
+		}
+	}
+	return nil
+}
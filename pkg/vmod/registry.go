@@ -1,26 +1,106 @@
 package vmod
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
+	"strings"
 	"sync"
 
-	"github.com/perbu/vclparser"
 	"github.com/perbu/vclparser/pkg/vcc"
+	"github.com/perbu/vclparser/vcclib"
 )
 
 // Registry manages VMOD definitions loaded from VCC files
 type Registry struct {
 	modules map[string]*vcc.Module
 	mutex   sync.RWMutex
+
+	// vmodPath lists the directories searched for VCC files of imported
+	// modules that are not loaded, like varnishd's vmod_path
+	vmodPath []string
+	// unsafePath allows import from absolute paths and paths leaving the
+	// search directories, like vcc_unsafe_path
+	unsafePath bool
+
+	// fsys is where files are read from; nil means the operating system
+	fsys fs.FS
+
+	// sources maps module names to the file each was loaded from
+	sources        map[string]string
+	conflictPolicy ConflictPolicy
+	conflicts      []Conflict
+}
+
+// ConflictPolicy decides what happens when a module is loaded under the name
+// of one the registry already holds, such as two VCC files for vmod std
+type ConflictPolicy int
+
+const (
+	// ConflictReplace replaces the module already loaded, so VCC files
+	// loaded on top of the embedded ones override them
+	ConflictReplace ConflictPolicy = iota
+	// ConflictError rejects the module loaded later
+	ConflictError
+	// ConflictPreferHigher keeps the module with the higher $Module
+	// version, and the one loaded later when both are the same
+	ConflictPreferHigher
+	// ConflictPreferFirst keeps the module already loaded
+	ConflictPreferFirst
+)
+
+// ParseConflictPolicy parses replace, error, prefer-higher or prefer-first
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch s {
+	case "", "replace":
+		return ConflictReplace, nil
+	case "error":
+		return ConflictError, nil
+	case "prefer-higher":
+		return ConflictPreferHigher, nil
+	case "prefer-first":
+		return ConflictPreferFirst, nil
+	}
+	return ConflictReplace, fmt.Errorf("unknown conflict policy %q, expected replace, error, prefer-higher or prefer-first", s)
+}
+
+// String returns the name ParseConflictPolicy accepts
+func (p ConflictPolicy) String() string {
+	switch p {
+	case ConflictError:
+		return "error"
+	case ConflictPreferHigher:
+		return "prefer-higher"
+	case ConflictPreferFirst:
+		return "prefer-first"
+	}
+	return "replace"
+}
+
+// Conflict records a module loaded under the name of one already registered
+type Conflict struct {
+	Module          string
+	Existing        string // source of the module already registered
+	ExistingVersion int
+	Loaded          string // source of the module loaded after it
+	LoadedVersion   int
+	Replaced        bool // whether the loaded module replaced the existing one
+}
+
+// String describes the conflict and its outcome
+func (c Conflict) String() string {
+	kept := c.Existing
+	if c.Replaced {
+		kept = c.Loaded
+	}
+	return fmt.Sprintf("module %s v%d from %s conflicts with v%d from %s; using %s",
+		c.Module, c.LoadedVersion, c.Loaded, c.ExistingVersion, c.Existing, kept)
 }
 
 // NewRegistry creates a new VMOD registry and automatically loads embedded VCC files
 func NewRegistry() *Registry {
-	r := &Registry{
-		modules: make(map[string]*vcc.Module),
-	}
+	r := NewEmptyRegistry()
 	// Load embedded VCC files automatically
 	_ = r.LoadEmbeddedVCCs()
 	return r
@@ -29,15 +109,48 @@ func NewRegistry() *Registry {
 // NewEmptyRegistry creates a new empty VMOD registry for testing purposes
 func NewEmptyRegistry() *Registry {
 	return &Registry{
-		modules: make(map[string]*vcc.Module),
+		modules:    make(map[string]*vcc.Module),
+		sources:    make(map[string]string),
+		unsafePath: true,
 	}
 }
 
+// SetConflictPolicy sets what happens to modules loaded after one of the
+// same name. The default, ConflictReplace, lets the later module win.
+func (r *Registry) SetConflictPolicy(policy ConflictPolicy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.conflictPolicy = policy
+}
+
+// Conflicts returns the modules loaded under the name of one already
+// registered, in the order they were loaded
+func (r *Registry) Conflicts() []Conflict {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return append([]Conflict(nil), r.conflicts...)
+}
+
+// Source returns the file a module was loaded from, with an embedded: prefix
+// for the VCC files built into the library
+func (r *Registry) Source(name string) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	source, ok := r.sources[name]
+	return source, ok
+}
+
 // LoadVCCFile loads a single VCC file
 func (r *Registry) LoadVCCFile(filename string) error {
-	file, err := os.Open(filename)
+	_, err := r.loadVCCFile(filename)
+	return err
+}
+
+// loadVCCFile loads a single VCC file and returns the module it describes
+func (r *Registry) loadVCCFile(filename string) (*vcc.Module, error) {
+	file, err := r.open(filename)
 	if err != nil {
-		return fmt.Errorf("failed to open VCC file %s: %v", filename, err)
+		return nil, fmt.Errorf("failed to open VCC file %s: %v", filename, err)
 	}
 	defer func() {
 		_ = file.Close() // Ignore error in defer
@@ -46,24 +159,122 @@ func (r *Registry) LoadVCCFile(filename string) error {
 	return r.loadVCCFromReader(file, filename)
 }
 
+// LoadVCCPath loads a VCC file, or every .vcc file in a directory
+func (r *Registry) LoadVCCPath(path string) error {
+	return r.LoadVCCPathContext(context.Background(), path)
+}
+
+// LoadVCCPathContext is LoadVCCPath that stops once ctx is done and returns
+// ctx.Err(). The files loaded until then stay in the registry.
+func (r *Registry) LoadVCCPathContext(ctx context.Context, path string) error {
+	info, err := r.stat(path)
+	if err != nil {
+		return err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = r.vccFiles(path)
+		if err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.LoadVCCFile(file); err != nil {
+			return fmt.Errorf("loading %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
 // loadVCCFromReader loads a VCC from an io.Reader
-func (r *Registry) loadVCCFromReader(reader io.Reader, source string) error {
-	parser := vcc.NewParser(reader)
+func (r *Registry) loadVCCFromReader(reader io.Reader, source string) (*vcc.Module, error) {
+	parser := vcc.NewDocsParser(reader)
 	module, err := parser.Parse()
 	if err != nil {
-		return fmt.Errorf("failed to parse VCC from %s: %v", source, err)
+		return nil, fmt.Errorf("failed to parse VCC from %s: %v", source, err)
 	}
 
-	// Register the module
+	return module, r.register(module, source)
+}
+
+// LoadSharedObject loads a module from the JSON description vmodtool.py
+// embeds in a compiled VMOD, such as libvmod_std.so, so installed VMODs can
+// be used without their VCC files. Such modules have no documentation.
+func (r *Registry) LoadSharedObject(filename string) error {
+	_, err := r.loadSharedObject(filename)
+	return err
+}
+
+func (r *Registry) loadSharedObject(filename string) (*vcc.Module, error) {
+	data, err := r.readFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VMOD %s: %v", filename, err)
+	}
+	spec, err := vcc.ExtractJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("VMOD %s: %v", filename, err)
+	}
+	return r.loadVMODJSON(spec, filename)
+}
+
+// LoadVMODJSON loads a module from the JSON description of a VMOD, as found
+// in its shared object
+func (r *Registry) LoadVMODJSON(reader io.Reader, source string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read VMOD JSON from %s: %v", source, err)
+	}
+	_, err = r.loadVMODJSON(data, source)
+	return err
+}
+
+func (r *Registry) loadVMODJSON(data []byte, source string) (*vcc.Module, error) {
+	module, err := vcc.ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse VMOD JSON from %s: %v", source, err)
+	}
+	return module, r.register(module, source)
+}
+
+// register adds a module loaded from source, applying the conflict policy
+// when a module of the same name is already registered
+func (r *Registry) register(module *vcc.Module, source string) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if module.Name != "" {
-		r.modules[module.Name] = module
-	} else {
+	if module.Name == "" {
 		return fmt.Errorf("module in %s has no name", source)
 	}
 
+	if existing, ok := r.modules[module.Name]; ok {
+		conflict := Conflict{
+			Module:          module.Name,
+			Existing:        r.sources[module.Name],
+			ExistingVersion: existing.Version,
+			Loaded:          source,
+			LoadedVersion:   module.Version,
+		}
+		switch r.conflictPolicy {
+		case ConflictError:
+			return fmt.Errorf("module %s in %s is already loaded from %s", module.Name, source, conflict.Existing)
+		case ConflictPreferHigher:
+			conflict.Replaced = module.Version >= existing.Version
+		case ConflictPreferFirst:
+			conflict.Replaced = false
+		default:
+			conflict.Replaced = true
+		}
+		r.conflicts = append(r.conflicts, conflict)
+		if !conflict.Replaced {
+			return nil
+		}
+	}
+
+	r.modules[module.Name] = module
+	r.sources[module.Name] = source
 	return nil
 }
 
@@ -216,6 +427,8 @@ func (r *Registry) Clear() {
 	defer r.mutex.Unlock()
 
 	r.modules = make(map[string]*vcc.Module)
+	r.sources = make(map[string]string)
+	r.conflicts = nil
 }
 
 // ModuleExists checks if a module is registered
@@ -238,28 +451,51 @@ func (r *Registry) GetBuiltinModules() []string {
 	return available
 }
 
+// EmbeddedVersion returns the Varnish release the embedded VCC files of the
+// built-in VMODs were copied from, such as "6.0-enterprise" or "7.5.0", as
+// recorded in the vcclib manifest
+func (r *Registry) EmbeddedVersion() string {
+	manifest, err := vcclib.ReadManifest()
+	if err != nil {
+		return ""
+	}
+	return manifest.Varnish
+}
+
 // DefaultRegistry is a global registry instance
 var DefaultRegistry = NewRegistry()
 
 // LoadEmbeddedVCCs loads all embedded VCC files
 func (r *Registry) LoadEmbeddedVCCs() error {
-	vccFiles, err := vclparser.ListEmbeddedVCCFiles()
+	vccFiles, err := vcclib.List()
 	if err != nil {
 		return fmt.Errorf("failed to list embedded VCC files: %v", err)
 	}
 
 	for _, filename := range vccFiles {
-		reader, err := vclparser.OpenEmbeddedVCCFile(filename)
-		if err != nil {
-			return fmt.Errorf("failed to open embedded VCC file %s: %v", filename, err)
+		if err := r.loadEmbeddedVCC(filename); err != nil {
+			return err
 		}
+	}
 
-		if err := r.loadVCCFromReader(reader, fmt.Sprintf("embedded:%s", filename)); err != nil {
-			_ = reader.Close()
-			return fmt.Errorf("failed to load embedded VCC file %s: %v", filename, err)
-		}
-		_ = reader.Close()
+	return nil
+}
+
+// loadEmbeddedVCC loads one embedded VCC file, given with or without the
+// vcclib/ prefix
+func (r *Registry) loadEmbeddedVCC(filename string) error {
+	filename = strings.TrimPrefix(filename, "vcclib/")
+	reader, err := vcclib.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open embedded VCC file %s: %v", filename, err)
 	}
+	defer func() {
+		_ = reader.Close()
+	}()
 
+	filename = "vcclib/" + filename
+	if _, err := r.loadVCCFromReader(reader, fmt.Sprintf("embedded:%s", filename)); err != nil {
+		return fmt.Errorf("failed to load embedded VCC file %s: %v", filename, err)
+	}
 	return nil
 }
@@ -1,9 +1,13 @@
 package vmod
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/perbu/vclparser/pkg/metadata"
 	"github.com/perbu/vclparser/pkg/vcc"
 )
 
@@ -201,6 +205,127 @@ $Method BACKEND .backend()`
 	}
 }
 
+func TestRegistryAliases(t *testing.T) {
+	registry := NewEmptyRegistry()
+
+	vccContent := `$Module example 3 "Example"
+
+$Function STRING hello(STRING name)
+$Alias greet hello
+
+$Object counter()
+$Method INT .get()
+$Alias .value counter.get
+$Alias tally counter`
+
+	if _, err := registry.loadVCCFromReader(strings.NewReader(vccContent), "example.vcc"); err != nil {
+		t.Fatalf("Failed to load VCC: %v", err)
+	}
+
+	if err := registry.ValidateFunctionCall("example", "greet", []vcc.VCCType{vcc.TypeString}); err != nil {
+		t.Errorf("Call of aliased function should validate: %v", err)
+	}
+	if err := registry.ValidateObjectConstruction("example", "tally", []vcc.VCCType{}); err != nil {
+		t.Errorf("Construction of aliased object should validate: %v", err)
+	}
+	if err := registry.ValidateMethodCall("example", "counter", "value", []vcc.VCCType{}); err != nil {
+		t.Errorf("Call of aliased method should validate: %v", err)
+	}
+}
+
+func TestRegistryConflicts(t *testing.T) {
+	load := func(registry *Registry, source string, version int) error {
+		vccContent := fmt.Sprintf("$Module std %d \"Standard library\"\n$Function VOID f()\n", version)
+		_, err := registry.loadVCCFromReader(strings.NewReader(vccContent), source)
+		return err
+	}
+
+	tests := []struct {
+		policy   string
+		versions []int
+		source   string
+		err      string
+	}{
+		{"replace", []int{3, 2}, "std2.vcc", ""},
+		{"prefer-first", []int{2, 3}, "std1.vcc", ""},
+		{"prefer-higher", []int{3, 2}, "std1.vcc", ""},
+		{"prefer-higher", []int{2, 3}, "std2.vcc", ""},
+		{"error", []int{3, 3}, "std1.vcc", "module std in std2.vcc is already loaded from std1.vcc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v", tt.policy, tt.versions), func(t *testing.T) {
+			policy, err := ParseConflictPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			registry := NewEmptyRegistry()
+			registry.SetConflictPolicy(policy)
+
+			if err := load(registry, "std1.vcc", tt.versions[0]); err != nil {
+				t.Fatal(err)
+			}
+			err = load(registry, "std2.vcc", tt.versions[1])
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.err)
+			}
+
+			if source, _ := registry.Source("std"); source != tt.source {
+				t.Errorf("Source(std) = %s, want %s", source, tt.source)
+			}
+			if tt.err == "" && len(registry.Conflicts()) != 1 {
+				t.Errorf("Conflicts() = %v, want one conflict", registry.Conflicts())
+			}
+		})
+	}
+
+	if _, err := ParseConflictPolicy("newest"); err == nil {
+		t.Error("ParseConflictPolicy(newest) should fail")
+	}
+}
+
+func TestRegistryLoadProfile(t *testing.T) {
+	for _, name := range Profiles() {
+		t.Run(name, func(t *testing.T) {
+			registry := NewEmptyRegistry()
+			if err := registry.LoadProfile(name); err != nil {
+				t.Fatalf("LoadProfile(%s) error = %v", name, err)
+			}
+			if !registry.ModuleExists("std") {
+				t.Error("every profile includes std")
+			}
+		})
+	}
+
+	registry := NewEmptyRegistry()
+	if err := registry.LoadProfile("enterprise"); err != nil {
+		t.Fatal(err)
+	}
+	if registry.ModuleExists("debug") {
+		t.Error("enterprise should not include the debug VMOD")
+	}
+	if source, _ := registry.Source("std"); source != "embedded:vcclib/vmod_std.vcc" {
+		t.Errorf("Source(std) = %s", source)
+	}
+
+	if err := registry.LoadProfile("plus"); err == nil || !strings.Contains(err.Error(), "unknown VMOD profile") {
+		t.Errorf("LoadProfile(plus) error = %v", err)
+	}
+}
+
+func TestRegistryEmbeddedVersion(t *testing.T) {
+	version := NewRegistry().EmbeddedVersion()
+	if version == "" {
+		t.Fatal("EmbeddedVersion() is empty")
+	}
+	if _, err := metadata.ParseRelease(version); err != nil {
+		t.Errorf("EmbeddedVersion() = %q is not a Varnish release: %v", version, err)
+	}
+}
+
 func TestRegistryStats(t *testing.T) {
 	registry := NewEmptyRegistry()
 
@@ -266,3 +391,104 @@ $Event event1`
 		t.Errorf("Expected 1 event, got %d", testStats.EventCount)
 	}
 }
+
+func TestRegistryLoadVCCPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"one.vcc":   "$Module one 3 \"First\"\n$ABI strict\n\n$Function VOID f()\n",
+		"two.vcc":   "$Module two 3 \"Second\"\n$ABI strict\n\n$Function VOID g()\n",
+		"notes.txt": "not a VCC file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := NewEmptyRegistry()
+	if err := registry.LoadVCCPath(dir); err != nil {
+		t.Fatalf("Failed to load VCC directory: %v", err)
+	}
+	if len(registry.ListModules()) != 2 {
+		t.Errorf("Expected 2 modules from the directory, got %v", registry.ListModules())
+	}
+
+	registry = NewEmptyRegistry()
+	if err := registry.LoadVCCPath(filepath.Join(dir, "one.vcc")); err != nil {
+		t.Fatalf("Failed to load VCC file: %v", err)
+	}
+	if !registry.ModuleExists("one") || registry.ModuleExists("two") {
+		t.Errorf("Expected only module one, got %v", registry.ListModules())
+	}
+
+	if err := registry.LoadVCCPath(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+}
+
+func TestResolveCall(t *testing.T) {
+	registry := NewEmptyRegistry()
+	vccFile := filepath.Join(t.TempDir(), "codec.vcc")
+	err := os.WriteFile(vccFile, []byte(`$Module codec 3 "Codec"
+$ABI strict
+$Function STRING encode(PRIV_TASK, ENUM {BASE64, HEX} encoding = "HEX", STRING s, [INT n], BOOL upper = 0)`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.LoadVCCFile(vccFile); err != nil {
+		t.Fatal(err)
+	}
+
+	call, err := registry.ResolveCall("codec", "encode",
+		[]Argument{{Type: vcc.TypeEnum, Value: "BASE64"}},
+		map[string]Argument{"s": {Type: vcc.TypeString, Value: `"x"`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.Function.Name != "encode" || len(call.OrderedArgs) != 4 {
+		t.Fatalf("ResolveCall() = %+v", call)
+	}
+	want := []Argument{{Type: vcc.TypeEnum, Value: "BASE64"}, {Type: vcc.TypeString, Value: `"x"`}, {}, {Type: vcc.TypeBool, Value: "0"}}
+	for i := range want {
+		if call.OrderedArgs[i] != want[i] {
+			t.Errorf("OrderedArgs[%d] = %+v, want %+v", i, call.OrderedArgs[i], want[i])
+		}
+	}
+	if len(call.DefaultsApplied) != 1 || call.DefaultsApplied[0] != "upper" {
+		t.Errorf("DefaultsApplied = %v, want [upper]", call.DefaultsApplied)
+	}
+	if call.States[2] != vcc.ArgumentOmitted {
+		t.Errorf("States[2] = %v, want omitted", call.States[2])
+	}
+	if len(call.EnumChoices) != 1 || call.EnumChoices["encoding"] != "BASE64" {
+		t.Errorf("EnumChoices = %v, want encoding BASE64", call.EnumChoices)
+	}
+
+	// The ENUM default is a choice too, and an untyped value takes the
+	// type of its parameter
+	call, err = registry.ResolveCall("codec", "encode", nil, map[string]Argument{"s": {Value: "req.url"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.EnumChoices["encoding"] != "HEX" || call.OrderedArgs[1].Type != vcc.TypeString {
+		t.Errorf("ResolveCall() = %+v", call)
+	}
+
+	errors := []struct {
+		args  []Argument
+		named map[string]Argument
+		want  string
+	}{
+		{named: map[string]Argument{"encoding": {Value: "HEX"}}, want: "function encode: missing required argument 's'"},
+		{args: []Argument{{Value: "BASE32"}, {Value: "x"}}, want: `function encode argument encoding: "BASE32" is not one of BASE64, HEX`},
+		{args: []Argument{{Value: "HEX"}, {Type: vcc.TypeInt, Value: "1"}}, want: "function encode argument 2: expected STRING, got INT"},
+	}
+	for _, test := range errors {
+		if _, err := registry.ResolveCall("codec", "encode", test.args, test.named); err == nil || err.Error() != test.want {
+			t.Errorf("ResolveCall(%v, %v) error = %v, want %q", test.args, test.named, err, test.want)
+		}
+	}
+	if _, err := registry.ResolveCall("codec", "decode", nil, nil); err == nil {
+		t.Error("Expected an error for an unknown function")
+	}
+}
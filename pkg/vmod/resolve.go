@@ -0,0 +1,96 @@
+package vmod
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/perbu/vclparser/pkg/vcc"
+)
+
+// Argument is an argument of a call given to ResolveCall
+type Argument struct {
+	// Type is the type of the argument. An empty type is taken to be the
+	// type of the parameter, as for an ENUM value written as a bare word.
+	Type vcc.VCCType
+	// Value is the argument as written in VCL, such as an ENUM value or a
+	// literal. ResolveCall only looks at it for ENUM parameters.
+	Value string
+}
+
+// BoundCall is a VMOD function call with its arguments bound to the
+// parameters of the function, for code generators and simulators
+type BoundCall struct {
+	Function *vcc.Function
+	// Parameters are the parameters written in VCL, without PRIV_*
+	// parameters
+	Parameters []vcc.Parameter
+	// OrderedArgs are the arguments in parameter order. Parameters left out
+	// get their default value with the type of the parameter, or the zero
+	// Argument if they are optional and have none.
+	OrderedArgs []Argument
+	// States tell for each parameter whether its argument was given,
+	// defaulted or omitted
+	States []vcc.ArgumentState
+	// DefaultsApplied are the names of the parameters that get their
+	// default value
+	DefaultsApplied []string
+	// EnumChoices maps the ENUM parameters with a value, given or default,
+	// to that value
+	EnumChoices map[string]string
+}
+
+// ResolveCall binds the positional and named arguments of a call of
+// module.function to its parameters and validates their number, types and
+// ENUM values
+func (r *Registry) ResolveCall(moduleName, functionName string, args []Argument, namedArgs map[string]Argument) (*BoundCall, error) {
+	function, err := r.GetFunction(moduleName, functionName)
+	if err != nil {
+		return nil, err
+	}
+	params := vcc.VCLParameters(function.Parameters)
+	ordered, states, err := vcc.Bind(params, args, namedArgs)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", functionName, err)
+	}
+
+	call := &BoundCall{
+		Function:    function,
+		Parameters:  params,
+		OrderedArgs: ordered,
+		States:      states,
+		EnumChoices: make(map[string]string),
+	}
+	argTypes := make([]vcc.VCCType, len(params))
+	for i, param := range params {
+		switch states[i] {
+		case vcc.ArgumentDefaulted:
+			call.OrderedArgs[i] = Argument{Type: param.Type, Value: param.DefaultValue}
+			call.DefaultsApplied = append(call.DefaultsApplied, param.Name)
+		case vcc.ArgumentGiven:
+			if call.OrderedArgs[i].Type == "" {
+				call.OrderedArgs[i].Type = param.Type
+			}
+		}
+		// Omitted arguments take the type of their parameter
+		argTypes[i] = call.OrderedArgs[i].Type
+		if argTypes[i] == "" {
+			argTypes[i] = param.Type
+		}
+
+		if param.Type != vcc.TypeEnum || states[i] == vcc.ArgumentOmitted {
+			continue
+		}
+		value := call.OrderedArgs[i].Value
+		if param.Enum != nil && len(param.Enum.Values) > 0 && !slices.Contains(param.Enum.Values, value) {
+			return nil, fmt.Errorf("function %s argument %s: %q is not one of %s",
+				functionName, param.Name, value, strings.Join(param.Enum.Values, ", "))
+		}
+		call.EnumChoices[param.Name] = value
+	}
+
+	if err := function.ValidateCall(argTypes); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
@@ -0,0 +1,85 @@
+package vtc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Problem is a mistake found in a VTC file without running it
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// expectOperators are the comparisons expect accepts
+var expectOperators = map[string]bool{
+	"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "~": true, "!~": true,
+}
+
+// sessionFields are the fields servers and clients can test. Servers
+// receive requests and clients responses.
+var sessionFields = map[string]map[string]bool{
+	"server": {
+		"req.method": true, "req.url": true, "req.proto": true, "req.body": true, "req.bodylen": true,
+	},
+	"client": {
+		"resp.status": true, "resp.reason": true, "resp.proto": true, "resp.body": true,
+		"resp.bodylen": true, "resp.chunklen": true,
+	},
+}
+
+// Check reports expectations of servers and clients that can never work:
+// malformed ones, unknown operators, and fields of the wrong side, like a
+// server testing resp.status
+func (f *File) Check() []Problem {
+	var problems []Problem
+	for _, kind := range []string{"server", "client"} {
+		sessions := f.Servers
+		if kind == "client" {
+			sessions = f.Clients
+		}
+		for _, session := range sessions {
+			for _, e := range session.Expectations() {
+				if msg := checkExpectation(kind, e); msg != "" {
+					problems = append(problems, Problem{Line: e.Line, Message: fmt.Sprintf("%s %s: %s", kind, session.Name, msg)})
+				}
+			}
+		}
+	}
+	return problems
+}
+
+func checkExpectation(kind string, e Expectation) string {
+	if e.Args != nil {
+		return fmt.Sprintf("expect takes a field, an operator and a value, got %d arguments", len(e.Args))
+	}
+	if !expectOperators[e.Operator] {
+		return fmt.Sprintf("unknown operator %q in expect", e.Operator)
+	}
+
+	fields, side := sessionFields[kind], "req"
+	if kind == "client" {
+		side = "resp"
+	}
+	prefix, _, _ := strings.Cut(e.Field, ".")
+	switch {
+	case fields[e.Field], strings.HasPrefix(e.Field, side+".http."):
+	case prefix == "req" || prefix == "resp":
+		if prefix != side {
+			return fmt.Sprintf("%s is not available in a %s, which %s", e.Field, kind, receives(kind))
+		}
+		return fmt.Sprintf("unknown field %s", e.Field)
+	}
+	return ""
+}
+
+func receives(kind string) string {
+	if kind == "server" {
+		return "receives requests (req.*)"
+	}
+	return "receives responses (resp.*)"
+}
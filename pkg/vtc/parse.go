@@ -0,0 +1,196 @@
+package vtc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Word is one argument of a command: a bare word, a "quoted string" with
+// its escapes decoded, or the raw text of a {braced block}
+type Word struct {
+	Text   string
+	Braced bool
+	// Line and Column locate the start of the text, just after the quote
+	// or brace for quoted and braced words
+	Line   int
+	Column int
+}
+
+// Command is a line of VTC: a name followed by its arguments
+type Command struct {
+	Name string
+	Args []Word
+	Line int
+}
+
+// Block returns the first braced argument of the command, if any
+func (c Command) Block() (Word, bool) {
+	for _, arg := range c.Args {
+		if arg.Braced {
+			return arg, true
+		}
+	}
+	return Word{}, false
+}
+
+// SyntaxError is a VTC file that cannot be split into commands
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseCommands splits VTC source into commands. line and column give the
+// position of the first character of src, so that the bodies of braced
+// blocks can be parsed with positions in the enclosing file.
+func ParseCommands(src string, line, column int) ([]Command, error) {
+	s := &scanner{src: src, line: line, column: column}
+	var commands []Command
+	for {
+		words, err := s.command()
+		if err != nil {
+			return nil, err
+		}
+		if words == nil {
+			return commands, nil
+		}
+		if len(words) > 0 {
+			commands = append(commands, Command{Name: words[0].Text, Args: words[1:], Line: words[0].Line})
+		}
+	}
+}
+
+// scanner reads the Tcl-like word syntax of VTC files
+type scanner struct {
+	src          string
+	pos          int
+	line, column int
+}
+
+func (s *scanner) peek() byte {
+	if s.pos < len(s.src) {
+		return s.src[s.pos]
+	}
+	return 0
+}
+
+func (s *scanner) advance() byte {
+	c := s.src[s.pos]
+	s.pos++
+	if c == '\n' {
+		s.line++
+		s.column = 1
+	} else {
+		s.column++
+	}
+	return c
+}
+
+// command returns the words of the next command, an empty slice for a
+// blank or comment line, and nil at the end of the input
+func (s *scanner) command() ([]Word, error) {
+	if s.pos >= len(s.src) {
+		return nil, nil
+	}
+	words := []Word{}
+	for s.pos < len(s.src) {
+		switch c := s.peek(); {
+		case c == '\n' || c == ';':
+			s.advance()
+			return words, nil
+		case c == ' ' || c == '\t' || c == '\r':
+			s.advance()
+		case c == '\\' && s.pos+1 < len(s.src) && s.src[s.pos+1] == '\n':
+			// Line continuation
+			s.advance()
+			s.advance()
+		case c == '#' && len(words) == 0:
+			for s.pos < len(s.src) && s.peek() != '\n' {
+				s.advance()
+			}
+		case c == '"':
+			word, err := s.quoted()
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, word)
+		case c == '{':
+			word, err := s.braced()
+			if err != nil {
+				return nil, err
+			}
+			words = append(words, word)
+		default:
+			words = append(words, s.bare())
+		}
+	}
+	return words, nil
+}
+
+func (s *scanner) bare() Word {
+	word := Word{Line: s.line, Column: s.column}
+	start := s.pos
+	for s.pos < len(s.src) && !strings.ContainsRune(" \t\r\n;", rune(s.peek())) {
+		s.advance()
+	}
+	word.Text = s.src[start:s.pos]
+	return word
+}
+
+func (s *scanner) quoted() (Word, error) {
+	startLine := s.line
+	s.advance()
+	word := Word{Line: s.line, Column: s.column}
+	var b strings.Builder
+	for s.pos < len(s.src) {
+		c := s.advance()
+		switch {
+		case c == '"':
+			word.Text = b.String()
+			return word, nil
+		case c == '\\' && s.pos < len(s.src):
+			escaped := s.advance()
+			switch escaped {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(escaped)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(escaped)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return Word{}, &SyntaxError{Line: startLine, Message: "unterminated string"}
+}
+
+// braced reads a {block}, counting nested braces like varnishtest does
+func (s *scanner) braced() (Word, error) {
+	startLine := s.line
+	s.advance()
+	word := Word{Line: s.line, Column: s.column, Braced: true}
+	start := s.pos
+	depth := 1
+	for s.pos < len(s.src) {
+		switch s.advance() {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				word.Text = s.src[start : s.pos-1]
+				return word, nil
+			}
+		}
+	}
+	return Word{}, &SyntaxError{Line: startLine, Message: "unterminated {"}
+}
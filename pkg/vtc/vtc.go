@@ -0,0 +1,246 @@
+// Package vtc parses varnishtest (.vtc) files, so the VCL they embed and the
+// expectations of their clients and servers can be checked statically
+// without running varnishtest.
+//
+// A VTC file is a list of commands in a Tcl-like syntax. The commands this
+// package interprets are varnishtest, server, client and varnish; all
+// others are kept in File.Commands as they are.
+package vtc
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// File is a parsed VTC file
+type File struct {
+	Filename string
+	// Description is the argument of the varnishtest command
+	Description string
+	Commands    []Command
+	Servers     []*Session
+	Clients     []*Session
+	Varnishes   []*Varnish
+}
+
+// Session is a server or client and the commands of its specification,
+// such as txreq, rxresp and expect
+type Session struct {
+	Name     string
+	Line     int
+	Commands []Command
+}
+
+// Expectations returns the expect commands of the session
+func (s *Session) Expectations() []Expectation {
+	var expectations []Expectation
+	for _, cmd := range s.Commands {
+		if cmd.Name != "expect" {
+			continue
+		}
+		e := Expectation{Line: cmd.Line}
+		args := make([]string, len(cmd.Args))
+		for i, arg := range cmd.Args {
+			args[i] = arg.Text
+		}
+		if len(args) == 3 {
+			e.Field, e.Operator, e.Value = args[0], args[1], args[2]
+		} else {
+			e.Args = args
+		}
+		expectations = append(expectations, e)
+	}
+	return expectations
+}
+
+// Expectation is an expect command like expect resp.status == 200
+type Expectation struct {
+	Line     int
+	Field    string
+	Operator string
+	Value    string
+	// Args holds the arguments of malformed expectations, which do not
+	// have exactly three
+	Args []string
+}
+
+// Varnish is a varnish instance and the VCL loaded into it
+type Varnish struct {
+	Name string
+	Line int
+	VCLs []VCL
+}
+
+// VCL is a VCL program embedded with -vcl, -vcl+backend or -errvcl
+type VCL struct {
+	Text string
+	// Line and Column locate the first character of Text in the file
+	Line   int
+	Column int
+	// Backend is set for -vcl+backend, which declares a backend for
+	// each server defined before it
+	Backend bool
+	// Servers are the servers defined when the VCL was loaded
+	Servers []string
+	// ExpectedError is the message of -errvcl, which must fail to compile
+	ExpectedError string
+}
+
+// ParseFile reads and parses a VTC file
+func ParseFile(filename string) (*File, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data), filename)
+}
+
+// Parse parses VTC source. Errors are of type *SyntaxError.
+func Parse(src, filename string) (*File, error) {
+	commands, err := ParseCommands(src, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{Filename: filename, Commands: commands}
+	for _, cmd := range commands {
+		if len(cmd.Args) == 0 {
+			continue
+		}
+		switch cmd.Name {
+		case "varnishtest", "vtest":
+			f.Description = cmd.Args[0].Text
+		case "server":
+			if err := f.addSession(&f.Servers, cmd); err != nil {
+				return nil, err
+			}
+		case "client":
+			if err := f.addSession(&f.Clients, cmd); err != nil {
+				return nil, err
+			}
+		case "varnish":
+			if err := f.addVarnish(cmd); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return f, nil
+}
+
+// addSession records the specification of a server or client. Later
+// commands for the same name, such as server s1 -wait, only add a
+// specification when they have one.
+func (f *File) addSession(sessions *[]*Session, cmd Command) error {
+	name := cmd.Args[0].Text
+	var session *Session
+	for _, s := range *sessions {
+		if s.Name == name {
+			session = s
+		}
+	}
+	if session == nil {
+		session = &Session{Name: name, Line: cmd.Line}
+		*sessions = append(*sessions, session)
+	}
+
+	block, ok := cmd.Block()
+	if !ok {
+		return nil
+	}
+	spec, err := ParseCommands(block.Text, block.Line, block.Column)
+	if err != nil {
+		return err
+	}
+	session.Commands = spec
+	return nil
+}
+
+func (f *File) addVarnish(cmd Command) error {
+	name := cmd.Args[0].Text
+	var v *Varnish
+	for _, existing := range f.Varnishes {
+		if existing.Name == name {
+			v = existing
+		}
+	}
+	if v == nil {
+		v = &Varnish{Name: name, Line: cmd.Line}
+		f.Varnishes = append(f.Varnishes, v)
+	}
+
+	args := cmd.Args[1:]
+	for i := 0; i < len(args); i++ {
+		var vcl VCL
+		switch args[i].Text {
+		case "-vcl":
+		case "-vcl+backend":
+			vcl.Backend = true
+		case "-errvcl":
+			if i+1 >= len(args) {
+				return &SyntaxError{Line: cmd.Line, Message: "-errvcl needs a message and a VCL block"}
+			}
+			i++
+			vcl.ExpectedError = args[i].Text
+		default:
+			continue
+		}
+		if i+1 >= len(args) || !args[i+1].Braced {
+			return &SyntaxError{Line: cmd.Line, Message: args[i].Text + " needs a VCL block"}
+		}
+		i++
+		vcl.Text, vcl.Line, vcl.Column = args[i].Text, args[i].Line, args[i].Column
+		for _, server := range f.Servers {
+			vcl.Servers = append(vcl.Servers, server.Name)
+		}
+		v.VCLs = append(v.VCLs, vcl)
+	}
+	return nil
+}
+
+// macro matches varnishtest macros such as ${s1_addr}
+var macro = regexp.MustCompile(`\$\{([A-Za-z0-9_]+)\}`)
+
+// Source returns the VCL as varnishtest loads it: with the vcl 4.1
+// declaration varnishtest adds, a backend for each server when Backend is
+// set, and macros replaced by placeholder values. Header is the number of
+// lines added before Text.
+func (v VCL) Source() (source string, header int) {
+	var b strings.Builder
+	if !strings.HasPrefix(strings.TrimSpace(v.Text), "vcl ") {
+		b.WriteString("vcl 4.1;\n")
+	}
+	if v.Backend {
+		for _, server := range v.Servers {
+			fmt.Fprintf(&b, "backend %s { .host = \"${%s_addr}\"; .port = \"${%s_port}\"; }\n", server, server, server)
+		}
+	}
+	header = strings.Count(b.String(), "\n")
+	b.WriteString(v.Text)
+	return ExpandMacros(b.String()), header
+}
+
+// ExpandMacros replaces the macros varnishtest defines at run time with
+// values of the same form, so the text can be parsed. Unknown macros are
+// left alone.
+func ExpandMacros(text string) string {
+	return macro.ReplaceAllStringFunc(text, func(m string) string {
+		name := macro.FindStringSubmatch(m)[1]
+		switch {
+		case strings.HasSuffix(name, "_addr"), name == "localhost":
+			return "127.0.0.1"
+		case strings.HasSuffix(name, "_port"):
+			return "8080"
+		case strings.HasSuffix(name, "_sock"):
+			return "127.0.0.1 8080"
+		case name == "bad_ip":
+			return "192.0.2.255"
+		case name == "bad_backend":
+			return "127.0.0.1 9"
+		case name == "tmpdir", name == "testdir", name == "topbuild", name == "topsrc":
+			return "/tmp"
+		}
+		return m
+	})
+}
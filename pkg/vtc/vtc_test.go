@@ -0,0 +1,154 @@
+package vtc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testVTC = `varnishtest "Purging \"objects\""
+
+# Backend serving a single object
+server s1 {
+	rxreq
+	expect req.url == "/"
+	txresp -hdr "Foo: bar" \
+		-body "hello"
+} -start
+
+varnish v1 -vcl+backend {
+	sub vcl_recv {
+		if (req.method == "PURGE") { return (purge); }
+	}
+} -start
+
+varnish v1 -errvcl {Unknown variable} {
+	sub vcl_recv { set req.foo = 1; }
+}
+
+client c1 -connect ${v1_sock} {
+	txreq -url "/"
+	rxresp
+	expect resp.status == 200
+	expect resp.http.Foo == "bar"
+} -run
+
+server s1 -wait
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse(testVTC, "test.vtc")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if f.Description != `Purging "objects"` {
+		t.Errorf("Description = %q", f.Description)
+	}
+	if len(f.Commands) != 6 {
+		t.Errorf("Expected 6 commands, got %d", len(f.Commands))
+	}
+
+	if len(f.Servers) != 1 || f.Servers[0].Name != "s1" || len(f.Servers[0].Commands) != 3 {
+		t.Fatalf("Servers = %+v", f.Servers)
+	}
+	txresp := f.Servers[0].Commands[2]
+	if txresp.Name != "txresp" || len(txresp.Args) != 4 || txresp.Args[3].Text != "hello" || txresp.Line != 7 {
+		t.Errorf("txresp = %+v", txresp)
+	}
+
+	if len(f.Clients) != 1 {
+		t.Fatalf("Clients = %+v", f.Clients)
+	}
+	expectations := f.Clients[0].Expectations()
+	if len(expectations) != 2 {
+		t.Fatalf("Expected 2 expectations, got %+v", expectations)
+	}
+	if e := expectations[1]; e.Field != "resp.http.Foo" || e.Operator != "==" || e.Value != "bar" || e.Line != 25 {
+		t.Errorf("expectation = %+v", e)
+	}
+
+	if len(f.Varnishes) != 1 || len(f.Varnishes[0].VCLs) != 2 {
+		t.Fatalf("Varnishes = %+v", f.Varnishes)
+	}
+	vcl := f.Varnishes[0].VCLs[0]
+	if !vcl.Backend || vcl.Line != 11 || strings.Join(vcl.Servers, ",") != "s1" {
+		t.Errorf("VCL = %+v", vcl)
+	}
+	if errvcl := f.Varnishes[0].VCLs[1]; errvcl.ExpectedError != "Unknown variable" {
+		t.Errorf("ExpectedError = %q", errvcl.ExpectedError)
+	}
+}
+
+func TestVCLSource(t *testing.T) {
+	vcl := VCL{Text: "\n\tsub vcl_recv { set req.http.x = \"${s1_addr}\"; }\n", Backend: true, Servers: []string{"s1", "s2"}}
+	source, header := vcl.Source()
+	want := "vcl 4.1;\n" +
+		"backend s1 { .host = \"127.0.0.1\"; .port = \"8080\"; }\n" +
+		"backend s2 { .host = \"127.0.0.1\"; .port = \"8080\"; }\n" +
+		"\n\tsub vcl_recv { set req.http.x = \"127.0.0.1\"; }\n"
+	if source != want || header != 3 {
+		t.Errorf("Source() = %q, %d, want %q, 3", source, header, want)
+	}
+
+	source, header = VCL{Text: "vcl 4.0;\nbackend b { .host = \"${unknown}\"; }"}.Source()
+	if header != 0 || !strings.Contains(source, "${unknown}") {
+		t.Errorf("Source() = %q, %d", source, header)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	src := `varnishtest "expectations"
+server s1 {
+	rxreq
+	expect req.http.Host == "example.com"
+	expect resp.status == 200
+} -start
+client c1 {
+	txreq
+	rxresp
+	expect resp.status = 200
+	expect resp.body
+	expect resp.stauts == 200
+} -run
+`
+	f, err := Parse(src, "test.vtc")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := []string{
+		"line 5: server s1: resp.status is not available in a server",
+		`line 10: client c1: unknown operator "="`,
+		"line 11: client c1: expect takes a field, an operator and a value, got 1 arguments",
+		"line 12: client c1: unknown field resp.stauts",
+	}
+	problems := f.Check()
+	if len(problems) != len(want) {
+		t.Fatalf("Expected %d problems, got %v", len(want), problems)
+	}
+	for i, fragment := range want {
+		if !strings.Contains(problems[i].String(), fragment) {
+			t.Errorf("problem %d = %q, want it to contain %q", i, problems[i], fragment)
+		}
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		line int
+	}{
+		{"varnishtest \"unterminated\n", 1},
+		{"server s1 {\n\trxreq\n", 1},
+		{"varnishtest \"x\"\nvarnish v1 -vcl -start\n", 2},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.src, "test.vtc")
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Line != test.line {
+			t.Errorf("Parse(%q) error = %v, want a syntax error at line %d", test.src, err, test.line)
+		}
+	}
+}
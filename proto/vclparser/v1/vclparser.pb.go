@@ -0,0 +1,6919 @@
+// Protocol buffer definitions of the VCL syntax tree, analyzer diagnostics
+// and a small service exposing the parser, so services in other languages
+// can use it as a sidecar.
+//
+// The messages follow the Go types in pkg/ast and pkg/analyzer field by
+// field. Node messages carry their source span; fields holding optional
+// nodes are unset when the Go field is nil.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: proto/vclparser/v1/vclparser.proto
+
+package vclparserv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_ERROR   Severity = 0
+	Severity_SEVERITY_WARNING Severity = 1
+	Severity_SEVERITY_INFO    Severity = 2
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_ERROR",
+		1: "SEVERITY_WARNING",
+		2: "SEVERITY_INFO",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_ERROR":   0,
+		"SEVERITY_WARNING": 1,
+		"SEVERITY_INFO":    2,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vclparser_v1_vclparser_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_proto_vclparser_v1_vclparser_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{0}
+}
+
+type ParseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Source   string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Stop after this many errors; 0 means no limit
+	MaxErrors int32 `protobuf:"varint,3,opt,name=max_errors,json=maxErrors,proto3" json:"max_errors,omitempty"`
+}
+
+func (x *ParseRequest) Reset() {
+	*x = ParseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseRequest) ProtoMessage() {}
+
+func (x *ParseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseRequest.ProtoReflect.Descriptor instead.
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ParseRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetMaxErrors() int32 {
+	if x != nil {
+		return x.MaxErrors
+	}
+	return 0
+}
+
+type ParseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Program     *Program      `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	Diagnostics []*Diagnostic `protobuf:"bytes,2,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+}
+
+func (x *ParseResponse) Reset() {
+	*x = ParseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResponse) ProtoMessage() {}
+
+func (x *ParseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParseResponse) GetProgram() *Program {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *ParseResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type AnalyzeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Source   string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	// Varnish release to check against, such as "7.4" or "6.0-enterprise"
+	VarnishVersion string `protobuf:"bytes,3,opt,name=varnish_version,json=varnishVersion,proto3" json:"varnish_version,omitempty"`
+	// Severity of unreferenced declarations: "warning", "error" or "off"
+	Unreferenced string `protobuf:"bytes,4,opt,name=unreferenced,proto3" json:"unreferenced,omitempty"`
+	// VCL labels that return (vcl(label)) may name
+	Labels []string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty"`
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AnalyzeRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetVarnishVersion() string {
+	if x != nil {
+		return x.VarnishVersion
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetUnreferenced() string {
+	if x != nil {
+		return x.Unreferenced
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type AnalyzeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Diagnostics []*Diagnostic `protobuf:"bytes,1,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+}
+
+func (x *AnalyzeResponse) Reset() {
+	*x = AnalyzeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnalyzeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeResponse) ProtoMessage() {}
+
+func (x *AnalyzeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AnalyzeResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type ResolveIncludesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MainFile string `protobuf:"bytes,1,opt,name=main_file,json=mainFile,proto3" json:"main_file,omitempty"`
+	// Files by path. When empty, files are read from the file system of the
+	// server below base_path.
+	Files    map[string]string `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	BasePath string            `protobuf:"bytes,3,opt,name=base_path,json=basePath,proto3" json:"base_path,omitempty"`
+	// Directories searched for relative includes, like vcl_path
+	SearchPath []string `protobuf:"bytes,4,rep,name=search_path,json=searchPath,proto3" json:"search_path,omitempty"`
+	MaxDepth   int32    `protobuf:"varint,5,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+}
+
+func (x *ResolveIncludesRequest) Reset() {
+	*x = ResolveIncludesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveIncludesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveIncludesRequest) ProtoMessage() {}
+
+func (x *ResolveIncludesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveIncludesRequest.ProtoReflect.Descriptor instead.
+func (*ResolveIncludesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResolveIncludesRequest) GetMainFile() string {
+	if x != nil {
+		return x.MainFile
+	}
+	return ""
+}
+
+func (x *ResolveIncludesRequest) GetFiles() map[string]string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ResolveIncludesRequest) GetBasePath() string {
+	if x != nil {
+		return x.BasePath
+	}
+	return ""
+}
+
+func (x *ResolveIncludesRequest) GetSearchPath() []string {
+	if x != nil {
+		return x.SearchPath
+	}
+	return nil
+}
+
+func (x *ResolveIncludesRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+type ResolveIncludesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Program *Program `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty"`
+	// The file each top-level declaration was read from, by index into
+	// program.declarations
+	Sources []*Source `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+}
+
+func (x *ResolveIncludesResponse) Reset() {
+	*x = ResolveIncludesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveIncludesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveIncludesResponse) ProtoMessage() {}
+
+func (x *ResolveIncludesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveIncludesResponse.ProtoReflect.Descriptor instead.
+func (*ResolveIncludesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResolveIncludesResponse) GetProgram() *Program {
+	if x != nil {
+		return x.Program
+	}
+	return nil
+}
+
+func (x *ResolveIncludesResponse) GetSources() []*Source {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type Source struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// Files that included file, outermost first
+	Chain []string `protobuf:"bytes,2,rep,name=chain,proto3" json:"chain,omitempty"`
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Source) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Source) GetChain() []string {
+	if x != nil {
+		return x.Chain
+	}
+	return nil
+}
+
+type Diagnostic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filename     string    `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Position     *Position `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	EndPosition  *Position `protobuf:"bytes,3,opt,name=end_position,json=endPosition,proto3" json:"end_position,omitempty"`
+	Severity     Severity  `protobuf:"varint,4,opt,name=severity,proto3,enum=vclparser.v1.Severity" json:"severity,omitempty"`
+	Code         string    `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	Message      string    `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	IncludeChain []string  `protobuf:"bytes,7,rep,name=include_chain,json=includeChain,proto3" json:"include_chain,omitempty"`
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Diagnostic) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Diagnostic) GetEndPosition() *Position {
+	if x != nil {
+		return x.EndPosition
+	}
+	return nil
+}
+
+func (x *Diagnostic) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_ERROR
+}
+
+func (x *Diagnostic) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetIncludeChain() []string {
+	if x != nil {
+		return x.IncludeChain
+	}
+	return nil
+}
+
+type Position struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Line       int32 `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`                               // 1-indexed, 0 when unknown
+	Column     int32 `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`                           // 1-indexed, 0 when unknown
+	Offset     int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                           // 0-indexed byte offset
+	RuneColumn int32 `protobuf:"varint,4,opt,name=rune_column,json=runeColumn,proto3" json:"rune_column,omitempty"` // column counted in characters rather than bytes
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Position) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Position) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *Position) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Position) GetRuneColumn() int32 {
+	if x != nil {
+		return x.RuneColumn
+	}
+	return 0
+}
+
+type Span struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Start *Position `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End   *Position `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *Span) Reset() {
+	*x = Span{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Span) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Span) ProtoMessage() {}
+
+func (x *Span) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Span.ProtoReflect.Descriptor instead.
+func (*Span) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Span) GetStart() *Position {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Span) GetEnd() *Position {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type Program struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span         *Span           `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	VclVersion   *VCLVersionDecl `protobuf:"bytes,2,opt,name=vcl_version,json=vclVersion,proto3" json:"vcl_version,omitempty"`
+	Declarations []*Declaration  `protobuf:"bytes,3,rep,name=declarations,proto3" json:"declarations,omitempty"`
+}
+
+func (x *Program) Reset() {
+	*x = Program{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Program) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Program) ProtoMessage() {}
+
+func (x *Program) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Program.ProtoReflect.Descriptor instead.
+func (*Program) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Program) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *Program) GetVclVersion() *VCLVersionDecl {
+	if x != nil {
+		return x.VclVersion
+	}
+	return nil
+}
+
+func (x *Program) GetDeclarations() []*Declaration {
+	if x != nil {
+		return x.Declarations
+	}
+	return nil
+}
+
+type Declaration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//	*Declaration_VclVersion
+	//	*Declaration_Import
+	//	*Declaration_Include
+	//	*Declaration_Backend
+	//	*Declaration_Probe
+	//	*Declaration_Acl
+	//	*Declaration_Sub
+	//	*Declaration_Table
+	//	*Declaration_Director
+	Kind isDeclaration_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *Declaration) Reset() {
+	*x = Declaration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Declaration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Declaration) ProtoMessage() {}
+
+func (x *Declaration) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Declaration.ProtoReflect.Descriptor instead.
+func (*Declaration) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{11}
+}
+
+func (m *Declaration) GetKind() isDeclaration_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *Declaration) GetVclVersion() *VCLVersionDecl {
+	if x, ok := x.GetKind().(*Declaration_VclVersion); ok {
+		return x.VclVersion
+	}
+	return nil
+}
+
+func (x *Declaration) GetImport() *ImportDecl {
+	if x, ok := x.GetKind().(*Declaration_Import); ok {
+		return x.Import
+	}
+	return nil
+}
+
+func (x *Declaration) GetInclude() *IncludeDecl {
+	if x, ok := x.GetKind().(*Declaration_Include); ok {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *Declaration) GetBackend() *BackendDecl {
+	if x, ok := x.GetKind().(*Declaration_Backend); ok {
+		return x.Backend
+	}
+	return nil
+}
+
+func (x *Declaration) GetProbe() *ProbeDecl {
+	if x, ok := x.GetKind().(*Declaration_Probe); ok {
+		return x.Probe
+	}
+	return nil
+}
+
+func (x *Declaration) GetAcl() *ACLDecl {
+	if x, ok := x.GetKind().(*Declaration_Acl); ok {
+		return x.Acl
+	}
+	return nil
+}
+
+func (x *Declaration) GetSub() *SubDecl {
+	if x, ok := x.GetKind().(*Declaration_Sub); ok {
+		return x.Sub
+	}
+	return nil
+}
+
+func (x *Declaration) GetTable() *TableDecl {
+	if x, ok := x.GetKind().(*Declaration_Table); ok {
+		return x.Table
+	}
+	return nil
+}
+
+func (x *Declaration) GetDirector() *DirectorDecl {
+	if x, ok := x.GetKind().(*Declaration_Director); ok {
+		return x.Director
+	}
+	return nil
+}
+
+type isDeclaration_Kind interface {
+	isDeclaration_Kind()
+}
+
+type Declaration_VclVersion struct {
+	VclVersion *VCLVersionDecl `protobuf:"bytes,1,opt,name=vcl_version,json=vclVersion,proto3,oneof"`
+}
+
+type Declaration_Import struct {
+	Import *ImportDecl `protobuf:"bytes,2,opt,name=import,proto3,oneof"`
+}
+
+type Declaration_Include struct {
+	Include *IncludeDecl `protobuf:"bytes,3,opt,name=include,proto3,oneof"`
+}
+
+type Declaration_Backend struct {
+	Backend *BackendDecl `protobuf:"bytes,4,opt,name=backend,proto3,oneof"`
+}
+
+type Declaration_Probe struct {
+	Probe *ProbeDecl `protobuf:"bytes,5,opt,name=probe,proto3,oneof"`
+}
+
+type Declaration_Acl struct {
+	Acl *ACLDecl `protobuf:"bytes,6,opt,name=acl,proto3,oneof"`
+}
+
+type Declaration_Sub struct {
+	Sub *SubDecl `protobuf:"bytes,7,opt,name=sub,proto3,oneof"`
+}
+
+type Declaration_Table struct {
+	Table *TableDecl `protobuf:"bytes,8,opt,name=table,proto3,oneof"`
+}
+
+type Declaration_Director struct {
+	Director *DirectorDecl `protobuf:"bytes,9,opt,name=director,proto3,oneof"`
+}
+
+func (*Declaration_VclVersion) isDeclaration_Kind() {}
+
+func (*Declaration_Import) isDeclaration_Kind() {}
+
+func (*Declaration_Include) isDeclaration_Kind() {}
+
+func (*Declaration_Backend) isDeclaration_Kind() {}
+
+func (*Declaration_Probe) isDeclaration_Kind() {}
+
+func (*Declaration_Acl) isDeclaration_Kind() {}
+
+func (*Declaration_Sub) isDeclaration_Kind() {}
+
+func (*Declaration_Table) isDeclaration_Kind() {}
+
+func (*Declaration_Director) isDeclaration_Kind() {}
+
+type VCLVersionDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span    *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *VCLVersionDecl) Reset() {
+	*x = VCLVersionDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VCLVersionDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VCLVersionDecl) ProtoMessage() {}
+
+func (x *VCLVersionDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VCLVersionDecl.ProtoReflect.Descriptor instead.
+func (*VCLVersionDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *VCLVersionDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *VCLVersionDecl) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type ImportDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span   *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Module string `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Alias  string `protobuf:"bytes,3,opt,name=alias,proto3" json:"alias,omitempty"`
+	From   string `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+}
+
+func (x *ImportDecl) Reset() {
+	*x = ImportDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportDecl) ProtoMessage() {}
+
+func (x *ImportDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportDecl.ProtoReflect.Descriptor instead.
+func (*ImportDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ImportDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ImportDecl) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *ImportDecl) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *ImportDecl) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+type IncludeDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Glob bool   `protobuf:"varint,3,opt,name=glob,proto3" json:"glob,omitempty"`
+}
+
+func (x *IncludeDecl) Reset() {
+	*x = IncludeDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IncludeDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncludeDecl) ProtoMessage() {}
+
+func (x *IncludeDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncludeDecl.ProtoReflect.Descriptor instead.
+func (*IncludeDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IncludeDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *IncludeDecl) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *IncludeDecl) GetGlob() bool {
+	if x != nil {
+		return x.Glob
+	}
+	return false
+}
+
+type BackendDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name       string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NamePos    *Position   `protobuf:"bytes,3,opt,name=name_pos,json=namePos,proto3" json:"name_pos,omitempty"`
+	Properties []*Property `protobuf:"bytes,4,rep,name=properties,proto3" json:"properties,omitempty"`
+}
+
+func (x *BackendDecl) Reset() {
+	*x = BackendDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BackendDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendDecl) ProtoMessage() {}
+
+func (x *BackendDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendDecl.ProtoReflect.Descriptor instead.
+func (*BackendDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BackendDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *BackendDecl) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackendDecl) GetNamePos() *Position {
+	if x != nil {
+		return x.NamePos
+	}
+	return nil
+}
+
+func (x *BackendDecl) GetProperties() []*Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type ProbeDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name       string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NamePos    *Position   `protobuf:"bytes,3,opt,name=name_pos,json=namePos,proto3" json:"name_pos,omitempty"`
+	Properties []*Property `protobuf:"bytes,4,rep,name=properties,proto3" json:"properties,omitempty"`
+}
+
+func (x *ProbeDecl) Reset() {
+	*x = ProbeDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProbeDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeDecl) ProtoMessage() {}
+
+func (x *ProbeDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeDecl.ProtoReflect.Descriptor instead.
+func (*ProbeDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProbeDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ProbeDecl) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProbeDecl) GetNamePos() *Position {
+	if x != nil {
+		return x.NamePos
+	}
+	return nil
+}
+
+func (x *ProbeDecl) GetProperties() []*Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+// Property is a .name = value property of a backend or probe
+type Property struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name  string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value *Expression `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Property) Reset() {
+	*x = Property{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Property) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Property) ProtoMessage() {}
+
+func (x *Property) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Property.ProtoReflect.Descriptor instead.
+func (*Property) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Property) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *Property) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Property) GetValue() *Expression {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type ACLDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span    *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name    string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NamePos *Position   `protobuf:"bytes,3,opt,name=name_pos,json=namePos,proto3" json:"name_pos,omitempty"`
+	Entries []*ACLEntry `protobuf:"bytes,4,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ACLDecl) Reset() {
+	*x = ACLDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ACLDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACLDecl) ProtoMessage() {}
+
+func (x *ACLDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACLDecl.ProtoReflect.Descriptor instead.
+func (*ACLDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ACLDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ACLDecl) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ACLDecl) GetNamePos() *Position {
+	if x != nil {
+		return x.NamePos
+	}
+	return nil
+}
+
+func (x *ACLDecl) GetEntries() []*ACLEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ACLEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span      *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Negated   bool        `protobuf:"varint,2,opt,name=negated,proto3" json:"negated,omitempty"`
+	Network   *Expression `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	Host      string      `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	PrefixLen int32       `protobuf:"varint,5,opt,name=prefix_len,json=prefixLen,proto3" json:"prefix_len,omitempty"` // -1 when there is no mask
+	Optional  bool        `protobuf:"varint,6,opt,name=optional,proto3" json:"optional,omitempty"`
+}
+
+func (x *ACLEntry) Reset() {
+	*x = ACLEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ACLEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ACLEntry) ProtoMessage() {}
+
+func (x *ACLEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ACLEntry.ProtoReflect.Descriptor instead.
+func (*ACLEntry) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ACLEntry) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ACLEntry) GetNegated() bool {
+	if x != nil {
+		return x.Negated
+	}
+	return false
+}
+
+func (x *ACLEntry) GetNetwork() *Expression {
+	if x != nil {
+		return x.Network
+	}
+	return nil
+}
+
+func (x *ACLEntry) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *ACLEntry) GetPrefixLen() int32 {
+	if x != nil {
+		return x.PrefixLen
+	}
+	return 0
+}
+
+func (x *ACLEntry) GetOptional() bool {
+	if x != nil {
+		return x.Optional
+	}
+	return false
+}
+
+type SubDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span           `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name       string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NamePos    *Position       `protobuf:"bytes,3,opt,name=name_pos,json=namePos,proto3" json:"name_pos,omitempty"`
+	Body       *BlockStatement `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	ReturnType string          `protobuf:"bytes,5,opt,name=return_type,json=returnType,proto3" json:"return_type,omitempty"` // Fastly typed subroutines only
+}
+
+func (x *SubDecl) Reset() {
+	*x = SubDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubDecl) ProtoMessage() {}
+
+func (x *SubDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubDecl.ProtoReflect.Descriptor instead.
+func (*SubDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SubDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *SubDecl) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubDecl) GetNamePos() *Position {
+	if x != nil {
+		return x.NamePos
+	}
+	return nil
+}
+
+func (x *SubDecl) GetBody() *BlockStatement {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *SubDecl) GetReturnType() string {
+	if x != nil {
+		return x.ReturnType
+	}
+	return ""
+}
+
+type TableDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span      *Span         `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name      string        `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NamePos   *Position     `protobuf:"bytes,3,opt,name=name_pos,json=namePos,proto3" json:"name_pos,omitempty"`
+	ValueType string        `protobuf:"bytes,4,opt,name=value_type,json=valueType,proto3" json:"value_type,omitempty"`
+	Entries   []*TableEntry `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *TableDecl) Reset() {
+	*x = TableDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableDecl) ProtoMessage() {}
+
+func (x *TableDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableDecl.ProtoReflect.Descriptor instead.
+func (*TableDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TableDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *TableDecl) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TableDecl) GetNamePos() *Position {
+	if x != nil {
+		return x.NamePos
+	}
+	return nil
+}
+
+func (x *TableDecl) GetValueType() string {
+	if x != nil {
+		return x.ValueType
+	}
+	return ""
+}
+
+func (x *TableDecl) GetEntries() []*TableEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type TableEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Key   string      `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value *Expression `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *TableEntry) Reset() {
+	*x = TableEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableEntry) ProtoMessage() {}
+
+func (x *TableEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableEntry.ProtoReflect.Descriptor instead.
+func (*TableEntry) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TableEntry) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *TableEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TableEntry) GetValue() *Expression {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type DirectorDecl struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span              `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name       string             `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NamePos    *Position          `protobuf:"bytes,3,opt,name=name_pos,json=namePos,proto3" json:"name_pos,omitempty"`
+	Policy     string             `protobuf:"bytes,4,opt,name=policy,proto3" json:"policy,omitempty"`
+	Properties []*Property        `protobuf:"bytes,5,rep,name=properties,proto3" json:"properties,omitempty"`
+	Backends   []*DirectorBackend `protobuf:"bytes,6,rep,name=backends,proto3" json:"backends,omitempty"`
+}
+
+func (x *DirectorDecl) Reset() {
+	*x = DirectorDecl{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DirectorDecl) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectorDecl) ProtoMessage() {}
+
+func (x *DirectorDecl) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectorDecl.ProtoReflect.Descriptor instead.
+func (*DirectorDecl) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DirectorDecl) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *DirectorDecl) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DirectorDecl) GetNamePos() *Position {
+	if x != nil {
+		return x.NamePos
+	}
+	return nil
+}
+
+func (x *DirectorDecl) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *DirectorDecl) GetProperties() []*Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *DirectorDecl) GetBackends() []*DirectorBackend {
+	if x != nil {
+		return x.Backends
+	}
+	return nil
+}
+
+type DirectorBackend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Properties []*Property `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty"`
+}
+
+func (x *DirectorBackend) Reset() {
+	*x = DirectorBackend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DirectorBackend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectorBackend) ProtoMessage() {}
+
+func (x *DirectorBackend) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectorBackend.ProtoReflect.Descriptor instead.
+func (*DirectorBackend) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DirectorBackend) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *DirectorBackend) GetProperties() []*Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type Statement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//	*Statement_Block
+	//	*Statement_Expression
+	//	*Statement_If
+	//	*Statement_Set
+	//	*Statement_Unset
+	//	*Statement_Call
+	//	*Statement_Return
+	//	*Statement_Synthetic
+	//	*Statement_Error
+	//	*Statement_Restart
+	//	*Statement_CSource
+	//	*Statement_New
+	//	*Statement_Declare
+	//	*Statement_Add
+	//	*Statement_Esi
+	//	*Statement_Log
+	//	*Statement_Goto
+	//	*Statement_Label
+	Kind isStatement_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *Statement) Reset() {
+	*x = Statement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Statement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statement) ProtoMessage() {}
+
+func (x *Statement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statement.ProtoReflect.Descriptor instead.
+func (*Statement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{25}
+}
+
+func (m *Statement) GetKind() isStatement_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *Statement) GetBlock() *BlockStatement {
+	if x, ok := x.GetKind().(*Statement_Block); ok {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *Statement) GetExpression() *ExpressionStatement {
+	if x, ok := x.GetKind().(*Statement_Expression); ok {
+		return x.Expression
+	}
+	return nil
+}
+
+func (x *Statement) GetIf() *IfStatement {
+	if x, ok := x.GetKind().(*Statement_If); ok {
+		return x.If
+	}
+	return nil
+}
+
+func (x *Statement) GetSet() *SetStatement {
+	if x, ok := x.GetKind().(*Statement_Set); ok {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *Statement) GetUnset() *UnsetStatement {
+	if x, ok := x.GetKind().(*Statement_Unset); ok {
+		return x.Unset
+	}
+	return nil
+}
+
+func (x *Statement) GetCall() *CallStatement {
+	if x, ok := x.GetKind().(*Statement_Call); ok {
+		return x.Call
+	}
+	return nil
+}
+
+func (x *Statement) GetReturn() *ReturnStatement {
+	if x, ok := x.GetKind().(*Statement_Return); ok {
+		return x.Return
+	}
+	return nil
+}
+
+func (x *Statement) GetSynthetic() *SyntheticStatement {
+	if x, ok := x.GetKind().(*Statement_Synthetic); ok {
+		return x.Synthetic
+	}
+	return nil
+}
+
+func (x *Statement) GetError() *ErrorStatement {
+	if x, ok := x.GetKind().(*Statement_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *Statement) GetRestart() *RestartStatement {
+	if x, ok := x.GetKind().(*Statement_Restart); ok {
+		return x.Restart
+	}
+	return nil
+}
+
+func (x *Statement) GetCSource() *CSourceStatement {
+	if x, ok := x.GetKind().(*Statement_CSource); ok {
+		return x.CSource
+	}
+	return nil
+}
+
+func (x *Statement) GetNew() *NewStatement {
+	if x, ok := x.GetKind().(*Statement_New); ok {
+		return x.New
+	}
+	return nil
+}
+
+func (x *Statement) GetDeclare() *DeclareStatement {
+	if x, ok := x.GetKind().(*Statement_Declare); ok {
+		return x.Declare
+	}
+	return nil
+}
+
+func (x *Statement) GetAdd() *AddStatement {
+	if x, ok := x.GetKind().(*Statement_Add); ok {
+		return x.Add
+	}
+	return nil
+}
+
+func (x *Statement) GetEsi() *EsiStatement {
+	if x, ok := x.GetKind().(*Statement_Esi); ok {
+		return x.Esi
+	}
+	return nil
+}
+
+func (x *Statement) GetLog() *LogStatement {
+	if x, ok := x.GetKind().(*Statement_Log); ok {
+		return x.Log
+	}
+	return nil
+}
+
+func (x *Statement) GetGoto() *GotoStatement {
+	if x, ok := x.GetKind().(*Statement_Goto); ok {
+		return x.Goto
+	}
+	return nil
+}
+
+func (x *Statement) GetLabel() *LabelStatement {
+	if x, ok := x.GetKind().(*Statement_Label); ok {
+		return x.Label
+	}
+	return nil
+}
+
+type isStatement_Kind interface {
+	isStatement_Kind()
+}
+
+type Statement_Block struct {
+	Block *BlockStatement `protobuf:"bytes,1,opt,name=block,proto3,oneof"`
+}
+
+type Statement_Expression struct {
+	Expression *ExpressionStatement `protobuf:"bytes,2,opt,name=expression,proto3,oneof"`
+}
+
+type Statement_If struct {
+	If *IfStatement `protobuf:"bytes,3,opt,name=if,proto3,oneof"`
+}
+
+type Statement_Set struct {
+	Set *SetStatement `protobuf:"bytes,4,opt,name=set,proto3,oneof"`
+}
+
+type Statement_Unset struct {
+	Unset *UnsetStatement `protobuf:"bytes,5,opt,name=unset,proto3,oneof"`
+}
+
+type Statement_Call struct {
+	Call *CallStatement `protobuf:"bytes,6,opt,name=call,proto3,oneof"`
+}
+
+type Statement_Return struct {
+	Return *ReturnStatement `protobuf:"bytes,7,opt,name=return,proto3,oneof"`
+}
+
+type Statement_Synthetic struct {
+	Synthetic *SyntheticStatement `protobuf:"bytes,8,opt,name=synthetic,proto3,oneof"`
+}
+
+type Statement_Error struct {
+	Error *ErrorStatement `protobuf:"bytes,9,opt,name=error,proto3,oneof"`
+}
+
+type Statement_Restart struct {
+	Restart *RestartStatement `protobuf:"bytes,10,opt,name=restart,proto3,oneof"`
+}
+
+type Statement_CSource struct {
+	CSource *CSourceStatement `protobuf:"bytes,11,opt,name=c_source,json=cSource,proto3,oneof"`
+}
+
+type Statement_New struct {
+	New *NewStatement `protobuf:"bytes,12,opt,name=new,proto3,oneof"`
+}
+
+type Statement_Declare struct {
+	Declare *DeclareStatement `protobuf:"bytes,13,opt,name=declare,proto3,oneof"`
+}
+
+type Statement_Add struct {
+	Add *AddStatement `protobuf:"bytes,14,opt,name=add,proto3,oneof"`
+}
+
+type Statement_Esi struct {
+	Esi *EsiStatement `protobuf:"bytes,15,opt,name=esi,proto3,oneof"`
+}
+
+type Statement_Log struct {
+	Log *LogStatement `protobuf:"bytes,16,opt,name=log,proto3,oneof"`
+}
+
+type Statement_Goto struct {
+	Goto *GotoStatement `protobuf:"bytes,17,opt,name=goto,proto3,oneof"`
+}
+
+type Statement_Label struct {
+	Label *LabelStatement `protobuf:"bytes,18,opt,name=label,proto3,oneof"`
+}
+
+func (*Statement_Block) isStatement_Kind() {}
+
+func (*Statement_Expression) isStatement_Kind() {}
+
+func (*Statement_If) isStatement_Kind() {}
+
+func (*Statement_Set) isStatement_Kind() {}
+
+func (*Statement_Unset) isStatement_Kind() {}
+
+func (*Statement_Call) isStatement_Kind() {}
+
+func (*Statement_Return) isStatement_Kind() {}
+
+func (*Statement_Synthetic) isStatement_Kind() {}
+
+func (*Statement_Error) isStatement_Kind() {}
+
+func (*Statement_Restart) isStatement_Kind() {}
+
+func (*Statement_CSource) isStatement_Kind() {}
+
+func (*Statement_New) isStatement_Kind() {}
+
+func (*Statement_Declare) isStatement_Kind() {}
+
+func (*Statement_Add) isStatement_Kind() {}
+
+func (*Statement_Esi) isStatement_Kind() {}
+
+func (*Statement_Log) isStatement_Kind() {}
+
+func (*Statement_Goto) isStatement_Kind() {}
+
+func (*Statement_Label) isStatement_Kind() {}
+
+type BlockStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span        `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Statements []*Statement `protobuf:"bytes,2,rep,name=statements,proto3" json:"statements,omitempty"`
+}
+
+func (x *BlockStatement) Reset() {
+	*x = BlockStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockStatement) ProtoMessage() {}
+
+func (x *BlockStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockStatement.ProtoReflect.Descriptor instead.
+func (*BlockStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BlockStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *BlockStatement) GetStatements() []*Statement {
+	if x != nil {
+		return x.Statements
+	}
+	return nil
+}
+
+type ExpressionStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Expression *Expression `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+}
+
+func (x *ExpressionStatement) Reset() {
+	*x = ExpressionStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExpressionStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpressionStatement) ProtoMessage() {}
+
+func (x *ExpressionStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpressionStatement.ProtoReflect.Descriptor instead.
+func (*ExpressionStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExpressionStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ExpressionStatement) GetExpression() *Expression {
+	if x != nil {
+		return x.Expression
+	}
+	return nil
+}
+
+type IfStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span      *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Condition *Expression `protobuf:"bytes,2,opt,name=condition,proto3" json:"condition,omitempty"`
+	Then      *Statement  `protobuf:"bytes,3,opt,name=then,proto3" json:"then,omitempty"`
+	Else      *Statement  `protobuf:"bytes,4,opt,name=else,proto3" json:"else,omitempty"`
+	// elsif, elseif or elif for an else branch written with that keyword
+	Keyword string `protobuf:"bytes,5,opt,name=keyword,proto3" json:"keyword,omitempty"`
+}
+
+func (x *IfStatement) Reset() {
+	*x = IfStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IfStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IfStatement) ProtoMessage() {}
+
+func (x *IfStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IfStatement.ProtoReflect.Descriptor instead.
+func (*IfStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *IfStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *IfStatement) GetCondition() *Expression {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+func (x *IfStatement) GetThen() *Statement {
+	if x != nil {
+		return x.Then
+	}
+	return nil
+}
+
+func (x *IfStatement) GetElse() *Statement {
+	if x != nil {
+		return x.Else
+	}
+	return nil
+}
+
+func (x *IfStatement) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+type SetStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Variable *Expression `protobuf:"bytes,2,opt,name=variable,proto3" json:"variable,omitempty"`
+	Operator string      `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value    *Expression `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *SetStatement) Reset() {
+	*x = SetStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetStatement) ProtoMessage() {}
+
+func (x *SetStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetStatement.ProtoReflect.Descriptor instead.
+func (*SetStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SetStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *SetStatement) GetVariable() *Expression {
+	if x != nil {
+		return x.Variable
+	}
+	return nil
+}
+
+func (x *SetStatement) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *SetStatement) GetValue() *Expression {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type UnsetStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Variable *Expression `protobuf:"bytes,2,opt,name=variable,proto3" json:"variable,omitempty"`
+}
+
+func (x *UnsetStatement) Reset() {
+	*x = UnsetStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnsetStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsetStatement) ProtoMessage() {}
+
+func (x *UnsetStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsetStatement.ProtoReflect.Descriptor instead.
+func (*UnsetStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UnsetStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *UnsetStatement) GetVariable() *Expression {
+	if x != nil {
+		return x.Variable
+	}
+	return nil
+}
+
+type CallStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Function *Expression `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
+}
+
+func (x *CallStatement) Reset() {
+	*x = CallStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CallStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallStatement) ProtoMessage() {}
+
+func (x *CallStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallStatement.ProtoReflect.Descriptor instead.
+func (*CallStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CallStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *CallStatement) GetFunction() *Expression {
+	if x != nil {
+		return x.Function
+	}
+	return nil
+}
+
+type ReturnStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span   *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Action *Expression `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+}
+
+func (x *ReturnStatement) Reset() {
+	*x = ReturnStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReturnStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnStatement) ProtoMessage() {}
+
+func (x *ReturnStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnStatement.ProtoReflect.Descriptor instead.
+func (*ReturnStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReturnStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ReturnStatement) GetAction() *Expression {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+type SyntheticStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Response *Expression `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *SyntheticStatement) Reset() {
+	*x = SyntheticStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyntheticStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyntheticStatement) ProtoMessage() {}
+
+func (x *SyntheticStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyntheticStatement.ProtoReflect.Descriptor instead.
+func (*SyntheticStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SyntheticStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *SyntheticStatement) GetResponse() *Expression {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type ErrorStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Code     *Expression `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Response *Expression `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *ErrorStatement) Reset() {
+	*x = ErrorStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorStatement) ProtoMessage() {}
+
+func (x *ErrorStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorStatement.ProtoReflect.Descriptor instead.
+func (*ErrorStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ErrorStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ErrorStatement) GetCode() *Expression {
+	if x != nil {
+		return x.Code
+	}
+	return nil
+}
+
+func (x *ErrorStatement) GetResponse() *Expression {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type RestartStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+}
+
+func (x *RestartStatement) Reset() {
+	*x = RestartStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestartStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartStatement) ProtoMessage() {}
+
+func (x *RestartStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartStatement.ProtoReflect.Descriptor instead.
+func (*RestartStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RestartStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+type CSourceStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *CSourceStatement) Reset() {
+	*x = CSourceStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CSourceStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CSourceStatement) ProtoMessage() {}
+
+func (x *CSourceStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CSourceStatement.ProtoReflect.Descriptor instead.
+func (*CSourceStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CSourceStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *CSourceStatement) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type NewStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span        *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name        *Expression `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Constructor *Expression `protobuf:"bytes,3,opt,name=constructor,proto3" json:"constructor,omitempty"`
+}
+
+func (x *NewStatement) Reset() {
+	*x = NewStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewStatement) ProtoMessage() {}
+
+func (x *NewStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewStatement.ProtoReflect.Descriptor instead.
+func (*NewStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *NewStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *NewStatement) GetName() *Expression {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *NewStatement) GetConstructor() *Expression {
+	if x != nil {
+		return x.Constructor
+	}
+	return nil
+}
+
+type DeclareStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Variable *Expression `protobuf:"bytes,2,opt,name=variable,proto3" json:"variable,omitempty"`
+	Type     string      `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *DeclareStatement) Reset() {
+	*x = DeclareStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeclareStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclareStatement) ProtoMessage() {}
+
+func (x *DeclareStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclareStatement.ProtoReflect.Descriptor instead.
+func (*DeclareStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeclareStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *DeclareStatement) GetVariable() *Expression {
+	if x != nil {
+		return x.Variable
+	}
+	return nil
+}
+
+func (x *DeclareStatement) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type AddStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Variable *Expression `protobuf:"bytes,2,opt,name=variable,proto3" json:"variable,omitempty"`
+	Value    *Expression `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *AddStatement) Reset() {
+	*x = AddStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddStatement) ProtoMessage() {}
+
+func (x *AddStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddStatement.ProtoReflect.Descriptor instead.
+func (*AddStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *AddStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *AddStatement) GetVariable() *Expression {
+	if x != nil {
+		return x.Variable
+	}
+	return nil
+}
+
+func (x *AddStatement) GetValue() *Expression {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type EsiStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+}
+
+func (x *EsiStatement) Reset() {
+	*x = EsiStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EsiStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EsiStatement) ProtoMessage() {}
+
+func (x *EsiStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EsiStatement.ProtoReflect.Descriptor instead.
+func (*EsiStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *EsiStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+type LogStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span    *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Message *Expression `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *LogStatement) Reset() {
+	*x = LogStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogStatement) ProtoMessage() {}
+
+func (x *LogStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogStatement.ProtoReflect.Descriptor instead.
+func (*LogStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *LogStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *LogStatement) GetMessage() *Expression {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type GotoStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Label string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+}
+
+func (x *GotoStatement) Reset() {
+	*x = GotoStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GotoStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GotoStatement) ProtoMessage() {}
+
+func (x *GotoStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GotoStatement.ProtoReflect.Descriptor instead.
+func (*GotoStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GotoStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *GotoStatement) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type LabelStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *LabelStatement) Reset() {
+	*x = LabelStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LabelStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelStatement) ProtoMessage() {}
+
+func (x *LabelStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelStatement.ProtoReflect.Descriptor instead.
+func (*LabelStatement) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LabelStatement) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *LabelStatement) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Expression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//	*Expression_Binary
+	//	*Expression_Unary
+	//	*Expression_Call
+	//	*Expression_Member
+	//	*Expression_Index
+	//	*Expression_Parenthesized
+	//	*Expression_RegexMatch
+	//	*Expression_Assignment
+	//	*Expression_Update
+	//	*Expression_Array
+	//	*Expression_Object
+	//	*Expression_Variable
+	//	*Expression_Time
+	//	*Expression_Ip
+	//	*Expression_Error
+	//	*Expression_Identifier
+	//	*Expression_String_
+	//	*Expression_Integer
+	//	*Expression_Float
+	//	*Expression_Boolean
+	//	*Expression_Duration
+	Kind isExpression_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *Expression) Reset() {
+	*x = Expression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Expression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expression) ProtoMessage() {}
+
+func (x *Expression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expression.ProtoReflect.Descriptor instead.
+func (*Expression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{44}
+}
+
+func (m *Expression) GetKind() isExpression_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *Expression) GetBinary() *BinaryExpression {
+	if x, ok := x.GetKind().(*Expression_Binary); ok {
+		return x.Binary
+	}
+	return nil
+}
+
+func (x *Expression) GetUnary() *UnaryExpression {
+	if x, ok := x.GetKind().(*Expression_Unary); ok {
+		return x.Unary
+	}
+	return nil
+}
+
+func (x *Expression) GetCall() *CallExpression {
+	if x, ok := x.GetKind().(*Expression_Call); ok {
+		return x.Call
+	}
+	return nil
+}
+
+func (x *Expression) GetMember() *MemberExpression {
+	if x, ok := x.GetKind().(*Expression_Member); ok {
+		return x.Member
+	}
+	return nil
+}
+
+func (x *Expression) GetIndex() *IndexExpression {
+	if x, ok := x.GetKind().(*Expression_Index); ok {
+		return x.Index
+	}
+	return nil
+}
+
+func (x *Expression) GetParenthesized() *ParenthesizedExpression {
+	if x, ok := x.GetKind().(*Expression_Parenthesized); ok {
+		return x.Parenthesized
+	}
+	return nil
+}
+
+func (x *Expression) GetRegexMatch() *RegexMatchExpression {
+	if x, ok := x.GetKind().(*Expression_RegexMatch); ok {
+		return x.RegexMatch
+	}
+	return nil
+}
+
+func (x *Expression) GetAssignment() *AssignmentExpression {
+	if x, ok := x.GetKind().(*Expression_Assignment); ok {
+		return x.Assignment
+	}
+	return nil
+}
+
+func (x *Expression) GetUpdate() *UpdateExpression {
+	if x, ok := x.GetKind().(*Expression_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *Expression) GetArray() *ArrayExpression {
+	if x, ok := x.GetKind().(*Expression_Array); ok {
+		return x.Array
+	}
+	return nil
+}
+
+func (x *Expression) GetObject() *ObjectExpression {
+	if x, ok := x.GetKind().(*Expression_Object); ok {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *Expression) GetVariable() *VariableExpression {
+	if x, ok := x.GetKind().(*Expression_Variable); ok {
+		return x.Variable
+	}
+	return nil
+}
+
+func (x *Expression) GetTime() *TimeExpression {
+	if x, ok := x.GetKind().(*Expression_Time); ok {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Expression) GetIp() *IPExpression {
+	if x, ok := x.GetKind().(*Expression_Ip); ok {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *Expression) GetError() *ErrorExpression {
+	if x, ok := x.GetKind().(*Expression_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *Expression) GetIdentifier() *Identifier {
+	if x, ok := x.GetKind().(*Expression_Identifier); ok {
+		return x.Identifier
+	}
+	return nil
+}
+
+func (x *Expression) GetString_() *StringLiteral {
+	if x, ok := x.GetKind().(*Expression_String_); ok {
+		return x.String_
+	}
+	return nil
+}
+
+func (x *Expression) GetInteger() *IntegerLiteral {
+	if x, ok := x.GetKind().(*Expression_Integer); ok {
+		return x.Integer
+	}
+	return nil
+}
+
+func (x *Expression) GetFloat() *FloatLiteral {
+	if x, ok := x.GetKind().(*Expression_Float); ok {
+		return x.Float
+	}
+	return nil
+}
+
+func (x *Expression) GetBoolean() *BooleanLiteral {
+	if x, ok := x.GetKind().(*Expression_Boolean); ok {
+		return x.Boolean
+	}
+	return nil
+}
+
+func (x *Expression) GetDuration() *DurationLiteral {
+	if x, ok := x.GetKind().(*Expression_Duration); ok {
+		return x.Duration
+	}
+	return nil
+}
+
+type isExpression_Kind interface {
+	isExpression_Kind()
+}
+
+type Expression_Binary struct {
+	Binary *BinaryExpression `protobuf:"bytes,1,opt,name=binary,proto3,oneof"`
+}
+
+type Expression_Unary struct {
+	Unary *UnaryExpression `protobuf:"bytes,2,opt,name=unary,proto3,oneof"`
+}
+
+type Expression_Call struct {
+	Call *CallExpression `protobuf:"bytes,3,opt,name=call,proto3,oneof"`
+}
+
+type Expression_Member struct {
+	Member *MemberExpression `protobuf:"bytes,4,opt,name=member,proto3,oneof"`
+}
+
+type Expression_Index struct {
+	Index *IndexExpression `protobuf:"bytes,5,opt,name=index,proto3,oneof"`
+}
+
+type Expression_Parenthesized struct {
+	Parenthesized *ParenthesizedExpression `protobuf:"bytes,6,opt,name=parenthesized,proto3,oneof"`
+}
+
+type Expression_RegexMatch struct {
+	RegexMatch *RegexMatchExpression `protobuf:"bytes,7,opt,name=regex_match,json=regexMatch,proto3,oneof"`
+}
+
+type Expression_Assignment struct {
+	Assignment *AssignmentExpression `protobuf:"bytes,8,opt,name=assignment,proto3,oneof"`
+}
+
+type Expression_Update struct {
+	Update *UpdateExpression `protobuf:"bytes,9,opt,name=update,proto3,oneof"`
+}
+
+type Expression_Array struct {
+	Array *ArrayExpression `protobuf:"bytes,10,opt,name=array,proto3,oneof"`
+}
+
+type Expression_Object struct {
+	Object *ObjectExpression `protobuf:"bytes,11,opt,name=object,proto3,oneof"`
+}
+
+type Expression_Variable struct {
+	Variable *VariableExpression `protobuf:"bytes,12,opt,name=variable,proto3,oneof"`
+}
+
+type Expression_Time struct {
+	Time *TimeExpression `protobuf:"bytes,13,opt,name=time,proto3,oneof"`
+}
+
+type Expression_Ip struct {
+	Ip *IPExpression `protobuf:"bytes,14,opt,name=ip,proto3,oneof"`
+}
+
+type Expression_Error struct {
+	Error *ErrorExpression `protobuf:"bytes,15,opt,name=error,proto3,oneof"`
+}
+
+type Expression_Identifier struct {
+	Identifier *Identifier `protobuf:"bytes,16,opt,name=identifier,proto3,oneof"`
+}
+
+type Expression_String_ struct {
+	String_ *StringLiteral `protobuf:"bytes,17,opt,name=string,proto3,oneof"`
+}
+
+type Expression_Integer struct {
+	Integer *IntegerLiteral `protobuf:"bytes,18,opt,name=integer,proto3,oneof"`
+}
+
+type Expression_Float struct {
+	Float *FloatLiteral `protobuf:"bytes,19,opt,name=float,proto3,oneof"`
+}
+
+type Expression_Boolean struct {
+	Boolean *BooleanLiteral `protobuf:"bytes,20,opt,name=boolean,proto3,oneof"`
+}
+
+type Expression_Duration struct {
+	Duration *DurationLiteral `protobuf:"bytes,21,opt,name=duration,proto3,oneof"`
+}
+
+func (*Expression_Binary) isExpression_Kind() {}
+
+func (*Expression_Unary) isExpression_Kind() {}
+
+func (*Expression_Call) isExpression_Kind() {}
+
+func (*Expression_Member) isExpression_Kind() {}
+
+func (*Expression_Index) isExpression_Kind() {}
+
+func (*Expression_Parenthesized) isExpression_Kind() {}
+
+func (*Expression_RegexMatch) isExpression_Kind() {}
+
+func (*Expression_Assignment) isExpression_Kind() {}
+
+func (*Expression_Update) isExpression_Kind() {}
+
+func (*Expression_Array) isExpression_Kind() {}
+
+func (*Expression_Object) isExpression_Kind() {}
+
+func (*Expression_Variable) isExpression_Kind() {}
+
+func (*Expression_Time) isExpression_Kind() {}
+
+func (*Expression_Ip) isExpression_Kind() {}
+
+func (*Expression_Error) isExpression_Kind() {}
+
+func (*Expression_Identifier) isExpression_Kind() {}
+
+func (*Expression_String_) isExpression_Kind() {}
+
+func (*Expression_Integer) isExpression_Kind() {}
+
+func (*Expression_Float) isExpression_Kind() {}
+
+func (*Expression_Boolean) isExpression_Kind() {}
+
+func (*Expression_Duration) isExpression_Kind() {}
+
+type BinaryExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Left     *Expression `protobuf:"bytes,2,opt,name=left,proto3" json:"left,omitempty"`
+	Operator string      `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	Right    *Expression `protobuf:"bytes,4,opt,name=right,proto3" json:"right,omitempty"`
+}
+
+func (x *BinaryExpression) Reset() {
+	*x = BinaryExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BinaryExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BinaryExpression) ProtoMessage() {}
+
+func (x *BinaryExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BinaryExpression.ProtoReflect.Descriptor instead.
+func (*BinaryExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *BinaryExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *BinaryExpression) GetLeft() *Expression {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+func (x *BinaryExpression) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *BinaryExpression) GetRight() *Expression {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+type UnaryExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Operator string      `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Operand  *Expression `protobuf:"bytes,3,opt,name=operand,proto3" json:"operand,omitempty"`
+}
+
+func (x *UnaryExpression) Reset() {
+	*x = UnaryExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnaryExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnaryExpression) ProtoMessage() {}
+
+func (x *UnaryExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnaryExpression.ProtoReflect.Descriptor instead.
+func (*UnaryExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *UnaryExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *UnaryExpression) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *UnaryExpression) GetOperand() *Expression {
+	if x != nil {
+		return x.Operand
+	}
+	return nil
+}
+
+type CallExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span           *Span                  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Function       *Expression            `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
+	Arguments      []*Expression          `protobuf:"bytes,3,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	NamedArguments map[string]*Expression `protobuf:"bytes,4,rep,name=named_arguments,json=namedArguments,proto3" json:"named_arguments,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *CallExpression) Reset() {
+	*x = CallExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CallExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallExpression) ProtoMessage() {}
+
+func (x *CallExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallExpression.ProtoReflect.Descriptor instead.
+func (*CallExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CallExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *CallExpression) GetFunction() *Expression {
+	if x != nil {
+		return x.Function
+	}
+	return nil
+}
+
+func (x *CallExpression) GetArguments() []*Expression {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+func (x *CallExpression) GetNamedArguments() map[string]*Expression {
+	if x != nil {
+		return x.NamedArguments
+	}
+	return nil
+}
+
+type MemberExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Object   *Expression `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	Property *Expression `protobuf:"bytes,3,opt,name=property,proto3" json:"property,omitempty"`
+}
+
+func (x *MemberExpression) Reset() {
+	*x = MemberExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MemberExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberExpression) ProtoMessage() {}
+
+func (x *MemberExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberExpression.ProtoReflect.Descriptor instead.
+func (*MemberExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *MemberExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *MemberExpression) GetObject() *Expression {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *MemberExpression) GetProperty() *Expression {
+	if x != nil {
+		return x.Property
+	}
+	return nil
+}
+
+type IndexExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span   *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Object *Expression `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	Index  *Expression `protobuf:"bytes,3,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (x *IndexExpression) Reset() {
+	*x = IndexExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexExpression) ProtoMessage() {}
+
+func (x *IndexExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexExpression.ProtoReflect.Descriptor instead.
+func (*IndexExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *IndexExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *IndexExpression) GetObject() *Expression {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *IndexExpression) GetIndex() *Expression {
+	if x != nil {
+		return x.Index
+	}
+	return nil
+}
+
+type ParenthesizedExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Expression *Expression `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+}
+
+func (x *ParenthesizedExpression) Reset() {
+	*x = ParenthesizedExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParenthesizedExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParenthesizedExpression) ProtoMessage() {}
+
+func (x *ParenthesizedExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParenthesizedExpression.ProtoReflect.Descriptor instead.
+func (*ParenthesizedExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ParenthesizedExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ParenthesizedExpression) GetExpression() *Expression {
+	if x != nil {
+		return x.Expression
+	}
+	return nil
+}
+
+type RegexMatchExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Left     *Expression `protobuf:"bytes,2,opt,name=left,proto3" json:"left,omitempty"`
+	Operator string      `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	Right    *Expression `protobuf:"bytes,4,opt,name=right,proto3" json:"right,omitempty"`
+}
+
+func (x *RegexMatchExpression) Reset() {
+	*x = RegexMatchExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegexMatchExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegexMatchExpression) ProtoMessage() {}
+
+func (x *RegexMatchExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegexMatchExpression.ProtoReflect.Descriptor instead.
+func (*RegexMatchExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RegexMatchExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *RegexMatchExpression) GetLeft() *Expression {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+func (x *RegexMatchExpression) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *RegexMatchExpression) GetRight() *Expression {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+type AssignmentExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Left     *Expression `protobuf:"bytes,2,opt,name=left,proto3" json:"left,omitempty"`
+	Operator string      `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	Right    *Expression `protobuf:"bytes,4,opt,name=right,proto3" json:"right,omitempty"`
+}
+
+func (x *AssignmentExpression) Reset() {
+	*x = AssignmentExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssignmentExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentExpression) ProtoMessage() {}
+
+func (x *AssignmentExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentExpression.ProtoReflect.Descriptor instead.
+func (*AssignmentExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *AssignmentExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *AssignmentExpression) GetLeft() *Expression {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+func (x *AssignmentExpression) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *AssignmentExpression) GetRight() *Expression {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+type UpdateExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Operator string      `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Operand  *Expression `protobuf:"bytes,3,opt,name=operand,proto3" json:"operand,omitempty"`
+	Prefix   bool        `protobuf:"varint,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (x *UpdateExpression) Reset() {
+	*x = UpdateExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateExpression) ProtoMessage() {}
+
+func (x *UpdateExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateExpression.ProtoReflect.Descriptor instead.
+func (*UpdateExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *UpdateExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *UpdateExpression) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *UpdateExpression) GetOperand() *Expression {
+	if x != nil {
+		return x.Operand
+	}
+	return nil
+}
+
+func (x *UpdateExpression) GetPrefix() bool {
+	if x != nil {
+		return x.Prefix
+	}
+	return false
+}
+
+type ArrayExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span     *Span         `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Elements []*Expression `protobuf:"bytes,2,rep,name=elements,proto3" json:"elements,omitempty"`
+}
+
+func (x *ArrayExpression) Reset() {
+	*x = ArrayExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArrayExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArrayExpression) ProtoMessage() {}
+
+func (x *ArrayExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArrayExpression.ProtoReflect.Descriptor instead.
+func (*ArrayExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ArrayExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ArrayExpression) GetElements() []*Expression {
+	if x != nil {
+		return x.Elements
+	}
+	return nil
+}
+
+type ObjectExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span       *Span             `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Properties []*ObjectProperty `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty"`
+}
+
+func (x *ObjectExpression) Reset() {
+	*x = ObjectExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObjectExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectExpression) ProtoMessage() {}
+
+func (x *ObjectExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectExpression.ProtoReflect.Descriptor instead.
+func (*ObjectExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ObjectExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ObjectExpression) GetProperties() []*ObjectProperty {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type ObjectProperty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span       `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Key   *Expression `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value *Expression `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *ObjectProperty) Reset() {
+	*x = ObjectProperty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObjectProperty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectProperty) ProtoMessage() {}
+
+func (x *ObjectProperty) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectProperty.ProtoReflect.Descriptor instead.
+func (*ObjectProperty) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ObjectProperty) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ObjectProperty) GetKey() *Expression {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *ObjectProperty) GetValue() *Expression {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type VariableExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *VariableExpression) Reset() {
+	*x = VariableExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VariableExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariableExpression) ProtoMessage() {}
+
+func (x *VariableExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariableExpression.ProtoReflect.Descriptor instead.
+func (*VariableExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *VariableExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *VariableExpression) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type TimeExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span           `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value string          `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Parts []*DurationPart `protobuf:"bytes,3,rep,name=parts,proto3" json:"parts,omitempty"`
+}
+
+func (x *TimeExpression) Reset() {
+	*x = TimeExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimeExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeExpression) ProtoMessage() {}
+
+func (x *TimeExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeExpression.ProtoReflect.Descriptor instead.
+func (*TimeExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *TimeExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *TimeExpression) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TimeExpression) GetParts() []*DurationPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+type DurationPart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Unit   string `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+}
+
+func (x *DurationPart) Reset() {
+	*x = DurationPart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DurationPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DurationPart) ProtoMessage() {}
+
+func (x *DurationPart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DurationPart.ProtoReflect.Descriptor instead.
+func (*DurationPart) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *DurationPart) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *DurationPart) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type IPExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *IPExpression) Reset() {
+	*x = IPExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPExpression) ProtoMessage() {}
+
+func (x *IPExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPExpression.ProtoReflect.Descriptor instead.
+func (*IPExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *IPExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *IPExpression) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ErrorExpression struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span    *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ErrorExpression) Reset() {
+	*x = ErrorExpression{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorExpression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorExpression) ProtoMessage() {}
+
+func (x *ErrorExpression) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorExpression.ProtoReflect.Descriptor instead.
+func (*ErrorExpression) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ErrorExpression) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ErrorExpression) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Identifier struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Identifier) Reset() {
+	*x = Identifier{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Identifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Identifier) ProtoMessage() {}
+
+func (x *Identifier) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Identifier.ProtoReflect.Descriptor instead.
+func (*Identifier) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *Identifier) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *Identifier) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type StringLiteral struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Set for long strings, written {"..."} or """..."""
+	Long bool `protobuf:"varint,3,opt,name=long,proto3" json:"long,omitempty"`
+}
+
+func (x *StringLiteral) Reset() {
+	*x = StringLiteral{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StringLiteral) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringLiteral) ProtoMessage() {}
+
+func (x *StringLiteral) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringLiteral.ProtoReflect.Descriptor instead.
+func (*StringLiteral) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *StringLiteral) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *StringLiteral) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *StringLiteral) GetLong() bool {
+	if x != nil {
+		return x.Long
+	}
+	return false
+}
+
+type IntegerLiteral struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value int64 `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *IntegerLiteral) Reset() {
+	*x = IntegerLiteral{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IntegerLiteral) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntegerLiteral) ProtoMessage() {}
+
+func (x *IntegerLiteral) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntegerLiteral.ProtoReflect.Descriptor instead.
+func (*IntegerLiteral) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *IntegerLiteral) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *IntegerLiteral) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type FloatLiteral struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span   `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value float64 `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *FloatLiteral) Reset() {
+	*x = FloatLiteral{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FloatLiteral) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloatLiteral) ProtoMessage() {}
+
+func (x *FloatLiteral) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloatLiteral.ProtoReflect.Descriptor instead.
+func (*FloatLiteral) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *FloatLiteral) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *FloatLiteral) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type BooleanLiteral struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value bool  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *BooleanLiteral) Reset() {
+	*x = BooleanLiteral{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BooleanLiteral) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BooleanLiteral) ProtoMessage() {}
+
+func (x *BooleanLiteral) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BooleanLiteral.ProtoReflect.Descriptor instead.
+func (*BooleanLiteral) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *BooleanLiteral) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *BooleanLiteral) GetValue() bool {
+	if x != nil {
+		return x.Value
+	}
+	return false
+}
+
+type DurationLiteral struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Span  *Span  `protobuf:"bytes,1,opt,name=span,proto3" json:"span,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *DurationLiteral) Reset() {
+	*x = DurationLiteral{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DurationLiteral) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DurationLiteral) ProtoMessage() {}
+
+func (x *DurationLiteral) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vclparser_v1_vclparser_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DurationLiteral.ProtoReflect.Descriptor instead.
+func (*DurationLiteral) Descriptor() ([]byte, []int) {
+	return file_proto_vclparser_v1_vclparser_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *DurationLiteral) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *DurationLiteral) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+var File_proto_vclparser_v1_vclparser_proto protoreflect.FileDescriptor
+
+var file_proto_vclparser_v1_vclparser_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x22, 0x61, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x7c, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x07,
+	0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x12, 0x3a, 0x0a, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e,
+	0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x61, 0x67,
+	0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x0e, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x76, 0x61,
+	0x72, 0x6e, 0x69, 0x73, 0x68, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x76, 0x61, 0x72, 0x6e, 0x69, 0x73, 0x68, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x75, 0x6e, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x75, 0x6e, 0x72, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x22,
+	0x4d, 0x0a, 0x0f, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69,
+	0x63, 0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x73, 0x22, 0x91,
+	0x02, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x69,
+	0x6e, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61,
+	0x69, 0x6e, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x45, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x62, 0x61, 0x73, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0a, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x61, 0x78, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x44, 0x65, 0x70, 0x74, 0x68, 0x1a, 0x38, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x7a, 0x0a, 0x17, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a,
+	0x07, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x12, 0x2e,
+	0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x32,
+	0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x61,
+	0x69, 0x6e, 0x22, 0x9e, 0x02, 0x0a, 0x0a, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69,
+	0x63, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x32, 0x0a,
+	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x39, 0x0a, 0x0c, 0x65, 0x6e, 0x64, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0b, 0x65, 0x6e, 0x64, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x08,
+	0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x43, 0x68,
+	0x61, 0x69, 0x6e, 0x22, 0x6f, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c,
+	0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x65, 0x5f, 0x63, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x65, 0x43, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x22, 0x5e, 0x0a, 0x04, 0x53, 0x70, 0x61, 0x6e, 0x12, 0x2c, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x28, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x03, 0x65, 0x6e, 0x64, 0x22, 0xaf, 0x01, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x61, 0x6d,
+	0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70,
+	0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x3d, 0x0a, 0x0b, 0x76, 0x63, 0x6c, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x43, 0x4c,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x63, 0x6c, 0x52, 0x0a, 0x76, 0x63, 0x6c,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x0c, 0x64, 0x65, 0x63, 0x6c, 0x61,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x63,
+	0x6c, 0x61, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xea, 0x03, 0x0a, 0x0b, 0x44, 0x65, 0x63, 0x6c, 0x61,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0b, 0x76, 0x63, 0x6c, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x43, 0x4c, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x63, 0x6c, 0x48, 0x00, 0x52, 0x0a, 0x76, 0x63, 0x6c,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x06, 0x69, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x65, 0x63,
+	0x6c, 0x48, 0x00, 0x52, 0x06, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x35, 0x0a, 0x07, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x44, 0x65, 0x63, 0x6c, 0x48, 0x00, 0x52, 0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x44, 0x65, 0x63, 0x6c, 0x48, 0x00,
+	0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x72, 0x6f,
+	0x62, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44, 0x65, 0x63,
+	0x6c, 0x48, 0x00, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x61, 0x63,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x43, 0x4c, 0x44, 0x65, 0x63, 0x6c, 0x48, 0x00,
+	0x52, 0x03, 0x61, 0x63, 0x6c, 0x12, 0x29, 0x0a, 0x03, 0x73, 0x75, 0x62, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x75, 0x62, 0x44, 0x65, 0x63, 0x6c, 0x48, 0x00, 0x52, 0x03, 0x73, 0x75, 0x62,
+	0x12, 0x2f, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x61, 0x62, 0x6c, 0x65, 0x44, 0x65, 0x63, 0x6c, 0x48, 0x00, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x12, 0x38, 0x0a, 0x08, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x44, 0x65, 0x63, 0x6c, 0x48,
+	0x00, 0x52, 0x08, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x06, 0x0a, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x22, 0x52, 0x0a, 0x0e, 0x56, 0x43, 0x4c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x76, 0x0a, 0x0a, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d,
+	0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x22,
+	0x5d, 0x0a, 0x0b, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26,
+	0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e,
+	0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x6c,
+	0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x22, 0xb4,
+	0x01, 0x0a, 0x0b, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26,
+	0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e,
+	0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x6e, 0x61,
+	0x6d, 0x65, 0x5f, 0x70, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x73, 0x12, 0x36, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65,
+	0x72, 0x74, 0x69, 0x65, 0x73, 0x22, 0xb2, 0x01, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x44,
+	0x65, 0x63, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x31, 0x0a, 0x08, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x70, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6e, 0x61, 0x6d, 0x65, 0x50,
+	0x6f, 0x73, 0x12, 0x36, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a,
+	0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x22, 0x76, 0x0a, 0x08, 0x50, 0x72,
+	0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0xaa, 0x01, 0x0a, 0x07, 0x41, 0x43, 0x4c, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26,
+	0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e,
+	0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x6e, 0x61,
+	0x6d, 0x65, 0x5f, 0x70, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x73, 0x12, 0x30, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x43,
+	0x4c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22,
+	0xcf, 0x01, 0x0a, 0x08, 0x41, 0x43, 0x4c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x26, 0x0a, 0x04,
+	0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04,
+	0x73, 0x70, 0x61, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x12, 0x32,
+	0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x4c, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x22, 0xcb, 0x01, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x6e, 0x61, 0x6d,
+	0x65, 0x5f, 0x70, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x73, 0x12, 0x30, 0x0a, 0x04,
+	0x62, 0x6f, 0x64, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x1f,
+	0x0a, 0x0b, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x22,
+	0xcd, 0x01, 0x0a, 0x09, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x6e, 0x61, 0x6d,
+	0x65, 0x5f, 0x70, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22,
+	0x76, 0x0a, 0x0a, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x88, 0x02, 0x0a, 0x0c, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x44, 0x65, 0x63, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x70, 0x6f, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07,
+	0x6e, 0x61, 0x6d, 0x65, 0x50, 0x6f, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x36, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f,
+	0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65,
+	0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x42, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x52, 0x08, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e,
+	0x64, 0x73, 0x22, 0x71, 0x0a, 0x0f, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x61,
+	0x63, 0x6b, 0x65, 0x6e, 0x64, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x36, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65,
+	0x72, 0x74, 0x69, 0x65, 0x73, 0x22, 0xe3, 0x07, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x43, 0x0a, 0x0a, 0x65, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2b,
+	0x0a, 0x02, 0x69, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x66, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x02, 0x69, 0x66, 0x12, 0x2e, 0x0a, 0x03, 0x73,
+	0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12, 0x34, 0x0a, 0x05, 0x75,
+	0x6e, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x6e, 0x73, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x05, 0x75, 0x6e, 0x73, 0x65,
+	0x74, 0x12, 0x31, 0x0a, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x61, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04,
+	0x63, 0x61, 0x6c, 0x6c, 0x12, 0x37, 0x0a, 0x06, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x40, 0x0a,
+	0x09, 0x73, 0x79, 0x6e, 0x74, 0x68, 0x65, 0x74, 0x69, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x79, 0x6e, 0x74, 0x68, 0x65, 0x74, 0x69, 0x63, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x73, 0x79, 0x6e, 0x74, 0x68, 0x65, 0x74, 0x69, 0x63, 0x12,
+	0x34, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3a, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x3b, 0x0a, 0x08, 0x63, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x07, 0x63, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x2e,
+	0x0a, 0x03, 0x6e, 0x65, 0x77, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x77, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x65, 0x77, 0x12, 0x3a,
+	0x0a, 0x07, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48,
+	0x00, 0x52, 0x07, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x12, 0x2e, 0x0a, 0x03, 0x61, 0x64,
+	0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x03, 0x61, 0x64, 0x64, 0x12, 0x2e, 0x0a, 0x03, 0x65, 0x73,
+	0x69, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x73, 0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x03, 0x65, 0x73, 0x69, 0x12, 0x2e, 0x0a, 0x03, 0x6c, 0x6f,
+	0x67, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x03, 0x6c, 0x6f, 0x67, 0x12, 0x31, 0x0a, 0x04, 0x67, 0x6f,
+	0x74, 0x6f, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x6f, 0x74, 0x6f, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x04, 0x67, 0x6f, 0x74, 0x6f, 0x12, 0x34, 0x0a,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x71, 0x0a, 0x0e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x37, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x77,
+	0x0a, 0x13, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x38, 0x0a,
+	0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xe1, 0x01, 0x0a, 0x0b, 0x49, 0x66, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12,
+	0x36, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x63, 0x6f,
+	0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x68, 0x65, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04,
+	0x74, 0x68, 0x65, 0x6e, 0x12, 0x2b, 0x0a, 0x04, 0x65, 0x6c, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x04, 0x65, 0x6c, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x22, 0xb8, 0x01, 0x0a, 0x0c,
+	0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04,
+	0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04,
+	0x73, 0x70, 0x61, 0x6e, 0x12, 0x34, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x6e, 0x0a, 0x0e, 0x55, 0x6e, 0x73, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x12, 0x34, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76, 0x61,
+	0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x22, 0x6d, 0x0a, 0x0d, 0x43, 0x61, 0x6c, 0x6c, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12,
+	0x34, 0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x66, 0x75, 0x6e,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6b, 0x0a, 0x0f, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x12, 0x30, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x72, 0x0a, 0x12, 0x53, 0x79, 0x6e, 0x74, 0x68, 0x65, 0x74, 0x69, 0x63, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x12, 0x34, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x0e, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12,
+	0x34, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3a, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x22, 0x4e, 0x0a, 0x10, 0x43, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x22, 0xa0, 0x01, 0x0a, 0x0c, 0x4e, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x6f, 0x72, 0x22, 0x84, 0x01, 0x0a, 0x10, 0x44, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x12, 0x34, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x9c, 0x01, 0x0a, 0x0c,
+	0x41, 0x64, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04,
+	0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04,
+	0x73, 0x70, 0x61, 0x6e, 0x12, 0x34, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x36, 0x0a, 0x0c, 0x45, 0x73,
+	0x69, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70,
+	0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70,
+	0x61, 0x6e, 0x22, 0x6a, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x32, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x4d,
+	0x0a, 0x0d, 0x47, 0x6f, 0x74, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61,
+	0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x4c, 0x0a,
+	0x0e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61,
+	0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xe2, 0x09, 0x0a, 0x0a,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x06, 0x62, 0x69,
+	0x6e, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x62, 0x69,
+	0x6e, 0x61, 0x72, 0x79, 0x12, 0x35, 0x0a, 0x05, 0x75, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x05, 0x75, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x04, 0x63,
+	0x61, 0x6c, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x45, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x04, 0x63, 0x61, 0x6c, 0x6c, 0x12,
+	0x38, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48,
+	0x00, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x45, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x4d, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x68, 0x65, 0x73, 0x69, 0x7a, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x68, 0x65, 0x73,
+	0x69, 0x7a, 0x65, 0x64, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x0d, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x68, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x64, 0x12,
+	0x45, 0x0a, 0x0b, 0x72, 0x65, 0x67, 0x65, 0x78, 0x5f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x67, 0x65, 0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x45, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x67, 0x65,
+	0x78, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x44, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x76, 0x63, 0x6c,
+	0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00,
+	0x52, 0x0a, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x38, 0x0a, 0x06,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x35, 0x0a, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x72, 0x72, 0x61, 0x79, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79, 0x12, 0x38, 0x0a,
+	0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x3e, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c,
+	0x65, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x32, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x02, 0x69,
+	0x70, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x02, 0x69, 0x70, 0x12, 0x35, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61,
+	0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x3a, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x48, 0x00,
+	0x52, 0x0a, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x06,
+	0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x12, 0x38, 0x0a, 0x07, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x18, 0x12,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x4c, 0x69, 0x74, 0x65, 0x72,
+	0x61, 0x6c, 0x48, 0x00, 0x52, 0x07, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x12, 0x32, 0x0a,
+	0x05, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x6c, 0x6f, 0x61,
+	0x74, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x05, 0x66, 0x6c, 0x6f, 0x61,
+	0x74, 0x12, 0x38, 0x0a, 0x07, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c,
+	0x48, 0x00, 0x52, 0x07, 0x62, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x12, 0x3b, 0x0a, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c, 0x48, 0x00, 0x52, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64,
+	0x22, 0xb4, 0x01, 0x0a, 0x10, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x45, 0x78, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x2c, 0x0a,
+	0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x22, 0x89, 0x01, 0x0a, 0x0f, 0x55, 0x6e, 0x61, 0x72,
+	0x79, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73,
+	0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73,
+	0x70, 0x61, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x32, 0x0a, 0x07, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x6e, 0x64, 0x22, 0xde, 0x02, 0x0a, 0x0e, 0x43, 0x61, 0x6c, 0x6c, 0x45, 0x78, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x34,
+	0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x59, 0x0a, 0x0f,
+	0x6e, 0x61, 0x6d, 0x65, 0x64, 0x5f, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x41, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x6e, 0x61, 0x6d, 0x65, 0x64, 0x41, 0x72,
+	0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x5b, 0x0a, 0x13, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x41, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xa2, 0x01, 0x0a, 0x10, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61,
+	0x6e, 0x12, 0x30, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x22, 0x9b, 0x01, 0x0a, 0x0f, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x30, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x2e, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x7b, 0x0a, 0x17, 0x50, 0x61, 0x72, 0x65, 0x6e,
+	0x74, 0x68, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x64, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x38, 0x0a, 0x0a, 0x65, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0xb8, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x67, 0x65, 0x78, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x2c, 0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x6c,
+	0x65, 0x66, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x2e, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x22,
+	0xb8, 0x01, 0x0a, 0x14, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x45, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e,
+	0x12, 0x2c, 0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70,
+	0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x22, 0xa2, 0x01, 0x0a, 0x10, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61,
+	0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x12, 0x32, 0x0a, 0x07, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22,
+	0x6f, 0x0a, 0x0f, 0x41, 0x72, 0x72, 0x61, 0x79, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x34, 0x0a, 0x08, 0x65, 0x6c,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76,
+	0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x22, 0x78, 0x0a, 0x10, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x3c, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a,
+	0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x0e, 0x4f,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52,
+	0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x2a, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x22, 0x50, 0x0a, 0x12, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x0e, 0x54, 0x69, 0x6d, 0x65, 0x45, 0x78, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x72, 0x74, 0x52,
+	0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x22, 0x3a, 0x0a, 0x0c, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x50, 0x61, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e,
+	0x69, 0x74, 0x22, 0x4c, 0x0a, 0x0c, 0x49, 0x50, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0x53, 0x0a, 0x0f, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x48, 0x0a, 0x0a, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66,
+	0x69, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22,
+	0x61, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4c, 0x69, 0x74, 0x65, 0x72, 0x61, 0x6c,
+	0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70,
+	0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x6f,
+	0x6e, 0x67, 0x22, 0x4e, 0x0a, 0x0e, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x4c, 0x69, 0x74,
+	0x65, 0x72, 0x61, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0x4c, 0x0a, 0x0c, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x4c, 0x69, 0x74, 0x65, 0x72,
+	0x61, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0x4e, 0x0a, 0x0e, 0x42, 0x6f, 0x6f, 0x6c, 0x65, 0x61, 0x6e, 0x4c, 0x69, 0x74, 0x65, 0x72,
+	0x61, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0x4f, 0x0a, 0x0f, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x74, 0x65,
+	0x72, 0x61, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x2a, 0x47, 0x0a, 0x08, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a,
+	0x0e, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10,
+	0x00, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x41,
+	0x52, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45, 0x56, 0x45, 0x52,
+	0x49, 0x54, 0x59, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x02, 0x32, 0xf5, 0x01, 0x0a, 0x09, 0x56,
+	0x43, 0x4c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x72, 0x12, 0x40, 0x0a, 0x05, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x12, 0x1a, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x07, 0x41, 0x6e,
+	0x61, 0x6c, 0x79, 0x7a, 0x65, 0x12, 0x1c, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x73, 0x12, 0x24, 0x2e, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x49, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x76, 0x63,
+	0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c,
+	0x76, 0x65, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x70, 0x65, 0x72, 0x62, 0x75, 0x2f, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72,
+	0x2f, 0x76, 0x31, 0x3b, 0x76, 0x63, 0x6c, 0x70, 0x61, 0x72, 0x73, 0x65, 0x72, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_vclparser_v1_vclparser_proto_rawDescOnce sync.Once
+	file_proto_vclparser_v1_vclparser_proto_rawDescData = file_proto_vclparser_v1_vclparser_proto_rawDesc
+)
+
+func file_proto_vclparser_v1_vclparser_proto_rawDescGZIP() []byte {
+	file_proto_vclparser_v1_vclparser_proto_rawDescOnce.Do(func() {
+		file_proto_vclparser_v1_vclparser_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_vclparser_v1_vclparser_proto_rawDescData)
+	})
+	return file_proto_vclparser_v1_vclparser_proto_rawDescData
+}
+
+var file_proto_vclparser_v1_vclparser_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_vclparser_v1_vclparser_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
+var file_proto_vclparser_v1_vclparser_proto_goTypes = []any{
+	(Severity)(0),                   // 0: vclparser.v1.Severity
+	(*ParseRequest)(nil),            // 1: vclparser.v1.ParseRequest
+	(*ParseResponse)(nil),           // 2: vclparser.v1.ParseResponse
+	(*AnalyzeRequest)(nil),          // 3: vclparser.v1.AnalyzeRequest
+	(*AnalyzeResponse)(nil),         // 4: vclparser.v1.AnalyzeResponse
+	(*ResolveIncludesRequest)(nil),  // 5: vclparser.v1.ResolveIncludesRequest
+	(*ResolveIncludesResponse)(nil), // 6: vclparser.v1.ResolveIncludesResponse
+	(*Source)(nil),                  // 7: vclparser.v1.Source
+	(*Diagnostic)(nil),              // 8: vclparser.v1.Diagnostic
+	(*Position)(nil),                // 9: vclparser.v1.Position
+	(*Span)(nil),                    // 10: vclparser.v1.Span
+	(*Program)(nil),                 // 11: vclparser.v1.Program
+	(*Declaration)(nil),             // 12: vclparser.v1.Declaration
+	(*VCLVersionDecl)(nil),          // 13: vclparser.v1.VCLVersionDecl
+	(*ImportDecl)(nil),              // 14: vclparser.v1.ImportDecl
+	(*IncludeDecl)(nil),             // 15: vclparser.v1.IncludeDecl
+	(*BackendDecl)(nil),             // 16: vclparser.v1.BackendDecl
+	(*ProbeDecl)(nil),               // 17: vclparser.v1.ProbeDecl
+	(*Property)(nil),                // 18: vclparser.v1.Property
+	(*ACLDecl)(nil),                 // 19: vclparser.v1.ACLDecl
+	(*ACLEntry)(nil),                // 20: vclparser.v1.ACLEntry
+	(*SubDecl)(nil),                 // 21: vclparser.v1.SubDecl
+	(*TableDecl)(nil),               // 22: vclparser.v1.TableDecl
+	(*TableEntry)(nil),              // 23: vclparser.v1.TableEntry
+	(*DirectorDecl)(nil),            // 24: vclparser.v1.DirectorDecl
+	(*DirectorBackend)(nil),         // 25: vclparser.v1.DirectorBackend
+	(*Statement)(nil),               // 26: vclparser.v1.Statement
+	(*BlockStatement)(nil),          // 27: vclparser.v1.BlockStatement
+	(*ExpressionStatement)(nil),     // 28: vclparser.v1.ExpressionStatement
+	(*IfStatement)(nil),             // 29: vclparser.v1.IfStatement
+	(*SetStatement)(nil),            // 30: vclparser.v1.SetStatement
+	(*UnsetStatement)(nil),          // 31: vclparser.v1.UnsetStatement
+	(*CallStatement)(nil),           // 32: vclparser.v1.CallStatement
+	(*ReturnStatement)(nil),         // 33: vclparser.v1.ReturnStatement
+	(*SyntheticStatement)(nil),      // 34: vclparser.v1.SyntheticStatement
+	(*ErrorStatement)(nil),          // 35: vclparser.v1.ErrorStatement
+	(*RestartStatement)(nil),        // 36: vclparser.v1.RestartStatement
+	(*CSourceStatement)(nil),        // 37: vclparser.v1.CSourceStatement
+	(*NewStatement)(nil),            // 38: vclparser.v1.NewStatement
+	(*DeclareStatement)(nil),        // 39: vclparser.v1.DeclareStatement
+	(*AddStatement)(nil),            // 40: vclparser.v1.AddStatement
+	(*EsiStatement)(nil),            // 41: vclparser.v1.EsiStatement
+	(*LogStatement)(nil),            // 42: vclparser.v1.LogStatement
+	(*GotoStatement)(nil),           // 43: vclparser.v1.GotoStatement
+	(*LabelStatement)(nil),          // 44: vclparser.v1.LabelStatement
+	(*Expression)(nil),              // 45: vclparser.v1.Expression
+	(*BinaryExpression)(nil),        // 46: vclparser.v1.BinaryExpression
+	(*UnaryExpression)(nil),         // 47: vclparser.v1.UnaryExpression
+	(*CallExpression)(nil),          // 48: vclparser.v1.CallExpression
+	(*MemberExpression)(nil),        // 49: vclparser.v1.MemberExpression
+	(*IndexExpression)(nil),         // 50: vclparser.v1.IndexExpression
+	(*ParenthesizedExpression)(nil), // 51: vclparser.v1.ParenthesizedExpression
+	(*RegexMatchExpression)(nil),    // 52: vclparser.v1.RegexMatchExpression
+	(*AssignmentExpression)(nil),    // 53: vclparser.v1.AssignmentExpression
+	(*UpdateExpression)(nil),        // 54: vclparser.v1.UpdateExpression
+	(*ArrayExpression)(nil),         // 55: vclparser.v1.ArrayExpression
+	(*ObjectExpression)(nil),        // 56: vclparser.v1.ObjectExpression
+	(*ObjectProperty)(nil),          // 57: vclparser.v1.ObjectProperty
+	(*VariableExpression)(nil),      // 58: vclparser.v1.VariableExpression
+	(*TimeExpression)(nil),          // 59: vclparser.v1.TimeExpression
+	(*DurationPart)(nil),            // 60: vclparser.v1.DurationPart
+	(*IPExpression)(nil),            // 61: vclparser.v1.IPExpression
+	(*ErrorExpression)(nil),         // 62: vclparser.v1.ErrorExpression
+	(*Identifier)(nil),              // 63: vclparser.v1.Identifier
+	(*StringLiteral)(nil),           // 64: vclparser.v1.StringLiteral
+	(*IntegerLiteral)(nil),          // 65: vclparser.v1.IntegerLiteral
+	(*FloatLiteral)(nil),            // 66: vclparser.v1.FloatLiteral
+	(*BooleanLiteral)(nil),          // 67: vclparser.v1.BooleanLiteral
+	(*DurationLiteral)(nil),         // 68: vclparser.v1.DurationLiteral
+	nil,                             // 69: vclparser.v1.ResolveIncludesRequest.FilesEntry
+	nil,                             // 70: vclparser.v1.CallExpression.NamedArgumentsEntry
+}
+var file_proto_vclparser_v1_vclparser_proto_depIdxs = []int32{
+	11,  // 0: vclparser.v1.ParseResponse.program:type_name -> vclparser.v1.Program
+	8,   // 1: vclparser.v1.ParseResponse.diagnostics:type_name -> vclparser.v1.Diagnostic
+	8,   // 2: vclparser.v1.AnalyzeResponse.diagnostics:type_name -> vclparser.v1.Diagnostic
+	69,  // 3: vclparser.v1.ResolveIncludesRequest.files:type_name -> vclparser.v1.ResolveIncludesRequest.FilesEntry
+	11,  // 4: vclparser.v1.ResolveIncludesResponse.program:type_name -> vclparser.v1.Program
+	7,   // 5: vclparser.v1.ResolveIncludesResponse.sources:type_name -> vclparser.v1.Source
+	9,   // 6: vclparser.v1.Diagnostic.position:type_name -> vclparser.v1.Position
+	9,   // 7: vclparser.v1.Diagnostic.end_position:type_name -> vclparser.v1.Position
+	0,   // 8: vclparser.v1.Diagnostic.severity:type_name -> vclparser.v1.Severity
+	9,   // 9: vclparser.v1.Span.start:type_name -> vclparser.v1.Position
+	9,   // 10: vclparser.v1.Span.end:type_name -> vclparser.v1.Position
+	10,  // 11: vclparser.v1.Program.span:type_name -> vclparser.v1.Span
+	13,  // 12: vclparser.v1.Program.vcl_version:type_name -> vclparser.v1.VCLVersionDecl
+	12,  // 13: vclparser.v1.Program.declarations:type_name -> vclparser.v1.Declaration
+	13,  // 14: vclparser.v1.Declaration.vcl_version:type_name -> vclparser.v1.VCLVersionDecl
+	14,  // 15: vclparser.v1.Declaration.import:type_name -> vclparser.v1.ImportDecl
+	15,  // 16: vclparser.v1.Declaration.include:type_name -> vclparser.v1.IncludeDecl
+	16,  // 17: vclparser.v1.Declaration.backend:type_name -> vclparser.v1.BackendDecl
+	17,  // 18: vclparser.v1.Declaration.probe:type_name -> vclparser.v1.ProbeDecl
+	19,  // 19: vclparser.v1.Declaration.acl:type_name -> vclparser.v1.ACLDecl
+	21,  // 20: vclparser.v1.Declaration.sub:type_name -> vclparser.v1.SubDecl
+	22,  // 21: vclparser.v1.Declaration.table:type_name -> vclparser.v1.TableDecl
+	24,  // 22: vclparser.v1.Declaration.director:type_name -> vclparser.v1.DirectorDecl
+	10,  // 23: vclparser.v1.VCLVersionDecl.span:type_name -> vclparser.v1.Span
+	10,  // 24: vclparser.v1.ImportDecl.span:type_name -> vclparser.v1.Span
+	10,  // 25: vclparser.v1.IncludeDecl.span:type_name -> vclparser.v1.Span
+	10,  // 26: vclparser.v1.BackendDecl.span:type_name -> vclparser.v1.Span
+	9,   // 27: vclparser.v1.BackendDecl.name_pos:type_name -> vclparser.v1.Position
+	18,  // 28: vclparser.v1.BackendDecl.properties:type_name -> vclparser.v1.Property
+	10,  // 29: vclparser.v1.ProbeDecl.span:type_name -> vclparser.v1.Span
+	9,   // 30: vclparser.v1.ProbeDecl.name_pos:type_name -> vclparser.v1.Position
+	18,  // 31: vclparser.v1.ProbeDecl.properties:type_name -> vclparser.v1.Property
+	10,  // 32: vclparser.v1.Property.span:type_name -> vclparser.v1.Span
+	45,  // 33: vclparser.v1.Property.value:type_name -> vclparser.v1.Expression
+	10,  // 34: vclparser.v1.ACLDecl.span:type_name -> vclparser.v1.Span
+	9,   // 35: vclparser.v1.ACLDecl.name_pos:type_name -> vclparser.v1.Position
+	20,  // 36: vclparser.v1.ACLDecl.entries:type_name -> vclparser.v1.ACLEntry
+	10,  // 37: vclparser.v1.ACLEntry.span:type_name -> vclparser.v1.Span
+	45,  // 38: vclparser.v1.ACLEntry.network:type_name -> vclparser.v1.Expression
+	10,  // 39: vclparser.v1.SubDecl.span:type_name -> vclparser.v1.Span
+	9,   // 40: vclparser.v1.SubDecl.name_pos:type_name -> vclparser.v1.Position
+	27,  // 41: vclparser.v1.SubDecl.body:type_name -> vclparser.v1.BlockStatement
+	10,  // 42: vclparser.v1.TableDecl.span:type_name -> vclparser.v1.Span
+	9,   // 43: vclparser.v1.TableDecl.name_pos:type_name -> vclparser.v1.Position
+	23,  // 44: vclparser.v1.TableDecl.entries:type_name -> vclparser.v1.TableEntry
+	10,  // 45: vclparser.v1.TableEntry.span:type_name -> vclparser.v1.Span
+	45,  // 46: vclparser.v1.TableEntry.value:type_name -> vclparser.v1.Expression
+	10,  // 47: vclparser.v1.DirectorDecl.span:type_name -> vclparser.v1.Span
+	9,   // 48: vclparser.v1.DirectorDecl.name_pos:type_name -> vclparser.v1.Position
+	18,  // 49: vclparser.v1.DirectorDecl.properties:type_name -> vclparser.v1.Property
+	25,  // 50: vclparser.v1.DirectorDecl.backends:type_name -> vclparser.v1.DirectorBackend
+	10,  // 51: vclparser.v1.DirectorBackend.span:type_name -> vclparser.v1.Span
+	18,  // 52: vclparser.v1.DirectorBackend.properties:type_name -> vclparser.v1.Property
+	27,  // 53: vclparser.v1.Statement.block:type_name -> vclparser.v1.BlockStatement
+	28,  // 54: vclparser.v1.Statement.expression:type_name -> vclparser.v1.ExpressionStatement
+	29,  // 55: vclparser.v1.Statement.if:type_name -> vclparser.v1.IfStatement
+	30,  // 56: vclparser.v1.Statement.set:type_name -> vclparser.v1.SetStatement
+	31,  // 57: vclparser.v1.Statement.unset:type_name -> vclparser.v1.UnsetStatement
+	32,  // 58: vclparser.v1.Statement.call:type_name -> vclparser.v1.CallStatement
+	33,  // 59: vclparser.v1.Statement.return:type_name -> vclparser.v1.ReturnStatement
+	34,  // 60: vclparser.v1.Statement.synthetic:type_name -> vclparser.v1.SyntheticStatement
+	35,  // 61: vclparser.v1.Statement.error:type_name -> vclparser.v1.ErrorStatement
+	36,  // 62: vclparser.v1.Statement.restart:type_name -> vclparser.v1.RestartStatement
+	37,  // 63: vclparser.v1.Statement.c_source:type_name -> vclparser.v1.CSourceStatement
+	38,  // 64: vclparser.v1.Statement.new:type_name -> vclparser.v1.NewStatement
+	39,  // 65: vclparser.v1.Statement.declare:type_name -> vclparser.v1.DeclareStatement
+	40,  // 66: vclparser.v1.Statement.add:type_name -> vclparser.v1.AddStatement
+	41,  // 67: vclparser.v1.Statement.esi:type_name -> vclparser.v1.EsiStatement
+	42,  // 68: vclparser.v1.Statement.log:type_name -> vclparser.v1.LogStatement
+	43,  // 69: vclparser.v1.Statement.goto:type_name -> vclparser.v1.GotoStatement
+	44,  // 70: vclparser.v1.Statement.label:type_name -> vclparser.v1.LabelStatement
+	10,  // 71: vclparser.v1.BlockStatement.span:type_name -> vclparser.v1.Span
+	26,  // 72: vclparser.v1.BlockStatement.statements:type_name -> vclparser.v1.Statement
+	10,  // 73: vclparser.v1.ExpressionStatement.span:type_name -> vclparser.v1.Span
+	45,  // 74: vclparser.v1.ExpressionStatement.expression:type_name -> vclparser.v1.Expression
+	10,  // 75: vclparser.v1.IfStatement.span:type_name -> vclparser.v1.Span
+	45,  // 76: vclparser.v1.IfStatement.condition:type_name -> vclparser.v1.Expression
+	26,  // 77: vclparser.v1.IfStatement.then:type_name -> vclparser.v1.Statement
+	26,  // 78: vclparser.v1.IfStatement.else:type_name -> vclparser.v1.Statement
+	10,  // 79: vclparser.v1.SetStatement.span:type_name -> vclparser.v1.Span
+	45,  // 80: vclparser.v1.SetStatement.variable:type_name -> vclparser.v1.Expression
+	45,  // 81: vclparser.v1.SetStatement.value:type_name -> vclparser.v1.Expression
+	10,  // 82: vclparser.v1.UnsetStatement.span:type_name -> vclparser.v1.Span
+	45,  // 83: vclparser.v1.UnsetStatement.variable:type_name -> vclparser.v1.Expression
+	10,  // 84: vclparser.v1.CallStatement.span:type_name -> vclparser.v1.Span
+	45,  // 85: vclparser.v1.CallStatement.function:type_name -> vclparser.v1.Expression
+	10,  // 86: vclparser.v1.ReturnStatement.span:type_name -> vclparser.v1.Span
+	45,  // 87: vclparser.v1.ReturnStatement.action:type_name -> vclparser.v1.Expression
+	10,  // 88: vclparser.v1.SyntheticStatement.span:type_name -> vclparser.v1.Span
+	45,  // 89: vclparser.v1.SyntheticStatement.response:type_name -> vclparser.v1.Expression
+	10,  // 90: vclparser.v1.ErrorStatement.span:type_name -> vclparser.v1.Span
+	45,  // 91: vclparser.v1.ErrorStatement.code:type_name -> vclparser.v1.Expression
+	45,  // 92: vclparser.v1.ErrorStatement.response:type_name -> vclparser.v1.Expression
+	10,  // 93: vclparser.v1.RestartStatement.span:type_name -> vclparser.v1.Span
+	10,  // 94: vclparser.v1.CSourceStatement.span:type_name -> vclparser.v1.Span
+	10,  // 95: vclparser.v1.NewStatement.span:type_name -> vclparser.v1.Span
+	45,  // 96: vclparser.v1.NewStatement.name:type_name -> vclparser.v1.Expression
+	45,  // 97: vclparser.v1.NewStatement.constructor:type_name -> vclparser.v1.Expression
+	10,  // 98: vclparser.v1.DeclareStatement.span:type_name -> vclparser.v1.Span
+	45,  // 99: vclparser.v1.DeclareStatement.variable:type_name -> vclparser.v1.Expression
+	10,  // 100: vclparser.v1.AddStatement.span:type_name -> vclparser.v1.Span
+	45,  // 101: vclparser.v1.AddStatement.variable:type_name -> vclparser.v1.Expression
+	45,  // 102: vclparser.v1.AddStatement.value:type_name -> vclparser.v1.Expression
+	10,  // 103: vclparser.v1.EsiStatement.span:type_name -> vclparser.v1.Span
+	10,  // 104: vclparser.v1.LogStatement.span:type_name -> vclparser.v1.Span
+	45,  // 105: vclparser.v1.LogStatement.message:type_name -> vclparser.v1.Expression
+	10,  // 106: vclparser.v1.GotoStatement.span:type_name -> vclparser.v1.Span
+	10,  // 107: vclparser.v1.LabelStatement.span:type_name -> vclparser.v1.Span
+	46,  // 108: vclparser.v1.Expression.binary:type_name -> vclparser.v1.BinaryExpression
+	47,  // 109: vclparser.v1.Expression.unary:type_name -> vclparser.v1.UnaryExpression
+	48,  // 110: vclparser.v1.Expression.call:type_name -> vclparser.v1.CallExpression
+	49,  // 111: vclparser.v1.Expression.member:type_name -> vclparser.v1.MemberExpression
+	50,  // 112: vclparser.v1.Expression.index:type_name -> vclparser.v1.IndexExpression
+	51,  // 113: vclparser.v1.Expression.parenthesized:type_name -> vclparser.v1.ParenthesizedExpression
+	52,  // 114: vclparser.v1.Expression.regex_match:type_name -> vclparser.v1.RegexMatchExpression
+	53,  // 115: vclparser.v1.Expression.assignment:type_name -> vclparser.v1.AssignmentExpression
+	54,  // 116: vclparser.v1.Expression.update:type_name -> vclparser.v1.UpdateExpression
+	55,  // 117: vclparser.v1.Expression.array:type_name -> vclparser.v1.ArrayExpression
+	56,  // 118: vclparser.v1.Expression.object:type_name -> vclparser.v1.ObjectExpression
+	58,  // 119: vclparser.v1.Expression.variable:type_name -> vclparser.v1.VariableExpression
+	59,  // 120: vclparser.v1.Expression.time:type_name -> vclparser.v1.TimeExpression
+	61,  // 121: vclparser.v1.Expression.ip:type_name -> vclparser.v1.IPExpression
+	62,  // 122: vclparser.v1.Expression.error:type_name -> vclparser.v1.ErrorExpression
+	63,  // 123: vclparser.v1.Expression.identifier:type_name -> vclparser.v1.Identifier
+	64,  // 124: vclparser.v1.Expression.string:type_name -> vclparser.v1.StringLiteral
+	65,  // 125: vclparser.v1.Expression.integer:type_name -> vclparser.v1.IntegerLiteral
+	66,  // 126: vclparser.v1.Expression.float:type_name -> vclparser.v1.FloatLiteral
+	67,  // 127: vclparser.v1.Expression.boolean:type_name -> vclparser.v1.BooleanLiteral
+	68,  // 128: vclparser.v1.Expression.duration:type_name -> vclparser.v1.DurationLiteral
+	10,  // 129: vclparser.v1.BinaryExpression.span:type_name -> vclparser.v1.Span
+	45,  // 130: vclparser.v1.BinaryExpression.left:type_name -> vclparser.v1.Expression
+	45,  // 131: vclparser.v1.BinaryExpression.right:type_name -> vclparser.v1.Expression
+	10,  // 132: vclparser.v1.UnaryExpression.span:type_name -> vclparser.v1.Span
+	45,  // 133: vclparser.v1.UnaryExpression.operand:type_name -> vclparser.v1.Expression
+	10,  // 134: vclparser.v1.CallExpression.span:type_name -> vclparser.v1.Span
+	45,  // 135: vclparser.v1.CallExpression.function:type_name -> vclparser.v1.Expression
+	45,  // 136: vclparser.v1.CallExpression.arguments:type_name -> vclparser.v1.Expression
+	70,  // 137: vclparser.v1.CallExpression.named_arguments:type_name -> vclparser.v1.CallExpression.NamedArgumentsEntry
+	10,  // 138: vclparser.v1.MemberExpression.span:type_name -> vclparser.v1.Span
+	45,  // 139: vclparser.v1.MemberExpression.object:type_name -> vclparser.v1.Expression
+	45,  // 140: vclparser.v1.MemberExpression.property:type_name -> vclparser.v1.Expression
+	10,  // 141: vclparser.v1.IndexExpression.span:type_name -> vclparser.v1.Span
+	45,  // 142: vclparser.v1.IndexExpression.object:type_name -> vclparser.v1.Expression
+	45,  // 143: vclparser.v1.IndexExpression.index:type_name -> vclparser.v1.Expression
+	10,  // 144: vclparser.v1.ParenthesizedExpression.span:type_name -> vclparser.v1.Span
+	45,  // 145: vclparser.v1.ParenthesizedExpression.expression:type_name -> vclparser.v1.Expression
+	10,  // 146: vclparser.v1.RegexMatchExpression.span:type_name -> vclparser.v1.Span
+	45,  // 147: vclparser.v1.RegexMatchExpression.left:type_name -> vclparser.v1.Expression
+	45,  // 148: vclparser.v1.RegexMatchExpression.right:type_name -> vclparser.v1.Expression
+	10,  // 149: vclparser.v1.AssignmentExpression.span:type_name -> vclparser.v1.Span
+	45,  // 150: vclparser.v1.AssignmentExpression.left:type_name -> vclparser.v1.Expression
+	45,  // 151: vclparser.v1.AssignmentExpression.right:type_name -> vclparser.v1.Expression
+	10,  // 152: vclparser.v1.UpdateExpression.span:type_name -> vclparser.v1.Span
+	45,  // 153: vclparser.v1.UpdateExpression.operand:type_name -> vclparser.v1.Expression
+	10,  // 154: vclparser.v1.ArrayExpression.span:type_name -> vclparser.v1.Span
+	45,  // 155: vclparser.v1.ArrayExpression.elements:type_name -> vclparser.v1.Expression
+	10,  // 156: vclparser.v1.ObjectExpression.span:type_name -> vclparser.v1.Span
+	57,  // 157: vclparser.v1.ObjectExpression.properties:type_name -> vclparser.v1.ObjectProperty
+	10,  // 158: vclparser.v1.ObjectProperty.span:type_name -> vclparser.v1.Span
+	45,  // 159: vclparser.v1.ObjectProperty.key:type_name -> vclparser.v1.Expression
+	45,  // 160: vclparser.v1.ObjectProperty.value:type_name -> vclparser.v1.Expression
+	10,  // 161: vclparser.v1.VariableExpression.span:type_name -> vclparser.v1.Span
+	10,  // 162: vclparser.v1.TimeExpression.span:type_name -> vclparser.v1.Span
+	60,  // 163: vclparser.v1.TimeExpression.parts:type_name -> vclparser.v1.DurationPart
+	10,  // 164: vclparser.v1.IPExpression.span:type_name -> vclparser.v1.Span
+	10,  // 165: vclparser.v1.ErrorExpression.span:type_name -> vclparser.v1.Span
+	10,  // 166: vclparser.v1.Identifier.span:type_name -> vclparser.v1.Span
+	10,  // 167: vclparser.v1.StringLiteral.span:type_name -> vclparser.v1.Span
+	10,  // 168: vclparser.v1.IntegerLiteral.span:type_name -> vclparser.v1.Span
+	10,  // 169: vclparser.v1.FloatLiteral.span:type_name -> vclparser.v1.Span
+	10,  // 170: vclparser.v1.BooleanLiteral.span:type_name -> vclparser.v1.Span
+	10,  // 171: vclparser.v1.DurationLiteral.span:type_name -> vclparser.v1.Span
+	45,  // 172: vclparser.v1.CallExpression.NamedArgumentsEntry.value:type_name -> vclparser.v1.Expression
+	1,   // 173: vclparser.v1.VCLParser.Parse:input_type -> vclparser.v1.ParseRequest
+	3,   // 174: vclparser.v1.VCLParser.Analyze:input_type -> vclparser.v1.AnalyzeRequest
+	5,   // 175: vclparser.v1.VCLParser.ResolveIncludes:input_type -> vclparser.v1.ResolveIncludesRequest
+	2,   // 176: vclparser.v1.VCLParser.Parse:output_type -> vclparser.v1.ParseResponse
+	4,   // 177: vclparser.v1.VCLParser.Analyze:output_type -> vclparser.v1.AnalyzeResponse
+	6,   // 178: vclparser.v1.VCLParser.ResolveIncludes:output_type -> vclparser.v1.ResolveIncludesResponse
+	176, // [176:179] is the sub-list for method output_type
+	173, // [173:176] is the sub-list for method input_type
+	173, // [173:173] is the sub-list for extension type_name
+	173, // [173:173] is the sub-list for extension extendee
+	0,   // [0:173] is the sub-list for field type_name
+}
+
+func init() { file_proto_vclparser_v1_vclparser_proto_init() }
+func file_proto_vclparser_v1_vclparser_proto_init() {
+	if File_proto_vclparser_v1_vclparser_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ParseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ParseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*AnalyzeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*AnalyzeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ResolveIncludesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ResolveIncludesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*Source); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Diagnostic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*Position); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Span); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*Program); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*Declaration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*VCLVersionDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*ImportDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*IncludeDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*BackendDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*ProbeDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*Property); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*ACLDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*ACLEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*SubDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*TableDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*TableEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*DirectorDecl); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*DirectorBackend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*Statement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*BlockStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*ExpressionStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*IfStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*SetStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*UnsetStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*CallStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*ReturnStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*SyntheticStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*ErrorStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[35].Exporter = func(v any, i int) any {
+			switch v := v.(*RestartStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[36].Exporter = func(v any, i int) any {
+			switch v := v.(*CSourceStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[37].Exporter = func(v any, i int) any {
+			switch v := v.(*NewStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[38].Exporter = func(v any, i int) any {
+			switch v := v.(*DeclareStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[39].Exporter = func(v any, i int) any {
+			switch v := v.(*AddStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[40].Exporter = func(v any, i int) any {
+			switch v := v.(*EsiStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[41].Exporter = func(v any, i int) any {
+			switch v := v.(*LogStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[42].Exporter = func(v any, i int) any {
+			switch v := v.(*GotoStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[43].Exporter = func(v any, i int) any {
+			switch v := v.(*LabelStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[44].Exporter = func(v any, i int) any {
+			switch v := v.(*Expression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[45].Exporter = func(v any, i int) any {
+			switch v := v.(*BinaryExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[46].Exporter = func(v any, i int) any {
+			switch v := v.(*UnaryExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[47].Exporter = func(v any, i int) any {
+			switch v := v.(*CallExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[48].Exporter = func(v any, i int) any {
+			switch v := v.(*MemberExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[49].Exporter = func(v any, i int) any {
+			switch v := v.(*IndexExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[50].Exporter = func(v any, i int) any {
+			switch v := v.(*ParenthesizedExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[51].Exporter = func(v any, i int) any {
+			switch v := v.(*RegexMatchExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[52].Exporter = func(v any, i int) any {
+			switch v := v.(*AssignmentExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[53].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[54].Exporter = func(v any, i int) any {
+			switch v := v.(*ArrayExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[55].Exporter = func(v any, i int) any {
+			switch v := v.(*ObjectExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[56].Exporter = func(v any, i int) any {
+			switch v := v.(*ObjectProperty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[57].Exporter = func(v any, i int) any {
+			switch v := v.(*VariableExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[58].Exporter = func(v any, i int) any {
+			switch v := v.(*TimeExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[59].Exporter = func(v any, i int) any {
+			switch v := v.(*DurationPart); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[60].Exporter = func(v any, i int) any {
+			switch v := v.(*IPExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[61].Exporter = func(v any, i int) any {
+			switch v := v.(*ErrorExpression); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[62].Exporter = func(v any, i int) any {
+			switch v := v.(*Identifier); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[63].Exporter = func(v any, i int) any {
+			switch v := v.(*StringLiteral); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[64].Exporter = func(v any, i int) any {
+			switch v := v.(*IntegerLiteral); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[65].Exporter = func(v any, i int) any {
+			switch v := v.(*FloatLiteral); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[66].Exporter = func(v any, i int) any {
+			switch v := v.(*BooleanLiteral); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_vclparser_v1_vclparser_proto_msgTypes[67].Exporter = func(v any, i int) any {
+			switch v := v.(*DurationLiteral); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_vclparser_v1_vclparser_proto_msgTypes[11].OneofWrappers = []any{
+		(*Declaration_VclVersion)(nil),
+		(*Declaration_Import)(nil),
+		(*Declaration_Include)(nil),
+		(*Declaration_Backend)(nil),
+		(*Declaration_Probe)(nil),
+		(*Declaration_Acl)(nil),
+		(*Declaration_Sub)(nil),
+		(*Declaration_Table)(nil),
+		(*Declaration_Director)(nil),
+	}
+	file_proto_vclparser_v1_vclparser_proto_msgTypes[25].OneofWrappers = []any{
+		(*Statement_Block)(nil),
+		(*Statement_Expression)(nil),
+		(*Statement_If)(nil),
+		(*Statement_Set)(nil),
+		(*Statement_Unset)(nil),
+		(*Statement_Call)(nil),
+		(*Statement_Return)(nil),
+		(*Statement_Synthetic)(nil),
+		(*Statement_Error)(nil),
+		(*Statement_Restart)(nil),
+		(*Statement_CSource)(nil),
+		(*Statement_New)(nil),
+		(*Statement_Declare)(nil),
+		(*Statement_Add)(nil),
+		(*Statement_Esi)(nil),
+		(*Statement_Log)(nil),
+		(*Statement_Goto)(nil),
+		(*Statement_Label)(nil),
+	}
+	file_proto_vclparser_v1_vclparser_proto_msgTypes[44].OneofWrappers = []any{
+		(*Expression_Binary)(nil),
+		(*Expression_Unary)(nil),
+		(*Expression_Call)(nil),
+		(*Expression_Member)(nil),
+		(*Expression_Index)(nil),
+		(*Expression_Parenthesized)(nil),
+		(*Expression_RegexMatch)(nil),
+		(*Expression_Assignment)(nil),
+		(*Expression_Update)(nil),
+		(*Expression_Array)(nil),
+		(*Expression_Object)(nil),
+		(*Expression_Variable)(nil),
+		(*Expression_Time)(nil),
+		(*Expression_Ip)(nil),
+		(*Expression_Error)(nil),
+		(*Expression_Identifier)(nil),
+		(*Expression_String_)(nil),
+		(*Expression_Integer)(nil),
+		(*Expression_Float)(nil),
+		(*Expression_Boolean)(nil),
+		(*Expression_Duration)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_vclparser_v1_vclparser_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   70,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_vclparser_v1_vclparser_proto_goTypes,
+		DependencyIndexes: file_proto_vclparser_v1_vclparser_proto_depIdxs,
+		EnumInfos:         file_proto_vclparser_v1_vclparser_proto_enumTypes,
+		MessageInfos:      file_proto_vclparser_v1_vclparser_proto_msgTypes,
+	}.Build()
+	File_proto_vclparser_v1_vclparser_proto = out.File
+	file_proto_vclparser_v1_vclparser_proto_rawDesc = nil
+	file_proto_vclparser_v1_vclparser_proto_goTypes = nil
+	file_proto_vclparser_v1_vclparser_proto_depIdxs = nil
+}
@@ -0,0 +1,201 @@
+// Protocol buffer definitions of the VCL syntax tree, analyzer diagnostics
+// and a small service exposing the parser, so services in other languages
+// can use it as a sidecar.
+//
+// The messages follow the Go types in pkg/ast and pkg/analyzer field by
+// field. Node messages carry their source span; fields holding optional
+// nodes are unset when the Go field is nil.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: proto/vclparser/v1/vclparser.proto
+
+package vclparserv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	VCLParser_Parse_FullMethodName           = "/vclparser.v1.VCLParser/Parse"
+	VCLParser_Analyze_FullMethodName         = "/vclparser.v1.VCLParser/Analyze"
+	VCLParser_ResolveIncludes_FullMethodName = "/vclparser.v1.VCLParser/ResolveIncludes"
+)
+
+// VCLParserClient is the client API for VCLParser service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VCLParserClient interface {
+	// Parse parses one file and returns its syntax tree and parse errors
+	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// Analyze parses one file and returns the diagnostics of parsing and, if
+	// it parsed, of semantic analysis
+	Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+	// ResolveIncludes parses the main file and merges the files it includes
+	// into a single program
+	ResolveIncludes(ctx context.Context, in *ResolveIncludesRequest, opts ...grpc.CallOption) (*ResolveIncludesResponse, error)
+}
+
+type vCLParserClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVCLParserClient(cc grpc.ClientConnInterface) VCLParserClient {
+	return &vCLParserClient{cc}
+}
+
+func (c *vCLParserClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, VCLParser_Parse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCLParserClient) Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+	out := new(AnalyzeResponse)
+	err := c.cc.Invoke(ctx, VCLParser_Analyze_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vCLParserClient) ResolveIncludes(ctx context.Context, in *ResolveIncludesRequest, opts ...grpc.CallOption) (*ResolveIncludesResponse, error) {
+	out := new(ResolveIncludesResponse)
+	err := c.cc.Invoke(ctx, VCLParser_ResolveIncludes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VCLParserServer is the server API for VCLParser service.
+// All implementations must embed UnimplementedVCLParserServer
+// for forward compatibility
+type VCLParserServer interface {
+	// Parse parses one file and returns its syntax tree and parse errors
+	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+	// Analyze parses one file and returns the diagnostics of parsing and, if
+	// it parsed, of semantic analysis
+	Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error)
+	// ResolveIncludes parses the main file and merges the files it includes
+	// into a single program
+	ResolveIncludes(context.Context, *ResolveIncludesRequest) (*ResolveIncludesResponse, error)
+	mustEmbedUnimplementedVCLParserServer()
+}
+
+// UnimplementedVCLParserServer must be embedded to have forward compatible implementations.
+type UnimplementedVCLParserServer struct {
+}
+
+func (UnimplementedVCLParserServer) Parse(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Parse not implemented")
+}
+func (UnimplementedVCLParserServer) Analyze(context.Context, *AnalyzeRequest) (*AnalyzeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Analyze not implemented")
+}
+func (UnimplementedVCLParserServer) ResolveIncludes(context.Context, *ResolveIncludesRequest) (*ResolveIncludesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveIncludes not implemented")
+}
+func (UnimplementedVCLParserServer) mustEmbedUnimplementedVCLParserServer() {}
+
+// UnsafeVCLParserServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VCLParserServer will
+// result in compilation errors.
+type UnsafeVCLParserServer interface {
+	mustEmbedUnimplementedVCLParserServer()
+}
+
+func RegisterVCLParserServer(s grpc.ServiceRegistrar, srv VCLParserServer) {
+	s.RegisterService(&VCLParser_ServiceDesc, srv)
+}
+
+func _VCLParser_Parse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCLParserServer).Parse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VCLParser_Parse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCLParserServer).Parse(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VCLParser_Analyze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCLParserServer).Analyze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VCLParser_Analyze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCLParserServer).Analyze(ctx, req.(*AnalyzeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VCLParser_ResolveIncludes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveIncludesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VCLParserServer).ResolveIncludes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VCLParser_ResolveIncludes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VCLParserServer).ResolveIncludes(ctx, req.(*ResolveIncludesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VCLParser_ServiceDesc is the grpc.ServiceDesc for VCLParser service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VCLParser_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vclparser.v1.VCLParser",
+	HandlerType: (*VCLParserServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Parse",
+			Handler:    _VCLParser_Parse_Handler,
+		},
+		{
+			MethodName: "Analyze",
+			Handler:    _VCLParser_Analyze_Handler,
+		},
+		{
+			MethodName: "ResolveIncludes",
+			Handler:    _VCLParser_ResolveIncludes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/vclparser/v1/vclparser.proto",
+}
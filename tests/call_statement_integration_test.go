@@ -4,7 +4,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/perbu/vclparser/pkg/analyzer"
 	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/vmod"
 )
 
 func TestCallStatementParsing(t *testing.T) {
@@ -45,7 +47,13 @@ sub vcl_recv {
     return (pass);
 }`
 
-	if _, err := parser.Parse(vclCode, "test.vcl"); err == nil {
-		t.Fatal("Expected parsing error, got none")
+	// The subroutine may be defined in an included file, so only the
+	// analysis of the whole program reports it
+	_, errs, err := analyzer.ParseWithCustomVMODValidation(vclCode, "test.vcl", vmod.NewEmptyRegistry())
+	if err != nil {
+		t.Fatalf("Unexpected parsing error: %v", err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0], "undefined subroutine: test_sub") {
+		t.Fatalf("Expected an undefined subroutine error, got %v", errs)
 	}
 }
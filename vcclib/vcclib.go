@@ -0,0 +1,80 @@
+// Package vcclib embeds the VCC files of the VMODs known without
+// configuration and the manifest recording where they came from. The files
+// shipped are those of Varnish Enterprise 6.0; cmd/vccsync replaces them
+// with the files of varnish-cache and varnish-modules releases. It has no
+// dependencies, so the VMOD registry can load the files without importing
+// the top-level vclparser package, which is built on the registry.
+package vcclib
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+//go:embed *.vcc manifest.json
+var files embed.FS
+
+// ManifestFile is the file recording where the embedded VCC files come
+// from. cmd/vccsync writes it when refreshing vcclib.
+const ManifestFile = "manifest.json"
+
+// Manifest records the upstream release each embedded VCC file was copied
+// from
+type Manifest struct {
+	// Varnish is the release the built-in VMODs, such as std and
+	// directors, were copied from: a varnish-cache release such as 7.5.0,
+	// or 6.0-enterprise for the files of Varnish Enterprise
+	Varnish string `json:"varnish,omitempty"`
+	// Files maps VCC file names, such as vmod_std.vcc, to their origin
+	Files map[string]Source `json:"files"`
+}
+
+// Source is the origin of an embedded VCC file
+type Source struct {
+	Upstream string `json:"upstream"`       // varnish-cache, varnish-modules or varnish-enterprise
+	Path     string `json:"path,omitempty"` // path of the file in the upstream tree, if known
+	Version  string `json:"version"`        // release of the upstream tree
+}
+
+// List returns the names of the embedded VCC files, such as vmod_std.vcc,
+// in lexical order
+func List() ([]string, error) {
+	names, err := fs.Glob(files, "*.vcc")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Open opens the embedded VCC file with the given name
+func Open(name string) (fs.File, error) {
+	return files.Open(path.Clean(name))
+}
+
+// FS returns the embedded VCC files, such as vmod_std.vcc, and the
+// manifest
+func FS() fs.FS {
+	return files
+}
+
+// ReadManifest returns the manifest of the embedded VCC files. Files added
+// by hand are not listed.
+func ReadManifest() (*Manifest, error) {
+	manifest := &Manifest{Files: map[string]Source{}}
+	data, err := fs.ReadFile(files, ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", ManifestFile, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]Source{}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,51 @@
+package vcclib
+
+import (
+	"io/fs"
+	"path"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	manifest, err := ReadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Varnish == "" {
+		t.Error("The manifest does not record the Varnish release")
+	}
+
+	names, err := List()
+	if err != nil || len(names) == 0 {
+		t.Fatalf("List() = %v, %v", names, err)
+	}
+	listed := map[string]bool{}
+	for _, name := range names {
+		listed[name] = true
+		source, ok := manifest.Files[name]
+		if !ok {
+			t.Errorf("%s is not in the manifest", name)
+			continue
+		}
+		if source.Upstream == "" || source.Version == "" {
+			t.Errorf("%s: incomplete source %+v", name, source)
+		}
+	}
+	for name := range manifest.Files {
+		if !listed[name] {
+			t.Errorf("The manifest lists %s, which is not embedded", name)
+		}
+	}
+}
+
+func TestEmbeddedFiles(t *testing.T) {
+	entries, err := fs.ReadDir(FS(), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != ManifestFile && path.Ext(name) != ".vcc" {
+			t.Errorf("%s is embedded", name)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package vclparser is the stable entry point to the parser. It wires the
+// lexer, parser, include resolver, VMOD registry, analyzer and formatter
+// together with the settings most programs need:
+//
+//	program, err := vclparser.ParseFile("/etc/varnish/default.vcl")
+//	diags, err := vclparser.Check(src, "default.vcl", vclparser.WithVarnishVersion("7.4"))
+//	out, err := vclparser.Format(src, "default.vcl")
+//
+// The functions of this package keep their signatures across releases, and
+// settings are added as new options. The packages under pkg remain
+// available for everything beyond that, and the options accept their
+// types, such as parser.Config or vmod.Registry.
+package vclparser
+
+import (
+	"context"
+	"os"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/lint"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/printer"
+	"github.com/perbu/vclparser/pkg/vmod"
+)
+
+// Option configures the functions of this package. Options that do not
+// apply to a function are ignored by it.
+type Option func(*options)
+
+// options holds the settings collected from Options
+type options struct {
+	ctx       context.Context
+	parser    *parser.Config
+	printer   *printer.Config
+	registry  *vmod.Registry
+	version   string
+	resolver  []include.Option
+	lint      map[string]string
+	configure func(*analyzer.Analyzer) error
+}
+
+// WithContext makes the functions stop once ctx is done and return
+// ctx.Err(), together with the partial tree or the findings so far where
+// there are any. Remote includes are fetched with ctx.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithParserConfig parses with config instead of parser.DefaultConfig()
+func WithParserConfig(config *parser.Config) Option {
+	return func(o *options) {
+		o.parser = config
+	}
+}
+
+// WithPrinterConfig makes Format use config instead of
+// printer.DefaultConfig()
+func WithPrinterConfig(config *printer.Config) Option {
+	return func(o *options) {
+		o.printer = config
+	}
+}
+
+// WithRegistry makes Check validate imports against registry instead of
+// vmod.DefaultRegistry, which holds the embedded VCC files
+func WithRegistry(registry *vmod.Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithVarnishVersion makes Check validate against a Varnish release, such
+// as "7.4", instead of the latest one
+func WithVarnishVersion(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithIncludeOptions configures the include resolver of ResolveIncludes,
+// for instance with include.WithSearchPath or include.WithFS
+func WithIncludeOptions(resolverOptions ...include.Option) Option {
+	return func(o *options) {
+		o.resolver = append(o.resolver, resolverOptions...)
+	}
+}
+
+// WithLintLevels sets the level of lint rules and analyzer checks for
+// Check, keyed by rule ID: "info", "warning", "error" or "off"
+func WithLintLevels(levels map[string]string) Option {
+	return func(o *options) {
+		o.lint = levels
+	}
+}
+
+// WithAnalyzer calls configure with the analyzer of Check before it runs,
+// for settings without an option of their own
+func WithAnalyzer(configure func(*analyzer.Analyzer) error) Option {
+	return func(o *options) {
+		o.configure = configure
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.parser == nil {
+		o.parser = parser.DefaultConfig()
+	}
+	if o.printer == nil {
+		o.printer = printer.DefaultConfig()
+	}
+	if o.registry == nil {
+		o.registry = vmod.DefaultRegistry
+	}
+	return o
+}
+
+// Parse parses VCL source. Include declarations are kept in the tree
+// without being resolved. When the source has syntax errors, the first one
+// is returned, as a parser.DetailedError, together with the partial tree.
+func Parse(src, filename string, opts ...Option) (*ast.Program, error) {
+	o := newOptions(opts)
+	return parser.ParseContext(o.ctx, src, filename, o.parser)
+}
+
+// ParseFile reads and parses a VCL file like Parse
+func ParseFile(filename string, opts ...Option) (*ast.Program, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data), filename, opts...)
+}
+
+// ResolveIncludes parses a VCL file and the files it includes, recursively,
+// and returns a single tree with the included declarations in place of the
+// include declarations. Errors are the types of the include package, such
+// as include.FileNotFoundError or include.ParseError.
+func ResolveIncludes(filename string, opts ...Option) (*ast.Program, error) {
+	o := newOptions(opts)
+	config := o.parser
+	resolverOptions := append(o.resolver[:len(o.resolver):len(o.resolver)],
+		include.WithParseFunc(func(input, filename string) (*ast.Program, error) {
+			return parser.ParseWithConfig(input, filename, config)
+		}))
+	return include.NewResolver(resolverOptions...).ResolveFileContext(o.ctx, filename)
+}
+
+// Check parses VCL source and returns its syntax errors or, when it parses,
+// its parse warnings and the findings of the semantic analysis and the lint
+// rules, like "vclparse check". Includes are not followed. The error is only
+// set for invalid options or when the context is done.
+func Check(src, filename string, opts ...Option) ([]analyzer.Diagnostic, error) {
+	o := newOptions(opts)
+	if err := o.ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := parser.ParseDetailed(src, filename, o.parser)
+	diags := analyzer.ParseDiagnostics(result)
+	if len(result.Errors) > 0 {
+		return diags, nil
+	}
+
+	a := analyzer.NewAnalyzer(o.registry)
+	if o.version != "" {
+		if err := a.SetVarnishVersion(o.version); err != nil {
+			return nil, err
+		}
+	}
+	if o.configure != nil {
+		if err := o.configure(a); err != nil {
+			return nil, err
+		}
+	}
+	linter := lint.New(a)
+	if err := linter.Configure(o.lint); err != nil {
+		return nil, err
+	}
+	lintDiags, err := linter.LintContext(o.ctx, result.Program, filename, src)
+	return append(diags, lintDiags...), err
+}
+
+// Format parses VCL source and returns it formatted, keeping its comments.
+// Source with syntax errors is returned as an error.
+func Format(src, filename string, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	if err := o.ctx.Err(); err != nil {
+		return "", err
+	}
+	return o.printer.Format(src, filename)
+}
@@ -0,0 +1,146 @@
+package vclparser
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/perbu/vclparser/pkg/analyzer"
+	"github.com/perbu/vclparser/pkg/ast"
+	"github.com/perbu/vclparser/pkg/include"
+	"github.com/perbu/vclparser/pkg/parser"
+	"github.com/perbu/vclparser/pkg/printer"
+)
+
+const source = `vcl 4.1;
+
+backend default { .host = "127.0.0.1"; }
+
+sub vcl_recv {
+    C{ int x; }C
+    return (lookup);
+}
+`
+
+func TestParse(t *testing.T) {
+	program, err := Parse(source, "default.vcl")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(program.Declarations) != 2 {
+		t.Errorf("Expected 2 declarations, got %d", len(program.Declarations))
+	}
+
+	_, err = Parse(source, "default.vcl", WithParserConfig(&parser.Config{DisableInlineC: true}))
+	if err == nil {
+		t.Error("Expected inline C to be rejected with DisableInlineC")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "default.vcl")
+	if err := os.WriteFile(filename, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseFile(filename); err != nil {
+		t.Errorf("ParseFile: %v", err)
+	}
+	if _, err := ParseFile(filename + ".missing"); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
+
+func TestResolveIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.vcl":     {Data: []byte("vcl 4.1;\ninclude \"backends.vcl\";\n")},
+		"backends.vcl": {Data: []byte("vcl 4.1;\nbackend default { .host = \"127.0.0.1\"; }\n")},
+	}
+	program, err := ResolveIncludes("main.vcl", WithIncludeOptions(include.WithFS(fsys)))
+	if err != nil {
+		t.Fatalf("ResolveIncludes: %v", err)
+	}
+	if len(program.Declarations) != 1 {
+		t.Fatalf("Expected the included backend only, got %d declarations", len(program.Declarations))
+	}
+	if _, ok := program.Declarations[0].(*ast.BackendDecl); !ok {
+		t.Errorf("Expected a backend, got %T", program.Declarations[0])
+	}
+}
+
+func TestCheck(t *testing.T) {
+	diags, err := Check(source, "default.vcl")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	var found bool
+	for _, d := range diags {
+		if d.Severity == analyzer.SeverityError && d.Position.Line == 7 && d.Filename == "default.vcl" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected an error for return (lookup) at line 7, got %v", diags)
+	}
+
+	diags, err = Check("vcl 4.1;\nsub vcl_recv {\n", "broken.vcl")
+	if err != nil || len(diags) == 0 || diags[0].Severity != analyzer.SeverityError {
+		t.Errorf("Expected syntax errors as diagnostics, got %v (err %v)", diags, err)
+	}
+
+	if _, err := Check(source, "default.vcl", WithVarnishVersion("nonsense")); err == nil {
+		t.Error("Expected an error for an invalid Varnish version")
+	}
+	if _, err := Check(source, "default.vcl", WithLintLevels(map[string]string{"unreferenced": "loud"})); err == nil {
+		t.Error("Expected an error for an invalid lint level")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	out, err := Format("vcl 4.1;\nsub vcl_recv { # keep\nreturn (hash); }\n", "default.vcl",
+		WithPrinterConfig(&printer.Config{Indent: "\t"}))
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	if !strings.Contains(out, "\treturn (hash);") || !strings.Contains(out, "# keep") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Parse(source, "default.vcl", WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Parse: expected context.Canceled, got %v", err)
+	}
+	if _, err := Check(source, "default.vcl", WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Check: expected context.Canceled, got %v", err)
+	}
+	fsys := fstest.MapFS{"main.vcl": {Data: []byte("vcl 4.1;\n")}}
+	if _, err := ResolveIncludes("main.vcl", WithContext(ctx), WithIncludeOptions(include.WithFS(fsys))); !errors.Is(err, context.Canceled) {
+		t.Errorf("ResolveIncludes: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestEmbeddedVCCFiles(t *testing.T) {
+	names, err := ListEmbeddedVCCFiles()
+	if err != nil || len(names) == 0 {
+		t.Fatalf("Expected embedded VCC files, got %v (err %v)", names, err)
+	}
+	for _, name := range names {
+		// The deprecated filesystem keeps the vcclib/ prefix
+		if _, err := GetEmbeddedVCCFiles().ReadFile(name); err != nil {
+			t.Errorf("GetEmbeddedVCCFiles: %v", err)
+		}
+		if _, err := fs.ReadFile(EmbeddedVCCFS(), strings.TrimPrefix(name, "vcclib/")); err != nil {
+			t.Errorf("EmbeddedVCCFS: %v", err)
+		}
+	}
+	if _, err := GetEmbeddedVCCFiles().ReadFile("vcclib/" + VCCManifestFile); err != nil {
+		t.Errorf("GetEmbeddedVCCFiles: %v", err)
+	}
+}